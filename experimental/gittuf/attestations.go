@@ -34,6 +34,7 @@ const githubTokenEnvKey = "GITHUB_TOKEN" //nolint:gosec
 var (
 	ErrNotSigningKey = errors.New("expected signing key")
 	ErrNoGitHubToken = errors.New("authentication token for GitHub API not provided")
+	ErrCannotSetHat  = errors.New("cannot declare role for reference authorization already signed by other keys")
 )
 
 var githubClient *gogithub.Client
@@ -63,6 +64,8 @@ func (r *Repository) ApplyAttestations(ctx context.Context, remoteName string, l
 // last RSL entry for the target ref. The to ID is that of the expected Git tree
 // created by merging the feature ref into the target ref. The commit used to
 // calculate the merge tree ID is identified using the RSL for the feature ref.
+// The role the authorization is made in may be declared using
+// attestopts.WithHat.
 func (r *Repository) AddReferenceAuthorization(ctx context.Context, signer sslibdsse.SignerVerifier, targetRef, featureRef string, signCommit bool, opts ...attestopts.Option) error {
 	if signCommit {
 		slog.Debug("Checking if Git signing is configured...")
@@ -173,6 +176,34 @@ func (r *Repository) AddReferenceAuthorization(ctx context.Context, signer sslib
 		return err
 	}
 
+	if options.Hat != "" {
+		hats, err := attestations.GetReferenceAuthorizationHats(env)
+		if err != nil {
+			return err
+		}
+
+		if hats[keyID] != options.Hat {
+			// Declaring the role changes the payload, invalidating any
+			// existing signatures
+			for _, signature := range env.Signatures {
+				if signature.KeyID != keyID {
+					return ErrCannotSetHat
+				}
+			}
+
+			slog.Debug(fmt.Sprintf("Declaring role '%s' for '%s' in reference authorization...", options.Hat, keyID))
+			statement, err := attestations.AddHatToReferenceAuthorization(env, keyID, options.Hat)
+			if err != nil {
+				return err
+			}
+
+			env, err = dsse.CreateEnvelope(statement)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
 	slog.Debug(fmt.Sprintf("Signing reference authorization using '%s'...", keyID))
 	env, err = dsse.SignEnvelope(ctx, env, signer)
 	if err != nil {
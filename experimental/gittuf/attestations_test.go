@@ -227,6 +227,105 @@ func TestAddAndRemoveReferenceAuthorization(t *testing.T) {
 	})
 }
 
+func TestAddReferenceAuthorizationWithHat(t *testing.T) {
+	testDir := t.TempDir()
+	r := gitinterface.CreateTestGitRepository(t, testDir, false)
+
+	// We need to change the directory for this test because we `checkout`
+	// for older Git versions, modifying the worktree. This chdir ensures
+	// that the temporary directory is used as the worktree.
+	pwd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chdir(testDir); err != nil {
+		t.Fatal(err)
+	}
+	defer os.Chdir(pwd) //nolint:errcheck
+
+	repo := &Repository{r: r}
+
+	absTargetRef := "refs/heads/main"
+	absFeatureRef := "refs/heads/feature"
+
+	// Create common base for main and feature branches
+	treeBuilder := gitinterface.NewTreeBuilder(repo.r)
+	emptyTreeID, err := treeBuilder.WriteTreeFromEntries(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	initialCommitID, err := repo.r.Commit(emptyTreeID, absTargetRef, "Initial commit\n", false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := repo.r.SetReference(absFeatureRef, initialCommitID); err != nil {
+		t.Fatal(err)
+	}
+
+	commitIDs := common.AddNTestCommitsToSpecifiedRef(t, r, absTargetRef, 1, gpgKeyBytes)
+	fromCommitID := commitIDs[0]
+	if err := repo.RecordRSLEntryForReference(testCtx, absTargetRef, false, rslopts.WithRecordLocalOnly()); err != nil {
+		t.Fatal(err)
+	}
+
+	commitIDs = common.AddNTestCommitsToSpecifiedRef(t, r, absFeatureRef, 1, gpgKeyBytes)
+	featureCommitID := commitIDs[0]
+	if err := repo.RecordRSLEntryForReference(testCtx, absFeatureRef, false, rslopts.WithRecordLocalOnly()); err != nil {
+		t.Fatal(err)
+	}
+
+	targetTreeID, err := r.GetMergeTree(fromCommitID, featureCommitID)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	firstSigner := setupSSHKeysForSigning(t, rootKeyBytes, rootPubKeyBytes)
+	firstKeyID, err := firstSigner.KeyID()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	secondSigner := setupSSHKeysForSigning(t, targetsKeyBytes, targetsPubKeyBytes)
+
+	err = repo.AddReferenceAuthorization(testCtx, firstSigner, absTargetRef, absFeatureRef, false, attestopts.WithRSLEntry(), attestopts.WithHat("security"))
+	assert.Nil(t, err)
+
+	allAttestations, err := attestations.LoadCurrentAttestations(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	env, err := allAttestations.GetReferenceAuthorizationFor(r, absTargetRef, fromCommitID.String(), targetTreeID.String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Len(t, env.Signatures, 1)
+	assert.Equal(t, firstKeyID, env.Signatures[0].KeyID)
+
+	hats, err := attestations.GetReferenceAuthorizationHats(env)
+	assert.Nil(t, err)
+	assert.Equal(t, map[string]string{firstKeyID: "security"}, hats)
+
+	// Signing without a hat retains the existing signature
+	err = repo.AddReferenceAuthorization(testCtx, secondSigner, absTargetRef, absFeatureRef, false, attestopts.WithRSLEntry())
+	assert.Nil(t, err)
+
+	allAttestations, err = attestations.LoadCurrentAttestations(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	env, err = allAttestations.GetReferenceAuthorizationFor(r, absTargetRef, fromCommitID.String(), targetTreeID.String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Len(t, env.Signatures, 2)
+
+	// Declaring a hat after others have signed is not possible
+	err = repo.AddReferenceAuthorization(testCtx, secondSigner, absTargetRef, absFeatureRef, false, attestopts.WithRSLEntry(), attestopts.WithHat("dev"))
+	assert.ErrorIs(t, err, ErrCannotSetHat)
+}
+
 func TestGetGitHubPullRequestApprovalPredicateFromEnvelope(t *testing.T) {
 	tests := map[string]struct {
 		envelope          *dsse.Envelope
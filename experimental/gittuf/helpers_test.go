@@ -4,8 +4,10 @@
 package gittuf
 
 import (
+	"bytes"
 	"context"
 	"os"
+	"os/exec"
 	"path/filepath"
 	"testing"
 
@@ -232,3 +234,27 @@ func setupSSHKeysForSigning(t *testing.T, privateBytes, publicBytes []byte) *ssh
 
 	return signer
 }
+
+// setupGPGKeyForSigning imports the GPG private key into a temporary keyring
+// used by the gpg agent and returns the key's fingerprint.
+func setupGPGKeyForSigning(t *testing.T, privateBytes []byte) string {
+	t.Helper()
+
+	t.Setenv("GNUPGHOME", t.TempDir())
+	t.Cleanup(func() {
+		exec.Command("gpgconf", "--kill", "gpg-agent").Run() //nolint:errcheck
+	})
+
+	cmd := exec.Command("gpg", "--batch", "--import")
+	cmd.Stdin = bytes.NewReader(privateBytes)
+	if output, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("unable to import gpg key: %s: %s", err, string(output))
+	}
+
+	key, err := gpg.LoadGPGKeyFromBytes(privateBytes)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return key.KeyID
+}
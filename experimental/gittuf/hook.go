@@ -14,10 +14,12 @@ import (
 	"strings"
 
 	hookopts "github.com/gittuf/gittuf/experimental/gittuf/options/hooks"
+	"github.com/gittuf/gittuf/internal/attestations"
 	"github.com/gittuf/gittuf/internal/gitinterface"
 	"github.com/gittuf/gittuf/internal/luasandbox"
 	luasandboxopts "github.com/gittuf/gittuf/internal/luasandbox/options/luasandbox"
 	"github.com/gittuf/gittuf/internal/policy"
+	"github.com/gittuf/gittuf/internal/signerverifier/dsse"
 	sslibdsse "github.com/gittuf/gittuf/internal/third_party/go-securesystemslib/dsse"
 	"github.com/gittuf/gittuf/internal/tuf"
 	lua "github.com/yuin/gopher-lua"
@@ -48,9 +50,10 @@ var HookPrePush = HookType("pre-push")
 
 // InvokeHooksForStage runs the hooks defined in the specified stage for the
 // user defined by principalID. Upon successful completion of all hooks for the
-// stage for the user, the map of hook names to exit codes is returned.  TODO:
-// Add attestations workflow
-func (r *Repository) InvokeHooksForStage(ctx context.Context, signer sslibdsse.Signer, stage tuf.HookStage, opts ...hookopts.Option) (map[string]int, error) {
+// stage for the user, the map of hook names to exit codes is returned. For the
+// pre-push stage, a hook execution attestation signed using signer is also
+// recorded for each hook and pushed ref.
+func (r *Repository) InvokeHooksForStage(ctx context.Context, signer sslibdsse.Signer, stage tuf.HookStage, signCommit bool, opts ...hookopts.Option) (map[string]int, error) {
 	options := &hookopts.Options{}
 	for _, fn := range opts {
 		fn(options)
@@ -60,6 +63,14 @@ func (r *Repository) InvokeHooksForStage(ctx context.Context, signer sslibdsse.S
 		return nil, sslibdsse.ErrNoSigners
 	}
 
+	if signCommit {
+		slog.Debug("Checking if Git signing is configured...")
+		err := r.r.CanSign()
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	keyID, err := signer.KeyID()
 	if err != nil {
 		return nil, err
@@ -115,6 +126,10 @@ func (r *Repository) InvokeHooksForStage(ctx context.Context, signer sslibdsse.S
 	// Determine what parameters must be supplied based on the hook stage
 	var luaParameters lua.LTable
 
+	// remoteObjects tracks the local and remote hashes for each pushed ref in
+	// the pre-push stage, used to record hook execution attestations
+	var remoteObjects map[string][]gitinterface.Hash
+
 	// At the moment, the only stage that we support that requires parameters is
 	// the pre-push stage.
 	if stage == tuf.HookStagePrePush {
@@ -138,7 +153,7 @@ func (r *Repository) InvokeHooksForStage(ctx context.Context, signer sslibdsse.S
 		luaParameters.RawSet(lua.LString("remoteName"), lua.LString(options.PrePush.RemoteName))
 		luaParameters.RawSet(lua.LString("remoteURL"), lua.LString(options.PrePush.RemoteURL))
 
-		remoteObjects := make(map[string][]gitinterface.Hash, len(options.PrePush.RefSpecs))
+		remoteObjects = make(map[string][]gitinterface.Hash, len(options.PrePush.RefSpecs))
 
 		for _, refSpec := range options.PrePush.RefSpecs {
 			splitRefSpec := strings.Split(refSpec, ":")
@@ -196,6 +211,48 @@ func (r *Repository) InvokeHooksForStage(ctx context.Context, signer sslibdsse.S
 		exitCodes[hook.ID()] = exitCode
 	}
 
+	if len(remoteObjects) == 0 {
+		return exitCodes, nil
+	}
+
+	slog.Debug("Loading current set of attestations...")
+	allAttestations, err := attestations.LoadCurrentAttestations(r.r)
+	if err != nil {
+		return nil, err
+	}
+
+	for refSpec, hashes := range remoteObjects {
+		remoteRef := strings.Split(refSpec, ":")[1]
+		fromID, toID := hashes[1].String(), hashes[0].String()
+
+		for _, hook := range selectedHooks {
+			slog.Debug(fmt.Sprintf("Recording execution of hook '%s' for '%s'...", hook.ID(), remoteRef))
+			statement, err := attestations.NewHookExecutionAttestation(remoteRef, fromID, toID, hook.ID(), exitCodes[hook.ID()])
+			if err != nil {
+				return nil, err
+			}
+
+			env, err := dsse.CreateEnvelope(statement)
+			if err != nil {
+				return nil, err
+			}
+
+			env, err = dsse.SignEnvelope(ctx, env, signer)
+			if err != nil {
+				return nil, err
+			}
+
+			if err := allAttestations.SetHookExecutionAttestation(r.r, env, remoteRef, fromID, toID, hook.ID()); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	slog.Debug("Committing attestations...")
+	if err := allAttestations.Commit(r.r, "Add hook execution attestations for pre-push", options.CreateRSLEntry, signCommit); err != nil {
+		return nil, err
+	}
+
 	return exitCodes, nil
 }
 
@@ -9,6 +9,7 @@ import (
 	"testing"
 
 	hookopts "github.com/gittuf/gittuf/experimental/gittuf/options/hooks"
+	"github.com/gittuf/gittuf/internal/attestations"
 	"github.com/gittuf/gittuf/internal/dev"
 	"github.com/gittuf/gittuf/internal/gitinterface"
 	sslibdsse "github.com/gittuf/gittuf/internal/third_party/go-securesystemslib/dsse"
@@ -87,7 +88,7 @@ func TestInvokeHooksForStage(t *testing.T) {
 
 		r := &Repository{r: repo}
 
-		_, err := r.InvokeHooksForStage(testCtx, nil, tuf.HookStagePreCommit)
+		_, err := r.InvokeHooksForStage(testCtx, nil, tuf.HookStagePreCommit, false)
 		assert.ErrorIs(t, err, sslibdsse.ErrNoSigners)
 	})
 
@@ -118,7 +119,7 @@ func TestInvokeHooksForStage(t *testing.T) {
 		err = r.ApplyPolicy(testCtx, "", true, false)
 		require.Nil(t, err)
 
-		_, err = r.InvokeHooksForStage(testCtx, rootSigner, hookStage)
+		_, err = r.InvokeHooksForStage(testCtx, rootSigner, hookStage, false)
 		assert.ErrorIs(t, err, ErrNoHooksFoundForPrincipal)
 	})
 
@@ -146,7 +147,7 @@ func TestInvokeHooksForStage(t *testing.T) {
 		err = r.ApplyPolicy(testCtx, "", true, false)
 		require.Nil(t, err)
 
-		codes, err := r.InvokeHooksForStage(testCtx, rootSigner, hookStage)
+		codes, err := r.InvokeHooksForStage(testCtx, rootSigner, hookStage, false)
 		assert.Nil(t, err)
 		assert.Len(t, codes, 1)
 	})
@@ -192,8 +193,22 @@ func TestInvokeHooksForStage(t *testing.T) {
 		err = r.ApplyPolicy(testCtx, "", true, false)
 		assert.Nil(t, err)
 
-		codes, err := r.InvokeHooksForStage(testCtx, rootSigner, hookStage, hookopts.WithPrePush("origin", remoteTmpDir, []string{"refs/heads/main:refs/heads/main"}))
+		codes, err := r.InvokeHooksForStage(testCtx, rootSigner, hookStage, false, hookopts.WithPrePush("origin", remoteTmpDir, []string{"refs/heads/main:refs/heads/main"}), hookopts.WithRSLEntry())
 		assert.Nil(t, err)
 		assert.Len(t, codes, 1)
+
+		localHash, err := repo.GetReference("refs/heads/main")
+		require.Nil(t, err)
+
+		allAttestations, err := attestations.LoadCurrentAttestations(repo)
+		require.Nil(t, err)
+
+		env, err := allAttestations.GetHookExecutionAttestationFor(repo, "refs/heads/main", gitinterface.ZeroHash.String(), localHash.String(), hookName)
+		require.Nil(t, err)
+		assert.Len(t, env.Signatures, 1)
+
+		hookExecution, err := attestations.GetHookExecution(env)
+		require.Nil(t, err)
+		assert.Equal(t, codes[hookName], hookExecution.GetExitCode())
 	})
 }
@@ -74,14 +74,20 @@ func LoadPublicKey(keyRef string) (tuf.Principal, error) {
 
 // LoadSigner loads a metadata signer for the specified key bytes. Currently,
 // the signer must be either for an SSH key (in which case the `key` is a path
-// to the private key) or for signing with Sigstore (where `key` has a prefix
-// `fulcio:`).
+// to the private key), for a GPG key available to the local gpg agent (where
+// `key` is the key's fingerprint with a prefix `gpg:`), or for signing with
+// Sigstore (where `key` has a prefix `fulcio:`). The repository is used to read
+// the Sigstore configuration and may be nil when signing outside a repository.
 func LoadSigner(repo *Repository, key string) (sslibdsse.SignerVerifier, error) {
 	switch {
 	case strings.HasPrefix(key, GPGKeyPrefix):
-		return nil, fmt.Errorf("not implemented")
+		return gpg.NewSignerFromFingerprint(strings.TrimPrefix(key, GPGKeyPrefix))
 	case strings.HasPrefix(key, FulcioPrefix):
 		opts := []sigstoresigneropts.Option{}
+		if repo == nil {
+			// Without a repository, the default Sigstore instance is used
+			return sigstore.NewSigner(opts...), nil
+		}
 
 		gitRepo := repo.GetGitRepository()
 		config, err := gitRepo.GetGitConfig()
@@ -41,3 +41,20 @@ func TestLoadSigner(t *testing.T) {
 		assert.Nil(t, err)
 	}
 }
+
+func TestLoadSignerGPG(t *testing.T) {
+	fingerprint := setupGPGKeyForSigning(t, artifacts.GPGKey1Private)
+
+	signer, err := LoadSigner(nil, GPGKeyPrefix+fingerprint)
+	assert.Nil(t, err)
+
+	keyID, err := signer.KeyID()
+	assert.Nil(t, err)
+	assert.Equal(t, fingerprint, keyID)
+
+	sig, err := signer.Sign(context.Background(), []byte("data"))
+	assert.Nil(t, err)
+
+	err = signer.Verify(context.Background(), []byte("data"), sig)
+	assert.Nil(t, err)
+}
@@ -5,6 +5,7 @@ package attest
 
 type Options struct {
 	CreateRSLEntry bool
+	Hat            string
 }
 
 type Option func(o *Options)
@@ -14,3 +15,11 @@ func WithRSLEntry() Option {
 		o.CreateRSLEntry = true
 	}
 }
+
+// WithHat declares the role (the "hat") a reference authorization is made in.
+// The authorization is only counted towards the threshold of that role.
+func WithHat(role string) Option {
+	return func(o *Options) {
+		o.Hat = role
+	}
+}
@@ -11,11 +11,20 @@ import (
 var ErrRequiredOptionNotSet = errors.New("required option not set")
 
 type Options struct {
-	PrePush *PrePushOptions
+	PrePush        *PrePushOptions
+	CreateRSLEntry bool
 }
 
 type Option func(o *Options)
 
+// WithRSLEntry can be used to record an RSL entry for the attestations created
+// for the hooks that were run.
+func WithRSLEntry() Option {
+	return func(o *Options) {
+		o.CreateRSLEntry = true
+	}
+}
+
 // WithPrePush can be used to specify arguments normally passed to Git pre-push
 // hooks.
 func WithPrePush(remoteName, remoteURL string, refSpecs []string) Option {
@@ -56,3 +56,22 @@ func WithAnnotateLocalOnly() AnnotateOption {
 		o.LocalOnly = true
 	}
 }
+
+type CheckpointOptions struct {
+	RemoteName string
+	LocalOnly  bool
+}
+
+type CheckpointOption func(o *CheckpointOptions)
+
+func WithCheckpointRemote(remoteName string) CheckpointOption {
+	return func(o *CheckpointOptions) {
+		o.RemoteName = remoteName
+	}
+}
+
+func WithCheckpointLocalOnly() CheckpointOption {
+	return func(o *CheckpointOptions) {
+		o.LocalOnly = true
+	}
+}
@@ -3,9 +3,14 @@
 
 package verify
 
+import "github.com/gittuf/gittuf/internal/policy"
+
 type Options struct {
 	RefNameOverride string
 	LatestOnly      bool
+	Report          *policy.VerificationReport
+	Workers         int
+	UseCheckpoints  bool
 }
 
 type Option func(o *Options)
@@ -21,3 +26,27 @@ func WithLatestOnly() Option {
 		o.LatestOnly = true
 	}
 }
+
+// WithReport populates the specified report with the checks performed during
+// verification and their outcomes. The report is populated even if
+// verification fails.
+func WithReport(report *policy.VerificationReport) Option {
+	return func(o *Options) {
+		o.Report = report
+	}
+}
+
+// WithWorkers sets the number of RSL entries that are verified concurrently.
+func WithWorkers(workers int) Option {
+	return func(o *Options) {
+		o.Workers = workers
+	}
+}
+
+// WithCheckpoints indicates that full verification may begin from the latest
+// RSL checkpoint approved by a threshold of root principals.
+func WithCheckpoints() Option {
+	return func(o *Options) {
+		o.UseCheckpoints = true
+	}
+}
@@ -3,8 +3,11 @@
 
 package verifymergeable
 
+import "github.com/gittuf/gittuf/internal/policy"
+
 type Options struct {
 	BypassRSLForFeatureRef bool
+	Report                 *policy.VerificationReport
 }
 
 type Option func(o *Options)
@@ -14,3 +17,12 @@ func WithBypassRSLForFeatureRef() Option {
 		o.BypassRSLForFeatureRef = true
 	}
 }
+
+// WithReport populates the specified report with the checks performed to
+// determine if the change is mergeable and their outcomes. The report is
+// populated even if the change is not mergeable.
+func WithReport(report *policy.VerificationReport) Option {
+	return func(o *Options) {
+		o.Report = report
+	}
+}
@@ -8,18 +8,26 @@ import (
 	"errors"
 	"fmt"
 	"log/slog"
+	"maps"
+	"slices"
 	"strings"
 
 	rslopts "github.com/gittuf/gittuf/experimental/gittuf/options/rsl"
+	trustpolicyopts "github.com/gittuf/gittuf/experimental/gittuf/options/trustpolicy"
+	"github.com/gittuf/gittuf/internal/gitinterface"
 	"github.com/gittuf/gittuf/internal/policy"
+	policyopts "github.com/gittuf/gittuf/internal/policy/options/policy"
 	"github.com/gittuf/gittuf/internal/rsl"
+	"github.com/gittuf/gittuf/internal/signerverifier/dsse"
+	sslibdsse "github.com/gittuf/gittuf/internal/third_party/go-securesystemslib/dsse"
 	"github.com/gittuf/gittuf/internal/tuf"
 )
 
 var (
-	ErrPushingPolicy     = errors.New("unable to push policy")
-	ErrPullingPolicy     = errors.New("unable to pull policy")
-	ErrNoRemoteSpecified = errors.New("no remote specified to push policy")
+	ErrPushingPolicy      = errors.New("unable to push policy")
+	ErrPullingPolicy      = errors.New("unable to pull policy")
+	ErrNoRemoteSpecified  = errors.New("no remote specified to push policy")
+	ErrInvalidPolicyState = errors.New("invalid policy state")
 )
 
 // PushPolicy pushes the local gittuf policy to the specified remote. As this
@@ -90,6 +98,11 @@ func (r *Repository) DiscardPolicy() error {
 type DelegationWithDepth struct {
 	Delegation tuf.Rule
 	Depth      int
+
+	// PrincipalValidity holds the validity windows of principals as seen
+	// from the rule file that contains Delegation, including windows
+	// inherited from the rule files that delegate to it.
+	PrincipalValidity map[string]*tuf.ValidityWindow
 }
 
 func (r *Repository) ListRules(ctx context.Context, targetRef string) ([]*DelegationWithDepth, error) {
@@ -114,11 +127,12 @@ func (r *Repository) ListRules(ctx context.Context, targetRef string) ([]*Delega
 	delegationsToSearch := []*DelegationWithDepth{}
 	allDelegations := []*DelegationWithDepth{}
 
+	topLevelPrincipalValidity := mergePrincipalValidity(nil, topLevelTargetsMetadata.GetPrincipalValidityWindows())
 	for _, topLevelDelegation := range topLevelTargetsMetadata.GetRules() {
 		if topLevelDelegation.ID() == tuf.AllowRuleName {
 			continue
 		}
-		delegationsToSearch = append(delegationsToSearch, &DelegationWithDepth{Delegation: topLevelDelegation, Depth: 0})
+		delegationsToSearch = append(delegationsToSearch, &DelegationWithDepth{Delegation: topLevelDelegation, Depth: 0, PrincipalValidity: topLevelPrincipalValidity})
 	}
 
 	seenRoles := map[string]bool{policy.TargetsRoleName: true}
@@ -145,11 +159,12 @@ func (r *Repository) ListRules(ctx context.Context, targetRef string) ([]*Delega
 			// We construct localDelegations first so that we preserve the order
 			// of delegations in currentMetadata in delegationsToSearch
 			localDelegations := []*DelegationWithDepth{}
+			localPrincipalValidity := mergePrincipalValidity(currentDelegation.PrincipalValidity, currentMetadata.GetPrincipalValidityWindows())
 			for _, delegation := range currentMetadata.GetRules() {
 				if delegation.ID() == tuf.AllowRuleName {
 					continue
 				}
-				localDelegations = append(localDelegations, &DelegationWithDepth{Delegation: delegation, Depth: currentDelegation.Depth + 1})
+				localDelegations = append(localDelegations, &DelegationWithDepth{Delegation: delegation, Depth: currentDelegation.Depth + 1, PrincipalValidity: localPrincipalValidity})
 			}
 
 			if len(localDelegations) > 0 {
@@ -161,6 +176,23 @@ func (r *Repository) ListRules(ctx context.Context, targetRef string) ([]*Delega
 	return allDelegations, nil
 }
 
+// mergePrincipalValidity returns a new map containing the windows in inherited
+// overridden by the windows in current, or nil if neither has any windows.
+func mergePrincipalValidity(inherited, current map[string]*tuf.ValidityWindow) map[string]*tuf.ValidityWindow {
+	if len(inherited) == 0 && len(current) == 0 {
+		return nil
+	}
+
+	merged := make(map[string]*tuf.ValidityWindow, len(inherited)+len(current))
+	for principalID, window := range inherited {
+		merged[principalID] = window
+	}
+	for principalID, window := range current {
+		merged[principalID] = window
+	}
+	return merged
+}
+
 func (r *Repository) ListPrincipals(ctx context.Context, targetRef, policyName string) (map[string]tuf.Principal, error) {
 	if !strings.HasPrefix(targetRef, "refs/gittuf/") {
 		targetRef = "refs/gittuf/" + targetRef
@@ -217,6 +249,299 @@ func (r *Repository) ListHooks(ctx context.Context, targetRef string) (map[tuf.H
 	return state.Hooks, nil
 }
 
+// DiffPolicy returns the semantic differences between two policy states. Each
+// state is identified either by a policy reference (policy or policy-staging)
+// or by the ID of an RSL entry for one of those references.
+func (r *Repository) DiffPolicy(ctx context.Context, from, to string) (*policy.StateDiff, error) {
+	slog.Debug(fmt.Sprintf("Loading policy state '%s'...", from))
+	fromState, err := r.loadPolicyState(ctx, from)
+	if err != nil {
+		return nil, err
+	}
+
+	slog.Debug(fmt.Sprintf("Loading policy state '%s'...", to))
+	toState, err := r.loadPolicyState(ctx, to)
+	if err != nil {
+		return nil, err
+	}
+
+	return policy.DiffStates(fromState, toState)
+}
+
+// loadPolicyState loads the policy state identified by the policy reference or
+// RSL entry ID. The staging reference is loaded without checking the RSL as
+// staged changes may not be recorded in it yet.
+func (r *Repository) loadPolicyState(ctx context.Context, target string) (*policy.State, error) {
+	targetRef := target
+	if !strings.HasPrefix(targetRef, "refs/gittuf/") {
+		targetRef = "refs/gittuf/" + targetRef
+	}
+
+	switch targetRef {
+	case policy.PolicyRef:
+		return policy.LoadCurrentState(ctx, r.r, policy.PolicyRef)
+	case policy.PolicyStagingRef:
+		return policy.LoadCurrentState(ctx, r.r, policy.PolicyStagingRef, policyopts.BypassRSL())
+	}
+
+	entryID, err := gitinterface.NewHash(target)
+	if err != nil {
+		return nil, fmt.Errorf("%w: '%s' is neither a policy reference nor an RSL entry ID", ErrInvalidPolicyState, target)
+	}
+
+	entry, err := rsl.GetEntry(r.r, entryID)
+	if err != nil {
+		return nil, err
+	}
+
+	referenceEntry, isReferenceEntry := entry.(*rsl.ReferenceEntry)
+	if !isReferenceEntry || (referenceEntry.RefName != policy.PolicyRef && referenceEntry.RefName != policy.PolicyStagingRef) {
+		return nil, fmt.Errorf("%w: RSL entry '%s' is not for a policy reference", ErrInvalidPolicyState, target)
+	}
+
+	return policy.LoadState(ctx, r.r, referenceEntry)
+}
+
+// ExplainPolicy returns how the verifiers for the namespace are determined
+// using the policy in targetRef. The namespace must be prefixed with "git:" or
+// "file:".
+func (r *Repository) ExplainPolicy(ctx context.Context, targetRef, path string) (*policy.PathExplanation, error) {
+	if !strings.HasPrefix(targetRef, "refs/gittuf/") {
+		targetRef = "refs/gittuf/" + targetRef
+	}
+
+	slog.Debug("Loading current policy...")
+	state, err := policy.LoadCurrentState(ctx, r.r, targetRef)
+	if err != nil {
+		return nil, err
+	}
+
+	return state.ExplainPath(path)
+}
+
+// LintPolicy analyzes the policy state for problems such as rules that can
+// never match, thresholds that can't be met, and unused principals. The state is
+// identified either by a policy reference (policy or policy-staging) or by the
+// ID of an RSL entry for one of those references.
+func (r *Repository) LintPolicy(ctx context.Context, target string) ([]*policy.LintFinding, error) {
+	slog.Debug(fmt.Sprintf("Loading policy state '%s'...", target))
+	state, err := r.loadPolicyState(ctx, target)
+	if err != nil {
+		return nil, err
+	}
+
+	return state.Lint()
+}
+
+// ExportPolicy returns the declarative policy document for the root of trust
+// and the rule files in the policy state. The state is identified either by a
+// policy reference (policy or policy-staging) or by the ID of an RSL entry for
+// one of those references.
+func (r *Repository) ExportPolicy(ctx context.Context, target string) (*policy.Document, error) {
+	slog.Debug(fmt.Sprintf("Loading policy state '%s'...", target))
+	state, err := r.loadPolicyState(ctx, target)
+	if err != nil {
+		return nil, err
+	}
+
+	return state.ToDocument()
+}
+
+// ImportPolicy stages the changes needed for the policy to match the
+// declarative policy document. Each metadata file changed as a result is
+// signed using the signer. The names of the metadata files that were updated
+// or removed are returned; if there are none, the policy is not committed.
+func (r *Repository) ImportPolicy(ctx context.Context, signer sslibdsse.SignerVerifier, document *policy.Document, signCommit bool, opts ...trustpolicyopts.Option) ([]string, error) {
+	if signCommit {
+		slog.Debug("Checking if Git signing is configured...")
+		err := r.r.CanSign()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	options := &trustpolicyopts.Options{}
+	for _, fn := range opts {
+		fn(options)
+	}
+
+	keyID, err := signer.KeyID()
+	if err != nil {
+		return nil, err
+	}
+
+	slog.Debug("Loading current policy...")
+	state, err := policy.LoadCurrentState(ctx, r.r, policy.PolicyStagingRef, policyopts.BypassRSL())
+	if err != nil {
+		return nil, err
+	}
+
+	// The signer must be trusted for the root of trust in the current policy
+	// to sign changes to it
+	rootAuthorized := true
+	if _, err := r.loadRootMetadata(state, keyID); err != nil {
+		if !errors.Is(err, ErrUnauthorizedKey) {
+			return nil, err
+		}
+		rootAuthorized = false
+	}
+
+	slog.Debug("Applying policy document...")
+	updated, removed, err := state.ApplyDocument(document)
+	if err != nil {
+		return nil, err
+	}
+	if len(updated) == 0 && len(removed) == 0 {
+		slog.Debug("Policy document does not change the policy")
+		return nil, nil
+	}
+
+	for _, name := range updated {
+		var env *sslibdsse.Envelope
+		switch name {
+		case policy.RootRoleName:
+			if !rootAuthorized {
+				return nil, ErrUnauthorizedKey
+			}
+			env = state.Metadata.RootEnvelope
+		case policy.TargetsRoleName:
+			env = state.Metadata.TargetsEnvelope
+		default:
+			env = state.Metadata.DelegationEnvelopes[name]
+		}
+
+		slog.Debug(fmt.Sprintf("Signing updated metadata '%s' using '%s'...", name, keyID))
+		env, err = dsse.SignEnvelope(ctx, env, signer)
+		if err != nil {
+			return nil, err
+		}
+
+		switch name {
+		case policy.RootRoleName:
+			state.Metadata.RootEnvelope = env
+		case policy.TargetsRoleName:
+			state.Metadata.TargetsEnvelope = env
+		default:
+			state.Metadata.DelegationEnvelopes[name] = env
+		}
+	}
+
+	commitMessage := "Import policy document"
+	if len(updated) != 0 {
+		commitMessage += fmt.Sprintf("\n\nUpdated: %s", strings.Join(updated, ", "))
+	}
+	if len(removed) != 0 {
+		commitMessage += fmt.Sprintf("\n\nRemoved: %s", strings.Join(removed, ", "))
+	}
+
+	slog.Debug("Committing policy...")
+	if err := state.Commit(r.r, commitMessage, options.CreateRSLEntry, signCommit); err != nil {
+		return nil, err
+	}
+
+	return append(updated, removed...), nil
+}
+
+// ExportSigningBundle returns a signing bundle with the envelopes of the staged
+// policy. The bundle can be signed by principals without access to the
+// repository, and the signatures merged back using ImportSignatures.
+func (r *Repository) ExportSigningBundle(ctx context.Context) (*policy.SigningBundle, error) {
+	slog.Debug("Loading current policy...")
+	state, err := policy.LoadCurrentState(ctx, r.r, policy.PolicyStagingRef, policyopts.BypassRSL())
+	if err != nil {
+		return nil, err
+	}
+
+	policyID, err := r.r.GetReference(policy.PolicyStagingRef)
+	if err != nil {
+		return nil, err
+	}
+
+	return state.NewSigningBundle(policyID.String()), nil
+}
+
+// ImportSignatures adds the signatures in the signing bundle to the staged
+// policy. The staged metadata must not have changed since the bundle was
+// exported. The IDs of the keys whose signatures were added are returned for
+// each metadata file; if there are none, the policy is not committed.
+func (r *Repository) ImportSignatures(ctx context.Context, bundle *policy.SigningBundle, signCommit bool, opts ...trustpolicyopts.Option) (map[string][]string, error) {
+	if signCommit {
+		slog.Debug("Checking if Git signing is configured...")
+		err := r.r.CanSign()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	options := &trustpolicyopts.Options{}
+	for _, fn := range opts {
+		fn(options)
+	}
+
+	slog.Debug("Loading current policy...")
+	state, err := policy.LoadCurrentState(ctx, r.r, policy.PolicyStagingRef, policyopts.BypassRSL())
+	if err != nil {
+		return nil, err
+	}
+
+	slog.Debug("Merging signatures from signing bundle...")
+	added, err := state.MergeSigningBundle(ctx, bundle)
+	if err != nil {
+		return nil, err
+	}
+	if len(added) == 0 {
+		slog.Debug("Signing bundle has no new signatures")
+		return added, nil
+	}
+
+	commitMessage := "Import signatures from signing bundle\n"
+	for _, name := range slices.Sorted(maps.Keys(added)) {
+		commitMessage += fmt.Sprintf("\n%s: %s", name, strings.Join(added[name], ", "))
+	}
+
+	slog.Debug("Committing policy...")
+	if err := state.Commit(r.r, commitMessage, options.CreateRSLEntry, signCommit); err != nil {
+		return nil, err
+	}
+
+	return added, nil
+}
+
+// GetPolicyStatus returns the signatures collected for each metadata file
+// changed in the staged policy compared to the applied policy, and whether the
+// staged policy can be applied.
+func (r *Repository) GetPolicyStatus(ctx context.Context) (*policy.StagedPolicyStatus, error) {
+	slog.Debug("Loading staged policy...")
+	stagedState, err := policy.LoadCurrentState(ctx, r.r, policy.PolicyStagingRef, policyopts.BypassRSL())
+	if err != nil {
+		return nil, err
+	}
+
+	slog.Debug("Loading applied policy...")
+	appliedState, err := policy.LoadCurrentState(ctx, r.r, policy.PolicyRef)
+	if err != nil {
+		if !errors.Is(err, rsl.ErrRSLEntryNotFound) {
+			return nil, err
+		}
+		// No policy has been applied yet
+		appliedState = nil
+	}
+
+	status, err := policy.GetStagedStatus(ctx, appliedState, stagedState)
+	if err != nil {
+		return nil, err
+	}
+
+	slog.Debug("Checking if staged policy can be applied...")
+	if err := policy.CheckApply(ctx, r.r); err != nil {
+		status.ApplyError = err.Error()
+	} else {
+		status.CanApply = true
+	}
+
+	return status, nil
+}
+
 func (r *Repository) StagePolicy(ctx context.Context, remoteName string, localOnly, signCommit bool) error {
 	if signCommit {
 		slog.Debug("Checking if Git signing is configured...")
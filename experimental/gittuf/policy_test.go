@@ -7,14 +7,18 @@ import (
 	"context"
 	"testing"
 
+	trustpolicyopts "github.com/gittuf/gittuf/experimental/gittuf/options/trustpolicy"
+	"github.com/gittuf/gittuf/internal/common"
 	"github.com/gittuf/gittuf/internal/common/set"
 	"github.com/gittuf/gittuf/internal/gitinterface"
 	"github.com/gittuf/gittuf/internal/policy"
 	"github.com/gittuf/gittuf/internal/rsl"
 	"github.com/gittuf/gittuf/internal/signerverifier/gpg"
 	"github.com/gittuf/gittuf/internal/tuf"
+	tufv01 "github.com/gittuf/gittuf/internal/tuf/v01"
 	tufv02 "github.com/gittuf/gittuf/internal/tuf/v02"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestPushPolicy(t *testing.T) {
@@ -306,3 +310,208 @@ func TestListPrincipals(t *testing.T) {
 		assert.Nil(t, principals)
 	})
 }
+
+func TestDiffPolicy(t *testing.T) {
+	repo := createTestRepositoryWithPolicy(t, "")
+
+	t.Run("applied policy against first policy entry", func(t *testing.T) {
+		firstEntry, _, err := rsl.GetFirstReferenceUpdaterEntryForRef(repo.r, policy.PolicyRef)
+		assert.Nil(t, err)
+
+		diff, err := repo.DiffPolicy(testCtx, firstEntry.GetID().String(), policy.PolicyRef)
+		assert.Nil(t, err)
+
+		targetsSigner := setupSSHKeysForSigning(t, targetsKeyBytes, targetsPubKeyBytes)
+		assert.Equal(t, []string{targetsSigner.MetadataKey().KeyID}, diff.PrimaryRuleFile.PrincipalsAdded)
+		if assert.Len(t, diff.RuleFiles, 1) {
+			assert.Equal(t, policy.TargetsRoleName, diff.RuleFiles[0].Name)
+			assert.True(t, diff.RuleFiles[0].Added)
+		}
+	})
+
+	t.Run("applied policy against staged policy", func(t *testing.T) {
+		diff, err := repo.DiffPolicy(testCtx, "policy", "policy-staging")
+		assert.Nil(t, err)
+		assert.True(t, diff.IsEmpty())
+
+		targetsSigner := setupSSHKeysForSigning(t, targetsKeyBytes, targetsPubKeyBytes)
+		err = repo.AddDelegation(testCtx, targetsSigner, policy.TargetsRoleName, "protect-release", []string{"157507bbe151e378ce8126c1dcfe043cdd2db96e"}, []string{"git:refs/heads/release"}, 1, false)
+		assert.Nil(t, err)
+
+		diff, err = repo.DiffPolicy(testCtx, "policy", "policy-staging")
+		assert.Nil(t, err)
+		if assert.Len(t, diff.RuleFiles, 1) {
+			assert.Len(t, diff.RuleFiles[0].Rules.Added, 1)
+			assert.Equal(t, "protect-release", diff.RuleFiles[0].Rules.Added[0].Name)
+		}
+	})
+
+	t.Run("invalid policy state", func(t *testing.T) {
+		_, err := repo.DiffPolicy(testCtx, "policy", "unknown")
+		assert.ErrorIs(t, err, ErrInvalidPolicyState)
+
+		// The latest RSL entry is for the policy staging reference, so use an
+		// entry for another reference
+		entryID := common.CreateTestRSLReferenceEntryCommit(t, repo.r, rsl.NewReferenceEntry("refs/heads/main", gitinterface.ZeroHash), gpgKeyBytes)
+		_, err = repo.DiffPolicy(testCtx, "policy", entryID.String())
+		assert.ErrorIs(t, err, ErrInvalidPolicyState)
+	})
+}
+
+func TestExplainPolicy(t *testing.T) {
+	repo := createTestRepositoryWithPolicy(t, "")
+
+	explanation, err := repo.ExplainPolicy(testCtx, policy.PolicyRef, "git:refs/heads/main")
+	assert.Nil(t, err)
+	if assert.Len(t, explanation.Steps, 1) {
+		assert.Equal(t, "protect-main", explanation.Steps[0].Rule)
+		assert.True(t, explanation.Steps[0].Matched)
+	}
+	if assert.Len(t, explanation.Verifiers, 1) {
+		assert.Equal(t, []string{"157507bbe151e378ce8126c1dcfe043cdd2db96e"}, explanation.Verifiers[0].Principals)
+	}
+
+	explanation, err = repo.ExplainPolicy(testCtx, policy.PolicyRef, "git:refs/heads/feature")
+	assert.Nil(t, err)
+	assert.Empty(t, explanation.Verifiers)
+
+	_, err = repo.ExplainPolicy(testCtx, policy.PolicyRef, "refs/heads/main")
+	assert.ErrorIs(t, err, policy.ErrInvalidNamespace)
+}
+
+func TestLintPolicy(t *testing.T) {
+	repo := createTestRepositoryWithPolicy(t, "")
+
+	findings, err := repo.LintPolicy(testCtx, policy.PolicyRef)
+	assert.Nil(t, err)
+	assert.Empty(t, findings)
+
+	// The staged policy is linted before it's recorded in the RSL
+	targetsSigner := setupSSHKeysForSigning(t, targetsKeyBytes, targetsPubKeyBytes)
+	targetsPubKey := tufv01.NewKeyFromSSLibKey(targetsSigner.MetadataKey())
+	err = repo.AddPrincipalToTargets(testCtx, targetsSigner, policy.TargetsRoleName, []tuf.Principal{targetsPubKey}, false)
+	assert.Nil(t, err)
+
+	findings, err = repo.LintPolicy(testCtx, policy.PolicyStagingRef)
+	assert.Nil(t, err)
+	if assert.Len(t, findings, 1) {
+		assert.Equal(t, policy.LintCheckUnusedPrincipal, findings[0].Check)
+		assert.False(t, policy.HasLintErrors(findings))
+	}
+}
+
+func TestExportImportPolicy(t *testing.T) {
+	repo := createTestRepositoryWithPolicy(t, "")
+	targetsSigner := setupSSHKeysForSigning(t, targetsKeyBytes, targetsPubKeyBytes)
+
+	document, err := repo.ExportPolicy(testCtx, policy.PolicyRef)
+	require.Nil(t, err)
+	require.Contains(t, document.RuleFiles, policy.TargetsRoleName)
+
+	// Importing the exported document doesn't change the policy
+	changed, err := repo.ImportPolicy(testCtx, targetsSigner, document, false)
+	assert.Nil(t, err)
+	assert.Empty(t, changed)
+
+	// Changes to the primary rule file are signed using the targets key
+	rules := document.RuleFiles[policy.TargetsRoleName].Rules
+	rules[0].Patterns = append(rules[0].Patterns, "git:refs/heads/release")
+	changed, err = repo.ImportPolicy(testCtx, targetsSigner, document, false, trustpolicyopts.WithRSLEntry())
+	assert.Nil(t, err)
+	assert.Equal(t, []string{policy.TargetsRoleName}, changed)
+
+	err = repo.ApplyPolicy(testCtx, "", true, false)
+	assert.Nil(t, err)
+
+	applied, err := repo.ExportPolicy(testCtx, policy.PolicyRef)
+	require.Nil(t, err)
+	assert.Equal(t, []string{"git:refs/heads/main", "git:refs/heads/release"}, applied.RuleFiles[policy.TargetsRoleName].Rules[0].Patterns)
+
+	// Changes to the root of trust must be signed by a root principal
+	document.Root.RepositoryLocation = "https://example.com/repository"
+	_, err = repo.ImportPolicy(testCtx, targetsSigner, document, false)
+	assert.ErrorIs(t, err, ErrUnauthorizedKey)
+}
+
+func TestSigningBundle(t *testing.T) {
+	repo := createTestRepositoryWithPolicy(t, "")
+
+	rootSigner := setupSSHKeysForSigning(t, rootKeyBytes, rootPubKeyBytes)
+	targetsSigner := setupSSHKeysForSigning(t, targetsKeyBytes, targetsPubKeyBytes)
+	targetsPubKey := tufv01.NewKeyFromSSLibKey(targetsSigner.MetadataKey())
+
+	err := repo.AddRootKey(testCtx, rootSigner, targetsPubKey, false)
+	require.Nil(t, err)
+	err = repo.UpdateRootThreshold(testCtx, rootSigner, 2, false)
+	require.Nil(t, err)
+
+	bundle, err := repo.ExportSigningBundle(testCtx)
+	require.Nil(t, err)
+	stagingID, err := repo.r.GetReference(policy.PolicyStagingRef)
+	require.Nil(t, err)
+	assert.Equal(t, stagingID.String(), bundle.PolicyID)
+
+	// The second root principal signs the bundle without the repository
+	require.Nil(t, bundle.Sign(testCtx, targetsSigner, policy.RootRoleName))
+
+	added, err := repo.ImportSignatures(testCtx, bundle, false, trustpolicyopts.WithRSLEntry())
+	require.Nil(t, err)
+	assert.Equal(t, map[string][]string{policy.RootRoleName: {targetsPubKey.KeyID}}, added)
+
+	err = repo.ApplyPolicy(testCtx, "", true, false)
+	assert.Nil(t, err)
+
+	// The bundle no longer matches the staged policy once it changes
+	err = repo.UpdateRootThreshold(testCtx, rootSigner, 1, false)
+	require.Nil(t, err)
+	_, err = repo.ImportSignatures(testCtx, bundle, false)
+	assert.ErrorIs(t, err, policy.ErrBundleMetadataMismatch)
+}
+
+func TestGetPolicyStatus(t *testing.T) {
+	repo := createTestRepositoryWithPolicy(t, "")
+
+	rootSigner := setupSSHKeysForSigning(t, rootKeyBytes, rootPubKeyBytes)
+	targetsSigner := setupSSHKeysForSigning(t, targetsKeyBytes, targetsPubKeyBytes)
+	targetsPubKey := tufv01.NewKeyFromSSLibKey(targetsSigner.MetadataKey())
+
+	status, err := repo.GetPolicyStatus(testCtx)
+	require.Nil(t, err)
+	assert.Empty(t, status.Metadata)
+	assert.True(t, status.CanApply)
+
+	err = repo.AddRootKey(testCtx, rootSigner, targetsPubKey, false)
+	require.Nil(t, err)
+	err = repo.UpdateRootThreshold(testCtx, rootSigner, 2, false, trustpolicyopts.WithRSLEntry())
+	require.Nil(t, err)
+
+	status, err = repo.GetPolicyStatus(testCtx)
+	require.Nil(t, err)
+	require.Len(t, status.Metadata, 1)
+	assert.Equal(t, policy.RootRoleName, status.Metadata[0].Name)
+	assert.Equal(t, policy.MetadataModified, status.Metadata[0].Change)
+	require.Len(t, status.Metadata[0].Signers, 2)
+
+	appliedRootStatus := status.Metadata[0].Signers[0]
+	assert.Equal(t, policy.AppliedRootVerifierName, appliedRootStatus.Verifier)
+	assert.True(t, appliedRootStatus.ThresholdMet)
+
+	rootStatus := status.Metadata[0].Signers[1]
+	assert.Equal(t, policy.RootRoleName, rootStatus.Verifier)
+	assert.Equal(t, 2, rootStatus.Threshold)
+	assert.Equal(t, []string{targetsPubKey.KeyID}, rootStatus.Pending)
+	assert.False(t, rootStatus.ThresholdMet)
+
+	assert.False(t, status.CanApply)
+	assert.NotEmpty(t, status.ApplyError)
+
+	// Once the second root principal signs, the staged policy can be applied
+	err = repo.SignRoot(testCtx, targetsSigner, false, trustpolicyopts.WithRSLEntry())
+	require.Nil(t, err)
+
+	status, err = repo.GetPolicyStatus(testCtx)
+	require.Nil(t, err)
+	assert.Empty(t, status.Metadata[0].Signers[1].Pending)
+	assert.True(t, status.Metadata[0].Signers[1].ThresholdMet)
+	assert.True(t, status.CanApply)
+}
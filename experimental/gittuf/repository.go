@@ -20,6 +20,7 @@ const (
 var (
 	ErrUnauthorizedKey    = errors.New("unauthorized key presented when updating gittuf metadata")
 	ErrCannotReinitialize = errors.New("cannot reinitialize metadata, it exists already")
+	ErrExpiryInPast       = errors.New("metadata expiry must be in the future")
 )
 
 // InDebugMode returns true if gittuf is currently in debug mode.
@@ -12,6 +12,7 @@ import (
 	"errors"
 	"fmt"
 	"log/slog"
+	"time"
 
 	"github.com/gittuf/gittuf/experimental/gittuf/options/root"
 	trustpolicyopts "github.com/gittuf/gittuf/experimental/gittuf/options/trustpolicy"
@@ -19,14 +20,18 @@ import (
 	"github.com/gittuf/gittuf/internal/gitinterface"
 	"github.com/gittuf/gittuf/internal/policy"
 	policyopts "github.com/gittuf/gittuf/internal/policy/options/policy"
+	"github.com/gittuf/gittuf/internal/rsl"
 	"github.com/gittuf/gittuf/internal/signerverifier/common"
 	"github.com/gittuf/gittuf/internal/signerverifier/dsse"
+	"github.com/gittuf/gittuf/internal/signerverifier/gpg"
 	"github.com/gittuf/gittuf/internal/signerverifier/sigstore"
 	"github.com/gittuf/gittuf/internal/signerverifier/ssh"
 	sslibdsse "github.com/gittuf/gittuf/internal/third_party/go-securesystemslib/dsse"
 	"github.com/gittuf/gittuf/internal/tuf"
 	tufv01 "github.com/gittuf/gittuf/internal/tuf/v01"
 	tufv02 "github.com/gittuf/gittuf/internal/tuf/v02"
+	tufv03 "github.com/gittuf/gittuf/internal/tuf/v03"
+	tufv04 "github.com/gittuf/gittuf/internal/tuf/v04"
 	"github.com/secure-systems-lab/go-securesystemslib/signerverifier"
 )
 
@@ -59,6 +64,8 @@ func (r *Repository) InitializeRoot(ctx context.Context, signer sslibdsse.Signer
 	switch signer := signer.(type) {
 	case *ssh.Signer:
 		publicKeyRaw = signer.MetadataKey()
+	case *gpg.Signer:
+		publicKeyRaw = signer.MetadataKey()
 	case *sigstore.Signer:
 		publicKeyRaw, err = signer.MetadataKey()
 		if err != nil {
@@ -221,6 +228,52 @@ func (r *Repository) RemoveRootKey(ctx context.Context, signer sslibdsse.SignerV
 	return r.updateRootMetadata(ctx, state, signer, rootMetadata, commitMessage, options.CreateRSLEntry, signCommit)
 }
 
+// RotateRootKey is the interface for the user to replace a key trusted to sign
+// the Root role with a new key. If gracePeriod is set, the old key continues to
+// be trusted until the grace period ends, so that changes already signed using
+// it can still be verified.
+func (r *Repository) RotateRootKey(ctx context.Context, signer sslibdsse.SignerVerifier, oldKeyID string, newKey tuf.Principal, gracePeriod time.Duration, signCommit bool, opts ...trustpolicyopts.Option) error {
+	if signCommit {
+		slog.Debug("Checking if Git signing is configured...")
+		err := r.r.CanSign()
+		if err != nil {
+			return err
+		}
+	}
+
+	options := &trustpolicyopts.Options{}
+	for _, fn := range opts {
+		fn(options)
+	}
+
+	rootKeyID, err := signer.KeyID()
+	if err != nil {
+		return err
+	}
+
+	slog.Debug("Loading current policy...")
+	state, err := policy.LoadCurrentState(ctx, r.r, policy.PolicyStagingRef, policyopts.BypassRSL())
+	if err != nil {
+		return err
+	}
+
+	rootMetadata, err := r.loadRootMetadata(state, rootKeyID)
+	if err != nil {
+		return err
+	}
+
+	slog.Debug("Rotating root key...")
+	if err := rootMetadata.RotateRootKey(oldKeyID, newKey, getGracePeriodEnd(gracePeriod)); err != nil {
+		return err
+	}
+
+	commitMessage := fmt.Sprintf("Rotate root key '%s' to '%s'", oldKeyID, newKey.ID())
+	if gracePeriod > 0 {
+		commitMessage += fmt.Sprintf("\n\nThe old key is trusted for a grace period of %s.", gracePeriod)
+	}
+	return r.updateRootMetadata(ctx, state, signer, rootMetadata, commitMessage, options.CreateRSLEntry, signCommit)
+}
+
 // AddTopLevelTargetsKey is the interface for the user to add an authorized key
 // for the top level Targets role / policy file.
 func (r *Repository) AddTopLevelTargetsKey(ctx context.Context, signer sslibdsse.SignerVerifier, targetsKey tuf.Principal, signCommit bool, opts ...trustpolicyopts.Option) error {
@@ -303,6 +356,123 @@ func (r *Repository) RemoveTopLevelTargetsKey(ctx context.Context, signer sslibd
 	return r.updateRootMetadata(ctx, state, signer, rootMetadata, commitMessage, options.CreateRSLEntry, signCommit)
 }
 
+// RotateTopLevelTargetsKey is the interface for the user to replace a key
+// trusted to sign the top level Targets role / policy file with a new key. If
+// gracePeriod is set, the old key continues to be trusted until the grace
+// period ends, so that changes already signed using it can still be verified.
+func (r *Repository) RotateTopLevelTargetsKey(ctx context.Context, signer sslibdsse.SignerVerifier, oldKeyID string, newKey tuf.Principal, gracePeriod time.Duration, signCommit bool, opts ...trustpolicyopts.Option) error {
+	if signCommit {
+		slog.Debug("Checking if Git signing is configured...")
+		err := r.r.CanSign()
+		if err != nil {
+			return err
+		}
+	}
+
+	options := &trustpolicyopts.Options{}
+	for _, fn := range opts {
+		fn(options)
+	}
+
+	rootKeyID, err := signer.KeyID()
+	if err != nil {
+		return err
+	}
+
+	slog.Debug("Loading current policy...")
+	state, err := policy.LoadCurrentState(ctx, r.r, policy.PolicyStagingRef, policyopts.BypassRSL())
+	if err != nil {
+		return err
+	}
+
+	rootMetadata, err := r.loadRootMetadata(state, rootKeyID)
+	if err != nil {
+		return err
+	}
+
+	slog.Debug("Rotating policy key...")
+	if err := rootMetadata.RotatePrimaryRuleFileKey(oldKeyID, newKey, getGracePeriodEnd(gracePeriod)); err != nil {
+		return err
+	}
+
+	commitMessage := fmt.Sprintf("Rotate policy key '%s' to '%s'", oldKeyID, newKey.ID())
+	if gracePeriod > 0 {
+		commitMessage += fmt.Sprintf("\n\nThe old key is trusted for a grace period of %s.", gracePeriod)
+	}
+	return r.updateRootMetadata(ctx, state, signer, rootMetadata, commitMessage, options.CreateRSLEntry, signCommit)
+}
+
+// RevokeKey is the interface for the user to record that a key is compromised.
+// Signatures made using the key after the RSL entry identified by
+// revokedAfterEntryID, or after revokedAfterTime, are not trusted, even when
+// verifying RSL entries using policy states from before the revocation. Exactly
+// one of the two must be set.
+func (r *Repository) RevokeKey(ctx context.Context, signer sslibdsse.SignerVerifier, key tuf.Principal, revokedAfterEntryID string, revokedAfterTime time.Time, signCommit bool, opts ...trustpolicyopts.Option) error {
+	if signCommit {
+		slog.Debug("Checking if Git signing is configured...")
+		err := r.r.CanSign()
+		if err != nil {
+			return err
+		}
+	}
+
+	options := &trustpolicyopts.Options{}
+	for _, fn := range opts {
+		fn(options)
+	}
+
+	rootKeyID, err := signer.KeyID()
+	if err != nil {
+		return err
+	}
+
+	revokedAfter := revokedAfterTime.Format(time.RFC3339)
+	if revokedAfterEntryID != "" {
+		slog.Debug("Checking RSL entry the key is revoked after exists...")
+		entryID, err := gitinterface.NewHash(revokedAfterEntryID)
+		if err != nil {
+			return err
+		}
+		if _, err := rsl.GetEntry(r.r, entryID); err != nil {
+			return fmt.Errorf("unable to find RSL entry '%s': %w", revokedAfterEntryID, err)
+		}
+		revokedAfter = fmt.Sprintf("RSL entry '%s'", revokedAfterEntryID)
+	}
+
+	slog.Debug("Loading current policy...")
+	state, err := policy.LoadCurrentState(ctx, r.r, policy.PolicyStagingRef, policyopts.BypassRSL())
+	if err != nil {
+		return err
+	}
+
+	rootMetadata, err := r.loadRootMetadata(state, rootKeyID)
+	if err != nil {
+		return err
+	}
+
+	slog.Debug("Revoking key...")
+	if err := rootMetadata.RevokeKey(key, revokedAfterEntryID, revokedAfterTime); err != nil {
+		return err
+	}
+
+	commitMessage := fmt.Sprintf("Revoke key '%s' after %s", key.ID(), revokedAfter)
+	return r.updateRootMetadata(ctx, state, signer, rootMetadata, commitMessage, options.CreateRSLEntry, signCommit)
+}
+
+// GetEntriesAffectedByKeyRevocations returns the RSL entries signed using a key
+// revoked in the staged policy after the key was revoked, either in the entry
+// itself or in the Git object the entry records.
+func (r *Repository) GetEntriesAffectedByKeyRevocations(ctx context.Context) ([]*policy.AffectedEntry, error) {
+	slog.Debug("Loading current policy...")
+	state, err := policy.LoadCurrentState(ctx, r.r, policy.PolicyStagingRef, policyopts.BypassRSL())
+	if err != nil {
+		return nil, err
+	}
+
+	slog.Debug("Searching RSL for entries signed using revoked keys...")
+	return policy.GetEntriesAffectedByKeyRevocations(ctx, r.r, state)
+}
+
 // AddGitHubApp is the interface for the user to add the authorized key for the
 // trusted GitHub app. This key is used to verify GitHub pull request approval
 // attestation signatures recorded by the app.
@@ -570,6 +740,49 @@ func (r *Repository) UpdateTopLevelTargetsThreshold(ctx context.Context, signer
 	return r.updateRootMetadata(ctx, state, signer, rootMetadata, commitMessage, options.CreateRSLEntry, signCommit)
 }
 
+// UpdateRootExpiry sets the expiry of the root metadata. Entries recorded in
+// the RSL after the root metadata expires fail verification.
+func (r *Repository) UpdateRootExpiry(ctx context.Context, signer sslibdsse.SignerVerifier, expires time.Time, signCommit bool, opts ...trustpolicyopts.Option) error {
+	if !expires.After(time.Now()) {
+		return ErrExpiryInPast
+	}
+
+	if signCommit {
+		slog.Debug("Checking if Git signing is configured...")
+		err := r.r.CanSign()
+		if err != nil {
+			return err
+		}
+	}
+
+	options := &trustpolicyopts.Options{}
+	for _, fn := range opts {
+		fn(options)
+	}
+
+	rootKeyID, err := signer.KeyID()
+	if err != nil {
+		return err
+	}
+
+	slog.Debug("Loading current policy...")
+	state, err := policy.LoadCurrentState(ctx, r.r, policy.PolicyStagingRef, policyopts.BypassRSL())
+	if err != nil {
+		return err
+	}
+
+	rootMetadata, err := r.loadRootMetadata(state, rootKeyID)
+	if err != nil {
+		return err
+	}
+
+	slog.Debug("Updating root expiry...")
+	rootMetadata.SetExpires(expires.UTC().Format(time.RFC3339))
+
+	commitMessage := fmt.Sprintf("Update root expiry to %s", expires.UTC().Format(time.RFC3339))
+	return r.updateRootMetadata(ctx, state, signer, rootMetadata, commitMessage, options.CreateRSLEntry, signCommit)
+}
+
 // AddGlobalRuleThreshold adds a threshold global rule to the root metadata.
 func (r *Repository) AddGlobalRuleThreshold(ctx context.Context, signer sslibdsse.SignerVerifier, name string, patterns []string, threshold int, signCommit bool, opts ...trustpolicyopts.Option) error {
 	if !dev.InDevMode() {
@@ -606,7 +819,12 @@ func (r *Repository) AddGlobalRuleThreshold(ctx context.Context, signer sslibdss
 	}
 
 	slog.Debug("Adding threshold global rule...")
-	if err := rootMetadata.AddGlobalRule(tufv01.NewGlobalRuleThreshold(name, patterns, threshold)); err != nil {
+	globalRule, err := tufv04.NewGlobalRuleThreshold(name, patterns, threshold)
+	if err != nil {
+		return err
+	}
+
+	if err := rootMetadata.AddGlobalRule(globalRule); err != nil {
 		return err
 	}
 
@@ -649,7 +867,7 @@ func (r *Repository) AddGlobalRuleBlockForcePushes(ctx context.Context, signer s
 		return err
 	}
 
-	globalRule, err := tufv01.NewGlobalRuleBlockForcePushes(name, patterns)
+	globalRule, err := tufv04.NewGlobalRuleBlockForcePushes(name, patterns)
 	if err != nil {
 		return err
 	}
@@ -663,6 +881,153 @@ func (r *Repository) AddGlobalRuleBlockForcePushes(ctx context.Context, signer s
 	return r.updateRootMetadata(ctx, state, signer, rootMetadata, commitMessage, options.CreateRSLEntry, signCommit)
 }
 
+// AddGlobalRuleRequireSignedCommits adds a global rule that requires signed commits to the root metadata.
+func (r *Repository) AddGlobalRuleRequireSignedCommits(ctx context.Context, signer sslibdsse.SignerVerifier, name string, patterns []string, signCommit bool, opts ...trustpolicyopts.Option) error {
+	if !dev.InDevMode() {
+		return dev.ErrNotInDevMode
+	}
+
+	options := &trustpolicyopts.Options{}
+	for _, fn := range opts {
+		fn(options)
+	}
+
+	if signCommit {
+		slog.Debug("Checking if Git signing is configured...")
+		err := r.r.CanSign()
+		if err != nil {
+			return err
+		}
+	}
+
+	rootKeyID, err := signer.KeyID()
+	if err != nil {
+		return err
+	}
+
+	slog.Debug("Loading current policy...")
+	state, err := policy.LoadCurrentState(ctx, r.r, policy.PolicyStagingRef, policyopts.BypassRSL())
+	if err != nil {
+		return err
+	}
+
+	rootMetadata, err := r.loadRootMetadata(state, rootKeyID)
+	if err != nil {
+		return err
+	}
+
+	globalRule, err := tufv04.NewGlobalRuleRequireSignedCommits(name, patterns)
+	if err != nil {
+		return err
+	}
+
+	slog.Debug("Adding require-signed-commits global rule...")
+	if err := rootMetadata.AddGlobalRule(globalRule); err != nil {
+		return err
+	}
+
+	commitMessage := fmt.Sprintf("Add global rule (%s) '%s' to root metadata", tuf.GlobalRuleRequireSignedCommitsType, name)
+	return r.updateRootMetadata(ctx, state, signer, rootMetadata, commitMessage, options.CreateRSLEntry, signCommit)
+}
+
+// AddGlobalRuleMergeStrategy adds a global rule that requires a merge strategy to the root metadata.
+func (r *Repository) AddGlobalRuleMergeStrategy(ctx context.Context, signer sslibdsse.SignerVerifier, name string, patterns []string, strategy string, signCommit bool, opts ...trustpolicyopts.Option) error {
+	if !dev.InDevMode() {
+		return dev.ErrNotInDevMode
+	}
+
+	options := &trustpolicyopts.Options{}
+	for _, fn := range opts {
+		fn(options)
+	}
+
+	if signCommit {
+		slog.Debug("Checking if Git signing is configured...")
+		err := r.r.CanSign()
+		if err != nil {
+			return err
+		}
+	}
+
+	rootKeyID, err := signer.KeyID()
+	if err != nil {
+		return err
+	}
+
+	slog.Debug("Loading current policy...")
+	state, err := policy.LoadCurrentState(ctx, r.r, policy.PolicyStagingRef, policyopts.BypassRSL())
+	if err != nil {
+		return err
+	}
+
+	rootMetadata, err := r.loadRootMetadata(state, rootKeyID)
+	if err != nil {
+		return err
+	}
+
+	globalRule, err := tufv04.NewGlobalRuleMergeStrategy(name, patterns, strategy)
+	if err != nil {
+		return err
+	}
+
+	slog.Debug("Adding merge strategy global rule...")
+	if err := rootMetadata.AddGlobalRule(globalRule); err != nil {
+		return err
+	}
+
+	commitMessage := fmt.Sprintf("Add global rule (%s) '%s' to root metadata", tuf.GlobalRuleMergeStrategyType, name)
+	return r.updateRootMetadata(ctx, state, signer, rootMetadata, commitMessage, options.CreateRSLEntry, signCommit)
+}
+
+// AddGlobalRuleRequireHookExecution adds a global rule that requires hooks to have passed to the root metadata.
+func (r *Repository) AddGlobalRuleRequireHookExecution(ctx context.Context, signer sslibdsse.SignerVerifier, name string, patterns, hookNames []string, signCommit bool, opts ...trustpolicyopts.Option) error {
+	if !dev.InDevMode() {
+		return dev.ErrNotInDevMode
+	}
+
+	options := &trustpolicyopts.Options{}
+	for _, fn := range opts {
+		fn(options)
+	}
+
+	if signCommit {
+		slog.Debug("Checking if Git signing is configured...")
+		err := r.r.CanSign()
+		if err != nil {
+			return err
+		}
+	}
+
+	rootKeyID, err := signer.KeyID()
+	if err != nil {
+		return err
+	}
+
+	slog.Debug("Loading current policy...")
+	state, err := policy.LoadCurrentState(ctx, r.r, policy.PolicyStagingRef, policyopts.BypassRSL())
+	if err != nil {
+		return err
+	}
+
+	rootMetadata, err := r.loadRootMetadata(state, rootKeyID)
+	if err != nil {
+		return err
+	}
+
+	globalRule, err := tufv04.NewGlobalRuleRequireHookExecution(name, patterns, hookNames)
+	if err != nil {
+		return err
+	}
+
+	slog.Debug("Adding require-hook-execution global rule...")
+	if err := rootMetadata.AddGlobalRule(globalRule); err != nil {
+		return err
+	}
+
+	commitMessage := fmt.Sprintf("Add global rule (%s) '%s' to root metadata", tuf.GlobalRuleRequireHookExecutionType, name)
+	return r.updateRootMetadata(ctx, state, signer, rootMetadata, commitMessage, options.CreateRSLEntry, signCommit)
+}
+
 // UpdateGlobalRuleThreshold updates an existing threshold global rule in the root metadata.
 func (r *Repository) UpdateGlobalRuleThreshold(ctx context.Context, signer sslibdsse.SignerVerifier, name string, patterns []string, threshold int, signCommit bool, opts ...trustpolicyopts.Option) error {
 	if !dev.InDevMode() {
@@ -699,7 +1064,12 @@ func (r *Repository) UpdateGlobalRuleThreshold(ctx context.Context, signer sslib
 	}
 
 	slog.Debug("Updating threshold global rule...")
-	if err := rootMetadata.UpdateGlobalRule(tufv01.NewGlobalRuleThreshold(name, patterns, threshold)); err != nil {
+	globalRule, err := tufv04.NewGlobalRuleThreshold(name, patterns, threshold)
+	if err != nil {
+		return err
+	}
+
+	if err := rootMetadata.UpdateGlobalRule(globalRule); err != nil {
 		return err
 	}
 
@@ -742,7 +1112,7 @@ func (r *Repository) UpdateGlobalRuleBlockForcePushes(ctx context.Context, signe
 		return err
 	}
 
-	globalRule, err := tufv01.NewGlobalRuleBlockForcePushes(name, patterns)
+	globalRule, err := tufv04.NewGlobalRuleBlockForcePushes(name, patterns)
 	if err != nil {
 		return err
 	}
@@ -756,6 +1126,153 @@ func (r *Repository) UpdateGlobalRuleBlockForcePushes(ctx context.Context, signe
 	return r.updateRootMetadata(ctx, state, signer, rootMetadata, commitMessage, options.CreateRSLEntry, signCommit)
 }
 
+// UpdateGlobalRuleRequireSignedCommits updates an existing require-signed-commits global rule in the root metadata.
+func (r *Repository) UpdateGlobalRuleRequireSignedCommits(ctx context.Context, signer sslibdsse.SignerVerifier, name string, patterns []string, signCommit bool, opts ...trustpolicyopts.Option) error {
+	if !dev.InDevMode() {
+		return dev.ErrNotInDevMode
+	}
+
+	if signCommit {
+		slog.Debug("Checking if Git signing is configured...")
+		err := r.r.CanSign()
+		if err != nil {
+			return err
+		}
+	}
+
+	options := &trustpolicyopts.Options{}
+	for _, fn := range opts {
+		fn(options)
+	}
+
+	rootKeyID, err := signer.KeyID()
+	if err != nil {
+		return err
+	}
+
+	slog.Debug("Loading current policy...")
+	state, err := policy.LoadCurrentState(ctx, r.r, policy.PolicyStagingRef)
+	if err != nil {
+		return err
+	}
+
+	rootMetadata, err := r.loadRootMetadata(state, rootKeyID)
+	if err != nil {
+		return err
+	}
+
+	globalRule, err := tufv04.NewGlobalRuleRequireSignedCommits(name, patterns)
+	if err != nil {
+		return err
+	}
+
+	slog.Debug("Updating require-signed-commits global rule...")
+	if err := rootMetadata.UpdateGlobalRule(globalRule); err != nil {
+		return err
+	}
+
+	commitMessage := fmt.Sprintf("Update global rule '%s' in root metadata", name)
+	return r.updateRootMetadata(ctx, state, signer, rootMetadata, commitMessage, options.CreateRSLEntry, signCommit)
+}
+
+// UpdateGlobalRuleMergeStrategy updates an existing merge strategy global rule in the root metadata.
+func (r *Repository) UpdateGlobalRuleMergeStrategy(ctx context.Context, signer sslibdsse.SignerVerifier, name string, patterns []string, strategy string, signCommit bool, opts ...trustpolicyopts.Option) error {
+	if !dev.InDevMode() {
+		return dev.ErrNotInDevMode
+	}
+
+	if signCommit {
+		slog.Debug("Checking if Git signing is configured...")
+		err := r.r.CanSign()
+		if err != nil {
+			return err
+		}
+	}
+
+	options := &trustpolicyopts.Options{}
+	for _, fn := range opts {
+		fn(options)
+	}
+
+	rootKeyID, err := signer.KeyID()
+	if err != nil {
+		return err
+	}
+
+	slog.Debug("Loading current policy...")
+	state, err := policy.LoadCurrentState(ctx, r.r, policy.PolicyStagingRef)
+	if err != nil {
+		return err
+	}
+
+	rootMetadata, err := r.loadRootMetadata(state, rootKeyID)
+	if err != nil {
+		return err
+	}
+
+	globalRule, err := tufv04.NewGlobalRuleMergeStrategy(name, patterns, strategy)
+	if err != nil {
+		return err
+	}
+
+	slog.Debug("Updating merge strategy global rule...")
+	if err := rootMetadata.UpdateGlobalRule(globalRule); err != nil {
+		return err
+	}
+
+	commitMessage := fmt.Sprintf("Update global rule '%s' in root metadata", name)
+	return r.updateRootMetadata(ctx, state, signer, rootMetadata, commitMessage, options.CreateRSLEntry, signCommit)
+}
+
+// UpdateGlobalRuleRequireHookExecution updates an existing require-hook-execution global rule in the root metadata.
+func (r *Repository) UpdateGlobalRuleRequireHookExecution(ctx context.Context, signer sslibdsse.SignerVerifier, name string, patterns, hookNames []string, signCommit bool, opts ...trustpolicyopts.Option) error {
+	if !dev.InDevMode() {
+		return dev.ErrNotInDevMode
+	}
+
+	if signCommit {
+		slog.Debug("Checking if Git signing is configured...")
+		err := r.r.CanSign()
+		if err != nil {
+			return err
+		}
+	}
+
+	options := &trustpolicyopts.Options{}
+	for _, fn := range opts {
+		fn(options)
+	}
+
+	rootKeyID, err := signer.KeyID()
+	if err != nil {
+		return err
+	}
+
+	slog.Debug("Loading current policy...")
+	state, err := policy.LoadCurrentState(ctx, r.r, policy.PolicyStagingRef)
+	if err != nil {
+		return err
+	}
+
+	rootMetadata, err := r.loadRootMetadata(state, rootKeyID)
+	if err != nil {
+		return err
+	}
+
+	globalRule, err := tufv04.NewGlobalRuleRequireHookExecution(name, patterns, hookNames)
+	if err != nil {
+		return err
+	}
+
+	slog.Debug("Updating require-hook-execution global rule...")
+	if err := rootMetadata.UpdateGlobalRule(globalRule); err != nil {
+		return err
+	}
+
+	commitMessage := fmt.Sprintf("Update global rule '%s' in root metadata", name)
+	return r.updateRootMetadata(ctx, state, signer, rootMetadata, commitMessage, options.CreateRSLEntry, signCommit)
+}
+
 // RemoveGlobalRule removes a global rule from the root metadata.
 func (r *Repository) RemoveGlobalRule(ctx context.Context, signer sslibdsse.SignerVerifier, name string, signCommit bool, opts ...trustpolicyopts.Option) error {
 	if !dev.InDevMode() {
@@ -841,6 +1358,10 @@ func (r *Repository) AddPropagationDirective(ctx context.Context, signer sslibds
 		directive = tufv01.NewPropagationDirective(directiveName, upstreamRepository, upstreamReference, downstreamReference, downstreamPath)
 	case *tufv02.RootMetadata:
 		directive = tufv02.NewPropagationDirective(directiveName, upstreamRepository, upstreamReference, downstreamReference, downstreamPath)
+	case *tufv03.RootMetadata:
+		directive = tufv03.NewPropagationDirective(directiveName, upstreamRepository, upstreamReference, downstreamReference, downstreamPath)
+	case *tufv04.RootMetadata:
+		directive = tufv04.NewPropagationDirective(directiveName, upstreamRepository, upstreamReference, downstreamReference, downstreamPath)
 	}
 
 	if err := rootMetadata.AddPropagationDirective(directive); err != nil {
@@ -1243,6 +1764,16 @@ func (r *Repository) SignRoot(ctx context.Context, signer sslibdsse.SignerVerifi
 	return state.Commit(r.r, commitMessage, options.CreateRSLEntry, signCommit)
 }
 
+// getGracePeriodEnd returns when a grace period that starts now ends. If no
+// grace period is set, the zero time is returned.
+func getGracePeriodEnd(gracePeriod time.Duration) time.Time {
+	if gracePeriod <= 0 {
+		return time.Time{}
+	}
+
+	return time.Now().Add(gracePeriod)
+}
+
 func (r *Repository) loadRootMetadata(state *policy.State, keyID string) (tuf.RootMetadata, error) {
 	slog.Debug("Loading current root metadata...")
 	rootMetadata, err := state.GetRootMetadata(true)
@@ -1263,6 +1794,8 @@ func (r *Repository) loadRootMetadata(state *policy.State, keyID string) (tuf.Ro
 }
 
 func (r *Repository) updateRootMetadata(ctx context.Context, state *policy.State, signer sslibdsse.SignerVerifier, rootMetadata tuf.RootMetadata, commitMessage string, createRSLEntry, signCommit bool) error {
+	rootMetadata.IncrementMetadataVersion()
+
 	rootMetadataBytes, err := json.Marshal(rootMetadata)
 	if err != nil {
 		return err
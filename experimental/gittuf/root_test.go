@@ -7,14 +7,18 @@ import (
 	"crypto/sha256"
 	"encoding/hex"
 	"testing"
+	"time"
 
 	rootopts "github.com/gittuf/gittuf/experimental/gittuf/options/root"
 	trustpolicyopts "github.com/gittuf/gittuf/experimental/gittuf/options/trustpolicy"
+	"github.com/gittuf/gittuf/internal/common"
 	"github.com/gittuf/gittuf/internal/common/set"
 	"github.com/gittuf/gittuf/internal/dev"
 	"github.com/gittuf/gittuf/internal/gitinterface"
 	"github.com/gittuf/gittuf/internal/policy"
+	"github.com/gittuf/gittuf/internal/rsl"
 	"github.com/gittuf/gittuf/internal/signerverifier/dsse"
+	"github.com/gittuf/gittuf/internal/signerverifier/gpg"
 	"github.com/gittuf/gittuf/internal/signerverifier/ssh"
 	artifacts "github.com/gittuf/gittuf/internal/testartifacts"
 	sslibdsse "github.com/gittuf/gittuf/internal/third_party/go-securesystemslib/dsse"
@@ -85,6 +89,38 @@ func TestInitializeRoot(t *testing.T) {
 
 		assert.Equal(t, location, rootMetadata.GetRepositoryLocation())
 	})
+
+	t.Run("with gpg key", func(t *testing.T) {
+		tempDir := t.TempDir()
+		repo := gitinterface.CreateTestGitRepository(t, tempDir, false)
+
+		r := &Repository{r: repo}
+
+		fingerprint := setupGPGKeyForSigning(t, gpgKeyBytes)
+		signer, err := LoadSigner(r, GPGKeyPrefix+fingerprint)
+		require.Nil(t, err)
+
+		err = r.InitializeRoot(testCtx, signer, false)
+		assert.Nil(t, err)
+		err = r.StagePolicy(testCtx, "", true, false)
+		require.Nil(t, err)
+
+		// Apply verifies the root metadata's signature using the GPG key
+		err = policy.Apply(testCtx, repo, false)
+		assert.Nil(t, err)
+
+		state, err := policy.LoadCurrentState(testCtx, r.r, policy.PolicyRef)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		rootMetadata, err := state.GetRootMetadata(false)
+		if err != nil {
+			t.Fatal(err)
+		}
+		assert.Equal(t, fingerprint, state.Metadata.RootEnvelope.Signatures[0].KeyID)
+		assert.True(t, getRootPrincipalIDs(t, rootMetadata).Has(fingerprint))
+	})
 }
 
 func TestSetRepositoryLocation(t *testing.T) {
@@ -217,6 +253,74 @@ func TestRemoveRootKey(t *testing.T) {
 	assert.Nil(t, err)
 }
 
+func TestRotateRootKey(t *testing.T) {
+	t.Setenv(dev.DevModeKey, "1")
+
+	r := createTestRepositoryWithRoot(t, "")
+
+	originalSigner := setupSSHKeysForSigning(t, rootKeyBytes, rootPubKeyBytes)
+	rootKey := tufv01.NewKeyFromSSLibKey(originalSigner.MetadataKey())
+
+	newSigner := setupSSHKeysForSigning(t, targetsKeyBytes, targetsPubKeyBytes)
+	newRootKey := tufv01.NewKeyFromSSLibKey(newSigner.MetadataKey())
+
+	err := r.RotateRootKey(testCtx, newSigner, rootKey.KeyID, newRootKey, time.Hour, false)
+	assert.ErrorIs(t, err, ErrUnauthorizedKey)
+
+	err = r.RotateRootKey(testCtx, originalSigner, newRootKey.KeyID, newRootKey, time.Hour, false)
+	assert.ErrorIs(t, err, tuf.ErrKeyNotFound)
+
+	err = r.RotateRootKey(testCtx, originalSigner, rootKey.KeyID, newRootKey, time.Hour, false)
+	assert.Nil(t, err)
+	err = r.StagePolicy(testCtx, "", true, false)
+	require.Nil(t, err)
+
+	state, err := policy.LoadCurrentState(testCtx, r.r, policy.PolicyStagingRef)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rootMetadata, err := state.GetRootMetadata(false)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rootPrincipalIDs := getRootPrincipalIDs(t, rootMetadata)
+	assert.Equal(t, set.NewSetFromItems(newRootKey.KeyID), rootPrincipalIDs)
+
+	keyRotations := rootMetadata.GetKeyRotations()
+	require.Len(t, keyRotations, 1)
+	assert.Equal(t, rootKey.KeyID, keyRotations[0].GetOldKey().KeyID)
+	assert.Equal(t, newRootKey.KeyID, keyRotations[0].GetNewKeyID())
+
+	// The old key is trusted for the new key during the grace period, so the
+	// root signed only by the old key is still valid
+	_, err = dsse.VerifyEnvelope(testCtx, state.Metadata.RootEnvelope, []sslibdsse.Verifier{originalSigner}, 1)
+	assert.Nil(t, err)
+	err = state.Verify(testCtx)
+	assert.Nil(t, err)
+
+	// Without a grace period, the old key is no longer trusted
+	err = r.RotateRootKey(testCtx, newSigner, newRootKey.KeyID, rootKey, 0, false)
+	assert.Nil(t, err)
+	err = r.StagePolicy(testCtx, "", true, false)
+	require.Nil(t, err)
+
+	state, err = policy.LoadCurrentState(testCtx, r.r, policy.PolicyStagingRef)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rootMetadata, err = state.GetRootMetadata(false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, set.NewSetFromItems(rootKey.KeyID), getRootPrincipalIDs(t, rootMetadata))
+	assert.Len(t, rootMetadata.GetKeyRotations(), 1)
+
+	err = r.RotateRootKey(testCtx, newSigner, rootKey.KeyID, newRootKey, 0, false)
+	assert.ErrorIs(t, err, ErrUnauthorizedKey)
+}
+
 func TestAddTopLevelTargetsKey(t *testing.T) {
 	r := createTestRepositoryWithRoot(t, "")
 
@@ -303,6 +407,119 @@ func TestRemoveTopLevelTargetsKey(t *testing.T) {
 	assert.Nil(t, err)
 }
 
+func TestRotateTopLevelTargetsKey(t *testing.T) {
+	t.Setenv(dev.DevModeKey, "1")
+
+	r := createTestRepositoryWithRoot(t, "")
+
+	rootSigner := setupSSHKeysForSigning(t, rootKeyBytes, rootPubKeyBytes)
+
+	targetsSigner := setupSSHKeysForSigning(t, targetsKeyBytes, targetsPubKeyBytes)
+	targetsKey := tufv01.NewKeyFromSSLibKey(targetsSigner.MetadataKey())
+
+	gpgKeyR, err := gpg.LoadGPGKeyFromBytes(gpgPubKeyBytes)
+	if err != nil {
+		t.Fatal(err)
+	}
+	gpgKey := tufv01.NewKeyFromSSLibKey(gpgKeyR)
+
+	err = r.RotateTopLevelTargetsKey(testCtx, rootSigner, targetsKey.KeyID, gpgKey, time.Hour, false)
+	assert.ErrorIs(t, err, tuf.ErrPrimaryRuleFileInformationNotFoundInRoot)
+
+	err = r.AddTopLevelTargetsKey(testCtx, rootSigner, targetsKey, false)
+	require.Nil(t, err)
+
+	err = r.RotateTopLevelTargetsKey(testCtx, rootSigner, targetsKey.KeyID, gpgKey, time.Hour, false)
+	assert.Nil(t, err)
+	err = r.StagePolicy(testCtx, "", true, false)
+	require.Nil(t, err)
+
+	state, err := policy.LoadCurrentState(testCtx, r.r, policy.PolicyStagingRef)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rootMetadata, err := state.GetRootMetadata(false)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	targetsPrincipals, err := rootMetadata.GetPrimaryRuleFilePrincipals()
+	require.Nil(t, err)
+	require.Len(t, targetsPrincipals, 1)
+	assert.Equal(t, gpgKey.KeyID, targetsPrincipals[0].ID())
+
+	keyRotations := rootMetadata.GetKeyRotations()
+	require.Len(t, keyRotations, 1)
+	assert.Equal(t, gpgKey.KeyID, keyRotations[0].GetPrincipalID())
+	assert.Equal(t, targetsKey.KeyID, keyRotations[0].GetOldKey().KeyID)
+}
+
+func TestRevokeKey(t *testing.T) {
+	t.Setenv(dev.DevModeKey, "1")
+
+	r := createTestRepositoryWithPolicy(t, "")
+	refName := "refs/heads/main"
+
+	rootSigner := setupSSHKeysForSigning(t, rootKeyBytes, rootPubKeyBytes)
+
+	gpgKeyR, err := gpg.LoadGPGKeyFromBytes(gpgPubKeyBytes)
+	if err != nil {
+		t.Fatal(err)
+	}
+	gpgKey := tufv01.NewKeyFromSSLibKey(gpgKeyR)
+
+	addEntry := func(t *testing.T) gitinterface.Hash {
+		t.Helper()
+
+		commitIDs := common.AddNTestCommitsToSpecifiedRef(t, r.r, refName, 1, gpgKeyBytes)
+		return common.CreateTestRSLReferenceEntryCommit(t, r.r, rsl.NewReferenceEntry(refName, commitIDs[0]), gpgKeyBytes)
+	}
+
+	beforeEntryID := addEntry(t)
+	afterEntryID := addEntry(t)
+
+	err = r.VerifyRef(testCtx, refName)
+	assert.Nil(t, err)
+
+	err = r.RevokeKey(testCtx, rootSigner, gpgKey, "", time.Time{}, false)
+	assert.ErrorIs(t, err, tuf.ErrInvalidKeyRevocation)
+
+	err = r.RevokeKey(testCtx, rootSigner, gpgKey, gitinterface.ZeroHash.String(), time.Time{}, false)
+	assert.NotNil(t, err)
+
+	err = r.RevokeKey(testCtx, rootSigner, gpgKey, beforeEntryID.String(), time.Time{}, false, trustpolicyopts.WithRSLEntry())
+	assert.Nil(t, err)
+
+	state, err := policy.LoadCurrentState(testCtx, r.r, policy.PolicyStagingRef)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rootMetadata, err := state.GetRootMetadata(false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyRevocations := rootMetadata.GetKeyRevocations()
+	require.Len(t, keyRevocations, 1)
+	assert.Equal(t, gpgKey.KeyID, keyRevocations[0].GetKey().KeyID)
+	assert.Equal(t, beforeEntryID.String(), keyRevocations[0].GetRevokedAfterEntryID())
+
+	affectedEntries, err := r.GetEntriesAffectedByKeyRevocations(testCtx)
+	require.Nil(t, err)
+	require.Len(t, affectedEntries, 1)
+	assert.Equal(t, afterEntryID.String(), affectedEntries[0].EntryID)
+	assert.True(t, affectedEntries[0].SignedEntry)
+	assert.True(t, affectedEntries[0].SignedTarget)
+
+	err = policy.Apply(testCtx, r.r, false)
+	require.Nil(t, err)
+
+	// The entry recorded after the revocation point is verified using the
+	// policy from before the key was revoked, but the revocation is enforced
+	err = r.VerifyRef(testCtx, refName)
+	assert.ErrorIs(t, err, policy.ErrVerificationFailed)
+}
+
 func TestAddGitHubApp(t *testing.T) {
 	r := createTestRepositoryWithRoot(t, "")
 
@@ -539,6 +756,54 @@ func TestUpdateRootThreshold(t *testing.T) {
 	assert.Equal(t, 2, rootThreshold)
 }
 
+func TestUpdateRootExpiry(t *testing.T) {
+	t.Setenv(dev.DevModeKey, "1")
+
+	r := createTestRepositoryWithRoot(t, "")
+
+	signer := setupSSHKeysForSigning(t, rootKeyBytes, rootPubKeyBytes)
+
+	t.Run("expiry in the past", func(t *testing.T) {
+		err := r.UpdateRootExpiry(testCtx, signer, time.Now().AddDate(0, 0, -1), false)
+		assert.ErrorIs(t, err, ErrExpiryInPast)
+	})
+
+	t.Run("unauthorized key", func(t *testing.T) {
+		unauthorizedSigner := setupSSHKeysForSigning(t, targetsKeyBytes, targetsPubKeyBytes)
+		err := r.UpdateRootExpiry(testCtx, unauthorizedSigner, time.Now().AddDate(2, 0, 0), false)
+		assert.ErrorIs(t, err, ErrUnauthorizedKey)
+	})
+
+	t.Run("successful update", func(t *testing.T) {
+		expires := time.Now().AddDate(2, 0, 0).UTC().Truncate(time.Second)
+
+		state, err := policy.LoadCurrentState(testCtx, r.r, policy.PolicyStagingRef)
+		if err != nil {
+			t.Fatal(err)
+		}
+		rootVersion := state.GetMetadataVersions()[policy.RootRoleName]
+
+		err = r.UpdateRootExpiry(testCtx, signer, expires, false)
+		assert.Nil(t, err)
+
+		err = r.StagePolicy(testCtx, "", true, false)
+		require.Nil(t, err)
+
+		state, err = policy.LoadCurrentState(testCtx, r.r, policy.PolicyStagingRef)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		rootMetadata, err := state.GetRootMetadata(false)
+		if err != nil {
+			t.Fatal(err)
+		}
+		assert.Equal(t, expires.Format(time.RFC3339), rootMetadata.GetExpires())
+		assert.True(t, expires.Equal(state.GetExpiries()[policy.RootRoleName]))
+		assert.Equal(t, rootVersion+1, state.GetMetadataVersions()[policy.RootRoleName])
+	})
+}
+
 func TestUpdateTopLevelTargetsThreshold(t *testing.T) {
 	r := createTestRepositoryWithRoot(t, "")
 
@@ -716,6 +981,73 @@ func TestAddGlobalRuleBlockForcePushes(t *testing.T) {
 	assert.Equal(t, "block-force-pushes-for-main", globalRules[0].GetName())
 	assert.Equal(t, []string{"git:refs/heads/main"}, globalRules[0].(tuf.GlobalRuleBlockForcePushes).GetProtectedNamespaces())
 }
+
+func TestAddGlobalRuleMergeStrategy(t *testing.T) {
+	t.Setenv(dev.DevModeKey, "1")
+
+	r := createTestRepositoryWithRoot(t, "")
+
+	rootSigner := setupSSHKeysForSigning(t, rootKeyBytes, rootPubKeyBytes)
+
+	err := r.AddGlobalRuleMergeStrategy(testCtx, rootSigner, "linear-history-for-main", []string{"git:refs/heads/main"}, "squash", false)
+	assert.ErrorIs(t, err, tuf.ErrInvalidMergeStrategy)
+
+	err = r.AddGlobalRuleMergeStrategy(testCtx, rootSigner, "linear-history-for-main", []string{"git:refs/heads/main"}, tuf.MergeStrategyLinearHistory, false)
+	assert.Nil(t, err)
+
+	err = r.StagePolicy(testCtx, "", true, false)
+	require.Nil(t, err)
+
+	state, err := policy.LoadCurrentState(testCtx, r.r, policy.PolicyStagingRef) // we haven't applied
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rootMetadata, err := state.GetRootMetadata(false)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	globalRules := rootMetadata.GetGlobalRules()
+	assert.Len(t, globalRules, 1)
+	assert.Equal(t, "linear-history-for-main", globalRules[0].GetName())
+	assert.Equal(t, []string{"git:refs/heads/main"}, globalRules[0].(tuf.GlobalRuleMergeStrategy).GetProtectedNamespaces())
+	assert.Equal(t, tuf.MergeStrategyLinearHistory, globalRules[0].(tuf.GlobalRuleMergeStrategy).GetMergeStrategy())
+}
+
+func TestAddGlobalRuleRequireHookExecution(t *testing.T) {
+	t.Setenv(dev.DevModeKey, "1")
+
+	r := createTestRepositoryWithRoot(t, "")
+
+	rootSigner := setupSSHKeysForSigning(t, rootKeyBytes, rootPubKeyBytes)
+
+	err := r.AddGlobalRuleRequireHookExecution(testCtx, rootSigner, "require-lint-for-main", []string{"git:refs/heads/main"}, nil, false)
+	assert.ErrorIs(t, err, tuf.ErrNoHooksDefined)
+
+	err = r.AddGlobalRuleRequireHookExecution(testCtx, rootSigner, "require-lint-for-main", []string{"git:refs/heads/main"}, []string{"lint"}, false)
+	assert.Nil(t, err)
+
+	err = r.StagePolicy(testCtx, "", true, false)
+	require.Nil(t, err)
+
+	state, err := policy.LoadCurrentState(testCtx, r.r, policy.PolicyStagingRef) // we haven't applied
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rootMetadata, err := state.GetRootMetadata(false)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	globalRules := rootMetadata.GetGlobalRules()
+	assert.Len(t, globalRules, 1)
+	assert.Equal(t, "require-lint-for-main", globalRules[0].GetName())
+	assert.Equal(t, []string{"git:refs/heads/main"}, globalRules[0].(tuf.GlobalRuleRequireHookExecution).GetProtectedNamespaces())
+	assert.Equal(t, []string{"lint"}, globalRules[0].(tuf.GlobalRuleRequireHookExecution).GetHookNames())
+}
+
 func TestRemoveGlobalRule(t *testing.T) {
 	t.Setenv(dev.DevModeKey, "1")
 
@@ -1167,7 +1499,7 @@ func TestUpdateGlobalRule(t *testing.T) {
 		globalRules = rootMetadata.GetGlobalRules()
 		assert.Len(t, globalRules, 1)
 		assert.Equal(t, "require-approval-for-main", globalRules[0].GetName())
-		assert.Equal(t, []string{"git:refs/heads/main"}, globalRules[0].(tuf.GlobalRuleBlockForcePushes).GetProtectedNamespaces())
+		assert.Equal(t, []string{"git:refs/heads/main"}, globalRules[0].(tuf.GlobalRuleThreshold).GetProtectedNamespaces())
 	})
 }
 
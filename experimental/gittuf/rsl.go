@@ -219,6 +219,59 @@ func (r *Repository) RecordRSLAnnotation(ctx context.Context, rslEntryIDs []stri
 	return err
 }
 
+// RecordRSLCheckpoint is the interface for the user to add an RSL checkpoint
+// entry. Every reference recorded in the RSL is verified, and the checkpoint
+// records the tip of each reference that passes verification along with the
+// policy in effect. Verifiers only trust the checkpoint once it is approved by
+// a threshold of root principals: the signature on the checkpoint counts as one
+// approval, and other root principals approve it by annotating it. The results
+// of verifying each reference are returned.
+func (r *Repository) RecordRSLCheckpoint(ctx context.Context, signCommit bool, opts ...rslopts.CheckpointOption) ([]*policy.RefVerificationResult, error) {
+	if signCommit {
+		slog.Debug("Checking if Git signing is configured...")
+		err := r.r.CanSign()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	options := &rslopts.CheckpointOptions{}
+	for _, fn := range opts {
+		fn(options)
+	}
+
+	if options.RemoteName == "" && !options.LocalOnly {
+		return nil, ErrRemoteNotSpecified
+	} else if options.RemoteName != "" && options.LocalOnly {
+		return nil, ErrCannotUseRemoteAndLocalOnly
+	}
+
+	if !options.LocalOnly {
+		_, err := r.Sync(ctx, options.RemoteName, false, signCommit)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	slog.Debug("Verifying all references to create checkpoint...")
+	entry, results, err := policy.NewPolicyVerifier(r.r).NewCheckpointEntry(ctx)
+	if err != nil {
+		return results, err
+	}
+
+	slog.Debug("Creating RSL checkpoint entry...")
+	if err := entry.Commit(r.r, signCommit); err != nil {
+		return results, err
+	}
+
+	if options.LocalOnly {
+		return results, nil
+	}
+
+	_, err = r.Sync(ctx, options.RemoteName, false, signCommit)
+	return results, err
+}
+
 // ReconcileLocalRSLWithRemote checks the local RSL against the specified remote
 // and reconciles the local RSL if needed. If the local RSL doesn't exist or is
 // strictly behind the remote RSL, then the local RSL is updated to match the
@@ -386,6 +439,12 @@ func (r *Repository) ReconcileLocalRSLWithRemote(ctx context.Context, remoteName
 			if err := rsl.NewAnnotationEntry(entry.RSLEntryIDs, entry.Skip, entry.Message).Commit(r.r, sign); err != nil {
 				return fmt.Errorf("unable to reapply annotation entry '%s': %w", entry.ID.String(), err)
 			}
+		case *rsl.CheckpointEntry:
+			// A checkpoint records the state of the repository when it
+			// was created, which no longer holds on top of the remote's
+			// entries, so it must be created afresh
+			slog.Warn(fmt.Sprintf("Dropping local checkpoint entry '%s' as it does not reflect the remote RSL, create a new checkpoint if needed", entry.ID.String()))
+			continue
 		}
 
 		if slog.Default().Enabled(ctx, slog.LevelDebug) {
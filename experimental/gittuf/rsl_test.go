@@ -10,6 +10,7 @@ import (
 	"testing"
 
 	rslopts "github.com/gittuf/gittuf/experimental/gittuf/options/rsl"
+	"github.com/gittuf/gittuf/internal/common"
 	"github.com/gittuf/gittuf/internal/common/set"
 	"github.com/gittuf/gittuf/internal/dev"
 	"github.com/gittuf/gittuf/internal/gitinterface"
@@ -269,6 +270,36 @@ func TestRecordRSLAnnotation(t *testing.T) {
 	assert.True(t, annotation.Skip)
 }
 
+func TestRecordRSLCheckpoint(t *testing.T) {
+	repo := createTestRepositoryWithPolicy(t, "")
+
+	_, err := repo.RecordRSLCheckpoint(testCtx, false)
+	assert.ErrorIs(t, err, ErrRemoteNotSpecified)
+
+	_, err = repo.RecordRSLCheckpoint(testCtx, false, rslopts.WithCheckpointLocalOnly())
+	assert.ErrorIs(t, err, policy.ErrNoVerifiedRefs)
+
+	mainRef := "refs/heads/main"
+	commitIDs := common.AddNTestCommitsToSpecifiedRef(t, repo.r, mainRef, 1, gpgKeyBytes)
+	common.CreateTestRSLReferenceEntryCommit(t, repo.r, rsl.NewReferenceEntry(mainRef, commitIDs[0]), gpgKeyBytes)
+
+	policyEntry, _, err := rsl.GetLatestReferenceUpdaterEntry(repo.r, rsl.ForReference(policy.PolicyRef))
+	require.Nil(t, err)
+
+	results, err := repo.RecordRSLCheckpoint(testCtx, false, rslopts.WithCheckpointLocalOnly())
+	assert.Nil(t, err)
+	require.Len(t, results, 1)
+	assert.Nil(t, results[0].Err)
+
+	latestEntry, err := rsl.GetLatestEntry(repo.r)
+	require.Nil(t, err)
+	require.IsType(t, &rsl.CheckpointEntry{}, latestEntry)
+
+	checkpoint := latestEntry.(*rsl.CheckpointEntry)
+	assert.Equal(t, policyEntry.GetID(), checkpoint.PolicyEntryID)
+	assert.Equal(t, map[string]gitinterface.Hash{mainRef: commitIDs[0]}, checkpoint.RefTips)
+}
+
 func TestReconcileLocalRSLWithRemote(t *testing.T) {
 	remoteName := "origin"
 	refName := "refs/heads/main"
@@ -9,6 +9,7 @@ import (
 	"fmt"
 	"log/slog"
 	"strings"
+	"time"
 
 	trustpolicyopts "github.com/gittuf/gittuf/experimental/gittuf/options/trustpolicy"
 	"github.com/gittuf/gittuf/internal/policy"
@@ -16,6 +17,7 @@ import (
 	"github.com/gittuf/gittuf/internal/signerverifier/dsse"
 	sslibdsse "github.com/gittuf/gittuf/internal/third_party/go-securesystemslib/dsse"
 	"github.com/gittuf/gittuf/internal/tuf"
+	tufv04 "github.com/gittuf/gittuf/internal/tuf/v04"
 )
 
 var ErrInvalidPolicyName = errors.New("invalid rule or policy file name, cannot be 'root'")
@@ -144,6 +146,8 @@ func (r *Repository) AddDelegation(ctx context.Context, signer sslibdsse.SignerV
 		return err
 	}
 
+	targetsMetadata.IncrementMetadataVersion()
+
 	env, err := dsse.CreateEnvelope(targetsMetadata)
 	if err != nil {
 		return err
@@ -218,6 +222,8 @@ func (r *Repository) UpdateDelegation(ctx context.Context, signer sslibdsse.Sign
 		return err
 	}
 
+	targetsMetadata.IncrementMetadataVersion()
+
 	env, err := dsse.CreateEnvelope(targetsMetadata)
 	if err != nil {
 		return err
@@ -283,6 +289,8 @@ func (r *Repository) ReorderDelegations(ctx context.Context, signer sslibdsse.Si
 		return err
 	}
 
+	targetsMetadata.IncrementMetadataVersion()
+
 	env, err := dsse.CreateEnvelope(targetsMetadata)
 	if err != nil {
 		return err
@@ -353,6 +361,8 @@ func (r *Repository) RemoveDelegation(ctx context.Context, signer sslibdsse.Sign
 		return err
 	}
 
+	targetsMetadata.IncrementMetadataVersion()
+
 	env, err := dsse.CreateEnvelope(targetsMetadata)
 	if err != nil {
 		return err
@@ -429,6 +439,8 @@ func (r *Repository) AddPrincipalToTargets(ctx context.Context, signer sslibdsse
 		}
 	}
 
+	targetsMetadata.IncrementMetadataVersion()
+
 	env, err := dsse.CreateEnvelope(targetsMetadata)
 	if err != nil {
 		return err
@@ -452,6 +464,367 @@ func (r *Repository) AddPrincipalToTargets(ctx context.Context, signer sslibdsse
 	return state.Commit(r.r, commitMessage, options.CreateRSLEntry, signCommit)
 }
 
+// AddTeamToTargets is the interface for a user to add a team of principals to
+// gittuf rule file metadata. The members of the team must already be trusted
+// principals in the rule file.
+func (r *Repository) AddTeamToTargets(ctx context.Context, signer sslibdsse.SignerVerifier, targetsRoleName string, teamID string, memberPrincipalIDs []string, threshold int, signCommit bool, opts ...trustpolicyopts.Option) error {
+	if signCommit {
+		slog.Debug("Checking if Git signing is configured...")
+		err := r.r.CanSign()
+		if err != nil {
+			return err
+		}
+	}
+
+	options := &trustpolicyopts.Options{}
+	for _, fn := range opts {
+		fn(options)
+	}
+
+	keyID, err := signer.KeyID()
+	if err != nil {
+		return err
+	}
+
+	slog.Debug("Loading current policy...")
+	state, err := policy.LoadCurrentState(ctx, r.r, policy.PolicyStagingRef, policyopts.BypassRSL())
+	if err != nil {
+		return err
+	}
+	if !state.HasTargetsRole(targetsRoleName) {
+		return policy.ErrMetadataNotFound
+	}
+
+	slog.Debug("Loading current rule file...")
+	targetsMetadata, err := state.GetTargetsMetadata(targetsRoleName, true)
+	if err != nil {
+		return err
+	}
+
+	principals := targetsMetadata.GetPrincipals()
+	members := make([]tuf.Principal, 0, len(memberPrincipalIDs))
+	for _, memberPrincipalID := range memberPrincipalIDs {
+		member, has := principals[memberPrincipalID]
+		if !has {
+			return fmt.Errorf("%w: '%s'", tuf.ErrPrincipalNotFound, memberPrincipalID)
+		}
+		members = append(members, member)
+	}
+
+	team, err := tufv04.NewTeam(teamID, members, threshold)
+	if err != nil {
+		return err
+	}
+
+	slog.Debug(fmt.Sprintf("Adding team '%s' to rule file...", teamID))
+	if err := targetsMetadata.AddPrincipal(team); err != nil {
+		return err
+	}
+
+	targetsMetadata.IncrementMetadataVersion()
+
+	env, err := dsse.CreateEnvelope(targetsMetadata)
+	if err != nil {
+		return err
+	}
+
+	slog.Debug(fmt.Sprintf("Signing updated rule file using '%s'...", keyID))
+	env, err = dsse.SignEnvelope(ctx, env, signer)
+	if err != nil {
+		return err
+	}
+
+	if targetsRoleName == policy.TargetsRoleName {
+		state.Metadata.TargetsEnvelope = env
+	} else {
+		state.Metadata.DelegationEnvelopes[targetsRoleName] = env
+	}
+
+	commitMessage := fmt.Sprintf("Add team '%s' to policy '%s'", teamID, targetsRoleName)
+
+	slog.Debug("Committing policy...")
+	return state.Commit(r.r, commitMessage, options.CreateRSLEntry, signCommit)
+}
+
+// UpdateTeamThresholdForRule is the interface for a user to set the number of
+// members of a team that must approve for the team to count towards the
+// threshold of a rule.
+func (r *Repository) UpdateTeamThresholdForRule(ctx context.Context, signer sslibdsse.SignerVerifier, targetsRoleName, ruleName, teamID string, threshold int, signCommit bool, opts ...trustpolicyopts.Option) error {
+	if signCommit {
+		slog.Debug("Checking if Git signing is configured...")
+		err := r.r.CanSign()
+		if err != nil {
+			return err
+		}
+	}
+
+	options := &trustpolicyopts.Options{}
+	for _, fn := range opts {
+		fn(options)
+	}
+
+	keyID, err := signer.KeyID()
+	if err != nil {
+		return err
+	}
+
+	slog.Debug("Loading current policy...")
+	state, err := policy.LoadCurrentState(ctx, r.r, policy.PolicyStagingRef, policyopts.BypassRSL())
+	if err != nil {
+		return err
+	}
+	if !state.HasTargetsRole(targetsRoleName) {
+		return policy.ErrMetadataNotFound
+	}
+
+	slog.Debug("Loading current rule file...")
+	targetsMetadata, err := state.GetTargetsMetadata(targetsRoleName, true)
+	if err != nil {
+		return err
+	}
+
+	slog.Debug(fmt.Sprintf("Updating threshold for team '%s' in rule '%s'...", teamID, ruleName))
+	if err := targetsMetadata.UpdateRuleTeamThreshold(ruleName, teamID, threshold); err != nil {
+		return err
+	}
+
+	targetsMetadata.IncrementMetadataVersion()
+
+	env, err := dsse.CreateEnvelope(targetsMetadata)
+	if err != nil {
+		return err
+	}
+
+	slog.Debug(fmt.Sprintf("Signing updated rule file using '%s'...", keyID))
+	env, err = dsse.SignEnvelope(ctx, env, signer)
+	if err != nil {
+		return err
+	}
+
+	if targetsRoleName == policy.TargetsRoleName {
+		state.Metadata.TargetsEnvelope = env
+	} else {
+		state.Metadata.DelegationEnvelopes[targetsRoleName] = env
+	}
+
+	commitMessage := fmt.Sprintf("Update threshold for team '%s' in rule '%s' in policy '%s'", teamID, ruleName, targetsRoleName)
+
+	slog.Debug("Committing policy...")
+	return state.Commit(r.r, commitMessage, options.CreateRSLEntry, signCommit)
+}
+
+// UpdateRuleActions is the interface for a user to set the reference actions a
+// rule applies to. If no actions are specified, the rule applies to all
+// actions.
+func (r *Repository) UpdateRuleActions(ctx context.Context, signer sslibdsse.SignerVerifier, targetsRoleName, ruleName string, actions []tuf.RefAction, signCommit bool, opts ...trustpolicyopts.Option) error {
+	if signCommit {
+		slog.Debug("Checking if Git signing is configured...")
+		err := r.r.CanSign()
+		if err != nil {
+			return err
+		}
+	}
+
+	options := &trustpolicyopts.Options{}
+	for _, fn := range opts {
+		fn(options)
+	}
+
+	keyID, err := signer.KeyID()
+	if err != nil {
+		return err
+	}
+
+	slog.Debug("Loading current policy...")
+	state, err := policy.LoadCurrentState(ctx, r.r, policy.PolicyStagingRef, policyopts.BypassRSL())
+	if err != nil {
+		return err
+	}
+	if !state.HasTargetsRole(targetsRoleName) {
+		return policy.ErrMetadataNotFound
+	}
+
+	slog.Debug("Loading current rule file...")
+	targetsMetadata, err := state.GetTargetsMetadata(targetsRoleName, true)
+	if err != nil {
+		return err
+	}
+
+	slog.Debug(fmt.Sprintf("Updating actions for rule '%s'...", ruleName))
+	if err := targetsMetadata.UpdateRuleActions(ruleName, actions); err != nil {
+		return err
+	}
+
+	targetsMetadata.IncrementMetadataVersion()
+
+	env, err := dsse.CreateEnvelope(targetsMetadata)
+	if err != nil {
+		return err
+	}
+
+	slog.Debug(fmt.Sprintf("Signing updated rule file using '%s'...", keyID))
+	env, err = dsse.SignEnvelope(ctx, env, signer)
+	if err != nil {
+		return err
+	}
+
+	if targetsRoleName == policy.TargetsRoleName {
+		state.Metadata.TargetsEnvelope = env
+	} else {
+		state.Metadata.DelegationEnvelopes[targetsRoleName] = env
+	}
+
+	commitMessage := fmt.Sprintf("Update actions for rule '%s' in policy '%s'", ruleName, targetsRoleName)
+
+	slog.Debug("Committing policy...")
+	return state.Commit(r.r, commitMessage, options.CreateRSLEntry, signCommit)
+}
+
+// UpdateRuleValidityWindow is the interface for a user to set the period during
+// which a rule is trusted. A zero notBefore or notAfter leaves that end of the
+// period open. If neither is set, the rule is trusted at all times.
+func (r *Repository) UpdateRuleValidityWindow(ctx context.Context, signer sslibdsse.SignerVerifier, targetsRoleName, ruleName string, notBefore, notAfter time.Time, signCommit bool, opts ...trustpolicyopts.Option) error {
+	if signCommit {
+		slog.Debug("Checking if Git signing is configured...")
+		err := r.r.CanSign()
+		if err != nil {
+			return err
+		}
+	}
+
+	options := &trustpolicyopts.Options{}
+	for _, fn := range opts {
+		fn(options)
+	}
+
+	window, err := tuf.NewValidityWindow(notBefore, notAfter)
+	if err != nil {
+		return err
+	}
+
+	keyID, err := signer.KeyID()
+	if err != nil {
+		return err
+	}
+
+	slog.Debug("Loading current policy...")
+	state, err := policy.LoadCurrentState(ctx, r.r, policy.PolicyStagingRef, policyopts.BypassRSL())
+	if err != nil {
+		return err
+	}
+	if !state.HasTargetsRole(targetsRoleName) {
+		return policy.ErrMetadataNotFound
+	}
+
+	slog.Debug("Loading current rule file...")
+	targetsMetadata, err := state.GetTargetsMetadata(targetsRoleName, true)
+	if err != nil {
+		return err
+	}
+
+	slog.Debug(fmt.Sprintf("Updating validity window for rule '%s'...", ruleName))
+	if err := targetsMetadata.UpdateRuleValidityWindow(ruleName, window); err != nil {
+		return err
+	}
+
+	targetsMetadata.IncrementMetadataVersion()
+
+	env, err := dsse.CreateEnvelope(targetsMetadata)
+	if err != nil {
+		return err
+	}
+
+	slog.Debug(fmt.Sprintf("Signing updated rule file using '%s'...", keyID))
+	env, err = dsse.SignEnvelope(ctx, env, signer)
+	if err != nil {
+		return err
+	}
+
+	if targetsRoleName == policy.TargetsRoleName {
+		state.Metadata.TargetsEnvelope = env
+	} else {
+		state.Metadata.DelegationEnvelopes[targetsRoleName] = env
+	}
+
+	commitMessage := fmt.Sprintf("Update validity window for rule '%s' in policy '%s'", ruleName, targetsRoleName)
+
+	slog.Debug("Committing policy...")
+	return state.Commit(r.r, commitMessage, options.CreateRSLEntry, signCommit)
+}
+
+// UpdatePrincipalValidityWindow is the interface for a user to set the period
+// during which a principal is trusted by the rules in a rule file. A zero
+// notBefore or notAfter leaves that end of the period open. If neither is set,
+// the principal is trusted at all times.
+func (r *Repository) UpdatePrincipalValidityWindow(ctx context.Context, signer sslibdsse.SignerVerifier, targetsRoleName, principalID string, notBefore, notAfter time.Time, signCommit bool, opts ...trustpolicyopts.Option) error {
+	if signCommit {
+		slog.Debug("Checking if Git signing is configured...")
+		err := r.r.CanSign()
+		if err != nil {
+			return err
+		}
+	}
+
+	options := &trustpolicyopts.Options{}
+	for _, fn := range opts {
+		fn(options)
+	}
+
+	window, err := tuf.NewValidityWindow(notBefore, notAfter)
+	if err != nil {
+		return err
+	}
+
+	keyID, err := signer.KeyID()
+	if err != nil {
+		return err
+	}
+
+	slog.Debug("Loading current policy...")
+	state, err := policy.LoadCurrentState(ctx, r.r, policy.PolicyStagingRef, policyopts.BypassRSL())
+	if err != nil {
+		return err
+	}
+	if !state.HasTargetsRole(targetsRoleName) {
+		return policy.ErrMetadataNotFound
+	}
+
+	slog.Debug("Loading current rule file...")
+	targetsMetadata, err := state.GetTargetsMetadata(targetsRoleName, true)
+	if err != nil {
+		return err
+	}
+
+	slog.Debug(fmt.Sprintf("Updating validity window for principal '%s'...", principalID))
+	if err := targetsMetadata.UpdatePrincipalValidityWindow(principalID, window); err != nil {
+		return err
+	}
+
+	targetsMetadata.IncrementMetadataVersion()
+
+	env, err := dsse.CreateEnvelope(targetsMetadata)
+	if err != nil {
+		return err
+	}
+
+	slog.Debug(fmt.Sprintf("Signing updated rule file using '%s'...", keyID))
+	env, err = dsse.SignEnvelope(ctx, env, signer)
+	if err != nil {
+		return err
+	}
+
+	if targetsRoleName == policy.TargetsRoleName {
+		state.Metadata.TargetsEnvelope = env
+	} else {
+		state.Metadata.DelegationEnvelopes[targetsRoleName] = env
+	}
+
+	commitMessage := fmt.Sprintf("Update validity window for principal '%s' in policy '%s'", principalID, targetsRoleName)
+
+	slog.Debug("Committing policy...")
+	return state.Commit(r.r, commitMessage, options.CreateRSLEntry, signCommit)
+}
+
 // RemovePrincipalFromTargets is the interface for a user to remove a principal
 // from gittuf rule file metadata.
 func (r *Repository) RemovePrincipalFromTargets(ctx context.Context, signer sslibdsse.SignerVerifier, targetsRoleName string, principalID string, signCommit bool, opts ...trustpolicyopts.Option) error {
@@ -493,6 +866,8 @@ func (r *Repository) RemovePrincipalFromTargets(ctx context.Context, signer ssli
 		return err
 	}
 
+	targetsMetadata.IncrementMetadataVersion()
+
 	env, err := dsse.CreateEnvelope(targetsMetadata)
 	if err != nil {
 		return err
@@ -516,6 +891,145 @@ func (r *Repository) RemovePrincipalFromTargets(ctx context.Context, signer ssli
 	return state.Commit(r.r, commitMessage, options.CreateRSLEntry, signCommit)
 }
 
+// RotatePersonKey replaces a key of the person identified by personID in the
+// specified rule file with a new key. If gracePeriod is set, the old key
+// continues to be trusted for the person until the grace period ends, so that
+// changes already signed using it can still be verified.
+func (r *Repository) RotatePersonKey(ctx context.Context, signer sslibdsse.SignerVerifier, targetsRoleName, personID, oldKeyID string, newKey tuf.Principal, gracePeriod time.Duration, signCommit bool, opts ...trustpolicyopts.Option) error {
+	if signCommit {
+		slog.Debug("Checking if Git signing is configured...")
+		err := r.r.CanSign()
+		if err != nil {
+			return err
+		}
+	}
+
+	options := &trustpolicyopts.Options{}
+	for _, fn := range opts {
+		fn(options)
+	}
+
+	keyID, err := signer.KeyID()
+	if err != nil {
+		return err
+	}
+
+	slog.Debug("Loading current policy...")
+	state, err := policy.LoadCurrentState(ctx, r.r, policy.PolicyStagingRef, policyopts.BypassRSL())
+	if err != nil {
+		return err
+	}
+	if !state.HasTargetsRole(targetsRoleName) {
+		return policy.ErrMetadataNotFound
+	}
+
+	slog.Debug("Loading current rule file...")
+	targetsMetadata, err := state.GetTargetsMetadata(targetsRoleName, true)
+	if err != nil {
+		return err
+	}
+
+	slog.Debug(fmt.Sprintf("Rotating key '%s' of person '%s'...", oldKeyID, personID))
+	if err := targetsMetadata.RotatePersonKey(personID, oldKeyID, newKey, getGracePeriodEnd(gracePeriod)); err != nil {
+		return err
+	}
+
+	targetsMetadata.IncrementMetadataVersion()
+
+	env, err := dsse.CreateEnvelope(targetsMetadata)
+	if err != nil {
+		return err
+	}
+
+	slog.Debug(fmt.Sprintf("Signing updated rule file using '%s'...", keyID))
+	env, err = dsse.SignEnvelope(ctx, env, signer)
+	if err != nil {
+		return err
+	}
+
+	if targetsRoleName == policy.TargetsRoleName {
+		state.Metadata.TargetsEnvelope = env
+	} else {
+		state.Metadata.DelegationEnvelopes[targetsRoleName] = env
+	}
+
+	commitMessage := fmt.Sprintf("Rotate key of person '%s' in policy '%s'\n\n'%s' replaced by '%s'", personID, targetsRoleName, oldKeyID, newKey.ID())
+	if gracePeriod > 0 {
+		commitMessage += fmt.Sprintf(", the old key is trusted for a grace period of %s", gracePeriod)
+	}
+
+	slog.Debug("Committing policy...")
+	return state.Commit(r.r, commitMessage, options.CreateRSLEntry, signCommit)
+}
+
+// UpdateTargetsExpiry sets the expiry of the specified rule file. Entries
+// recorded in the RSL after the rule file expires fail verification.
+func (r *Repository) UpdateTargetsExpiry(ctx context.Context, signer sslibdsse.SignerVerifier, targetsRoleName string, expires time.Time, signCommit bool, opts ...trustpolicyopts.Option) error {
+	if !expires.After(time.Now()) {
+		return ErrExpiryInPast
+	}
+
+	if signCommit {
+		slog.Debug("Checking if Git signing is configured...")
+		err := r.r.CanSign()
+		if err != nil {
+			return err
+		}
+	}
+
+	options := &trustpolicyopts.Options{}
+	for _, fn := range opts {
+		fn(options)
+	}
+
+	keyID, err := signer.KeyID()
+	if err != nil {
+		return err
+	}
+
+	slog.Debug("Loading current policy...")
+	state, err := policy.LoadCurrentState(ctx, r.r, policy.PolicyStagingRef, policyopts.BypassRSL())
+	if err != nil {
+		return err
+	}
+	if !state.HasTargetsRole(targetsRoleName) {
+		return policy.ErrMetadataNotFound
+	}
+
+	slog.Debug("Loading current rule file...")
+	targetsMetadata, err := state.GetTargetsMetadata(targetsRoleName, true)
+	if err != nil {
+		return err
+	}
+
+	slog.Debug("Updating rule file expiry...")
+	targetsMetadata.SetExpires(expires.UTC().Format(time.RFC3339))
+
+	targetsMetadata.IncrementMetadataVersion()
+
+	env, err := dsse.CreateEnvelope(targetsMetadata)
+	if err != nil {
+		return err
+	}
+
+	slog.Debug(fmt.Sprintf("Signing updated rule file using '%s'...", keyID))
+	env, err = dsse.SignEnvelope(ctx, env, signer)
+	if err != nil {
+		return err
+	}
+
+	if targetsRoleName == policy.TargetsRoleName {
+		state.Metadata.TargetsEnvelope = env
+	} else {
+		state.Metadata.DelegationEnvelopes[targetsRoleName] = env
+	}
+
+	commitMessage := fmt.Sprintf("Update expiry of policy '%s' to %s", targetsRoleName, expires.UTC().Format(time.RFC3339))
+
+	slog.Debug("Committing policy...")
+	return state.Commit(r.r, commitMessage, options.CreateRSLEntry, signCommit)
+}
+
 // SignTargets adds a signature to specified Targets role's envelope. Note that
 // the metadata itself is not modified, so its version remains the same.
 func (r *Repository) SignTargets(ctx context.Context, signer sslibdsse.SignerVerifier, targetsRoleName string, signCommit bool, opts ...trustpolicyopts.Option) error {
@@ -5,13 +5,16 @@ package gittuf
 
 import (
 	"testing"
+	"time"
 
 	"github.com/gittuf/gittuf/internal/common/set"
+	"github.com/gittuf/gittuf/internal/dev"
 	"github.com/gittuf/gittuf/internal/policy"
 	"github.com/gittuf/gittuf/internal/signerverifier/gpg"
 	"github.com/gittuf/gittuf/internal/tuf"
 	tufv01 "github.com/gittuf/gittuf/internal/tuf/v01"
 	tufv02 "github.com/gittuf/gittuf/internal/tuf/v02"
+	tufv04 "github.com/gittuf/gittuf/internal/tuf/v04"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -307,6 +310,282 @@ func TestAddPrincipalToTargets(t *testing.T) {
 	assert.Equal(t, 2, len(targetsMetadata.GetPrincipals()))
 }
 
+func TestAddTeamToTargets(t *testing.T) {
+	t.Setenv(dev.DevModeKey, "1")
+
+	r := createTestRepositoryWithPolicy(t, "")
+
+	targetsSigner := setupSSHKeysForSigning(t, targetsKeyBytes, targetsPubKeyBytes)
+	targetsPubKey := tufv01.NewKeyFromSSLibKey(targetsSigner.MetadataKey())
+
+	gpgKeyR, err := gpg.LoadGPGKeyFromBytes(gpgPubKeyBytes)
+	if err != nil {
+		t.Fatal(err)
+	}
+	gpgKey := tufv01.NewKeyFromSSLibKey(gpgKeyR)
+
+	if err := r.AddPrincipalToTargets(testCtx, targetsSigner, policy.TargetsRoleName, []tuf.Principal{targetsPubKey}, false); err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("add team", func(t *testing.T) {
+		err := r.AddTeamToTargets(testCtx, targetsSigner, policy.TargetsRoleName, "dev", []string{gpgKey.KeyID, targetsPubKey.KeyID}, 2, false)
+		assert.Nil(t, err)
+
+		err = r.StagePolicy(testCtx, "", true, false)
+		require.Nil(t, err)
+
+		state, err := policy.LoadCurrentState(testCtx, r.r, policy.PolicyStagingRef)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		targetsMetadata, err := state.GetTargetsMetadata(policy.TargetsRoleName, false)
+		require.Nil(t, err)
+
+		principals := targetsMetadata.GetPrincipals()
+		require.Contains(t, principals, "dev")
+
+		team, isTeam := principals["dev"].(tuf.Team)
+		require.True(t, isTeam)
+		assert.Equal(t, 2, team.GetThreshold())
+		assert.Len(t, team.GetMembers(), 2)
+	})
+
+	t.Run("member not in policy", func(t *testing.T) {
+		err := r.AddTeamToTargets(testCtx, targetsSigner, policy.TargetsRoleName, "security", []string{"jane.doe"}, 1, false)
+		assert.ErrorIs(t, err, tuf.ErrPrincipalNotFound)
+	})
+}
+
+func TestUpdateTeamThresholdForRule(t *testing.T) {
+	t.Setenv(dev.DevModeKey, "1")
+
+	r := createTestRepositoryWithPolicy(t, "")
+
+	targetsSigner := setupSSHKeysForSigning(t, targetsKeyBytes, targetsPubKeyBytes)
+	targetsPubKey := tufv01.NewKeyFromSSLibKey(targetsSigner.MetadataKey())
+
+	gpgKeyR, err := gpg.LoadGPGKeyFromBytes(gpgPubKeyBytes)
+	if err != nil {
+		t.Fatal(err)
+	}
+	gpgKey := tufv01.NewKeyFromSSLibKey(gpgKeyR)
+
+	if err := r.AddPrincipalToTargets(testCtx, targetsSigner, policy.TargetsRoleName, []tuf.Principal{targetsPubKey}, false); err != nil {
+		t.Fatal(err)
+	}
+	if err := r.AddTeamToTargets(testCtx, targetsSigner, policy.TargetsRoleName, "dev", []string{gpgKey.KeyID, targetsPubKey.KeyID}, 1, false); err != nil {
+		t.Fatal(err)
+	}
+	if err := r.UpdateDelegation(testCtx, targetsSigner, policy.TargetsRoleName, "protect-main", []string{"dev"}, []string{"git:refs/heads/main"}, 1, false); err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("update team threshold", func(t *testing.T) {
+		err := r.UpdateTeamThresholdForRule(testCtx, targetsSigner, policy.TargetsRoleName, "protect-main", "dev", 2, false)
+		assert.Nil(t, err)
+
+		err = r.StagePolicy(testCtx, "", true, false)
+		require.Nil(t, err)
+
+		state, err := policy.LoadCurrentState(testCtx, r.r, policy.PolicyStagingRef)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		targetsMetadata, err := state.GetTargetsMetadata(policy.TargetsRoleName, false)
+		require.Nil(t, err)
+
+		assert.Contains(t, targetsMetadata.GetRules(), &tufv04.Delegation{
+			Name:           "protect-main",
+			Paths:          []string{"git:refs/heads/main"},
+			Terminating:    false,
+			Role:           tufv04.Role{PrincipalIDs: set.NewSetFromItems("dev"), Threshold: 1},
+			TeamThresholds: map[string]int{"dev": 2},
+		})
+	})
+
+	t.Run("threshold too high", func(t *testing.T) {
+		err := r.UpdateTeamThresholdForRule(testCtx, targetsSigner, policy.TargetsRoleName, "protect-main", "dev", 3, false)
+		assert.ErrorIs(t, err, tuf.ErrCannotMeetThreshold)
+	})
+
+	t.Run("team not trusted by rule", func(t *testing.T) {
+		err := r.UpdateTeamThresholdForRule(testCtx, targetsSigner, policy.TargetsRoleName, "protect-main", targetsPubKey.KeyID, 1, false)
+		assert.ErrorIs(t, err, tuf.ErrTeamNotFound)
+	})
+}
+
+func TestUpdateRuleActions(t *testing.T) {
+	t.Setenv(dev.DevModeKey, "1")
+
+	r := createTestRepositoryWithPolicy(t, "")
+
+	targetsSigner := setupSSHKeysForSigning(t, targetsKeyBytes, targetsPubKeyBytes)
+	targetsPubKey := tufv01.NewKeyFromSSLibKey(targetsSigner.MetadataKey())
+
+	if err := r.AddPrincipalToTargets(testCtx, targetsSigner, policy.TargetsRoleName, []tuf.Principal{targetsPubKey}, false); err != nil {
+		t.Fatal(err)
+	}
+	if err := r.AddDelegation(testCtx, targetsSigner, policy.TargetsRoleName, "protect-deletions", []string{targetsPubKey.KeyID}, []string{"git:refs/heads/*"}, 1, false); err != nil {
+		t.Fatal(err)
+	}
+	if err := r.AddDelegation(testCtx, targetsSigner, policy.TargetsRoleName, "protect-files", []string{targetsPubKey.KeyID}, []string{"file:*"}, 1, false); err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("update rule actions", func(t *testing.T) {
+		err := r.UpdateRuleActions(testCtx, targetsSigner, policy.TargetsRoleName, "protect-deletions", []tuf.RefAction{tuf.RefActionDelete}, false)
+		assert.Nil(t, err)
+
+		err = r.StagePolicy(testCtx, "", true, false)
+		require.Nil(t, err)
+
+		state, err := policy.LoadCurrentState(testCtx, r.r, policy.PolicyStagingRef)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		targetsMetadata, err := state.GetTargetsMetadata(policy.TargetsRoleName, false)
+		require.Nil(t, err)
+
+		assert.Contains(t, targetsMetadata.GetRules(), &tufv04.Delegation{
+			Name:        "protect-deletions",
+			Paths:       []string{"git:refs/heads/*"},
+			Terminating: false,
+			Role:        tufv04.Role{PrincipalIDs: set.NewSetFromItems(targetsPubKey.KeyID), Threshold: 1},
+			Actions:     []tuf.RefAction{tuf.RefActionDelete},
+		})
+	})
+
+	t.Run("rule protects files", func(t *testing.T) {
+		err := r.UpdateRuleActions(testCtx, targetsSigner, policy.TargetsRoleName, "protect-files", []tuf.RefAction{tuf.RefActionDelete}, false)
+		assert.ErrorIs(t, err, tuf.ErrRefActionsOnlyApplyToGitPaths)
+	})
+
+	t.Run("rule not found", func(t *testing.T) {
+		err := r.UpdateRuleActions(testCtx, targetsSigner, policy.TargetsRoleName, "missing-rule", []tuf.RefAction{tuf.RefActionDelete}, false)
+		assert.ErrorIs(t, err, tuf.ErrRuleNotFound)
+	})
+}
+
+func TestUpdateRuleValidityWindow(t *testing.T) {
+	t.Setenv(dev.DevModeKey, "1")
+
+	r := createTestRepositoryWithPolicy(t, "")
+
+	targetsSigner := setupSSHKeysForSigning(t, targetsKeyBytes, targetsPubKeyBytes)
+	targetsPubKey := tufv01.NewKeyFromSSLibKey(targetsSigner.MetadataKey())
+
+	if err := r.AddPrincipalToTargets(testCtx, targetsSigner, policy.TargetsRoleName, []tuf.Principal{targetsPubKey}, false); err != nil {
+		t.Fatal(err)
+	}
+	if err := r.AddDelegation(testCtx, targetsSigner, policy.TargetsRoleName, "contractor-access", []string{targetsPubKey.KeyID}, []string{"git:refs/heads/contractor/*"}, 1, false); err != nil {
+		t.Fatal(err)
+	}
+
+	notBefore := time.Date(2025, time.March, 1, 0, 0, 0, 0, time.UTC)
+	notAfter := time.Date(2025, time.March, 8, 0, 0, 0, 0, time.UTC)
+
+	t.Run("set validity window", func(t *testing.T) {
+		err := r.UpdateRuleValidityWindow(testCtx, targetsSigner, policy.TargetsRoleName, "contractor-access", notBefore, notAfter, false)
+		assert.Nil(t, err)
+
+		err = r.StagePolicy(testCtx, "", true, false)
+		require.Nil(t, err)
+
+		state, err := policy.LoadCurrentState(testCtx, r.r, policy.PolicyStagingRef)
+		require.Nil(t, err)
+
+		targetsMetadata, err := state.GetTargetsMetadata(policy.TargetsRoleName, false)
+		require.Nil(t, err)
+
+		rules := targetsMetadata.GetRules()
+		assert.Equal(t, "contractor-access", rules[1].ID())
+		assert.Equal(t, &tuf.ValidityWindow{NotBefore: &notBefore, NotAfter: &notAfter}, rules[1].GetValidityWindow())
+	})
+
+	t.Run("clear validity window", func(t *testing.T) {
+		err := r.UpdateRuleValidityWindow(testCtx, targetsSigner, policy.TargetsRoleName, "contractor-access", time.Time{}, time.Time{}, false)
+		assert.Nil(t, err)
+
+		err = r.StagePolicy(testCtx, "", true, false)
+		require.Nil(t, err)
+
+		state, err := policy.LoadCurrentState(testCtx, r.r, policy.PolicyStagingRef)
+		require.Nil(t, err)
+
+		targetsMetadata, err := state.GetTargetsMetadata(policy.TargetsRoleName, false)
+		require.Nil(t, err)
+
+		rules := targetsMetadata.GetRules()
+		assert.Nil(t, rules[1].GetValidityWindow())
+	})
+
+	t.Run("invalid validity window", func(t *testing.T) {
+		err := r.UpdateRuleValidityWindow(testCtx, targetsSigner, policy.TargetsRoleName, "contractor-access", notAfter, notBefore, false)
+		assert.ErrorIs(t, err, tuf.ErrInvalidValidityWindow)
+	})
+
+	t.Run("rule not found", func(t *testing.T) {
+		err := r.UpdateRuleValidityWindow(testCtx, targetsSigner, policy.TargetsRoleName, "missing-rule", notBefore, notAfter, false)
+		assert.ErrorIs(t, err, tuf.ErrRuleNotFound)
+	})
+}
+
+func TestUpdatePrincipalValidityWindow(t *testing.T) {
+	t.Setenv(dev.DevModeKey, "1")
+
+	r := createTestRepositoryWithPolicy(t, "")
+
+	targetsSigner := setupSSHKeysForSigning(t, targetsKeyBytes, targetsPubKeyBytes)
+	targetsPubKey := tufv01.NewKeyFromSSLibKey(targetsSigner.MetadataKey())
+
+	if err := r.AddPrincipalToTargets(testCtx, targetsSigner, policy.TargetsRoleName, []tuf.Principal{targetsPubKey}, false); err != nil {
+		t.Fatal(err)
+	}
+
+	notAfter := time.Date(2025, time.March, 8, 0, 0, 0, 0, time.UTC)
+
+	t.Run("set validity window", func(t *testing.T) {
+		err := r.UpdatePrincipalValidityWindow(testCtx, targetsSigner, policy.TargetsRoleName, targetsPubKey.ID(), time.Time{}, notAfter, false)
+		assert.Nil(t, err)
+
+		err = r.StagePolicy(testCtx, "", true, false)
+		require.Nil(t, err)
+
+		state, err := policy.LoadCurrentState(testCtx, r.r, policy.PolicyStagingRef)
+		require.Nil(t, err)
+
+		targetsMetadata, err := state.GetTargetsMetadata(policy.TargetsRoleName, false)
+		require.Nil(t, err)
+
+		assert.Equal(t, map[string]*tuf.ValidityWindow{targetsPubKey.ID(): {NotAfter: &notAfter}}, targetsMetadata.GetPrincipalValidityWindows())
+	})
+
+	t.Run("clear validity window", func(t *testing.T) {
+		err := r.UpdatePrincipalValidityWindow(testCtx, targetsSigner, policy.TargetsRoleName, targetsPubKey.ID(), time.Time{}, time.Time{}, false)
+		assert.Nil(t, err)
+
+		err = r.StagePolicy(testCtx, "", true, false)
+		require.Nil(t, err)
+
+		state, err := policy.LoadCurrentState(testCtx, r.r, policy.PolicyStagingRef)
+		require.Nil(t, err)
+
+		targetsMetadata, err := state.GetTargetsMetadata(policy.TargetsRoleName, false)
+		require.Nil(t, err)
+
+		assert.Empty(t, targetsMetadata.GetPrincipalValidityWindows())
+	})
+
+	t.Run("principal not found", func(t *testing.T) {
+		err := r.UpdatePrincipalValidityWindow(testCtx, targetsSigner, policy.TargetsRoleName, "missing-principal", time.Time{}, notAfter, false)
+		assert.ErrorIs(t, err, tuf.ErrPrincipalNotFound)
+	})
+}
+
 func TestRemovePrincicpalFromTargets(t *testing.T) {
 	r := createTestRepositoryWithPolicy(t, "")
 
@@ -353,6 +632,53 @@ func TestRemovePrincicpalFromTargets(t *testing.T) {
 	assert.Contains(t, targetsMetadata.GetPrincipals(), gpgKey.KeyID)
 }
 
+func TestRotatePersonKey(t *testing.T) {
+	t.Setenv(dev.DevModeKey, "1")
+
+	r := createTestRepositoryWithPolicy(t, "")
+
+	targetsSigner := setupSSHKeysForSigning(t, targetsKeyBytes, targetsPubKeyBytes)
+
+	oldKey := tufv04.NewKeyFromSSLibKey(setupSSHKeysForSigning(t, rootKeyBytes, rootPubKeyBytes).MetadataKey())
+	newKey := tufv04.NewKeyFromSSLibKey(targetsSigner.MetadataKey())
+
+	person := &tufv04.Person{
+		PersonID:   "jane.doe@example.com",
+		PublicKeys: map[string]*tufv04.Key{oldKey.KeyID: oldKey},
+	}
+
+	err := r.AddPrincipalToTargets(testCtx, targetsSigner, policy.TargetsRoleName, []tuf.Principal{person}, false)
+	require.Nil(t, err)
+
+	err = r.RotatePersonKey(testCtx, targetsSigner, policy.TargetsRoleName, person.PersonID, newKey.KeyID, newKey, time.Hour, false)
+	assert.ErrorIs(t, err, tuf.ErrKeyNotFound)
+
+	err = r.RotatePersonKey(testCtx, targetsSigner, policy.TargetsRoleName, person.PersonID, oldKey.KeyID, newKey, time.Hour, false)
+	assert.Nil(t, err)
+
+	err = r.StagePolicy(testCtx, "", true, false)
+	require.Nil(t, err)
+
+	state, err := policy.LoadCurrentState(testCtx, r.r, policy.PolicyStagingRef)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	targetsMetadata, err := state.GetTargetsMetadata(policy.TargetsRoleName, false)
+	require.Nil(t, err)
+
+	rotatedPerson := targetsMetadata.GetPrincipals()[person.PersonID]
+	require.Len(t, rotatedPerson.Keys(), 1)
+	assert.Equal(t, newKey.KeyID, rotatedPerson.Keys()[0].KeyID)
+
+	keyRotations := targetsMetadata.GetKeyRotations()
+	require.Len(t, keyRotations, 1)
+	assert.Equal(t, person.PersonID, keyRotations[0].GetPrincipalID())
+	assert.Equal(t, oldKey.KeyID, keyRotations[0].GetOldKey().KeyID)
+	assert.Equal(t, newKey.KeyID, keyRotations[0].GetNewKeyID())
+	assert.WithinDuration(t, time.Now().Add(time.Hour), keyRotations[0].GetGracePeriodEnd(), time.Minute)
+}
+
 func TestSignTargets(t *testing.T) {
 	r := createTestRepositoryWithPolicy(t, "")
 
@@ -378,3 +704,40 @@ func TestSignTargets(t *testing.T) {
 
 	assert.Equal(t, 2, len(state.Metadata.TargetsEnvelope.Signatures))
 }
+
+func TestUpdateTargetsExpiry(t *testing.T) {
+	r := createTestRepositoryWithPolicy(t, "")
+
+	targetsSigner := setupSSHKeysForSigning(t, targetsKeyBytes, targetsPubKeyBytes)
+
+	t.Run("expiry in the past", func(t *testing.T) {
+		err := r.UpdateTargetsExpiry(testCtx, targetsSigner, policy.TargetsRoleName, time.Now().AddDate(0, 0, -1), false)
+		assert.ErrorIs(t, err, ErrExpiryInPast)
+	})
+
+	t.Run("unknown policy file", func(t *testing.T) {
+		err := r.UpdateTargetsExpiry(testCtx, targetsSigner, "does-not-exist", time.Now().AddDate(2, 0, 0), false)
+		assert.ErrorIs(t, err, policy.ErrMetadataNotFound)
+	})
+
+	t.Run("successful update", func(t *testing.T) {
+		expires := time.Now().AddDate(2, 0, 0).UTC().Truncate(time.Second)
+
+		err := r.UpdateTargetsExpiry(testCtx, targetsSigner, policy.TargetsRoleName, expires, false)
+		assert.Nil(t, err)
+
+		err = r.StagePolicy(testCtx, "", true, false)
+		require.Nil(t, err)
+
+		state, err := policy.LoadCurrentState(testCtx, r.r, policy.PolicyStagingRef)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		targetsMetadata, err := state.GetTargetsMetadata(policy.TargetsRoleName, false)
+		if err != nil {
+			t.Fatal(err)
+		}
+		assert.Equal(t, expires.Format(time.RFC3339), targetsMetadata.GetExpires())
+	})
+}
@@ -14,6 +14,7 @@ import (
 	"github.com/gittuf/gittuf/internal/dev"
 	"github.com/gittuf/gittuf/internal/gitinterface"
 	"github.com/gittuf/gittuf/internal/policy"
+	policyopts "github.com/gittuf/gittuf/internal/policy/options/policy"
 )
 
 // ErrRefStateDoesNotMatchRSL is returned when a Git reference being verified
@@ -60,7 +61,7 @@ func (r *Repository) VerifyRef(ctx context.Context, refName string, opts ...veri
 
 	slog.Debug(fmt.Sprintf("Verifying gittuf policies for '%s'", refName))
 
-	verifier := policy.NewPolicyVerifier(r.r)
+	verifier := policy.NewPolicyVerifier(r.r, policyVerifierOptions(options)...)
 
 	if options.LatestOnly {
 		expectedTip, err = verifier.VerifyRef(ctx, refName)
@@ -68,12 +69,15 @@ func (r *Repository) VerifyRef(ctx context.Context, refName string, opts ...veri
 		expectedTip, err = verifier.VerifyRefFull(ctx, refName)
 	}
 	if err != nil {
+		populateReport(options.Report, verifier, refName, err)
 		return err
 	}
 
 	// To verify the tip, we _must_ use the localRefName
 	slog.Debug("Verifying if tip of reference matches expected value from RSL...")
-	if err := r.verifyRefTip(localRefName, expectedTip); err != nil {
+	err = r.verifyRefTip(localRefName, expectedTip)
+	populateReport(options.Report, verifier, refName, err)
+	if err != nil {
 		return err
 	}
 
@@ -121,15 +125,18 @@ func (r *Repository) VerifyRefFromEntry(ctx context.Context, refName, entryID st
 	}
 
 	slog.Debug(fmt.Sprintf("Verifying gittuf policies for '%s' from entry '%s'", refName, entryID))
-	verifier := policy.NewPolicyVerifier(r.r)
+	verifier := policy.NewPolicyVerifier(r.r, policyVerifierOptions(options)...)
 	expectedTip, err := verifier.VerifyRefFromEntry(ctx, refName, entryIDHash)
 	if err != nil {
+		populateReport(options.Report, verifier, refName, err)
 		return err
 	}
 
 	// To verify the tip, we _must_ use the localRefName
 	slog.Debug("Verifying if tip of reference matches expected value from RSL...")
-	if err := r.verifyRefTip(localRefName, expectedTip); err != nil {
+	err = r.verifyRefTip(localRefName, expectedTip)
+	populateReport(options.Report, verifier, refName, err)
+	if err != nil {
 		return err
 	}
 
@@ -137,6 +144,69 @@ func (r *Repository) VerifyRefFromEntry(ctx context.Context, refName, entryID st
 	return nil
 }
 
+// VerifyAllRefs verifies every reference recorded in the RSL that matches the
+// glob pattern in one pass, returning a result for each reference. All
+// references outside the gittuf namespace are verified if pattern is empty. The
+// tip of each reference that exists in the local repository must also match the
+// latest RSL entry for the reference. An error is returned only if the RSL
+// cannot be inspected; verification failures are recorded in the results.
+func (r *Repository) VerifyAllRefs(ctx context.Context, pattern string, opts ...verifyopts.Option) ([]*policy.RefVerificationResult, error) {
+	options := &verifyopts.Options{}
+	for _, fn := range opts {
+		fn(options)
+	}
+
+	slog.Debug(fmt.Sprintf("Verifying gittuf policies for all references matching '%s'", pattern))
+
+	verifier := policy.NewPolicyVerifier(r.r, policyVerifierOptions(options)...)
+
+	var (
+		results []*policy.RefVerificationResult
+		err     error
+	)
+	if options.LatestOnly {
+		results, err = verifier.VerifyAllRefs(ctx, pattern)
+	} else {
+		results, err = verifier.VerifyAllRefsFull(ctx, pattern)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	slog.Debug("Verifying if tips of references match expected values from RSL...")
+	for _, result := range results {
+		if result.Err != nil {
+			continue
+		}
+
+		err := r.verifyRefTip(result.Ref, result.ExpectedTip)
+		if errors.Is(err, gitinterface.ErrReferenceNotFound) {
+			slog.Debug(fmt.Sprintf("Reference '%s' does not exist locally, skipping check of its tip...", result.Ref))
+			continue
+		}
+		if err != nil {
+			result.Err = err
+			if errors.Is(err, ErrRefStateDoesNotMatchRSL) {
+				result.Report.AddCheck(&policy.VerificationCheck{Type: policy.CheckTypeRefTip, Ref: result.Ref, Passed: false, Message: err.Error()})
+			}
+			result.Report.SetOutcome(err)
+		}
+	}
+
+	return results, nil
+}
+
+// policyVerifierOptions returns the options for the policy verifier
+// corresponding to the verification options.
+func policyVerifierOptions(options *verifyopts.Options) []policyopts.PolicyVerifierOption {
+	verifierOpts := []policyopts.PolicyVerifierOption{policyopts.WithWorkers(options.Workers)}
+	if options.UseCheckpoints {
+		verifierOpts = append(verifierOpts, policyopts.WithCheckpoints())
+	}
+
+	return verifierOpts
+}
+
 // VerifyMergeable checks if the targetRef can be updated to reflect the changes
 // in featureRef. It checks if sufficient authorizations / approvals exist for
 // the merge to happen, indicated by the error being nil. Additionally, a
@@ -174,20 +244,19 @@ func (r *Repository) VerifyMergeable(ctx context.Context, targetRef, featureRef
 
 	if options.BypassRSLForFeatureRef {
 		slog.Debug("Not using RSL for feature ref...")
-		featureID, err := r.r.GetReference(featureRef)
+		var featureID gitinterface.Hash
+		featureID, err = r.r.GetReference(featureRef)
 		if err != nil {
 			return false, err
 		}
 
 		needRSLSignature, err = verifier.VerifyMergeableForCommit(ctx, targetRef, featureID)
-		if err != nil {
-			return false, err
-		}
 	} else {
 		needRSLSignature, err = verifier.VerifyMergeable(ctx, targetRef, featureRef)
-		if err != nil {
-			return false, err
-		}
+	}
+	populateReport(options.Report, verifier, targetRef, err)
+	if err != nil {
+		return false, err
 	}
 
 	if needRSLSignature {
@@ -199,6 +268,28 @@ func (r *Repository) VerifyMergeable(ctx context.Context, targetRef, featureRef
 	return needRSLSignature, nil
 }
 
+// populateReport copies the report recorded by the verifier into dst. If the
+// verifier did not record a report, for example because verification failed
+// before any RSL entries were inspected, dst only records the outcome. A check
+// is added when the reference's tip does not match the RSL.
+func populateReport(dst *policy.VerificationReport, verifier *policy.PolicyVerifier, ref string, err error) {
+	if dst == nil {
+		return
+	}
+
+	report := verifier.Report()
+	if report == nil {
+		report = policy.NewVerificationReport(ref)
+	}
+
+	if errors.Is(err, ErrRefStateDoesNotMatchRSL) {
+		report.AddCheck(&policy.VerificationCheck{Type: policy.CheckTypeRefTip, Ref: ref, Passed: false, Message: err.Error()})
+	}
+	report.SetOutcome(err)
+
+	*dst = *report
+}
+
 // verifyRefTip inspects the specified reference in the local repository to
 // check if it points to the expected Git object.
 func (r *Repository) verifyRefTip(target string, expectedTip gitinterface.Hash) error {
@@ -14,6 +14,7 @@ import (
 	"github.com/gittuf/gittuf/internal/policy"
 	"github.com/gittuf/gittuf/internal/rsl"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestVerifyRef(t *testing.T) {
@@ -97,6 +98,62 @@ func TestVerifyRef(t *testing.T) {
 	assert.ErrorIs(t, err, ErrRefStateDoesNotMatchRSL)
 	err = repo.VerifyRef(testCtx, refName, verifyopts.WithLatestOnly())
 	assert.ErrorIs(t, err, ErrRefStateDoesNotMatchRSL)
+
+	t.Run("with report", func(t *testing.T) {
+		report := &policy.VerificationReport{}
+		err := repo.VerifyRef(testCtx, refName, verifyopts.WithLatestOnly(), verifyopts.WithReport(report))
+		assert.ErrorIs(t, err, ErrRefStateDoesNotMatchRSL)
+
+		assert.Equal(t, refName, report.Ref)
+		assert.False(t, report.Passed)
+		assert.Equal(t, ErrRefStateDoesNotMatchRSL.Error(), report.Error)
+
+		failures := report.Failures()
+		require.Len(t, failures, 1)
+		assert.Equal(t, policy.CheckTypeRefTip, failures[0].Type)
+	})
+}
+
+func TestVerifyAllRefs(t *testing.T) {
+	repo := createTestRepositoryWithPolicy(t, "")
+
+	mainRef := "refs/heads/main"
+	featureRef := "refs/heads/feature"
+
+	commitIDs := common.AddNTestCommitsToSpecifiedRef(t, repo.r, mainRef, 1, gpgKeyBytes)
+	common.CreateTestRSLReferenceEntryCommit(t, repo.r, rsl.NewReferenceEntry(mainRef, commitIDs[0]), gpgKeyBytes)
+
+	commitIDs = common.AddNTestCommitsToSpecifiedRef(t, repo.r, featureRef, 1, gpgKeyBytes)
+	common.CreateTestRSLReferenceEntryCommit(t, repo.r, rsl.NewReferenceEntry(featureRef, commitIDs[0]), gpgKeyBytes)
+
+	results, err := repo.VerifyAllRefs(testCtx, "")
+	require.Nil(t, err)
+	require.Len(t, results, 2)
+	for _, result := range results {
+		assert.Nil(t, result.Err)
+	}
+
+	results, err = repo.VerifyAllRefs(testCtx, "refs/heads/f*", verifyopts.WithLatestOnly())
+	require.Nil(t, err)
+	require.Len(t, results, 1)
+	assert.Equal(t, featureRef, results[0].Ref)
+
+	// Add a commit to main without an RSL entry
+	common.AddNTestCommitsToSpecifiedRef(t, repo.r, mainRef, 1, gpgKeyBytes)
+
+	results, err = repo.VerifyAllRefs(testCtx, "")
+	require.Nil(t, err)
+	require.Len(t, results, 2)
+
+	assert.Equal(t, featureRef, results[0].Ref)
+	assert.Nil(t, results[0].Err)
+
+	assert.Equal(t, mainRef, results[1].Ref)
+	assert.ErrorIs(t, results[1].Err, ErrRefStateDoesNotMatchRSL)
+	assert.False(t, results[1].Report.Passed)
+	failures := results[1].Report.Failures()
+	require.Len(t, failures, 1)
+	assert.Equal(t, policy.CheckTypeRefTip, failures[0].Type)
 }
 
 func TestVerifyRefFromEntry(t *testing.T) {
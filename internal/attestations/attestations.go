@@ -25,6 +25,8 @@ const (
 	codeReviewApprovalAttestationsTreeEntryName = "code-review-approvals"
 	codeReviewApprovalIndexTreeEntryName        = "review-index.json"
 
+	hookExecutionAttestationsTreeEntryName = "hook-executions"
+
 	initialCommitMessage = "Initial commit"
 	defaultCommitMessage = "Update attestations"
 )
@@ -70,6 +72,14 @@ type Attestations struct {
 	// attestations namespace as a special blob in the
 	// codeReviewApprovalAttestations tree.
 	codeReviewApprovalIndex map[string]string
+
+	// hookExecutionAttestations maps each run of a hook for a change to a
+	// reference to the blob ID of the attestation. The key is a path of the
+	// form `<ref-path>/<from-id>-<to-id>/<hook-name>`, where `ref-path` is the
+	// absolute ref path such as `refs/heads/main`, `from-id` and `to-id`
+	// determine how the ref in question moved, and `hook-name` is the name of
+	// the hook in the root of trust.
+	hookExecutionAttestations map[string]gitinterface.Hash
 }
 
 // LoadCurrentAttestations inspects the repository's attestations namespace and
@@ -116,6 +126,7 @@ func LoadAttestationsForEntry(repo *gitinterface.Repository, entry rsl.Reference
 		githubPullRequestAttestations:  map[string]gitinterface.Hash{},
 		codeReviewApprovalAttestations: map[string]gitinterface.Hash{},
 		codeReviewApprovalIndex:        map[string]string{},
+		hookExecutionAttestations:      map[string]gitinterface.Hash{},
 	}
 
 	for name, blobID := range treeContents {
@@ -126,6 +137,8 @@ func LoadAttestationsForEntry(repo *gitinterface.Repository, entry rsl.Reference
 			attestations.githubPullRequestAttestations[strings.TrimPrefix(name, githubPullRequestAttestationsTreeEntryName+"/")] = blobID
 		case strings.HasPrefix(name, codeReviewApprovalAttestationsTreeEntryName+"/"):
 			attestations.codeReviewApprovalAttestations[strings.TrimPrefix(name, codeReviewApprovalAttestationsTreeEntryName+"/")] = blobID
+		case strings.HasPrefix(name, hookExecutionAttestationsTreeEntryName+"/"):
+			attestations.hookExecutionAttestations[strings.TrimPrefix(name, hookExecutionAttestationsTreeEntryName+"/")] = blobID
 		}
 	}
 
@@ -179,6 +192,9 @@ func (a *Attestations) Commit(repo *gitinterface.Repository, commitMessage strin
 	for name, blobID := range a.codeReviewApprovalAttestations {
 		allAttestations = append(allAttestations, gitinterface.NewEntryBlob(path.Join(codeReviewApprovalAttestationsTreeEntryName, name), blobID))
 	}
+	for name, blobID := range a.hookExecutionAttestations {
+		allAttestations = append(allAttestations, gitinterface.NewEntryBlob(path.Join(hookExecutionAttestationsTreeEntryName, name), blobID))
+	}
 
 	attestationsTreeID, err := treeBuilder.WriteTreeFromEntries(allAttestations)
 	if err != nil {
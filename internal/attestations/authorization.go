@@ -36,6 +36,44 @@ func NewReferenceAuthorizationForTag(targetRef, fromID, toID string) (*ita.State
 	return authorizationsv02.NewReferenceAuthorizationForTag(targetRef, fromID, toID)
 }
 
+// AddHatToReferenceAuthorization returns a copy of the reference authorization
+// statement embedded in env that declares that the signature made using keyID
+// is made in the specified role. The returned statement must be embedded in a
+// new envelope and signed again, as existing signatures are over the prior
+// payload.
+func AddHatToReferenceAuthorization(env *sslibdsse.Envelope, keyID, role string) (*ita.Statement, error) {
+	predicateType, err := inspectPredicateType(env)
+	if err != nil {
+		return nil, err
+	}
+
+	switch predicateType {
+	case authorizationsv01.PredicateType:
+		return nil, authorizations.ErrHatsNotSupported
+	case authorizationsv02.PredicateType:
+		return authorizationsv02.AddHat(env, keyID, role)
+	default:
+		return nil, authorizations.ErrUnknownAuthorizationVersion
+	}
+}
+
+// GetReferenceAuthorizationHats returns the roles declared for the signatures
+// on the reference authorization embedded in env, keyed by the ID of the
+// signing key. If env does not contain a reference authorization that supports
+// hats, no hats are returned.
+func GetReferenceAuthorizationHats(env *sslibdsse.Envelope) (map[string]string, error) {
+	predicateType, err := inspectPredicateType(env)
+	if err != nil {
+		return nil, err
+	}
+
+	if predicateType != authorizationsv02.PredicateType {
+		return nil, nil
+	}
+
+	return authorizationsv02.GetHats(env)
+}
+
 // SetReferenceAuthorization writes the new reference authorization attestation
 // to the object store and tracks it in the current attestations state.
 func (a *Attestations) SetReferenceAuthorization(repo *gitinterface.Repository, env *sslibdsse.Envelope, refName, fromID, toID string) error {
@@ -140,3 +178,17 @@ func (a *Attestations) GetReferenceAuthorizationFor(repo *gitinterface.Repositor
 func ReferenceAuthorizationPath(refName, fromID, toID string) string {
 	return path.Join(refName, fmt.Sprintf("%s-%s", fromID, toID))
 }
+
+func inspectPredicateType(env *sslibdsse.Envelope) (any, error) {
+	payloadBytes, err := env.DecodeB64Payload()
+	if err != nil {
+		return nil, fmt.Errorf("unable to inspect reference authorization: %w", err)
+	}
+
+	inspectAuthorization := map[string]any{}
+	if err := json.Unmarshal(payloadBytes, &inspectAuthorization); err != nil {
+		return nil, fmt.Errorf("unable to inspect reference authorization: %w", err)
+	}
+
+	return inspectAuthorization["predicate_type"], nil
+}
@@ -6,11 +6,13 @@ package attestations
 import (
 	"testing"
 
+	"github.com/gittuf/gittuf/internal/attestations/authorizations"
 	"github.com/gittuf/gittuf/internal/gitinterface"
 	"github.com/gittuf/gittuf/internal/signerverifier/dsse"
 	sslibdsse "github.com/gittuf/gittuf/internal/third_party/go-securesystemslib/dsse"
 	ita "github.com/in-toto/attestation/go/v1"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestSetReferenceAuthorization(t *testing.T) {
@@ -168,6 +170,40 @@ func TestGetReferenceAuthorizationFor(t *testing.T) {
 	})
 }
 
+func TestReferenceAuthorizationHats(t *testing.T) {
+	testRef := "refs/heads/main"
+	testID := gitinterface.ZeroHash.String()
+
+	t.Run("add and get hats", func(t *testing.T) {
+		env := createReferenceAuthorizationAttestationEnvelopes(t, testRef, testID, testID, false)
+
+		hats, err := GetReferenceAuthorizationHats(env)
+		assert.Nil(t, err)
+		assert.Empty(t, hats)
+
+		statement, err := AddHatToReferenceAuthorization(env, "key-1", "security")
+		require.Nil(t, err)
+		env, err = dsse.CreateEnvelope(statement)
+		require.Nil(t, err)
+
+		hats, err = GetReferenceAuthorizationHats(env)
+		assert.Nil(t, err)
+		assert.Equal(t, map[string]string{"key-1": "security"}, hats)
+	})
+
+	t.Run("not a reference authorization", func(t *testing.T) {
+		env, err := dsse.CreateEnvelope(map[string]string{"type": "root"})
+		require.Nil(t, err)
+
+		hats, err := GetReferenceAuthorizationHats(env)
+		assert.Nil(t, err)
+		assert.Nil(t, hats)
+
+		_, err = AddHatToReferenceAuthorization(env, "key-1", "security")
+		assert.ErrorIs(t, err, authorizations.ErrUnknownAuthorizationVersion)
+	})
+}
+
 func createReferenceAuthorizationAttestationEnvelopes(t *testing.T, refName, fromID, toID string, tag bool) *sslibdsse.Envelope {
 	t.Helper()
 
@@ -23,6 +23,7 @@ const (
 	targetRefKey       = "targetRef"
 	fromIDKey          = "fromID"
 	targetIDKey        = "targetID"
+	hatsKey            = "hats"
 )
 
 // ReferenceAuthorization is a lightweight record of a detached authorization in
@@ -32,6 +33,11 @@ type ReferenceAuthorization struct {
 	TargetRef string `json:"targetRef"`
 	FromID    string `json:"fromID"`
 	TargetID  string `json:"targetID"`
+
+	// Hats records the role (the "hat") each signature on the authorization is
+	// made in, keyed by the ID of the signing key. A signature with a declared
+	// hat is only counted towards the threshold of that role.
+	Hats map[string]string `json:"hats,omitempty"`
 }
 
 func (r *ReferenceAuthorization) GetRef() string {
@@ -46,6 +52,12 @@ func (r *ReferenceAuthorization) GetTargetID() string {
 	return r.TargetID
 }
 
+// GetHats returns the roles declared for the signatures on the authorization,
+// keyed by the ID of the signing key.
+func (r *ReferenceAuthorization) GetHats() map[string]string {
+	return r.Hats
+}
+
 // NewReferenceAuthorizationForCommit creates a new reference authorization for
 // the provided information. The authorization is embedded in an in-toto
 // "statement" and returned with the appropriate "predicate type" set. The
@@ -144,6 +156,76 @@ func Validate(env *sslibdsse.Envelope, targetRef, fromID, targetID string) error
 	return nil
 }
 
+// AddHat returns a copy of the reference authorization statement embedded in
+// env that declares that the signature made using keyID is made in the
+// specified role. As the payload changes, the returned statement must be
+// embedded in a new envelope and signed again.
+func AddHat(env *sslibdsse.Envelope, keyID, role string) (*ita.Statement, error) {
+	attestation, err := loadStatement(env)
+	if err != nil {
+		return nil, err
+	}
+
+	predicate := attestation.Predicate.AsMap()
+	hats, isMap := predicate[hatsKey].(map[string]any)
+	if !isMap {
+		hats = map[string]any{}
+	}
+	hats[keyID] = role
+	predicate[hatsKey] = hats
+
+	predicateStruct, err := structpb.NewStruct(predicate)
+	if err != nil {
+		return nil, err
+	}
+	attestation.Predicate = predicateStruct
+
+	return attestation, nil
+}
+
+// GetHats returns the roles declared for the signatures on the reference
+// authorization embedded in env, keyed by the ID of the signing key.
+func GetHats(env *sslibdsse.Envelope) (map[string]string, error) {
+	attestation, err := loadStatement(env)
+	if err != nil {
+		return nil, err
+	}
+
+	hats, isMap := attestation.Predicate.AsMap()[hatsKey].(map[string]any)
+	if !isMap {
+		return nil, nil
+	}
+
+	declaredHats := make(map[string]string, len(hats))
+	for keyID, role := range hats {
+		role, isString := role.(string)
+		if !isString {
+			return nil, authorizations.ErrInvalidAuthorization
+		}
+		declaredHats[keyID] = role
+	}
+
+	return declaredHats, nil
+}
+
+func loadStatement(env *sslibdsse.Envelope) (*ita.Statement, error) {
+	payload, err := env.DecodeB64Payload()
+	if err != nil {
+		return nil, err
+	}
+
+	attestation := &ita.Statement{}
+	if err := json.Unmarshal(payload, attestation); err != nil {
+		return nil, err
+	}
+
+	if attestation.PredicateType != PredicateType || attestation.Predicate == nil {
+		return nil, authorizations.ErrInvalidAuthorization
+	}
+
+	return attestation, nil
+}
+
 func newReferenceAuthorizationStruct(targetRef, fromID, targetID string) (*structpb.Struct, error) {
 	predicate := &ReferenceAuthorization{
 		TargetRef: targetRef,
@@ -12,6 +12,7 @@ import (
 	sslibdsse "github.com/gittuf/gittuf/internal/third_party/go-securesystemslib/dsse"
 	ita "github.com/in-toto/attestation/go/v1"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestNewReferenceAuthorization(t *testing.T) {
@@ -123,6 +124,59 @@ func TestValidate(t *testing.T) {
 	})
 }
 
+func TestHats(t *testing.T) {
+	testRef := "refs/heads/main"
+	testID := gitinterface.ZeroHash.String()
+
+	t.Run("no hats declared", func(t *testing.T) {
+		env := createTestEnvelope(t, testRef, testID, testID, false)
+
+		hats, err := GetHats(env)
+		assert.Nil(t, err)
+		assert.Empty(t, hats)
+	})
+
+	t.Run("add hats", func(t *testing.T) {
+		env := createTestEnvelope(t, testRef, testID, testID, false)
+
+		statement, err := AddHat(env, "key-1", "dev")
+		require.Nil(t, err)
+		env, err = dsse.CreateEnvelope(statement)
+		require.Nil(t, err)
+
+		statement, err = AddHat(env, "key-2", "security")
+		require.Nil(t, err)
+		env, err = dsse.CreateEnvelope(statement)
+		require.Nil(t, err)
+
+		hats, err := GetHats(env)
+		assert.Nil(t, err)
+		assert.Equal(t, map[string]string{"key-1": "dev", "key-2": "security"}, hats)
+
+		// The authorization must still be valid for the change
+		err = Validate(env, testRef, testID, testID)
+		assert.Nil(t, err)
+	})
+
+	t.Run("replace hat", func(t *testing.T) {
+		env := createTestEnvelope(t, testRef, testID, testID, false)
+
+		statement, err := AddHat(env, "key-1", "dev")
+		require.Nil(t, err)
+		env, err = dsse.CreateEnvelope(statement)
+		require.Nil(t, err)
+
+		statement, err = AddHat(env, "key-1", "security")
+		require.Nil(t, err)
+		env, err = dsse.CreateEnvelope(statement)
+		require.Nil(t, err)
+
+		hats, err := GetHats(env)
+		assert.Nil(t, err)
+		assert.Equal(t, map[string]string{"key-1": "security"}, hats)
+	})
+}
+
 func createTestEnvelope(t *testing.T, refName, fromID, toID string, tag bool) *sslibdsse.Envelope {
 	t.Helper()
 
@@ -0,0 +1,87 @@
+// Copyright The gittuf Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package attestations
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"path"
+
+	"github.com/gittuf/gittuf/internal/attestations/hooks"
+	hooksv01 "github.com/gittuf/gittuf/internal/attestations/hooks/v01"
+	"github.com/gittuf/gittuf/internal/gitinterface"
+	sslibdsse "github.com/gittuf/gittuf/internal/third_party/go-securesystemslib/dsse"
+	ita "github.com/in-toto/attestation/go/v1"
+)
+
+// NewHookExecutionAttestation creates a new hook execution attestation for the
+// provided information. The attestation is embedded in an in-toto "statement"
+// and returned with the appropriate "predicate type" set. The `fromID` and
+// `toID` specify the change to `targetRef` the hook was run for.
+func NewHookExecutionAttestation(targetRef, fromID, toID, hookName string, exitCode int) (*ita.Statement, error) {
+	return hooksv01.NewHookExecutionAttestation(targetRef, fromID, toID, hookName, exitCode)
+}
+
+// GetHookExecution returns the hook execution recorded in the attestation
+// embedded in env.
+func GetHookExecution(env *sslibdsse.Envelope) (hooks.HookExecutionAttestation, error) {
+	return hooksv01.Load(env)
+}
+
+// SetHookExecutionAttestation writes the new hook execution attestation to the
+// object store and tracks it in the current attestations state.
+func (a *Attestations) SetHookExecutionAttestation(repo *gitinterface.Repository, env *sslibdsse.Envelope, refName, fromID, toID, hookName string) error {
+	if err := hooksv01.Validate(env, refName, fromID, toID, hookName); err != nil {
+		return errors.Join(hooks.ErrInvalidHookExecutionAttestation, err)
+	}
+
+	envBytes, err := json.Marshal(env)
+	if err != nil {
+		return err
+	}
+
+	blobID, err := repo.WriteBlob(envBytes)
+	if err != nil {
+		return err
+	}
+
+	if a.hookExecutionAttestations == nil {
+		a.hookExecutionAttestations = map[string]gitinterface.Hash{}
+	}
+
+	a.hookExecutionAttestations[HookExecutionAttestationPath(refName, fromID, toID, hookName)] = blobID
+	return nil
+}
+
+// GetHookExecutionAttestationFor returns the requested hook execution
+// attestation (with its signatures).
+func (a *Attestations) GetHookExecutionAttestationFor(repo *gitinterface.Repository, refName, fromID, toID, hookName string) (*sslibdsse.Envelope, error) {
+	blobID, has := a.hookExecutionAttestations[HookExecutionAttestationPath(refName, fromID, toID, hookName)]
+	if !has {
+		return nil, hooks.ErrHookExecutionAttestationNotFound
+	}
+
+	envBytes, err := repo.ReadBlob(blobID)
+	if err != nil {
+		return nil, err
+	}
+
+	env := &sslibdsse.Envelope{}
+	if err := json.Unmarshal(envBytes, env); err != nil {
+		return nil, err
+	}
+
+	if err := hooksv01.Validate(env, refName, fromID, toID, hookName); err != nil {
+		return nil, err
+	}
+
+	return env, nil
+}
+
+// HookExecutionAttestationPath constructs the expected path on-disk for the
+// hook execution attestation.
+func HookExecutionAttestationPath(refName, fromID, toID, hookName string) string {
+	return path.Join(refName, fmt.Sprintf("%s-%s", fromID, toID), hookName)
+}
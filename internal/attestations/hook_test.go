@@ -0,0 +1,86 @@
+// Copyright The gittuf Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package attestations
+
+import (
+	"testing"
+
+	"github.com/gittuf/gittuf/internal/attestations/hooks"
+	"github.com/gittuf/gittuf/internal/gitinterface"
+	"github.com/gittuf/gittuf/internal/signerverifier/dsse"
+	sslibdsse "github.com/gittuf/gittuf/internal/third_party/go-securesystemslib/dsse"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSetHookExecutionAttestation(t *testing.T) {
+	testRef := "refs/heads/main"
+	testID := gitinterface.ZeroHash.String()
+
+	tmpDir := t.TempDir()
+	repo := gitinterface.CreateTestGitRepository(t, tmpDir, false)
+
+	attestations := &Attestations{}
+
+	env := createHookExecutionAttestationEnvelope(t, testRef, testID, testID, "lint", 0)
+
+	// The attestation must be for the change and hook it's set for
+	err := attestations.SetHookExecutionAttestation(repo, env, testRef, testID, testID, "test")
+	assert.ErrorIs(t, err, hooks.ErrInvalidHookExecutionAttestation)
+	assert.Empty(t, attestations.hookExecutionAttestations)
+
+	err = attestations.SetHookExecutionAttestation(repo, env, testRef, testID, testID, "lint")
+	assert.Nil(t, err)
+	assert.Contains(t, attestations.hookExecutionAttestations, HookExecutionAttestationPath(testRef, testID, testID, "lint"))
+}
+
+func TestGetHookExecutionAttestationFor(t *testing.T) {
+	testRef := "refs/heads/main"
+	testID := gitinterface.ZeroHash.String()
+
+	tmpDir := t.TempDir()
+	repo := gitinterface.CreateTestGitRepository(t, tmpDir, false)
+
+	attestations := &Attestations{}
+
+	env := createHookExecutionAttestationEnvelope(t, testRef, testID, testID, "lint", 1)
+	if err := attestations.SetHookExecutionAttestation(repo, env, testRef, testID, testID, "lint"); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := attestations.Commit(repo, "Add hook execution attestation", true, false); err != nil {
+		t.Fatal(err)
+	}
+
+	attestations, err := LoadCurrentAttestations(repo)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	storedEnv, err := attestations.GetHookExecutionAttestationFor(repo, testRef, testID, testID, "lint")
+	assert.Nil(t, err)
+	assert.Equal(t, env, storedEnv)
+
+	execution, err := GetHookExecution(storedEnv)
+	assert.Nil(t, err)
+	assert.Equal(t, "lint", execution.GetHookName())
+	assert.Equal(t, 1, execution.GetExitCode())
+
+	_, err = attestations.GetHookExecutionAttestationFor(repo, testRef, testID, testID, "test")
+	assert.ErrorIs(t, err, hooks.ErrHookExecutionAttestationNotFound)
+}
+
+func createHookExecutionAttestationEnvelope(t *testing.T, refName, fromID, toID, hookName string, exitCode int) *sslibdsse.Envelope {
+	t.Helper()
+
+	attestation, err := NewHookExecutionAttestation(refName, fromID, toID, hookName, exitCode)
+	if err != nil {
+		t.Fatal(err)
+	}
+	env, err := dsse.CreateEnvelope(attestation)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return env
+}
@@ -0,0 +1,33 @@
+// Copyright The gittuf Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package hooks
+
+import "errors"
+
+var (
+	ErrInvalidHookExecutionAttestation  = errors.New("hook execution attestation does not match expected details")
+	ErrHookExecutionAttestationNotFound = errors.New("requested hook execution attestation not found")
+)
+
+// HookExecutionAttestation records that a gittuf hook was run for a change to
+// a reference, and the exit code the hook returned. The change is identified
+// using the updated ref, the prior state of the ref, and the target state of
+// the ref after the change is made.
+type HookExecutionAttestation interface {
+	// GetRef returns the reference for the change the hook was run for.
+	GetRef() string
+
+	// GetFromID returns the Git ID of the reference prior to the change.
+	GetFromID() string
+
+	// GetTargetID returns the Git ID of the reference after the change is
+	// applied.
+	GetTargetID() string
+
+	// GetHookName returns the name of the hook that was run.
+	GetHookName() string
+
+	// GetExitCode returns the exit code returned by the hook.
+	GetExitCode() int
+}
@@ -0,0 +1,146 @@
+// Copyright The gittuf Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package v01
+
+import (
+	"encoding/json"
+
+	"github.com/gittuf/gittuf/internal/attestations/common"
+	"github.com/gittuf/gittuf/internal/attestations/hooks"
+	sslibdsse "github.com/gittuf/gittuf/internal/third_party/go-securesystemslib/dsse"
+	ita "github.com/in-toto/attestation/go/v1"
+)
+
+const (
+	PredicateType = "https://gittuf.dev/hook-execution/v0.1"
+
+	digestGitCommitKey = "gitCommit"
+	targetRefKey       = "targetRef"
+	fromIDKey          = "fromID"
+	targetIDKey        = "targetID"
+	hookNameKey        = "hookName"
+	exitCodeKey        = "exitCode"
+)
+
+// HookExecution is a record of a gittuf hook being run for a change to a
+// reference. It is meant to be used as a "predicate" in an in-toto
+// attestation.
+type HookExecution struct {
+	TargetRef string `json:"targetRef"`
+	FromID    string `json:"fromID"`
+	TargetID  string `json:"targetID"`
+	HookName  string `json:"hookName"`
+	ExitCode  int    `json:"exitCode"`
+}
+
+func (h *HookExecution) GetRef() string {
+	return h.TargetRef
+}
+
+func (h *HookExecution) GetFromID() string {
+	return h.FromID
+}
+
+func (h *HookExecution) GetTargetID() string {
+	return h.TargetID
+}
+
+func (h *HookExecution) GetHookName() string {
+	return h.HookName
+}
+
+func (h *HookExecution) GetExitCode() int {
+	return h.ExitCode
+}
+
+// NewHookExecutionAttestation creates a new hook execution attestation for the
+// provided information. The attestation is embedded in an in-toto "statement"
+// and returned with the appropriate "predicate type" set. The `fromID` and
+// `targetID` specify the change to `targetRef` the hook was run for. The
+// targetID is expected to be the Git ID of the commit or tag the ref is
+// updated to.
+func NewHookExecutionAttestation(targetRef, fromID, targetID, hookName string, exitCode int) (*ita.Statement, error) {
+	predicate := &HookExecution{
+		TargetRef: targetRef,
+		FromID:    fromID,
+		TargetID:  targetID,
+		HookName:  hookName,
+		ExitCode:  exitCode,
+	}
+
+	predicateStruct, err := common.PredicateToPBStruct(predicate)
+	if err != nil {
+		return nil, err
+	}
+
+	return &ita.Statement{
+		Type: ita.StatementTypeUri,
+		Subject: []*ita.ResourceDescriptor{
+			{
+				Digest: map[string]string{digestGitCommitKey: targetID},
+			},
+		},
+		PredicateType: PredicateType,
+		Predicate:     predicateStruct,
+	}, nil
+}
+
+// Validate checks that the returned envelope contains the expected in-toto
+// attestation and predicate contents.
+func Validate(env *sslibdsse.Envelope, targetRef, fromID, targetID, hookName string) error {
+	execution, err := Load(env)
+	if err != nil {
+		return err
+	}
+
+	if execution.TargetRef != targetRef || execution.FromID != fromID || execution.TargetID != targetID || execution.HookName != hookName {
+		return hooks.ErrInvalidHookExecutionAttestation
+	}
+
+	return nil
+}
+
+// Load returns the hook execution recorded in the envelope.
+func Load(env *sslibdsse.Envelope) (*HookExecution, error) {
+	payload, err := env.DecodeB64Payload()
+	if err != nil {
+		return nil, err
+	}
+
+	attestation := &ita.Statement{}
+	if err := json.Unmarshal(payload, attestation); err != nil {
+		return nil, err
+	}
+
+	if attestation.PredicateType != PredicateType || len(attestation.Subject) == 0 {
+		return nil, hooks.ErrInvalidHookExecutionAttestation
+	}
+
+	predicate := attestation.Predicate.AsMap()
+	execution := &HookExecution{}
+	var isString bool
+	if execution.TargetRef, isString = predicate[targetRefKey].(string); !isString {
+		return nil, hooks.ErrInvalidHookExecutionAttestation
+	}
+	if execution.FromID, isString = predicate[fromIDKey].(string); !isString {
+		return nil, hooks.ErrInvalidHookExecutionAttestation
+	}
+	if execution.TargetID, isString = predicate[targetIDKey].(string); !isString {
+		return nil, hooks.ErrInvalidHookExecutionAttestation
+	}
+	if execution.HookName, isString = predicate[hookNameKey].(string); !isString {
+		return nil, hooks.ErrInvalidHookExecutionAttestation
+	}
+	exitCode, isNumber := predicate[exitCodeKey].(float64)
+	if !isNumber {
+		return nil, hooks.ErrInvalidHookExecutionAttestation
+	}
+	execution.ExitCode = int(exitCode)
+
+	if attestation.Subject[0].Digest[digestGitCommitKey] != execution.TargetID {
+		return nil, hooks.ErrInvalidHookExecutionAttestation
+	}
+
+	return execution, nil
+}
@@ -0,0 +1,84 @@
+// Copyright The gittuf Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package v01
+
+import (
+	"testing"
+
+	"github.com/gittuf/gittuf/internal/attestations/hooks"
+	"github.com/gittuf/gittuf/internal/gitinterface"
+	"github.com/gittuf/gittuf/internal/signerverifier/dsse"
+	sslibdsse "github.com/gittuf/gittuf/internal/third_party/go-securesystemslib/dsse"
+	ita "github.com/in-toto/attestation/go/v1"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewHookExecutionAttestation(t *testing.T) {
+	testRef := "refs/heads/main"
+	testID := gitinterface.ZeroHash.String()
+
+	attestation, err := NewHookExecutionAttestation(testRef, testID, testID, "lint", 1)
+	assert.Nil(t, err)
+
+	// Check value of statement type
+	assert.Equal(t, ita.StatementTypeUri, attestation.Type)
+
+	// Check subject contents
+	assert.Equal(t, 1, len(attestation.Subject))
+	assert.Equal(t, testID, attestation.Subject[0].Digest[digestGitCommitKey])
+
+	// Check predicate type
+	assert.Equal(t, PredicateType, attestation.PredicateType)
+
+	// Check predicate
+	predicate := attestation.Predicate.AsMap()
+	assert.Equal(t, testRef, predicate[targetRefKey])
+	assert.Equal(t, testID, predicate[fromIDKey])
+	assert.Equal(t, testID, predicate[targetIDKey])
+	assert.Equal(t, "lint", predicate[hookNameKey])
+	assert.Equal(t, float64(1), predicate[exitCodeKey])
+}
+
+func TestValidate(t *testing.T) {
+	testRef := "refs/heads/main"
+	testAnotherRef := "refs/heads/feature"
+	testID := gitinterface.ZeroHash.String()
+
+	env := createTestEnvelope(t, testRef, testID, testID, "lint", 0)
+
+	err := Validate(env, testRef, testID, testID, "lint")
+	assert.Nil(t, err)
+
+	err = Validate(env, testAnotherRef, testID, testID, "lint")
+	assert.ErrorIs(t, err, hooks.ErrInvalidHookExecutionAttestation)
+
+	err = Validate(env, testRef, testID, testID, "test")
+	assert.ErrorIs(t, err, hooks.ErrInvalidHookExecutionAttestation)
+}
+
+func TestLoad(t *testing.T) {
+	testRef := "refs/heads/main"
+	testID := gitinterface.ZeroHash.String()
+
+	env := createTestEnvelope(t, testRef, testID, testID, "lint", 2)
+
+	execution, err := Load(env)
+	assert.Nil(t, err)
+	assert.Equal(t, &HookExecution{TargetRef: testRef, FromID: testID, TargetID: testID, HookName: "lint", ExitCode: 2}, execution)
+}
+
+func createTestEnvelope(t *testing.T, refName, fromID, toID, hookName string, exitCode int) *sslibdsse.Envelope {
+	t.Helper()
+
+	attestation, err := NewHookExecutionAttestation(refName, fromID, toID, hookName, exitCode)
+	if err != nil {
+		t.Fatal(err)
+	}
+	env, err := dsse.CreateEnvelope(attestation)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return env
+}
@@ -7,6 +7,7 @@ import (
 	"encoding/json"
 	"errors"
 	"log/slog"
+	"time"
 
 	"github.com/gittuf/gittuf/internal/attestations"
 	"github.com/gittuf/gittuf/internal/gitinterface"
@@ -46,10 +47,16 @@ type Persistent struct {
 	// LastVerifiedEntryForRef is a map that indicates the last verified RSL
 	// entry for a ref.
 	LastVerifiedEntryForRef map[string]RSLEntryIndex `json:"lastVerifiedEntryForRef"`
+
+	// CheckpointRecordedTimes is a map of the IDs of trusted RSL checkpoints
+	// to when they were recorded in the RSL. Determining when later entries
+	// were recorded stops at these checkpoints instead of walking back to
+	// the first entry in the RSL.
+	CheckpointRecordedTimes map[string]time.Time `json:"checkpointRecordedTimes,omitempty"`
 }
 
 func (p *Persistent) Commit(repo *gitinterface.Repository) error {
-	if len(p.PolicyEntries) == 0 && len(p.AttestationEntries) == 0 && p.AddedAttestationsBeforeNumber == 0 && len(p.LastVerifiedEntryForRef) == 0 {
+	if len(p.PolicyEntries) == 0 && len(p.AttestationEntries) == 0 && p.AddedAttestationsBeforeNumber == 0 && len(p.LastVerifiedEntryForRef) == 0 && len(p.CheckpointRecordedTimes) == 0 {
 		// nothing to do
 		return nil
 	}
@@ -0,0 +1,22 @@
+// Copyright The gittuf Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package cache
+
+import (
+	"time"
+
+	"github.com/gittuf/gittuf/internal/gitinterface"
+)
+
+func (p *Persistent) GetCheckpointRecordedTimes() map[string]time.Time {
+	return p.CheckpointRecordedTimes
+}
+
+func (p *Persistent) SetCheckpointRecordedTime(entryID gitinterface.Hash, recordedTime time.Time) {
+	if p.CheckpointRecordedTimes == nil {
+		p.CheckpointRecordedTimes = map[string]time.Time{}
+	}
+
+	p.CheckpointRecordedTimes[entryID.String()] = recordedTime
+}
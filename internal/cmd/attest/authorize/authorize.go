@@ -16,6 +16,7 @@ import (
 type options struct {
 	p       *persistent.Options
 	fromRef string
+	hat     string
 	revoke  bool
 }
 
@@ -29,6 +30,13 @@ func (o *options) AddFlags(cmd *cobra.Command) {
 	)
 	cmd.MarkFlagRequired("from-ref") //nolint:errcheck
 
+	cmd.Flags().StringVar(
+		&o.hat,
+		"hat",
+		"",
+		"role (team or rule name) the authorization is made in",
+	)
+
 	cmd.Flags().BoolVarP(
 		&o.revoke,
 		"revoke",
@@ -61,6 +69,9 @@ func (o *options) Run(cmd *cobra.Command, args []string) error {
 	if o.p.WithRSLEntry {
 		opts = append(opts, attestopts.WithRSLEntry())
 	}
+	if o.hat != "" {
+		opts = append(opts, attestopts.WithHat(o.hat))
+	}
 
 	return repo.AddReferenceAuthorization(cmd.Context(), signer, args[0], o.fromRef, true, opts...)
 }
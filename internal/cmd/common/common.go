@@ -4,13 +4,65 @@
 package common
 
 import (
+	"encoding/json"
 	"errors"
+	"fmt"
+	"io"
+	"os"
 	"strings"
+	"time"
 
+	"github.com/gittuf/gittuf/internal/policy"
 	"github.com/spf13/cobra"
 )
 
-var ErrSigningKeyNotSet = errors.New("required flag \"signing-key\" not set")
+const (
+	OutputFormatJSON  = "json"
+	OutputFormatSARIF = "sarif"
+)
+
+var (
+	ErrSigningKeyNotSet    = errors.New("required flag \"signing-key\" not set")
+	ErrInvalidExpiry       = errors.New("expiry must be an RFC 3339 timestamp or a date in the YYYY-MM-DD format")
+	ErrInvalidTimestamp    = errors.New("timestamp must be an RFC 3339 timestamp or a date in the YYYY-MM-DD format")
+	ErrInvalidOutputFormat = fmt.Errorf("output format must be one of '%s' or '%s'", OutputFormatJSON, OutputFormatSARIF)
+	ErrOutputFormatNotJSON = fmt.Errorf("output format must be '%s'", OutputFormatJSON)
+)
+
+// ParseExpiry parses an expiry specified either as an RFC 3339 timestamp or as
+// a date. A date is interpreted as midnight UTC on that day.
+func ParseExpiry(value string) (time.Time, error) {
+	if expires, err := time.Parse(time.RFC3339, value); err == nil {
+		return expires, nil
+	}
+
+	expires, err := time.Parse(time.DateOnly, value)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("%w: '%s'", ErrInvalidExpiry, value)
+	}
+
+	return expires, nil
+}
+
+// ParseTimestamp parses an optional timestamp specified either as an RFC 3339
+// timestamp or as a date. A date is interpreted as midnight UTC on that day. An
+// empty value returns the zero time.
+func ParseTimestamp(value string) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, nil
+	}
+
+	if timestamp, err := time.Parse(time.RFC3339, value); err == nil {
+		return timestamp, nil
+	}
+
+	timestamp, err := time.Parse(time.DateOnly, value)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("%w: '%s'", ErrInvalidTimestamp, value)
+	}
+
+	return timestamp, nil
+}
 
 // PublicKeys is a custom type to represent a list of paths
 type PublicKeys []string
@@ -43,3 +95,88 @@ func CheckForSigningKeyFlag(cmd *cobra.Command, _ []string) error {
 
 	return nil
 }
+
+// CheckOutputFormat checks that the report format specified via the "output"
+// flag, if any, is supported.
+func CheckOutputFormat(cmd *cobra.Command, _ []string) error {
+	switch cmd.Flags().Lookup("output").Value.String() {
+	case "", OutputFormatJSON, OutputFormatSARIF:
+		return nil
+	default:
+		return ErrInvalidOutputFormat
+	}
+}
+
+// WriteVerificationReport writes the report to w in the specified format.
+func WriteVerificationReport(w io.Writer, format string, report *policy.VerificationReport) error {
+	var (
+		reportBytes []byte
+		err         error
+	)
+
+	switch format {
+	case OutputFormatJSON:
+		reportBytes, err = json.MarshalIndent(report, "", "  ")
+	case OutputFormatSARIF:
+		reportBytes, err = report.MarshalSARIF()
+	default:
+		return ErrInvalidOutputFormat
+	}
+	if err != nil {
+		return err
+	}
+
+	_, err = fmt.Fprintln(w, string(reportBytes))
+	return err
+}
+
+// WriteVerificationReports writes the reports to w in the specified format. In
+// the JSON format, the reports are written as a list, while in the SARIF format,
+// the checks from all reports are combined into a single log.
+func WriteVerificationReports(w io.Writer, format string, reports []*policy.VerificationReport) error {
+	var (
+		reportBytes []byte
+		err         error
+	)
+
+	switch format {
+	case OutputFormatJSON:
+		reportBytes, err = json.MarshalIndent(reports, "", "  ")
+	case OutputFormatSARIF:
+		reportBytes, err = policy.MarshalSARIF(reports...)
+	default:
+		return ErrInvalidOutputFormat
+	}
+	if err != nil {
+		return err
+	}
+
+	_, err = fmt.Fprintln(w, string(reportBytes))
+	return err
+}
+
+// ReadSigningBundle reads the policy signing bundle stored at path.
+func ReadSigningBundle(path string) (*policy.SigningBundle, error) {
+	bundleBytes, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	bundle := &policy.SigningBundle{}
+	if err := json.Unmarshal(bundleBytes, bundle); err != nil {
+		return nil, fmt.Errorf("unable to read signing bundle: %w", err)
+	}
+
+	return bundle, nil
+}
+
+// WriteSigningBundle writes the policy signing bundle to path, replacing any
+// existing file.
+func WriteSigningBundle(path string, bundle *policy.SigningBundle) error {
+	bundleBytes, err := json.MarshalIndent(bundle, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(path, append(bundleBytes, '\n'), 0o644) // nolint:gosec
+}
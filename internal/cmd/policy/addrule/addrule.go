@@ -58,7 +58,7 @@ func (o *options) AddFlags(cmd *cobra.Command) {
 		&o.rulePatterns,
 		"rule-pattern",
 		[]string{},
-		"patterns used to identify namespaces rule applies to",
+		"patterns used to identify namespaces rule applies to (prefix a pattern with '!' to exclude the namespaces it matches)",
 	)
 	cmd.MarkFlagRequired("rule-pattern") //nolint:errcheck
 
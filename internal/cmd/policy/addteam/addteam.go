@@ -0,0 +1,86 @@
+// Copyright The gittuf Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package addteam
+
+import (
+	"github.com/gittuf/gittuf/experimental/gittuf"
+	trustpolicyopts "github.com/gittuf/gittuf/experimental/gittuf/options/trustpolicy"
+	"github.com/gittuf/gittuf/internal/cmd/common"
+	"github.com/gittuf/gittuf/internal/cmd/policy/persistent"
+	"github.com/gittuf/gittuf/internal/policy"
+	"github.com/spf13/cobra"
+)
+
+type options struct {
+	p          *persistent.Options
+	policyName string
+	teamID     string
+	members    []string
+	threshold  int
+}
+
+func (o *options) AddFlags(cmd *cobra.Command) {
+	cmd.Flags().StringVar(
+		&o.policyName,
+		"policy-name",
+		policy.TargetsRoleName,
+		"name of policy file to add team to",
+	)
+
+	cmd.Flags().StringVar(
+		&o.teamID,
+		"team-ID",
+		"",
+		"team ID",
+	)
+	cmd.MarkFlagRequired("team-ID") //nolint:errcheck
+
+	cmd.Flags().StringArrayVar(
+		&o.members,
+		"member",
+		[]string{},
+		"principal ID of a member of the team",
+	)
+	cmd.MarkFlagRequired("member") //nolint:errcheck
+
+	cmd.Flags().IntVar(
+		&o.threshold,
+		"threshold",
+		1,
+		"default number of members who must approve for the team to be counted",
+	)
+}
+
+func (o *options) Run(cmd *cobra.Command, _ []string) error {
+	repo, err := gittuf.LoadRepository(".")
+	if err != nil {
+		return err
+	}
+
+	signer, err := gittuf.LoadSigner(repo, o.p.SigningKey)
+	if err != nil {
+		return err
+	}
+
+	opts := []trustpolicyopts.Option{}
+	if o.p.WithRSLEntry {
+		opts = append(opts, trustpolicyopts.WithRSLEntry())
+	}
+	return repo.AddTeamToTargets(cmd.Context(), signer, o.policyName, o.teamID, o.members, o.threshold, true, opts...)
+}
+
+func New(persistent *persistent.Options) *cobra.Command {
+	o := &options{p: persistent}
+	cmd := &cobra.Command{
+		Use:               "add-team",
+		Short:             "Add a team of trusted principals to a policy file",
+		Long:              `This command allows users to add a team to the specified policy file. The team's members must already be principals in the policy file. A team can be authorized for a rule like any other principal, and is counted towards the rule's threshold once the team's threshold of members approve. By default, the main policy file is selected.`,
+		PreRunE:           common.CheckForSigningKeyFlag,
+		RunE:              o.Run,
+		DisableAutoGenTag: true,
+	}
+	o.AddFlags(cmd)
+
+	return cmd
+}
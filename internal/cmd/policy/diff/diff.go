@@ -0,0 +1,79 @@
+// Copyright The gittuf Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package diff
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/gittuf/gittuf/experimental/gittuf"
+	"github.com/gittuf/gittuf/internal/cmd/common"
+	"github.com/gittuf/gittuf/internal/policy"
+	"github.com/spf13/cobra"
+)
+
+type options struct {
+	output string
+}
+
+func (o *options) AddFlags(cmd *cobra.Command) {
+	cmd.Flags().StringVar(
+		&o.output,
+		"output",
+		"",
+		fmt.Sprintf("write the differences to stdout in the specified format (%s)", common.OutputFormatJSON),
+	)
+}
+
+func (o *options) Run(cmd *cobra.Command, args []string) error {
+	if o.output != "" && o.output != common.OutputFormatJSON {
+		return common.ErrOutputFormatNotJSON
+	}
+
+	from, to := policy.PolicyRef, policy.PolicyStagingRef
+	if len(args) > 0 {
+		from = args[0]
+	}
+	if len(args) > 1 {
+		to = args[1]
+	}
+
+	repo, err := gittuf.LoadRepository(".")
+	if err != nil {
+		return err
+	}
+
+	diff, err := repo.DiffPolicy(cmd.Context(), from, to)
+	if err != nil {
+		return err
+	}
+
+	if o.output == common.OutputFormatJSON {
+		diffJSON, err := json.MarshalIndent(diff, "", "  ")
+		if err != nil {
+			return err
+		}
+
+		fmt.Fprintln(cmd.OutOrStdout(), string(diffJSON))
+		return nil
+	}
+
+	fmt.Fprint(cmd.OutOrStdout(), diff.String())
+	return nil
+}
+
+func New() *cobra.Command {
+	o := &options{}
+	cmd := &cobra.Command{
+		Use:               "diff [from] [to]",
+		Short:             "Show the differences between two policy states",
+		Long:              `This command lists the semantic differences between two policy states, such as added and removed principals, changed rules and thresholds, and changes to global rules, hooks, and controller or network repositories. Each policy state is identified either by a policy reference ("policy" or "policy-staging") or by the ID of an RSL entry for one of those references. By default, the applied policy is compared with the staged policy.`,
+		Args:              cobra.MaximumNArgs(2),
+		RunE:              o.Run,
+		DisableAutoGenTag: true,
+	}
+	o.AddFlags(cmd)
+
+	return cmd
+}
@@ -0,0 +1,78 @@
+// Copyright The gittuf Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package explain
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/gittuf/gittuf/experimental/gittuf"
+	"github.com/gittuf/gittuf/internal/cmd/common"
+	"github.com/spf13/cobra"
+)
+
+type options struct {
+	targetRef string
+	output    string
+}
+
+func (o *options) AddFlags(cmd *cobra.Command) {
+	cmd.Flags().StringVar(
+		&o.targetRef,
+		"target-ref",
+		"policy",
+		"specify which policy ref should be inspected",
+	)
+
+	cmd.Flags().StringVar(
+		&o.output,
+		"output",
+		"",
+		fmt.Sprintf("write the explanation to stdout in the specified format (%s)", common.OutputFormatJSON),
+	)
+}
+
+func (o *options) Run(cmd *cobra.Command, args []string) error {
+	if o.output != "" && o.output != common.OutputFormatJSON {
+		return common.ErrOutputFormatNotJSON
+	}
+
+	repo, err := gittuf.LoadRepository(".")
+	if err != nil {
+		return err
+	}
+
+	explanation, err := repo.ExplainPolicy(cmd.Context(), o.targetRef, args[0])
+	if err != nil {
+		return err
+	}
+
+	if o.output == common.OutputFormatJSON {
+		explanationJSON, err := json.MarshalIndent(explanation, "", "  ")
+		if err != nil {
+			return err
+		}
+
+		fmt.Fprintln(cmd.OutOrStdout(), string(explanationJSON))
+		return nil
+	}
+
+	fmt.Fprint(cmd.OutOrStdout(), explanation.String())
+	return nil
+}
+
+func New() *cobra.Command {
+	o := &options{}
+	cmd := &cobra.Command{
+		Use:               "explain <git:ref|file:path>",
+		Short:             "Show which rules apply to a Git reference or file",
+		Long:              `This command walks the policy's delegations for the specified namespace, which must be prefixed with "git:" for Git references or "file:" for files. It shows each rule visited and whether it matched, the rule files delegated to, where a terminating rule stopped the search of a rule file, the resulting verifiers with their principals and thresholds, and the global rules that apply to the namespace.`,
+		Args:              cobra.ExactArgs(1),
+		RunE:              o.Run,
+		DisableAutoGenTag: true,
+	}
+	o.AddFlags(cmd)
+
+	return cmd
+}
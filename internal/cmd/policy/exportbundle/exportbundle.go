@@ -0,0 +1,43 @@
+// Copyright The gittuf Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package exportbundle
+
+import (
+	"github.com/gittuf/gittuf/experimental/gittuf"
+	"github.com/gittuf/gittuf/internal/cmd/common"
+	"github.com/spf13/cobra"
+)
+
+type options struct{}
+
+func (o *options) AddFlags(_ *cobra.Command) {}
+
+func (o *options) Run(cmd *cobra.Command, args []string) error {
+	repo, err := gittuf.LoadRepository(".")
+	if err != nil {
+		return err
+	}
+
+	bundle, err := repo.ExportSigningBundle(cmd.Context())
+	if err != nil {
+		return err
+	}
+
+	return common.WriteSigningBundle(args[0], bundle)
+}
+
+func New() *cobra.Command {
+	o := &options{}
+	cmd := &cobra.Command{
+		Use:               "export-bundle <path>",
+		Short:             "Export the staged policy as a signing bundle",
+		Long:              `This command writes the envelopes of the root of trust and all rule files in the staged policy, along with the signatures they already have, to a portable signing bundle. The bundle can be signed without access to the repository using "gittuf trust sign --bundle" and "gittuf policy sign --bundle", and the signatures merged back into the staged policy using "gittuf policy import-signatures".`,
+		Args:              cobra.ExactArgs(1),
+		RunE:              o.Run,
+		DisableAutoGenTag: true,
+	}
+	o.AddFlags(cmd)
+
+	return cmd
+}
@@ -0,0 +1,87 @@
+// Copyright The gittuf Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package exportpolicy
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/gittuf/gittuf/experimental/gittuf"
+	"github.com/spf13/cobra"
+	"sigs.k8s.io/yaml"
+)
+
+const (
+	formatYAML = "yaml"
+	formatJSON = "json"
+)
+
+var ErrInvalidDocumentFormat = errors.New("invalid document format, must be 'yaml' or 'json'")
+
+type options struct {
+	targetRef string
+	format    string
+}
+
+func (o *options) AddFlags(cmd *cobra.Command) {
+	cmd.Flags().StringVar(
+		&o.targetRef,
+		"target-ref",
+		"policy",
+		"specify which policy ref or RSL entry for a policy ref should be exported",
+	)
+
+	cmd.Flags().StringVar(
+		&o.format,
+		"format",
+		formatYAML,
+		fmt.Sprintf("format of the policy document (%s, %s)", formatYAML, formatJSON),
+	)
+}
+
+func (o *options) Run(cmd *cobra.Command, _ []string) error {
+	if o.format != formatYAML && o.format != formatJSON {
+		return ErrInvalidDocumentFormat
+	}
+
+	repo, err := gittuf.LoadRepository(".")
+	if err != nil {
+		return err
+	}
+
+	document, err := repo.ExportPolicy(cmd.Context(), o.targetRef)
+	if err != nil {
+		return err
+	}
+
+	var documentBytes []byte
+	if o.format == formatJSON {
+		documentBytes, err = json.MarshalIndent(document, "", "  ")
+		documentBytes = append(documentBytes, '\n')
+	} else {
+		documentBytes, err = yaml.Marshal(document)
+	}
+	if err != nil {
+		return err
+	}
+
+	_, err = cmd.OutOrStdout().Write(documentBytes)
+	return err
+}
+
+func New() *cobra.Command {
+	o := &options{}
+	cmd := &cobra.Command{
+		Use:               "export",
+		Short:             "Export the policy as a declarative document",
+		Long:              `This command writes the root of trust and all rule files of the policy to stdout as a single YAML or JSON document, without signatures. Hooks, GitHub apps, propagation directives, and controller and network repositories are not included. The document can be reviewed, stored alongside other configuration, and staged using "gittuf policy import". By default, the applied policy is exported.`,
+		Args:              cobra.NoArgs,
+		RunE:              o.Run,
+		DisableAutoGenTag: true,
+	}
+	o.AddFlags(cmd)
+
+	return cmd
+}
@@ -0,0 +1,79 @@
+// Copyright The gittuf Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package importpolicy
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/gittuf/gittuf/experimental/gittuf"
+	trustpolicyopts "github.com/gittuf/gittuf/experimental/gittuf/options/trustpolicy"
+	"github.com/gittuf/gittuf/internal/cmd/common"
+	"github.com/gittuf/gittuf/internal/cmd/policy/persistent"
+	"github.com/gittuf/gittuf/internal/policy"
+	"github.com/spf13/cobra"
+	"sigs.k8s.io/yaml"
+)
+
+type options struct {
+	p *persistent.Options
+}
+
+func (o *options) AddFlags(_ *cobra.Command) {}
+
+func (o *options) Run(cmd *cobra.Command, args []string) error {
+	documentBytes, err := os.ReadFile(args[0])
+	if err != nil {
+		return err
+	}
+
+	// YAML is a superset of JSON, so both formats are decoded here
+	document := &policy.Document{}
+	if err := yaml.Unmarshal(documentBytes, document); err != nil {
+		return fmt.Errorf("%w: %w", policy.ErrInvalidDocument, err)
+	}
+
+	repo, err := gittuf.LoadRepository(".")
+	if err != nil {
+		return err
+	}
+
+	signer, err := gittuf.LoadSigner(repo, o.p.SigningKey)
+	if err != nil {
+		return err
+	}
+
+	opts := []trustpolicyopts.Option{}
+	if o.p.WithRSLEntry {
+		opts = append(opts, trustpolicyopts.WithRSLEntry())
+	}
+	changed, err := repo.ImportPolicy(cmd.Context(), signer, document, true, opts...)
+	if err != nil {
+		return err
+	}
+
+	if len(changed) == 0 {
+		fmt.Fprintln(cmd.OutOrStdout(), "No policy changes")
+		return nil
+	}
+	fmt.Fprintf(cmd.OutOrStdout(), "Staged changes to: %s\n", strings.Join(changed, ", "))
+	return nil
+}
+
+func New(persistent *persistent.Options) *cobra.Command {
+	o := &options{p: persistent}
+	cmd := &cobra.Command{
+		Use:               "import <document>",
+		Short:             "Stage the policy described by a declarative document",
+		Long:              `This command reads a policy document in YAML or JSON, such as one written by "gittuf policy export", and stages the changes needed for the policy to match it. Changes are made using the same operations as the individual policy and trust commands, so they are validated the same way. Each metadata file that changes is signed using the specified key; changes to the root of trust require a key trusted for it. Rule files that are not in the document are removed. Hooks, GitHub apps, propagation directives, and controller and network repositories are left unchanged.`,
+		Args:              cobra.ExactArgs(1),
+		PreRunE:           common.CheckForSigningKeyFlag,
+		RunE:              o.Run,
+		DisableAutoGenTag: true,
+	}
+	o.AddFlags(cmd)
+
+	return cmd
+}
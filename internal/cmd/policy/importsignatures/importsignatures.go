@@ -0,0 +1,68 @@
+// Copyright The gittuf Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package importsignatures
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/gittuf/gittuf/experimental/gittuf"
+	trustpolicyopts "github.com/gittuf/gittuf/experimental/gittuf/options/trustpolicy"
+	"github.com/gittuf/gittuf/internal/cmd/common"
+	"github.com/gittuf/gittuf/internal/cmd/policy/persistent"
+	"github.com/spf13/cobra"
+)
+
+type options struct {
+	p *persistent.Options
+}
+
+func (o *options) AddFlags(_ *cobra.Command) {}
+
+func (o *options) Run(cmd *cobra.Command, args []string) error {
+	bundle, err := common.ReadSigningBundle(args[0])
+	if err != nil {
+		return err
+	}
+
+	repo, err := gittuf.LoadRepository(".")
+	if err != nil {
+		return err
+	}
+
+	opts := []trustpolicyopts.Option{}
+	if o.p.WithRSLEntry {
+		opts = append(opts, trustpolicyopts.WithRSLEntry())
+	}
+	added, err := repo.ImportSignatures(cmd.Context(), bundle, true, opts...)
+	if err != nil {
+		return err
+	}
+
+	if len(added) == 0 {
+		fmt.Fprintln(cmd.OutOrStdout(), "No new signatures")
+		return nil
+	}
+	for _, name := range slices.Sorted(maps.Keys(added)) {
+		fmt.Fprintf(cmd.OutOrStdout(), "Added signatures to '%s' from: %s\n", name, strings.Join(added[name], ", "))
+	}
+	return nil
+}
+
+func New(persistent *persistent.Options) *cobra.Command {
+	o := &options{p: persistent}
+	cmd := &cobra.Command{
+		Use:               "import-signatures <path>",
+		Short:             "Add the signatures in a signing bundle to the staged policy",
+		Long:              `This command merges the signatures in a signing bundle created using "gittuf policy export-bundle" into the staged policy. The staged metadata must not have changed since the bundle was exported. Each signature must be verified by a principal trusted for the metadata it signs: root principals for the root of trust, primary rule file principals for the primary rule file, and any principal in the policy for other rule files.`,
+		Args:              cobra.ExactArgs(1),
+		RunE:              o.Run,
+		DisableAutoGenTag: true,
+	}
+	o.AddFlags(cmd)
+
+	return cmd
+}
@@ -0,0 +1,90 @@
+// Copyright The gittuf Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package lint
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/gittuf/gittuf/experimental/gittuf"
+	"github.com/gittuf/gittuf/internal/cmd/common"
+	"github.com/gittuf/gittuf/internal/policy"
+	"github.com/spf13/cobra"
+)
+
+var ErrPolicyHasLintErrors = errors.New("policy has lint errors")
+
+type options struct {
+	targetRef string
+	output    string
+}
+
+func (o *options) AddFlags(cmd *cobra.Command) {
+	cmd.Flags().StringVar(
+		&o.targetRef,
+		"target-ref",
+		"policy-staging",
+		"specify which policy ref or RSL entry for a policy ref should be inspected",
+	)
+
+	cmd.Flags().StringVar(
+		&o.output,
+		"output",
+		"",
+		fmt.Sprintf("write the findings to stdout in the specified format (%s)", common.OutputFormatJSON),
+	)
+}
+
+func (o *options) Run(cmd *cobra.Command, _ []string) error {
+	if o.output != "" && o.output != common.OutputFormatJSON {
+		return common.ErrOutputFormatNotJSON
+	}
+
+	repo, err := gittuf.LoadRepository(".")
+	if err != nil {
+		return err
+	}
+
+	findings, err := repo.LintPolicy(cmd.Context(), o.targetRef)
+	if err != nil {
+		return err
+	}
+
+	if o.output == common.OutputFormatJSON {
+		findingsJSON, err := json.MarshalIndent(findings, "", "  ")
+		if err != nil {
+			return err
+		}
+
+		fmt.Fprintln(cmd.OutOrStdout(), string(findingsJSON))
+	} else {
+		if len(findings) == 0 {
+			fmt.Fprintln(cmd.OutOrStdout(), "No problems found")
+		}
+		for _, finding := range findings {
+			fmt.Fprintln(cmd.OutOrStdout(), finding.String())
+		}
+	}
+
+	if policy.HasLintErrors(findings) {
+		return ErrPolicyHasLintErrors
+	}
+	return nil
+}
+
+func New() *cobra.Command {
+	o := &options{}
+	cmd := &cobra.Command{
+		Use:               "lint",
+		Short:             "Check the policy for rules that can't be met or never apply",
+		Long:              `This command analyzes the rules and global rules in the policy. It reports rules shadowed by prior terminating rules, rules in delegated rule files that can never be reached, thresholds that can't be met, principals that aren't trusted by any rule, terminating rules whose rule files don't exist, rule files that no rule delegates to, and global rules that require more approvals than the rules protecting the same namespaces trust. By default, the staged policy is checked. The command exits with an error if any problem found is an error rather than a warning.`,
+		Args:              cobra.NoArgs,
+		RunE:              o.Run,
+		DisableAutoGenTag: true,
+	}
+	o.AddFlags(cmd)
+
+	return cmd
+}
@@ -49,6 +49,14 @@ func (o *options) Run(cmd *cobra.Command, _ []string) error {
 	for _, principal := range principals {
 		fmt.Printf("Principal %s:\n", principal.ID())
 
+		if team, isTeam := principal.(tuf.Team); isTeam {
+			fmt.Printf(indentString+"Team Threshold: %d\n", team.GetThreshold())
+			fmt.Printf(indentString + "Members:\n")
+			for _, member := range team.GetMembers() {
+				fmt.Printf(strings.Repeat(indentString, 2)+"%s\n", member.ID())
+			}
+		}
+
 		fmt.Printf(indentString + "Keys:\n")
 		for _, key := range principal.Keys() {
 			fmt.Printf(strings.Repeat(indentString, 2)+"%s (%s)\n", key.KeyID, key.KeyType)
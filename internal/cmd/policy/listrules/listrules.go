@@ -5,9 +5,13 @@ package listrules
 
 import (
 	"fmt"
+	"maps"
+	"slices"
 	"strings"
+	"time"
 
 	"github.com/gittuf/gittuf/experimental/gittuf"
+	"github.com/gittuf/gittuf/internal/tuf"
 	"github.com/spf13/cobra"
 )
 
@@ -38,11 +42,12 @@ func (o *options) Run(cmd *cobra.Command, _ []string) error {
 	// Iterate through the rules, they are already in order, and the depth tells us how to indent.
 	// The order is a pre-order traversal of the delegation tree, so that the parent is always before the children.
 
+	now := time.Now()
 	for _, curRule := range rules {
-		fmt.Printf(strings.Repeat("    ", curRule.Depth)+"Rule %s:\n", curRule.Delegation.ID())
+		fmt.Printf(strings.Repeat("    ", curRule.Depth)+"Rule %s%s:\n", curRule.Delegation.ID(), validityStatus(curRule.Delegation.GetValidityWindow(), now))
 		gitpaths, filepaths := []string{}, []string{}
 		for _, path := range curRule.Delegation.GetProtectedNamespaces() {
-			if strings.HasPrefix(path, "git:") {
+			if strings.HasPrefix(strings.TrimPrefix(path, tuf.NegatedPatternPrefix), "git:") {
 				gitpaths = append(gitpaths, path)
 			} else {
 				filepaths = append(filepaths, path)
@@ -61,16 +66,53 @@ func (o *options) Run(cmd *cobra.Command, _ []string) error {
 			}
 		}
 
+		if window := curRule.Delegation.GetValidityWindow(); window != nil {
+			fmt.Println(strings.Repeat("    ", curRule.Depth+1) + fmt.Sprintf("Valid: %s", window.String()))
+		}
+
 		fmt.Println(strings.Repeat("    ", curRule.Depth+1) + "Authorized keys:")
 		for _, key := range curRule.Delegation.GetPrincipalIDs().Contents() {
-			fmt.Printf(strings.Repeat("    ", curRule.Depth+2)+"%s\n", key)
+			window := curRule.PrincipalValidity[key]
+			if window == nil {
+				fmt.Printf(strings.Repeat("    ", curRule.Depth+2)+"%s\n", key)
+				continue
+			}
+			fmt.Printf(strings.Repeat("    ", curRule.Depth+2)+"%s%s (valid %s)\n", key, validityStatus(window, now), window.String())
 		}
 
 		fmt.Println(strings.Repeat("    ", curRule.Depth+1) + fmt.Sprintf("Required valid signatures: %d", curRule.Delegation.GetThreshold()))
+
+		teamThresholds := curRule.Delegation.GetTeamThresholds()
+		if len(teamThresholds) > 0 {
+			fmt.Println(strings.Repeat("    ", curRule.Depth+1) + "Required approvals per team:")
+			for _, teamID := range slices.Sorted(maps.Keys(teamThresholds)) {
+				fmt.Printf(strings.Repeat("    ", curRule.Depth+2)+"%s: %d\n", teamID, teamThresholds[teamID])
+			}
+		}
+
+		if actions := curRule.Delegation.GetAllowedActions(); len(actions) > 0 {
+			fmt.Println(strings.Repeat("    ", curRule.Depth+1) + "Allowed actions:")
+			for _, action := range actions {
+				fmt.Printf(strings.Repeat("    ", curRule.Depth+2)+"%s\n", action)
+			}
+		}
 	}
 	return nil
 }
 
+// validityStatus returns a marker for entries whose validity window is not
+// active at the specified time.
+func validityStatus(window *tuf.ValidityWindow, at time.Time) string {
+	switch {
+	case window.HasExpiredAt(at):
+		return " (expired)"
+	case window.IsPendingAt(at):
+		return " (not yet valid)"
+	default:
+		return ""
+	}
+}
+
 func New() *cobra.Command {
 	o := &options{}
 	cmd := &cobra.Command{
@@ -7,7 +7,15 @@ import (
 	"github.com/gittuf/gittuf/internal/cmd/policy/addkey"
 	"github.com/gittuf/gittuf/internal/cmd/policy/addperson"
 	"github.com/gittuf/gittuf/internal/cmd/policy/addrule"
+	"github.com/gittuf/gittuf/internal/cmd/policy/addteam"
+	"github.com/gittuf/gittuf/internal/cmd/policy/diff"
+	"github.com/gittuf/gittuf/internal/cmd/policy/explain"
+	"github.com/gittuf/gittuf/internal/cmd/policy/exportbundle"
+	"github.com/gittuf/gittuf/internal/cmd/policy/exportpolicy"
+	"github.com/gittuf/gittuf/internal/cmd/policy/importpolicy"
+	"github.com/gittuf/gittuf/internal/cmd/policy/importsignatures"
 	i "github.com/gittuf/gittuf/internal/cmd/policy/init"
+	"github.com/gittuf/gittuf/internal/cmd/policy/lint"
 	"github.com/gittuf/gittuf/internal/cmd/policy/listprincipals"
 	"github.com/gittuf/gittuf/internal/cmd/policy/listrules"
 	"github.com/gittuf/gittuf/internal/cmd/policy/persistent"
@@ -15,9 +23,16 @@ import (
 	"github.com/gittuf/gittuf/internal/cmd/policy/removeperson"
 	"github.com/gittuf/gittuf/internal/cmd/policy/removerule"
 	"github.com/gittuf/gittuf/internal/cmd/policy/reorderrules"
+	"github.com/gittuf/gittuf/internal/cmd/policy/rotatekey"
 	"github.com/gittuf/gittuf/internal/cmd/policy/sign"
+	"github.com/gittuf/gittuf/internal/cmd/policy/status"
 	"github.com/gittuf/gittuf/internal/cmd/policy/tui"
+	"github.com/gittuf/gittuf/internal/cmd/policy/updateexpiry"
+	"github.com/gittuf/gittuf/internal/cmd/policy/updateprincipalvalidity"
 	"github.com/gittuf/gittuf/internal/cmd/policy/updaterule"
+	"github.com/gittuf/gittuf/internal/cmd/policy/updateruleactions"
+	"github.com/gittuf/gittuf/internal/cmd/policy/updaterulevalidity"
+	"github.com/gittuf/gittuf/internal/cmd/policy/updateteamthreshold"
 	"github.com/gittuf/gittuf/internal/cmd/trustpolicy/apply"
 	"github.com/gittuf/gittuf/internal/cmd/trustpolicy/discard"
 	"github.com/gittuf/gittuf/internal/cmd/trustpolicy/remote"
@@ -37,9 +52,17 @@ func New() *cobra.Command {
 	cmd.AddCommand(addkey.New(o))
 	cmd.AddCommand(addperson.New(o))
 	cmd.AddCommand(addrule.New(o))
+	cmd.AddCommand(addteam.New(o))
 	cmd.AddCommand(apply.New())
+	cmd.AddCommand(diff.New())
 	cmd.AddCommand(discard.New())
+	cmd.AddCommand(explain.New())
+	cmd.AddCommand(exportbundle.New())
+	cmd.AddCommand(exportpolicy.New())
+	cmd.AddCommand(importpolicy.New(o))
+	cmd.AddCommand(importsignatures.New(o))
 	cmd.AddCommand(i.New(o))
+	cmd.AddCommand(lint.New())
 	cmd.AddCommand(listprincipals.New())
 	cmd.AddCommand(listrules.New())
 	cmd.AddCommand(remote.New())
@@ -47,10 +70,17 @@ func New() *cobra.Command {
 	cmd.AddCommand(removeperson.New(o))
 	cmd.AddCommand(removerule.New(o))
 	cmd.AddCommand(reorderrules.New(o))
+	cmd.AddCommand(rotatekey.New(o))
 	cmd.AddCommand(sign.New(o))
 	cmd.AddCommand(stage.New())
+	cmd.AddCommand(status.New())
 	cmd.AddCommand(tui.New(o))
+	cmd.AddCommand(updateexpiry.New(o))
+	cmd.AddCommand(updateprincipalvalidity.New(o))
 	cmd.AddCommand(updaterule.New(o))
+	cmd.AddCommand(updateruleactions.New(o))
+	cmd.AddCommand(updaterulevalidity.New(o))
+	cmd.AddCommand(updateteamthreshold.New(o))
 
 	return cmd
 }
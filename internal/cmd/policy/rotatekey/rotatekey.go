@@ -0,0 +1,95 @@
+// Copyright The gittuf Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package rotatekey
+
+import (
+	"strings"
+	"time"
+
+	"github.com/gittuf/gittuf/experimental/gittuf"
+	trustpolicyopts "github.com/gittuf/gittuf/experimental/gittuf/options/trustpolicy"
+	"github.com/gittuf/gittuf/internal/cmd/common"
+	"github.com/gittuf/gittuf/internal/cmd/policy/persistent"
+	"github.com/gittuf/gittuf/internal/policy"
+	"github.com/spf13/cobra"
+)
+
+type options struct {
+	p           *persistent.Options
+	policyName  string
+	oldKeyID    string
+	newKey      string
+	gracePeriod time.Duration
+}
+
+func (o *options) AddFlags(cmd *cobra.Command) {
+	cmd.Flags().StringVar(
+		&o.policyName,
+		"policy-name",
+		policy.TargetsRoleName,
+		"name of policy file the person is in",
+	)
+
+	cmd.Flags().StringVar(
+		&o.oldKeyID,
+		"old",
+		"",
+		"ID of the person's key to be replaced",
+	)
+	cmd.MarkFlagRequired("old") //nolint:errcheck
+
+	cmd.Flags().StringVar(
+		&o.newKey,
+		"new",
+		"",
+		"public key to replace the old key with",
+	)
+	cmd.MarkFlagRequired("new") //nolint:errcheck
+
+	cmd.Flags().DurationVar(
+		&o.gracePeriod,
+		"grace-period",
+		0,
+		"duration for which the old key continues to be trusted (e.g. 72h)",
+	)
+}
+
+func (o *options) Run(cmd *cobra.Command, args []string) error {
+	repo, err := gittuf.LoadRepository(".")
+	if err != nil {
+		return err
+	}
+
+	signer, err := gittuf.LoadSigner(repo, o.p.SigningKey)
+	if err != nil {
+		return err
+	}
+
+	newKey, err := gittuf.LoadPublicKey(o.newKey)
+	if err != nil {
+		return err
+	}
+
+	opts := []trustpolicyopts.Option{}
+	if o.p.WithRSLEntry {
+		opts = append(opts, trustpolicyopts.WithRSLEntry())
+	}
+	return repo.RotatePersonKey(cmd.Context(), signer, o.policyName, args[0], strings.ToLower(o.oldKeyID), newKey, o.gracePeriod, true, opts...)
+}
+
+func New(persistent *persistent.Options) *cobra.Command {
+	o := &options{p: persistent}
+	cmd := &cobra.Command{
+		Use:               "rotate-key <person>",
+		Short:             "Replace a key of a person in a policy file",
+		Long:              `This command replaces one of the keys of the specified person in a policy file with a new key. If a grace period is set, the old key continues to be trusted for the person until the grace period ends, so that changes already signed using the old key, such as commits in open pull requests, can still be verified. The grace period is checked against when the RSL entry being verified was recorded. By default, the main policy file is selected.`,
+		Args:              cobra.ExactArgs(1),
+		PreRunE:           common.CheckForSigningKeyFlag,
+		RunE:              o.Run,
+		DisableAutoGenTag: true,
+	}
+	o.AddFlags(cmd)
+
+	return cmd
+}
@@ -15,6 +15,7 @@ import (
 type options struct {
 	p          *persistent.Options
 	policyName string
+	bundle     string
 }
 
 func (o *options) AddFlags(cmd *cobra.Command) {
@@ -24,9 +25,20 @@ func (o *options) AddFlags(cmd *cobra.Command) {
 		policy.TargetsRoleName,
 		"name of policy file to sign",
 	)
+
+	cmd.Flags().StringVar(
+		&o.bundle,
+		"bundle",
+		"",
+		"sign the policy file in the specified signing bundle instead of the staged policy; the repository is not required",
+	)
 }
 
 func (o *options) Run(cmd *cobra.Command, _ []string) error {
+	if o.bundle != "" {
+		return o.signBundle(cmd)
+	}
+
 	repo, err := gittuf.LoadRepository(".")
 	if err != nil {
 		return err
@@ -44,12 +56,37 @@ func (o *options) Run(cmd *cobra.Command, _ []string) error {
 	return repo.SignTargets(cmd.Context(), signer, o.policyName, true, opts...)
 }
 
+func (o *options) signBundle(cmd *cobra.Command) error {
+	bundle, err := common.ReadSigningBundle(o.bundle)
+	if err != nil {
+		return err
+	}
+
+	// The bundle may be signed outside the repository, in which case the
+	// signer is loaded without the repository's configuration
+	repo, err := gittuf.LoadRepository(".")
+	if err != nil {
+		repo = nil
+	}
+
+	signer, err := gittuf.LoadSigner(repo, o.p.SigningKey)
+	if err != nil {
+		return err
+	}
+
+	if err := bundle.Sign(cmd.Context(), signer, o.policyName); err != nil {
+		return err
+	}
+
+	return common.WriteSigningBundle(o.bundle, bundle)
+}
+
 func New(persistent *persistent.Options) *cobra.Command {
 	o := &options{p: persistent}
 	cmd := &cobra.Command{
 		Use:               "sign",
 		Short:             "Sign policy file",
-		Long:              "This command allows users to add their signature to the specified policy file.",
+		Long:              `This command allows users to add their signature to the specified policy file. With "--bundle", the signature is added to the policy file in a signing bundle created using "gittuf policy export-bundle" instead, which does not require access to the repository.`,
 		PreRunE:           common.CheckForSigningKeyFlag,
 		RunE:              o.Run,
 		DisableAutoGenTag: true,
@@ -0,0 +1,70 @@
+// Copyright The gittuf Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package status
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/gittuf/gittuf/experimental/gittuf"
+	"github.com/gittuf/gittuf/internal/cmd/common"
+	"github.com/spf13/cobra"
+)
+
+type options struct {
+	output string
+}
+
+func (o *options) AddFlags(cmd *cobra.Command) {
+	cmd.Flags().StringVar(
+		&o.output,
+		"output",
+		"",
+		fmt.Sprintf("write the status to stdout in the specified format (%s)", common.OutputFormatJSON),
+	)
+}
+
+func (o *options) Run(cmd *cobra.Command, _ []string) error {
+	if o.output != "" && o.output != common.OutputFormatJSON {
+		return common.ErrOutputFormatNotJSON
+	}
+
+	repo, err := gittuf.LoadRepository(".")
+	if err != nil {
+		return err
+	}
+
+	status, err := repo.GetPolicyStatus(cmd.Context())
+	if err != nil {
+		return err
+	}
+
+	if o.output == common.OutputFormatJSON {
+		statusJSON, err := json.MarshalIndent(status, "", "  ")
+		if err != nil {
+			return err
+		}
+
+		fmt.Fprintln(cmd.OutOrStdout(), string(statusJSON))
+		return nil
+	}
+
+	fmt.Fprint(cmd.OutOrStdout(), status.String())
+	return nil
+}
+
+func New() *cobra.Command {
+	o := &options{}
+	cmd := &cobra.Command{
+		Use:               "status",
+		Short:             "Show the signatures collected for the staged policy",
+		Long:              `This command lists the root of trust and rule files that differ between the staged policy and the applied policy. For each one, it shows the principals trusted to sign it, the threshold of signatures required, the principals who have signed, and the principals who still have to sign. A changed root of trust must be signed both by the root principals it declares and by the root principals of the applied policy. The command also reports whether the staged policy can be applied right now.`,
+		Args:              cobra.NoArgs,
+		RunE:              o.Run,
+		DisableAutoGenTag: true,
+	}
+	o.AddFlags(cmd)
+
+	return cmd
+}
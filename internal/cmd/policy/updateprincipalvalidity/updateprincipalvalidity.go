@@ -0,0 +1,95 @@
+// Copyright The gittuf Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package updateprincipalvalidity
+
+import (
+	"github.com/gittuf/gittuf/experimental/gittuf"
+	trustpolicyopts "github.com/gittuf/gittuf/experimental/gittuf/options/trustpolicy"
+	"github.com/gittuf/gittuf/internal/cmd/common"
+	"github.com/gittuf/gittuf/internal/cmd/policy/persistent"
+	"github.com/gittuf/gittuf/internal/policy"
+	"github.com/spf13/cobra"
+)
+
+type options struct {
+	p           *persistent.Options
+	policyName  string
+	principalID string
+	notBefore   string
+	notAfter    string
+}
+
+func (o *options) AddFlags(cmd *cobra.Command) {
+	cmd.Flags().StringVar(
+		&o.policyName,
+		"policy-name",
+		policy.TargetsRoleName,
+		"name of policy file containing the principal",
+	)
+
+	cmd.Flags().StringVar(
+		&o.principalID,
+		"principal-ID",
+		"",
+		"principal ID",
+	)
+	cmd.MarkFlagRequired("principal-ID") //nolint:errcheck
+
+	cmd.Flags().StringVar(
+		&o.notBefore,
+		"not-before",
+		"",
+		"time from which the principal is trusted, as an RFC 3339 timestamp or a date in the YYYY-MM-DD format",
+	)
+
+	cmd.Flags().StringVar(
+		&o.notAfter,
+		"not-after",
+		"",
+		"time after which the principal is no longer trusted, as an RFC 3339 timestamp or a date in the YYYY-MM-DD format",
+	)
+}
+
+func (o *options) Run(cmd *cobra.Command, _ []string) error {
+	notBefore, err := common.ParseTimestamp(o.notBefore)
+	if err != nil {
+		return err
+	}
+
+	notAfter, err := common.ParseTimestamp(o.notAfter)
+	if err != nil {
+		return err
+	}
+
+	repo, err := gittuf.LoadRepository(".")
+	if err != nil {
+		return err
+	}
+
+	signer, err := gittuf.LoadSigner(repo, o.p.SigningKey)
+	if err != nil {
+		return err
+	}
+
+	opts := []trustpolicyopts.Option{}
+	if o.p.WithRSLEntry {
+		opts = append(opts, trustpolicyopts.WithRSLEntry())
+	}
+	return repo.UpdatePrincipalValidityWindow(cmd.Context(), signer, o.policyName, o.principalID, notBefore, notAfter, true, opts...)
+}
+
+func New(persistent *persistent.Options) *cobra.Command {
+	o := &options{p: persistent}
+	cmd := &cobra.Command{
+		Use:               "update-principal-validity",
+		Short:             "Update the validity window of a principal",
+		Long:              `This command allows users to limit the trust placed in a principal (a key or a person) declared in a policy file to a validity window using the "--not-before" and "--not-after" flags. Outside its window, the principal's signatures are not counted by any rule in the policy file or the policy files it delegates to when verifying RSL entries, using the time each entry was recorded. If neither flag is specified, the validity window is removed and the principal is always trusted. By default, the main policy file is selected.`,
+		PreRunE:           common.CheckForSigningKeyFlag,
+		RunE:              o.Run,
+		DisableAutoGenTag: true,
+	}
+	o.AddFlags(cmd)
+
+	return cmd
+}
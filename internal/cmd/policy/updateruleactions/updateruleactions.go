@@ -0,0 +1,83 @@
+// Copyright The gittuf Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package updateruleactions
+
+import (
+	"github.com/gittuf/gittuf/experimental/gittuf"
+	trustpolicyopts "github.com/gittuf/gittuf/experimental/gittuf/options/trustpolicy"
+	"github.com/gittuf/gittuf/internal/cmd/common"
+	"github.com/gittuf/gittuf/internal/cmd/policy/persistent"
+	"github.com/gittuf/gittuf/internal/policy"
+	"github.com/gittuf/gittuf/internal/tuf"
+	"github.com/spf13/cobra"
+)
+
+type options struct {
+	p          *persistent.Options
+	policyName string
+	ruleName   string
+	actions    []string
+}
+
+func (o *options) AddFlags(cmd *cobra.Command) {
+	cmd.Flags().StringVar(
+		&o.policyName,
+		"policy-name",
+		policy.TargetsRoleName,
+		"name of policy file containing the rule",
+	)
+
+	cmd.Flags().StringVar(
+		&o.ruleName,
+		"rule-name",
+		"",
+		"name of rule",
+	)
+	cmd.MarkFlagRequired("rule-name") //nolint:errcheck
+
+	cmd.Flags().StringArrayVar(
+		&o.actions,
+		"action",
+		[]string{},
+		"action the rule applies to (create, fast-forward, non-fast-forward, delete, annotate), can be specified multiple times",
+	)
+}
+
+func (o *options) Run(cmd *cobra.Command, _ []string) error {
+	actions, err := tuf.ParseRefActions(o.actions)
+	if err != nil {
+		return err
+	}
+
+	repo, err := gittuf.LoadRepository(".")
+	if err != nil {
+		return err
+	}
+
+	signer, err := gittuf.LoadSigner(repo, o.p.SigningKey)
+	if err != nil {
+		return err
+	}
+
+	opts := []trustpolicyopts.Option{}
+	if o.p.WithRSLEntry {
+		opts = append(opts, trustpolicyopts.WithRSLEntry())
+	}
+	return repo.UpdateRuleActions(cmd.Context(), signer, o.policyName, o.ruleName, actions, true, opts...)
+}
+
+func New(persistent *persistent.Options) *cobra.Command {
+	o := &options{p: persistent}
+	cmd := &cobra.Command{
+		Use:               "update-rule-actions",
+		Short:             "Update the reference actions a rule applies to",
+		Long:              `This command allows users to limit a rule protecting Git references to specific actions: "create", "fast-forward", "non-fast-forward", "delete", and "annotate". For example, a rule that only applies to "delete" can require a different set of principals to delete a branch than to push to it. If no actions are specified, the rule applies to all actions except "annotate". Rules that list "annotate" control who may annotate RSL entries for the matching references: entries are only considered skipped when their skip annotations are signed by a threshold of the principals trusted by one of these rules. By default, the main policy file is selected.`,
+		PreRunE:           common.CheckForSigningKeyFlag,
+		RunE:              o.Run,
+		DisableAutoGenTag: true,
+	}
+	o.AddFlags(cmd)
+
+	return cmd
+}
@@ -0,0 +1,95 @@
+// Copyright The gittuf Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package updaterulevalidity
+
+import (
+	"github.com/gittuf/gittuf/experimental/gittuf"
+	trustpolicyopts "github.com/gittuf/gittuf/experimental/gittuf/options/trustpolicy"
+	"github.com/gittuf/gittuf/internal/cmd/common"
+	"github.com/gittuf/gittuf/internal/cmd/policy/persistent"
+	"github.com/gittuf/gittuf/internal/policy"
+	"github.com/spf13/cobra"
+)
+
+type options struct {
+	p          *persistent.Options
+	policyName string
+	ruleName   string
+	notBefore  string
+	notAfter   string
+}
+
+func (o *options) AddFlags(cmd *cobra.Command) {
+	cmd.Flags().StringVar(
+		&o.policyName,
+		"policy-name",
+		policy.TargetsRoleName,
+		"name of policy file containing the rule",
+	)
+
+	cmd.Flags().StringVar(
+		&o.ruleName,
+		"rule-name",
+		"",
+		"name of rule",
+	)
+	cmd.MarkFlagRequired("rule-name") //nolint:errcheck
+
+	cmd.Flags().StringVar(
+		&o.notBefore,
+		"not-before",
+		"",
+		"time from which the rule applies, as an RFC 3339 timestamp or a date in the YYYY-MM-DD format",
+	)
+
+	cmd.Flags().StringVar(
+		&o.notAfter,
+		"not-after",
+		"",
+		"time after which the rule no longer applies, as an RFC 3339 timestamp or a date in the YYYY-MM-DD format",
+	)
+}
+
+func (o *options) Run(cmd *cobra.Command, _ []string) error {
+	notBefore, err := common.ParseTimestamp(o.notBefore)
+	if err != nil {
+		return err
+	}
+
+	notAfter, err := common.ParseTimestamp(o.notAfter)
+	if err != nil {
+		return err
+	}
+
+	repo, err := gittuf.LoadRepository(".")
+	if err != nil {
+		return err
+	}
+
+	signer, err := gittuf.LoadSigner(repo, o.p.SigningKey)
+	if err != nil {
+		return err
+	}
+
+	opts := []trustpolicyopts.Option{}
+	if o.p.WithRSLEntry {
+		opts = append(opts, trustpolicyopts.WithRSLEntry())
+	}
+	return repo.UpdateRuleValidityWindow(cmd.Context(), signer, o.policyName, o.ruleName, notBefore, notAfter, true, opts...)
+}
+
+func New(persistent *persistent.Options) *cobra.Command {
+	o := &options{p: persistent}
+	cmd := &cobra.Command{
+		Use:               "update-rule-validity",
+		Short:             "Update the validity window of a rule",
+		Long:              `This command allows users to limit a rule to a validity window using the "--not-before" and "--not-after" flags. Outside its window, a rule is ignored when verifying RSL entries, using the time each entry was recorded. This is useful to grant temporary access to a namespace without having to remember to remove the rule afterwards. If neither flag is specified, the validity window is removed and the rule always applies. By default, the main policy file is selected.`,
+		PreRunE:           common.CheckForSigningKeyFlag,
+		RunE:              o.Run,
+		DisableAutoGenTag: true,
+	}
+	o.AddFlags(cmd)
+
+	return cmd
+}
@@ -0,0 +1,72 @@
+// Copyright The gittuf Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package checkpoint
+
+import (
+	"fmt"
+
+	"github.com/gittuf/gittuf/experimental/gittuf"
+	rslopts "github.com/gittuf/gittuf/experimental/gittuf/options/rsl"
+	"github.com/spf13/cobra"
+)
+
+type options struct {
+	remoteName string
+	localOnly  bool
+}
+
+func (o *options) AddFlags(cmd *cobra.Command) {
+	cmd.Flags().StringVar(
+		&o.remoteName,
+		"remote-name",
+		"",
+		"remote name",
+	)
+
+	cmd.Flags().BoolVar(
+		&o.localOnly,
+		"local-only",
+		false,
+		"local only",
+	)
+
+	cmd.MarkFlagsOneRequired("remote-name", "local-only")
+	cmd.MarkFlagsMutuallyExclusive("remote-name", "local-only")
+}
+
+func (o *options) Run(cmd *cobra.Command, _ []string) error {
+	repo, err := gittuf.LoadRepository(".")
+	if err != nil {
+		return err
+	}
+
+	opts := []rslopts.CheckpointOption{rslopts.WithCheckpointRemote(o.remoteName)}
+	if o.localOnly {
+		opts = append(opts, rslopts.WithCheckpointLocalOnly())
+	}
+
+	results, err := repo.RecordRSLCheckpoint(cmd.Context(), true, opts...)
+	for _, result := range results {
+		if result.Err != nil {
+			fmt.Fprintf(cmd.ErrOrStderr(), "%s: not recorded in checkpoint, verification failed: %s\n", result.Ref, result.Err.Error())
+		}
+	}
+
+	return err
+}
+
+func New() *cobra.Command {
+	o := &options{}
+	cmd := &cobra.Command{
+		Use:               "checkpoint",
+		Short:             "Record a checkpoint of the verified state of the repository in the RSL",
+		Long:              `The 'checkpoint' command verifies every reference recorded in the RSL and records an RSL checkpoint entry with the tip of each reference that passed verification and the policy in effect. Verifiers using 'gittuf verify-ref --from-checkpoint' begin verification from the latest checkpoint approved by a threshold of root principals. The signature on the checkpoint entry counts as one approval, and other root principals approve the checkpoint using 'gittuf rsl annotate <checkpoint-entry-id>'.`,
+		Args:              cobra.NoArgs,
+		RunE:              o.Run,
+		DisableAutoGenTag: true,
+	}
+	o.AddFlags(cmd)
+
+	return cmd
+}
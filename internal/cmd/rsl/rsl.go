@@ -5,6 +5,7 @@ package rsl
 
 import (
 	"github.com/gittuf/gittuf/internal/cmd/rsl/annotate"
+	"github.com/gittuf/gittuf/internal/cmd/rsl/checkpoint"
 	"github.com/gittuf/gittuf/internal/cmd/rsl/log"
 	"github.com/gittuf/gittuf/internal/cmd/rsl/propagate"
 	"github.com/gittuf/gittuf/internal/cmd/rsl/record"
@@ -21,6 +22,7 @@ func New() *cobra.Command {
 	}
 
 	cmd.AddCommand(annotate.New())
+	cmd.AddCommand(checkpoint.New())
 	cmd.AddCommand(log.New())
 	cmd.AddCommand(propagate.New())
 	cmd.AddCommand(record.New())
@@ -43,10 +43,19 @@ func (o *options) Run(cmd *cobra.Command, _ []string) error {
 
 	thresholdRules := []tuf.GlobalRuleThreshold{}
 	blockForcePushesRules := []tuf.GlobalRuleBlockForcePushes{}
+	requireSignedCommitsRules := []tuf.GlobalRuleRequireSignedCommits{}
+	mergeStrategyRules := []tuf.GlobalRuleMergeStrategy{}
+	requireHookExecutionRules := []tuf.GlobalRuleRequireHookExecution{}
 	for _, curRule := range rules {
 		switch globalRule := curRule.(type) {
 		case tuf.GlobalRuleThreshold:
 			thresholdRules = append(thresholdRules, globalRule)
+		case tuf.GlobalRuleRequireSignedCommits:
+			requireSignedCommitsRules = append(requireSignedCommitsRules, globalRule)
+		case tuf.GlobalRuleMergeStrategy:
+			mergeStrategyRules = append(mergeStrategyRules, globalRule)
+		case tuf.GlobalRuleRequireHookExecution:
+			requireHookExecutionRules = append(requireHookExecutionRules, globalRule)
 		case tuf.GlobalRuleBlockForcePushes:
 			blockForcePushesRules = append(blockForcePushesRules, globalRule)
 		}
@@ -65,6 +74,26 @@ func (o *options) Run(cmd *cobra.Command, _ []string) error {
 		printNamespaces(curRule.GetProtectedNamespaces())
 	}
 
+	for _, curRule := range requireSignedCommitsRules {
+		fmt.Printf("Global Rule: %v\n", curRule.GetName())
+		fmt.Println(indentString + "Type: " + tuf.GlobalRuleRequireSignedCommitsType)
+		printNamespaces(curRule.GetProtectedNamespaces())
+	}
+
+	for _, curRule := range mergeStrategyRules {
+		fmt.Printf("Global Rule: %v\n", curRule.GetName())
+		fmt.Println(indentString + "Type: " + tuf.GlobalRuleMergeStrategyType)
+		printNamespaces(curRule.GetProtectedNamespaces())
+		fmt.Println(indentString + "Strategy: " + curRule.GetMergeStrategy())
+	}
+
+	for _, curRule := range requireHookExecutionRules {
+		fmt.Printf("Global Rule: %v\n", curRule.GetName())
+		fmt.Println(indentString + "Type: " + tuf.GlobalRuleRequireHookExecutionType)
+		printNamespaces(curRule.GetProtectedNamespaces())
+		fmt.Println(indentString + "Hooks: " + strings.Join(curRule.GetHookNames(), ", "))
+	}
+
 	return nil
 }
 
@@ -84,7 +113,7 @@ func New() *cobra.Command {
 func printNamespaces(namespaces []string) {
 	gitpaths, filepaths := []string{}, []string{}
 	for _, path := range namespaces {
-		if strings.HasPrefix(path, "git:") {
+		if strings.HasPrefix(strings.TrimPrefix(path, tuf.NegatedPatternPrefix), "git:") {
 			gitpaths = append(gitpaths, path)
 		} else {
 			filepaths = append(filepaths, path)
@@ -0,0 +1,122 @@
+// Copyright The gittuf Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package revokekey
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/gittuf/gittuf/experimental/gittuf"
+	trustpolicyopts "github.com/gittuf/gittuf/experimental/gittuf/options/trustpolicy"
+	"github.com/gittuf/gittuf/internal/cmd/common"
+	"github.com/gittuf/gittuf/internal/cmd/trust/persistent"
+	"github.com/spf13/cobra"
+)
+
+type options struct {
+	p            *persistent.Options
+	key          string
+	afterEntryID string
+	after        string
+}
+
+func (o *options) AddFlags(cmd *cobra.Command) {
+	cmd.Flags().StringVar(
+		&o.key,
+		"key",
+		"",
+		"public key to revoke",
+	)
+	cmd.MarkFlagRequired("key") //nolint:errcheck
+
+	cmd.Flags().StringVar(
+		&o.afterEntryID,
+		"after-entry",
+		"",
+		"ID of RSL entry after which signatures made using the key are invalid",
+	)
+
+	cmd.Flags().StringVar(
+		&o.after,
+		"after",
+		"",
+		"RFC 3339 timestamp after which signatures made using the key are invalid",
+	)
+
+	cmd.MarkFlagsOneRequired("after-entry", "after")
+	cmd.MarkFlagsMutuallyExclusive("after-entry", "after")
+}
+
+func (o *options) Run(cmd *cobra.Command, _ []string) error {
+	var revokedAfter time.Time
+	if o.after != "" {
+		var err error
+		revokedAfter, err = time.Parse(time.RFC3339, o.after)
+		if err != nil {
+			return fmt.Errorf("invalid timestamp '%s': %w", o.after, err)
+		}
+	}
+
+	repo, err := gittuf.LoadRepository(".")
+	if err != nil {
+		return err
+	}
+
+	signer, err := gittuf.LoadSigner(repo, o.p.SigningKey)
+	if err != nil {
+		return err
+	}
+
+	key, err := gittuf.LoadPublicKey(o.key)
+	if err != nil {
+		return err
+	}
+
+	opts := []trustpolicyopts.Option{}
+	if o.p.WithRSLEntry {
+		opts = append(opts, trustpolicyopts.WithRSLEntry())
+	}
+	if err := repo.RevokeKey(cmd.Context(), signer, key, o.afterEntryID, revokedAfter, true, opts...); err != nil {
+		return err
+	}
+
+	affectedEntries, err := repo.GetEntriesAffectedByKeyRevocations(cmd.Context())
+	if err != nil {
+		return err
+	}
+
+	if len(affectedEntries) == 0 {
+		fmt.Fprintln(cmd.OutOrStdout(), "No RSL entries were signed using revoked keys after they were revoked")
+		return nil
+	}
+
+	fmt.Fprintln(cmd.OutOrStdout(), "RSL entries signed using revoked keys after they were revoked:")
+	for _, entry := range affectedEntries {
+		signed := "entry"
+		switch {
+		case entry.SignedEntry && entry.SignedTarget:
+			signed = "entry and target"
+		case entry.SignedTarget:
+			signed = "target"
+		}
+		fmt.Fprintf(cmd.OutOrStdout(), "    %s (%s): %s signed using key '%s'\n", entry.EntryID, entry.Ref, signed, entry.KeyID)
+	}
+
+	return nil
+}
+
+func New(persistent *persistent.Options) *cobra.Command {
+	o := &options{p: persistent}
+	cmd := &cobra.Command{
+		Use:               "revoke-key",
+		Short:             "Revoke a compromised key as of a point in the RSL",
+		Long:              `This command records that a key is compromised. Signatures made using the key after the specified RSL entry, or after the specified time, are not trusted, even when verifying RSL entries using policy states from before the key was revoked. After the revocation is recorded, the RSL entries signed using revoked keys after they were revoked are reported, as these entries will fail verification.`,
+		PreRunE:           common.CheckForSigningKeyFlag,
+		RunE:              o.Run,
+		DisableAutoGenTag: true,
+	}
+	o.AddFlags(cmd)
+
+	return cmd
+}
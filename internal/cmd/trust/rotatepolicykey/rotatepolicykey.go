@@ -0,0 +1,85 @@
+// Copyright The gittuf Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package rotatepolicykey
+
+import (
+	"strings"
+	"time"
+
+	"github.com/gittuf/gittuf/experimental/gittuf"
+	trustpolicyopts "github.com/gittuf/gittuf/experimental/gittuf/options/trustpolicy"
+	"github.com/gittuf/gittuf/internal/cmd/common"
+	"github.com/gittuf/gittuf/internal/cmd/trust/persistent"
+	"github.com/spf13/cobra"
+)
+
+type options struct {
+	p           *persistent.Options
+	oldKeyID    string
+	newKey      string
+	gracePeriod time.Duration
+}
+
+func (o *options) AddFlags(cmd *cobra.Command) {
+	cmd.Flags().StringVar(
+		&o.oldKeyID,
+		"old",
+		"",
+		"ID of Policy key to be replaced",
+	)
+	cmd.MarkFlagRequired("old") //nolint:errcheck
+
+	cmd.Flags().StringVar(
+		&o.newKey,
+		"new",
+		"",
+		"public key to replace the old Policy key with",
+	)
+	cmd.MarkFlagRequired("new") //nolint:errcheck
+
+	cmd.Flags().DurationVar(
+		&o.gracePeriod,
+		"grace-period",
+		0,
+		"duration for which the old key continues to be trusted (e.g. 72h)",
+	)
+}
+
+func (o *options) Run(cmd *cobra.Command, _ []string) error {
+	repo, err := gittuf.LoadRepository(".")
+	if err != nil {
+		return err
+	}
+
+	signer, err := gittuf.LoadSigner(repo, o.p.SigningKey)
+	if err != nil {
+		return err
+	}
+
+	newKey, err := gittuf.LoadPublicKey(o.newKey)
+	if err != nil {
+		return err
+	}
+
+	opts := []trustpolicyopts.Option{}
+	if o.p.WithRSLEntry {
+		opts = append(opts, trustpolicyopts.WithRSLEntry())
+	}
+	return repo.RotateTopLevelTargetsKey(cmd.Context(), signer, strings.ToLower(o.oldKeyID), newKey, o.gracePeriod, true, opts...)
+}
+
+func New(persistent *persistent.Options) *cobra.Command {
+	o := &options{p: persistent}
+	cmd := &cobra.Command{
+		Use:               "rotate-policy-key",
+		Short:             "Replace a Policy key in gittuf root of trust",
+		Long:              `This command replaces a key trusted for the primary policy file with a new key. If the key belongs to a person, the person's key is replaced. If a grace period is set, the old key continues to be trusted until the grace period ends, so that metadata already signed using the old key can still be verified. The grace period is checked against when the RSL entry being verified was recorded.`,
+		PreRunE:           common.CheckForSigningKeyFlag,
+		RunE:              o.Run,
+		DisableAutoGenTag: true,
+	}
+	o.AddFlags(cmd)
+
+	return cmd
+}
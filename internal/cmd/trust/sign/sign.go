@@ -8,16 +8,29 @@ import (
 	trustpolicyopts "github.com/gittuf/gittuf/experimental/gittuf/options/trustpolicy"
 	"github.com/gittuf/gittuf/internal/cmd/common"
 	"github.com/gittuf/gittuf/internal/cmd/trust/persistent"
+	"github.com/gittuf/gittuf/internal/policy"
 	"github.com/spf13/cobra"
 )
 
 type options struct {
-	p *persistent.Options
+	p      *persistent.Options
+	bundle string
 }
 
-func (o *options) AddFlags(_ *cobra.Command) {}
+func (o *options) AddFlags(cmd *cobra.Command) {
+	cmd.Flags().StringVar(
+		&o.bundle,
+		"bundle",
+		"",
+		"sign the root of trust in the specified signing bundle instead of the staged policy; the repository is not required",
+	)
+}
 
 func (o *options) Run(cmd *cobra.Command, _ []string) error {
+	if o.bundle != "" {
+		return o.signBundle(cmd)
+	}
+
 	repo, err := gittuf.LoadRepository(".")
 	if err != nil {
 		return err
@@ -35,12 +48,37 @@ func (o *options) Run(cmd *cobra.Command, _ []string) error {
 	return repo.SignRoot(cmd.Context(), signer, true, opts...)
 }
 
+func (o *options) signBundle(cmd *cobra.Command) error {
+	bundle, err := common.ReadSigningBundle(o.bundle)
+	if err != nil {
+		return err
+	}
+
+	// The bundle may be signed outside the repository, in which case the
+	// signer is loaded without the repository's configuration
+	repo, err := gittuf.LoadRepository(".")
+	if err != nil {
+		repo = nil
+	}
+
+	signer, err := gittuf.LoadSigner(repo, o.p.SigningKey)
+	if err != nil {
+		return err
+	}
+
+	if err := bundle.Sign(cmd.Context(), signer, policy.RootRoleName); err != nil {
+		return err
+	}
+
+	return common.WriteSigningBundle(o.bundle, bundle)
+}
+
 func New(persistent *persistent.Options) *cobra.Command {
 	o := &options{p: persistent}
 	cmd := &cobra.Command{
 		Use:               "sign",
 		Short:             "Sign root of trust",
-		Long:              "This command allows users to add their signature to the root of trust file.",
+		Long:              `This command allows users to add their signature to the root of trust file. With "--bundle", the signature is added to the root of trust in a signing bundle created using "gittuf policy export-bundle" instead, which does not require access to the repository.`,
 		PreRunE:           common.CheckForSigningKeyFlag,
 		RunE:              o.Run,
 		DisableAutoGenTag: true,
@@ -25,10 +25,14 @@ import (
 	"github.com/gittuf/gittuf/internal/cmd/trust/removepolicykey"
 	"github.com/gittuf/gittuf/internal/cmd/trust/removepropagationdirective"
 	"github.com/gittuf/gittuf/internal/cmd/trust/removerootkey"
+	"github.com/gittuf/gittuf/internal/cmd/trust/revokekey"
+	"github.com/gittuf/gittuf/internal/cmd/trust/rotatepolicykey"
+	"github.com/gittuf/gittuf/internal/cmd/trust/rotaterootkey"
 	"github.com/gittuf/gittuf/internal/cmd/trust/setrepositorylocation"
 	"github.com/gittuf/gittuf/internal/cmd/trust/sign"
 	"github.com/gittuf/gittuf/internal/cmd/trust/updateglobalrule"
 	"github.com/gittuf/gittuf/internal/cmd/trust/updatepolicythreshold"
+	"github.com/gittuf/gittuf/internal/cmd/trust/updaterootexpiry"
 	"github.com/gittuf/gittuf/internal/cmd/trust/updaterootthreshold"
 	"github.com/gittuf/gittuf/internal/cmd/trustpolicy/apply"
 	"github.com/gittuf/gittuf/internal/cmd/trustpolicy/remote"
@@ -66,11 +70,15 @@ func New() *cobra.Command {
 	cmd.AddCommand(removepolicykey.New(o))
 	cmd.AddCommand(removepropagationdirective.New(o))
 	cmd.AddCommand(removerootkey.New(o))
+	cmd.AddCommand(revokekey.New(o))
+	cmd.AddCommand(rotatepolicykey.New(o))
+	cmd.AddCommand(rotaterootkey.New(o))
 	cmd.AddCommand(setrepositorylocation.New(o))
 	cmd.AddCommand(sign.New(o))
 	cmd.AddCommand(stage.New())
 	cmd.AddCommand(updateglobalrule.New(o))
 	cmd.AddCommand(updatepolicythreshold.New(o))
+	cmd.AddCommand(updaterootexpiry.New(o))
 	cmd.AddCommand(updaterootthreshold.New(o))
 	cmd.AddCommand(listglobalrules.New())
 
@@ -16,11 +16,13 @@ import (
 )
 
 type options struct {
-	p            *persistent.Options
-	ruleName     string
-	ruleType     string
-	rulePatterns []string
-	threshold    int
+	p             *persistent.Options
+	ruleName      string
+	ruleType      string
+	rulePatterns  []string
+	threshold     int
+	mergeStrategy string
+	hookNames     []string
 }
 
 func (o *options) AddFlags(cmd *cobra.Command) {
@@ -36,7 +38,7 @@ func (o *options) AddFlags(cmd *cobra.Command) {
 		&o.ruleType,
 		"type",
 		"",
-		fmt.Sprintf("type of rule (%s|%s)", tuf.GlobalRuleThresholdType, tuf.GlobalRuleBlockForcePushesType),
+		fmt.Sprintf("type of rule (%s|%s|%s|%s|%s)", tuf.GlobalRuleThresholdType, tuf.GlobalRuleBlockForcePushesType, tuf.GlobalRuleRequireSignedCommitsType, tuf.GlobalRuleMergeStrategyType, tuf.GlobalRuleRequireHookExecutionType),
 	)
 	cmd.MarkFlagRequired("type") //nolint:errcheck
 
@@ -44,7 +46,7 @@ func (o *options) AddFlags(cmd *cobra.Command) {
 		&o.rulePatterns,
 		"rule-pattern",
 		[]string{},
-		"patterns used to identify namespaces rule applies to",
+		"patterns used to identify namespaces rule applies to (prefix a pattern with '!' to exclude the namespaces it matches)",
 	)
 
 	cmd.Flags().IntVar(
@@ -53,6 +55,20 @@ func (o *options) AddFlags(cmd *cobra.Command) {
 		1,
 		"threshold of required valid signatures",
 	)
+
+	cmd.Flags().StringVar(
+		&o.mergeStrategy,
+		"merge-strategy",
+		"",
+		fmt.Sprintf("merge strategy required by rule (%s|%s)", tuf.MergeStrategyLinearHistory, tuf.MergeStrategyMergeCommit),
+	)
+
+	cmd.Flags().StringArrayVar(
+		&o.hookNames,
+		"hook-name",
+		[]string{},
+		"name of hook that must have passed for updates to the namespaces rule applies to",
+	)
 }
 
 func (o *options) Run(cmd *cobra.Command, _ []string) error {
@@ -90,6 +106,33 @@ func (o *options) Run(cmd *cobra.Command, _ []string) error {
 
 		return repo.UpdateGlobalRuleBlockForcePushes(cmd.Context(), signer, o.ruleName, o.rulePatterns, true, opts...)
 
+	case tuf.GlobalRuleRequireSignedCommitsType:
+		if len(o.rulePatterns) == 0 {
+			return fmt.Errorf("required flag --rule-pattern not set for global rule type '%s'", tuf.GlobalRuleRequireSignedCommitsType)
+		}
+
+		return repo.UpdateGlobalRuleRequireSignedCommits(cmd.Context(), signer, o.ruleName, o.rulePatterns, true, opts...)
+
+	case tuf.GlobalRuleMergeStrategyType:
+		if len(o.rulePatterns) == 0 {
+			return fmt.Errorf("required flag --rule-pattern not set for global rule type '%s'", tuf.GlobalRuleMergeStrategyType)
+		}
+		if o.mergeStrategy == "" {
+			return fmt.Errorf("required flag --merge-strategy not set for global rule type '%s'", tuf.GlobalRuleMergeStrategyType)
+		}
+
+		return repo.UpdateGlobalRuleMergeStrategy(cmd.Context(), signer, o.ruleName, o.rulePatterns, o.mergeStrategy, true, opts...)
+
+	case tuf.GlobalRuleRequireHookExecutionType:
+		if len(o.rulePatterns) == 0 {
+			return fmt.Errorf("required flag --rule-pattern not set for global rule type '%s'", tuf.GlobalRuleRequireHookExecutionType)
+		}
+		if len(o.hookNames) == 0 {
+			return fmt.Errorf("required flag --hook-name not set for global rule type '%s'", tuf.GlobalRuleRequireHookExecutionType)
+		}
+
+		return repo.UpdateGlobalRuleRequireHookExecution(cmd.Context(), signer, o.ruleName, o.rulePatterns, o.hookNames, true, opts...)
+
 	default:
 		return tuf.ErrUnknownGlobalRuleType
 	}
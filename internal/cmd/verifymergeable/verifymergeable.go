@@ -4,8 +4,13 @@
 package verifymergeable
 
 import (
+	"errors"
+	"fmt"
+
 	"github.com/gittuf/gittuf/experimental/gittuf"
 	"github.com/gittuf/gittuf/experimental/gittuf/options/verifymergeable"
+	"github.com/gittuf/gittuf/internal/cmd/common"
+	"github.com/gittuf/gittuf/internal/policy"
 	"github.com/spf13/cobra"
 )
 
@@ -13,6 +18,7 @@ type options struct {
 	baseBranch    string
 	featureBranch string
 	bypassRSL     bool
+	output        string
 }
 
 func (o *options) AddFlags(cmd *cobra.Command) {
@@ -38,6 +44,13 @@ func (o *options) AddFlags(cmd *cobra.Command) {
 		false,
 		"bypass RSL when identifying current state of feature ref",
 	)
+
+	cmd.Flags().StringVar(
+		&o.output,
+		"output",
+		"",
+		fmt.Sprintf("write a report of the checks performed to stdout in the specified format (%s, %s)", common.OutputFormatJSON, common.OutputFormatSARIF),
+	)
 }
 
 func (o *options) Run(cmd *cobra.Command, _ []string) error {
@@ -51,7 +64,22 @@ func (o *options) Run(cmd *cobra.Command, _ []string) error {
 		opts = append(opts, verifymergeable.WithBypassRSLForFeatureRef())
 	}
 
+	var report *policy.VerificationReport
+	if o.output != "" {
+		report = policy.NewVerificationReport(o.baseBranch)
+		opts = append(opts, verifymergeable.WithReport(report))
+	}
+
 	_, err = repo.VerifyMergeable(cmd.Context(), o.baseBranch, o.featureBranch, opts...)
+
+	if report != nil {
+		// Verification may fail before any checks are recorded
+		report.SetOutcome(err)
+		if reportErr := common.WriteVerificationReport(cmd.OutOrStdout(), o.output, report); reportErr != nil {
+			return errors.Join(err, reportErr)
+		}
+	}
+
 	return err
 }
 
@@ -61,6 +89,7 @@ func New() *cobra.Command {
 		Use:               "verify-mergeable",
 		Short:             "Tools for verifying mergeability using gittuf policies",
 		Args:              cobra.ExactArgs(0),
+		PreRunE:           common.CheckOutputFormat,
 		RunE:              o.Run,
 		DisableAutoGenTag: true,
 	}
@@ -4,11 +4,15 @@
 package verifyref
 
 import (
+	"errors"
 	"fmt"
+	"runtime"
 
 	"github.com/gittuf/gittuf/experimental/gittuf"
 	verifyopts "github.com/gittuf/gittuf/experimental/gittuf/options/verify"
+	"github.com/gittuf/gittuf/internal/cmd/common"
 	"github.com/gittuf/gittuf/internal/dev"
+	"github.com/gittuf/gittuf/internal/policy"
 	"github.com/spf13/cobra"
 )
 
@@ -16,6 +20,10 @@ type options struct {
 	latestOnly    bool
 	fromEntry     string
 	remoteRefName string
+	output        string
+	all           bool
+	workers       int
+	useCheckpoint bool
 }
 
 func (o *options) AddFlags(cmd *cobra.Command) {
@@ -41,6 +49,40 @@ func (o *options) AddFlags(cmd *cobra.Command) {
 		"",
 		"name of remote reference, if it differs from the local name",
 	)
+
+	cmd.Flags().BoolVar(
+		&o.all,
+		"all",
+		false,
+		"verify all references recorded in the RSL in one pass, optionally filtered by the glob pattern specified in place of the reference",
+	)
+
+	cmd.MarkFlagsMutuallyExclusive("all", "from-entry")
+	cmd.MarkFlagsMutuallyExclusive("all", "remote-ref-name")
+
+	cmd.Flags().BoolVar(
+		&o.useCheckpoint,
+		"from-checkpoint",
+		false,
+		"perform verification from the latest RSL checkpoint approved by a threshold of root principals",
+	)
+
+	cmd.MarkFlagsMutuallyExclusive("from-checkpoint", "latest-only")
+	cmd.MarkFlagsMutuallyExclusive("from-checkpoint", "from-entry")
+
+	cmd.Flags().IntVar(
+		&o.workers,
+		"workers",
+		1,
+		"number of RSL entries to verify concurrently, 0 uses the number of CPUs",
+	)
+
+	cmd.Flags().StringVar(
+		&o.output,
+		"output",
+		"",
+		fmt.Sprintf("write a report of the checks performed to stdout in the specified format (%s, %s)", common.OutputFormatJSON, common.OutputFormatSARIF),
+	)
 }
 
 func (o *options) Run(cmd *cobra.Command, args []string) error {
@@ -49,19 +91,108 @@ func (o *options) Run(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
+	if o.workers == 0 {
+		o.workers = runtime.NumCPU()
+	}
+
+	if o.all {
+		return o.runAll(cmd, repo, args)
+	}
+
+	opts := []verifyopts.Option{verifyopts.WithOverrideRefName(o.remoteRefName), verifyopts.WithWorkers(o.workers)}
+
+	var report *policy.VerificationReport
+	if o.output != "" {
+		report = policy.NewVerificationReport(args[0])
+		opts = append(opts, verifyopts.WithReport(report))
+	}
+
 	if o.fromEntry != "" {
 		if !dev.InDevMode() {
 			return dev.ErrNotInDevMode
 		}
 
-		return repo.VerifyRefFromEntry(cmd.Context(), args[0], o.fromEntry, verifyopts.WithOverrideRefName(o.remoteRefName))
+		err = repo.VerifyRefFromEntry(cmd.Context(), args[0], o.fromEntry, opts...)
+	} else {
+		if o.latestOnly {
+			opts = append(opts, verifyopts.WithLatestOnly())
+		}
+		if o.useCheckpoint {
+			opts = append(opts, verifyopts.WithCheckpoints())
+		}
+		err = repo.VerifyRef(cmd.Context(), args[0], opts...)
+	}
+
+	if report != nil {
+		// Verification may fail before any checks are recorded
+		report.SetOutcome(err)
+		if reportErr := common.WriteVerificationReport(cmd.OutOrStdout(), o.output, report); reportErr != nil {
+			return errors.Join(err, reportErr)
+		}
 	}
 
-	opts := []verifyopts.Option{verifyopts.WithOverrideRefName(o.remoteRefName)}
+	return err
+}
+
+// runAll verifies all references recorded in the RSL, reporting the outcome for
+// each reference.
+func (o *options) runAll(cmd *cobra.Command, repo *gittuf.Repository, args []string) error {
+	pattern := ""
+	if len(args) > 0 {
+		pattern = args[0]
+	}
+
+	opts := []verifyopts.Option{verifyopts.WithWorkers(o.workers)}
 	if o.latestOnly {
 		opts = append(opts, verifyopts.WithLatestOnly())
 	}
-	return repo.VerifyRef(cmd.Context(), args[0], opts...)
+	if o.useCheckpoint {
+		opts = append(opts, verifyopts.WithCheckpoints())
+	}
+
+	results, err := repo.VerifyAllRefs(cmd.Context(), pattern, opts...)
+	if err != nil {
+		return err
+	}
+
+	failed := 0
+	reports := make([]*policy.VerificationReport, 0, len(results))
+	for _, result := range results {
+		if result.Err != nil {
+			failed++
+		}
+
+		if o.output == "" {
+			if result.Err != nil {
+				fmt.Fprintf(cmd.OutOrStdout(), "%s: verification failed: %s\n", result.Ref, result.Err.Error())
+			} else {
+				fmt.Fprintf(cmd.OutOrStdout(), "%s: verified\n", result.Ref)
+			}
+		}
+		reports = append(reports, result.Report)
+	}
+
+	if o.output != "" {
+		if err := common.WriteVerificationReports(cmd.OutOrStdout(), o.output, reports); err != nil {
+			return err
+		}
+	}
+
+	if failed != 0 {
+		return fmt.Errorf("%w for %d of %d references", policy.ErrVerificationFailed, failed, len(results))
+	}
+
+	return nil
+}
+
+// validateArgs requires a reference to be specified, unless all references are
+// being verified, in which case a glob pattern may be specified instead.
+func (o *options) validateArgs(cmd *cobra.Command, args []string) error {
+	if o.all {
+		return cobra.MaximumNArgs(1)(cmd, args)
+	}
+
+	return cobra.ExactArgs(1)(cmd, args)
 }
 
 func New() *cobra.Command {
@@ -69,7 +200,8 @@ func New() *cobra.Command {
 	cmd := &cobra.Command{
 		Use:               "verify-ref",
 		Short:             "Tools for verifying gittuf policies",
-		Args:              cobra.ExactArgs(1),
+		Args:              o.validateArgs,
+		PreRunE:           common.CheckOutputFormat,
 		RunE:              o.Run,
 		DisableAutoGenTag: true,
 	}
@@ -11,6 +11,8 @@ import (
 	"github.com/gittuf/gittuf/internal/gitinterface"
 	"github.com/gittuf/gittuf/internal/rsl"
 	"github.com/go-git/go-git/v5/config"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
 	"github.com/jonboulle/clockwork"
 )
 
@@ -134,3 +136,59 @@ func CreateTestSignedTag(t *testing.T, repo *gitinterface.Repository, tagName st
 
 	return tagID
 }
+
+// AddTestMergeCommitToSpecifiedRef is a test helper that merges the specified
+// commit into the Git ref using an unsigned merge commit. The first parent of
+// the merge commit is the current tip of the ref, and it uses the tree of the
+// merged commit.
+func AddTestMergeCommitToSpecifiedRef(t *testing.T, repo *gitinterface.Repository, refName string, mergedCommitID gitinterface.Hash) gitinterface.Hash {
+	t.Helper()
+
+	refTip, err := repo.GetReference(refName)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	treeID, err := repo.GetCommitTreeID(mergedCommitID)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	commitMetadata := object.Signature{
+		Name:  testName,
+		Email: testEmail,
+		When:  TestClock.Now(),
+	}
+	commit := &object.Commit{
+		Author:       commitMetadata,
+		Committer:    commitMetadata,
+		TreeHash:     plumbing.NewHash(treeID.String()),
+		ParentHashes: []plumbing.Hash{plumbing.NewHash(refTip.String()), plumbing.NewHash(mergedCommitID.String())},
+		Message:      "Merge commit\n",
+	}
+
+	goGitRepo, err := repo.GetGoGitRepository()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	obj := goGitRepo.Storer.NewEncodedObject()
+	if err := commit.Encode(obj); err != nil {
+		t.Fatal(err)
+	}
+	commitHash, err := goGitRepo.Storer.SetEncodedObject(obj)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	commitID, err := gitinterface.NewHash(commitHash.String())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := repo.SetReference(refName, commitID); err != nil {
+		t.Fatal(err)
+	}
+
+	return commitID
+}
@@ -8,6 +8,7 @@ import (
 	"fmt"
 	"io"
 	"log/slog"
+	"slices"
 	"strings"
 
 	"github.com/gittuf/gittuf/internal/gitinterface"
@@ -66,6 +67,15 @@ func RSLLog(repo *gitinterface.Repository, writer io.WriteCloser) error {
 				// killing the pager
 				return nil
 			}
+
+		case *rsl.CheckpointEntry:
+			slog.Debug(fmt.Sprintf("Writing checkpoint entry '%s'...", iteratorEntry.ID.String()))
+			if err := writeRSLCheckpointEntry(writer, iteratorEntry, annotationsMap[iteratorEntry.ID.String()], hasParent); err != nil {
+				// We return nil here to avoid noisy output when
+				// the writer is unexpectedly closed, such as by
+				// killing the pager
+				return nil
+			}
 		}
 
 		if !hasParent {
@@ -173,3 +183,68 @@ func writeRSLPropagationEntry(writer io.WriteCloser, entry *rsl.PropagationEntry
 	_, err := writer.Write([]byte(text))
 	return err
 }
+
+func writeRSLCheckpointEntry(writer io.WriteCloser, entry *rsl.CheckpointEntry, annotations []*rsl.AnnotationEntry, hasParent bool) error {
+	/* Output format:
+	   checkpoint entry <entryID> (skipped)
+
+	     Policy Entry: <policyEntryID>
+	     Ref Tips:
+	       <refName>: <targetID>
+	     Number:       <number>
+
+	       Annotation ID: <annotationID>
+	       Skip:          <yes/no>
+	       Number:        <number>
+	       Message:
+	         <message>
+	*/
+
+	text := colorer(fmt.Sprintf("checkpoint entry %s", entry.ID.String()), yellow)
+
+	for _, annotation := range annotations {
+		if annotation.Skip {
+			text += fmt.Sprintf(" %s", colorer("(skipped)", red))
+			break
+		}
+	}
+
+	text += "\n"
+
+	text += fmt.Sprintf("\n  Policy Entry: %s", entry.PolicyEntryID.String())
+	text += "\n  Ref Tips:"
+	refNames := make([]string, 0, len(entry.RefTips))
+	for refName := range entry.RefTips {
+		refNames = append(refNames, refName)
+	}
+	slices.Sort(refNames)
+	for _, refName := range refNames {
+		text += fmt.Sprintf("\n    %s: %s", refName, entry.RefTips[refName].String())
+	}
+	if entry.Number != 0 {
+		text += fmt.Sprintf("\n  Number:       %d", entry.Number)
+	}
+
+	for _, annotation := range annotations {
+		text += "\n\n"
+		text += colorer(fmt.Sprintf("    Annotation ID: %s", annotation.ID.String()), green)
+		text += "\n"
+		if annotation.Skip {
+			text += colorer("    Skip:          yes", red)
+		} else {
+			text += "    Skip:          no"
+		}
+		if annotation.Number != 0 {
+			text += fmt.Sprintf("\n    Number:        %d", annotation.Number)
+		}
+		text += fmt.Sprintf("\n    Message:\n      %s", strings.TrimSpace(annotation.Message))
+	}
+
+	text += "\n" // single trailing newline by default
+	if hasParent {
+		text += "\n" // extra newline for all intermediate (i.e., not last) entries
+	}
+
+	_, err := writer.Write([]byte(text))
+	return err
+}
@@ -220,6 +220,25 @@ func (r *Repository) GetCommitMessage(commitID Hash) (string, error) {
 	return commitMessage, nil
 }
 
+// GetCommitTime returns the time recorded for the commit's committer.
+func (r *Repository) GetCommitTime(commitID Hash) (time.Time, error) {
+	if err := r.ensureIsCommit(commitID); err != nil {
+		return time.Time{}, err
+	}
+
+	stdOut, err := r.executor("show", "-s", "--format=%cI", commitID.String()).executeString()
+	if err != nil {
+		return time.Time{}, fmt.Errorf("unable to identify time for commit '%s': %w", commitID.String(), err)
+	}
+
+	commitTime, err := time.Parse(time.RFC3339, stdOut)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid time for commit '%s': %w", commitID.String(), err)
+	}
+
+	return commitTime, nil
+}
+
 // GetCommitTreeID returns the commit's Git tree ID.
 func (r *Repository) GetCommitTreeID(commitID Hash) (Hash, error) {
 	if err := r.ensureIsCommit(commitID); err != nil {
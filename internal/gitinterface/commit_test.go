@@ -459,6 +459,32 @@ func TestRepositoryGetCommitMessage(t *testing.T) {
 	assert.Equal(t, message, commitMessage)
 }
 
+func TestRepositoryGetCommitTime(t *testing.T) {
+	tempDir := t.TempDir()
+	repo := CreateTestGitRepository(t, tempDir, false)
+
+	refName := "refs/heads/main"
+	treeBuilder := NewTreeBuilder(repo)
+
+	// Write empty tree
+	emptyTreeID, err := treeBuilder.WriteTreeFromEntries(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	commit, err := repo.Commit(emptyTreeID, refName, "Initial commit", false)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	commitTime, err := repo.GetCommitTime(commit)
+	assert.Nil(t, err)
+	assert.True(t, testClock.Now().Equal(commitTime))
+
+	_, err = repo.GetCommitTime(emptyTreeID)
+	assert.NotNil(t, err)
+}
+
 func TestGetCommitTreeID(t *testing.T) {
 	tempDir := t.TempDir()
 	repo := CreateTestGitRepository(t, tempDir, false)
@@ -54,6 +54,15 @@ func CreateTestGitRepository(t *testing.T, dir string, bare bool) *Repository {
 	return repo
 }
 
+// SetTestCommitTime sets the time recorded in commits subsequently created in a
+// test repository. This is meant to be used by tests that depend on when
+// commits were created.
+func SetTestCommitTime(t *testing.T, repo *Repository, commitTime time.Time) {
+	t.Helper()
+
+	repo.clock = clockwork.NewFakeClockAt(commitTime)
+}
+
 func setupRepository(t *testing.T, dir string, bare bool) *Repository {
 	t.Helper()
 
@@ -64,18 +64,9 @@ func LoadRepository(repositoryPath string) (*Repository, error) {
 	}
 
 	repo := &Repository{clock: clockwork.NewRealClock()}
-	currentDir, err := os.Getwd()
-	if err != nil {
-		return nil, err
-	}
-
-	if err = os.Chdir(repositoryPath); err != nil {
-		return nil, err
-	}
-	defer os.Chdir(currentDir) //nolint:errcheck
 
 	slog.Debug("Identifying git directory for repository...")
-	stdOut, stdErr, err := repo.executor("rev-parse", "--git-dir").withoutGitDir().execute()
+	stdOut, stdErr, err := repo.executor("rev-parse", "--git-dir").withoutGitDir().withDir(repositoryPath).execute()
 	if err != nil {
 		errContents, newErr := io.ReadAll(stdErr)
 		if newErr != nil {
@@ -89,9 +80,14 @@ func LoadRepository(repositoryPath string) (*Repository, error) {
 		return nil, fmt.Errorf("unable to identify git directory for repository: %w", err)
 	}
 
-	// git rev-parse --git-dir returns a local path, so filepath.Abs gives us
-	// the final path _including_ symlink follows.
-	absPath, err := filepath.Abs(strings.TrimSpace(string(stdOutContents)))
+	// git rev-parse --git-dir returns a path relative to the repository path
+	// unless the git directory is elsewhere, so filepath.Abs gives us the
+	// final path _including_ symlink follows.
+	gitDirPath := strings.TrimSpace(string(stdOutContents))
+	if !filepath.IsAbs(gitDirPath) {
+		gitDirPath = filepath.Join(repositoryPath, gitDirPath)
+	}
+	absPath, err := filepath.Abs(gitDirPath)
 	if err != nil {
 		return nil, err
 	}
@@ -109,6 +105,7 @@ type executor struct {
 	args        []string
 	env         []string
 	stdIn       io.Reader
+	dir         string
 	unsetGitDir bool
 }
 
@@ -132,6 +129,14 @@ func (e *executor) withoutGitDir() *executor {
 	return e
 }
 
+// withDir sets the working directory of the command. Setting the directory of
+// the command rather than changing the working directory of the process ensures
+// the repository can be used concurrently.
+func (e *executor) withDir(dir string) *executor {
+	e.dir = dir
+	return e
+}
+
 // withStdIn sets the contents of stdin to be passed in to the command.
 func (e *executor) withStdIn(stdIn *bytes.Buffer) *executor {
 	e.stdIn = stdIn
@@ -170,6 +175,7 @@ func (e *executor) execute() (io.Reader, io.Reader, error) {
 	cmd := exec.Command(binary, e.args...) //nolint:gosec
 	cmd.Env = e.env
 	cmd.Env = append(cmd.Env, "LC_ALL=C") // force git to the C (and thus english) locale
+	cmd.Dir = e.dir
 
 	var (
 		stdOut bytes.Buffer
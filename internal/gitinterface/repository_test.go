@@ -5,6 +5,7 @@ package gitinterface
 
 import (
 	"path/filepath"
+	"sync"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -53,4 +54,35 @@ func TestRepository(t *testing.T) {
 		require.Nil(t, err)
 		assert.Equal(t, expectedPath, actualPath)
 	})
+	t.Run("concurrent use", func(t *testing.T) {
+		tmpDir := t.TempDir()
+		repo := CreateTestGitRepository(t, tmpDir, false)
+
+		treeBuilder := NewTreeBuilder(repo)
+		emptyTreeID, err := treeBuilder.WriteTreeFromEntries(nil)
+		require.Nil(t, err)
+		commitID, err := repo.Commit(emptyTreeID, "refs/heads/main", "Initial commit\n", false)
+		require.Nil(t, err)
+
+		var wg sync.WaitGroup
+		for range 8 {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+
+				loadedRepo, err := LoadRepository(tmpDir)
+				assert.Nil(t, err)
+				assert.Equal(t, repo.GetGitDir(), loadedRepo.GetGitDir())
+
+				treeID, err := loadedRepo.GetCommitTreeID(commitID)
+				assert.Nil(t, err)
+				assert.Equal(t, emptyTreeID, treeID)
+
+				statuses, err := loadedRepo.Status()
+				assert.Nil(t, err)
+				assert.Empty(t, statuses)
+			}()
+		}
+		wg.Wait()
+	})
 }
@@ -0,0 +1,194 @@
+// Copyright The gittuf Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package policy
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+
+	"github.com/gittuf/gittuf/internal/signerverifier/dsse"
+	sslibdsse "github.com/gittuf/gittuf/internal/third_party/go-securesystemslib/dsse"
+	"github.com/gittuf/gittuf/internal/tuf"
+)
+
+var (
+	ErrBundleMetadataMismatch = errors.New("signing bundle does not match the staged policy metadata")
+	ErrInvalidBundleSignature = errors.New("signature in signing bundle cannot be verified using the principals trusted for the metadata")
+)
+
+// SigningBundle is a portable copy of the envelopes of a policy state. It is
+// used to collect signatures for the policy state from principals who do not
+// have access to the repository. The signatures are merged back into the
+// policy state using MergeSigningBundle.
+type SigningBundle struct {
+	// PolicyID records the ID of the policy commit the bundle was created
+	// from. It's informational, the envelopes in the bundle are matched
+	// against the policy state using their payloads.
+	PolicyID string `json:"policyID,omitempty"`
+
+	// Envelopes records the envelope for each metadata file in the policy
+	// state, keyed by the metadata file's name.
+	Envelopes map[string]*sslibdsse.Envelope `json:"envelopes"`
+}
+
+// NewSigningBundle returns a signing bundle with a copy of the envelopes for
+// the root of trust and the rule files in the state, including any signatures
+// they already have.
+func (s *State) NewSigningBundle(policyID string) *SigningBundle {
+	bundle := &SigningBundle{
+		PolicyID:  policyID,
+		Envelopes: map[string]*sslibdsse.Envelope{},
+	}
+
+	bundle.Envelopes[RootRoleName] = copyEnvelope(s.Metadata.RootEnvelope)
+	if s.Metadata.TargetsEnvelope != nil {
+		bundle.Envelopes[TargetsRoleName] = copyEnvelope(s.Metadata.TargetsEnvelope)
+	}
+	for name, env := range s.Metadata.DelegationEnvelopes {
+		bundle.Envelopes[name] = copyEnvelope(env)
+	}
+
+	return bundle
+}
+
+// Sign adds a signature using the signer to the envelope of the specified
+// metadata file in the bundle. An existing signature from the same key is
+// replaced.
+func (b *SigningBundle) Sign(ctx context.Context, signer sslibdsse.Signer, metadataName string) error {
+	env, has := b.Envelopes[metadataName]
+	if !has {
+		return fmt.Errorf("%w: '%s' is not in the signing bundle", ErrMetadataNotFound, metadataName)
+	}
+
+	env, err := dsse.SignEnvelope(ctx, env, signer)
+	if err != nil {
+		return err
+	}
+
+	b.Envelopes[metadataName] = env
+	return nil
+}
+
+// MergeSigningBundle adds the signatures in the bundle to the envelopes in the
+// state. Each envelope in the bundle must have the same payload as the
+// corresponding envelope in the state, and each signature that is added must
+// be verified by a principal trusted for the metadata file. The root of trust
+// must be signed by root principals and the primary rule file by primary rule
+// file principals. As the rule file delegating to another may not be
+// determined, signatures for delegated rule files are verified using all
+// principals in the state. The IDs of the keys whose signatures were added are
+// returned for each metadata file.
+func (s *State) MergeSigningBundle(ctx context.Context, bundle *SigningBundle) (map[string][]string, error) {
+	added := map[string][]string{}
+
+	for _, name := range slices.Sorted(maps.Keys(bundle.Envelopes)) {
+		bundleEnv := bundle.Envelopes[name]
+
+		var env *sslibdsse.Envelope
+		switch name {
+		case RootRoleName:
+			env = s.Metadata.RootEnvelope
+		case TargetsRoleName:
+			env = s.Metadata.TargetsEnvelope
+		default:
+			env = s.Metadata.DelegationEnvelopes[name]
+		}
+		if env == nil {
+			return nil, fmt.Errorf("%w: '%s' is not in the staged policy", ErrMetadataNotFound, name)
+		}
+		if bundleEnv == nil || bundleEnv.PayloadType != env.PayloadType || bundleEnv.Payload != env.Payload {
+			return nil, fmt.Errorf("%w: '%s'", ErrBundleMetadataMismatch, name)
+		}
+
+		verifier, err := s.getBundleVerifier(name)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, signature := range bundleEnv.Signatures {
+			if slices.ContainsFunc(env.Signatures, func(existing sslibdsse.Signature) bool {
+				return existing.KeyID == signature.KeyID && existing.Sig == signature.Sig
+			}) {
+				continue
+			}
+
+			// Each signature is verified on its own so that a signature that
+			// can't be verified isn't merged alongside ones that can
+			singleSignatureEnv := &sslibdsse.Envelope{
+				PayloadType: env.PayloadType,
+				Payload:     env.Payload,
+				Signatures:  []sslibdsse.Signature{signature},
+			}
+			usedPrincipalIDs, err := verifier.Verify(ctx, nil, singleSignatureEnv)
+			if err != nil {
+				return nil, err
+			}
+			if usedPrincipalIDs.Len() == 0 {
+				return nil, fmt.Errorf("%w: signature from key '%s' for '%s'", ErrInvalidBundleSignature, signature.KeyID, name)
+			}
+
+			// As when signing, a prior signature from the same key is
+			// replaced
+			signatures := []sslibdsse.Signature{}
+			for _, existing := range env.Signatures {
+				if existing.KeyID != signature.KeyID {
+					signatures = append(signatures, existing)
+				}
+			}
+			env.Signatures = append(signatures, signature)
+
+			added[name] = append(added[name], signature.KeyID)
+		}
+	}
+
+	return added, nil
+}
+
+// getBundleVerifier returns a verifier that accepts a signature from any
+// principal trusted for the metadata file.
+func (s *State) getBundleVerifier(metadataName string) (*SignatureVerifier, error) {
+	verifier := &SignatureVerifier{
+		repository:         s.repository,
+		name:               metadataName,
+		threshold:          1,
+		verifyExhaustively: true,
+	}
+
+	var principals []tuf.Principal
+	switch metadataName {
+	case RootRoleName:
+		rootVerifier, err := s.getRootVerifier()
+		if err != nil {
+			return nil, err
+		}
+		principals = rootVerifier.principals
+		verifier.keyRotations = rootVerifier.keyRotations
+	case TargetsRoleName:
+		targetsVerifier, err := s.getTargetsVerifier()
+		if err != nil {
+			return nil, err
+		}
+		principals = targetsVerifier.principals
+		verifier.keyRotations = targetsVerifier.keyRotations
+	default:
+		principals = slices.Collect(maps.Values(s.allPrincipals))
+		verifier.keyRotations = s.keyRotations
+	}
+	verifier.setPrincipals(principals, nil)
+
+	return verifier, nil
+}
+
+// copyEnvelope returns a copy of the envelope so that signatures added to the
+// copy aren't added to the original.
+func copyEnvelope(env *sslibdsse.Envelope) *sslibdsse.Envelope {
+	return &sslibdsse.Envelope{
+		PayloadType: env.PayloadType,
+		Payload:     env.Payload,
+		Signatures:  slices.Clone(env.Signatures),
+	}
+}
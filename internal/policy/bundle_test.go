@@ -0,0 +1,97 @@
+// Copyright The gittuf Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package policy
+
+import (
+	"encoding/base64"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSigningBundle(t *testing.T) {
+	rootSigner := setupSSHKeysForSigning(t, rootKeyBytes, rootPubKeyBytes)
+	secondRootSigner := setupSSHKeysForSigning(t, targets1KeyBytes, targets1PubKeyBytes)
+	unauthorizedSigner := setupSSHKeysForSigning(t, targets2KeyBytes, targets2PubKeyBytes)
+
+	// createStateAwaitingSignature returns a state whose root of trust is
+	// signed by only one of the two root principals required
+	createStateAwaitingSignature := func(t *testing.T) *State {
+		t.Helper()
+
+		state := createTestStateWithRootThreshold(t)
+		state.Metadata.RootEnvelope.Signatures = state.Metadata.RootEnvelope.Signatures[:1]
+		return state
+	}
+
+	t.Run("sign and merge", func(t *testing.T) {
+		state := createStateAwaitingSignature(t)
+
+		bundle := state.NewSigningBundle("policy-id")
+		assert.Equal(t, "policy-id", bundle.PolicyID)
+		assert.Len(t, bundle.Envelopes, 2)
+
+		require.Nil(t, bundle.Sign(testCtx, secondRootSigner, RootRoleName))
+		assert.Len(t, bundle.Envelopes[RootRoleName].Signatures, 2)
+		// The state's envelope is not modified by signing the bundle
+		assert.Len(t, state.Metadata.RootEnvelope.Signatures, 1)
+
+		secondRootKeyID, err := secondRootSigner.KeyID()
+		require.Nil(t, err)
+
+		added, err := state.MergeSigningBundle(testCtx, bundle)
+		require.Nil(t, err)
+		assert.Equal(t, map[string][]string{RootRoleName: {secondRootKeyID}}, added)
+		assert.Len(t, state.Metadata.RootEnvelope.Signatures, 2)
+
+		rootVerifier, err := state.getRootVerifier()
+		require.Nil(t, err)
+		_, err = rootVerifier.Verify(testCtx, nil, state.Metadata.RootEnvelope)
+		assert.Nil(t, err)
+
+		// Merging the same bundle again adds nothing
+		added, err = state.MergeSigningBundle(testCtx, bundle)
+		require.Nil(t, err)
+		assert.Empty(t, added)
+	})
+
+	t.Run("sign rule file", func(t *testing.T) {
+		state := createStateAwaitingSignature(t)
+
+		bundle := state.NewSigningBundle("")
+		require.Nil(t, bundle.Sign(testCtx, rootSigner, TargetsRoleName))
+
+		// The primary rule file already has the same signature from the key
+		added, err := state.MergeSigningBundle(testCtx, bundle)
+		require.Nil(t, err)
+		assert.Empty(t, added)
+		assert.Len(t, state.Metadata.TargetsEnvelope.Signatures, 1)
+
+		err = bundle.Sign(testCtx, rootSigner, "unknown")
+		assert.ErrorIs(t, err, ErrMetadataNotFound)
+	})
+
+	t.Run("unauthorized signature", func(t *testing.T) {
+		state := createStateAwaitingSignature(t)
+
+		bundle := state.NewSigningBundle("")
+		require.Nil(t, bundle.Sign(testCtx, unauthorizedSigner, RootRoleName))
+
+		_, err := state.MergeSigningBundle(testCtx, bundle)
+		assert.ErrorIs(t, err, ErrInvalidBundleSignature)
+		assert.Len(t, state.Metadata.RootEnvelope.Signatures, 1)
+	})
+
+	t.Run("metadata changed after export", func(t *testing.T) {
+		state := createStateAwaitingSignature(t)
+
+		bundle := state.NewSigningBundle("")
+		bundle.Envelopes[RootRoleName].Payload = base64.StdEncoding.EncodeToString([]byte("{}"))
+		require.Nil(t, bundle.Sign(testCtx, secondRootSigner, RootRoleName))
+
+		_, err := state.MergeSigningBundle(testCtx, bundle)
+		assert.ErrorIs(t, err, ErrBundleMetadataMismatch)
+	})
+}
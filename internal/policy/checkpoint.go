@@ -0,0 +1,384 @@
+// Copyright The gittuf Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package policy
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"slices"
+	"strings"
+
+	"github.com/gittuf/gittuf/internal/attestations"
+	"github.com/gittuf/gittuf/internal/common/set"
+	"github.com/gittuf/gittuf/internal/gitinterface"
+	"github.com/gittuf/gittuf/internal/rsl"
+	"github.com/gittuf/gittuf/internal/tuf"
+)
+
+var (
+	ErrCheckpointNotFound        = errors.New("no trusted RSL checkpoint found")
+	ErrCheckpointNotApproved     = errors.New("RSL checkpoint is not approved by a threshold of root principals")
+	ErrCheckpointPolicyMismatch  = errors.New("RSL checkpoint does not record the policy in effect when it was created")
+	ErrCheckpointPolicyUntrusted = errors.New("RSL checkpoint's policy cannot be reached from the initial policy through verified policy states")
+	ErrNoVerifiedRefs            = errors.New("no references passed verification, cannot create RSL checkpoint")
+)
+
+// trustedCheckpoint records an RSL checkpoint that has been approved by a
+// threshold of the root principals in the policy it records, along with that
+// policy.
+type trustedCheckpoint struct {
+	entry       *rsl.CheckpointEntry
+	policyEntry rsl.ReferenceUpdaterEntry
+	policy      *State
+
+	approvers *set.Set[string]
+	threshold int
+}
+
+// records returns true if the checkpoint records the tip of the reference.
+func (c *trustedCheckpoint) records(refName string) bool {
+	_, has := c.entry.GetRefTip(refName)
+	return has
+}
+
+// NewCheckpointEntry verifies every reference recorded in the RSL and returns
+// an RSL checkpoint entry recording the tip of each reference that passed
+// verification and the latest policy entry. The results of verifying each
+// reference are also returned so that references excluded from the checkpoint
+// can be reported. The returned entry is not committed to the RSL.
+func (v *PolicyVerifier) NewCheckpointEntry(ctx context.Context) (*rsl.CheckpointEntry, []*RefVerificationResult, error) {
+	results, err := v.VerifyAllRefsFull(ctx, "")
+	if err != nil {
+		return nil, nil, err
+	}
+
+	refTips := map[string]gitinterface.Hash{}
+	for _, result := range results {
+		if result.Err != nil {
+			slog.Debug(fmt.Sprintf("Reference '%s' failed verification, not recording it in checkpoint...", result.Ref))
+			continue
+		}
+
+		refTips[result.Ref] = result.ExpectedTip
+	}
+	if len(refTips) == 0 {
+		return nil, results, ErrNoVerifiedRefs
+	}
+
+	policyEntry, err := v.searcher.FindLatestPolicyEntry()
+	if err != nil {
+		return nil, results, err
+	}
+
+	return rsl.NewCheckpointEntry(policyEntry.GetID(), refTips), results, nil
+}
+
+// findLatestTrustedCheckpoint walks back the RSL from the latest entry to find
+// the latest checkpoint that has not been skipped and is approved by a
+// threshold of root principals. Checkpoints that are not approved are ignored.
+func (v *PolicyVerifier) findLatestTrustedCheckpoint(ctx context.Context) (*trustedCheckpoint, error) {
+	iterator, err := rsl.GetLatestEntry(v.repo)
+	if err != nil {
+		return nil, err
+	}
+
+	allAnnotations := []*rsl.AnnotationEntry{}
+	for {
+		switch entry := iterator.(type) {
+		case *rsl.AnnotationEntry:
+			allAnnotations = append(allAnnotations, entry)
+
+		case *rsl.CheckpointEntry:
+			if entry.SkippedBy(allAnnotations) {
+				slog.Debug(fmt.Sprintf("Checkpoint '%s' has been skipped, ignoring...", entry.GetID().String()))
+				break
+			}
+
+			checkpoint, err := v.loadTrustedCheckpoint(ctx, entry, allAnnotations)
+			if err == nil {
+				return checkpoint, nil
+			}
+			slog.Debug(fmt.Sprintf("Unable to trust checkpoint '%s': %s", entry.GetID().String(), err.Error()))
+		}
+
+		iterator, err = rsl.GetParentForEntry(v.repo, iterator)
+		if err != nil {
+			if errors.Is(err, rsl.ErrRSLEntryNotFound) {
+				return nil, ErrCheckpointNotFound
+			}
+
+			return nil, err
+		}
+	}
+}
+
+// loadTrustedCheckpoint verifies that the checkpoint records the policy in
+// effect when it was created, and that it is approved by a threshold of the
+// root principals in that policy. The checkpoint's own signature and those of
+// annotations that refer to the checkpoint without skipping it are counted as
+// approvals.
+func (v *PolicyVerifier) loadTrustedCheckpoint(ctx context.Context, checkpoint *rsl.CheckpointEntry, annotations []*rsl.AnnotationEntry) (*trustedCheckpoint, error) {
+	policyEntry, err := loadRSLReferenceUpdaterEntry(v.repo, checkpoint.PolicyEntryID)
+	if err != nil {
+		return nil, err
+	}
+	if policyEntry.GetRefName() != PolicyRef {
+		return nil, ErrCheckpointPolicyMismatch
+	}
+
+	expectedPolicyEntry, err := v.searcher.FindPolicyEntryFor(checkpoint)
+	if err != nil {
+		return nil, err
+	}
+	if !expectedPolicyEntry.GetID().Equal(policyEntry.GetID()) {
+		return nil, ErrCheckpointPolicyMismatch
+	}
+
+	// The checkpoint's policy is trusted as the root of trust for the rest of
+	// the RSL, so it must be reachable from the very first policy state through
+	// a chain of verified policy states. Otherwise, a checkpoint signed by a
+	// root of trust that was never approved could vouch for its own policy.
+	state, err := v.loadCheckpointPolicy(ctx, policyEntry)
+	if err != nil {
+		return nil, err
+	}
+
+	rootVerifier, err := state.getRootVerifier()
+	if err != nil {
+		return nil, err
+	}
+
+	approvals := []rsl.Entry{checkpoint}
+	for _, annotation := range annotations {
+		if annotation.RefersTo(checkpoint.GetID()) && !annotation.Skip {
+			approvals = append(approvals, annotation)
+		}
+	}
+
+	approvers := set.NewSet[string]()
+	for _, approval := range approvals {
+		// Keys revoked or rotated out when the approval was recorded can't
+		// approve the checkpoint
+		approvalTime, err := rsl.GetRecordedTime(v.repo, approval)
+		if err != nil {
+			return nil, err
+		}
+		revokedKeyIDs := state.getRevokedKeyIDs(approval.GetNumber(), approvalTime)
+
+		principalIDs, err := rootVerifier.at(approvalTime).withoutKeys(revokedKeyIDs).Verify(ctx, approval.GetID(), nil)
+		if err != nil && !errors.Is(err, ErrVerifierConditionsUnmet) {
+			return nil, err
+		}
+		if principalIDs != nil {
+			approvers.Extend(principalIDs)
+		}
+	}
+
+	if rootVerifier.countTowardsThreshold(approvers, nil) < rootVerifier.Threshold() {
+		return nil, ErrCheckpointNotApproved
+	}
+
+	if v.persistentCacheEnabled {
+		// The checkpoint's recorded time was determined when verifying its
+		// approval, so this doesn't walk the RSL again
+		checkpointTime, err := rsl.GetRecordedTime(v.repo, checkpoint)
+		if err != nil {
+			return nil, err
+		}
+		v.persistentCache.SetCheckpointRecordedTime(checkpoint.GetID(), checkpointTime)
+	}
+
+	return &trustedCheckpoint{
+		entry:       checkpoint,
+		policyEntry: policyEntry,
+		policy:      state,
+		approvers:   approvers,
+		threshold:   rootVerifier.Threshold(),
+	}, nil
+}
+
+// loadCheckpointPolicy returns the policy state recorded in the checkpoint's
+// policy entry after verifying the chain of policy states from the first policy
+// entry in the RSL up to it.
+func (v *PolicyVerifier) loadCheckpointPolicy(ctx context.Context, policyEntry rsl.ReferenceUpdaterEntry) (*State, error) {
+	firstEntry, _, err := rsl.GetFirstEntry(v.repo)
+	if err != nil {
+		return nil, err
+	}
+
+	policyEntries, err := v.searcher.FindPolicyEntriesInRange(firstEntry, policyEntry)
+	if err != nil {
+		return nil, err
+	}
+	if len(policyEntries) == 0 || !policyEntries[len(policyEntries)-1].GetID().Equal(policyEntry.GetID()) {
+		return nil, ErrCheckpointPolicyUntrusted
+	}
+
+	loader, err := v.newStateLoader()
+	if err != nil {
+		return nil, err
+	}
+
+	policyChainErrs := loader.verifyPolicyChain(ctx, policyEntries)
+	state, err := loader.loadVerifiedPolicy(ctx, policyEntry, policyChainErrs)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrCheckpointPolicyUntrusted, err)
+	}
+
+	return state, nil
+}
+
+// verifyRefFullFromCheckpoint verifies the RSL for the target ref from the
+// trusted checkpoint, recording the checks performed in the verifier's report.
+func (v *PolicyVerifier) verifyRefFullFromCheckpoint(ctx context.Context, checkpoint *trustedCheckpoint, target string) (gitinterface.Hash, error) {
+	v.report = NewVerificationReport(target)
+
+	if v.persistentCacheEnabled {
+		defer v.persistentCache.Commit(v.repo) //nolint:errcheck
+	}
+
+	loader, err := v.newStateLoader()
+	if err != nil {
+		v.report.SetOutcome(err)
+		return gitinterface.ZeroHash, err
+	}
+
+	expectedTip, err := v.verifyRefFromCheckpoint(ctx, checkpoint, target, loader, v.report)
+	v.report.SetOutcome(err)
+
+	return expectedTip, err
+}
+
+// verifyRefFromCheckpoint verifies the entries for refName recorded after the
+// trusted checkpoint, using the checkpoint's policy as the initial policy. The
+// expected tip of the reference is returned, which is the tip recorded in the
+// checkpoint if the reference has not been updated since.
+func (v *PolicyVerifier) verifyRefFromCheckpoint(ctx context.Context, checkpoint *trustedCheckpoint, refName string, loader *stateLoader, report *VerificationReport) (gitinterface.Hash, error) {
+	expectedTip, _ := checkpoint.entry.GetRefTip(refName)
+	report.AddCheck(&VerificationCheck{
+		Type:      CheckTypeCheckpoint,
+		EntryID:   checkpoint.entry.GetID().String(),
+		Ref:       refName,
+		Threshold: checkpoint.threshold,
+		SignedBy:  sortedPrincipalIDs(checkpoint.approvers),
+		Passed:    true,
+	})
+
+	latestEntry, err := rsl.GetLatestEntry(v.repo)
+	if err != nil {
+		return gitinterface.ZeroHash, err
+	}
+	if latestEntry.GetID().Equal(checkpoint.entry.GetID()) {
+		return expectedTip, nil
+	}
+
+	slog.Debug(fmt.Sprintf("Identifying latest RSL entry for '%s' after checkpoint...", refName))
+	lastEntry, _, err := rsl.GetLatestReferenceUpdaterEntry(v.repo, rsl.ForReference(refName), rsl.UntilEntryNumber(checkpoint.entry.GetNumber()))
+	if err != nil {
+		if errors.Is(err, rsl.ErrRSLEntryNotFound) {
+			slog.Debug(fmt.Sprintf("'%s' has not been updated since the checkpoint", refName))
+			return expectedTip, nil
+		}
+
+		return gitinterface.ZeroHash, err
+	}
+
+	var currentAttestations *attestations.Attestations
+	attestationsEntry, err := v.searcher.FindAttestationsEntryFor(checkpoint.entry)
+	if err == nil {
+		currentAttestations, err = loader.loadAttestations(attestationsEntry)
+		if err != nil {
+			return gitinterface.ZeroHash, err
+		}
+	} else if !errors.Is(err, attestations.ErrAttestationsNotFound) {
+		return gitinterface.ZeroHash, err
+	}
+
+	slog.Debug("Identifying all entries after checkpoint...")
+	entries, annotations, err := rsl.GetReferenceUpdaterEntriesInRangeForRef(v.repo, checkpoint.entry.GetID(), lastEntry.GetID(), refName)
+	if err != nil {
+		return gitinterface.ZeroHash, err
+	}
+
+	// The checkpoint's policy entry precedes the range, so any policy entry in
+	// the range is verified using the checkpoint's policy
+	return lastEntry.GetTargetID(), v.verifyEntriesForRef(ctx, checkpoint.policyEntry, entries, annotations, checkpoint.policy, currentAttestations, loader, report)
+}
+
+// verifyAllRefsFromCheckpoint verifies every reference that matches the glob
+// pattern and is either recorded in the trusted checkpoint or updated after it.
+// References recorded in the checkpoint are verified from the checkpoint, while
+// the rest are verified from the start of the RSL.
+func (v *PolicyVerifier) verifyAllRefsFromCheckpoint(ctx context.Context, pattern string, checkpoint *trustedCheckpoint) ([]*RefVerificationResult, error) {
+	refNames := set.NewSet[string]()
+	for refName := range checkpoint.entry.RefTips {
+		if matchesRefPattern(pattern, refName) {
+			refNames.Add(refName)
+		}
+	}
+
+	latestEntry, err := rsl.GetLatestEntry(v.repo)
+	if err != nil {
+		return nil, err
+	}
+	if !latestEntry.GetID().Equal(checkpoint.entry.GetID()) {
+		slog.Debug("Identifying all entries after checkpoint...")
+		entries, _, err := rsl.GetReferenceUpdaterEntriesInRange(v.repo, checkpoint.entry.GetID(), latestEntry.GetID())
+		if err != nil {
+			return nil, err
+		}
+
+		for _, entry := range entries {
+			if matchesRefPattern(pattern, entry.GetRefName()) {
+				refNames.Add(entry.GetRefName())
+			}
+		}
+	}
+
+	sortedRefNames := refNames.Contents()
+	slices.Sort(sortedRefNames)
+
+	loader, err := v.newStateLoader()
+	if err != nil {
+		return nil, err
+	}
+	results := make([]*RefVerificationResult, 0, len(sortedRefNames))
+	for _, refName := range sortedRefNames {
+		slog.Debug(fmt.Sprintf("Verifying '%s'...", refName))
+		result := &RefVerificationResult{
+			Ref:    refName,
+			Report: NewVerificationReport(refName),
+		}
+
+		if checkpoint.records(refName) {
+			result.ExpectedTip, result.Err = v.verifyRefFromCheckpoint(ctx, checkpoint, refName, loader, result.Report)
+		} else {
+			slog.Debug(fmt.Sprintf("'%s' is not recorded in checkpoint, verifying from the start of the RSL...", refName))
+			fullVerifier := *v
+			fullVerifier.useCheckpoints = false
+			result.ExpectedTip, result.Err = fullVerifier.VerifyRefFull(ctx, refName)
+			if report := fullVerifier.Report(); report != nil {
+				result.Report = report
+			}
+		}
+		result.Report.SetOutcome(result.Err)
+
+		results = append(results, result)
+	}
+
+	return results, nil
+}
+
+// matchesRefPattern returns true if the reference is outside the gittuf
+// namespace and matches the glob pattern, which uses the same syntax as the
+// patterns in rules. All such references match an empty pattern.
+func matchesRefPattern(pattern, refName string) bool {
+	if strings.HasPrefix(refName, gittufNamespacePrefix) {
+		return false
+	}
+
+	return pattern == "" || tuf.MatchPatterns([]string{pattern}, refName)
+}
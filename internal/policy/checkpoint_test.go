@@ -0,0 +1,379 @@
+// Copyright The gittuf Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package policy
+
+import (
+	"testing"
+	"time"
+
+	"github.com/gittuf/gittuf/internal/cache"
+	"github.com/gittuf/gittuf/internal/common"
+	"github.com/gittuf/gittuf/internal/dev"
+	"github.com/gittuf/gittuf/internal/gitinterface"
+	policyopts "github.com/gittuf/gittuf/internal/policy/options/policy"
+	"github.com/gittuf/gittuf/internal/rsl"
+	"github.com/gittuf/gittuf/internal/signerverifier/dsse"
+	"github.com/gittuf/gittuf/internal/signerverifier/ssh"
+	tufv01 "github.com/gittuf/gittuf/internal/tuf/v01"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestVerifyRefFullFromCheckpoint(t *testing.T) {
+	mainRef := "refs/heads/main"
+	featureRef := "refs/heads/feature"
+
+	// setupRepository creates a repository where main passes verification and
+	// feature does not, and returns a checkpoint entry for it
+	setupRepository := func(t *testing.T, stateCreator func(*testing.T) *State) (*gitinterface.Repository, *rsl.CheckpointEntry) {
+		t.Helper()
+
+		repo, _ := createTestRepository(t, stateCreator)
+
+		commitIDs := common.AddNTestCommitsToSpecifiedRef(t, repo, mainRef, 1, gpgKeyBytes)
+		common.CreateTestRSLReferenceEntryCommit(t, repo, rsl.NewReferenceEntry(mainRef, commitIDs[0]), gpgKeyBytes)
+
+		commitIDs = common.AddNTestCommitsToSpecifiedRef(t, repo, featureRef, 1, gpgUnauthorizedKeyBytes)
+		common.CreateTestRSLReferenceEntryCommit(t, repo, rsl.NewReferenceEntry(featureRef, commitIDs[0]), gpgKeyBytes)
+
+		checkpoint, results, err := NewPolicyVerifier(repo).NewCheckpointEntry(testCtx)
+		require.Nil(t, err)
+		require.Len(t, results, 2)
+
+		return repo, checkpoint
+	}
+
+	t.Run("create checkpoint entry", func(t *testing.T) {
+		repo, checkpoint := setupRepository(t, createTestStateWithPolicy)
+
+		policyEntry, _, err := rsl.GetLatestReferenceUpdaterEntry(repo, rsl.ForReference(PolicyRef))
+		require.Nil(t, err)
+		mainEntry, _, err := rsl.GetLatestReferenceUpdaterEntry(repo, rsl.ForReference(mainRef))
+		require.Nil(t, err)
+
+		// Only main passes verification
+		assert.Equal(t, policyEntry.GetID(), checkpoint.PolicyEntryID)
+		assert.Equal(t, map[string]gitinterface.Hash{mainRef: mainEntry.GetTargetID()}, checkpoint.RefTips)
+	})
+
+	t.Run("verify from trusted checkpoint", func(t *testing.T) {
+		repo, checkpoint := setupRepository(t, createTestStateWithPolicy)
+		require.Nil(t, checkpoint.CommitUsingSpecificKey(repo, rootKeyBytes))
+
+		commitIDs := common.AddNTestCommitsToSpecifiedRef(t, repo, mainRef, 1, gpgKeyBytes)
+		entryID := common.CreateTestRSLReferenceEntryCommit(t, repo, rsl.NewReferenceEntry(mainRef, commitIDs[0]), gpgKeyBytes)
+
+		verifier := NewPolicyVerifier(repo, policyopts.WithCheckpoints())
+		expectedTip, err := verifier.VerifyRefFull(testCtx, mainRef)
+		assert.Nil(t, err)
+		assert.Equal(t, commitIDs[0], expectedTip)
+
+		report := verifier.Report()
+		require.NotNil(t, report)
+		assert.True(t, report.Passed)
+
+		checkpointChecks := checksOfType(report, CheckTypeCheckpoint)
+		require.Len(t, checkpointChecks, 1)
+		assert.Equal(t, 1, checkpointChecks[0].Threshold)
+		assert.Len(t, checkpointChecks[0].SignedBy, 1)
+
+		// Only the entry after the checkpoint is verified
+		for _, check := range checksOfType(report, CheckTypeRule) {
+			assert.Equal(t, entryID.String(), check.EntryID)
+		}
+	})
+
+	t.Run("verify from trusted checkpoint with persistent cache", func(t *testing.T) {
+		repo, checkpoint := setupRepository(t, createTestStateWithPolicy)
+		require.Nil(t, checkpoint.CommitUsingSpecificKey(repo, rootKeyBytes))
+		require.Nil(t, cache.PopulatePersistentCache(repo))
+
+		verifier := NewPolicyVerifier(repo, policyopts.WithCheckpoints())
+		expectedTip, err := verifier.VerifyRefFull(testCtx, mainRef)
+		assert.Nil(t, err)
+		assert.Equal(t, checkpoint.RefTips[mainRef], expectedTip)
+		assert.Len(t, checksOfType(verifier.Report(), CheckTypeCheckpoint), 1)
+	})
+
+	t.Run("verify from trusted checkpoint without updates", func(t *testing.T) {
+		repo, checkpoint := setupRepository(t, createTestStateWithPolicy)
+		require.Nil(t, checkpoint.CommitUsingSpecificKey(repo, rootKeyBytes))
+
+		verifier := NewPolicyVerifier(repo, policyopts.WithCheckpoints())
+		expectedTip, err := verifier.VerifyRefFull(testCtx, mainRef)
+		assert.Nil(t, err)
+		assert.Equal(t, checkpoint.RefTips[mainRef], expectedTip)
+		assert.Empty(t, checksOfType(verifier.Report(), CheckTypeRule))
+	})
+
+	t.Run("checkpoint not signed by root principal", func(t *testing.T) {
+		repo, checkpoint := setupRepository(t, createTestStateWithPolicy)
+		require.Nil(t, checkpoint.CommitUsingSpecificKey(repo, gpgKeyBytes))
+
+		verifier := NewPolicyVerifier(repo, policyopts.WithCheckpoints())
+		_, err := verifier.findLatestTrustedCheckpoint(testCtx)
+		assert.ErrorIs(t, err, ErrCheckpointNotFound)
+
+		// Verification falls back to the start of the RSL
+		_, err = verifier.VerifyRefFull(testCtx, mainRef)
+		assert.Nil(t, err)
+		assert.Empty(t, checksOfType(verifier.Report(), CheckTypeCheckpoint))
+	})
+
+	t.Run("skipped checkpoint", func(t *testing.T) {
+		repo, checkpoint := setupRepository(t, createTestStateWithPolicy)
+		require.Nil(t, checkpoint.CommitUsingSpecificKey(repo, rootKeyBytes))
+		checkpointID, err := repo.GetReference(rsl.Ref)
+		require.Nil(t, err)
+
+		common.CreateTestRSLAnnotationEntryCommit(t, repo, rsl.NewAnnotationEntry([]gitinterface.Hash{checkpointID}, true, "revoke checkpoint"), rootKeyBytes)
+
+		_, err = NewPolicyVerifier(repo, policyopts.WithCheckpoints()).findLatestTrustedCheckpoint(testCtx)
+		assert.ErrorIs(t, err, ErrCheckpointNotFound)
+	})
+
+	t.Run("checkpoint approved via annotation", func(t *testing.T) {
+		repo, checkpoint := setupRepository(t, createTestStateWithRootThreshold)
+		require.Nil(t, checkpoint.CommitUsingSpecificKey(repo, rootKeyBytes))
+		checkpointID, err := repo.GetReference(rsl.Ref)
+		require.Nil(t, err)
+		checkpoint.ID = checkpointID
+
+		verifier := NewPolicyVerifier(repo, policyopts.WithCheckpoints())
+		_, err = verifier.loadTrustedCheckpoint(testCtx, checkpoint, nil)
+		assert.ErrorIs(t, err, ErrCheckpointNotApproved)
+
+		common.CreateTestRSLAnnotationEntryCommit(t, repo, rsl.NewAnnotationEntry([]gitinterface.Hash{checkpointID}, false, "approve checkpoint"), targets1KeyBytes)
+
+		trusted, err := verifier.findLatestTrustedCheckpoint(testCtx)
+		require.Nil(t, err)
+		assert.Equal(t, checkpointID, trusted.entry.GetID())
+		assert.Equal(t, 2, trusted.approvers.Len())
+	})
+
+	t.Run("checkpoint signed only by revoked root key", func(t *testing.T) {
+		t.Setenv(dev.DevModeKey, "1")
+
+		repo, checkpoint := setupRepository(t, createTestStateWithRevokedRootKey)
+		require.Nil(t, checkpoint.CommitUsingSpecificKey(repo, targets1KeyBytes))
+		checkpointID, err := repo.GetReference(rsl.Ref)
+		require.Nil(t, err)
+		checkpoint.ID = checkpointID
+
+		verifier := NewPolicyVerifier(repo, policyopts.WithCheckpoints())
+		_, err = verifier.loadTrustedCheckpoint(testCtx, checkpoint, nil)
+		assert.ErrorIs(t, err, ErrCheckpointNotApproved)
+
+		_, err = verifier.findLatestTrustedCheckpoint(testCtx)
+		assert.ErrorIs(t, err, ErrCheckpointNotFound)
+	})
+
+	t.Run("checkpoint for policy signed by untrusted root", func(t *testing.T) {
+		repo, _ := createTestRepository(t, createTestStateWithPolicy)
+
+		commitIDs := common.AddNTestCommitsToSpecifiedRef(t, repo, mainRef, 1, gpgUnauthorizedKeyBytes)
+		common.CreateTestRSLReferenceEntryCommit(t, repo, rsl.NewReferenceEntry(mainRef, commitIDs[0]), gpgUnauthorizedKeyBytes)
+
+		// Record a policy whose root of trust is not approved by the prior
+		// policy, and a checkpoint for it signed by the same untrusted key
+		untrustedState := createTestStateWithUntrustedRoot(t)
+		require.Nil(t, untrustedState.Commit(repo, "Replace root of trust", false, false))
+		untrustedPolicyTip, err := repo.GetReference(PolicyStagingRef)
+		require.Nil(t, err)
+		require.Nil(t, repo.SetReference(PolicyRef, untrustedPolicyTip))
+		require.Nil(t, rsl.NewReferenceEntry(PolicyRef, untrustedPolicyTip).Commit(repo, false))
+		untrustedPolicyEntry, _, err := rsl.GetLatestReferenceUpdaterEntry(repo, rsl.ForReference(PolicyRef))
+		require.Nil(t, err)
+
+		checkpoint := rsl.NewCheckpointEntry(untrustedPolicyEntry.GetID(), map[string]gitinterface.Hash{mainRef: commitIDs[0]})
+		require.Nil(t, checkpoint.CommitUsingSpecificKey(repo, targets1KeyBytes))
+		checkpointID, err := repo.GetReference(rsl.Ref)
+		require.Nil(t, err)
+		checkpoint.ID = checkpointID
+
+		verifier := NewPolicyVerifier(repo, policyopts.WithCheckpoints())
+		_, err = verifier.loadTrustedCheckpoint(testCtx, checkpoint, nil)
+		assert.ErrorIs(t, err, ErrCheckpointPolicyUntrusted)
+
+		_, err = verifier.findLatestTrustedCheckpoint(testCtx)
+		assert.ErrorIs(t, err, ErrCheckpointNotFound)
+
+		// Verification falls back to the start of the RSL, where the
+		// unauthorized update to main is found
+		_, err = verifier.VerifyRefFull(testCtx, mainRef)
+		assert.NotNil(t, err)
+		assert.Empty(t, checksOfType(verifier.Report(), CheckTypeCheckpoint))
+	})
+
+	t.Run("trusted checkpoint's recorded time is persisted", func(t *testing.T) {
+		repo, checkpoint := setupRepository(t, createTestStateWithPolicy)
+		require.Nil(t, checkpoint.CommitUsingSpecificKey(repo, rootKeyBytes))
+		checkpointID, err := repo.GetReference(rsl.Ref)
+		require.Nil(t, err)
+		require.Nil(t, cache.PopulatePersistentCache(repo))
+
+		_, err = NewPolicyVerifier(repo, policyopts.WithCheckpoints()).VerifyRefFull(testCtx, mainRef)
+		require.Nil(t, err)
+
+		persistentCache, err := cache.LoadPersistentCache(repo)
+		require.Nil(t, err)
+		checkpointEntry, err := rsl.GetEntry(repo, checkpointID)
+		require.Nil(t, err)
+		checkpointTime, err := rsl.GetRecordedTime(repo, checkpointEntry)
+		require.Nil(t, err)
+		checkpointRecordedTimes := persistentCache.GetCheckpointRecordedTimes()
+		assert.Len(t, checkpointRecordedTimes, 1)
+		assert.True(t, checkpointTime.Equal(checkpointRecordedTimes[checkpointID.String()]))
+
+		// The persisted time is used by later verifiers instead of walking
+		// back the RSL from the checkpoint
+		persistedTime := checkpointTime.Add(time.Hour)
+		persistentCache.SetCheckpointRecordedTime(checkpointID, persistedTime)
+		require.Nil(t, persistentCache.Commit(repo))
+
+		NewPolicyVerifier(repo, policyopts.WithCheckpoints())
+		recordedTime, err := rsl.GetRecordedTime(repo, checkpointEntry)
+		require.Nil(t, err)
+		assert.True(t, persistedTime.Equal(recordedTime))
+	})
+
+	t.Run("verify all refs from checkpoint", func(t *testing.T) {
+		repo, checkpoint := setupRepository(t, createTestStateWithPolicy)
+		require.Nil(t, checkpoint.CommitUsingSpecificKey(repo, rootKeyBytes))
+
+		commitIDs := common.AddNTestCommitsToSpecifiedRef(t, repo, featureRef, 1, gpgKeyBytes)
+		common.CreateTestRSLReferenceEntryCommit(t, repo, rsl.NewReferenceEntry(featureRef, commitIDs[0]), gpgKeyBytes)
+
+		results, err := NewPolicyVerifier(repo, policyopts.WithCheckpoints()).VerifyAllRefsFull(testCtx, "")
+		require.Nil(t, err)
+		require.Len(t, results, 2)
+
+		// feature is not recorded in the checkpoint, so it is verified from
+		// the start of the RSL and its earlier violation is found
+		assert.Equal(t, featureRef, results[0].Ref)
+		assert.ErrorIs(t, results[0].Err, ErrVerificationFailed)
+		assert.Empty(t, checksOfType(results[0].Report, CheckTypeCheckpoint))
+
+		assert.Equal(t, mainRef, results[1].Ref)
+		assert.Nil(t, results[1].Err)
+		assert.Equal(t, checkpoint.RefTips[mainRef], results[1].ExpectedTip)
+		assert.Len(t, checksOfType(results[1].Report, CheckTypeCheckpoint), 1)
+	})
+}
+
+// createTestStateWithRootThreshold sets up a test policy like
+// createTestStateWithPolicy, with targets1PubKeyBytes added as a second root
+// principal and the root threshold set to two.
+func createTestStateWithRootThreshold(t *testing.T) *State {
+	t.Helper()
+
+	state := createTestStateWithPolicy(t)
+
+	rootMetadata, err := state.GetRootMetadata(false)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	secondRootKey := tufv01.NewKeyFromSSLibKey(ssh.NewKeyFromBytes(t, targets1PubKeyBytes))
+	if err := rootMetadata.AddRootPrincipal(secondRootKey); err != nil {
+		t.Fatal(err)
+	}
+	if err := rootMetadata.UpdateRootThreshold(2); err != nil {
+		t.Fatal(err)
+	}
+
+	rootEnv, err := dsse.CreateEnvelope(rootMetadata)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, signer := range []*ssh.Signer{
+		setupSSHKeysForSigning(t, rootKeyBytes, rootPubKeyBytes),
+		setupSSHKeysForSigning(t, targets1KeyBytes, targets1PubKeyBytes),
+	} {
+		rootEnv, err = dsse.SignEnvelope(testCtx, rootEnv, signer)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	state.Metadata.RootEnvelope = rootEnv
+
+	return state
+}
+
+// createTestStateWithRevokedRootKey sets up a test policy like
+// createTestStateWithPolicy, with targets1PubKeyBytes added as a second root
+// principal whose key is revoked before any test RSL entry is recorded.
+func createTestStateWithRevokedRootKey(t *testing.T) *State {
+	t.Helper()
+
+	state := createTestStateWithPolicy(t)
+
+	rootMetadata, err := state.GetRootMetadata(false)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	revokedRootKey := tufv01.NewKeyFromSSLibKey(ssh.NewKeyFromBytes(t, targets1PubKeyBytes))
+	if err := rootMetadata.AddRootPrincipal(revokedRootKey); err != nil {
+		t.Fatal(err)
+	}
+	if err := rootMetadata.RevokeKey(revokedRootKey, "", time.Date(1995, time.January, 1, 0, 0, 0, 0, time.UTC)); err != nil {
+		t.Fatal(err)
+	}
+
+	rootEnv, err := dsse.CreateEnvelope(rootMetadata)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rootEnv, err = dsse.SignEnvelope(testCtx, rootEnv, setupSSHKeysForSigning(t, rootKeyBytes, rootPubKeyBytes))
+	if err != nil {
+		t.Fatal(err)
+	}
+	state.Metadata.RootEnvelope = rootEnv
+
+	return state
+}
+
+// createTestStateWithUntrustedRoot sets up a test policy whose root of trust
+// and rule file are signed only by targets1PubKeyBytes, which is not a root
+// principal in any other test policy.
+func createTestStateWithUntrustedRoot(t *testing.T) *State {
+	t.Helper()
+
+	signer := setupSSHKeysForSigning(t, targets1KeyBytes, targets1PubKeyBytes)
+	key := tufv01.NewKeyFromSSLibKey(signer.MetadataKey())
+
+	rootMetadata, err := InitializeRootMetadata(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := rootMetadata.AddPrimaryRuleFilePrincipal(key); err != nil {
+		t.Fatal(err)
+	}
+
+	rootEnv, err := dsse.CreateEnvelope(rootMetadata)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rootEnv, err = dsse.SignEnvelope(testCtx, rootEnv, signer)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	targetsEnv, err := dsse.CreateEnvelope(InitializeTargetsMetadata())
+	if err != nil {
+		t.Fatal(err)
+	}
+	targetsEnv, err = dsse.SignEnvelope(testCtx, targetsEnv, signer)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return &State{
+		Metadata: &StateMetadata{
+			RootEnvelope:    rootEnv,
+			TargetsEnvelope: targetsEnv,
+		},
+	}
+}
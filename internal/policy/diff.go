@@ -0,0 +1,689 @@
+// Copyright The gittuf Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package policy
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"reflect"
+	"slices"
+	"strings"
+
+	"github.com/gittuf/gittuf/internal/common/set"
+	"github.com/gittuf/gittuf/internal/tuf"
+)
+
+// StateDiff records the semantic differences between two policy states. Only
+// the fields that differ between the states are set.
+type StateDiff struct {
+	// Principals records changes to the principals declared in the root of
+	// trust metadata.
+	Principals *PrincipalsDiff `json:"principals,omitempty"`
+
+	// Root records changes to the principals and threshold trusted for the
+	// root of trust metadata.
+	Root *RoleDiff `json:"root,omitempty"`
+
+	// PrimaryRuleFile records changes to the principals and threshold trusted
+	// for the primary rule file.
+	PrimaryRuleFile *RoleDiff `json:"primaryRuleFile,omitempty"`
+
+	// RuleFiles records changes to each rule file, sorted by name.
+	RuleFiles []*RuleFileDiff `json:"ruleFiles,omitempty"`
+
+	GlobalRules            *ItemsDiff[*GlobalRuleSummary] `json:"globalRules,omitempty"`
+	Hooks                  *ItemsDiff[*HookSummary]       `json:"hooks,omitempty"`
+	ControllerRepositories *ItemsDiff[*RepositorySummary] `json:"controllerRepositories,omitempty"`
+	NetworkRepositories    *ItemsDiff[*RepositorySummary] `json:"networkRepositories,omitempty"`
+}
+
+// PrincipalsDiff records the principals added to, removed from, or modified in
+// a metadata file. A principal is modified when its keys, team membership, or
+// custom metadata change.
+type PrincipalsDiff struct {
+	Added    []string `json:"added,omitempty"`
+	Removed  []string `json:"removed,omitempty"`
+	Modified []string `json:"modified,omitempty"`
+}
+
+// RoleDiff records changes to the principals trusted for a role and the role's
+// threshold.
+type RoleDiff struct {
+	PrincipalsAdded   []string         `json:"principalsAdded,omitempty"`
+	PrincipalsRemoved []string         `json:"principalsRemoved,omitempty"`
+	Threshold         *ThresholdChange `json:"threshold,omitempty"`
+}
+
+// ThresholdChange records a change in a threshold.
+type ThresholdChange struct {
+	From int `json:"from"`
+	To   int `json:"to"`
+}
+
+// RuleFileDiff records the changes to a single rule file.
+type RuleFileDiff struct {
+	Name string `json:"name"`
+
+	// Added and Removed are set when the rule file exists in only one of the
+	// states.
+	Added   bool `json:"added,omitempty"`
+	Removed bool `json:"removed,omitempty"`
+
+	Principals *PrincipalsDiff          `json:"principals,omitempty"`
+	Rules      *ItemsDiff[*RuleSummary] `json:"rules,omitempty"`
+
+	// Reordered records the order of the rules present in both states when
+	// their relative order has changed.
+	Reordered *OrderChange `json:"reordered,omitempty"`
+}
+
+// OrderChange records a change in the order of rules.
+type OrderChange struct {
+	From []string `json:"from"`
+	To   []string `json:"to"`
+}
+
+// ItemsDiff records the named items added, removed, and modified between two
+// policy states.
+type ItemsDiff[T any] struct {
+	Added    []T          `json:"added,omitempty"`
+	Removed  []T          `json:"removed,omitempty"`
+	Modified []*Change[T] `json:"modified,omitempty"`
+}
+
+// Change records the prior and new versions of a named item.
+type Change[T any] struct {
+	Name string `json:"name"`
+	From T      `json:"from"`
+	To   T      `json:"to"`
+}
+
+// RuleSummary describes a rule in a rule file.
+type RuleSummary struct {
+	Name           string          `json:"name"`
+	Patterns       []string        `json:"patterns"`
+	Principals     []string        `json:"principals"`
+	Threshold      int             `json:"threshold"`
+	TeamThresholds map[string]int  `json:"teamThresholds,omitempty"`
+	Terminating    bool            `json:"terminating,omitempty"`
+	Actions        []tuf.RefAction `json:"actions,omitempty"`
+
+	// Validity records the period during which the rule is trusted. If it's
+	// not set, the rule is trusted at all times.
+	Validity *tuf.ValidityWindow `json:"validity,omitempty"`
+}
+
+// GlobalRuleSummary describes a global rule in the root of trust metadata.
+type GlobalRuleSummary struct {
+	Name       string   `json:"name"`
+	Type       string   `json:"type"`
+	Namespaces []string `json:"namespaces,omitempty"`
+	Threshold  int      `json:"threshold,omitempty"`
+	Strategy   string   `json:"strategy,omitempty"`
+	Hooks      []string `json:"hooks,omitempty"`
+}
+
+// HookSummary describes a hook for a Git stage in the root of trust metadata.
+type HookSummary struct {
+	Name        string            `json:"name"`
+	Stage       string            `json:"stage"`
+	Principals  []string          `json:"principals"`
+	Hashes      map[string]string `json:"hashes"`
+	Environment string            `json:"environment"`
+	Timeout     int               `json:"timeout"`
+}
+
+// RepositorySummary describes a controller or network repository in the root
+// of trust metadata.
+type RepositorySummary struct {
+	Name                  string   `json:"name"`
+	Location              string   `json:"location"`
+	InitialRootPrincipals []string `json:"initialRootPrincipals"`
+}
+
+// DiffStates returns the semantic differences between the from and to policy
+// states.
+func DiffStates(from, to *State) (*StateDiff, error) {
+	fromRoot, err := from.GetRootMetadata(false)
+	if err != nil {
+		return nil, err
+	}
+	toRoot, err := to.GetRootMetadata(false)
+	if err != nil {
+		return nil, err
+	}
+
+	diff := &StateDiff{
+		Principals: diffPrincipals(fromRoot.GetPrincipals(), toRoot.GetPrincipals()),
+	}
+
+	diff.Root, err = diffRole(fromRoot.GetRootPrincipals, fromRoot.GetRootThreshold, toRoot.GetRootPrincipals, toRoot.GetRootThreshold)
+	if err != nil {
+		return nil, err
+	}
+
+	diff.PrimaryRuleFile, err = diffRole(fromRoot.GetPrimaryRuleFilePrincipals, fromRoot.GetPrimaryRuleFileThreshold, toRoot.GetPrimaryRuleFilePrincipals, toRoot.GetPrimaryRuleFileThreshold)
+	if err != nil {
+		return nil, err
+	}
+
+	diff.RuleFiles, err = diffRuleFiles(from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	diff.GlobalRules = diffItems(summarizeGlobalRules(fromRoot), summarizeGlobalRules(toRoot), func(rule *GlobalRuleSummary) string { return rule.Name })
+	diff.Hooks = diffItems(summarizeHooks(from.Hooks), summarizeHooks(to.Hooks), func(hook *HookSummary) string { return hook.Stage + "/" + hook.Name })
+	diff.ControllerRepositories = diffItems(summarizeRepositories(fromRoot.GetControllerRepositories()), summarizeRepositories(toRoot.GetControllerRepositories()), func(repository *RepositorySummary) string { return repository.Name })
+	diff.NetworkRepositories = diffItems(summarizeRepositories(fromRoot.GetNetworkRepositories()), summarizeRepositories(toRoot.GetNetworkRepositories()), func(repository *RepositorySummary) string { return repository.Name })
+
+	return diff, nil
+}
+
+// IsEmpty indicates if the two policy states have no semantic differences.
+func (d *StateDiff) IsEmpty() bool {
+	return d.Principals == nil && d.Root == nil && d.PrimaryRuleFile == nil && len(d.RuleFiles) == 0 && d.GlobalRules == nil && d.Hooks == nil && d.ControllerRepositories == nil && d.NetworkRepositories == nil
+}
+
+// String returns a human readable description of the differences.
+func (d *StateDiff) String() string {
+	if d.IsEmpty() {
+		return "No policy changes\n"
+	}
+
+	var sb strings.Builder
+
+	if d.Principals != nil {
+		sb.WriteString("Principals:\n")
+		d.Principals.write(&sb, "    ")
+	}
+	if d.Root != nil {
+		sb.WriteString("Root of trust:\n")
+		d.Root.write(&sb, "    ")
+	}
+	if d.PrimaryRuleFile != nil {
+		sb.WriteString("Primary rule file:\n")
+		d.PrimaryRuleFile.write(&sb, "    ")
+	}
+
+	for _, ruleFile := range d.RuleFiles {
+		switch {
+		case ruleFile.Added:
+			fmt.Fprintf(&sb, "Rule file '%s' (added):\n", ruleFile.Name)
+		case ruleFile.Removed:
+			fmt.Fprintf(&sb, "Rule file '%s' (removed):\n", ruleFile.Name)
+		default:
+			fmt.Fprintf(&sb, "Rule file '%s':\n", ruleFile.Name)
+		}
+
+		if ruleFile.Principals != nil {
+			sb.WriteString("    Principals:\n")
+			ruleFile.Principals.write(&sb, "        ")
+		}
+		if ruleFile.Rules != nil {
+			writeItemsDiff(&sb, ruleFile.Rules, "    ", "Rule", func(rule *RuleSummary) string { return rule.Name }, (*RuleSummary).describe)
+		}
+		if ruleFile.Reordered != nil {
+			fmt.Fprintf(&sb, "    Rules reordered: %s -> %s\n", strings.Join(ruleFile.Reordered.From, ", "), strings.Join(ruleFile.Reordered.To, ", "))
+		}
+	}
+
+	if d.GlobalRules != nil {
+		sb.WriteString("Global rules:\n")
+		writeItemsDiff(&sb, d.GlobalRules, "    ", "Global rule", func(rule *GlobalRuleSummary) string { return rule.Name }, (*GlobalRuleSummary).describe)
+	}
+	if d.Hooks != nil {
+		sb.WriteString("Hooks:\n")
+		writeItemsDiff(&sb, d.Hooks, "    ", "Hook", func(hook *HookSummary) string { return hook.Stage + "/" + hook.Name }, (*HookSummary).describe)
+	}
+	if d.ControllerRepositories != nil {
+		sb.WriteString("Controller repositories:\n")
+		writeItemsDiff(&sb, d.ControllerRepositories, "    ", "Repository", func(repository *RepositorySummary) string { return repository.Name }, (*RepositorySummary).describe)
+	}
+	if d.NetworkRepositories != nil {
+		sb.WriteString("Network repositories:\n")
+		writeItemsDiff(&sb, d.NetworkRepositories, "    ", "Repository", func(repository *RepositorySummary) string { return repository.Name }, (*RepositorySummary).describe)
+	}
+
+	return sb.String()
+}
+
+func (p *PrincipalsDiff) write(sb *strings.Builder, indent string) {
+	for _, principalID := range p.Added {
+		fmt.Fprintf(sb, "%s+ %s\n", indent, principalID)
+	}
+	for _, principalID := range p.Removed {
+		fmt.Fprintf(sb, "%s- %s\n", indent, principalID)
+	}
+	for _, principalID := range p.Modified {
+		fmt.Fprintf(sb, "%s~ %s\n", indent, principalID)
+	}
+}
+
+func (r *RoleDiff) write(sb *strings.Builder, indent string) {
+	for _, principalID := range r.PrincipalsAdded {
+		fmt.Fprintf(sb, "%s+ %s\n", indent, principalID)
+	}
+	for _, principalID := range r.PrincipalsRemoved {
+		fmt.Fprintf(sb, "%s- %s\n", indent, principalID)
+	}
+	if r.Threshold != nil {
+		fmt.Fprintf(sb, "%s~ threshold: %d -> %d\n", indent, r.Threshold.From, r.Threshold.To)
+	}
+}
+
+func writeItemsDiff[T any](sb *strings.Builder, diff *ItemsDiff[T], indent, kind string, name func(T) string, describe func(T) string) {
+	for _, item := range diff.Added {
+		fmt.Fprintf(sb, "%s+ %s '%s': %s\n", indent, kind, name(item), describe(item))
+	}
+	for _, item := range diff.Removed {
+		fmt.Fprintf(sb, "%s- %s '%s': %s\n", indent, kind, name(item), describe(item))
+	}
+	for _, change := range diff.Modified {
+		fmt.Fprintf(sb, "%s~ %s '%s':\n", indent, kind, change.Name)
+		fmt.Fprintf(sb, "%s    from: %s\n", indent, describe(change.From))
+		fmt.Fprintf(sb, "%s    to:   %s\n", indent, describe(change.To))
+	}
+}
+
+func (r *RuleSummary) describe() string {
+	description := fmt.Sprintf("patterns [%s], principals [%s], threshold %d", strings.Join(r.Patterns, ", "), strings.Join(r.Principals, ", "), r.Threshold)
+	for _, teamID := range slices.Sorted(maps.Keys(r.TeamThresholds)) {
+		description += fmt.Sprintf(", team %s threshold %d", teamID, r.TeamThresholds[teamID])
+	}
+	if r.Terminating {
+		description += ", terminating"
+	}
+	if len(r.Actions) != 0 {
+		actions := make([]string, 0, len(r.Actions))
+		for _, action := range r.Actions {
+			actions = append(actions, string(action))
+		}
+		description += fmt.Sprintf(", actions [%s]", strings.Join(actions, ", "))
+	}
+	if r.Validity != nil {
+		description += fmt.Sprintf(", valid %s", r.Validity)
+	}
+	return description
+}
+
+func (g *GlobalRuleSummary) describe() string {
+	description := fmt.Sprintf("type %s, namespaces [%s]", g.Type, strings.Join(g.Namespaces, ", "))
+	switch g.Type {
+	case tuf.GlobalRuleThresholdType:
+		description += fmt.Sprintf(", threshold %d", g.Threshold)
+	case tuf.GlobalRuleMergeStrategyType:
+		description += fmt.Sprintf(", strategy %s", g.Strategy)
+	case tuf.GlobalRuleRequireHookExecutionType:
+		description += fmt.Sprintf(", hooks [%s]", strings.Join(g.Hooks, ", "))
+	}
+	return description
+}
+
+func (h *HookSummary) describe() string {
+	hashes := []string{}
+	for _, algorithm := range slices.Sorted(maps.Keys(h.Hashes)) {
+		hashes = append(hashes, fmt.Sprintf("%s:%s", algorithm, h.Hashes[algorithm]))
+	}
+	return fmt.Sprintf("principals [%s], hashes [%s], environment %s, timeout %d", strings.Join(h.Principals, ", "), strings.Join(hashes, ", "), h.Environment, h.Timeout)
+}
+
+func (r *RepositorySummary) describe() string {
+	return fmt.Sprintf("location %s, initial root principals [%s]", r.Location, strings.Join(r.InitialRootPrincipals, ", "))
+}
+
+// diffPrincipals returns the differences between two sets of principals, or
+// nil if there are none.
+func diffPrincipals(from, to map[string]tuf.Principal) *PrincipalsDiff {
+	diff := &PrincipalsDiff{}
+	for _, principalID := range slices.Sorted(maps.Keys(to)) {
+		fromPrincipal, has := from[principalID]
+		switch {
+		case !has:
+			diff.Added = append(diff.Added, principalID)
+		case !reflect.DeepEqual(summarizePrincipal(fromPrincipal), summarizePrincipal(to[principalID])):
+			diff.Modified = append(diff.Modified, principalID)
+		}
+	}
+	for _, principalID := range slices.Sorted(maps.Keys(from)) {
+		if _, has := to[principalID]; !has {
+			diff.Removed = append(diff.Removed, principalID)
+		}
+	}
+
+	if len(diff.Added) == 0 && len(diff.Removed) == 0 && len(diff.Modified) == 0 {
+		return nil
+	}
+	return diff
+}
+
+// withPrincipalValidityChanges records the principals present in both rule
+// files whose validity windows differ as modified.
+func withPrincipalValidityChanges(diff *PrincipalsDiff, fromPrincipals, toPrincipals map[string]tuf.Principal, fromValidity, toValidity map[string]*tuf.ValidityWindow) *PrincipalsDiff {
+	for _, principalID := range slices.Sorted(maps.Keys(toPrincipals)) {
+		if _, has := fromPrincipals[principalID]; !has || fromValidity[principalID].Equal(toValidity[principalID]) {
+			continue
+		}
+
+		if diff == nil {
+			diff = &PrincipalsDiff{}
+		}
+		if !slices.Contains(diff.Modified, principalID) {
+			diff.Modified = append(diff.Modified, principalID)
+		}
+	}
+
+	if diff != nil {
+		slices.Sort(diff.Modified)
+	}
+	return diff
+}
+
+// diffRole returns the differences in the principals and threshold trusted for
+// a role, or nil if there are none.
+func diffRole(fromPrincipals func() ([]tuf.Principal, error), fromThreshold func() (int, error), toPrincipals func() ([]tuf.Principal, error), toThreshold func() (int, error)) (*RoleDiff, error) {
+	fromIDs, fromThresholdValue, err := getRoleTrust(fromPrincipals, fromThreshold)
+	if err != nil {
+		return nil, err
+	}
+	toIDs, toThresholdValue, err := getRoleTrust(toPrincipals, toThreshold)
+	if err != nil {
+		return nil, err
+	}
+
+	diff := &RoleDiff{}
+	if added := toIDs.Minus(fromIDs); added.Len() != 0 {
+		diff.PrincipalsAdded = sortedPrincipalIDs(added)
+	}
+	if removed := fromIDs.Minus(toIDs); removed.Len() != 0 {
+		diff.PrincipalsRemoved = sortedPrincipalIDs(removed)
+	}
+	if fromThresholdValue != toThresholdValue {
+		diff.Threshold = &ThresholdChange{From: fromThresholdValue, To: toThresholdValue}
+	}
+
+	if len(diff.PrincipalsAdded) == 0 && len(diff.PrincipalsRemoved) == 0 && diff.Threshold == nil {
+		return nil, nil
+	}
+	return diff, nil
+}
+
+// getRoleTrust returns the IDs of the principals trusted for a role and its
+// threshold. A role that is not declared has no principals and a threshold of
+// zero.
+func getRoleTrust(getPrincipals func() ([]tuf.Principal, error), getThreshold func() (int, error)) (*set.Set[string], int, error) {
+	principalIDs := set.NewSet[string]()
+
+	principals, err := getPrincipals()
+	if err != nil {
+		if errors.Is(err, tuf.ErrPrimaryRuleFileInformationNotFoundInRoot) {
+			return principalIDs, 0, nil
+		}
+		return nil, 0, err
+	}
+	for _, principal := range principals {
+		principalIDs.Add(principal.ID())
+	}
+
+	threshold, err := getThreshold()
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return principalIDs, threshold, nil
+}
+
+// diffRuleFiles returns the changes to each rule file present in either state,
+// sorted by the name of the rule file.
+func diffRuleFiles(from, to *State) ([]*RuleFileDiff, error) {
+	names := set.NewSetFromItems(ruleFileNames(from)...)
+	names.Extend(set.NewSetFromItems(ruleFileNames(to)...))
+
+	ruleFileNames := names.Contents()
+	slices.Sort(ruleFileNames)
+
+	diffs := []*RuleFileDiff{}
+	for _, name := range ruleFileNames {
+		fromMetadata, err := getRuleFile(from, name)
+		if err != nil {
+			return nil, err
+		}
+		toMetadata, err := getRuleFile(to, name)
+		if err != nil {
+			return nil, err
+		}
+
+		diff := &RuleFileDiff{
+			Name:    name,
+			Added:   fromMetadata == nil,
+			Removed: toMetadata == nil,
+		}
+
+		fromPrincipals, fromRules := map[string]tuf.Principal{}, []*RuleSummary{}
+		var fromValidity map[string]*tuf.ValidityWindow
+		if fromMetadata != nil {
+			fromPrincipals, fromRules = fromMetadata.GetPrincipals(), summarizeRules(fromMetadata)
+			fromValidity = fromMetadata.GetPrincipalValidityWindows()
+		}
+		toPrincipals, toRules := map[string]tuf.Principal{}, []*RuleSummary{}
+		var toValidity map[string]*tuf.ValidityWindow
+		if toMetadata != nil {
+			toPrincipals, toRules = toMetadata.GetPrincipals(), summarizeRules(toMetadata)
+			toValidity = toMetadata.GetPrincipalValidityWindows()
+		}
+
+		ruleName := func(rule *RuleSummary) string { return rule.Name }
+		diff.Principals = diffPrincipals(fromPrincipals, toPrincipals)
+		diff.Principals = withPrincipalValidityChanges(diff.Principals, fromPrincipals, toPrincipals, fromValidity, toValidity)
+		diff.Rules = diffItems(fromRules, toRules, ruleName)
+		diff.Reordered = diffOrder(fromRules, toRules, ruleName)
+
+		if diff.Added || diff.Removed || diff.Principals != nil || diff.Rules != nil || diff.Reordered != nil {
+			diffs = append(diffs, diff)
+		}
+	}
+
+	return diffs, nil
+}
+
+// ruleFileNames returns the names of the rule files in the state.
+func ruleFileNames(state *State) []string {
+	names := []string{}
+	if state.Metadata.TargetsEnvelope != nil {
+		names = append(names, TargetsRoleName)
+	}
+	for name := range state.Metadata.DelegationEnvelopes {
+		names = append(names, name)
+	}
+	return names
+}
+
+// getRuleFile returns the rule file in the state, or nil if the state does not
+// have it.
+func getRuleFile(state *State, name string) (tuf.TargetsMetadata, error) {
+	if !state.HasTargetsRole(name) {
+		return nil, nil
+	}
+	return state.GetTargetsMetadata(name, false)
+}
+
+// diffItems returns the items added, removed, and modified between from and
+// to, identified using the specified key, or nil if there are no differences.
+func diffItems[T any](from, to []T, key func(T) string) *ItemsDiff[T] {
+	fromItems := map[string]T{}
+	for _, item := range from {
+		fromItems[key(item)] = item
+	}
+	toItems := map[string]T{}
+	for _, item := range to {
+		toItems[key(item)] = item
+	}
+
+	diff := &ItemsDiff[T]{}
+	for _, name := range slices.Sorted(maps.Keys(toItems)) {
+		fromItem, has := fromItems[name]
+		switch {
+		case !has:
+			diff.Added = append(diff.Added, toItems[name])
+		case !reflect.DeepEqual(fromItem, toItems[name]):
+			diff.Modified = append(diff.Modified, &Change[T]{Name: name, From: fromItem, To: toItems[name]})
+		}
+	}
+	for _, name := range slices.Sorted(maps.Keys(fromItems)) {
+		if _, has := toItems[name]; !has {
+			diff.Removed = append(diff.Removed, fromItems[name])
+		}
+	}
+
+	if len(diff.Added) == 0 && len(diff.Removed) == 0 && len(diff.Modified) == 0 {
+		return nil
+	}
+	return diff
+}
+
+// diffOrder returns the relative order of the items present in both from and
+// to if it has changed, or nil otherwise. Items that are added or removed do
+// not by themselves constitute a reordering.
+func diffOrder[T any](from, to []T, key func(T) string) *OrderChange {
+	fromNames := []string{}
+	for _, item := range from {
+		fromNames = append(fromNames, key(item))
+	}
+	toNames := []string{}
+	for _, item := range to {
+		toNames = append(toNames, key(item))
+	}
+
+	commonFrom := slices.DeleteFunc(slices.Clone(fromNames), func(name string) bool { return !slices.Contains(toNames, name) })
+	commonTo := slices.DeleteFunc(slices.Clone(toNames), func(name string) bool { return !slices.Contains(fromNames, name) })
+	if slices.Equal(commonFrom, commonTo) {
+		return nil
+	}
+
+	return &OrderChange{From: commonFrom, To: commonTo}
+}
+
+// principalSummary is used to identify modifications to a principal with the
+// same ID.
+type principalSummary struct {
+	KeyIDs         []string
+	Members        []string
+	Threshold      int
+	CustomMetadata map[string]string
+}
+
+func summarizePrincipal(principal tuf.Principal) *principalSummary {
+	summary := &principalSummary{CustomMetadata: principal.CustomMetadata()}
+	for _, key := range principal.Keys() {
+		summary.KeyIDs = append(summary.KeyIDs, key.KeyID)
+	}
+	slices.Sort(summary.KeyIDs)
+
+	if team, isTeam := principal.(tuf.Team); isTeam {
+		for _, member := range team.GetMembers() {
+			summary.Members = append(summary.Members, member.ID())
+		}
+		slices.Sort(summary.Members)
+		summary.Threshold = team.GetThreshold()
+	}
+
+	return summary
+}
+
+// summarizeRules returns the rules in the rule file in order, omitting the
+// implicit allow rule.
+func summarizeRules(metadata tuf.TargetsMetadata) []*RuleSummary {
+	rules := []*RuleSummary{}
+	for _, rule := range metadata.GetRules() {
+		if rule.ID() == tuf.AllowRuleName {
+			continue
+		}
+
+		summary := &RuleSummary{
+			Name:        rule.ID(),
+			Patterns:    rule.GetProtectedNamespaces(),
+			Principals:  sortedPrincipalIDs(rule.GetPrincipalIDs()),
+			Threshold:   rule.GetThreshold(),
+			Terminating: rule.IsLastTrustedInRuleFile(),
+		}
+		if teamThresholds := rule.GetTeamThresholds(); len(teamThresholds) != 0 {
+			summary.TeamThresholds = teamThresholds
+		}
+		if actions := rule.GetAllowedActions(); len(actions) != 0 {
+			summary.Actions = actions
+		}
+		summary.Validity = rule.GetValidityWindow()
+		rules = append(rules, summary)
+	}
+	return rules
+}
+
+func summarizeGlobalRules(rootMetadata tuf.RootMetadata) []*GlobalRuleSummary {
+	rules := []*GlobalRuleSummary{}
+	for _, rule := range rootMetadata.GetGlobalRules() {
+		rules = append(rules, summarizeGlobalRule(rule))
+	}
+	return rules
+}
+
+func summarizeGlobalRule(rule tuf.GlobalRule) *GlobalRuleSummary {
+	summary := &GlobalRuleSummary{Name: rule.GetName()}
+	switch rule := rule.(type) {
+	case tuf.GlobalRuleThreshold:
+		summary.Type = tuf.GlobalRuleThresholdType
+		summary.Namespaces = rule.GetProtectedNamespaces()
+		summary.Threshold = rule.GetThreshold()
+	case tuf.GlobalRuleRequireSignedCommits:
+		summary.Type = tuf.GlobalRuleRequireSignedCommitsType
+		summary.Namespaces = rule.GetProtectedNamespaces()
+	case tuf.GlobalRuleRequireHookExecution:
+		summary.Type = tuf.GlobalRuleRequireHookExecutionType
+		summary.Namespaces = rule.GetProtectedNamespaces()
+		summary.Hooks = rule.GetHookNames()
+	case tuf.GlobalRuleMergeStrategy:
+		summary.Type = tuf.GlobalRuleMergeStrategyType
+		summary.Namespaces = rule.GetProtectedNamespaces()
+		summary.Strategy = rule.GetMergeStrategy()
+	case tuf.GlobalRuleBlockForcePushes:
+		summary.Type = tuf.GlobalRuleBlockForcePushesType
+		summary.Namespaces = rule.GetProtectedNamespaces()
+	}
+	return summary
+}
+
+func summarizeHooks(hooks map[tuf.HookStage][]tuf.Hook) []*HookSummary {
+	summaries := []*HookSummary{}
+	for stage, stageHooks := range hooks {
+		for _, hook := range stageHooks {
+			summaries = append(summaries, &HookSummary{
+				Name:        hook.ID(),
+				Stage:       stage.String(),
+				Principals:  sortedPrincipalIDs(hook.GetPrincipalIDs()),
+				Hashes:      hook.GetHashes(),
+				Environment: hook.GetEnvironment().String(),
+				Timeout:     hook.GetTimeout(),
+			})
+		}
+	}
+	return summaries
+}
+
+func summarizeRepositories(repositories []tuf.OtherRepository) []*RepositorySummary {
+	summaries := []*RepositorySummary{}
+	for _, repository := range repositories {
+		principalIDs := []string{}
+		for _, principal := range repository.GetInitialRootPrincipals() {
+			principalIDs = append(principalIDs, principal.ID())
+		}
+		slices.Sort(principalIDs)
+
+		summaries = append(summaries, &RepositorySummary{
+			Name:                  repository.GetName(),
+			Location:              repository.GetLocation(),
+			InitialRootPrincipals: principalIDs,
+		})
+	}
+	return summaries
+}
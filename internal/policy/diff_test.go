@@ -0,0 +1,173 @@
+// Copyright The gittuf Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package policy
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/gittuf/gittuf/internal/signerverifier/dsse"
+	"github.com/gittuf/gittuf/internal/signerverifier/gpg"
+	"github.com/gittuf/gittuf/internal/signerverifier/ssh"
+	sslibdsse "github.com/gittuf/gittuf/internal/third_party/go-securesystemslib/dsse"
+	"github.com/gittuf/gittuf/internal/tuf"
+	tufv01 "github.com/gittuf/gittuf/internal/tuf/v01"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDiffStates(t *testing.T) {
+	gpgKeyR, err := gpg.LoadGPGKeyFromBytes(gpgPubKeyBytes)
+	require.Nil(t, err)
+	gpgKey := tufv01.NewKeyFromSSLibKey(gpgKeyR)
+	targets1Key := tufv01.NewKeyFromSSLibKey(ssh.NewKeyFromBytes(t, targets1PubKeyBytes))
+
+	t.Run("no changes", func(t *testing.T) {
+		diff, err := DiffStates(createTestStateWithPolicy(t), createTestStateWithPolicy(t))
+		require.Nil(t, err)
+		assert.True(t, diff.IsEmpty())
+		assert.Equal(t, "No policy changes\n", diff.String())
+
+		diffJSON, err := json.Marshal(diff)
+		require.Nil(t, err)
+		assert.Equal(t, "{}", string(diffJSON))
+	})
+
+	t.Run("root changes", func(t *testing.T) {
+		diff, err := DiffStates(createTestStateWithPolicy(t), createTestStateWithRootThreshold(t))
+		require.Nil(t, err)
+
+		assert.Equal(t, &PrincipalsDiff{Added: []string{targets1Key.KeyID}}, diff.Principals)
+		assert.Equal(t, &RoleDiff{PrincipalsAdded: []string{targets1Key.KeyID}, Threshold: &ThresholdChange{From: 1, To: 2}}, diff.Root)
+		assert.Nil(t, diff.PrimaryRuleFile)
+		assert.Empty(t, diff.RuleFiles)
+
+		assert.Contains(t, diff.String(), "Root of trust:\n    + "+targets1Key.KeyID+"\n    ~ threshold: 1 -> 2\n")
+	})
+
+	t.Run("global rule changes", func(t *testing.T) {
+		diff, err := DiffStates(createTestStateWithGlobalConstraintThreshold(t), createTestStateWithGlobalConstraintBlockForcePushes(t))
+		require.Nil(t, err)
+
+		require.NotNil(t, diff.GlobalRules)
+		assert.Equal(t, []*GlobalRuleSummary{{Name: "block-force-pushes-main", Type: tuf.GlobalRuleBlockForcePushesType, Namespaces: []string{"git:refs/heads/main"}}}, diff.GlobalRules.Added)
+		assert.Equal(t, []*GlobalRuleSummary{{Name: "threshold-2-main", Type: tuf.GlobalRuleThresholdType, Namespaces: []string{"git:refs/heads/main"}, Threshold: 2}}, diff.GlobalRules.Removed)
+		assert.Empty(t, diff.GlobalRules.Modified)
+
+		assert.Contains(t, diff.String(), "- Global rule 'threshold-2-main': type threshold, namespaces [git:refs/heads/main], threshold 2\n")
+	})
+
+	t.Run("rule changes", func(t *testing.T) {
+		from := createTestStateWithPolicy(t)
+		to := createTestStateWithPolicy(t)
+
+		updateTestTargetsMetadata(t, to, TargetsRoleName, func(targetsMetadata tuf.TargetsMetadata) {
+			require.Nil(t, targetsMetadata.AddPrincipal(targets1Key))
+			require.Nil(t, targetsMetadata.UpdateRule("protect-main", []string{gpgKey.KeyID, targets1Key.KeyID}, []string{"git:refs/heads/main"}, 2))
+			require.Nil(t, targetsMetadata.AddRule("protect-release", []string{targets1Key.KeyID}, []string{"git:refs/heads/release"}, 1))
+			require.Nil(t, targetsMetadata.ReorderRules([]string{"protect-files-1-and-2", "protect-release", "protect-main"}))
+		})
+
+		diff, err := DiffStates(from, to)
+		require.Nil(t, err)
+		require.Len(t, diff.RuleFiles, 1)
+
+		ruleFile := diff.RuleFiles[0]
+		assert.Equal(t, TargetsRoleName, ruleFile.Name)
+		assert.False(t, ruleFile.Added)
+		assert.Equal(t, &PrincipalsDiff{Added: []string{targets1Key.KeyID}}, ruleFile.Principals)
+
+		require.NotNil(t, ruleFile.Rules)
+		require.Len(t, ruleFile.Rules.Added, 1)
+		assert.Equal(t, "protect-release", ruleFile.Rules.Added[0].Name)
+		assert.Empty(t, ruleFile.Rules.Removed)
+		require.Len(t, ruleFile.Rules.Modified, 1)
+		assert.Equal(t, "protect-main", ruleFile.Rules.Modified[0].Name)
+		assert.Equal(t, 1, ruleFile.Rules.Modified[0].From.Threshold)
+		assert.Equal(t, 2, ruleFile.Rules.Modified[0].To.Threshold)
+		assert.Len(t, ruleFile.Rules.Modified[0].To.Principals, 2)
+
+		// protect-release is new and isn't considered when detecting reordering
+		assert.Equal(t, &OrderChange{From: []string{"protect-main", "protect-files-1-and-2"}, To: []string{"protect-files-1-and-2", "protect-main"}}, ruleFile.Reordered)
+
+		assert.Contains(t, diff.String(), "Rules reordered: protect-main, protect-files-1-and-2 -> protect-files-1-and-2, protect-main\n")
+	})
+
+	t.Run("rule file added", func(t *testing.T) {
+		from := createTestStateWithDelegatedPolicies(t)
+		to := createTestStateWithDelegatedPolicies(t)
+
+		delegationMetadata := InitializeTargetsMetadata()
+		require.Nil(t, delegationMetadata.AddPrincipal(targets1Key))
+		require.Nil(t, delegationMetadata.AddRule("5", []string{targets1Key.KeyID}, []string{"file:2/subpath/*"}, 1))
+		env, err := dsse.CreateEnvelope(delegationMetadata)
+		require.Nil(t, err)
+		to.Metadata.DelegationEnvelopes["2"] = env
+
+		diff, err := DiffStates(from, to)
+		require.Nil(t, err)
+		require.Len(t, diff.RuleFiles, 1)
+		assert.Equal(t, "2", diff.RuleFiles[0].Name)
+		assert.True(t, diff.RuleFiles[0].Added)
+		assert.Len(t, diff.RuleFiles[0].Rules.Added, 1)
+
+		// The reverse diff records the rule file's removal
+		diff, err = DiffStates(to, from)
+		require.Nil(t, err)
+		require.Len(t, diff.RuleFiles, 1)
+		assert.True(t, diff.RuleFiles[0].Removed)
+		assert.Contains(t, diff.String(), "Rule file '2' (removed):\n")
+	})
+
+	t.Run("hook and repository changes", func(t *testing.T) {
+		from := createTestStateWithPolicy(t)
+		to := createTestStateWithPolicy(t)
+
+		rootMetadata, err := to.GetRootMetadata(false)
+		require.Nil(t, err)
+		require.Nil(t, rootMetadata.AddControllerRepository("controller", "https://example.com/controller", []tuf.Principal{gpgKey}))
+		rootEnv, err := dsse.CreateEnvelope(rootMetadata)
+		require.Nil(t, err)
+		to.Metadata.RootEnvelope = rootEnv
+
+		hook, err := rootMetadata.AddHook([]tuf.HookStage{tuf.HookStagePreCommit}, "lint", []string{gpgKey.KeyID}, map[string]string{"sha256": "abcd"}, tuf.HookEnvironmentLua, 100)
+		require.Nil(t, err)
+		to.Hooks = map[tuf.HookStage][]tuf.Hook{tuf.HookStagePreCommit: {hook}}
+
+		diff, err := DiffStates(from, to)
+		require.Nil(t, err)
+
+		require.NotNil(t, diff.ControllerRepositories)
+		assert.Equal(t, []*RepositorySummary{{Name: "controller", Location: "https://example.com/controller", InitialRootPrincipals: []string{gpgKey.KeyID}}}, diff.ControllerRepositories.Added)
+		assert.Nil(t, diff.NetworkRepositories)
+
+		require.NotNil(t, diff.Hooks)
+		require.Len(t, diff.Hooks.Added, 1)
+		assert.Equal(t, "lint", diff.Hooks.Added[0].Name)
+		assert.Equal(t, tuf.HookStagePreCommitString, diff.Hooks.Added[0].Stage)
+	})
+}
+
+// updateTestTargetsMetadata applies the update to the specified rule file in
+// the state. The updated rule file is not signed.
+func updateTestTargetsMetadata(t *testing.T, state *State, roleName string, update func(tuf.TargetsMetadata)) {
+	t.Helper()
+
+	targetsMetadata, err := state.GetTargetsMetadata(roleName, false)
+	require.Nil(t, err)
+
+	update(targetsMetadata)
+
+	env, err := dsse.CreateEnvelope(targetsMetadata)
+	require.Nil(t, err)
+
+	if roleName == TargetsRoleName {
+		state.Metadata.TargetsEnvelope = env
+		return
+	}
+	if state.Metadata.DelegationEnvelopes == nil {
+		state.Metadata.DelegationEnvelopes = map[string]*sslibdsse.Envelope{}
+	}
+	state.Metadata.DelegationEnvelopes[roleName] = env
+}
@@ -0,0 +1,585 @@
+// Copyright The gittuf Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package policy
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"reflect"
+	"slices"
+
+	"github.com/gittuf/gittuf/internal/common/set"
+	"github.com/gittuf/gittuf/internal/signerverifier/dsse"
+	sslibdsse "github.com/gittuf/gittuf/internal/third_party/go-securesystemslib/dsse"
+	"github.com/gittuf/gittuf/internal/tuf"
+	tufv04 "github.com/gittuf/gittuf/internal/tuf/v04"
+)
+
+var ErrInvalidDocument = errors.New("invalid policy document")
+
+// Document is a declarative representation of the root of trust and the rule
+// files in a policy state, without their signatures. Hooks, GitHub apps,
+// propagation directives, and controller and network repositories are not
+// recorded in the document and are left unchanged when a document is applied
+// to a policy state.
+type Document struct {
+	Root      *RootDocument                `json:"root"`
+	RuleFiles map[string]*RuleFileDocument `json:"ruleFiles"`
+}
+
+// RootDocument records the root of trust in a policy document.
+type RootDocument struct {
+	Expires            string `json:"expires,omitempty"`
+	RepositoryLocation string `json:"repositoryLocation,omitempty"`
+
+	// Principals records the definitions of the principals trusted for the
+	// root of trust and the primary rule file.
+	Principals map[string]tuf.Principal `json:"principals"`
+
+	RootPrincipals []string `json:"rootPrincipals"`
+	RootThreshold  int      `json:"rootThreshold"`
+
+	PrimaryRuleFilePrincipals []string `json:"primaryRuleFilePrincipals,omitempty"`
+	PrimaryRuleFileThreshold  int      `json:"primaryRuleFileThreshold,omitempty"`
+
+	GlobalRules []*GlobalRuleSummary `json:"globalRules,omitempty"`
+}
+
+// UnmarshalJSON decodes the root document, identifying the type of each
+// principal.
+func (r *RootDocument) UnmarshalJSON(data []byte) error {
+	type rootDocument RootDocument
+	type tempType struct {
+		*rootDocument
+		Principals map[string]json.RawMessage `json:"principals"`
+	}
+
+	temp := &tempType{rootDocument: (*rootDocument)(r)}
+	if err := json.Unmarshal(data, temp); err != nil {
+		return fmt.Errorf("unable to unmarshal json: %w", err)
+	}
+
+	principals, err := unmarshalDocumentPrincipals(temp.Principals)
+	if err != nil {
+		return err
+	}
+	r.Principals = principals
+
+	return nil
+}
+
+// RuleFileDocument records a rule file in a policy document. The allow rule
+// is not recorded.
+type RuleFileDocument struct {
+	Expires    string                   `json:"expires,omitempty"`
+	Principals map[string]tuf.Principal `json:"principals"`
+	Rules      []*RuleSummary           `json:"rules"`
+
+	// PrincipalValidity records the periods during which principals are
+	// trusted by the rules in the rule file, keyed by principal ID.
+	PrincipalValidity map[string]*tuf.ValidityWindow `json:"principalValidity,omitempty"`
+}
+
+// UnmarshalJSON decodes the rule file document, identifying the type of each
+// principal.
+func (r *RuleFileDocument) UnmarshalJSON(data []byte) error {
+	type ruleFileDocument RuleFileDocument
+	type tempType struct {
+		*ruleFileDocument
+		Principals map[string]json.RawMessage `json:"principals"`
+	}
+
+	temp := &tempType{ruleFileDocument: (*ruleFileDocument)(r)}
+	if err := json.Unmarshal(data, temp); err != nil {
+		return fmt.Errorf("unable to unmarshal json: %w", err)
+	}
+
+	principals, err := unmarshalDocumentPrincipals(temp.Principals)
+	if err != nil {
+		return err
+	}
+	r.Principals = principals
+
+	return nil
+}
+
+// ToDocument returns the policy document for the root of trust and the rule
+// files in the state.
+func (s *State) ToDocument() (*Document, error) {
+	rootMetadata, err := s.GetRootMetadata(true)
+	if err != nil {
+		return nil, err
+	}
+
+	rootPrincipals, err := rootMetadata.GetRootPrincipals()
+	if err != nil {
+		return nil, err
+	}
+	rootThreshold, err := rootMetadata.GetRootThreshold()
+	if err != nil {
+		return nil, err
+	}
+
+	root := &RootDocument{
+		Expires:            rootMetadata.GetExpires(),
+		RepositoryLocation: rootMetadata.GetRepositoryLocation(),
+		Principals:         map[string]tuf.Principal{},
+		RootPrincipals:     []string{},
+		RootThreshold:      rootThreshold,
+		GlobalRules:        summarizeGlobalRules(rootMetadata),
+	}
+	for _, principal := range rootPrincipals {
+		root.Principals[principal.ID()] = principal
+		root.RootPrincipals = append(root.RootPrincipals, principal.ID())
+	}
+	slices.Sort(root.RootPrincipals)
+
+	primaryRuleFilePrincipals, err := rootMetadata.GetPrimaryRuleFilePrincipals()
+	if err != nil && !errors.Is(err, tuf.ErrPrimaryRuleFileInformationNotFoundInRoot) {
+		return nil, err
+	}
+	if err == nil {
+		root.PrimaryRuleFileThreshold, err = rootMetadata.GetPrimaryRuleFileThreshold()
+		if err != nil {
+			return nil, err
+		}
+		for _, principal := range primaryRuleFilePrincipals {
+			root.Principals[principal.ID()] = principal
+			root.PrimaryRuleFilePrincipals = append(root.PrimaryRuleFilePrincipals, principal.ID())
+		}
+		slices.Sort(root.PrimaryRuleFilePrincipals)
+	}
+
+	document := &Document{Root: root, RuleFiles: map[string]*RuleFileDocument{}}
+	for _, name := range ruleFileNames(s) {
+		targetsMetadata, err := s.GetTargetsMetadata(name, true)
+		if err != nil {
+			return nil, err
+		}
+
+		document.RuleFiles[name] = &RuleFileDocument{
+			Expires:           targetsMetadata.GetExpires(),
+			Principals:        targetsMetadata.GetPrincipals(),
+			Rules:             summarizeRules(targetsMetadata),
+			PrincipalValidity: targetsMetadata.GetPrincipalValidityWindows(),
+		}
+	}
+
+	return document, nil
+}
+
+// ApplyDocument updates the root of trust and the rule files in the state to
+// match the document using the metadata mutators, so the changes are
+// validated as they are when made individually. Rule files that are not in
+// the document are removed from the state. The names of the metadata files
+// that were updated are returned; their envelopes are not signed. The names
+// of the rule files that were removed are also returned.
+func (s *State) ApplyDocument(document *Document) ([]string, []string, error) {
+	if err := validateDocument(document); err != nil {
+		return nil, nil, err
+	}
+
+	updated := []string{}
+
+	rootMetadata, err := s.GetRootMetadata(true)
+	if err != nil {
+		return nil, nil, err
+	}
+	changed, err := applyMetadataChanges(rootMetadata, func() error {
+		return applyRootDocument(rootMetadata, document.Root)
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	if changed {
+		rootMetadata.IncrementMetadataVersion()
+		env, err := dsse.CreateEnvelope(rootMetadata)
+		if err != nil {
+			return nil, nil, err
+		}
+		s.Metadata.RootEnvelope = env
+		updated = append(updated, RootRoleName)
+	}
+
+	for _, name := range slices.Sorted(maps.Keys(document.RuleFiles)) {
+		var targetsMetadata tuf.TargetsMetadata
+		if s.HasTargetsRole(name) {
+			targetsMetadata, err = s.GetTargetsMetadata(name, true)
+			if err != nil {
+				return nil, nil, err
+			}
+		} else {
+			targetsMetadata = InitializeTargetsMetadata()
+		}
+
+		changed, err := applyMetadataChanges(targetsMetadata, func() error {
+			return applyRuleFileDocument(targetsMetadata, document.RuleFiles[name])
+		})
+		if err != nil {
+			return nil, nil, fmt.Errorf("unable to apply rule file '%s': %w", name, err)
+		}
+		if !changed && s.HasTargetsRole(name) {
+			continue
+		}
+		if s.HasTargetsRole(name) {
+			targetsMetadata.IncrementMetadataVersion()
+		}
+
+		env, err := dsse.CreateEnvelope(targetsMetadata)
+		if err != nil {
+			return nil, nil, err
+		}
+		if name == TargetsRoleName {
+			s.Metadata.TargetsEnvelope = env
+		} else {
+			if s.Metadata.DelegationEnvelopes == nil {
+				s.Metadata.DelegationEnvelopes = map[string]*sslibdsse.Envelope{}
+			}
+			s.Metadata.DelegationEnvelopes[name] = env
+		}
+		updated = append(updated, name)
+	}
+
+	removed := []string{}
+	for _, name := range ruleFileNames(s) {
+		if _, has := document.RuleFiles[name]; has {
+			continue
+		}
+		if name == TargetsRoleName {
+			return nil, nil, fmt.Errorf("%w: primary rule file '%s' cannot be removed", ErrInvalidDocument, TargetsRoleName)
+		}
+
+		delete(s.Metadata.DelegationEnvelopes, name)
+		removed = append(removed, name)
+	}
+	slices.Sort(removed)
+
+	return updated, removed, nil
+}
+
+// validateDocument checks that the document declares a root of trust and that
+// rule names are unique across its rule files.
+func validateDocument(document *Document) error {
+	if document == nil || document.Root == nil {
+		return fmt.Errorf("%w: root of trust is not declared", ErrInvalidDocument)
+	}
+	if len(document.Root.RootPrincipals) == 0 || document.Root.RootThreshold < 1 {
+		return fmt.Errorf("%w: root of trust must declare principals and a threshold", ErrInvalidDocument)
+	}
+	if len(document.Root.PrimaryRuleFilePrincipals) != 0 && document.Root.PrimaryRuleFileThreshold < 1 {
+		return fmt.Errorf("%w: primary rule file threshold must be at least 1", ErrInvalidDocument)
+	}
+
+	ruleNames := set.NewSet[string]()
+	for _, name := range slices.Sorted(maps.Keys(document.RuleFiles)) {
+		if name == RootRoleName {
+			return fmt.Errorf("%w: rule file cannot be named '%s'", ErrInvalidDocument, RootRoleName)
+		}
+
+		ruleFile := document.RuleFiles[name]
+		if ruleFile == nil {
+			return fmt.Errorf("%w: rule file '%s' is empty", ErrInvalidDocument, name)
+		}
+		for _, rule := range ruleFile.Rules {
+			if rule.Name == RootRoleName {
+				return fmt.Errorf("%w: rule cannot be named '%s'", ErrInvalidDocument, RootRoleName)
+			}
+			if ruleNames.Has(rule.Name) {
+				return fmt.Errorf("%w: '%s'", tuf.ErrDuplicatedRuleName, rule.Name)
+			}
+			ruleNames.Add(rule.Name)
+		}
+	}
+
+	return nil
+}
+
+// applyMetadataChanges applies the changes to the metadata and returns true if
+// its contents were changed as a result.
+func applyMetadataChanges(metadata any, apply func() error) (bool, error) {
+	before, err := json.Marshal(metadata)
+	if err != nil {
+		return false, err
+	}
+
+	if err := apply(); err != nil {
+		return false, err
+	}
+
+	after, err := json.Marshal(metadata)
+	if err != nil {
+		return false, err
+	}
+
+	return !bytes.Equal(before, after), nil
+}
+
+// applyRootDocument updates the root of trust metadata to match the document.
+func applyRootDocument(rootMetadata tuf.RootMetadata, root *RootDocument) error {
+	if root.Expires != "" {
+		rootMetadata.SetExpires(root.Expires)
+	}
+	rootMetadata.SetRepositoryLocation(root.RepositoryLocation)
+
+	if err := applyRoleTrust(root.Principals, root.RootPrincipals, root.RootThreshold, rootMetadata.GetRootPrincipals, rootMetadata.GetRootThreshold, rootMetadata.AddRootPrincipal, rootMetadata.DeleteRootPrincipal, rootMetadata.UpdateRootThreshold); err != nil {
+		return fmt.Errorf("unable to apply root of trust principals: %w", err)
+	}
+	if err := applyRoleTrust(root.Principals, root.PrimaryRuleFilePrincipals, root.PrimaryRuleFileThreshold, rootMetadata.GetPrimaryRuleFilePrincipals, rootMetadata.GetPrimaryRuleFileThreshold, rootMetadata.AddPrimaryRuleFilePrincipal, rootMetadata.DeletePrimaryRuleFilePrincipal, rootMetadata.UpdatePrimaryRuleFileThreshold); err != nil {
+		return fmt.Errorf("unable to apply primary rule file principals: %w", err)
+	}
+
+	currentGlobalRules := map[string]*GlobalRuleSummary{}
+	for _, summary := range summarizeGlobalRules(rootMetadata) {
+		currentGlobalRules[summary.Name] = summary
+	}
+
+	declaredGlobalRules := set.NewSet[string]()
+	for _, summary := range root.GlobalRules {
+		declaredGlobalRules.Add(summary.Name)
+
+		current, has := currentGlobalRules[summary.Name]
+		if has && reflect.DeepEqual(current, summary) {
+			continue
+		}
+
+		globalRule, err := newGlobalRule(summary)
+		if err != nil {
+			return err
+		}
+
+		if has {
+			err = rootMetadata.UpdateGlobalRule(globalRule)
+		} else {
+			err = rootMetadata.AddGlobalRule(globalRule)
+		}
+		if err != nil {
+			return fmt.Errorf("unable to apply global rule '%s': %w", summary.Name, err)
+		}
+	}
+
+	for _, name := range slices.Sorted(maps.Keys(currentGlobalRules)) {
+		if declaredGlobalRules.Has(name) {
+			continue
+		}
+		if err := rootMetadata.DeleteGlobalRule(name); err != nil {
+			return fmt.Errorf("unable to remove global rule '%s': %w", name, err)
+		}
+	}
+
+	return nil
+}
+
+// applyRoleTrust updates the principals trusted for a role and its threshold.
+// Principals are added before the threshold is updated, and removed after, so
+// that the threshold can be met at each step.
+func applyRoleTrust(principals map[string]tuf.Principal, principalIDs []string, threshold int, getPrincipals func() ([]tuf.Principal, error), getThreshold func() (int, error), addPrincipal func(tuf.Principal) error, deletePrincipal func(string) error, updateThreshold func(int) error) error {
+	currentIDs, currentThreshold, err := getRoleTrust(getPrincipals, getThreshold)
+	if err != nil {
+		return err
+	}
+
+	declaredIDs := set.NewSetFromItems(principalIDs...)
+	for _, principalID := range principalIDs {
+		principal, has := principals[principalID]
+		if !has {
+			return fmt.Errorf("%w: principal '%s' is not defined", ErrInvalidDocument, principalID)
+		}
+
+		if err := addPrincipal(principal); err != nil {
+			return err
+		}
+	}
+
+	if threshold != currentThreshold {
+		if err := updateThreshold(threshold); err != nil {
+			return err
+		}
+	}
+
+	for _, principalID := range sortedPrincipalIDs(currentIDs.Minus(declaredIDs)) {
+		if err := deletePrincipal(principalID); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// newGlobalRule returns the global rule described by the summary.
+func newGlobalRule(summary *GlobalRuleSummary) (tuf.GlobalRule, error) {
+	switch summary.Type {
+	case tuf.GlobalRuleThresholdType:
+		return tufv04.NewGlobalRuleThreshold(summary.Name, summary.Namespaces, summary.Threshold)
+	case tuf.GlobalRuleBlockForcePushesType:
+		return tufv04.NewGlobalRuleBlockForcePushes(summary.Name, summary.Namespaces)
+	case tuf.GlobalRuleRequireSignedCommitsType:
+		return tufv04.NewGlobalRuleRequireSignedCommits(summary.Name, summary.Namespaces)
+	case tuf.GlobalRuleMergeStrategyType:
+		return tufv04.NewGlobalRuleMergeStrategy(summary.Name, summary.Namespaces, summary.Strategy)
+	case tuf.GlobalRuleRequireHookExecutionType:
+		return tufv04.NewGlobalRuleRequireHookExecution(summary.Name, summary.Namespaces, summary.Hooks)
+	default:
+		return nil, fmt.Errorf("%w: unknown type '%s' for global rule '%s'", ErrInvalidDocument, summary.Type, summary.Name)
+	}
+}
+
+// applyRuleFileDocument updates the rule file metadata to match the document.
+// As the mutators cannot change whether a rule is terminating, a document that
+// requires it is rejected.
+func applyRuleFileDocument(targetsMetadata tuf.TargetsMetadata, ruleFile *RuleFileDocument) error {
+	if ruleFile.Expires != "" {
+		targetsMetadata.SetExpires(ruleFile.Expires)
+	}
+
+	for _, principalID := range slices.Sorted(maps.Keys(ruleFile.Principals)) {
+		if err := targetsMetadata.AddPrincipal(ruleFile.Principals[principalID]); err != nil {
+			return err
+		}
+	}
+
+	currentRules := map[string]*RuleSummary{}
+	for _, rule := range summarizeRules(targetsMetadata) {
+		currentRules[rule.Name] = rule
+	}
+
+	ruleNames := []string{}
+	for _, rule := range ruleFile.Rules {
+		ruleNames = append(ruleNames, rule.Name)
+
+		principalIDs := slices.Clone(rule.Principals)
+		slices.Sort(principalIDs)
+
+		current, has := currentRules[rule.Name]
+		if rule.Terminating != (has && current.Terminating) {
+			return fmt.Errorf("%w: terminating setting of rule '%s' cannot be changed", ErrInvalidDocument, rule.Name)
+		}
+
+		// Team thresholds cannot be removed from a rule, so the rule is
+		// recreated when the document drops any of them
+		if has {
+			for teamID := range current.TeamThresholds {
+				if _, declared := rule.TeamThresholds[teamID]; !declared {
+					if err := targetsMetadata.RemoveRule(rule.Name); err != nil {
+						return err
+					}
+					has = false
+					break
+				}
+			}
+		}
+
+		// The actions are cleared before the patterns are updated as they
+		// constrain which patterns the rule may have
+		var currentActions []tuf.RefAction
+		if has {
+			currentActions = current.Actions
+		}
+		actionsChanged := !slices.Equal(rule.Actions, currentActions)
+		if actionsChanged && len(currentActions) != 0 {
+			if err := targetsMetadata.UpdateRuleActions(rule.Name, nil); err != nil {
+				return fmt.Errorf("unable to update actions of rule '%s': %w", rule.Name, err)
+			}
+		}
+
+		switch {
+		case !has:
+			if err := targetsMetadata.AddRule(rule.Name, principalIDs, rule.Patterns, rule.Threshold); err != nil {
+				return fmt.Errorf("unable to add rule '%s': %w", rule.Name, err)
+			}
+		case !slices.Equal(current.Patterns, rule.Patterns) || !slices.Equal(current.Principals, principalIDs) || current.Threshold != rule.Threshold:
+			if err := targetsMetadata.UpdateRule(rule.Name, principalIDs, rule.Patterns, rule.Threshold); err != nil {
+				return fmt.Errorf("unable to update rule '%s': %w", rule.Name, err)
+			}
+		}
+
+		for _, teamID := range slices.Sorted(maps.Keys(rule.TeamThresholds)) {
+			if has && current.TeamThresholds[teamID] == rule.TeamThresholds[teamID] {
+				continue
+			}
+			if err := targetsMetadata.UpdateRuleTeamThreshold(rule.Name, teamID, rule.TeamThresholds[teamID]); err != nil {
+				return fmt.Errorf("unable to update threshold of team '%s' for rule '%s': %w", teamID, rule.Name, err)
+			}
+		}
+
+		if actionsChanged && len(rule.Actions) != 0 {
+			if err := targetsMetadata.UpdateRuleActions(rule.Name, rule.Actions); err != nil {
+				return fmt.Errorf("unable to update actions of rule '%s': %w", rule.Name, err)
+			}
+		}
+
+		var currentValidity *tuf.ValidityWindow
+		if has {
+			currentValidity = current.Validity
+		}
+		if !rule.Validity.Equal(currentValidity) {
+			if err := targetsMetadata.UpdateRuleValidityWindow(rule.Name, rule.Validity); err != nil {
+				return fmt.Errorf("unable to update validity window of rule '%s': %w", rule.Name, err)
+			}
+		}
+	}
+
+	for _, name := range slices.Sorted(maps.Keys(currentRules)) {
+		if slices.Contains(ruleNames, name) {
+			continue
+		}
+		if err := targetsMetadata.RemoveRule(name); err != nil {
+			return fmt.Errorf("unable to remove rule '%s': %w", name, err)
+		}
+	}
+
+	if err := targetsMetadata.ReorderRules(ruleNames); err != nil {
+		return err
+	}
+
+	for _, principalID := range slices.Sorted(maps.Keys(targetsMetadata.GetPrincipals())) {
+		if _, declared := ruleFile.Principals[principalID]; declared {
+			continue
+		}
+		if err := targetsMetadata.RemovePrincipal(principalID); err != nil {
+			return fmt.Errorf("unable to remove principal '%s': %w", principalID, err)
+		}
+	}
+
+	currentPrincipalValidity := targetsMetadata.GetPrincipalValidityWindows()
+	for _, principalID := range slices.Sorted(maps.Keys(currentPrincipalValidity)) {
+		if _, declared := ruleFile.PrincipalValidity[principalID]; declared {
+			continue
+		}
+		if err := targetsMetadata.UpdatePrincipalValidityWindow(principalID, nil); err != nil {
+			return fmt.Errorf("unable to update validity window of principal '%s': %w", principalID, err)
+		}
+	}
+	for _, principalID := range slices.Sorted(maps.Keys(ruleFile.PrincipalValidity)) {
+		if ruleFile.PrincipalValidity[principalID].Equal(targetsMetadata.GetPrincipalValidityWindows()[principalID]) {
+			continue
+		}
+		if err := targetsMetadata.UpdatePrincipalValidityWindow(principalID, ruleFile.PrincipalValidity[principalID]); err != nil {
+			return fmt.Errorf("unable to update validity window of principal '%s': %w", principalID, err)
+		}
+	}
+
+	return nil
+}
+
+// unmarshalDocumentPrincipals decodes the serialized principals in a policy
+// document.
+func unmarshalDocumentPrincipals(serialized map[string]json.RawMessage) (map[string]tuf.Principal, error) {
+	principals := map[string]tuf.Principal{}
+	for principalID, principalBytes := range serialized {
+		principal, err := tufv04.UnmarshalPrincipal(principalBytes)
+		if err != nil {
+			return nil, err
+		}
+		if principal.ID() != principalID {
+			return nil, fmt.Errorf("%w: principal '%s' is declared with ID '%s'", ErrInvalidDocument, principalID, principal.ID())
+		}
+
+		principals[principalID] = principal
+	}
+
+	return principals, nil
+}
@@ -0,0 +1,223 @@
+// Copyright The gittuf Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package policy
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/gittuf/gittuf/internal/dev"
+	"github.com/gittuf/gittuf/internal/signerverifier/gpg"
+	"github.com/gittuf/gittuf/internal/signerverifier/ssh"
+	"github.com/gittuf/gittuf/internal/tuf"
+	tufv01 "github.com/gittuf/gittuf/internal/tuf/v01"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStateDocument(t *testing.T) {
+	gpgKeyR, err := gpg.LoadGPGKeyFromBytes(gpgPubKeyBytes)
+	require.Nil(t, err)
+	gpgKey := tufv01.NewKeyFromSSLibKey(gpgKeyR)
+	targets1Key := tufv01.NewKeyFromSSLibKey(ssh.NewKeyFromBytes(t, targets1PubKeyBytes))
+
+	// roundtrip serializes the state's document and decodes it again
+	roundtrip := func(t *testing.T, state *State) *Document {
+		t.Helper()
+
+		document, err := state.ToDocument()
+		require.Nil(t, err)
+
+		documentBytes, err := json.Marshal(document)
+		require.Nil(t, err)
+
+		decoded := &Document{}
+		require.Nil(t, json.Unmarshal(documentBytes, decoded))
+		return decoded
+	}
+
+	t.Run("export", func(t *testing.T) {
+		state := createTestStateWithDelegatedPolicies(t)
+
+		document, err := state.ToDocument()
+		require.Nil(t, err)
+
+		rootMetadata, err := state.GetRootMetadata(false)
+		require.Nil(t, err)
+		rootPrincipals, err := rootMetadata.GetRootPrincipals()
+		require.Nil(t, err)
+		rootKeyID := rootPrincipals[0].ID()
+
+		assert.Equal(t, []string{rootKeyID}, document.Root.RootPrincipals)
+		assert.Equal(t, 1, document.Root.RootThreshold)
+		assert.Equal(t, []string{rootKeyID}, document.Root.PrimaryRuleFilePrincipals)
+		assert.Equal(t, 1, document.Root.PrimaryRuleFileThreshold)
+		assert.Contains(t, document.Root.Principals, rootKeyID)
+
+		assert.Len(t, document.RuleFiles, 2)
+		assert.Equal(t, []*RuleSummary{
+			{Name: "1", Patterns: []string{"file:1/*"}, Principals: []string{rootKeyID}, Threshold: 1},
+			{Name: "2", Patterns: []string{"file:2/*"}, Principals: []string{rootKeyID}, Threshold: 1},
+		}, document.RuleFiles[TargetsRoleName].Rules)
+		assert.Contains(t, document.RuleFiles["1"].Principals, gpgKey.KeyID)
+	})
+
+	t.Run("apply unchanged document", func(t *testing.T) {
+		state := createTestStateWithDelegatedPolicies(t)
+		document := roundtrip(t, state)
+
+		updated, removed, err := state.ApplyDocument(document)
+		require.Nil(t, err)
+		assert.Empty(t, updated)
+		assert.Empty(t, removed)
+	})
+
+	t.Run("apply team policy roundtrip", func(t *testing.T) {
+		t.Setenv(dev.DevModeKey, "1")
+
+		state := createTestStateWithTeamPolicy(t)
+		document := roundtrip(t, state)
+		assert.Equal(t, map[string]int{"dev": 2}, document.RuleFiles[TargetsRoleName].Rules[0].TeamThresholds)
+
+		updated, removed, err := state.ApplyDocument(document)
+		require.Nil(t, err)
+		assert.Empty(t, updated)
+		assert.Empty(t, removed)
+
+		// Dropping the team threshold recreates the rule in place
+		document.RuleFiles[TargetsRoleName].Rules[0].TeamThresholds = nil
+		updated, _, err = state.ApplyDocument(document)
+		require.Nil(t, err)
+		assert.Equal(t, []string{TargetsRoleName}, updated)
+
+		updatedDocument := roundtrip(t, state)
+		assert.Equal(t, "protect-main", updatedDocument.RuleFiles[TargetsRoleName].Rules[0].Name)
+		assert.Nil(t, updatedDocument.RuleFiles[TargetsRoleName].Rules[0].TeamThresholds)
+	})
+
+	t.Run("apply reference actions roundtrip", func(t *testing.T) {
+		t.Setenv(dev.DevModeKey, "1")
+
+		state := createTestStateWithRefActionPolicy(t)
+		document := roundtrip(t, state)
+		assert.Equal(t, []tuf.RefAction{tuf.RefActionDelete}, document.RuleFiles[TargetsRoleName].Rules[0].Actions)
+
+		updated, removed, err := state.ApplyDocument(document)
+		require.Nil(t, err)
+		assert.Empty(t, updated)
+		assert.Empty(t, removed)
+
+		document.RuleFiles[TargetsRoleName].Rules[0].Actions = []tuf.RefAction{tuf.RefActionNonFastForward, tuf.RefActionDelete}
+		updated, _, err = state.ApplyDocument(document)
+		require.Nil(t, err)
+		assert.Equal(t, []string{TargetsRoleName}, updated)
+
+		updatedDocument := roundtrip(t, state)
+		assert.Equal(t, []tuf.RefAction{tuf.RefActionNonFastForward, tuf.RefActionDelete}, updatedDocument.RuleFiles[TargetsRoleName].Rules[0].Actions)
+
+		// Actions can't be set for rules that protect files
+		document.RuleFiles[TargetsRoleName].Rules[0].Patterns = []string{"file:*"}
+		_, _, err = state.ApplyDocument(document)
+		assert.ErrorIs(t, err, tuf.ErrRefActionsOnlyApplyToGitPaths)
+	})
+
+	t.Run("apply changes", func(t *testing.T) {
+		state := createTestStateWithDelegatedPolicies(t)
+		document := roundtrip(t, state)
+
+		// Add a root principal and raise the threshold
+		document.Root.Principals[targets1Key.KeyID] = targets1Key
+		document.Root.RootPrincipals = append(document.Root.RootPrincipals, targets1Key.KeyID)
+		document.Root.RootThreshold = 2
+		document.Root.GlobalRules = []*GlobalRuleSummary{{Name: "block-force-pushes-main", Type: tuf.GlobalRuleBlockForcePushesType, Namespaces: []string{"git:refs/heads/main"}}}
+
+		// Add a rule to the primary rule file, reorder its rules, and remove
+		// rule file 1
+		primaryRuleFile := document.RuleFiles[TargetsRoleName]
+		primaryRuleFile.Principals[targets1Key.KeyID] = targets1Key
+		primaryRuleFile.Rules = append([]*RuleSummary{
+			{Name: "protect-main", Patterns: []string{"git:refs/heads/main"}, Principals: []string{targets1Key.KeyID}, Threshold: 1},
+		}, primaryRuleFile.Rules[1], primaryRuleFile.Rules[0])
+		delete(document.RuleFiles, "1")
+
+		// Add rule file 2
+		document.RuleFiles["2"] = &RuleFileDocument{
+			Expires:    "2030-01-01T00:00:00Z",
+			Principals: map[string]tuf.Principal{gpgKey.KeyID: gpgKey},
+			Rules: []*RuleSummary{
+				{Name: "5", Patterns: []string{"file:2/subpath/*"}, Principals: []string{gpgKey.KeyID}, Threshold: 1},
+			},
+		}
+
+		updated, removed, err := state.ApplyDocument(document)
+		require.Nil(t, err)
+		assert.Equal(t, []string{RootRoleName, "2", TargetsRoleName}, updated)
+		assert.Equal(t, []string{"1"}, removed)
+
+		rootMetadata, err := state.GetRootMetadata(false)
+		require.Nil(t, err)
+		rootThreshold, err := rootMetadata.GetRootThreshold()
+		require.Nil(t, err)
+		assert.Equal(t, 2, rootThreshold)
+		assert.Len(t, rootMetadata.GetGlobalRules(), 1)
+
+		targetsMetadata, err := state.GetTargetsMetadata(TargetsRoleName, false)
+		require.Nil(t, err)
+		ruleNames := []string{}
+		for _, rule := range targetsMetadata.GetRules() {
+			ruleNames = append(ruleNames, rule.ID())
+		}
+		assert.Equal(t, []string{"protect-main", "2", "1", tuf.AllowRuleName}, ruleNames)
+
+		assert.True(t, state.HasTargetsRole("2"))
+		assert.False(t, state.HasTargetsRole("1"))
+
+		// The applied state exports the same document
+		assert.Equal(t, document, roundtrip(t, state))
+	})
+
+	t.Run("remove principals", func(t *testing.T) {
+		state := createTestStateWithDelegatedPolicies(t)
+		document := roundtrip(t, state)
+
+		document.RuleFiles["1"].Rules = document.RuleFiles["1"].Rules[:1]
+		document.RuleFiles["1"].Principals = map[string]tuf.Principal{}
+
+		// The remaining rule still uses the principal
+		_, _, err := state.ApplyDocument(document)
+		assert.ErrorIs(t, err, tuf.ErrPrincipalStillInUse)
+	})
+
+	t.Run("invalid documents", func(t *testing.T) {
+		state := createTestStateWithDelegatedPolicies(t)
+
+		_, _, err := state.ApplyDocument(&Document{})
+		assert.ErrorIs(t, err, ErrInvalidDocument)
+
+		document := roundtrip(t, state)
+		document.RuleFiles["1"].Rules[0].Name = "1"
+		_, _, err = state.ApplyDocument(document)
+		assert.ErrorIs(t, err, tuf.ErrDuplicatedRuleName)
+
+		document = roundtrip(t, state)
+		document.RuleFiles["1"].Rules[0].Terminating = true
+		_, _, err = state.ApplyDocument(document)
+		assert.ErrorIs(t, err, ErrInvalidDocument)
+
+		document = roundtrip(t, state)
+		delete(document.RuleFiles, TargetsRoleName)
+		_, _, err = state.ApplyDocument(document)
+		assert.ErrorIs(t, err, ErrInvalidDocument)
+
+		document = roundtrip(t, state)
+		document.Root.RootPrincipals = append(document.Root.RootPrincipals, "unknown")
+		_, _, err = state.ApplyDocument(document)
+		assert.ErrorIs(t, err, ErrInvalidDocument)
+
+		document = roundtrip(t, state)
+		document.Root.GlobalRules = []*GlobalRuleSummary{{Name: "unknown", Type: "unknown"}}
+		_, _, err = state.ApplyDocument(document)
+		assert.ErrorIs(t, err, ErrInvalidDocument)
+	})
+}
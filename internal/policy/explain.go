@@ -0,0 +1,250 @@
+// Copyright The gittuf Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package policy
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/gittuf/gittuf/internal/tuf"
+)
+
+var ErrInvalidNamespace = errors.New("namespace must be prefixed with 'git:' or 'file:'")
+
+// PathExplanation records how the verifiers for a namespace are determined
+// from a policy state.
+type PathExplanation struct {
+	Path string `json:"path"`
+
+	// Steps records the rules visited while walking the delegation graph, in
+	// the order they are visited.
+	Steps []*DelegationStep `json:"steps"`
+
+	// Verifiers records the verifiers that apply to the namespace, in the
+	// order they are used during verification.
+	Verifiers []*VerifierSummary `json:"verifiers"`
+
+	// GlobalRules records the global rules that apply to the namespace.
+	GlobalRules []*ApplicableGlobalRule `json:"globalRules,omitempty"`
+}
+
+// DelegationStep records a single rule visited while walking the delegation
+// graph for a namespace.
+type DelegationStep struct {
+	RuleFile string   `json:"ruleFile"`
+	Depth    int      `json:"depth"`
+	Rule     string   `json:"rule"`
+	Patterns []string `json:"patterns"`
+	Matched  bool     `json:"matched"`
+
+	// Delegated is set when the rule matched and the rule file it delegates
+	// to is walked after the remaining rules in the current rule file.
+	Delegated bool `json:"delegated,omitempty"`
+
+	// Terminating is set when the matching rule is terminating and stopped
+	// the search in its rule file. SkippedRules records the rules in the rule
+	// file that were not visited as a result.
+	Terminating  bool     `json:"terminating,omitempty"`
+	SkippedRules []string `json:"skippedRules,omitempty"`
+}
+
+// VerifierSummary describes a verifier that applies to a namespace.
+type VerifierSummary struct {
+	Name       string         `json:"name"`
+	Principals []string       `json:"principals"`
+	Threshold  int            `json:"threshold"`
+	Teams      map[string]int `json:"teams,omitempty"`
+
+	// Exhaustive is set for the verifier that checks every signature when
+	// global rules are declared.
+	Exhaustive bool `json:"exhaustive,omitempty"`
+}
+
+// ApplicableGlobalRule describes a global rule that applies to a namespace.
+// Controller is set when the global rule is declared by a controller
+// repository.
+type ApplicableGlobalRule struct {
+	*GlobalRuleSummary
+	Controller string `json:"controller,omitempty"`
+}
+
+// ExplainPath returns the rules visited while determining the verifiers for
+// the namespace, the resulting verifiers, and the global rules that apply to
+// it. The namespace must be prefixed with "git:" or "file:".
+func (s *State) ExplainPath(path string) (*PathExplanation, error) {
+	if !strings.HasPrefix(path, gitReferenceRuleScheme+":") && !strings.HasPrefix(path, fileRuleScheme+":") {
+		return nil, ErrInvalidNamespace
+	}
+
+	explanation := &PathExplanation{Path: path, Steps: []*DelegationStep{}, Verifiers: []*VerifierSummary{}}
+
+	// The verifiers are determined as they are during verification, and the
+	// delegation graph is walked again to record the rules visited as the
+	// verifiers for the namespace may already be cached
+	verifiers, err := s.FindVerifiersForPath(path)
+	if err != nil {
+		return nil, err
+	}
+	trace := &delegationTrace{steps: []*DelegationStep{}}
+	if _, err := s.walkDelegationsForPath(path, "", time.Time{}, trace); err != nil {
+		return nil, err
+	}
+	explanation.Steps = trace.steps
+
+	for _, verifier := range verifiers {
+		summary := &VerifierSummary{
+			Name:       verifier.Name(),
+			Principals: sortedPrincipalIDs(verifier.TrustedPrincipalIDs()),
+			Threshold:  verifier.Threshold(),
+			Exhaustive: verifier.verifyExhaustively,
+		}
+		if len(verifier.teams) != 0 {
+			summary.Teams = map[string]int{}
+			for teamID, team := range verifier.teams {
+				summary.Teams[teamID] = team.threshold
+			}
+		}
+		explanation.Verifiers = append(explanation.Verifiers, summary)
+	}
+
+	for _, controller := range slices.Sorted(maps.Keys(s.globalRules)) {
+		for _, rule := range s.globalRules[controller] {
+			var matches bool
+			switch rule := rule.(type) {
+			case tuf.GlobalRuleThreshold:
+				matches = rule.Matches(path)
+			case tuf.GlobalRuleRequireSignedCommits:
+				matches = rule.Matches(path)
+			case tuf.GlobalRuleMergeStrategy:
+				matches = rule.Matches(path)
+			case tuf.GlobalRuleRequireHookExecution:
+				matches = rule.Matches(path)
+			case tuf.GlobalRuleBlockForcePushes:
+				matches = rule.Matches(path)
+			}
+			if !matches {
+				continue
+			}
+
+			explanation.GlobalRules = append(explanation.GlobalRules, &ApplicableGlobalRule{
+				GlobalRuleSummary: summarizeGlobalRule(rule),
+				Controller:        controller,
+			})
+		}
+	}
+
+	return explanation, nil
+}
+
+// String returns a human readable description of the explanation.
+func (e *PathExplanation) String() string {
+	var sb strings.Builder
+
+	fmt.Fprintf(&sb, "Delegations visited for '%s':\n", e.Path)
+	if len(e.Steps) == 0 {
+		sb.WriteString("    No rules visited\n")
+	}
+	for _, step := range e.Steps {
+		indent := strings.Repeat("    ", step.Depth+1)
+
+		outcome := "no match"
+		if step.Matched {
+			outcome = "matched"
+		}
+		fmt.Fprintf(&sb, "%s%s: rule '%s' [%s]: %s\n", indent, step.RuleFile, step.Rule, strings.Join(step.Patterns, ", "), outcome)
+
+		if step.Delegated {
+			fmt.Fprintf(&sb, "%s    Delegates to rule file '%s'\n", indent, step.Rule)
+		}
+		if step.Terminating {
+			fmt.Fprintf(&sb, "%s    Terminating rule, search of rule file '%s' stopped", indent, step.RuleFile)
+			if len(step.SkippedRules) != 0 {
+				fmt.Fprintf(&sb, ", skipped rules: %s", strings.Join(step.SkippedRules, ", "))
+			}
+			sb.WriteString("\n")
+		}
+	}
+
+	sb.WriteString("Verifiers:\n")
+	if len(e.Verifiers) == 0 {
+		sb.WriteString("    None, the namespace is not protected by any rule\n")
+	}
+	for _, verifier := range e.Verifiers {
+		if verifier.Exhaustive {
+			fmt.Fprintf(&sb, "    %s: all principals, used to verify global rules\n", verifier.Name)
+			continue
+		}
+
+		fmt.Fprintf(&sb, "    %s: threshold %d, principals [%s]", verifier.Name, verifier.Threshold, strings.Join(verifier.Principals, ", "))
+		for _, teamID := range slices.Sorted(maps.Keys(verifier.Teams)) {
+			fmt.Fprintf(&sb, ", team %s threshold %d", teamID, verifier.Teams[teamID])
+		}
+		sb.WriteString("\n")
+	}
+
+	sb.WriteString("Global rules:\n")
+	if len(e.GlobalRules) == 0 {
+		sb.WriteString("    None\n")
+	}
+	for _, rule := range e.GlobalRules {
+		fmt.Fprintf(&sb, "    %s: %s", rule.Name, rule.describe())
+		if rule.Controller != "" {
+			fmt.Fprintf(&sb, ", declared by controller %s", rule.Controller)
+		}
+		sb.WriteString("\n")
+	}
+
+	return sb.String()
+}
+
+// delegationTrace records the rules visited while walking the delegation
+// graph. Its methods are safe to call on a nil trace.
+type delegationTrace struct {
+	steps []*DelegationStep
+}
+
+// visit records that the rule in the rule file was visited.
+func (t *delegationTrace) visit(group *delegationGroup, rule tuf.Rule, matched bool) {
+	if t == nil {
+		return
+	}
+
+	t.steps = append(t.steps, &DelegationStep{
+		RuleFile: group.ruleFile,
+		Depth:    group.depth,
+		Rule:     rule.ID(),
+		Patterns: rule.GetProtectedNamespaces(),
+		Matched:  matched,
+	})
+}
+
+// delegate records that the rule file of the last visited rule is walked.
+func (t *delegationTrace) delegate() {
+	if t == nil || len(t.steps) == 0 {
+		return
+	}
+
+	t.steps[len(t.steps)-1].Delegated = true
+}
+
+// stop records that the last visited rule is terminating and that the
+// remaining rules in its rule file are not visited.
+func (t *delegationTrace) stop(remaining []tuf.Rule) {
+	if t == nil || len(t.steps) == 0 {
+		return
+	}
+
+	step := t.steps[len(t.steps)-1]
+	step.Terminating = true
+	for _, rule := range remaining {
+		if rule.ID() == tuf.AllowRuleName {
+			continue
+		}
+		step.SkippedRules = append(step.SkippedRules, rule.ID())
+	}
+}
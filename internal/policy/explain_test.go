@@ -0,0 +1,100 @@
+// Copyright The gittuf Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package policy
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/gittuf/gittuf/internal/tuf"
+	tufv02 "github.com/gittuf/gittuf/internal/tuf/v02"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExplainPath(t *testing.T) {
+	t.Run("delegated rules", func(t *testing.T) {
+		state := createTestStateWithDelegatedPolicies(t)
+
+		// The remaining rules in a rule file are visited before the rules in
+		// the rule files delegated to
+		explanation, err := state.ExplainPath("file:1/subpath1/a")
+		require.Nil(t, err)
+
+		expectedSteps := []*DelegationStep{
+			{RuleFile: TargetsRoleName, Depth: 0, Rule: "1", Patterns: []string{"file:1/*"}, Matched: true, Delegated: true},
+			{RuleFile: TargetsRoleName, Depth: 0, Rule: "2", Patterns: []string{"file:2/*"}, Matched: false},
+			{RuleFile: "1", Depth: 1, Rule: "3", Patterns: []string{"file:1/subpath1/*"}, Matched: true},
+			{RuleFile: "1", Depth: 1, Rule: "4", Patterns: []string{"file:1/subpath2/*"}, Matched: false},
+		}
+		assert.Equal(t, expectedSteps, explanation.Steps)
+
+		require.Len(t, explanation.Verifiers, 2)
+		assert.Equal(t, "1", explanation.Verifiers[0].Name)
+		assert.Equal(t, "3", explanation.Verifiers[1].Name)
+		assert.Equal(t, 1, explanation.Verifiers[1].Threshold)
+		assert.Len(t, explanation.Verifiers[1].Principals, 1)
+		assert.Empty(t, explanation.GlobalRules)
+
+		assert.Contains(t, explanation.String(), "    targets: rule '1' [file:1/*]: matched\n        Delegates to rule file '1'\n    targets: rule '2' [file:2/*]: no match\n        1: rule '3' [file:1/subpath1/*]: matched\n")
+	})
+
+	t.Run("terminating rule", func(t *testing.T) {
+		state := createTestStateWithDelegatedPolicies(t)
+		updateTestTargetsMetadata(t, state, TargetsRoleName, func(targetsMetadata tuf.TargetsMetadata) {
+			targetsMetadata.GetRules()[0].(*tufv02.Delegation).Terminating = true
+		})
+
+		explanation, err := state.ExplainPath("file:1/subpath1/a")
+		require.Nil(t, err)
+
+		require.Len(t, explanation.Steps, 3)
+		assert.True(t, explanation.Steps[0].Terminating)
+		assert.Equal(t, []string{"2"}, explanation.Steps[0].SkippedRules)
+		assert.Equal(t, "4", explanation.Steps[2].Rule)
+		assert.Contains(t, explanation.String(), "Terminating rule, search of rule file 'targets' stopped, skipped rules: 2\n")
+
+		// The terminating rule doesn't match, so the search continues
+		explanation, err = state.ExplainPath("file:2/a")
+		require.Nil(t, err)
+		require.Len(t, explanation.Steps, 2)
+		assert.False(t, explanation.Steps[0].Matched)
+		assert.False(t, explanation.Steps[0].Terminating)
+		assert.True(t, explanation.Steps[1].Matched)
+		require.Len(t, explanation.Verifiers, 1)
+		assert.Equal(t, "2", explanation.Verifiers[0].Name)
+	})
+
+	t.Run("unprotected namespace with global rule", func(t *testing.T) {
+		state := createTestStateWithGlobalConstraintThreshold(t)
+
+		explanation, err := state.ExplainPath("git:refs/heads/main")
+		require.Nil(t, err)
+
+		assert.Empty(t, explanation.Steps)
+		require.Len(t, explanation.Verifiers, 1)
+		assert.True(t, explanation.Verifiers[0].Exhaustive)
+		require.Len(t, explanation.GlobalRules, 1)
+		assert.Equal(t, "threshold-2-main", explanation.GlobalRules[0].Name)
+		assert.Empty(t, explanation.GlobalRules[0].Controller)
+
+		explanationJSON, err := json.Marshal(explanation)
+		require.Nil(t, err)
+		assert.Contains(t, string(explanationJSON), `"steps":[]`)
+		assert.Contains(t, string(explanationJSON), `"globalRules":[{"name":"threshold-2-main","type":"threshold","namespaces":["git:refs/heads/main"],"threshold":2}]`)
+
+		// The global rule doesn't apply to other namespaces
+		explanation, err = state.ExplainPath("git:refs/heads/feature")
+		require.Nil(t, err)
+		assert.Empty(t, explanation.GlobalRules)
+		assert.Contains(t, explanation.String(), "Global rules:\n    None\n")
+	})
+
+	t.Run("invalid namespace", func(t *testing.T) {
+		state := createTestStateWithPolicy(t)
+
+		_, err := state.ExplainPath("refs/heads/main")
+		assert.ErrorIs(t, err, ErrInvalidNamespace)
+	})
+}
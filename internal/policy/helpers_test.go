@@ -20,6 +20,7 @@ import (
 	"github.com/gittuf/gittuf/internal/tuf"
 	tufv01 "github.com/gittuf/gittuf/internal/tuf/v01"
 	tufv02 "github.com/gittuf/gittuf/internal/tuf/v02"
+	tufv04 "github.com/gittuf/gittuf/internal/tuf/v04"
 )
 
 var (
@@ -153,6 +154,162 @@ func createTestStateWithPolicy(t *testing.T) *State {
 	return state
 }
 
+// createTestStateWithRefActionPolicy creates a policy state where deleting the
+// main branch is protected by `rootPubKeyBytes` while other changes to the main
+// branch are protected by `gpgPubKeyBytes`.
+func createTestStateWithRefActionPolicy(t *testing.T) *State {
+	t.Helper()
+
+	signer := setupSSHKeysForSigning(t, rootKeyBytes, rootPubKeyBytes)
+	key := tufv01.NewKeyFromSSLibKey(signer.MetadataKey())
+
+	rootMetadata, err := InitializeRootMetadata(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := rootMetadata.AddPrimaryRuleFilePrincipal(key); err != nil {
+		t.Fatal(err)
+	}
+
+	rootEnv, err := dsse.CreateEnvelope(rootMetadata)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rootEnv, err = dsse.SignEnvelope(context.Background(), rootEnv, signer)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	gpgKeyR, err := gpg.LoadGPGKeyFromBytes(gpgPubKeyBytes)
+	if err != nil {
+		t.Fatal(err)
+	}
+	gpgKey := tufv01.NewKeyFromSSLibKey(gpgKeyR)
+
+	targetsMetadata := InitializeTargetsMetadata()
+	if err := targetsMetadata.AddPrincipal(key); err != nil {
+		t.Fatal(err)
+	}
+	if err := targetsMetadata.AddPrincipal(gpgKey); err != nil {
+		t.Fatal(err)
+	}
+	if err := targetsMetadata.AddRule("protect-main-deletion", []string{key.KeyID}, []string{"git:refs/heads/main"}, 1); err != nil {
+		t.Fatal(err)
+	}
+	if err := targetsMetadata.UpdateRuleActions("protect-main-deletion", []tuf.RefAction{tuf.RefActionDelete}); err != nil {
+		t.Fatal(err)
+	}
+	if err := targetsMetadata.AddRule("protect-main", []string{gpgKey.KeyID}, []string{"git:refs/heads/main"}, 1); err != nil {
+		t.Fatal(err)
+	}
+	if err := targetsMetadata.UpdateRuleActions("protect-main", []tuf.RefAction{tuf.RefActionCreate, tuf.RefActionFastForward, tuf.RefActionNonFastForward}); err != nil {
+		t.Fatal(err)
+	}
+
+	targetsEnv, err := dsse.CreateEnvelope(targetsMetadata)
+	if err != nil {
+		t.Fatal(err)
+	}
+	targetsEnv, err = dsse.SignEnvelope(context.Background(), targetsEnv, signer)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	state := &State{
+		Metadata: &StateMetadata{
+			RootEnvelope:    rootEnv,
+			TargetsEnvelope: targetsEnv,
+		},
+	}
+
+	if err := state.preprocess(); err != nil {
+		t.Fatal(err)
+	}
+
+	return state
+}
+
+// createTestStateWithAnnotationPolicy creates a policy state where changes to
+// the main branch are protected by `gpgPubKeyBytes`, and skip annotations for
+// the main branch must be signed by both `rootPubKeyBytes` and
+// `gpgPubKeyBytes`. A global rule also applies to the main branch.
+func createTestStateWithAnnotationPolicy(t *testing.T) *State {
+	t.Helper()
+
+	signer := setupSSHKeysForSigning(t, rootKeyBytes, rootPubKeyBytes)
+	key := tufv01.NewKeyFromSSLibKey(signer.MetadataKey())
+
+	rootMetadata, err := InitializeRootMetadata(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := rootMetadata.AddPrimaryRuleFilePrincipal(key); err != nil {
+		t.Fatal(err)
+	}
+
+	// The global rule ensures every principal is trusted by the exhaustive
+	// verifier for the main branch
+	if err := rootMetadata.AddGlobalRule(tufv01.NewGlobalRuleThreshold("threshold-1-main", []string{"git:refs/heads/main"}, 1)); err != nil {
+		t.Fatal(err)
+	}
+
+	rootEnv, err := dsse.CreateEnvelope(rootMetadata)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rootEnv, err = dsse.SignEnvelope(context.Background(), rootEnv, signer)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	gpgKeyR, err := gpg.LoadGPGKeyFromBytes(gpgPubKeyBytes)
+	if err != nil {
+		t.Fatal(err)
+	}
+	gpgKey := tufv01.NewKeyFromSSLibKey(gpgKeyR)
+
+	targetsMetadata := InitializeTargetsMetadata()
+	if err := targetsMetadata.AddPrincipal(key); err != nil {
+		t.Fatal(err)
+	}
+	if err := targetsMetadata.AddPrincipal(gpgKey); err != nil {
+		t.Fatal(err)
+	}
+	if err := targetsMetadata.AddRule("annotate-main", []string{key.KeyID, gpgKey.KeyID}, []string{"git:refs/heads/main"}, 2); err != nil {
+		t.Fatal(err)
+	}
+	if err := targetsMetadata.UpdateRuleActions("annotate-main", []tuf.RefAction{tuf.RefActionAnnotate}); err != nil {
+		t.Fatal(err)
+	}
+	if err := targetsMetadata.AddRule("protect-main", []string{gpgKey.KeyID}, []string{"git:refs/heads/main"}, 1); err != nil {
+		t.Fatal(err)
+	}
+
+	targetsEnv, err := dsse.CreateEnvelope(targetsMetadata)
+	if err != nil {
+		t.Fatal(err)
+	}
+	targetsEnv, err = dsse.SignEnvelope(context.Background(), targetsEnv, signer)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	state := &State{
+		Metadata: &StateMetadata{
+			RootEnvelope:    rootEnv,
+			TargetsEnvelope: targetsEnv,
+		},
+	}
+
+	if err := state.preprocess(); err != nil {
+		t.Fatal(err)
+	}
+
+	return state
+}
+
 // createTestStateWithGlobalConstraintThreshold creates a policy state with no
 // explicit branch protection rules but with a two-approval constraint on
 // changes to the main branch. The two keys trusted are `rootPubKeyBytes` and
@@ -288,6 +445,218 @@ func createTestStateWithGlobalConstraintBlockForcePushes(t *testing.T) *State {
 	return state
 }
 
+// createTestStateWithGlobalConstraintRequireSignedCommits creates a policy
+// state with no explicit branch protection rules but with a rule that requires
+// commits to main to be signed by a principal in the policy.
+func createTestStateWithGlobalConstraintRequireSignedCommits(t *testing.T) *State {
+	t.Helper()
+
+	signer := setupSSHKeysForSigning(t, rootKeyBytes, rootPubKeyBytes)
+	key := tufv01.NewKeyFromSSLibKey(signer.MetadataKey())
+
+	rootMetadata, err := InitializeRootMetadata(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := rootMetadata.AddPrimaryRuleFilePrincipal(key); err != nil {
+		t.Fatal(err)
+	}
+
+	signedCommitsGlobalRule, err := tufv04.NewGlobalRuleRequireSignedCommits("require-signed-commits-main", []string{"git:refs/heads/main"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := rootMetadata.AddGlobalRule(signedCommitsGlobalRule); err != nil {
+		t.Fatal(err)
+	}
+
+	rootEnv, err := dsse.CreateEnvelope(rootMetadata)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rootEnv, err = dsse.SignEnvelope(context.Background(), rootEnv, signer)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	gpgKeyR, err := gpg.LoadGPGKeyFromBytes(gpgPubKeyBytes)
+	if err != nil {
+		t.Fatal(err)
+	}
+	gpgKey := tufv01.NewKeyFromSSLibKey(gpgKeyR)
+
+	targetsMetadata := InitializeTargetsMetadata()
+	if err := targetsMetadata.AddPrincipal(gpgKey); err != nil {
+		t.Fatal(err)
+	}
+
+	targetsEnv, err := dsse.CreateEnvelope(targetsMetadata)
+	if err != nil {
+		t.Fatal(err)
+	}
+	targetsEnv, err = dsse.SignEnvelope(context.Background(), targetsEnv, signer)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	state := &State{
+		Metadata: &StateMetadata{
+			RootEnvelope:    rootEnv,
+			TargetsEnvelope: targetsEnv,
+		},
+	}
+
+	if err := state.preprocess(); err != nil {
+		t.Fatal(err)
+	}
+
+	return state
+}
+
+// createTestStateWithGlobalConstraintMergeStrategy creates a policy state with
+// no explicit branch protection rules but with a rule that requires updates to
+// main to follow the specified merge strategy.
+func createTestStateWithGlobalConstraintMergeStrategy(t *testing.T, strategy string) *State {
+	t.Helper()
+
+	signer := setupSSHKeysForSigning(t, rootKeyBytes, rootPubKeyBytes)
+	key := tufv01.NewKeyFromSSLibKey(signer.MetadataKey())
+
+	rootMetadata, err := InitializeRootMetadata(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := rootMetadata.AddPrimaryRuleFilePrincipal(key); err != nil {
+		t.Fatal(err)
+	}
+
+	mergeStrategyGlobalRule, err := tufv04.NewGlobalRuleMergeStrategy("merge-strategy-main", []string{"git:refs/heads/main"}, strategy)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := rootMetadata.AddGlobalRule(mergeStrategyGlobalRule); err != nil {
+		t.Fatal(err)
+	}
+
+	rootEnv, err := dsse.CreateEnvelope(rootMetadata)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rootEnv, err = dsse.SignEnvelope(context.Background(), rootEnv, signer)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	gpgKeyR, err := gpg.LoadGPGKeyFromBytes(gpgPubKeyBytes)
+	if err != nil {
+		t.Fatal(err)
+	}
+	gpgKey := tufv01.NewKeyFromSSLibKey(gpgKeyR)
+
+	targetsMetadata := InitializeTargetsMetadata()
+	if err := targetsMetadata.AddPrincipal(gpgKey); err != nil {
+		t.Fatal(err)
+	}
+
+	targetsEnv, err := dsse.CreateEnvelope(targetsMetadata)
+	if err != nil {
+		t.Fatal(err)
+	}
+	targetsEnv, err = dsse.SignEnvelope(context.Background(), targetsEnv, signer)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	state := &State{
+		Metadata: &StateMetadata{
+			RootEnvelope:    rootEnv,
+			TargetsEnvelope: targetsEnv,
+		},
+	}
+
+	if err := state.preprocess(); err != nil {
+		t.Fatal(err)
+	}
+
+	return state
+}
+
+// createTestStateWithGlobalConstraintRequireHookExecution creates a policy
+// state with no explicit branch protection rules but with a pre-push hook named
+// "lint" backed by the specified blob, and a rule that requires the hook to have
+// been executed for updates to main.
+func createTestStateWithGlobalConstraintRequireHookExecution(t *testing.T, hookBlobID gitinterface.Hash) *State {
+	t.Helper()
+
+	signer := setupSSHKeysForSigning(t, rootKeyBytes, rootPubKeyBytes)
+	key := tufv01.NewKeyFromSSLibKey(signer.MetadataKey())
+
+	rootMetadata, err := InitializeRootMetadata(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := rootMetadata.AddPrimaryRuleFilePrincipal(key); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := rootMetadata.AddHook([]tuf.HookStage{tuf.HookStagePrePush}, "lint", []string{key.KeyID}, map[string]string{gitinterface.GitBlobHashName: hookBlobID.String()}, tuf.HookEnvironmentLua, 100); err != nil {
+		t.Fatal(err)
+	}
+
+	requireHookExecutionGlobalRule, err := tufv04.NewGlobalRuleRequireHookExecution("require-lint-main", []string{"git:refs/heads/main"}, []string{"lint"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := rootMetadata.AddGlobalRule(requireHookExecutionGlobalRule); err != nil {
+		t.Fatal(err)
+	}
+
+	rootEnv, err := dsse.CreateEnvelope(rootMetadata)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rootEnv, err = dsse.SignEnvelope(context.Background(), rootEnv, signer)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	gpgKeyR, err := gpg.LoadGPGKeyFromBytes(gpgPubKeyBytes)
+	if err != nil {
+		t.Fatal(err)
+	}
+	gpgKey := tufv01.NewKeyFromSSLibKey(gpgKeyR)
+
+	targetsMetadata := InitializeTargetsMetadata()
+	if err := targetsMetadata.AddPrincipal(gpgKey); err != nil {
+		t.Fatal(err)
+	}
+
+	targetsEnv, err := dsse.CreateEnvelope(targetsMetadata)
+	if err != nil {
+		t.Fatal(err)
+	}
+	targetsEnv, err = dsse.SignEnvelope(context.Background(), targetsEnv, signer)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	state := &State{
+		Metadata: &StateMetadata{
+			RootEnvelope:    rootEnv,
+			TargetsEnvelope: targetsEnv,
+		},
+	}
+
+	if err := state.preprocess(); err != nil {
+		t.Fatal(err)
+	}
+
+	return state
+}
+
 func createTestStateWithPolicyUsingPersons(t *testing.T) *State {
 	t.Helper()
 
@@ -463,6 +832,54 @@ func createTestStateWithDelegatedPolicies(t *testing.T) *State {
 	return curState
 }
 
+// createTestRepositoryWithReaddedRuleFile creates a repository whose policy
+// states raise rule file "1" to version 2, remove it, and then add back the
+// original copy at version 1. The policy entries are returned in RSL order.
+func createTestRepositoryWithReaddedRuleFile(t *testing.T) (*gitinterface.Repository, []rsl.ReferenceUpdaterEntry) {
+	t.Helper()
+
+	repo, state := createTestRepository(t, createTestStateWithDelegatedPolicies)
+
+	updatedState := createTestStateWithDelegatedPolicies(t)
+	updateTestTargetsMetadata(t, updatedState, "1", func(targetsMetadata tuf.TargetsMetadata) {
+		targetsMetadata.IncrementMetadataVersion()
+	})
+
+	removedState := createTestStateWithDelegatedPolicies(t)
+	delete(removedState.Metadata.DelegationEnvelopes, "1")
+
+	readdedState := createTestStateWithDelegatedPolicies(t)
+
+	policyEntries := []rsl.ReferenceUpdaterEntry{state.loadedEntry}
+	for _, newState := range []*State{updatedState, removedState, readdedState} {
+		if err := newState.preprocess(); err != nil {
+			t.Fatal(err)
+		}
+		if err := newState.Commit(repo, "Update test state", false, false); err != nil {
+			t.Fatal(err)
+		}
+
+		policyTip, err := repo.GetReference(PolicyStagingRef)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := repo.SetReference(PolicyRef, policyTip); err != nil {
+			t.Fatal(err)
+		}
+		if err := rsl.NewReferenceEntry(PolicyRef, policyTip).Commit(repo, false); err != nil {
+			t.Fatal(err)
+		}
+
+		entry, _, err := rsl.GetLatestReferenceUpdaterEntry(repo, rsl.ForReference(PolicyRef))
+		if err != nil {
+			t.Fatal(err)
+		}
+		policyEntries = append(policyEntries, entry)
+	}
+
+	return repo, policyEntries
+}
+
 func createTestStateWithThresholdPolicy(t *testing.T) *State {
 	t.Helper()
 
@@ -508,6 +925,71 @@ func createTestStateWithThresholdPolicy(t *testing.T) *State {
 	return state
 }
 
+// createTestStateWithTeamPolicy sets up a test policy where the main branch is
+// protected by a rule that requires approvals from two teams.
+//
+// Usage notes:
+//   - The team "dev" has gpgPubKeyBytes and targets1PubKeyBytes as members,
+//     and requires both members to approve for the rule
+//   - The team "security" has targets2PubKeyBytes as its sole member
+//   - The rule requires both teams to approve
+func createTestStateWithTeamPolicy(t *testing.T) *State {
+	t.Helper()
+
+	state := createTestStateWithPolicy(t)
+
+	gpgKeyR, err := gpg.LoadGPGKeyFromBytes(gpgPubKeyBytes)
+	if err != nil {
+		t.Fatal(err)
+	}
+	gpgKey := tufv04.NewKeyFromSSLibKey(gpgKeyR)
+	approverKey := tufv04.NewKeyFromSSLibKey(ssh.NewKeyFromBytes(t, targets1PubKeyBytes))
+	securityKey := tufv04.NewKeyFromSSLibKey(ssh.NewKeyFromBytes(t, targets2PubKeyBytes))
+
+	devTeam, err := tufv04.NewTeam("dev", []tuf.Principal{gpgKey, approverKey}, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	securityTeam, err := tufv04.NewTeam("security", []tuf.Principal{securityKey}, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	targetsMetadata, err := state.GetTargetsMetadata(TargetsRoleName, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := targetsMetadata.AddPrincipal(devTeam); err != nil {
+		t.Fatal(err)
+	}
+	if err := targetsMetadata.AddPrincipal(securityTeam); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := targetsMetadata.UpdateRule("protect-main", []string{devTeam.TeamID, securityTeam.TeamID}, []string{"git:refs/heads/main"}, 2); err != nil {
+		t.Fatal(err)
+	}
+	if err := targetsMetadata.UpdateRuleTeamThreshold("protect-main", devTeam.TeamID, 2); err != nil {
+		t.Fatal(err)
+	}
+
+	targetsEnv, err := dsse.CreateEnvelope(targetsMetadata)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	signer := setupSSHKeysForSigning(t, rootKeyBytes, rootPubKeyBytes)
+
+	targetsEnv, err = dsse.SignEnvelope(context.Background(), targetsEnv, signer)
+	if err != nil {
+		t.Fatal(err)
+	}
+	state.Metadata.TargetsEnvelope = targetsEnv
+
+	return state
+}
+
 // createTestStateWithThresholdPolicyAndGitHubAppTrust sets up a test policy
 // with threshold rules. It uses v0.2 (and higher) policy metadata to support
 // GitHub apps.
@@ -0,0 +1,233 @@
+// Copyright The gittuf Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package policy
+
+import (
+	"maps"
+	"slices"
+	"sync"
+
+	sslibdsse "github.com/gittuf/gittuf/internal/third_party/go-securesystemslib/dsse"
+	"github.com/gittuf/gittuf/internal/tuf"
+)
+
+// ruleIndex is a precompiled index of the rule files in a State. It records
+// the decoded contents of each rule file, and indexes the rules in each rule
+// file by the literal prefixes of their patterns so that the delegation graph
+// can be walked for a path without matching the path against every rule.
+//
+// The index only narrows down the rules that may match a path: each candidate
+// rule is still matched against the path, in the order the rules are listed
+// in the rule file, so the ordered and terminating semantics of the walk are
+// unchanged.
+type ruleIndex struct {
+	ruleFiles map[string]*indexedRuleFile
+
+	// mutex guards ruleFiles as RSL entries may be verified concurrently
+	// using the same state
+	mutex sync.RWMutex
+}
+
+// indexedRuleFile records the contents of a rule file along with the prefix
+// trie over its rules' patterns.
+type indexedRuleFile struct {
+	// envelope is the envelope the rule file was indexed from, used to detect
+	// when the state's metadata is changed after the index is built
+	envelope *sslibdsse.Envelope
+
+	rules             []tuf.Rule
+	principals        map[string]tuf.Principal
+	principalValidity map[string]*tuf.ValidityWindow
+	keyRotations      []tuf.KeyRotation
+	prefixes          *prefixNode
+}
+
+// prefixNode is a node in a trie over the literal prefixes of patterns. Each
+// node records the indices of the rules with a pattern whose literal prefix
+// ends at the node.
+type prefixNode struct {
+	children    map[byte]*prefixNode
+	ruleIndices []int
+}
+
+// indexRuleFiles indexes every rule file in the state.
+func (s *State) indexRuleFiles() error {
+	ruleFileNames := []string{TargetsRoleName}
+	for name := range s.Metadata.DelegationEnvelopes {
+		ruleFileNames = append(ruleFileNames, name)
+	}
+
+	ruleFiles := make(map[string]*indexedRuleFile, len(ruleFileNames))
+	for _, name := range ruleFileNames {
+		ruleFile, err := s.indexRuleFile(name)
+		if err != nil {
+			return err
+		}
+		ruleFiles[name] = ruleFile
+	}
+
+	s.ruleIndex.mutex.Lock()
+	s.ruleIndex.ruleFiles = ruleFiles
+	s.ruleIndex.mutex.Unlock()
+
+	return nil
+}
+
+// getIndexedRuleFile returns the indexed contents of the rule file. The rule
+// file is indexed again if its envelope in the state has changed since it was
+// indexed.
+func (s *State) getIndexedRuleFile(name string) (*indexedRuleFile, error) {
+	s.ruleIndex.mutex.RLock()
+	ruleFile, has := s.ruleIndex.ruleFiles[name]
+	s.ruleIndex.mutex.RUnlock()
+	if has && ruleFile.envelope == s.getRuleFileEnvelope(name) {
+		return ruleFile, nil
+	}
+
+	ruleFile, err := s.indexRuleFile(name)
+	if err != nil {
+		return nil, err
+	}
+
+	s.ruleIndex.mutex.Lock()
+	if s.ruleIndex.ruleFiles == nil {
+		s.ruleIndex.ruleFiles = map[string]*indexedRuleFile{}
+	}
+	s.ruleIndex.ruleFiles[name] = ruleFile
+	s.ruleIndex.mutex.Unlock()
+
+	return ruleFile, nil
+}
+
+// getRuleFileEnvelope returns the envelope of the rule file in the state.
+func (s *State) getRuleFileEnvelope(name string) *sslibdsse.Envelope {
+	if name == TargetsRoleName {
+		return s.Metadata.TargetsEnvelope
+	}
+	return s.Metadata.DelegationEnvelopes[name]
+}
+
+// indexRuleFile decodes the rule file and indexes its rules.
+func (s *State) indexRuleFile(name string) (*indexedRuleFile, error) {
+	targetsMetadata, err := s.GetTargetsMetadata(name, true) // migrating is fine since this is purely a query
+	if err != nil {
+		return nil, err
+	}
+
+	ruleFile := &indexedRuleFile{
+		envelope:          s.getRuleFileEnvelope(name),
+		rules:             targetsMetadata.GetRules(),
+		principals:        targetsMetadata.GetPrincipals(),
+		principalValidity: targetsMetadata.GetPrincipalValidityWindows(),
+		keyRotations:      targetsMetadata.GetKeyRotations(),
+		prefixes:          &prefixNode{},
+	}
+
+	for index, rule := range ruleFile.rules {
+		for _, pattern := range rule.GetProtectedNamespaces() {
+			// A rule only matches a path if one of its patterns that isn't
+			// negated matches the path
+			if tuf.IsNegatedPattern(pattern) {
+				continue
+			}
+
+			ruleFile.prefixes.insert(literalPrefix(pattern), index)
+		}
+	}
+
+	return ruleFile, nil
+}
+
+// candidateRules returns the rules in the rule file, in order, that may match
+// the path, excluding the rule file's last rule, the allow rule. If all is
+// set, every rule except the allow rule is returned.
+func (r *indexedRuleFile) candidateRules(path string, all bool) []tuf.Rule {
+	if len(r.rules) == 0 {
+		return nil
+	}
+	lastIndex := len(r.rules) - 1
+
+	if all {
+		return r.rules[:lastIndex]
+	}
+
+	ruleIndices := r.prefixes.lookup(path)
+	slices.Sort(ruleIndices)
+	ruleIndices = slices.Compact(ruleIndices)
+
+	candidates := make([]tuf.Rule, 0, len(ruleIndices))
+	for _, index := range ruleIndices {
+		if index != lastIndex {
+			candidates = append(candidates, r.rules[index])
+		}
+	}
+
+	return candidates
+}
+
+// clonePrincipals returns a copy of the rule file's principals that can be
+// extended with the principals of delegated rule files.
+func (r *indexedRuleFile) clonePrincipals() map[string]tuf.Principal {
+	return maps.Clone(r.principals)
+}
+
+// clonePrincipalValidity returns a copy of the validity windows of the
+// principals in the rule file that can be extended while walking delegations.
+func (r *indexedRuleFile) clonePrincipalValidity() map[string]*tuf.ValidityWindow {
+	principalValidity := maps.Clone(r.principalValidity)
+	if principalValidity == nil {
+		principalValidity = map[string]*tuf.ValidityWindow{}
+	}
+	return principalValidity
+}
+
+// insert records the rule index at the node for the prefix.
+func (n *prefixNode) insert(prefix string, ruleIndex int) {
+	node := n
+	for i := 0; i < len(prefix); i++ {
+		if node.children == nil {
+			node.children = map[byte]*prefixNode{}
+		}
+
+		child, has := node.children[prefix[i]]
+		if !has {
+			child = &prefixNode{}
+			node.children[prefix[i]] = child
+		}
+		node = child
+	}
+
+	node.ruleIndices = append(node.ruleIndices, ruleIndex)
+}
+
+// lookup returns the indices of the rules with a pattern whose literal prefix
+// is a prefix of the path.
+func (n *prefixNode) lookup(path string) []int {
+	ruleIndices := slices.Clone(n.ruleIndices)
+
+	node := n
+	for i := 0; i < len(path); i++ {
+		child, has := node.children[path[i]]
+		if !has {
+			break
+		}
+		node = child
+		ruleIndices = append(ruleIndices, node.ruleIndices...)
+	}
+
+	return ruleIndices
+}
+
+// literalPrefix returns the part of the pattern before its first special
+// character. Every path matched by the pattern starts with this prefix.
+func literalPrefix(pattern string) string {
+	for i := 0; i < len(pattern); i++ {
+		switch pattern[i] {
+		case '*', '?', '[', '\\':
+			return pattern[:i]
+		}
+	}
+
+	return pattern
+}
@@ -0,0 +1,341 @@
+// Copyright The gittuf Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package policy
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/gittuf/gittuf/internal/dev"
+	"github.com/gittuf/gittuf/internal/signerverifier/dsse"
+	"github.com/gittuf/gittuf/internal/signerverifier/gpg"
+	"github.com/gittuf/gittuf/internal/signerverifier/ssh"
+	sslibdsse "github.com/gittuf/gittuf/internal/third_party/go-securesystemslib/dsse"
+	"github.com/gittuf/gittuf/internal/tuf"
+	tufv01 "github.com/gittuf/gittuf/internal/tuf/v01"
+	tufv04 "github.com/gittuf/gittuf/internal/tuf/v04"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLiteralPrefix(t *testing.T) {
+	tests := map[string]struct {
+		pattern  string
+		expected string
+	}{
+		"literal pattern":          {pattern: "git:refs/heads/main", expected: "git:refs/heads/main"},
+		"star":                     {pattern: "file:src/*.go", expected: "file:src/"},
+		"double star":              {pattern: "file:src/**", expected: "file:src/"},
+		"double star directory":    {pattern: "file:**/generated/*", expected: "file:"},
+		"question mark":            {pattern: "git:refs/tags/v?", expected: "git:refs/tags/v"},
+		"character class":          {pattern: "git:refs/tags/v[0-9]*", expected: "git:refs/tags/v"},
+		"escaped character":        {pattern: `file:src/\*`, expected: "file:src/"},
+		"only special characters":  {pattern: "**", expected: ""},
+		"empty pattern":            {pattern: "", expected: ""},
+		"special character at end": {pattern: "file:src/a*", expected: "file:src/a"},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, test.expected, literalPrefix(test.pattern))
+		})
+	}
+}
+
+func TestIndexedRuleFileCandidateRules(t *testing.T) {
+	state := createTestStateWithDelegatedPolicies(t)
+	key := tufv01.NewKeyFromSSLibKey(ssh.NewKeyFromBytes(t, rootPubKeyBytes))
+
+	updateTestTargetsMetadata(t, state, TargetsRoleName, func(targetsMetadata tuf.TargetsMetadata) {
+		require.Nil(t, targetsMetadata.AddRule("docs", []string{key.KeyID}, []string{"file:**/*.md"}, 1))
+		require.Nil(t, targetsMetadata.AddRule("src", []string{key.KeyID}, []string{"file:src/**", "!file:1/**"}, 1))
+	})
+
+	ruleFile, err := state.getIndexedRuleFile(TargetsRoleName)
+	require.Nil(t, err)
+
+	tests := map[string]struct {
+		path     string
+		all      bool
+		expected []string
+	}{
+		"candidates for literal prefix": {
+			path:     "file:1/a",
+			expected: []string{"1", "docs"},
+		},
+		"candidates do not include negated patterns": {
+			path:     "file:src/a",
+			expected: []string{"docs", "src"},
+		},
+		"candidates for unprotected namespace": {
+			path:     "git:refs/heads/main",
+			expected: []string{},
+		},
+		"all candidates": {
+			path:     "git:refs/heads/main",
+			all:      true,
+			expected: []string{"1", "2", "docs", "src"},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			ruleNames := []string{}
+			for _, rule := range ruleFile.candidateRules(test.path, test.all) {
+				ruleNames = append(ruleNames, rule.ID())
+			}
+			assert.Equal(t, test.expected, ruleNames)
+		})
+	}
+}
+
+func TestRuleIndexMatchesWalk(t *testing.T) {
+	t.Setenv(dev.DevModeKey, "1")
+
+	state := createTestStateWithDelegatedPolicies(t)
+
+	gpgKeyR, err := gpg.LoadGPGKeyFromBytes(gpgPubKeyBytes)
+	require.Nil(t, err)
+	gpgKey := tufv01.NewKeyFromSSLibKey(gpgKeyR)
+
+	updateTestTargetsMetadata(t, state, TargetsRoleName, func(targetsMetadata tuf.TargetsMetadata) {
+		require.Nil(t, targetsMetadata.AddPrincipal(gpgKey))
+		targetsMetadata.GetRules()[0].(*tufv04.Delegation).Terminating = true
+		require.Nil(t, targetsMetadata.AddRule("after-terminating", []string{gpgKey.KeyID}, []string{"file:1/**"}, 1))
+		require.Nil(t, targetsMetadata.AddRule("docs", []string{gpgKey.KeyID}, []string{"file:**/*.md"}, 1))
+		require.Nil(t, targetsMetadata.AddRule("src", []string{gpgKey.KeyID}, []string{"file:src/**", "!file:src/generated/**"}, 1))
+		require.Nil(t, targetsMetadata.AddRule("tags", []string{gpgKey.KeyID}, []string{"git:refs/tags/v[0-9]*"}, 1))
+		require.Nil(t, targetsMetadata.AddRule("everything", []string{gpgKey.KeyID}, []string{"**"}, 1))
+	})
+	updateTestTargetsMetadata(t, state, "1", func(targetsMetadata tuf.TargetsMetadata) {
+		require.Nil(t, targetsMetadata.AddRule("1-docs", []string{gpgKey.KeyID}, []string{"file:1/**/*.md"}, 1))
+	})
+
+	paths := []string{
+		"file:1/subpath1/a",
+		"file:1/subpath2/b",
+		"file:1/c",
+		"file:1/docs/README.md",
+		"file:2/d",
+		"file:README.md",
+		"file:src/main.go",
+		"file:src/generated/a.go",
+		"git:refs/heads/main",
+		"git:refs/tags/v1.0.0",
+		"git:refs/tags/latest",
+	}
+
+	for _, path := range paths {
+		t.Run(path, func(t *testing.T) {
+			expectedVerifiers, err := walkDelegationsForPathWithoutIndex(state, path)
+			require.Nil(t, err)
+
+			verifiers, err := state.findVerifiersForPathIfProtected(path, "", time.Time{})
+			assert.Nil(t, err)
+			assert.Equal(t, expectedVerifiers, verifiers)
+		})
+	}
+}
+
+func TestRuleIndexUpdatedMetadata(t *testing.T) {
+	state := createTestStateWithDelegatedPolicies(t)
+	key := tufv01.NewKeyFromSSLibKey(ssh.NewKeyFromBytes(t, rootPubKeyBytes))
+
+	verifiers, err := state.findVerifiersForPathIfProtected("file:3/a", "", time.Time{})
+	require.Nil(t, err)
+	assert.Empty(t, verifiers)
+
+	// The index is built when the state is loaded, the rule file must be
+	// indexed again when its envelope is replaced
+	updateTestTargetsMetadata(t, state, TargetsRoleName, func(targetsMetadata tuf.TargetsMetadata) {
+		require.Nil(t, targetsMetadata.AddRule("3", []string{key.KeyID}, []string{"file:3/**"}, 1))
+	})
+
+	verifiers, err = state.findVerifiersForPathIfProtected("file:3/a", "", time.Time{})
+	assert.Nil(t, err)
+	require.Len(t, verifiers, 1)
+	assert.Equal(t, "3", verifiers[0].Name())
+}
+
+// BenchmarkFindVerifiersForPath compares finding the verifiers for a path
+// using the rule index with walking and matching every rule in the delegation
+// graph. The policy has about 1,500 file rules spread across rule files.
+func BenchmarkFindVerifiersForPath(b *testing.B) {
+	const (
+		ruleFileCount     = 50
+		rulesPerRuleFile  = 30
+		pathsPerDirectory = 4
+	)
+
+	state := createBenchmarkState(b, ruleFileCount, rulesPerRuleFile)
+
+	paths := []string{}
+	for i := 0; i < ruleFileCount; i++ {
+		for j := 0; j < rulesPerRuleFile; j++ {
+			for k := 0; k < pathsPerDirectory; k++ {
+				paths = append(paths, fmt.Sprintf("file:pkg/%d/%d/file%d.go", i, j, k))
+			}
+		}
+		paths = append(paths, fmt.Sprintf("file:pkg/%d/README.md", i))
+	}
+	paths = append(paths, "file:README.md", "git:refs/heads/main")
+
+	b.Run("indexed", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			if _, err := state.findVerifiersForPathIfProtected(paths[i%len(paths)], "", time.Time{}); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+
+	b.Run("walk", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			if _, err := walkDelegationsForPathWithoutIndex(state, paths[i%len(paths)]); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
+
+// createBenchmarkState returns a state where the primary rule file delegates
+// "file:pkg/<i>/**" to a rule file that protects "file:pkg/<i>/<j>/**" for
+// each of its rules. The metadata is not signed.
+func createBenchmarkState(b *testing.B, ruleFileCount, rulesPerRuleFile int) *State {
+	b.Helper()
+
+	gpgKeyR, err := gpg.LoadGPGKeyFromBytes(gpgPubKeyBytes)
+	if err != nil {
+		b.Fatal(err)
+	}
+	key := tufv01.NewKeyFromSSLibKey(gpgKeyR)
+
+	rootMetadata, err := InitializeRootMetadata(key)
+	if err != nil {
+		b.Fatal(err)
+	}
+	if err := rootMetadata.AddPrimaryRuleFilePrincipal(key); err != nil {
+		b.Fatal(err)
+	}
+
+	state := &State{
+		Metadata: &StateMetadata{
+			DelegationEnvelopes: map[string]*sslibdsse.Envelope{},
+		},
+	}
+
+	state.Metadata.RootEnvelope, err = dsse.CreateEnvelope(rootMetadata)
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	targetsMetadata := InitializeTargetsMetadata()
+	if err := targetsMetadata.AddPrincipal(key); err != nil {
+		b.Fatal(err)
+	}
+
+	for i := 0; i < ruleFileCount; i++ {
+		ruleFileName := fmt.Sprintf("pkg-%d", i)
+		if err := targetsMetadata.AddRule(ruleFileName, []string{key.KeyID}, []string{fmt.Sprintf("file:pkg/%d/**", i)}, 1); err != nil {
+			b.Fatal(err)
+		}
+
+		delegatedMetadata := InitializeTargetsMetadata()
+		if err := delegatedMetadata.AddPrincipal(key); err != nil {
+			b.Fatal(err)
+		}
+		for j := 0; j < rulesPerRuleFile; j++ {
+			if err := delegatedMetadata.AddRule(fmt.Sprintf("pkg-%d-%d", i, j), []string{key.KeyID}, []string{fmt.Sprintf("file:pkg/%d/%d/**", i, j)}, 1); err != nil {
+				b.Fatal(err)
+			}
+		}
+
+		state.Metadata.DelegationEnvelopes[ruleFileName], err = dsse.CreateEnvelope(delegatedMetadata)
+		if err != nil {
+			b.Fatal(err)
+		}
+	}
+
+	state.Metadata.TargetsEnvelope, err = dsse.CreateEnvelope(targetsMetadata)
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	if err := state.preprocess(); err != nil {
+		b.Fatal(err)
+	}
+
+	return state
+}
+
+// walkDelegationsForPathWithoutIndex finds the verifiers for the path by
+// decoding each rule file and matching the path against every rule in it, as
+// was done before rule files were indexed. It's used to check that the index
+// doesn't change the result of the search, and as the baseline in benchmarks.
+func walkDelegationsForPathWithoutIndex(s *State, path string) ([]*SignatureVerifier, error) {
+	targetsMetadata, err := s.GetTargetsMetadata(TargetsRoleName, true)
+	if err != nil {
+		return nil, err
+	}
+
+	allPrincipals := targetsMetadata.GetPrincipals()
+	keyRotations := groupKeyRotations(nil, allPrincipals, targetsMetadata.GetKeyRotations())
+	groupedDelegations := [][]tuf.Rule{targetsMetadata.GetRules()}
+
+	seenRoles := map[string]bool{TargetsRoleName: true}
+
+	verifiers := []*SignatureVerifier{}
+	for len(groupedDelegations) > 0 {
+		rules := groupedDelegations[0]
+		groupedDelegations = groupedDelegations[1:]
+
+		for len(rules) > 1 {
+			delegation := rules[0]
+			rules = rules[1:]
+
+			if !delegation.Matches(path) {
+				continue
+			}
+
+			verifier := &SignatureVerifier{
+				repository:   s.repository,
+				name:         delegation.ID(),
+				threshold:    delegation.GetThreshold(),
+				keyRotations: keyRotations,
+			}
+			principals := make([]tuf.Principal, 0, delegation.GetPrincipalIDs().Len())
+			for _, principalID := range delegation.GetPrincipalIDs().Contents() {
+				principals = append(principals, allPrincipals[principalID])
+			}
+			verifier.setPrincipals(principals, delegation.GetTeamThresholds())
+			verifiers = append(verifiers, verifier)
+
+			if _, seen := seenRoles[delegation.ID()]; seen {
+				continue
+			}
+
+			if s.HasTargetsRole(delegation.ID()) {
+				delegatedMetadata, err := s.GetTargetsMetadata(delegation.ID(), true)
+				if err != nil {
+					return nil, err
+				}
+
+				seenRoles[delegation.ID()] = true
+
+				for principalID, principal := range delegatedMetadata.GetPrincipals() {
+					allPrincipals[principalID] = principal
+				}
+				keyRotations = groupKeyRotations(keyRotations, delegatedMetadata.GetPrincipals(), delegatedMetadata.GetKeyRotations())
+
+				groupedDelegations = append([][]tuf.Rule{delegatedMetadata.GetRules()}, groupedDelegations...)
+
+				if delegation.IsLastTrustedInRuleFile() {
+					break
+				}
+			}
+		}
+	}
+
+	return verifiers, nil
+}
@@ -0,0 +1,410 @@
+// Copyright The gittuf Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package policy
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/danwakefield/fnmatch"
+	"github.com/gittuf/gittuf/internal/common/set"
+	"github.com/gittuf/gittuf/internal/tuf"
+)
+
+// LintSeverity indicates how severe a problem found by the policy linter is.
+type LintSeverity string
+
+const (
+	// LintSeverityError is used for problems that prevent the policy from
+	// being met or verified.
+	LintSeverityError LintSeverity = "error"
+
+	// LintSeverityWarning is used for problems that are likely unintended but
+	// don't prevent the policy from being met.
+	LintSeverityWarning LintSeverity = "warning"
+)
+
+// LintCheck identifies the check of the policy linter that reported a problem.
+type LintCheck string
+
+const (
+	// LintCheckShadowedRule is reported for rules that are never visited for
+	// the namespaces they protect as a prior terminating rule in the same rule
+	// file stops the search.
+	LintCheckShadowedRule LintCheck = "shadowed-rule"
+
+	// LintCheckUnreachableRule is reported for rules in a delegated rule file
+	// whose patterns don't overlap with those of the rule delegating to it.
+	LintCheckUnreachableRule LintCheck = "unreachable-rule"
+
+	// LintCheckUnsatisfiableThreshold is reported when a threshold exceeds the
+	// number of principals that can approve.
+	LintCheckUnsatisfiableThreshold LintCheck = "unsatisfiable-threshold"
+
+	// LintCheckUnusedPrincipal is reported for principals declared in a rule
+	// file that aren't trusted by any rule.
+	LintCheckUnusedPrincipal LintCheck = "unused-principal"
+
+	// LintCheckMissingRuleFile is reported for terminating rules that delegate
+	// to a rule file that doesn't exist. The search only stops at a
+	// terminating rule when its rule file exists.
+	LintCheckMissingRuleFile LintCheck = "missing-rule-file"
+
+	// LintCheckDanglingRuleFile is reported for rule files that no rule
+	// delegates to.
+	LintCheckDanglingRuleFile LintCheck = "dangling-rule-file"
+
+	// LintCheckGlobalRuleConflict is reported when a global rule requires
+	// more approvals than a rule protecting an overlapping namespace trusts.
+	LintCheckGlobalRuleConflict LintCheck = "global-rule-conflict"
+)
+
+// LintFinding records a problem found in a policy state by the linter.
+type LintFinding struct {
+	Severity LintSeverity `json:"severity"`
+	Check    LintCheck    `json:"check"`
+	RuleFile string       `json:"ruleFile,omitempty"`
+	Rule     string       `json:"rule,omitempty"`
+	Message  string       `json:"message"`
+}
+
+// String returns a human readable description of the finding.
+func (f *LintFinding) String() string {
+	var sb strings.Builder
+
+	fmt.Fprintf(&sb, "%s [%s]", f.Severity, f.Check)
+	if f.RuleFile != "" {
+		fmt.Fprintf(&sb, " rule file '%s'", f.RuleFile)
+	}
+	if f.Rule != "" {
+		fmt.Fprintf(&sb, " rule '%s'", f.Rule)
+	}
+	fmt.Fprintf(&sb, ": %s", f.Message)
+
+	return sb.String()
+}
+
+// HasLintErrors indicates if any of the findings is an error.
+func HasLintErrors(findings []*LintFinding) bool {
+	return slices.ContainsFunc(findings, func(finding *LintFinding) bool {
+		return finding.Severity == LintSeverityError
+	})
+}
+
+// Lint analyzes the rules in the policy state's rule files and its global rules
+// for problems such as rules that can never match, thresholds that can't be
+// met, and unused principals.
+func (s *State) Lint() ([]*LintFinding, error) {
+	l := &linter{state: s, findings: []*LintFinding{}}
+
+	rootMetadata, err := s.GetRootMetadata(false)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := l.lintRoot(rootMetadata); err != nil {
+		return nil, err
+	}
+
+	if err := l.loadRuleFiles(); err != nil {
+		return nil, err
+	}
+
+	for _, ruleFile := range l.ruleFileNames {
+		l.lintRuleFile(ruleFile)
+	}
+	l.lintUnusedPrincipals()
+	l.lintGlobalRules(rootMetadata)
+
+	return l.findings, nil
+}
+
+// linter records the rule files of a policy state being analyzed and the
+// problems found.
+type linter struct {
+	state    *State
+	findings []*LintFinding
+
+	// ruleFileNames records the names of the rule files, with the primary rule
+	// file first
+	ruleFileNames []string
+	rules         map[string][]tuf.Rule
+	principals    map[string]map[string]tuf.Principal
+
+	// allPrincipals records the principals declared across all rule files
+	allPrincipals map[string]tuf.Principal
+}
+
+func (l *linter) report(severity LintSeverity, check LintCheck, ruleFile, rule, message string, args ...any) {
+	l.findings = append(l.findings, &LintFinding{
+		Severity: severity,
+		Check:    check,
+		RuleFile: ruleFile,
+		Rule:     rule,
+		Message:  fmt.Sprintf(message, args...),
+	})
+}
+
+// lintRoot checks that the thresholds for the root of trust and the primary
+// rule file can be met.
+func (l *linter) lintRoot(rootMetadata tuf.RootMetadata) error {
+	rootPrincipals, err := rootMetadata.GetRootPrincipals()
+	if err != nil {
+		return err
+	}
+	rootThreshold, err := rootMetadata.GetRootThreshold()
+	if err != nil {
+		return err
+	}
+	if rootThreshold > len(rootPrincipals) {
+		l.report(LintSeverityError, LintCheckUnsatisfiableThreshold, tuf.RootRoleName, "", "root threshold %d exceeds the %d trusted root principals", rootThreshold, len(rootPrincipals))
+	}
+
+	if !l.state.HasTargetsRole(TargetsRoleName) {
+		return nil
+	}
+
+	primaryRuleFilePrincipals, err := rootMetadata.GetPrimaryRuleFilePrincipals()
+	if err != nil {
+		return err
+	}
+	primaryRuleFileThreshold, err := rootMetadata.GetPrimaryRuleFileThreshold()
+	if err != nil {
+		return err
+	}
+	if primaryRuleFileThreshold > len(primaryRuleFilePrincipals) {
+		l.report(LintSeverityError, LintCheckUnsatisfiableThreshold, tuf.RootRoleName, "", "primary rule file threshold %d exceeds the %d trusted principals", primaryRuleFileThreshold, len(primaryRuleFilePrincipals))
+	}
+
+	return nil
+}
+
+// loadRuleFiles loads the rules and principals of every rule file in the
+// state.
+func (l *linter) loadRuleFiles() error {
+	l.rules = map[string][]tuf.Rule{}
+	l.principals = map[string]map[string]tuf.Principal{}
+	l.allPrincipals = map[string]tuf.Principal{}
+
+	names := ruleFileNames(l.state)
+	slices.SortFunc(names, func(a, b string) int {
+		switch {
+		case a == b:
+			return 0
+		case a == TargetsRoleName:
+			return -1
+		case b == TargetsRoleName:
+			return 1
+		default:
+			return strings.Compare(a, b)
+		}
+	})
+	l.ruleFileNames = names
+
+	for _, name := range names {
+		metadata, err := l.state.GetTargetsMetadata(name, false)
+		if err != nil {
+			return err
+		}
+
+		rules := []tuf.Rule{}
+		for _, rule := range metadata.GetRules() {
+			if rule.ID() == tuf.AllowRuleName {
+				continue
+			}
+			rules = append(rules, rule)
+		}
+		l.rules[name] = rules
+
+		l.principals[name] = metadata.GetPrincipals()
+		for principalID, principal := range l.principals[name] {
+			l.allPrincipals[principalID] = principal
+		}
+	}
+
+	return nil
+}
+
+// lintRuleFile checks each rule in the rule file.
+func (l *linter) lintRuleFile(ruleFile string) {
+	delegatingRules := l.findDelegatingRules(ruleFile)
+	if ruleFile != TargetsRoleName && len(delegatingRules) == 0 {
+		l.report(LintSeverityError, LintCheckDanglingRuleFile, ruleFile, "", "no rule delegates to the rule file")
+	}
+
+	for _, principal := range l.principals[ruleFile] {
+		if team, isTeam := principal.(tuf.Team); isTeam && team.GetThreshold() > len(team.GetMembers()) {
+			l.report(LintSeverityError, LintCheckUnsatisfiableThreshold, ruleFile, "", "team '%s' threshold %d exceeds its %d members", team.ID(), team.GetThreshold(), len(team.GetMembers()))
+		}
+	}
+
+	rules := l.rules[ruleFile]
+	for index, rule := range rules {
+		patterns := rule.GetProtectedNamespaces()
+
+		// A rule in a delegated rule file is only visited for namespaces that
+		// match the rules delegating to it
+		if len(delegatingRules) != 0 && !slices.ContainsFunc(delegatingRules, func(delegatingRule tuf.Rule) bool {
+			return patternsOverlap(delegatingRule.GetProtectedNamespaces(), patterns)
+		}) {
+			l.report(LintSeverityWarning, LintCheckUnreachableRule, ruleFile, rule.ID(), "patterns [%s] don't overlap with the patterns of the rules delegating to the rule file", strings.Join(patterns, ", "))
+		}
+
+		for _, priorRule := range rules[:index] {
+			if !priorRule.IsLastTrustedInRuleFile() || !l.state.HasTargetsRole(priorRule.ID()) {
+				continue
+			}
+
+			if patternsCovered(priorRule, patterns) {
+				l.report(LintSeverityWarning, LintCheckShadowedRule, ruleFile, rule.ID(), "patterns [%s] are shadowed by prior terminating rule '%s'", strings.Join(patterns, ", "), priorRule.ID())
+				break
+			}
+		}
+
+		if rule.IsLastTrustedInRuleFile() && !l.state.HasTargetsRole(rule.ID()) {
+			l.report(LintSeverityWarning, LintCheckMissingRuleFile, ruleFile, rule.ID(), "rule is terminating but the rule file it delegates to doesn't exist, so the search of the rule file doesn't stop at it")
+		}
+
+		trustedPrincipals := 0
+		for _, principalID := range rule.GetPrincipalIDs().Contents() {
+			if _, has := l.allPrincipals[principalID]; has {
+				trustedPrincipals++
+			}
+		}
+		if rule.GetThreshold() > trustedPrincipals {
+			l.report(LintSeverityError, LintCheckUnsatisfiableThreshold, ruleFile, rule.ID(), "threshold %d exceeds the %d principals trusted by the rule", rule.GetThreshold(), trustedPrincipals)
+		}
+
+		for teamID, threshold := range rule.GetTeamThresholds() {
+			team, isTeam := l.allPrincipals[teamID].(tuf.Team)
+			if isTeam && threshold > len(team.GetMembers()) {
+				l.report(LintSeverityError, LintCheckUnsatisfiableThreshold, ruleFile, rule.ID(), "team '%s' threshold %d exceeds its %d members", teamID, threshold, len(team.GetMembers()))
+			}
+		}
+	}
+}
+
+// lintUnusedPrincipals reports the principals declared in each rule file that
+// aren't trusted by any rule, directly or as a member of a trusted team.
+func (l *linter) lintUnusedPrincipals() {
+	usedPrincipalIDs := set.NewSet[string]()
+	for _, rules := range l.rules {
+		for _, rule := range rules {
+			for _, principalID := range rule.GetPrincipalIDs().Contents() {
+				usedPrincipalIDs.Add(principalID)
+				if team, isTeam := l.allPrincipals[principalID].(tuf.Team); isTeam {
+					for _, member := range team.GetMembers() {
+						usedPrincipalIDs.Add(member.ID())
+					}
+				}
+			}
+		}
+	}
+
+	for _, ruleFile := range l.ruleFileNames {
+		principalIDs := []string{}
+		for principalID := range l.principals[ruleFile] {
+			principalIDs = append(principalIDs, principalID)
+		}
+		slices.Sort(principalIDs)
+
+		for _, principalID := range principalIDs {
+			if !usedPrincipalIDs.Has(principalID) {
+				l.report(LintSeverityWarning, LintCheckUnusedPrincipal, ruleFile, "", "principal '%s' isn't trusted by any rule", principalID)
+			}
+		}
+	}
+}
+
+// lintGlobalRules checks that the threshold global rules can be met, and
+// reports rules protecting overlapping namespaces that trust fewer principals
+// than the global rule requires.
+func (l *linter) lintGlobalRules(rootMetadata tuf.RootMetadata) {
+	for _, globalRule := range rootMetadata.GetGlobalRules() {
+		thresholdRule, isThresholdRule := globalRule.(tuf.GlobalRuleThreshold)
+		if !isThresholdRule {
+			continue
+		}
+
+		threshold := thresholdRule.GetThreshold()
+		if threshold > len(l.state.allPrincipals) {
+			l.report(LintSeverityError, LintCheckUnsatisfiableThreshold, tuf.RootRoleName, thresholdRule.GetName(), "global rule threshold %d exceeds the %d principals in the policy", threshold, len(l.state.allPrincipals))
+			continue
+		}
+
+		for _, ruleFile := range l.ruleFileNames {
+			for _, rule := range l.rules[ruleFile] {
+				if !patternsOverlap(thresholdRule.GetProtectedNamespaces(), rule.GetProtectedNamespaces()) {
+					continue
+				}
+
+				if rule.GetPrincipalIDs().Len() < threshold {
+					l.report(LintSeverityWarning, LintCheckGlobalRuleConflict, ruleFile, rule.ID(), "global rule '%s' requires %d approvals but the rule trusts %d principals", thresholdRule.GetName(), threshold, rule.GetPrincipalIDs().Len())
+				}
+			}
+		}
+	}
+}
+
+// findDelegatingRules returns the rules in other rule files that delegate to
+// the specified rule file.
+func (l *linter) findDelegatingRules(ruleFile string) []tuf.Rule {
+	delegatingRules := []tuf.Rule{}
+	for name, rules := range l.rules {
+		if name == ruleFile {
+			continue
+		}
+		for _, rule := range rules {
+			if rule.ID() == ruleFile {
+				delegatingRules = append(delegatingRules, rule)
+			}
+		}
+	}
+	return delegatingRules
+}
+
+// patternsOverlap indicates if a namespace may be matched by patterns in both
+// sets. Negated patterns are ignored, and fnmatch's `*` also matches path
+// separators, so this may report overlaps that don't exist but never misses
+// one.
+func patternsOverlap(patterns, otherPatterns []string) bool {
+	for _, pattern := range patterns {
+		if tuf.IsNegatedPattern(pattern) {
+			continue
+		}
+
+		for _, otherPattern := range otherPatterns {
+			if tuf.IsNegatedPattern(otherPattern) {
+				continue
+			}
+
+			if fnmatch.Match(pattern, otherPattern, 0) || fnmatch.Match(otherPattern, pattern, 0) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// patternsCovered indicates if every pattern in patterns is matched by the
+// covering rule, using the pattern dialect of the rule's schema version. Rules
+// that exclude namespaces using negated patterns are never considered to cover
+// other patterns.
+func patternsCovered(coveringRule tuf.Rule, patterns []string) bool {
+	if slices.ContainsFunc(coveringRule.GetProtectedNamespaces(), tuf.IsNegatedPattern) {
+		return false
+	}
+
+	for _, pattern := range patterns {
+		if tuf.IsNegatedPattern(pattern) {
+			continue
+		}
+
+		if !coveringRule.Matches(pattern) {
+			return false
+		}
+	}
+	return true
+}
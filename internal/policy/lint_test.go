@@ -0,0 +1,121 @@
+// Copyright The gittuf Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package policy
+
+import (
+	"testing"
+
+	"github.com/gittuf/gittuf/internal/signerverifier/dsse"
+	"github.com/gittuf/gittuf/internal/signerverifier/gpg"
+	"github.com/gittuf/gittuf/internal/signerverifier/ssh"
+	"github.com/gittuf/gittuf/internal/tuf"
+	tufv01 "github.com/gittuf/gittuf/internal/tuf/v01"
+	tufv02 "github.com/gittuf/gittuf/internal/tuf/v02"
+	tufv04 "github.com/gittuf/gittuf/internal/tuf/v04"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLint(t *testing.T) {
+	rootKey := tufv01.NewKeyFromSSLibKey(ssh.NewKeyFromBytes(t, rootPubKeyBytes))
+	targets1Key := tufv01.NewKeyFromSSLibKey(ssh.NewKeyFromBytes(t, targets1PubKeyBytes))
+	gpgKeyR, err := gpg.LoadGPGKeyFromBytes(gpgPubKeyBytes)
+	require.Nil(t, err)
+	gpgKey := tufv01.NewKeyFromSSLibKey(gpgKeyR)
+
+	t.Run("no problems", func(t *testing.T) {
+		for _, stateCreator := range []func(*testing.T) *State{createTestStateWithOnlyRoot, createTestStateWithPolicy, createTestStateWithDelegatedPolicies} {
+			findings, err := stateCreator(t).Lint()
+			require.Nil(t, err)
+			assert.Empty(t, findings)
+			assert.False(t, HasLintErrors(findings))
+		}
+	})
+
+	t.Run("rule problems", func(t *testing.T) {
+		state := createTestStateWithDelegatedPolicies(t)
+
+		updateTestTargetsMetadata(t, state, TargetsRoleName, func(targetsMetadata tuf.TargetsMetadata) {
+			require.Nil(t, targetsMetadata.AddPrincipal(targets1Key))
+			require.Nil(t, targetsMetadata.AddRule("shadowed", []string{rootKey.KeyID}, []string{"file:1/a/*", "file:1/b"}, 1))
+			require.Nil(t, targetsMetadata.AddRule("terminating-leaf", []string{rootKey.KeyID}, []string{"file:3/*"}, 1))
+
+			rules := targetsMetadata.GetRules()
+			rules[0].(*tufv02.Delegation).Terminating = true
+			rules[3].(*tufv02.Delegation).Terminating = true
+
+			// The threshold can't be set above the number of principals using
+			// the metadata's methods
+			rules[1].(*tufv02.Delegation).Threshold = 2
+		})
+		updateTestTargetsMetadata(t, state, "1", func(targetsMetadata tuf.TargetsMetadata) {
+			require.Nil(t, targetsMetadata.AddRule("unreachable", []string{gpgKey.KeyID}, []string{"file:2/*"}, 1))
+		})
+
+		orphanMetadata := InitializeTargetsMetadata()
+		env, err := dsse.CreateEnvelope(orphanMetadata)
+		require.Nil(t, err)
+		state.Metadata.DelegationEnvelopes["orphan"] = env
+
+		findings, err := state.Lint()
+		require.Nil(t, err)
+
+		expectedFindings := []*LintFinding{
+			{Severity: LintSeverityError, Check: LintCheckUnsatisfiableThreshold, RuleFile: TargetsRoleName, Rule: "2", Message: "threshold 2 exceeds the 1 principals trusted by the rule"},
+			{Severity: LintSeverityWarning, Check: LintCheckShadowedRule, RuleFile: TargetsRoleName, Rule: "shadowed", Message: "patterns [file:1/a/*, file:1/b] are shadowed by prior terminating rule '1'"},
+			{Severity: LintSeverityWarning, Check: LintCheckMissingRuleFile, RuleFile: TargetsRoleName, Rule: "terminating-leaf", Message: "rule is terminating but the rule file it delegates to doesn't exist, so the search of the rule file doesn't stop at it"},
+			{Severity: LintSeverityWarning, Check: LintCheckUnreachableRule, RuleFile: "1", Rule: "unreachable", Message: "patterns [file:2/*] don't overlap with the patterns of the rules delegating to the rule file"},
+			{Severity: LintSeverityError, Check: LintCheckDanglingRuleFile, RuleFile: "orphan", Message: "no rule delegates to the rule file"},
+			{Severity: LintSeverityWarning, Check: LintCheckUnusedPrincipal, RuleFile: TargetsRoleName, Message: "principal '" + targets1Key.KeyID + "' isn't trusted by any rule"},
+		}
+		assert.Equal(t, expectedFindings, findings)
+		assert.True(t, HasLintErrors(findings))
+
+		assert.Equal(t, "error [unsatisfiable-threshold] rule file 'targets' rule '2': threshold 2 exceeds the 1 principals trusted by the rule", findings[0].String())
+	})
+
+	t.Run("global rule problems", func(t *testing.T) {
+		state := createTestStateWithGlobalConstraintThreshold(t)
+
+		updateTestTargetsMetadata(t, state, TargetsRoleName, func(targetsMetadata tuf.TargetsMetadata) {
+			require.Nil(t, targetsMetadata.AddRule("protect-main", []string{gpgKey.KeyID}, []string{"git:refs/heads/main"}, 1))
+			require.Nil(t, targetsMetadata.AddRule("protect-feature", []string{gpgKey.KeyID}, []string{"git:refs/heads/feature"}, 1))
+		})
+
+		findings, err := state.Lint()
+		require.Nil(t, err)
+		assert.Equal(t, []*LintFinding{
+			{Severity: LintSeverityWarning, Check: LintCheckGlobalRuleConflict, RuleFile: TargetsRoleName, Rule: "protect-main", Message: "global rule 'threshold-2-main' requires 2 approvals but the rule trusts 1 principals"},
+		}, findings)
+		assert.False(t, HasLintErrors(findings))
+
+		rootMetadata, err := state.GetRootMetadata(false)
+		require.Nil(t, err)
+		require.Nil(t, rootMetadata.UpdateGlobalRule(tufv01.NewGlobalRuleThreshold("threshold-2-main", []string{"git:refs/heads/main"}, 5)))
+		rootEnv, err := dsse.CreateEnvelope(rootMetadata)
+		require.Nil(t, err)
+		state.Metadata.RootEnvelope = rootEnv
+
+		findings, err = state.Lint()
+		require.Nil(t, err)
+		require.Len(t, findings, 1)
+		assert.Equal(t, LintCheckUnsatisfiableThreshold, findings[0].Check)
+		assert.True(t, HasLintErrors(findings))
+	})
+}
+
+func TestPatternsCovered(t *testing.T) {
+	assert.True(t, patternsCovered(&tufv04.Delegation{Paths: []string{"file:src/**"}}, []string{"file:src/a/*", "file:src/b"}))
+	assert.False(t, patternsCovered(&tufv04.Delegation{Paths: []string{"file:src/*"}}, []string{"file:src/a/*"}))
+
+	// fnmatch's `*` matches path separators in older schema versions
+	assert.True(t, patternsCovered(&tufv02.Delegation{Paths: []string{"file:src/*"}}, []string{"file:src/a/*"}))
+
+	// Negated covering patterns may leave gaps
+	assert.False(t, patternsCovered(&tufv04.Delegation{Paths: []string{"file:src/**", "!file:src/generated/**"}}, []string{"file:src/a"}))
+
+	// Negated patterns don't add to what must be covered
+	assert.True(t, patternsCovered(&tufv04.Delegation{Paths: []string{"file:src/**"}}, []string{"file:src/a/*", "!file:src/a/b"}))
+	assert.False(t, patternsOverlap([]string{"!file:src/**"}, []string{"file:src/a"}))
+}
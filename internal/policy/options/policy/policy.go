@@ -23,3 +23,27 @@ func BypassRSL() LoadStateOption {
 		o.BypassRSL = true
 	}
 }
+
+type PolicyVerifierOptions struct {
+	Workers        int
+	UseCheckpoints bool
+}
+
+type PolicyVerifierOption func(*PolicyVerifierOptions)
+
+// WithWorkers sets the number of RSL entries that are verified concurrently.
+// Entries are verified one at a time if workers is less than two.
+func WithWorkers(workers int) PolicyVerifierOption {
+	return func(o *PolicyVerifierOptions) {
+		o.Workers = workers
+	}
+}
+
+// WithCheckpoints indicates that full verification may begin from the latest
+// RSL checkpoint approved by a threshold of root principals rather than from
+// the start of the RSL.
+func WithCheckpoints() PolicyVerifierOption {
+	return func(o *PolicyVerifierOptions) {
+		o.UseCheckpoints = true
+	}
+}
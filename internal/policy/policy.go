@@ -9,10 +9,16 @@ import (
 	"errors"
 	"fmt"
 	"log/slog"
+	"maps"
 	"os"
+	"slices"
+	"sort"
 	"strings"
+	"sync"
+	"time"
 
 	"github.com/gittuf/gittuf/internal/common/set"
+	"github.com/gittuf/gittuf/internal/dev"
 	"github.com/gittuf/gittuf/internal/gitinterface"
 	policyopts "github.com/gittuf/gittuf/internal/policy/options/policy"
 	"github.com/gittuf/gittuf/internal/rsl"
@@ -21,6 +27,8 @@ import (
 	"github.com/gittuf/gittuf/internal/tuf/migrations"
 	tufv01 "github.com/gittuf/gittuf/internal/tuf/v01"
 	tufv02 "github.com/gittuf/gittuf/internal/tuf/v02"
+	tufv03 "github.com/gittuf/gittuf/internal/tuf/v03"
+	tufv04 "github.com/gittuf/gittuf/internal/tuf/v04"
 )
 
 const (
@@ -53,8 +61,14 @@ var (
 	ErrNotAncestor                   = errors.New("cannot apply changes since policy is not an ancestor of the policy staging")
 	ErrControllerMetadataNotFound    = errors.New("requested controller repository metadata not found")
 	ErrControllerMetadataNotVerified = errors.New("unable to verify controller repository metadata")
+	ErrMetadataExpired               = errors.New("policy metadata has expired")
+	ErrMetadataRollback              = errors.New("policy metadata version is older than the current version")
 )
 
+// MetadataExpiryWarningPeriod is the duration before a metadata file's expiry
+// during which gittuf warns that the metadata must be renewed.
+const MetadataExpiryWarningPeriod = 30 * 24 * time.Hour
+
 // State contains the full set of metadata and root keys present in a policy
 // state.
 type State struct {
@@ -67,11 +81,28 @@ type State struct {
 
 	repository     *gitinterface.Repository
 	loadedEntry    rsl.ReferenceUpdaterEntry
-	verifiersCache map[string][]*SignatureVerifier
+	verifiersCache map[verifiersCacheKey][]*SignatureVerifier
 	ruleNames      *set.Set[string]
 	allPrincipals  map[string]tuf.Principal
+	keyRotations   map[string][]tuf.KeyRotation
+	keyRevocations []*keyRevocation
 	hasFileRule    bool
 	globalRules    map[string][]tuf.GlobalRule
+	expiries       map[string]time.Time
+	versions       map[string]int
+
+	// hasValidityWindows is set when a rule or a principal in a rule file is
+	// only trusted for a period of time, in which case the verifiers for a
+	// path depend on when the change being verified was recorded
+	hasValidityWindows bool
+
+	// verifiersCacheMutex guards verifiersCache as RSL entries may be
+	// verified concurrently using the same state
+	verifiersCacheMutex sync.RWMutex
+
+	// ruleIndex records the decoded rule files of the state, indexed by the
+	// literal prefixes of their rules' patterns
+	ruleIndex ruleIndex
 }
 
 type StateMetadata struct {
@@ -216,7 +247,11 @@ func LoadState(ctx context.Context, repo *gitinterface.Repository, requestedEntr
 		}
 	}
 
+	// Metadata versions are checked against the highest version seen in the
+	// policy chain so that a rule file can't be rolled back by removing it and
+	// adding an older copy later
 	verifiedState := initialPolicyState
+	metadataVersions := initialPolicyState.GetMetadataVersions()
 	for _, entry := range allPolicyEntries[1:] {
 		if entry.GetRefName() != PolicyRef {
 			// The searcher _may_ include refs/gittuf/attestations
@@ -230,11 +265,12 @@ func LoadState(ctx context.Context, repo *gitinterface.Repository, requestedEntr
 		}
 
 		slog.Debug(fmt.Sprintf("Verifying root of trust for policy '%s'...", entry.GetID().String()))
-		if err := verifiedState.VerifyNewState(ctx, underTestState); err != nil {
+		if err := verifiedState.verifyNewState(ctx, underTestState, metadataVersions); err != nil {
 			return nil, fmt.Errorf("unable to verify roots of trust for policy states: %w", err)
 		}
 
 		verifiedState = underTestState
+		metadataVersions = mergeMetadataVersions(metadataVersions, underTestState)
 	}
 
 	if requestedEntry.GetRefName() == PolicyRef {
@@ -300,13 +336,38 @@ func LoadFirstState(ctx context.Context, repo *gitinterface.Repository, opts ...
 // specified path. While walking the delegation graph for the path, signatures
 // for delegated metadata files are verified using the verifier context.
 func (s *State) FindVerifiersForPath(path string) ([]*SignatureVerifier, error) {
-	if s.verifiersCache == nil {
-		slog.Debug("Initializing path cache in policy...")
-		s.verifiersCache = map[string][]*SignatureVerifier{}
-	} else if verifiers, cacheHit := s.verifiersCache[path]; cacheHit {
-		// Cache hit for this path in this policy
-		slog.Debug(fmt.Sprintf("Found cached verifiers for path '%s'", path))
-		return verifiers, nil
+	return s.FindVerifiersForPathAndAction(path, "")
+}
+
+// FindVerifiersForPathAndAction identifies the trusted set of verifiers for
+// the specified action on the path. Rules that limit the actions they apply to
+// are only included if they allow the action. If the action is not set, all
+// rules that match the path are included.
+func (s *State) FindVerifiersForPathAndAction(path string, action tuf.RefAction) ([]*SignatureVerifier, error) {
+	return s.findVerifiersForPathAndActionAt(path, action, time.Time{})
+}
+
+// findVerifiersForPathAndActionAt identifies the trusted set of verifiers for
+// the specified action on the path at the specified time, typically when the
+// RSL entry being verified was recorded. Rules and principals that are only
+// trusted for a period that doesn't include the time are ignored. If the time
+// is not set, the current time is used.
+func (s *State) findVerifiersForPathAndActionAt(path string, action tuf.RefAction, at time.Time) ([]*SignatureVerifier, error) {
+	cacheKey := verifiersCacheKey{path: path, action: action}
+
+	// The verifiers only depend on the time when the policy has validity
+	// windows, in which case they aren't cached
+	useCache := !s.hasValidityWindows
+
+	if useCache {
+		s.verifiersCacheMutex.RLock()
+		verifiers, cacheHit := s.verifiersCache[cacheKey]
+		s.verifiersCacheMutex.RUnlock()
+		if cacheHit {
+			// Cache hit for this path in this policy
+			slog.Debug(fmt.Sprintf("Found cached verifiers for path '%s'", path))
+			return verifiers, nil
+		}
 	}
 
 	allVerifiers := []*SignatureVerifier{}
@@ -318,21 +379,23 @@ func (s *State) FindVerifiersForPath(path string) ([]*SignatureVerifier, error)
 		verifier := &SignatureVerifier{
 			repository: s.repository,
 			name:       tuf.ExhaustiveVerifierName,
-			principals: []tuf.Principal{}, // we'll add all principals below
 
 			// threshold doesn't matter since we set verifyExhaustively to true
 			threshold:          1,
 			verifyExhaustively: true, // very important!
+			keyRotations:       s.keyRotations,
 		}
 
+		principals := make([]tuf.Principal, 0, len(s.allPrincipals))
 		for _, principal := range s.allPrincipals {
-			verifier.principals = append(verifier.principals, principal)
+			principals = append(principals, principal)
 		}
+		verifier.setPrincipals(principals, nil)
 
 		allVerifiers = append(allVerifiers, verifier)
 	}
 
-	specificVerifiers, err := s.findVerifiersForPathIfProtected(path)
+	specificVerifiers, err := s.findVerifiersForPathIfProtected(path, action, at)
 	if err != nil {
 		return nil, err
 	}
@@ -347,13 +410,46 @@ func (s *State) FindVerifiersForPath(path string) ([]*SignatureVerifier, error)
 	// would also want to verify every applicable global constraint for
 	// safety, so we would be doing extra work for no reason.
 
+	if !useCache {
+		return allVerifiers, nil
+	}
+
 	// add to cache
-	s.verifiersCache[path] = allVerifiers
+	s.verifiersCacheMutex.Lock()
+	if s.verifiersCache == nil {
+		slog.Debug("Initializing path cache in policy...")
+		s.verifiersCache = map[verifiersCacheKey][]*SignatureVerifier{}
+	}
+	s.verifiersCache[cacheKey] = allVerifiers
+	s.verifiersCacheMutex.Unlock()
 	// return verifiers
 	return allVerifiers, nil
 }
 
-func (s *State) findVerifiersForPathIfProtected(path string) ([]*SignatureVerifier, error) {
+func (s *State) findVerifiersForPathIfProtected(path string, action tuf.RefAction, at time.Time) ([]*SignatureVerifier, error) {
+	return s.walkDelegationsForPath(path, action, at, nil)
+}
+
+// verifiersCacheKey identifies the verifiers cached for a path and action.
+type verifiersCacheKey struct {
+	path   string
+	action tuf.RefAction
+}
+
+// delegationGroup records the rules of a rule file that remain to be walked
+// when searching for the verifiers of a path.
+type delegationGroup struct {
+	ruleFile string
+	depth    int
+	rules    []tuf.Rule
+}
+
+// walkDelegationsForPath walks the delegation graph depth first for the
+// specified path, returning the verifiers of the rules that match the path and
+// apply to the action. Rules and principals with validity windows that don't
+// include the specified time, or the current time if it is not set, are not
+// trusted. If trace is set, the rules visited are recorded in it.
+func (s *State) walkDelegationsForPath(path string, action tuf.RefAction, at time.Time, trace *delegationTrace) ([]*SignatureVerifier, error) {
 	if !s.HasTargetsRole(TargetsRoleName) {
 		// No policies exist
 		return nil, ErrMetadataNotFound
@@ -361,20 +457,31 @@ func (s *State) findVerifiersForPathIfProtected(path string) ([]*SignatureVerifi
 
 	// This envelope is verified when state is loaded, as this is
 	// the start for all delegation graph searches
-	targetsMetadata, err := s.GetTargetsMetadata(TargetsRoleName, true) // migrating is fine since this is purely a query, let's start using tufv02 metadata
+	targetsRuleFile, err := s.getIndexedRuleFile(TargetsRoleName)
 	if err != nil {
 		return nil, err
 	}
 
-	allPrincipals := targetsMetadata.GetPrincipals()
-	// each entry is a list of delegations from a particular metadata file
-	groupedDelegations := [][]tuf.Rule{
-		targetsMetadata.GetRules(),
+	// When tracing, every rule is visited so that the rules that don't match
+	// are reported too
+	allRules := trace != nil
+
+	if at.IsZero() {
+		at = time.Now()
+	}
+
+	allPrincipals := targetsRuleFile.clonePrincipals()
+	principalValidity := targetsRuleFile.clonePrincipalValidity()
+	keyRotations := groupKeyRotations(nil, allPrincipals, targetsRuleFile.keyRotations)
+	// each entry is a list of delegations from a particular metadata file,
+	// excluding the allow rule, that may match the path
+	groupedDelegations := []*delegationGroup{
+		{ruleFile: TargetsRoleName, rules: targetsRuleFile.candidateRules(path, allRules)},
 	}
 
 	seenRoles := map[string]bool{TargetsRoleName: true}
 
-	var currentDelegationGroup []tuf.Rule
+	var currentDelegationGroup *delegationGroup
 	verifiers := []*SignatureVerifier{}
 	for {
 		if len(groupedDelegations) == 0 {
@@ -384,23 +491,30 @@ func (s *State) findVerifiersForPathIfProtected(path string) ([]*SignatureVerifi
 		currentDelegationGroup = groupedDelegations[0]
 		groupedDelegations = groupedDelegations[1:]
 
-		for len(currentDelegationGroup) > 1 {
-			// Exit condition: Only allow rule found in the current group
-			// => len(currentDelegationGroup) <= 1
+		for len(currentDelegationGroup.rules) > 0 {
+			// Exit condition: No candidate rules left in the current group
+
+			delegation := currentDelegationGroup.rules[0]
+			currentDelegationGroup.rules = currentDelegationGroup.rules[1:]
 
-			delegation := currentDelegationGroup[0]
-			currentDelegationGroup = currentDelegationGroup[1:]
+			matches := delegation.Matches(path) && tuf.AllowsAction(delegation.GetAllowedActions(), action) && delegation.GetValidityWindow().IsActiveAt(at)
+			trace.visit(currentDelegationGroup, delegation, matches)
 
-			if delegation.Matches(path) {
+			if matches {
 				verifier := &SignatureVerifier{
-					repository: s.repository,
-					name:       delegation.ID(),
-					principals: make([]tuf.Principal, 0, delegation.GetPrincipalIDs().Len()),
-					threshold:  delegation.GetThreshold(),
+					repository:   s.repository,
+					name:         delegation.ID(),
+					threshold:    delegation.GetThreshold(),
+					keyRotations: keyRotations,
 				}
+				principals := make([]tuf.Principal, 0, delegation.GetPrincipalIDs().Len())
 				for _, principalID := range delegation.GetPrincipalIDs().Contents() {
-					verifier.principals = append(verifier.principals, allPrincipals[principalID])
+					if !principalValidity[principalID].IsActiveAt(at) {
+						continue
+					}
+					principals = append(principals, allPrincipals[principalID])
 				}
+				verifier.setPrincipals(principals, delegation.GetTeamThresholds())
 				verifiers = append(verifiers, verifier)
 
 				if _, seen := seenRoles[delegation.ID()]; seen {
@@ -408,24 +522,28 @@ func (s *State) findVerifiersForPathIfProtected(path string) ([]*SignatureVerifi
 				}
 
 				if s.HasTargetsRole(delegation.ID()) {
-					delegatedMetadata, err := s.GetTargetsMetadata(delegation.ID(), true) // migrating is fine since this is purely a query, let's start using tufv02 metadata
+					delegatedRuleFile, err := s.getIndexedRuleFile(delegation.ID())
 					if err != nil {
 						return nil, err
 					}
 
 					seenRoles[delegation.ID()] = true
+					trace.delegate()
 
-					for principalID, principal := range delegatedMetadata.GetPrincipals() {
+					for principalID, principal := range delegatedRuleFile.principals {
 						allPrincipals[principalID] = principal
+						principalValidity[principalID] = delegatedRuleFile.principalValidity[principalID]
 					}
+					keyRotations = groupKeyRotations(keyRotations, delegatedRuleFile.principals, delegatedRuleFile.keyRotations)
 
 					// Add the current metadata's further delegations upfront to
 					// be depth-first
-					groupedDelegations = append([][]tuf.Rule{delegatedMetadata.GetRules()}, groupedDelegations...)
+					groupedDelegations = append([]*delegationGroup{{ruleFile: delegation.ID(), depth: currentDelegationGroup.depth + 1, rules: delegatedRuleFile.candidateRules(path, allRules)}}, groupedDelegations...)
 
 					if delegation.IsLastTrustedInRuleFile() {
 						// Stop processing current delegation group, but proceed
 						// with other groups
+						trace.stop(currentDelegationGroup.rules)
 						break
 					}
 				}
@@ -444,12 +562,19 @@ func (s *State) GetAllPrincipals() map[string]tuf.Principal {
 // top level Targets role and all reachable delegated Targets roles. Any
 // unreachable role returns an error.
 func (s *State) Verify(ctx context.Context) error {
+	// Key rotation grace periods are checked against when the state was
+	// recorded in the RSL
+	verificationTime, err := s.getLoadedEntryTime()
+	if err != nil {
+		return err
+	}
+
 	rootVerifier, err := s.getRootVerifier()
 	if err != nil {
 		return err
 	}
 
-	if _, err := rootVerifier.Verify(ctx, gitinterface.ZeroHash, s.Metadata.RootEnvelope); err != nil {
+	if _, err := rootVerifier.at(verificationTime).Verify(ctx, gitinterface.ZeroHash, s.Metadata.RootEnvelope); err != nil {
 		return err
 	}
 
@@ -480,7 +605,7 @@ func (s *State) Verify(ctx context.Context) error {
 			return err
 		}
 
-		if _, err := targetsVerifier.Verify(ctx, gitinterface.ZeroHash, s.Metadata.TargetsEnvelope); err != nil {
+		if _, err := targetsVerifier.at(verificationTime).Verify(ctx, gitinterface.ZeroHash, s.Metadata.TargetsEnvelope); err != nil {
 			return err
 		}
 
@@ -497,6 +622,7 @@ func (s *State) Verify(ctx context.Context) error {
 
 		delegationsQueue := targetsMetadata.GetRules()
 		delegationKeys := targetsMetadata.GetPrincipals()
+		delegationKeyRotations := groupKeyRotations(nil, delegationKeys, targetsMetadata.GetKeyRotations())
 		for len(delegationsQueue) > 1 {
 			// Exit condition: The last entry in the queue is always the allow
 			// rule, which we don't process during DFS
@@ -515,10 +641,12 @@ func (s *State) Verify(ctx context.Context) error {
 				}
 
 				verifier := &SignatureVerifier{
-					repository: s.repository,
-					name:       delegation.ID(),
-					principals: principals,
-					threshold:  delegation.GetThreshold(),
+					repository:       s.repository,
+					name:             delegation.ID(),
+					principals:       principals,
+					threshold:        delegation.GetThreshold(),
+					keyRotations:     delegationKeyRotations,
+					verificationTime: verificationTime,
 				}
 
 				if _, err := verifier.Verify(ctx, gitinterface.ZeroHash, env); err != nil {
@@ -534,6 +662,7 @@ func (s *State) Verify(ctx context.Context) error {
 				for keyID, key := range delegatedMetadata.GetPrincipals() {
 					delegationKeys[keyID] = key
 				}
+				delegationKeyRotations = groupKeyRotations(delegationKeyRotations, delegatedMetadata.GetPrincipals(), delegatedMetadata.GetKeyRotations())
 			}
 		}
 
@@ -686,12 +815,45 @@ func Apply(ctx context.Context, repo *gitinterface.Repository, signRSLEntry bool
 		return err
 	}
 
+	policyTip, policyStagingTip, err := verifyStagedPolicy(ctx, repo)
+	if err != nil {
+		return err
+	}
+
+	// Update the reference for the base to point to the new commit
+	if err := repo.SetReference(PolicyRef, policyStagingTip); err != nil {
+		return fmt.Errorf("failed to set new policy reference: %w", err)
+	}
+
+	if err := rsl.NewReferenceEntry(PolicyRef, policyStagingTip).Commit(repo, signRSLEntry); err != nil {
+		if !policyTip.IsZero() {
+			return repo.ResetDueToError(err, PolicyRef, policyTip)
+		}
+
+		return err
+	}
+
+	return nil
+}
+
+// CheckApply performs the checks Apply does before updating the policy ref,
+// without making any changes to the repository. Unlike Apply, the policy
+// staging ref is not reconciled with the policy ref first.
+func CheckApply(ctx context.Context, repo *gitinterface.Repository) error {
+	_, _, err := verifyStagedPolicy(ctx, repo)
+	return err
+}
+
+// verifyStagedPolicy checks that the policy staging ref can be fast-forward
+// merged into the policy ref and that the staged policy is valid. It returns
+// the tips of the policy and policy staging refs.
+func verifyStagedPolicy(ctx context.Context, repo *gitinterface.Repository) (gitinterface.Hash, gitinterface.Hash, error) {
 	// Get the reference for the PolicyRef
 	referenceFound := true
 	policyTip, err := repo.GetReference(PolicyRef)
 	if err != nil {
 		if !errors.Is(err, gitinterface.ErrReferenceNotFound) {
-			return fmt.Errorf("failed to get policy reference %s: %w", PolicyRef, err)
+			return nil, nil, fmt.Errorf("failed to get policy reference %s: %w", PolicyRef, err)
 		}
 		referenceFound = false
 	}
@@ -700,7 +862,7 @@ func Apply(ctx context.Context, repo *gitinterface.Repository, signRSLEntry bool
 	policyEntry, _, err := rsl.GetLatestReferenceUpdaterEntry(repo, rsl.ForReference(PolicyRef))
 	if err != nil {
 		if !errors.Is(err, rsl.ErrRSLEntryNotFound) {
-			return fmt.Errorf("failed to get policy RSL entry: %w", err)
+			return nil, nil, fmt.Errorf("failed to get policy RSL entry: %w", err)
 		}
 
 		entryFound = false
@@ -712,10 +874,10 @@ func Apply(ctx context.Context, repo *gitinterface.Repository, signRSLEntry bool
 	switch {
 	case referenceFound && entryFound:
 		if !policyEntry.GetTargetID().Equal(policyTip) {
-			return ErrInvalidPolicy
+			return nil, nil, ErrInvalidPolicy
 		}
 	case (referenceFound && !entryFound) || (!referenceFound && entryFound):
-		return ErrInvalidPolicy
+		return nil, nil, ErrInvalidPolicy
 	default:
 		slog.Debug("No prior applied policy found")
 		// Nothing to check or return here
@@ -724,7 +886,7 @@ func Apply(ctx context.Context, repo *gitinterface.Repository, signRSLEntry bool
 	// Get the reference for the PolicyStagingRef
 	policyStagingTip, err := repo.GetReference(PolicyStagingRef)
 	if err != nil {
-		return fmt.Errorf("failed to get policy staging reference %s: %w", PolicyStagingRef, err)
+		return nil, nil, fmt.Errorf("failed to get policy staging reference %s: %w", PolicyStagingRef, err)
 	}
 
 	// Check if the PolicyStagingRef is ahead of PolicyRef (fast-forward)
@@ -738,10 +900,10 @@ func Apply(ctx context.Context, repo *gitinterface.Repository, signRSLEntry bool
 		// is no tip, then it cannot be an ancestor of the tip of the policy staging ref
 		isAncestor, err := repo.KnowsCommit(policyStagingTip, policyTip)
 		if err != nil {
-			return fmt.Errorf("failed to check if policy commit is ancestor of policy staging commit: %w", err)
+			return nil, nil, fmt.Errorf("failed to check if policy commit is ancestor of policy staging commit: %w", err)
 		}
 		if !isAncestor {
-			return ErrNotAncestor
+			return nil, nil, ErrNotAncestor
 		}
 	}
 
@@ -749,26 +911,13 @@ func Apply(ctx context.Context, repo *gitinterface.Repository, signRSLEntry bool
 	// latest state is valid
 	state, err := LoadCurrentState(ctx, repo, PolicyStagingRef)
 	if err != nil {
-		return fmt.Errorf("failed to load current state: %w", err)
+		return nil, nil, fmt.Errorf("failed to load current state: %w", err)
 	}
 	if err := state.Verify(ctx); err != nil {
-		return fmt.Errorf("staged policy is invalid: %w", err)
-	}
-
-	// Update the reference for the base to point to the new commit
-	if err := repo.SetReference(PolicyRef, policyStagingTip); err != nil {
-		return fmt.Errorf("failed to set new policy reference: %w", err)
-	}
-
-	if err := rsl.NewReferenceEntry(PolicyRef, policyStagingTip).Commit(repo, signRSLEntry); err != nil {
-		if !policyTip.IsZero() {
-			return repo.ResetDueToError(err, PolicyRef, policyTip)
-		}
-
-		return err
+		return nil, nil, fmt.Errorf("staged policy is invalid: %w", err)
 	}
 
-	return nil
+	return policyTip, policyStagingTip, nil
 }
 
 // Discard resets the policy staging ref, discarding any changes made to the policy staging ref.
@@ -983,7 +1132,7 @@ func (s *State) GetRootKeys() ([]tuf.Principal, error) {
 
 // GetRootMetadata returns the deserialized payload of the State's RootEnvelope.
 // The `migrate` parameter determines if the schema must be converted to a newer
-// version.
+// version. Metadata is only migrated past v02 in developer mode.
 func (s *State) GetRootMetadata(migrate bool) (tuf.RootMetadata, error) {
 	payloadBytes, err := s.Metadata.RootEnvelope.DecodeB64Payload()
 	if err != nil {
@@ -1027,6 +1176,9 @@ func (s *State) getRootMetadataFromBytes(metadataBytes []byte, migrate bool) (tu
 		}
 
 		if migrate {
+			if dev.InDevMode() {
+				return migrations.MigrateRootMetadataV03ToV04(migrations.MigrateRootMetadataV02ToV03(migrations.MigrateRootMetadataV01ToV02(rootMetadata))), nil
+			}
 			return migrations.MigrateRootMetadataV01ToV02(rootMetadata), nil
 		}
 
@@ -1038,6 +1190,30 @@ func (s *State) getRootMetadataFromBytes(metadataBytes []byte, migrate bool) (tu
 			return nil, fmt.Errorf("unable to unmarshal root metadata: %w", err)
 		}
 
+		if migrate && dev.InDevMode() {
+			return migrations.MigrateRootMetadataV03ToV04(migrations.MigrateRootMetadataV02ToV03(rootMetadata)), nil
+		}
+
+		return rootMetadata, nil
+
+	case schemaVersion == tufv03.RootVersion:
+		rootMetadata := &tufv03.RootMetadata{}
+		if err := json.Unmarshal(metadataBytes, rootMetadata); err != nil {
+			return nil, fmt.Errorf("unable to unmarshal root metadata: %w", err)
+		}
+
+		if migrate && dev.InDevMode() {
+			return migrations.MigrateRootMetadataV03ToV04(rootMetadata), nil
+		}
+
+		return rootMetadata, nil
+
+	case schemaVersion == tufv04.RootVersion:
+		rootMetadata := &tufv04.RootMetadata{}
+		if err := json.Unmarshal(metadataBytes, rootMetadata); err != nil {
+			return nil, fmt.Errorf("unable to unmarshal root metadata: %w", err)
+		}
+
 		return rootMetadata, nil
 
 	default:
@@ -1047,7 +1223,8 @@ func (s *State) getRootMetadataFromBytes(metadataBytes []byte, migrate bool) (tu
 
 // GetTargetsMetadata returns the deserialized payload of the State's
 // TargetsEnvelope for the specified `roleName`.  The `migrate` parameter
-// determines if the schema must be converted to a newer version.
+// determines if the schema must be converted to a newer version. Metadata is
+// only migrated past v02 in developer mode.
 func (s *State) GetTargetsMetadata(roleName string, migrate bool) (tuf.TargetsMetadata, error) {
 	e := s.Metadata.TargetsEnvelope
 	if roleName != TargetsRoleName {
@@ -1088,6 +1265,9 @@ func (s *State) GetTargetsMetadata(roleName string, migrate bool) (tuf.TargetsMe
 		}
 
 		if migrate {
+			if dev.InDevMode() {
+				return migrations.MigrateTargetsMetadataV03ToV04(migrations.MigrateTargetsMetadataV02ToV03(migrations.MigrateTargetsMetadataV01ToV02(targetsMetadata))), nil
+			}
 			return migrations.MigrateTargetsMetadataV01ToV02(targetsMetadata), nil
 		}
 
@@ -1099,6 +1279,30 @@ func (s *State) GetTargetsMetadata(roleName string, migrate bool) (tuf.TargetsMe
 			return nil, fmt.Errorf("unable to unmarshal rule file metadata: %w", err)
 		}
 
+		if migrate && dev.InDevMode() {
+			return migrations.MigrateTargetsMetadataV03ToV04(migrations.MigrateTargetsMetadataV02ToV03(targetsMetadata)), nil
+		}
+
+		return targetsMetadata, nil
+
+	case schemaVersion == tufv03.TargetsVersion:
+		targetsMetadata := &tufv03.TargetsMetadata{}
+		if err := json.Unmarshal(payloadBytes, targetsMetadata); err != nil {
+			return nil, fmt.Errorf("unable to unmarshal rule file metadata: %w", err)
+		}
+
+		if migrate && dev.InDevMode() {
+			return migrations.MigrateTargetsMetadataV03ToV04(targetsMetadata), nil
+		}
+
+		return targetsMetadata, nil
+
+	case schemaVersion == tufv04.TargetsVersion:
+		targetsMetadata := &tufv04.TargetsMetadata{}
+		if err := json.Unmarshal(payloadBytes, targetsMetadata); err != nil {
+			return nil, fmt.Errorf("unable to unmarshal rule file metadata: %w", err)
+		}
+
 		return targetsMetadata, nil
 
 	default:
@@ -1119,6 +1323,143 @@ func (s *State) HasRuleName(name string) bool {
 	return s.ruleNames.Has(name)
 }
 
+// GetExpiries returns the expiry of the root of trust and every rule file in
+// the state, keyed by the name of the metadata file. Metadata that does not
+// declare an expiry is not included.
+func (s *State) GetExpiries() map[string]time.Time {
+	return maps.Clone(s.expiries)
+}
+
+// GetMetadataVersions returns the version of the root of trust and every rule
+// file in the state, keyed by the name of the metadata file.
+func (s *State) GetMetadataVersions() map[string]int {
+	return maps.Clone(s.versions)
+}
+
+// checkMetadataVersions returns ErrMetadataRollback if the root of trust or
+// any rule file in the new state is at an older version than the version
+// specified for it.
+func checkMetadataVersions(versions map[string]int, newPolicy *State) error {
+	for _, name := range slices.Sorted(maps.Keys(newPolicy.versions)) {
+		currentVersion, has := versions[name]
+		if !has {
+			continue
+		}
+
+		if newVersion := newPolicy.versions[name]; newVersion < currentVersion {
+			return fmt.Errorf("%w: '%s' is at version %d, current version is %d", ErrMetadataRollback, name, newVersion, currentVersion)
+		}
+	}
+
+	return nil
+}
+
+// mergeMetadataVersions returns the highest version of each metadata file in
+// the specified versions and the state.
+func mergeMetadataVersions(versions map[string]int, state *State) map[string]int {
+	merged := maps.Clone(versions)
+	if merged == nil {
+		merged = map[string]int{}
+	}
+	for name, version := range state.versions {
+		if currentVersion, has := merged[name]; !has || version > currentVersion {
+			merged[name] = version
+		}
+	}
+	return merged
+}
+
+// CheckExpiry returns ErrMetadataExpired if the root of trust, the primary
+// rule file, or any of the specified rule files in the state has expired at the
+// specified time. Other rule files are not checked, as they aren't used when
+// verifying changes they don't apply to.
+func (s *State) CheckExpiry(at time.Time, ruleFiles ...string) error {
+	names := append([]string{RootRoleName, TargetsRoleName}, ruleFiles...)
+	for _, name := range names {
+		if expires, has := s.expiries[name]; has && !at.Before(expires) {
+			return fmt.Errorf("%w: '%s' expired at %s", ErrMetadataExpired, name, expires.Format(time.RFC3339))
+		}
+	}
+
+	return nil
+}
+
+// checkExpiryForPathAndAction returns ErrMetadataExpired if the root of trust,
+// the primary rule file, or any rule file walked to find the verifiers for the
+// action on the path has expired at the specified time.
+func (s *State) checkExpiryForPathAndAction(path string, action tuf.RefAction, at time.Time) error {
+	ruleFiles := []string{}
+	if s.HasTargetsRole(TargetsRoleName) {
+		verifiers, err := s.findVerifiersForPathAndActionAt(path, action, at)
+		if err != nil {
+			return err
+		}
+
+		// A rule file is walked only when the rule delegating to it matches,
+		// so it is named by one of the verifiers
+		for _, verifier := range verifiers {
+			if s.HasTargetsRole(verifier.Name()) {
+				ruleFiles = append(ruleFiles, verifier.Name())
+			}
+		}
+	}
+
+	return s.CheckExpiry(at, ruleFiles...)
+}
+
+// GetMetadataExpiringWithin returns the names of metadata files in the state
+// that have not expired at the specified time but expire within the specified
+// period after it.
+func (s *State) GetMetadataExpiringWithin(at time.Time, period time.Duration) []string {
+	expiring := []string{}
+	for _, name := range s.sortedExpiryNames() {
+		expires := s.expiries[name]
+		if at.Before(expires) && !at.Add(period).Before(expires) {
+			expiring = append(expiring, name)
+		}
+	}
+	return expiring
+}
+
+// sortedExpiryNames returns the names of metadata files with an expiry with the
+// root of trust first, followed by the primary rule file, and then all other
+// rule files in lexicographic order.
+func (s *State) sortedExpiryNames() []string {
+	names := []string{}
+	for name := range s.expiries {
+		if name == RootRoleName || name == TargetsRoleName {
+			continue
+		}
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	if _, has := s.expiries[TargetsRoleName]; has {
+		names = append([]string{TargetsRoleName}, names...)
+	}
+	if _, has := s.expiries[RootRoleName]; has {
+		names = append([]string{RootRoleName}, names...)
+	}
+
+	return names
+}
+
+// addExpiry records the expiry declared by the specified metadata file. An
+// empty expiry indicates the metadata does not expire.
+func (s *State) addExpiry(name, expires string) error {
+	if expires == "" {
+		return nil
+	}
+
+	expiresTime, err := time.Parse(time.RFC3339, expires)
+	if err != nil {
+		return fmt.Errorf("invalid expiry '%s' for '%s': %w", expires, name, err)
+	}
+
+	s.expiries[name] = expiresTime
+	return nil
+}
+
 // preprocess handles several "one time" tasks when the state is first loaded.
 // This includes things like loading the set of rule names present in the state,
 // checking if it has file rules, etc.
@@ -1170,12 +1511,24 @@ func (s *State) preprocess() error {
 	for principalID, principal := range rootMetadata.GetPrincipals() {
 		s.allPrincipals[principalID] = principal
 	}
+	s.keyRotations = groupKeyRotations(nil, rootMetadata.GetPrincipals(), rootMetadata.GetKeyRotations())
+
+	s.keyRevocations, err = resolveKeyRevocations(s.repository, rootMetadata.GetKeyRevocations())
+	if err != nil {
+		return err
+	}
 
 	s.GitHubApps, err = rootMetadata.GetGitHubAppEntries()
 	if err != nil {
 		return err
 	}
 
+	s.expiries = map[string]time.Time{}
+	if err := s.addExpiry(RootRoleName, rootMetadata.GetExpires()); err != nil {
+		return err
+	}
+	s.versions = map[string]int{RootRoleName: rootMetadata.GetMetadataVersion()}
+
 	if s.Metadata.TargetsEnvelope == nil {
 		return nil
 	}
@@ -1187,9 +1540,18 @@ func (s *State) preprocess() error {
 		return err
 	}
 
+	if err := s.addExpiry(TargetsRoleName, targetsMetadata.GetExpires()); err != nil {
+		return err
+	}
+	s.versions[TargetsRoleName] = targetsMetadata.GetMetadataVersion()
+
 	for principalID, principal := range targetsMetadata.GetPrincipals() {
 		s.allPrincipals[principalID] = principal
 	}
+	s.keyRotations = groupKeyRotations(s.keyRotations, targetsMetadata.GetPrincipals(), targetsMetadata.GetKeyRotations())
+	if len(targetsMetadata.GetPrincipalValidityWindows()) != 0 {
+		s.hasValidityWindows = true
+	}
 
 	for _, rule := range targetsMetadata.GetRules() {
 		if rule.ID() == tuf.AllowRuleName {
@@ -1202,6 +1564,10 @@ func (s *State) preprocess() error {
 
 		s.ruleNames.Add(rule.ID())
 
+		if rule.GetValidityWindow() != nil {
+			s.hasValidityWindows = true
+		}
+
 		if !s.hasFileRule {
 			patterns := rule.GetProtectedNamespaces()
 			for _, pattern := range patterns {
@@ -1220,9 +1586,18 @@ func (s *State) preprocess() error {
 				return err
 			}
 
+			if err := s.addExpiry(delegatedRoleName, delegatedMetadata.GetExpires()); err != nil {
+				return err
+			}
+			s.versions[delegatedRoleName] = delegatedMetadata.GetMetadataVersion()
+
 			for principalID, principal := range delegatedMetadata.GetPrincipals() {
 				s.allPrincipals[principalID] = principal
 			}
+			s.keyRotations = groupKeyRotations(s.keyRotations, delegatedMetadata.GetPrincipals(), delegatedMetadata.GetKeyRotations())
+			if len(delegatedMetadata.GetPrincipalValidityWindows()) != 0 {
+				s.hasValidityWindows = true
+			}
 
 			for _, rule := range delegatedMetadata.GetRules() {
 				if rule.ID() == tuf.AllowRuleName {
@@ -1235,6 +1610,10 @@ func (s *State) preprocess() error {
 
 				s.ruleNames.Add(rule.ID())
 
+				if rule.GetValidityWindow() != nil {
+					s.hasValidityWindows = true
+				}
+
 				if !s.hasFileRule {
 					patterns := rule.GetProtectedNamespaces()
 					for _, pattern := range patterns {
@@ -1248,6 +1627,10 @@ func (s *State) preprocess() error {
 		}
 	}
 
+	if err := s.indexRuleFiles(); err != nil {
+		return err
+	}
+
 	for controllerName := range s.ControllerMetadata {
 		controllerRootMetadata, err := s.GetControllerRootMetadata(controllerName)
 		if err != nil {
@@ -1267,6 +1650,17 @@ func (s *State) preprocess() error {
 	return nil
 }
 
+// getLoadedEntryTime returns when the RSL entry the state was loaded from was
+// recorded, which is never before the entries that precede it. If the state
+// wasn't loaded from the RSL, the zero time is returned.
+func (s *State) getLoadedEntryTime() (time.Time, error) {
+	if s.loadedEntry == nil || s.repository == nil {
+		return time.Time{}, nil
+	}
+
+	return rsl.GetRecordedTime(s.repository, s.loadedEntry)
+}
+
 func (s *State) getRootVerifier() (*SignatureVerifier, error) {
 	rootMetadata, err := s.GetRootMetadata(false)
 	if err != nil {
@@ -1283,11 +1677,14 @@ func (s *State) getRootVerifier() (*SignatureVerifier, error) {
 		return nil, err
 	}
 
-	return &SignatureVerifier{
-		repository: s.repository,
-		principals: principals,
-		threshold:  threshold,
-	}, nil
+	verifier := &SignatureVerifier{
+		repository:   s.repository,
+		threshold:    threshold,
+		keyRotations: groupKeyRotations(nil, rootMetadata.GetPrincipals(), rootMetadata.GetKeyRotations()),
+	}
+	verifier.setPrincipals(principals, nil)
+
+	return verifier, nil
 }
 
 func (s *State) getTargetsVerifier() (*SignatureVerifier, error) {
@@ -1306,11 +1703,14 @@ func (s *State) getTargetsVerifier() (*SignatureVerifier, error) {
 		return nil, err
 	}
 
-	return &SignatureVerifier{
-		repository: s.repository,
-		principals: principals,
-		threshold:  threshold,
-	}, nil
+	verifier := &SignatureVerifier{
+		repository:   s.repository,
+		threshold:    threshold,
+		keyRotations: groupKeyRotations(nil, rootMetadata.GetPrincipals(), rootMetadata.GetKeyRotations()),
+	}
+	verifier.setPrincipals(principals, nil)
+
+	return verifier, nil
 }
 
 // loadStateForEntry returns the State for a specified RSL reference entry for
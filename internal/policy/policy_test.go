@@ -8,12 +8,15 @@ import (
 	"fmt"
 	"path"
 	"testing"
+	"time"
 
 	"github.com/gittuf/gittuf/internal/common/set"
+	"github.com/gittuf/gittuf/internal/dev"
 	"github.com/gittuf/gittuf/internal/gitinterface"
 	policyopts "github.com/gittuf/gittuf/internal/policy/options/policy"
 	"github.com/gittuf/gittuf/internal/rsl"
 	"github.com/gittuf/gittuf/internal/signerverifier/dsse"
+	"github.com/gittuf/gittuf/internal/signerverifier/gpg"
 	"github.com/gittuf/gittuf/internal/signerverifier/ssh"
 	"github.com/gittuf/gittuf/internal/tuf"
 	tufv01 "github.com/gittuf/gittuf/internal/tuf/v01"
@@ -234,6 +237,18 @@ func TestLoadState(t *testing.T) {
 		_, err = LoadState(context.Background(), repo, entry.(*rsl.ReferenceEntry), policyopts.WithInitialRootPrincipals(initialRootPrincipals))
 		assert.ErrorIs(t, err, ErrVerifierConditionsUnmet)
 	})
+
+	t.Run("rule file rolled back after removal", func(t *testing.T) {
+		t.Setenv(dev.DevModeKey, "1")
+
+		repo, policyEntries := createTestRepositoryWithReaddedRuleFile(t)
+
+		_, err := LoadState(context.Background(), repo, policyEntries[2])
+		assert.Nil(t, err)
+
+		_, err = LoadState(context.Background(), repo, policyEntries[3])
+		assert.ErrorIs(t, err, ErrMetadataRollback)
+	})
 }
 
 func TestLoadCurrentState(t *testing.T) {
@@ -799,6 +814,105 @@ func TestStateFindVerifiersForPath(t *testing.T) {
 	})
 }
 
+func TestStateFindVerifiersForPathWithValidityWindows(t *testing.T) {
+	t.Setenv(dev.DevModeKey, "1")
+
+	state := createTestStateWithDelegatedPolicies(t)
+
+	keyR := ssh.NewKeyFromBytes(t, rootPubKeyBytes)
+	key := tufv01.NewKeyFromSSLibKey(keyR)
+
+	gpgKeyR, err := gpg.LoadGPGKeyFromBytes(gpgPubKeyBytes)
+	require.Nil(t, err)
+	gpgKey := tufv01.NewKeyFromSSLibKey(gpgKeyR)
+
+	start := time.Date(2025, time.March, 1, 0, 0, 0, 0, time.UTC)
+	end := time.Date(2025, time.March, 8, 0, 0, 0, 0, time.UTC)
+	window, err := tuf.NewValidityWindow(start, end)
+	require.Nil(t, err)
+
+	updateTestTargetsMetadata(t, state, TargetsRoleName, func(targetsMetadata tuf.TargetsMetadata) {
+		require.Nil(t, targetsMetadata.AddPrincipal(gpgKey))
+		require.Nil(t, targetsMetadata.AddRule("contractor", []string{key.KeyID, gpgKey.KeyID}, []string{"git:refs/heads/contractor"}, 1))
+		require.Nil(t, targetsMetadata.UpdateRuleValidityWindow("contractor", window))
+		require.Nil(t, targetsMetadata.AddRule("incident", []string{key.KeyID, gpgKey.KeyID}, []string{"git:refs/heads/incident"}, 1))
+		require.Nil(t, targetsMetadata.UpdatePrincipalValidityWindow(gpgKey.KeyID, window))
+	})
+	require.Nil(t, state.preprocess())
+
+	tests := map[string]struct {
+		path      string
+		at        time.Time
+		verifiers []*SignatureVerifier
+	}{
+		"rule before window": {
+			path:      "git:refs/heads/contractor",
+			at:        start.Add(-time.Hour),
+			verifiers: []*SignatureVerifier{},
+		},
+		"rule within window": {
+			path: "git:refs/heads/contractor",
+			at:   start.Add(time.Hour),
+			verifiers: []*SignatureVerifier{{
+				name:       "contractor",
+				principals: []tuf.Principal{gpgKey, key},
+				threshold:  1,
+			}},
+		},
+		"rule after window": {
+			path:      "git:refs/heads/contractor",
+			at:        end.Add(time.Hour),
+			verifiers: []*SignatureVerifier{},
+		},
+		"principal after window": {
+			path: "git:refs/heads/incident",
+			at:   end.Add(time.Hour),
+			verifiers: []*SignatureVerifier{{
+				name:       "incident",
+				principals: []tuf.Principal{key},
+				threshold:  1,
+			}},
+		},
+	}
+
+	for name, test := range tests {
+		verifiers, err := state.findVerifiersForPathAndActionAt(test.path, "", test.at)
+		assert.Nil(t, err, fmt.Sprintf("unexpected error in test '%s'", name))
+		require.Len(t, verifiers, len(test.verifiers), fmt.Sprintf("unexpected number of verifiers for path '%s' in test '%s'", test.path, name))
+		for i, verifier := range verifiers {
+			// The order of a rule's principals is not significant
+			assert.Equal(t, test.verifiers[i].name, verifier.name, fmt.Sprintf("unexpected verifier for path '%s' in test '%s'", test.path, name))
+			assert.Equal(t, test.verifiers[i].threshold, verifier.threshold, fmt.Sprintf("unexpected threshold for path '%s' in test '%s'", test.path, name))
+			assert.ElementsMatch(t, test.verifiers[i].principals, verifier.principals, fmt.Sprintf("unexpected principals for path '%s' in test '%s'", test.path, name))
+		}
+	}
+}
+
+func TestStateFindVerifiersForPathWithNegatedPatterns(t *testing.T) {
+	t.Setenv(dev.DevModeKey, "1")
+
+	state := createTestStateWithDelegatedPolicies(t)
+
+	keyR := ssh.NewKeyFromBytes(t, rootPubKeyBytes)
+	key := tufv01.NewKeyFromSSLibKey(keyR)
+
+	updateTestTargetsMetadata(t, state, TargetsRoleName, func(targetsMetadata tuf.TargetsMetadata) {
+		require.Nil(t, targetsMetadata.AddRule("src", []string{key.KeyID}, []string{"file:src/**", "!file:src/generated/**"}, 1))
+	})
+
+	verifiers, err := state.FindVerifiersForPath("file:src/cmd/main.go")
+	assert.Nil(t, err)
+	assert.Equal(t, []*SignatureVerifier{{
+		name:       "src",
+		principals: []tuf.Principal{key},
+		threshold:  1,
+	}}, verifiers)
+
+	verifiers, err = state.FindVerifiersForPath("file:src/generated/types.go")
+	assert.Nil(t, err)
+	assert.Empty(t, verifiers)
+}
+
 func TestStateHasFileRule(t *testing.T) {
 	t.Parallel()
 	t.Run("with file rules", func(t *testing.T) {
@@ -817,6 +931,71 @@ func TestStateHasFileRule(t *testing.T) {
 	})
 }
 
+func TestStateCheckExpiry(t *testing.T) {
+	state := createTestStateWithDelegatedPolicies(t)
+
+	expiries := state.GetExpiries()
+	assert.Contains(t, expiries, RootRoleName)
+	assert.Contains(t, expiries, TargetsRoleName)
+	assert.Contains(t, expiries, "1")
+
+	t.Run("metadata has not expired", func(t *testing.T) {
+		err := state.CheckExpiry(time.Now())
+		assert.Nil(t, err)
+	})
+
+	t.Run("metadata has expired", func(t *testing.T) {
+		err := state.CheckExpiry(time.Now().AddDate(2, 0, 0))
+		assert.ErrorIs(t, err, ErrMetadataExpired)
+		assert.ErrorContains(t, err, fmt.Sprintf("'%s'", RootRoleName))
+	})
+
+	t.Run("rule file has expired", func(t *testing.T) {
+		state := createTestStateWithDelegatedPolicies(t)
+		state.expiries["1"] = time.Now().AddDate(0, 0, -1)
+
+		err := state.CheckExpiry(time.Now(), "1")
+		assert.ErrorIs(t, err, ErrMetadataExpired)
+		assert.ErrorContains(t, err, "'1'")
+
+		// Rule files that aren't specified aren't checked
+		err = state.CheckExpiry(time.Now())
+		assert.Nil(t, err)
+	})
+
+	t.Run("rule file for path has expired", func(t *testing.T) {
+		state := createTestStateWithDelegatedPolicies(t)
+		state.expiries["1"] = time.Now().AddDate(0, 0, -1)
+
+		err := state.checkExpiryForPathAndAction("file:1/subpath1/foo", "", time.Now())
+		assert.ErrorIs(t, err, ErrMetadataExpired)
+		assert.ErrorContains(t, err, "'1'")
+
+		// Rule file '1' isn't walked for paths protected by rule '2'
+		err = state.checkExpiryForPathAndAction("file:2/foo", "", time.Now())
+		assert.Nil(t, err)
+	})
+
+	t.Run("metadata expiring within warning period", func(t *testing.T) {
+		state := createTestStateWithDelegatedPolicies(t)
+		state.expiries[TargetsRoleName] = time.Now().AddDate(0, 0, 7)
+
+		expiring := state.GetMetadataExpiringWithin(time.Now(), MetadataExpiryWarningPeriod)
+		assert.Equal(t, []string{TargetsRoleName}, expiring)
+
+		expiring = state.GetMetadataExpiringWithin(time.Now(), 24*time.Hour)
+		assert.Empty(t, expiring)
+	})
+}
+
+func TestStateGetMetadataVersions(t *testing.T) {
+	t.Setenv(dev.DevModeKey, "1")
+
+	state := createTestStateWithDelegatedPolicies(t)
+
+	assert.Equal(t, map[string]int{RootRoleName: 1, TargetsRoleName: 1, "1": 1}, state.GetMetadataVersions())
+}
+
 func TestApply(t *testing.T) {
 	t.Run("regular apply", func(t *testing.T) {
 		repo, state := createTestRepository(t, createTestStateWithOnlyRoot)
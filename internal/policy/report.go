@@ -0,0 +1,387 @@
+// Copyright The gittuf Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package policy
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/gittuf/gittuf/internal/common/set"
+	"github.com/gittuf/gittuf/internal/version"
+)
+
+const (
+	sarifVersion   = "2.1.0"
+	sarifSchemaURI = "https://json.schemastore.org/sarif-2.1.0.json"
+	sarifToolName  = "gittuf"
+	sarifToolURI   = "https://gittuf.dev"
+)
+
+// CheckType identifies the kind of check recorded in a VerificationReport.
+type CheckType string
+
+const (
+	// CheckTypeExpiry is recorded when the policy's metadata is checked for
+	// expiry at the time an RSL entry was recorded.
+	CheckTypeExpiry CheckType = "expiry"
+
+	// CheckTypeRule is recorded when a rule protecting a Git reference or file
+	// is verified.
+	CheckTypeRule CheckType = "rule"
+
+	// CheckTypeGlobalRule is recorded when a global rule is verified.
+	CheckTypeGlobalRule CheckType = "global-rule"
+
+	// CheckTypeTagObject is recorded when the signature of a tag object is
+	// verified.
+	CheckTypeTagObject CheckType = "tag-object"
+
+	// CheckTypePolicy is recorded when a new policy state is verified using the
+	// prior policy state.
+	CheckTypePolicy CheckType = "policy"
+
+	// CheckTypeRecovery is recorded when the RSL is checked for a fix for an
+	// invalid entry.
+	CheckTypeRecovery CheckType = "recovery"
+
+	// CheckTypeRefTip is recorded when the tip of the verified reference is
+	// compared with the latest RSL entry.
+	CheckTypeRefTip CheckType = "ref-tip"
+
+	// CheckTypeCheckpoint is recorded when verification begins from an RSL
+	// checkpoint approved by a threshold of root principals.
+	CheckTypeCheckpoint CheckType = "checkpoint"
+
+	// CheckTypeKeyRevocation is recorded when an RSL entry is found to be
+	// signed using a key revoked before the entry was recorded.
+	CheckTypeKeyRevocation CheckType = "key-revocation"
+
+	// CheckTypeAnnotation is recorded when the skip annotations for an RSL
+	// entry are checked against rules that control who may annotate the
+	// entry's reference.
+	CheckTypeAnnotation CheckType = "annotation"
+)
+
+// VerificationCheck records a single check performed during verification and
+// its outcome.
+type VerificationCheck struct {
+	Type      CheckType `json:"type"`
+	EntryID   string    `json:"entryID,omitempty"`
+	Ref       string    `json:"ref,omitempty"`
+	CommitID  string    `json:"commitID,omitempty"`
+	Path      string    `json:"path,omitempty"`
+	Rule      string    `json:"rule,omitempty"`
+	Threshold int       `json:"threshold,omitempty"`
+
+	// SignedBy records the principals whose signatures or approvals were
+	// counted for the check.
+	SignedBy []string `json:"signedBy,omitempty"`
+
+	Passed bool `json:"passed"`
+
+	// Skipped is set for failed checks of RSL entries that have been marked as
+	// skipped and fixed by a subsequent entry.
+	Skipped bool   `json:"skipped,omitempty"`
+	Message string `json:"message,omitempty"`
+}
+
+// VerificationReport records the checks performed by a PolicyVerifier and the
+// outcome of verification.
+type VerificationReport struct {
+	Ref string `json:"ref"`
+
+	// FeatureID is set when the report is for checking if a change is
+	// mergeable.
+	FeatureID string `json:"featureID,omitempty"`
+
+	// RSLEntrySignatureNeeded is set when a change is mergeable only if the
+	// merge is performed by an authorized person.
+	RSLEntrySignatureNeeded bool `json:"rslEntrySignatureNeeded,omitempty"`
+
+	Passed bool                 `json:"passed"`
+	Error  string               `json:"error,omitempty"`
+	Checks []*VerificationCheck `json:"checks"`
+}
+
+// NewVerificationReport returns an empty report for verification of ref.
+func NewVerificationReport(ref string) *VerificationReport {
+	return &VerificationReport{Ref: ref, Checks: []*VerificationCheck{}}
+}
+
+// AddCheck records the check in the report.
+func (r *VerificationReport) AddCheck(check *VerificationCheck) {
+	if r == nil {
+		return
+	}
+
+	r.Checks = append(r.Checks, check)
+}
+
+// SetOutcome records the outcome of verification using the error returned by
+// the verification workflow.
+func (r *VerificationReport) SetOutcome(err error) {
+	if r == nil {
+		return
+	}
+
+	r.Passed = err == nil
+	r.Error = ""
+	if err != nil {
+		r.Error = err.Error()
+	}
+}
+
+// Failures returns the failed checks in the report that were not skipped.
+func (r *VerificationReport) Failures() []*VerificationCheck {
+	failures := []*VerificationCheck{}
+	for _, check := range r.Checks {
+		if !check.Passed && !check.Skipped {
+			failures = append(failures, check)
+		}
+	}
+
+	return failures
+}
+
+// markEntrySkipped flags the failed checks recorded for the entry as skipped.
+func (r *VerificationReport) markEntrySkipped(entryID string) {
+	if r == nil {
+		return
+	}
+
+	for _, check := range r.Checks {
+		if check.EntryID == entryID && !check.Passed {
+			check.Skipped = true
+		}
+	}
+}
+
+// MarshalSARIF returns the report in the SARIF 2.1.0 format. Each check is
+// recorded as a result, with failed checks reported as errors.
+func (r *VerificationReport) MarshalSARIF() ([]byte, error) {
+	return MarshalSARIF(r)
+}
+
+// MarshalSARIF returns the specified reports as a single SARIF 2.1.0 log. Each
+// check in each report is recorded as a result, with failed checks reported as
+// errors.
+func MarshalSARIF(reports ...*VerificationReport) ([]byte, error) {
+	rules := []*sarifRule{}
+	seenRules := set.NewSet[string]()
+
+	results := []*sarifResult{}
+	for _, report := range reports {
+		for _, check := range report.Checks {
+			ruleID := fmt.Sprintf("gittuf/%s", check.Type)
+			if !seenRules.Has(ruleID) {
+				seenRules.Add(ruleID)
+				rules = append(rules, &sarifRule{
+					ID:               ruleID,
+					ShortDescription: &sarifMessage{Text: fmt.Sprintf("gittuf %s check", check.Type)},
+				})
+			}
+
+			results = append(results, check.sarifResult(ruleID))
+		}
+	}
+
+	log := &sarifLog{
+		Version: sarifVersion,
+		Schema:  sarifSchemaURI,
+		Runs: []*sarifRun{
+			{
+				Tool: &sarifTool{
+					Driver: &sarifDriver{
+						Name:           sarifToolName,
+						Version:        version.GetVersion(),
+						InformationURI: sarifToolURI,
+						Rules:          rules,
+					},
+				},
+				Results: results,
+			},
+		},
+	}
+
+	return json.MarshalIndent(log, "", "  ")
+}
+
+// sarifResult returns the check as a SARIF result for the specified rule.
+func (c *VerificationCheck) sarifResult(ruleID string) *sarifResult {
+	result := &sarifResult{
+		RuleID:  ruleID,
+		Kind:    "pass",
+		Level:   "none",
+		Message: &sarifMessage{Text: c.summary()},
+		Properties: map[string]any{
+			"entryID":   c.EntryID,
+			"ref":       c.Ref,
+			"commitID":  c.CommitID,
+			"rule":      c.Rule,
+			"threshold": c.Threshold,
+			"signedBy":  c.SignedBy,
+			"skipped":   c.Skipped,
+		},
+	}
+	if !c.Passed {
+		result.Kind = "fail"
+		result.Level = "error"
+		if c.Skipped {
+			result.Level = "note"
+		}
+	}
+	if c.Path != "" {
+		result.Locations = []*sarifLocation{{PhysicalLocation: &sarifPhysicalLocation{ArtifactLocation: &sarifArtifactLocation{URI: c.Path}}}}
+	}
+
+	return result
+}
+
+// summary returns a human readable description of the check and its outcome.
+func (c *VerificationCheck) summary() string {
+	var sb strings.Builder
+
+	outcome := "passed"
+	if !c.Passed {
+		outcome = "failed"
+		if c.Skipped {
+			outcome = "failed (entry skipped)"
+		}
+	}
+	fmt.Fprintf(&sb, "%s check %s", c.Type, outcome)
+
+	if c.Rule != "" {
+		fmt.Fprintf(&sb, " for rule '%s'", c.Rule)
+	}
+	if c.Ref != "" {
+		fmt.Fprintf(&sb, " on '%s'", c.Ref)
+	}
+	if c.Path != "" {
+		fmt.Fprintf(&sb, " for path '%s'", c.Path)
+	}
+	if c.EntryID != "" {
+		fmt.Fprintf(&sb, " in RSL entry '%s'", c.EntryID)
+	}
+	if c.CommitID != "" {
+		fmt.Fprintf(&sb, " at commit '%s'", c.CommitID)
+	}
+	if c.Threshold != 0 {
+		fmt.Fprintf(&sb, ", required threshold %d, signed by %d", c.Threshold, len(c.SignedBy))
+		if len(c.SignedBy) != 0 {
+			fmt.Fprintf(&sb, " (%s)", strings.Join(c.SignedBy, ", "))
+		}
+	}
+	if c.Message != "" {
+		fmt.Fprintf(&sb, ": %s", c.Message)
+	}
+
+	return sb.String()
+}
+
+// checkRecorder records checks into a report, filling in the details of the
+// entry, commit, and path under verification.
+type checkRecorder struct {
+	report *VerificationReport
+	base   VerificationCheck
+}
+
+// record adds the check to the report. It is safe to call on a nil recorder.
+func (c *checkRecorder) record(check *VerificationCheck) {
+	if c == nil || c.report == nil {
+		return
+	}
+
+	if check.EntryID == "" {
+		check.EntryID = c.base.EntryID
+	}
+	if check.Ref == "" {
+		check.Ref = c.base.Ref
+	}
+	if check.CommitID == "" {
+		check.CommitID = c.base.CommitID
+	}
+	if check.Path == "" {
+		check.Path = c.base.Path
+	}
+
+	c.report.AddCheck(check)
+}
+
+// forPath returns a recorder for checks of the specified commit and path.
+func (c *checkRecorder) forPath(commitID, path string) *checkRecorder {
+	if c == nil {
+		return nil
+	}
+
+	base := c.base
+	base.CommitID = commitID
+	base.Path = path
+	return &checkRecorder{report: c.report, base: base}
+}
+
+// sortedPrincipalIDs returns the contents of the set in a deterministic order
+// for reporting.
+func sortedPrincipalIDs(principalIDs *set.Set[string]) []string {
+	if principalIDs == nil {
+		return nil
+	}
+
+	contents := principalIDs.Contents()
+	slices.Sort(contents)
+	return contents
+}
+
+type sarifLog struct {
+	Version string      `json:"version"`
+	Schema  string      `json:"$schema"`
+	Runs    []*sarifRun `json:"runs"`
+}
+
+type sarifRun struct {
+	Tool    *sarifTool     `json:"tool"`
+	Results []*sarifResult `json:"results"`
+}
+
+type sarifTool struct {
+	Driver *sarifDriver `json:"driver"`
+}
+
+type sarifDriver struct {
+	Name           string       `json:"name"`
+	Version        string       `json:"version,omitempty"`
+	InformationURI string       `json:"informationUri,omitempty"`
+	Rules          []*sarifRule `json:"rules,omitempty"`
+}
+
+type sarifRule struct {
+	ID               string        `json:"id"`
+	ShortDescription *sarifMessage `json:"shortDescription,omitempty"`
+}
+
+type sarifResult struct {
+	RuleID     string           `json:"ruleId"`
+	Kind       string           `json:"kind"`
+	Level      string           `json:"level"`
+	Message    *sarifMessage    `json:"message"`
+	Locations  []*sarifLocation `json:"locations,omitempty"`
+	Properties map[string]any   `json:"properties,omitempty"`
+}
+
+type sarifMessage struct {
+	Text string `json:"text"`
+}
+
+type sarifLocation struct {
+	PhysicalLocation *sarifPhysicalLocation `json:"physicalLocation"`
+}
+
+type sarifPhysicalLocation struct {
+	ArtifactLocation *sarifArtifactLocation `json:"artifactLocation"`
+}
+
+type sarifArtifactLocation struct {
+	URI string `json:"uri"`
+}
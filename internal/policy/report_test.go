@@ -0,0 +1,182 @@
+// Copyright The gittuf Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package policy
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/gittuf/gittuf/internal/common"
+	"github.com/gittuf/gittuf/internal/rsl"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestVerificationReport(t *testing.T) {
+	t.Run("outcome", func(t *testing.T) {
+		report := NewVerificationReport("refs/heads/main")
+		report.SetOutcome(nil)
+		assert.True(t, report.Passed)
+		assert.Empty(t, report.Error)
+
+		report.SetOutcome(ErrVerificationFailed)
+		assert.False(t, report.Passed)
+		assert.Equal(t, ErrVerificationFailed.Error(), report.Error)
+	})
+
+	t.Run("nil report", func(t *testing.T) {
+		var report *VerificationReport
+		assert.NotPanics(t, func() {
+			report.AddCheck(&VerificationCheck{Type: CheckTypeRule})
+			report.SetOutcome(nil)
+		})
+
+		var recorder *checkRecorder
+		assert.NotPanics(t, func() {
+			recorder.record(&VerificationCheck{Type: CheckTypeRule})
+		})
+		assert.Nil(t, recorder.forPath("commit", "path"))
+	})
+
+	t.Run("failures exclude skipped entries", func(t *testing.T) {
+		report := NewVerificationReport("refs/heads/main")
+		recorder := &checkRecorder{report: report, base: VerificationCheck{EntryID: "entry-1", Ref: "refs/heads/main"}}
+		recorder.record(&VerificationCheck{Type: CheckTypeRule, Rule: "protect-main", Passed: false})
+		recorder.forPath("commit", "foo/bar").record(&VerificationCheck{Type: CheckTypeRule, Rule: "protect-foo", Passed: true})
+
+		require.Len(t, report.Checks, 2)
+		assert.Equal(t, "entry-1", report.Checks[0].EntryID)
+		assert.Equal(t, "refs/heads/main", report.Checks[0].Ref)
+		assert.Equal(t, "foo/bar", report.Checks[1].Path)
+		assert.Equal(t, "commit", report.Checks[1].CommitID)
+		assert.Len(t, report.Failures(), 1)
+
+		report.markEntrySkipped("entry-1")
+		assert.True(t, report.Checks[0].Skipped)
+		assert.False(t, report.Checks[1].Skipped)
+		assert.Empty(t, report.Failures())
+	})
+}
+
+func TestVerificationReportMarshalSARIF(t *testing.T) {
+	report := NewVerificationReport("refs/heads/main")
+	report.AddCheck(&VerificationCheck{Type: CheckTypeExpiry, EntryID: "entry-1", Passed: true})
+	report.AddCheck(&VerificationCheck{Type: CheckTypeRule, EntryID: "entry-1", Path: "foo/bar", Rule: "protect-foo", Threshold: 2, SignedBy: []string{"alice"}, Passed: false})
+	report.SetOutcome(ErrVerificationFailed)
+
+	sarifBytes, err := report.MarshalSARIF()
+	require.Nil(t, err)
+
+	log := &sarifLog{}
+	err = json.Unmarshal(sarifBytes, log)
+	require.Nil(t, err)
+
+	assert.Equal(t, sarifVersion, log.Version)
+	require.Len(t, log.Runs, 1)
+	assert.Equal(t, sarifToolName, log.Runs[0].Tool.Driver.Name)
+	assert.Len(t, log.Runs[0].Tool.Driver.Rules, 2)
+
+	results := log.Runs[0].Results
+	require.Len(t, results, 2)
+
+	assert.Equal(t, "gittuf/expiry", results[0].RuleID)
+	assert.Equal(t, "pass", results[0].Kind)
+	assert.Equal(t, "none", results[0].Level)
+
+	assert.Equal(t, "gittuf/rule", results[1].RuleID)
+	assert.Equal(t, "fail", results[1].Kind)
+	assert.Equal(t, "error", results[1].Level)
+	assert.Equal(t, "foo/bar", results[1].Locations[0].PhysicalLocation.ArtifactLocation.URI)
+	assert.Contains(t, results[1].Message.Text, "required threshold 2, signed by 1 (alice)")
+}
+
+func TestVerifyEntryReport(t *testing.T) {
+	refName := "refs/heads/main"
+
+	t.Run("successful verification", func(t *testing.T) {
+		repo, state := createTestRepository(t, createTestStateWithPolicy)
+
+		commitIDs := common.AddNTestCommitsToSpecifiedRef(t, repo, refName, 1, gpgKeyBytes)
+		entry := rsl.NewReferenceEntry(refName, commitIDs[0])
+		entryID := common.CreateTestRSLReferenceEntryCommit(t, repo, entry, gpgKeyBytes)
+		entry.ID = entryID
+
+		report := NewVerificationReport(refName)
+		err := verifyEntry(testCtx, repo, state, nil, entry, report)
+		assert.Nil(t, err)
+
+		assert.Empty(t, report.Failures())
+
+		ruleChecks := checksOfType(report, CheckTypeRule)
+		require.Len(t, ruleChecks, 2)
+
+		// The RSL entry is verified for the Git namespace rule
+		assert.Equal(t, "protect-main", ruleChecks[0].Rule)
+		assert.Equal(t, 1, ruleChecks[0].Threshold)
+		assert.Len(t, ruleChecks[0].SignedBy, 1)
+		assert.Equal(t, entryID.String(), ruleChecks[0].EntryID)
+		assert.Equal(t, refName, ruleChecks[0].Ref)
+		assert.Empty(t, ruleChecks[0].Path)
+
+		// The commit is verified for the file namespace rule
+		assert.Equal(t, "protect-files-1-and-2", ruleChecks[1].Rule)
+		assert.Equal(t, "1", ruleChecks[1].Path)
+		assert.Equal(t, commitIDs[0].String(), ruleChecks[1].CommitID)
+	})
+
+	t.Run("unsuccessful verification", func(t *testing.T) {
+		repo, state := createTestRepository(t, createTestStateWithPolicy)
+
+		commitIDs := common.AddNTestCommitsToSpecifiedRef(t, repo, refName, 1, gpgUnauthorizedKeyBytes)
+		entry := rsl.NewReferenceEntry(refName, commitIDs[0])
+		entryID := common.CreateTestRSLReferenceEntryCommit(t, repo, entry, gpgKeyBytes)
+		entry.ID = entryID
+
+		report := NewVerificationReport(refName)
+		err := verifyEntry(testCtx, repo, state, nil, entry, report)
+		assert.ErrorIs(t, err, ErrVerificationFailed)
+
+		// The RSL entry is signed by an authorized key, but the commit that
+		// modifies protected files is not
+		failures := report.Failures()
+		require.Len(t, failures, 1)
+		assert.Equal(t, CheckTypeRule, failures[0].Type)
+		assert.Equal(t, "protect-files-1-and-2", failures[0].Rule)
+		assert.Equal(t, "1", failures[0].Path)
+		assert.Equal(t, 1, failures[0].Threshold)
+		assert.Empty(t, failures[0].SignedBy)
+		assert.Equal(t, entryID.String(), failures[0].EntryID)
+	})
+}
+
+func TestPolicyVerifierReport(t *testing.T) {
+	repo, _ := createTestRepository(t, createTestStateWithPolicy)
+	refName := "refs/heads/main"
+
+	commitIDs := common.AddNTestCommitsToSpecifiedRef(t, repo, refName, 1, gpgUnauthorizedKeyBytes)
+	entry := rsl.NewReferenceEntry(refName, commitIDs[0])
+	common.CreateTestRSLReferenceEntryCommit(t, repo, entry, gpgKeyBytes)
+
+	verifier := NewPolicyVerifier(repo)
+	_, err := verifier.VerifyRef(testCtx, refName)
+	assert.ErrorIs(t, err, ErrVerificationFailed)
+
+	report := verifier.Report()
+	require.NotNil(t, report)
+	assert.Equal(t, refName, report.Ref)
+	assert.False(t, report.Passed)
+	assert.Contains(t, report.Error, ErrVerificationFailed.Error())
+	assert.NotEmpty(t, report.Failures())
+}
+
+func checksOfType(report *VerificationReport, checkType CheckType) []*VerificationCheck {
+	checks := []*VerificationCheck{}
+	for _, check := range report.Checks {
+		if check.Type == checkType {
+			checks = append(checks, check)
+		}
+	}
+
+	return checks
+}
@@ -0,0 +1,204 @@
+// Copyright The gittuf Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package policy
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/gittuf/gittuf/internal/common/set"
+	"github.com/gittuf/gittuf/internal/gitinterface"
+	"github.com/gittuf/gittuf/internal/rsl"
+	"github.com/gittuf/gittuf/internal/tuf"
+	"github.com/secure-systems-lab/go-securesystemslib/signerverifier"
+)
+
+var ErrKeyRevoked = errors.New("signed using revoked key")
+
+// keyRevocation is a key revocation with its revocation point resolved against
+// the RSL.
+type keyRevocation struct {
+	key *signerverifier.SSLibKey
+
+	// entryNumber is the number of the RSL entry after which the key is
+	// revoked. It's zero if the key is revoked after a time, or if the entry
+	// predates RSL entry numbers.
+	entryNumber uint64
+	// revokedAfter is the time after which the key is revoked. When the key
+	// is revoked after an RSL entry, it's the time the entry was recorded.
+	revokedAfter time.Time
+}
+
+// appliesTo indicates whether the key is revoked for the RSL entry with the
+// specified number recorded at the specified time. RSL entry numbers are
+// compared when available as they can't be backdated.
+func (r *keyRevocation) appliesTo(entryNumber uint64, entryTime time.Time) bool {
+	if r.entryNumber != 0 && entryNumber != 0 {
+		return entryNumber > r.entryNumber
+	}
+
+	return entryTime.After(r.revokedAfter)
+}
+
+// resolveKeyRevocations resolves the revocation point of each key revocation
+// using the RSL entries in the repository.
+func resolveKeyRevocations(repo *gitinterface.Repository, keyRevocations []tuf.KeyRevocation) ([]*keyRevocation, error) {
+	resolved := make([]*keyRevocation, 0, len(keyRevocations))
+	for _, revocation := range keyRevocations {
+		entryIDString := revocation.GetRevokedAfterEntryID()
+		if entryIDString == "" {
+			resolved = append(resolved, &keyRevocation{key: revocation.GetKey(), revokedAfter: revocation.GetRevokedAfterTime()})
+			continue
+		}
+
+		if repo == nil {
+			return nil, fmt.Errorf("unable to resolve revocation of key '%s' without repository", revocation.GetKey().KeyID)
+		}
+
+		entryID, err := gitinterface.NewHash(entryIDString)
+		if err != nil {
+			return nil, err
+		}
+		entry, err := rsl.GetEntry(repo, entryID)
+		if err != nil {
+			return nil, fmt.Errorf("unable to find RSL entry '%s' that key '%s' is revoked after: %w", entryIDString, revocation.GetKey().KeyID, err)
+		}
+		entryTime, err := repo.GetCommitTime(entryID)
+		if err != nil {
+			return nil, err
+		}
+
+		resolved = append(resolved, &keyRevocation{key: revocation.GetKey(), entryNumber: entry.GetNumber(), revokedAfter: entryTime})
+	}
+
+	return resolved, nil
+}
+
+// addKeyRevocations adds the key revocations to the state, replacing any
+// revocation of the same key recorded in the state. This is used to enforce
+// key revocations recorded in the latest policy when verifying RSL entries
+// using earlier policy states.
+func (s *State) addKeyRevocations(keyRevocations []*keyRevocation) {
+	for _, revocation := range keyRevocations {
+		index := slices.IndexFunc(s.keyRevocations, func(existing *keyRevocation) bool {
+			return existing.key.KeyID == revocation.key.KeyID
+		})
+		if index == -1 {
+			s.keyRevocations = append(s.keyRevocations, revocation)
+		} else {
+			s.keyRevocations[index] = revocation
+		}
+	}
+}
+
+// getRevokedKeys returns the keys revoked for the RSL entry with the specified
+// number recorded at the specified time.
+func (s *State) getRevokedKeys(entryNumber uint64, entryTime time.Time) []*signerverifier.SSLibKey {
+	revokedKeys := []*signerverifier.SSLibKey{}
+	for _, revocation := range s.keyRevocations {
+		if revocation.appliesTo(entryNumber, entryTime) {
+			revokedKeys = append(revokedKeys, revocation.key)
+		}
+	}
+
+	return revokedKeys
+}
+
+// getRevokedKeyIDs returns the IDs of the keys revoked for the RSL entry with
+// the specified number recorded at the specified time.
+func (s *State) getRevokedKeyIDs(entryNumber uint64, entryTime time.Time) *set.Set[string] {
+	revokedKeyIDs := set.NewSet[string]()
+	for _, key := range s.getRevokedKeys(entryNumber, entryTime) {
+		revokedKeyIDs.Add(key.KeyID)
+	}
+
+	return revokedKeyIDs
+}
+
+// checkEntryNotSignedByRevokedKey returns an error if the RSL entry was signed
+// using a key revoked for the entry.
+func checkEntryNotSignedByRevokedKey(ctx context.Context, repo *gitinterface.Repository, policy *State, entry *rsl.ReferenceEntry, entryTime time.Time) error {
+	for _, key := range policy.getRevokedKeys(entry.GetNumber(), entryTime) {
+		if isSignedByKey(ctx, repo, entry.GetID(), key) {
+			return fmt.Errorf("%w '%s'", ErrKeyRevoked, key.KeyID)
+		}
+	}
+
+	return nil
+}
+
+// AffectedEntry records an RSL entry that was signed using a revoked key after
+// the key was revoked, either in the entry itself or in the Git object the
+// entry records.
+type AffectedEntry struct {
+	EntryID      string `json:"entryID"`
+	EntryNumber  uint64 `json:"entryNumber,omitempty"`
+	Ref          string `json:"ref"`
+	KeyID        string `json:"keyID"`
+	SignedEntry  bool   `json:"signedEntry"`
+	SignedTarget bool   `json:"signedTarget"`
+}
+
+// GetEntriesAffectedByKeyRevocations returns the RSL reference entries in the
+// repository that were signed using a key revoked in the state after the key
+// was revoked. The entries are returned in the order they were recorded in the
+// RSL.
+func GetEntriesAffectedByKeyRevocations(ctx context.Context, repo *gitinterface.Repository, state *State) ([]*AffectedEntry, error) {
+	affectedEntries := []*AffectedEntry{}
+	if len(state.keyRevocations) == 0 {
+		return affectedEntries, nil
+	}
+
+	entry, err := rsl.GetLatestEntry(repo)
+	if err != nil {
+		if errors.Is(err, rsl.ErrRSLEntryNotFound) {
+			return affectedEntries, nil
+		}
+		return nil, err
+	}
+
+	for {
+		if entry, isReferenceEntry := entry.(*rsl.ReferenceEntry); isReferenceEntry {
+			entryTime, err := repo.GetCommitTime(entry.GetID())
+			if err != nil {
+				return nil, err
+			}
+
+			for _, key := range state.getRevokedKeys(entry.GetNumber(), entryTime) {
+				signedEntry := isSignedByKey(ctx, repo, entry.GetID(), key)
+				signedTarget := !entry.GetTargetID().IsZero() && isSignedByKey(ctx, repo, entry.GetTargetID(), key)
+				if signedEntry || signedTarget {
+					affectedEntries = append(affectedEntries, &AffectedEntry{
+						EntryID:      entry.GetID().String(),
+						EntryNumber:  entry.GetNumber(),
+						Ref:          entry.GetRefName(),
+						KeyID:        key.KeyID,
+						SignedEntry:  signedEntry,
+						SignedTarget: signedTarget,
+					})
+				}
+			}
+		}
+
+		entry, err = rsl.GetParentForEntry(repo, entry)
+		if err != nil {
+			if errors.Is(err, rsl.ErrRSLEntryNotFound) {
+				break
+			}
+			return nil, err
+		}
+	}
+
+	slices.Reverse(affectedEntries)
+	return affectedEntries, nil
+}
+
+// isSignedByKey indicates whether the Git commit or tag was signed using the
+// key.
+func isSignedByKey(ctx context.Context, repo *gitinterface.Repository, objectID gitinterface.Hash, key *signerverifier.SSLibKey) bool {
+	return repo.VerifySignature(ctx, objectID, key) == nil
+}
@@ -0,0 +1,152 @@
+// Copyright The gittuf Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package policy
+
+import (
+	"testing"
+	"time"
+
+	"github.com/gittuf/gittuf/internal/common"
+	"github.com/gittuf/gittuf/internal/gitinterface"
+	"github.com/gittuf/gittuf/internal/rsl"
+	"github.com/gittuf/gittuf/internal/signerverifier/gpg"
+	"github.com/gittuf/gittuf/internal/tuf"
+	tufv04 "github.com/gittuf/gittuf/internal/tuf/v04"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestKeyRevocationAppliesTo(t *testing.T) {
+	revokedAfter := time.Date(2025, time.January, 1, 0, 0, 0, 0, time.UTC)
+
+	t.Run("revoked after entry", func(t *testing.T) {
+		revocation := &keyRevocation{entryNumber: 5, revokedAfter: revokedAfter}
+
+		assert.False(t, revocation.appliesTo(5, revokedAfter.Add(time.Hour)))
+		assert.True(t, revocation.appliesTo(6, revokedAfter.Add(-time.Hour)))
+
+		// Times are compared for entries without numbers
+		assert.False(t, revocation.appliesTo(0, revokedAfter))
+		assert.True(t, revocation.appliesTo(0, revokedAfter.Add(time.Second)))
+	})
+
+	t.Run("revoked after time", func(t *testing.T) {
+		revocation := &keyRevocation{revokedAfter: revokedAfter}
+
+		assert.False(t, revocation.appliesTo(10, revokedAfter))
+		assert.True(t, revocation.appliesTo(1, revokedAfter.Add(time.Second)))
+	})
+}
+
+func TestKeyRevocationVerification(t *testing.T) {
+	refName := "refs/heads/main"
+
+	gpgKeyR, err := gpg.LoadGPGKeyFromBytes(gpgPubKeyBytes)
+	if err != nil {
+		t.Fatal(err)
+	}
+	gpgKey := tufv04.NewKeyFromSSLibKey(gpgKeyR)
+
+	addEntry := func(t *testing.T, repo *gitinterface.Repository) *rsl.ReferenceEntry {
+		t.Helper()
+
+		commitIDs := common.AddNTestCommitsToSpecifiedRef(t, repo, refName, 1, gpgKeyBytes)
+		entry := rsl.NewReferenceEntry(refName, commitIDs[0])
+		entry.ID = common.CreateTestRSLReferenceEntryCommit(t, repo, entry, gpgKeyBytes)
+
+		entryT, err := rsl.GetEntry(repo, entry.ID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return entryT.(*rsl.ReferenceEntry)
+	}
+
+	t.Run("revoked after entry", func(t *testing.T) {
+		repo, state := createTestRepository(t, createTestStateWithPolicy)
+
+		beforeEntry := addEntry(t, repo)
+		afterEntry := addEntry(t, repo)
+
+		keyRevocations, err := resolveKeyRevocations(repo, []tuf.KeyRevocation{
+			&tufv04.KeyRevocation{Key: gpgKey, RevokedAfterEntryID: beforeEntry.GetID().String()},
+		})
+		require.Nil(t, err)
+		require.Len(t, keyRevocations, 1)
+		assert.Equal(t, beforeEntry.GetNumber(), keyRevocations[0].entryNumber)
+		state.addKeyRevocations(keyRevocations)
+
+		err = verifyEntry(testCtx, repo, state, nil, beforeEntry, nil)
+		assert.Nil(t, err)
+
+		report := NewVerificationReport(refName)
+		err = verifyEntry(testCtx, repo, state, nil, afterEntry, report)
+		assert.ErrorIs(t, err, ErrVerificationFailed)
+		assert.ErrorIs(t, err, ErrKeyRevoked)
+		require.NotEmpty(t, report.Checks)
+		lastCheck := report.Checks[len(report.Checks)-1]
+		assert.Equal(t, CheckTypeKeyRevocation, lastCheck.Type)
+		assert.False(t, lastCheck.Passed)
+
+		affectedEntries, err := GetEntriesAffectedByKeyRevocations(testCtx, repo, state)
+		require.Nil(t, err)
+		assert.Equal(t, []*AffectedEntry{{
+			EntryID:      afterEntry.GetID().String(),
+			EntryNumber:  afterEntry.GetNumber(),
+			Ref:          refName,
+			KeyID:        gpgKey.KeyID,
+			SignedEntry:  true,
+			SignedTarget: true,
+		}}, affectedEntries)
+	})
+
+	t.Run("revoked after time", func(t *testing.T) {
+		repo, state := createTestRepository(t, createTestStateWithPolicy)
+
+		entry := addEntry(t, repo)
+		entryTime, err := repo.GetCommitTime(entry.GetID())
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		revokedAfter := entryTime.Add(-time.Hour)
+		keyRevocations, err := resolveKeyRevocations(repo, []tuf.KeyRevocation{
+			&tufv04.KeyRevocation{Key: gpgKey, RevokedAfterTime: &revokedAfter},
+		})
+		require.Nil(t, err)
+		state.addKeyRevocations(keyRevocations)
+
+		err = verifyEntry(testCtx, repo, state, nil, entry, nil)
+		assert.ErrorIs(t, err, ErrKeyRevoked)
+	})
+
+	t.Run("revocation replaced", func(t *testing.T) {
+		repo, state := createTestRepository(t, createTestStateWithPolicy)
+
+		entry := addEntry(t, repo)
+		entryTime, err := repo.GetCommitTime(entry.GetID())
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		state.addKeyRevocations([]*keyRevocation{{key: gpgKeyR, revokedAfter: entryTime.Add(-time.Hour)}})
+		state.addKeyRevocations([]*keyRevocation{{key: gpgKeyR, revokedAfter: entryTime.Add(time.Hour)}})
+		assert.Len(t, state.keyRevocations, 1)
+
+		err = verifyEntry(testCtx, repo, state, nil, entry, nil)
+		assert.Nil(t, err)
+
+		affectedEntries, err := GetEntriesAffectedByKeyRevocations(testCtx, repo, state)
+		require.Nil(t, err)
+		assert.Empty(t, affectedEntries)
+	})
+
+	t.Run("revoked after unknown entry", func(t *testing.T) {
+		repo, _ := createTestRepository(t, createTestStateWithPolicy)
+
+		_, err := resolveKeyRevocations(repo, []tuf.KeyRevocation{
+			&tufv04.KeyRevocation{Key: gpgKey, RevokedAfterEntryID: gitinterface.ZeroHash.String()},
+		})
+		assert.NotNil(t, err)
+	})
+}
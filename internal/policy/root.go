@@ -6,15 +6,22 @@ package policy
 import (
 	"time"
 
+	"github.com/gittuf/gittuf/internal/dev"
 	"github.com/gittuf/gittuf/internal/tuf"
 	tufv02 "github.com/gittuf/gittuf/internal/tuf/v02"
+	tufv04 "github.com/gittuf/gittuf/internal/tuf/v04"
 )
 
 // InitializeRootMetadata initializes a new instance of tuf.RootMetadata with
 // default values and a given key. The default values are version set to 1,
-// expiry date set to one year from now, and the provided key is added.
+// expiry date set to one year from now, and the provided key is added. The
+// metadata uses the v04 schema in developer mode and the v02 schema otherwise.
 func InitializeRootMetadata(key tuf.Principal) (tuf.RootMetadata, error) {
-	rootMetadata := tufv02.NewRootMetadata()
+	var rootMetadata tuf.RootMetadata = tufv02.NewRootMetadata()
+	if dev.InDevMode() {
+		// The v04 schema is still under development
+		rootMetadata = tufv04.NewRootMetadata()
+	}
 	rootMetadata.SetExpires(time.Now().AddDate(1, 0, 0).Format(time.RFC3339))
 
 	if err := rootMetadata.AddRootPrincipal(key); err != nil {
@@ -4,12 +4,17 @@
 package policy
 
 import (
+	"cmp"
 	"context"
 	"errors"
 	"fmt"
 	"log/slog"
+	"maps"
+	"slices"
 	"strings"
+	"time"
 
+	"github.com/gittuf/gittuf/internal/attestations"
 	"github.com/gittuf/gittuf/internal/common/set"
 	"github.com/gittuf/gittuf/internal/gitinterface"
 	"github.com/gittuf/gittuf/internal/signerverifier/common"
@@ -29,6 +34,161 @@ type SignatureVerifier struct {
 	principals         []tuf.Principal
 	threshold          int
 	verifyExhaustively bool // verifyExhaustively checks all possible signatures and returns all matched principals, even if threshold is already met
+
+	// teams records the teams trusted by the verifier, keyed by team ID. The
+	// members of each team are included in principals, but a team is counted
+	// only once towards the threshold when its own threshold is met.
+	teams map[string]*verifierTeam
+
+	// directPrincipalIDs records the principals trusted directly by the
+	// verifier rather than via a team. It is only set when teams is set.
+	directPrincipalIDs *set.Set[string]
+
+	// keyRotations records the keys rotated out for the trusted principals,
+	// keyed by principal ID. An old key continues to be trusted for its
+	// principal until the end of the rotation's grace period.
+	keyRotations map[string][]tuf.KeyRotation
+
+	// verificationTime is the time the grace periods of key rotations are
+	// checked against, typically when the RSL entry being verified was
+	// recorded. If it's unset, the current time is used.
+	verificationTime time.Time
+
+	// revokedKeyIDs records the keys that are not trusted for any principal,
+	// as they were revoked before the RSL entry being verified was recorded.
+	revokedKeyIDs *set.Set[string]
+}
+
+// verifierTeam records the members of a team and the number of members who
+// must approve for the team to be counted towards a verifier's threshold.
+type verifierTeam struct {
+	memberIDs *set.Set[string]
+	threshold int
+}
+
+// setPrincipals sets the principals trusted by the verifier. Teams are expanded
+// into their members, with the team's threshold determined by teamThresholds or
+// the team's own definition.
+func (v *SignatureVerifier) setPrincipals(principals []tuf.Principal, teamThresholds map[string]int) {
+	v.principals = make([]tuf.Principal, 0, len(principals))
+	v.teams = nil
+	v.directPrincipalIDs = nil
+
+	seenPrincipalIDs := set.NewSet[string]()
+	addPrincipal := func(principal tuf.Principal) {
+		if seenPrincipalIDs.Has(principal.ID()) {
+			return
+		}
+		seenPrincipalIDs.Add(principal.ID())
+		v.principals = append(v.principals, principal)
+	}
+
+	directPrincipalIDs := set.NewSet[string]()
+	for _, principal := range principals {
+		team, isTeam := principal.(tuf.Team)
+		if !isTeam {
+			directPrincipalIDs.Add(principal.ID())
+			addPrincipal(principal)
+			continue
+		}
+
+		threshold := team.GetThreshold()
+		if teamThreshold, has := teamThresholds[team.ID()]; has {
+			threshold = teamThreshold
+		}
+
+		memberIDs := set.NewSet[string]()
+		for _, member := range team.GetMembers() {
+			memberIDs.Add(member.ID())
+			addPrincipal(member)
+		}
+
+		if v.teams == nil {
+			v.teams = map[string]*verifierTeam{}
+		}
+		v.teams[team.ID()] = &verifierTeam{memberIDs: memberIDs, threshold: threshold}
+	}
+
+	if len(v.teams) != 0 {
+		v.directPrincipalIDs = directPrincipalIDs
+	}
+}
+
+// at returns a copy of the verifier that checks the grace periods of key
+// rotations against the specified time.
+func (v *SignatureVerifier) at(verificationTime time.Time) *SignatureVerifier {
+	verifier := *v
+	verifier.verificationTime = verificationTime
+	return &verifier
+}
+
+// withoutKeys returns a copy of the verifier that doesn't trust the specified
+// revoked keys for any principal.
+func (v *SignatureVerifier) withoutKeys(revokedKeyIDs *set.Set[string]) *SignatureVerifier {
+	verifier := *v
+	verifier.revokedKeyIDs = revokedKeyIDs
+	return &verifier
+}
+
+// principalKeys returns the keys trusted for the principal. This includes keys
+// rotated out for the principal whose grace period had not ended at the
+// verification time, and excludes revoked keys.
+func (v *SignatureVerifier) principalKeys(principal tuf.Principal) []*signerverifier.SSLibKey {
+	keys := v.rotatedPrincipalKeys(principal)
+	if v.revokedKeyIDs == nil || v.revokedKeyIDs.Len() == 0 {
+		return keys
+	}
+
+	return slices.DeleteFunc(keys, func(key *signerverifier.SSLibKey) bool {
+		if v.revokedKeyIDs.Has(key.KeyID) {
+			slog.Debug(fmt.Sprintf("Not trusting revoked key '%s' for principal '%s'...", key.KeyID, principal.ID()))
+			return true
+		}
+		return false
+	})
+}
+
+// rotatedPrincipalKeys returns the keys trusted for the principal, including
+// keys rotated out for the principal whose grace period had not ended at the
+// verification time.
+func (v *SignatureVerifier) rotatedPrincipalKeys(principal tuf.Principal) []*signerverifier.SSLibKey {
+	keys := principal.Keys()
+
+	keyRotations := v.keyRotations[principal.ID()]
+	if len(keyRotations) == 0 {
+		return keys
+	}
+
+	verificationTime := v.verificationTime
+	if verificationTime.IsZero() {
+		verificationTime = time.Now()
+	}
+	for _, keyRotation := range keyRotations {
+		if verificationTime.Before(keyRotation.GetGracePeriodEnd()) {
+			slog.Debug(fmt.Sprintf("Trusting key '%s' rotated out for principal '%s' until %s...", keyRotation.GetOldKey().KeyID, principal.ID(), keyRotation.GetGracePeriodEnd().Format(time.RFC3339)))
+			keys = append(keys, keyRotation.GetOldKey())
+		}
+	}
+
+	return keys
+}
+
+// groupKeyRotations adds the key rotations to keyRotations, keyed by the
+// principal each applies to. Only rotations for the specified principals, which
+// are declared in the same metadata as the rotations, are added.
+func groupKeyRotations(keyRotations map[string][]tuf.KeyRotation, principals map[string]tuf.Principal, rotations []tuf.KeyRotation) map[string][]tuf.KeyRotation {
+	for _, keyRotation := range rotations {
+		if _, has := principals[keyRotation.GetPrincipalID()]; !has {
+			continue
+		}
+
+		if keyRotations == nil {
+			keyRotations = map[string][]tuf.KeyRotation{}
+		}
+		keyRotations[keyRotation.GetPrincipalID()] = append(keyRotations[keyRotation.GetPrincipalID()], keyRotation)
+	}
+
+	return keyRotations
 }
 
 func (v *SignatureVerifier) Name() string {
@@ -48,11 +208,187 @@ func (v *SignatureVerifier) TrustedPrincipalIDs() *set.Set[string] {
 	return principalIDs
 }
 
+// countTowardsThreshold returns the number of approvals in usedPrincipalIDs
+// that count towards the verifier's threshold. Each principal is counted at
+// most once: either directly, if the principal is trusted directly by the
+// verifier, or towards exactly one of the teams it is a member of. A team is
+// counted once if the threshold of its members is met. principalHats records
+// the role each principal declared their approval is made in, restricting the
+// approval to that role if it is the verifier's rule or one of its teams.
+func (v *SignatureVerifier) countTowardsThreshold(usedPrincipalIDs *set.Set[string], principalHats map[string]string) int {
+	if len(v.teams) == 0 && len(principalHats) == 0 {
+		return v.TrustedPrincipalIDs().Intersection(usedPrincipalIDs).Len()
+	}
+
+	count := 0
+	teamApprovers := map[string][]string{}
+	for _, principal := range v.principals {
+		principalID := principal.ID()
+		if !usedPrincipalIDs.Has(principalID) {
+			continue
+		}
+
+		hat, hasHat := principalHats[principalID]
+		if _, isTeam := v.teams[hat]; hasHat && hat != v.name && !isTeam {
+			// The role isn't one the verifier trusts principals in, so it
+			// doesn't restrict what the approval counts towards here
+			hasHat = false
+		}
+
+		if v.isTrustedDirectly(principalID) && (!hasHat || hat == v.name) {
+			// Counting a principal directly is always at least as good as
+			// counting it towards a team
+			count++
+			continue
+		}
+
+		for teamID, team := range v.teams {
+			if !team.memberIDs.Has(principalID) {
+				continue
+			}
+			if hasHat && hat != teamID {
+				slog.Debug(fmt.Sprintf("Principal '%s' declared role '%s', not counting principal towards team '%s'...", principalID, hat, teamID))
+				continue
+			}
+
+			teamApprovers[teamID] = append(teamApprovers[teamID], principalID)
+		}
+	}
+
+	return count + v.countTeamsMet(teamApprovers)
+}
+
+// countTeamsMet returns the number of teams whose thresholds are met when each
+// approving principal in teamApprovers is counted towards only one of the teams
+// it is listed for. Teams are considered in order of increasing threshold, and
+// a team is counted if its threshold can be met by matching approvers to it
+// without leaving a team counted earlier unmet. This bounds the work to a
+// polynomial in the number of approvals.
+func (v *SignatureVerifier) countTeamsMet(teamApprovers map[string][]string) int {
+	teamIDs := slices.SortedFunc(maps.Keys(v.teams), func(a, b string) int {
+		if c := cmp.Compare(v.teams[a].threshold, v.teams[b].threshold); c != 0 {
+			return c
+		}
+		return cmp.Compare(a, b)
+	})
+
+	// matchedTeams records the team each approver is counted towards
+	matchedTeams := map[string]string{}
+
+	// assign finds an approver for teamID, moving approvers counted towards
+	// other teams along an augmenting path if necessary
+	var assign func(teamID string, visited *set.Set[string]) bool
+	assign = func(teamID string, visited *set.Set[string]) bool {
+		for _, principalID := range teamApprovers[teamID] {
+			if visited.Has(principalID) {
+				continue
+			}
+			visited.Add(principalID)
+
+			matchedTeamID, matched := matchedTeams[principalID]
+			if !matched || assign(matchedTeamID, visited) {
+				matchedTeams[principalID] = teamID
+				return true
+			}
+		}
+
+		return false
+	}
+
+	met := 0
+	for _, teamID := range teamIDs {
+		team := v.teams[teamID]
+		if len(teamApprovers[teamID]) < team.threshold {
+			continue
+		}
+
+		previousMatchedTeams := maps.Clone(matchedTeams)
+		thresholdMet := true
+		for range team.threshold {
+			if !assign(teamID, set.NewSet[string]()) {
+				thresholdMet = false
+				break
+			}
+		}
+
+		if !thresholdMet {
+			matchedTeams = previousMatchedTeams
+			continue
+		}
+
+		met++
+	}
+
+	return met
+}
+
+// isTrustedDirectly indicates if the principal is trusted by the verifier
+// directly rather than via a team.
+func (v *SignatureVerifier) isTrustedDirectly(principalID string) bool {
+	if v.directPrincipalIDs == nil {
+		return len(v.teams) == 0
+	}
+
+	return v.directPrincipalIDs.Has(principalID)
+}
+
+// thresholdMetWithOneMorePrincipal indicates if the verifier's threshold would
+// be met if one more trusted principal were to approve.
+func (v *SignatureVerifier) thresholdMetWithOneMorePrincipal(usedPrincipalIDs *set.Set[string], principalHats map[string]string) bool {
+	if len(v.teams) == 0 && len(principalHats) == 0 {
+		return v.countTowardsThreshold(usedPrincipalIDs, nil) >= v.threshold-1
+	}
+
+	for _, principal := range v.principals {
+		if usedPrincipalIDs.Has(principal.ID()) {
+			continue
+		}
+
+		candidatePrincipalIDs := set.NewSetFromItems(principal.ID())
+		candidatePrincipalIDs.Extend(usedPrincipalIDs)
+		if v.countTowardsThreshold(candidatePrincipalIDs, principalHats) >= v.threshold {
+			return true
+		}
+	}
+
+	return false
+}
+
+// getPrincipalHats returns the roles declared in the reference authorization
+// in env by the principals trusted by the verifier, keyed by principal ID.
+func (v *SignatureVerifier) getPrincipalHats(env *sslibdsse.Envelope) (map[string]string, error) {
+	if env == nil {
+		return nil, nil
+	}
+
+	keyHats, err := attestations.GetReferenceAuthorizationHats(env)
+	if err != nil {
+		return nil, err
+	}
+	if len(keyHats) == 0 {
+		return nil, nil
+	}
+
+	principalHats := map[string]string{}
+	for _, principal := range v.principals {
+		for _, key := range v.principalKeys(principal) {
+			if hat, has := keyHats[key.KeyID]; has {
+				principalHats[principal.ID()] = hat
+				break
+			}
+		}
+	}
+
+	return principalHats, nil
+}
+
 // Verify is used to check for a threshold of signatures using the verifier. The
 // threshold of signatures may be met using a combination of at most one Git
-// signature and signatures embedded in a DSSE envelope. Verify does not inspect
+// signature and signatures embedded in a DSSE envelope. Verify does not validate
 // the envelope's payload, but instead only verifies the signatures. The caller
-// must ensure the validity of the envelope's contents.
+// must ensure the validity of the envelope's contents. If the envelope contains
+// a reference authorization, the roles (or "hats") declared in it for each
+// signature are honoured when counting approvals towards the threshold.
 func (v *SignatureVerifier) Verify(ctx context.Context, gitObjectID gitinterface.Hash, env *sslibdsse.Envelope) (*set.Set[string], error) {
 	if v.threshold < 1 || len(v.principals) < 1 {
 		return nil, ErrInvalidVerifier
@@ -76,7 +412,7 @@ func (v *SignatureVerifier) Verify(ctx context.Context, gitObjectID gitinterface
 		slog.Debug(fmt.Sprintf("Verifying signature of Git object with ID '%s'...", gitObjectID.String()))
 		for _, principal := range v.principals {
 			// there are multiple keys we must try
-			keys := principal.Keys()
+			keys := v.principalKeys(principal)
 
 			for _, key := range keys {
 				err := v.repository.VerifySignature(ctx, gitObjectID, key)
@@ -107,9 +443,14 @@ func (v *SignatureVerifier) Verify(ctx context.Context, gitObjectID gitinterface
 		}
 	}
 
-	// If we don't have to verify exhaustively and threshold is 1 and the Git
-	// signature is verified, we can return
-	if !v.verifyExhaustively && v.threshold == 1 && gitObjectVerified {
+	principalHats, err := v.getPrincipalHats(env)
+	if err != nil {
+		return nil, err
+	}
+
+	// If we don't have to verify exhaustively and the Git signature is
+	// sufficient to meet the threshold, we can return
+	if !v.verifyExhaustively && gitObjectVerified && v.countTowardsThreshold(usedPrincipalIDs, principalHats) >= v.threshold {
 		return usedPrincipalIDs, nil
 	}
 
@@ -131,7 +472,7 @@ func (v *SignatureVerifier) Verify(ctx context.Context, gitObjectID gitinterface
 
 			principalVerifiers := []sslibdsse.Verifier{}
 
-			keys := principal.Keys()
+			keys := v.principalKeys(principal)
 			for _, key := range keys {
 				if usedKeyIDs.Has(key.KeyID) {
 					// this key has been encountered before, possibly because
@@ -152,8 +493,11 @@ func (v *SignatureVerifier) Verify(ctx context.Context, gitObjectID gitinterface
 						return nil, err
 					}
 				case gpg.KeyType:
-					slog.Debug(fmt.Sprintf("Found GPG key '%s', cannot use for DSSE signature verification yet...", key.KeyID))
-					continue
+					slog.Debug(fmt.Sprintf("Found GPG key '%s'...", key.KeyID))
+					dsseVerifier, err = gpg.NewVerifierFromKey(key)
+					if err != nil {
+						return nil, err
+					}
 				case sigstore.KeyType:
 					slog.Debug(fmt.Sprintf("Found Sigstore key '%s'...", key.KeyID))
 					opts := []sigstoreverifieropts.Option{}
@@ -225,7 +569,7 @@ func (v *SignatureVerifier) Verify(ctx context.Context, gitObjectID gitinterface
 		}
 	}
 
-	if v.verifyExhaustively || usedPrincipalIDs.Len() >= v.Threshold() {
+	if v.verifyExhaustively || v.countTowardsThreshold(usedPrincipalIDs, principalHats) >= v.Threshold() {
 		// TODO: double check that this is okay!
 		return usedPrincipalIDs, nil
 	}
@@ -5,15 +5,21 @@ package policy
 
 import (
 	"fmt"
+	"slices"
 	"testing"
+	"time"
 
+	"github.com/gittuf/gittuf/internal/attestations"
 	"github.com/gittuf/gittuf/internal/common"
+	"github.com/gittuf/gittuf/internal/common/set"
 	"github.com/gittuf/gittuf/internal/gitinterface"
 	"github.com/gittuf/gittuf/internal/signerverifier/dsse"
 	"github.com/gittuf/gittuf/internal/signerverifier/gpg"
 	sslibdsse "github.com/gittuf/gittuf/internal/third_party/go-securesystemslib/dsse"
 	"github.com/gittuf/gittuf/internal/tuf"
 	tufv01 "github.com/gittuf/gittuf/internal/tuf/v01"
+	tufv04 "github.com/gittuf/gittuf/internal/tuf/v04"
+	"github.com/secure-systems-lab/go-securesystemslib/signerverifier"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -169,3 +175,404 @@ func TestSignatureVerifier(t *testing.T) {
 		}
 	}
 }
+
+func TestSignatureVerifierWithTeams(t *testing.T) {
+	t.Parallel()
+	tmpDir := t.TempDir()
+	repo := gitinterface.CreateTestGitRepository(t, tmpDir, false)
+
+	gpgKeyR, err := gpg.LoadGPGKeyFromBytes(gpgPubKeyBytes)
+	if err != nil {
+		t.Fatal(err)
+	}
+	gpgKey := tufv04.NewKeyFromSSLibKey(gpgKeyR)
+
+	rootSigner := setupSSHKeysForSigning(t, rootKeyBytes, rootPubKeyBytes)
+	rootPubKey := tufv04.NewKeyFromSSLibKey(rootSigner.MetadataKey())
+
+	targetsSigner := setupSSHKeysForSigning(t, targets1KeyBytes, targets1PubKeyBytes)
+	targetsPubKey := tufv04.NewKeyFromSSLibKey(targetsSigner.MetadataKey())
+
+	devTeam, err := tufv04.NewTeam("dev", []tuf.Principal{gpgKey, rootPubKey}, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	securityTeam, err := tufv04.NewTeam("security", []tuf.Principal{targetsPubKey}, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	commitIDs := common.AddNTestCommitsToSpecifiedRef(t, repo, "refs/heads/main", 1, gpgKeyBytes)
+	commitID := commitIDs[0]
+
+	attestation, err := dsse.CreateEnvelope(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	attestation, err = dsse.SignEnvelope(testCtx, attestation, rootSigner)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	attestationWithTwoSigs, err := dsse.CreateEnvelope(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	attestationWithTwoSigs, err = dsse.SignEnvelope(testCtx, attestationWithTwoSigs, rootSigner)
+	if err != nil {
+		t.Fatal(err)
+	}
+	attestationWithTwoSigs, err = dsse.SignEnvelope(testCtx, attestationWithTwoSigs, targetsSigner)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := map[string]struct {
+		principals     []tuf.Principal
+		threshold      int
+		teamThresholds map[string]int
+		gitObjectID    gitinterface.Hash
+		attestation    *sslibdsse.Envelope
+
+		expectedError error
+	}{
+		"one team, team threshold 1, commit signature only": {
+			principals:  []tuf.Principal{devTeam},
+			threshold:   1,
+			gitObjectID: commitID,
+		},
+		"one team, team threshold 2, commit signature only": {
+			principals:     []tuf.Principal{devTeam},
+			threshold:      1,
+			teamThresholds: map[string]int{devTeam.TeamID: 2},
+			gitObjectID:    commitID,
+			expectedError:  ErrVerifierConditionsUnmet,
+		},
+		"one team, team threshold 2, commit signature and attestation": {
+			principals:     []tuf.Principal{devTeam},
+			threshold:      1,
+			teamThresholds: map[string]int{devTeam.TeamID: 2},
+			gitObjectID:    commitID,
+			attestation:    attestation,
+		},
+		"two members of one team do not count as two approvals": {
+			principals:    []tuf.Principal{devTeam, securityTeam},
+			threshold:     2,
+			gitObjectID:   commitID,
+			attestation:   attestation,
+			expectedError: ErrVerifierConditionsUnmet,
+		},
+		"two teams, both team thresholds met": {
+			principals:     []tuf.Principal{devTeam, securityTeam},
+			threshold:      2,
+			teamThresholds: map[string]int{devTeam.TeamID: 2},
+			gitObjectID:    commitID,
+			attestation:    attestationWithTwoSigs,
+		},
+		"two teams, dev team threshold unmet": {
+			principals:     []tuf.Principal{devTeam, securityTeam},
+			threshold:      2,
+			teamThresholds: map[string]int{devTeam.TeamID: 2},
+			attestation:    attestationWithTwoSigs,
+			expectedError:  ErrVerifierConditionsUnmet,
+		},
+		"team and key": {
+			principals:  []tuf.Principal{devTeam, targetsPubKey},
+			threshold:   2,
+			gitObjectID: commitID,
+			attestation: attestationWithTwoSigs,
+		},
+	}
+
+	for name, test := range tests {
+		verifier := &SignatureVerifier{
+			repository: repo,
+			name:       "test-verifier",
+			threshold:  test.threshold,
+		}
+		verifier.setPrincipals(test.principals, test.teamThresholds)
+
+		_, err := verifier.Verify(testCtx, test.gitObjectID, test.attestation)
+		if test.expectedError == nil {
+			assert.Nil(t, err, fmt.Sprintf("unexpected error in test '%s'", name))
+		} else {
+			assert.ErrorIs(t, err, test.expectedError, fmt.Sprintf("incorrect error received in test '%s'", name))
+		}
+	}
+
+	t.Run("threshold met with one more principal", func(t *testing.T) {
+		verifier := &SignatureVerifier{
+			repository: repo,
+			name:       "test-verifier",
+			threshold:  2,
+		}
+		verifier.setPrincipals([]tuf.Principal{devTeam, securityTeam}, map[string]int{devTeam.TeamID: 2})
+
+		// dev has one of two approvals, security has none: one more approval
+		// cannot meet both team thresholds
+		assert.False(t, verifier.thresholdMetWithOneMorePrincipal(set.NewSetFromItems(gpgKey.KeyID), nil))
+
+		// dev's threshold is met, security's approval is pending
+		assert.True(t, verifier.thresholdMetWithOneMorePrincipal(set.NewSetFromItems(gpgKey.KeyID, rootPubKey.KeyID), nil))
+
+		// security has approved, dev needs one more approval
+		assert.True(t, verifier.thresholdMetWithOneMorePrincipal(set.NewSetFromItems(gpgKey.KeyID, targetsPubKey.KeyID), nil))
+	})
+
+	t.Run("many approvers in overlapping teams", func(t *testing.T) {
+		members := []tuf.Principal{}
+		approverIDs := set.NewSet[string]()
+		for i := range 40 {
+			key := tufv04.NewKeyFromSSLibKey(&signerverifier.SSLibKey{KeyID: fmt.Sprintf("key-%d", i)})
+			members = append(members, key)
+			approverIDs.Add(key.KeyID)
+		}
+
+		teamA, err := tufv04.NewTeam("a", members, 20)
+		if err != nil {
+			t.Fatal(err)
+		}
+		teamB, err := tufv04.NewTeam("b", members, 20)
+		if err != nil {
+			t.Fatal(err)
+		}
+		teamC, err := tufv04.NewTeam("c", members[:10], 1)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		verifier := &SignatureVerifier{
+			repository: repo,
+			name:       "test-verifier",
+			threshold:  3,
+		}
+		verifier.setPrincipals([]tuf.Principal{teamA, teamB, teamC}, nil)
+
+		// Each approver is counted towards one team, so 40 approvers can meet
+		// the thresholds of two teams of 20 but not a third as well
+		assert.Equal(t, 2, verifier.countTowardsThreshold(approverIDs, nil))
+
+		// With one more approver in the first team, the thresholds of all
+		// three teams can be met
+		extraKey := tufv04.NewKeyFromSSLibKey(&signerverifier.SSLibKey{KeyID: "key-40"})
+		approverIDs.Add(extraKey.KeyID)
+		teamA, err = tufv04.NewTeam("a", append(slices.Clone(members), extraKey), 20)
+		if err != nil {
+			t.Fatal(err)
+		}
+		verifier.setPrincipals([]tuf.Principal{teamA, teamB, teamC}, nil)
+		assert.Equal(t, 3, verifier.countTowardsThreshold(approverIDs, nil))
+	})
+}
+
+func TestSignatureVerifierWithHats(t *testing.T) {
+	t.Parallel()
+	tmpDir := t.TempDir()
+	repo := gitinterface.CreateTestGitRepository(t, tmpDir, false)
+
+	gpgKeyR, err := gpg.LoadGPGKeyFromBytes(gpgPubKeyBytes)
+	if err != nil {
+		t.Fatal(err)
+	}
+	gpgKey := tufv04.NewKeyFromSSLibKey(gpgKeyR)
+
+	rootSigner := setupSSHKeysForSigning(t, rootKeyBytes, rootPubKeyBytes)
+	rootPubKey := tufv04.NewKeyFromSSLibKey(rootSigner.MetadataKey())
+
+	targetsSigner := setupSSHKeysForSigning(t, targets1KeyBytes, targets1PubKeyBytes)
+	targetsPubKey := tufv04.NewKeyFromSSLibKey(targetsSigner.MetadataKey())
+
+	// rootPubKey is a member of both teams
+	devTeam, err := tufv04.NewTeam("dev", []tuf.Principal{gpgKey, rootPubKey}, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	securityTeam, err := tufv04.NewTeam("security", []tuf.Principal{rootPubKey, targetsPubKey}, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	commitIDs := common.AddNTestCommitsToSpecifiedRef(t, repo, "refs/heads/main", 1, gpgKeyBytes)
+	commitID := commitIDs[0]
+
+	createAuthorization := func(t *testing.T, hats map[string]string, signers ...sslibdsse.SignerVerifier) *sslibdsse.Envelope {
+		t.Helper()
+
+		authorization, err := attestations.NewReferenceAuthorizationForCommit("refs/heads/main", gitinterface.ZeroHash.String(), gitinterface.ZeroHash.String())
+		if err != nil {
+			t.Fatal(err)
+		}
+		env, err := dsse.CreateEnvelope(authorization)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		for keyID, hat := range hats {
+			authorization, err = attestations.AddHatToReferenceAuthorization(env, keyID, hat)
+			if err != nil {
+				t.Fatal(err)
+			}
+			env, err = dsse.CreateEnvelope(authorization)
+			if err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		for _, signer := range signers {
+			env, err = dsse.SignEnvelope(testCtx, env, signer)
+			if err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		return env
+	}
+
+	tests := map[string]struct {
+		principals  []tuf.Principal
+		gitObjectID gitinterface.Hash
+		attestation *sslibdsse.Envelope
+
+		expectedError error
+	}{
+		"member of both teams cannot approve for both": {
+			principals:    []tuf.Principal{devTeam, securityTeam},
+			attestation:   createAuthorization(t, nil, rootSigner),
+			expectedError: ErrVerifierConditionsUnmet,
+		},
+		"member of both teams counted towards team without other approvals": {
+			principals:  []tuf.Principal{devTeam, securityTeam},
+			gitObjectID: commitID,
+			attestation: createAuthorization(t, nil, rootSigner),
+		},
+		"member of both teams approves as security": {
+			principals:  []tuf.Principal{devTeam, securityTeam},
+			gitObjectID: commitID,
+			attestation: createAuthorization(t, map[string]string{rootPubKey.KeyID: securityTeam.TeamID}, rootSigner),
+		},
+		"member of both teams approves as dev": {
+			principals:    []tuf.Principal{devTeam, securityTeam},
+			gitObjectID:   commitID,
+			attestation:   createAuthorization(t, map[string]string{rootPubKey.KeyID: devTeam.TeamID}, rootSigner),
+			expectedError: ErrVerifierConditionsUnmet,
+		},
+		"principal trusted directly approves in role not trusted by rule": {
+			principals:  []tuf.Principal{gpgKey, targetsPubKey},
+			gitObjectID: commitID,
+			attestation: createAuthorization(t, map[string]string{targetsPubKey.KeyID: securityTeam.TeamID}, targetsSigner),
+		},
+		"principal trusted directly and via team approves as team member": {
+			principals:  []tuf.Principal{gpgKey, rootPubKey, securityTeam},
+			gitObjectID: commitID,
+			attestation: createAuthorization(t, map[string]string{rootPubKey.KeyID: securityTeam.TeamID}, rootSigner),
+		},
+		"principal trusted directly and via team approves as member of other team": {
+			principals:  []tuf.Principal{gpgKey, rootPubKey, securityTeam},
+			gitObjectID: commitID,
+			attestation: createAuthorization(t, map[string]string{rootPubKey.KeyID: devTeam.TeamID}, rootSigner),
+		},
+		"principal trusted directly and via team approves as team member without other approvals": {
+			principals:    []tuf.Principal{rootPubKey, securityTeam},
+			attestation:   createAuthorization(t, map[string]string{rootPubKey.KeyID: securityTeam.TeamID}, rootSigner),
+			expectedError: ErrVerifierConditionsUnmet,
+		},
+		"principal trusted directly approves in rule's role": {
+			principals:  []tuf.Principal{gpgKey, targetsPubKey},
+			gitObjectID: commitID,
+			attestation: createAuthorization(t, map[string]string{targetsPubKey.KeyID: "test-verifier"}, targetsSigner),
+		},
+	}
+
+	for name, test := range tests {
+		verifier := &SignatureVerifier{
+			repository: repo,
+			name:       "test-verifier",
+			threshold:  2,
+		}
+		verifier.setPrincipals(test.principals, nil)
+
+		_, err := verifier.Verify(testCtx, test.gitObjectID, test.attestation)
+		if test.expectedError == nil {
+			assert.Nil(t, err, fmt.Sprintf("unexpected error in test '%s'", name))
+		} else {
+			assert.ErrorIs(t, err, test.expectedError, fmt.Sprintf("incorrect error received in test '%s'", name))
+		}
+	}
+}
+
+func TestSignatureVerifierWithKeyRotation(t *testing.T) {
+	t.Parallel()
+	tmpDir := t.TempDir()
+	repo := gitinterface.CreateTestGitRepository(t, tmpDir, false)
+
+	oldSigner := setupSSHKeysForSigning(t, rootKeyBytes, rootPubKeyBytes)
+	oldKey := tufv04.NewKeyFromSSLibKey(oldSigner.MetadataKey())
+
+	newSigner := setupSSHKeysForSigning(t, targets1KeyBytes, targets1PubKeyBytes)
+	newKey := tufv04.NewKeyFromSSLibKey(newSigner.MetadataKey())
+
+	person := &tufv04.Person{
+		PersonID:   "jane.doe@example.com",
+		PublicKeys: map[string]*tufv04.Key{newKey.KeyID: newKey},
+	}
+
+	gracePeriodEnd := time.Date(2030, time.January, 1, 0, 0, 0, 0, time.UTC)
+	keyRotations := groupKeyRotations(nil, map[string]tuf.Principal{person.PersonID: person}, []tuf.KeyRotation{
+		&tufv04.KeyRotation{
+			PrincipalID:    person.PersonID,
+			OldKey:         oldKey,
+			NewKeyID:       newKey.KeyID,
+			GracePeriodEnd: gracePeriodEnd,
+		},
+		// Rotations for principals not declared alongside them are ignored
+		&tufv04.KeyRotation{
+			PrincipalID:    "john.doe@example.com",
+			OldKey:         oldKey,
+			NewKeyID:       newKey.KeyID,
+			GracePeriodEnd: gracePeriodEnd,
+		},
+	})
+	assert.Len(t, keyRotations, 1)
+
+	authorization, err := attestations.NewReferenceAuthorizationForCommit("refs/heads/main", gitinterface.ZeroHash.String(), gitinterface.ZeroHash.String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	env, err := dsse.CreateEnvelope(authorization)
+	if err != nil {
+		t.Fatal(err)
+	}
+	env, err = dsse.SignEnvelope(testCtx, env, oldSigner)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	verifier := &SignatureVerifier{
+		repository:   repo,
+		name:         "test-verifier",
+		principals:   []tuf.Principal{person},
+		threshold:    1,
+		keyRotations: keyRotations,
+	}
+
+	t.Run("old key trusted during grace period", func(t *testing.T) {
+		_, err := verifier.at(gracePeriodEnd.Add(-time.Hour)).Verify(testCtx, nil, env)
+		assert.Nil(t, err)
+	})
+
+	t.Run("old key not trusted after grace period", func(t *testing.T) {
+		_, err := verifier.at(gracePeriodEnd.Add(time.Hour)).Verify(testCtx, nil, env)
+		assert.ErrorIs(t, err, ErrVerifierConditionsUnmet)
+	})
+
+	t.Run("old key not trusted without rotation record", func(t *testing.T) {
+		verifier := &SignatureVerifier{
+			repository: repo,
+			name:       "test-verifier",
+			principals: []tuf.Principal{person},
+			threshold:  1,
+		}
+		_, err := verifier.at(gracePeriodEnd.Add(-time.Hour)).Verify(testCtx, nil, env)
+		assert.ErrorIs(t, err, ErrVerifierConditionsUnmet)
+	})
+}
@@ -0,0 +1,321 @@
+// Copyright The gittuf Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package policy
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/gittuf/gittuf/internal/common/set"
+	sslibdsse "github.com/gittuf/gittuf/internal/third_party/go-securesystemslib/dsse"
+	"github.com/gittuf/gittuf/internal/tuf"
+)
+
+const (
+	MetadataAdded    = "added"
+	MetadataModified = "modified"
+	MetadataRemoved  = "removed"
+
+	// AppliedRootVerifierName identifies the root principals of the applied
+	// policy, who must also sign a new root of trust.
+	AppliedRootVerifierName = "applied-root"
+)
+
+// StagedPolicyStatus records the signatures collected for each metadata file
+// changed in the staged policy, and whether the staged policy can be applied.
+type StagedPolicyStatus struct {
+	Metadata []*MetadataStatus `json:"metadata"`
+
+	// CanApply is set when the staged policy can be applied. ApplyError
+	// records why it can't otherwise.
+	CanApply   bool   `json:"canApply"`
+	ApplyError string `json:"applyError,omitempty"`
+}
+
+// MetadataStatus records the signatures collected for a metadata file changed
+// in the staged policy. Removed rule files don't need signatures.
+type MetadataStatus struct {
+	Name    string          `json:"name"`
+	Change  string          `json:"change"`
+	Signers []*SignerStatus `json:"signers,omitempty"`
+}
+
+// SignerStatus records the principals trusted to sign a metadata file, the
+// number that must sign, and who has signed so far. Verifier identifies the
+// role the principals are trusted for: the root of trust, the primary rule
+// file, or the rule delegating to the rule file.
+type SignerStatus struct {
+	Verifier     string   `json:"verifier"`
+	Principals   []string `json:"principals"`
+	Threshold    int      `json:"threshold"`
+	Signed       []string `json:"signed"`
+	Pending      []string `json:"pending"`
+	ThresholdMet bool     `json:"thresholdMet"`
+}
+
+// GetStagedStatus returns the signatures collected for each metadata file that
+// differs between the applied and staged policy states. Metadata files are
+// compared by their contents, ignoring signatures. applied may be nil if no
+// policy has been applied yet, in which case every metadata file is treated as
+// added. The apply status is not set.
+func GetStagedStatus(ctx context.Context, applied, staged *State) (*StagedPolicyStatus, error) {
+	status := &StagedPolicyStatus{Metadata: []*MetadataStatus{}}
+
+	appliedEnvelopes := map[string]*sslibdsse.Envelope{}
+	if applied != nil {
+		appliedEnvelopes = getEnvelopes(applied)
+	}
+	stagedEnvelopes := getEnvelopes(staged)
+
+	ruleFileVerifiers, err := staged.getRuleFileVerifiers()
+	if err != nil {
+		return nil, err
+	}
+
+	for _, name := range metadataNames(appliedEnvelopes, stagedEnvelopes) {
+		appliedEnv, inApplied := appliedEnvelopes[name]
+		stagedEnv, inStaged := stagedEnvelopes[name]
+
+		metadataStatus := &MetadataStatus{Name: name}
+		switch {
+		case !inStaged:
+			metadataStatus.Change = MetadataRemoved
+			status.Metadata = append(status.Metadata, metadataStatus)
+			continue
+		case !inApplied:
+			metadataStatus.Change = MetadataAdded
+		case appliedEnv.Payload != stagedEnv.Payload:
+			metadataStatus.Change = MetadataModified
+		default:
+			continue
+		}
+
+		verifiers := map[string]*SignatureVerifier{}
+		switch name {
+		case RootRoleName:
+			verifier, err := staged.getRootVerifier()
+			if err != nil {
+				return nil, err
+			}
+			verifiers[RootRoleName] = verifier
+
+			// A new root of trust must also be signed by the root principals
+			// of the applied policy
+			if applied != nil {
+				appliedVerifier, err := applied.getRootVerifier()
+				if err != nil {
+					return nil, err
+				}
+				if appliedVerifier.threshold != verifier.threshold || !appliedVerifier.TrustedPrincipalIDs().Equal(verifier.TrustedPrincipalIDs()) {
+					verifiers[AppliedRootVerifierName] = appliedVerifier
+				}
+			}
+		case TargetsRoleName:
+			verifier, err := staged.getTargetsVerifier()
+			if err != nil {
+				// Until the root of trust declares the primary rule file's
+				// principals, no principal can sign it
+				if !errors.Is(err, tuf.ErrPrimaryRuleFileInformationNotFoundInRoot) {
+					return nil, err
+				}
+				break
+			}
+			verifiers[TargetsRoleName] = verifier
+		default:
+			for _, verifier := range ruleFileVerifiers[name] {
+				verifiers[verifier.Name()] = verifier
+			}
+		}
+
+		for _, verifierName := range slices.Sorted(maps.Keys(verifiers)) {
+			signerStatus, err := getSignerStatus(ctx, verifierName, verifiers[verifierName], stagedEnv)
+			if err != nil {
+				return nil, err
+			}
+			metadataStatus.Signers = append(metadataStatus.Signers, signerStatus)
+		}
+
+		status.Metadata = append(status.Metadata, metadataStatus)
+	}
+
+	return status, nil
+}
+
+// String returns a human readable description of the status.
+func (s *StagedPolicyStatus) String() string {
+	var sb strings.Builder
+
+	if len(s.Metadata) == 0 {
+		sb.WriteString("No staged policy changes\n")
+	}
+	for _, metadata := range s.Metadata {
+		fmt.Fprintf(&sb, "%s (%s)\n", metadata.Name, metadata.Change)
+
+		for _, signer := range metadata.Signers {
+			outcome := "threshold not met"
+			if signer.ThresholdMet {
+				outcome = "threshold met"
+			}
+			fmt.Fprintf(&sb, "    %s: %d of %d signatures, %s\n", describeVerifier(signer.Verifier), len(signer.Signed), signer.Threshold, outcome)
+
+			if len(signer.Signed) != 0 {
+				fmt.Fprintf(&sb, "        Signed: %s\n", strings.Join(signer.Signed, ", "))
+			}
+			if len(signer.Pending) != 0 {
+				fmt.Fprintf(&sb, "        Pending: %s\n", strings.Join(signer.Pending, ", "))
+			}
+		}
+	}
+
+	if s.CanApply {
+		sb.WriteString("Staged policy can be applied\n")
+	} else {
+		fmt.Fprintf(&sb, "Staged policy cannot be applied: %s\n", s.ApplyError)
+	}
+
+	return sb.String()
+}
+
+// describeVerifier returns a human readable name for the principals trusted by
+// the verifier.
+func describeVerifier(verifierName string) string {
+	switch verifierName {
+	case RootRoleName:
+		return "Root principals"
+	case AppliedRootVerifierName:
+		return "Applied root principals"
+	case TargetsRoleName:
+		return "Primary rule file principals"
+	default:
+		return fmt.Sprintf("Principals of rule '%s'", verifierName)
+	}
+}
+
+// getSignerStatus returns the principals trusted by the verifier who have and
+// haven't signed the envelope.
+func getSignerStatus(ctx context.Context, verifierName string, verifier *SignatureVerifier, env *sslibdsse.Envelope) (*SignerStatus, error) {
+	// All signatures are checked to find every principal who has signed, even
+	// if the threshold is already met
+	exhaustiveVerifier := *verifier
+	exhaustiveVerifier.verifyExhaustively = true
+	signedPrincipalIDs, err := exhaustiveVerifier.Verify(ctx, nil, env)
+	if err != nil {
+		return nil, err
+	}
+
+	thresholdMet := true
+	if _, err := verifier.Verify(ctx, nil, env); err != nil {
+		if !errors.Is(err, ErrVerifierConditionsUnmet) {
+			return nil, err
+		}
+		thresholdMet = false
+	}
+
+	trustedPrincipalIDs := verifier.TrustedPrincipalIDs()
+	return &SignerStatus{
+		Verifier:     verifierName,
+		Principals:   sortedPrincipalIDs(trustedPrincipalIDs),
+		Threshold:    verifier.Threshold(),
+		Signed:       sortedPrincipalIDs(trustedPrincipalIDs.Intersection(signedPrincipalIDs)),
+		Pending:      sortedPrincipalIDs(trustedPrincipalIDs.Minus(signedPrincipalIDs)),
+		ThresholdMet: thresholdMet,
+	}, nil
+}
+
+// getRuleFileVerifiers returns the verifiers for each delegated rule file in
+// the state, one for each rule delegating to the rule file. The verifiers are
+// determined as they are when the state is verified.
+func (s *State) getRuleFileVerifiers() (map[string][]*SignatureVerifier, error) {
+	verifiers := map[string][]*SignatureVerifier{}
+	if s.Metadata.TargetsEnvelope == nil {
+		return verifiers, nil
+	}
+
+	targetsMetadata, err := s.GetTargetsMetadata(TargetsRoleName, false)
+	if err != nil {
+		return nil, err
+	}
+
+	walkedRuleFiles := set.NewSetFromItems(TargetsRoleName)
+	delegationsQueue := targetsMetadata.GetRules()
+	delegationKeys := targetsMetadata.GetPrincipals()
+	delegationKeyRotations := groupKeyRotations(nil, delegationKeys, targetsMetadata.GetKeyRotations())
+	for len(delegationsQueue) > 1 {
+		delegation := delegationsQueue[0]
+		delegationsQueue = delegationsQueue[1:]
+
+		if delegation.ID() == tuf.AllowRuleName || !s.HasTargetsRole(delegation.ID()) {
+			continue
+		}
+
+		principals := []tuf.Principal{}
+		for _, principalID := range delegation.GetPrincipalIDs().Contents() {
+			principals = append(principals, delegationKeys[principalID])
+		}
+		verifiers[delegation.ID()] = append(verifiers[delegation.ID()], &SignatureVerifier{
+			repository:   s.repository,
+			name:         delegation.ID(),
+			principals:   principals,
+			threshold:    delegation.GetThreshold(),
+			keyRotations: delegationKeyRotations,
+		})
+
+		if walkedRuleFiles.Has(delegation.ID()) {
+			continue
+		}
+		walkedRuleFiles.Add(delegation.ID())
+
+		delegatedMetadata, err := s.GetTargetsMetadata(delegation.ID(), false)
+		if err != nil {
+			return nil, err
+		}
+
+		delegationsQueue = append(delegatedMetadata.GetRules(), delegationsQueue...)
+		for principalID, principal := range delegatedMetadata.GetPrincipals() {
+			delegationKeys[principalID] = principal
+		}
+		delegationKeyRotations = groupKeyRotations(delegationKeyRotations, delegatedMetadata.GetPrincipals(), delegatedMetadata.GetKeyRotations())
+	}
+
+	return verifiers, nil
+}
+
+// getEnvelopes returns the envelopes of the metadata files in the state, keyed
+// by the metadata file's name.
+func getEnvelopes(state *State) map[string]*sslibdsse.Envelope {
+	envelopes := map[string]*sslibdsse.Envelope{RootRoleName: state.Metadata.RootEnvelope}
+	if state.Metadata.TargetsEnvelope != nil {
+		envelopes[TargetsRoleName] = state.Metadata.TargetsEnvelope
+	}
+	for name, env := range state.Metadata.DelegationEnvelopes {
+		envelopes[name] = env
+	}
+	return envelopes
+}
+
+// metadataNames returns the names of the metadata files in either set of
+// envelopes, with the root of trust and the primary rule file first followed
+// by the other rule files in sorted order.
+func metadataNames(from, to map[string]*sslibdsse.Envelope) []string {
+	names := set.NewSet[string]()
+	for name := range from {
+		names.Add(name)
+	}
+	for name := range to {
+		names.Add(name)
+	}
+
+	ordered := []string{}
+	for _, name := range []string{RootRoleName, TargetsRoleName} {
+		if names.Has(name) {
+			ordered = append(ordered, name)
+			names.Remove(name)
+		}
+	}
+	return append(ordered, sortedPrincipalIDs(names)...)
+}
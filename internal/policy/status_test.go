@@ -0,0 +1,127 @@
+// Copyright The gittuf Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package policy
+
+import (
+	"testing"
+
+	sslibdsse "github.com/gittuf/gittuf/internal/third_party/go-securesystemslib/dsse"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetStagedStatus(t *testing.T) {
+	rootSigner := setupSSHKeysForSigning(t, rootKeyBytes, rootPubKeyBytes)
+	rootKeyID, err := rootSigner.KeyID()
+	require.Nil(t, err)
+	secondRootSigner := setupSSHKeysForSigning(t, targets1KeyBytes, targets1PubKeyBytes)
+	secondRootKeyID, err := secondRootSigner.KeyID()
+	require.Nil(t, err)
+
+	// withoutRuleFile returns a state with the same metadata as the specified
+	// state, except for the rule file
+	withoutRuleFile := func(state *State, ruleFileName string) *State {
+		delegationEnvelopes := map[string]*sslibdsse.Envelope{}
+		for name, env := range state.Metadata.DelegationEnvelopes {
+			if name != ruleFileName {
+				delegationEnvelopes[name] = env
+			}
+		}
+
+		return &State{
+			Metadata: &StateMetadata{
+				RootEnvelope:        state.Metadata.RootEnvelope,
+				TargetsEnvelope:     state.Metadata.TargetsEnvelope,
+				DelegationEnvelopes: delegationEnvelopes,
+			},
+			repository: state.repository,
+		}
+	}
+
+	t.Run("no changes", func(t *testing.T) {
+		state := createTestStateWithDelegatedPolicies(t)
+
+		status, err := GetStagedStatus(testCtx, state, state)
+		require.Nil(t, err)
+		assert.Empty(t, status.Metadata)
+	})
+
+	t.Run("no applied policy, root threshold not met", func(t *testing.T) {
+		state := createTestStateWithRootThreshold(t)
+		state.Metadata.RootEnvelope.Signatures = state.Metadata.RootEnvelope.Signatures[:1]
+
+		status, err := GetStagedStatus(testCtx, nil, state)
+		require.Nil(t, err)
+		require.Len(t, status.Metadata, 2)
+
+		rootStatus := status.Metadata[0]
+		assert.Equal(t, RootRoleName, rootStatus.Name)
+		assert.Equal(t, MetadataAdded, rootStatus.Change)
+		require.Len(t, rootStatus.Signers, 1)
+		assert.Equal(t, RootRoleName, rootStatus.Signers[0].Verifier)
+		assert.Equal(t, 2, rootStatus.Signers[0].Threshold)
+		assert.Len(t, rootStatus.Signers[0].Principals, 2)
+		assert.Len(t, rootStatus.Signers[0].Signed, 1)
+		assert.Len(t, rootStatus.Signers[0].Pending, 1)
+		assert.False(t, rootStatus.Signers[0].ThresholdMet)
+
+		targetsStatus := status.Metadata[1]
+		assert.Equal(t, TargetsRoleName, targetsStatus.Name)
+		assert.Equal(t, MetadataAdded, targetsStatus.Change)
+		require.Len(t, targetsStatus.Signers, 1)
+		assert.Equal(t, []string{rootKeyID}, targetsStatus.Signers[0].Signed)
+		assert.Empty(t, targetsStatus.Signers[0].Pending)
+		assert.True(t, targetsStatus.Signers[0].ThresholdMet)
+
+		assert.Contains(t, status.String(), "Root principals: 1 of 2 signatures, threshold not met")
+	})
+
+	t.Run("new root requires applied root principals", func(t *testing.T) {
+		applied := createTestStateWithOnlyRoot(t)
+		staged := createTestStateWithRootThreshold(t)
+
+		status, err := GetStagedStatus(testCtx, applied, staged)
+		require.Nil(t, err)
+
+		rootStatus := status.Metadata[0]
+		assert.Equal(t, MetadataModified, rootStatus.Change)
+		require.Len(t, rootStatus.Signers, 2)
+		assert.Equal(t, AppliedRootVerifierName, rootStatus.Signers[0].Verifier)
+		assert.Equal(t, []string{rootKeyID}, rootStatus.Signers[0].Principals)
+		assert.True(t, rootStatus.Signers[0].ThresholdMet)
+		assert.Equal(t, RootRoleName, rootStatus.Signers[1].Verifier)
+		assert.Contains(t, rootStatus.Signers[1].Signed, secondRootKeyID)
+		assert.True(t, rootStatus.Signers[1].ThresholdMet)
+	})
+
+	t.Run("rule file added", func(t *testing.T) {
+		staged := createTestStateWithDelegatedPolicies(t)
+		applied := withoutRuleFile(staged, "1")
+
+		status, err := GetStagedStatus(testCtx, applied, staged)
+		require.Nil(t, err)
+		require.Len(t, status.Metadata, 1)
+
+		ruleFileStatus := status.Metadata[0]
+		assert.Equal(t, "1", ruleFileStatus.Name)
+		assert.Equal(t, MetadataAdded, ruleFileStatus.Change)
+		assert.Equal(t, []*SignerStatus{{
+			Verifier:     "1",
+			Principals:   []string{rootKeyID},
+			Threshold:    1,
+			Signed:       []string{rootKeyID},
+			Pending:      []string{},
+			ThresholdMet: true,
+		}}, ruleFileStatus.Signers)
+	})
+
+	t.Run("rule file removed", func(t *testing.T) {
+		applied := createTestStateWithDelegatedPolicies(t)
+		staged := withoutRuleFile(applied, "1")
+
+		status, err := GetStagedStatus(testCtx, applied, staged)
+		require.Nil(t, err)
+		assert.Equal(t, []*MetadataStatus{{Name: "1", Change: MetadataRemoved}}, status.Metadata)
+	})
+}
@@ -6,13 +6,20 @@ package policy
 import (
 	"time"
 
+	"github.com/gittuf/gittuf/internal/dev"
 	"github.com/gittuf/gittuf/internal/tuf"
 	tufv02 "github.com/gittuf/gittuf/internal/tuf/v02"
+	tufv04 "github.com/gittuf/gittuf/internal/tuf/v04"
 )
 
-// InitializeTargetsMetadata creates a new instance of TargetsMetadata.
+// InitializeTargetsMetadata creates a new instance of TargetsMetadata. The
+// metadata uses the v04 schema in developer mode and the v02 schema otherwise.
 func InitializeTargetsMetadata() tuf.TargetsMetadata {
-	targetsMetadata := tufv02.NewTargetsMetadata()
+	var targetsMetadata tuf.TargetsMetadata = tufv02.NewTargetsMetadata()
+	if dev.InDevMode() {
+		// The v04 schema is still under development
+		targetsMetadata = tufv04.NewTargetsMetadata()
+	}
 
 	targetsMetadata.SetExpires(time.Now().AddDate(1, 0, 0).Format(time.RFC3339))
 	return targetsMetadata
@@ -10,14 +10,18 @@ import (
 	"fmt"
 	"log/slog"
 	"strings"
+	"sync"
+	"time"
 
 	"github.com/gittuf/gittuf/internal/attestations"
 	"github.com/gittuf/gittuf/internal/attestations/authorizations"
 	"github.com/gittuf/gittuf/internal/attestations/github"
 	githubv01 "github.com/gittuf/gittuf/internal/attestations/github/v01"
+	"github.com/gittuf/gittuf/internal/attestations/hooks"
 	"github.com/gittuf/gittuf/internal/cache"
 	"github.com/gittuf/gittuf/internal/common/set"
 	"github.com/gittuf/gittuf/internal/gitinterface"
+	policyopts "github.com/gittuf/gittuf/internal/policy/options/policy"
 	"github.com/gittuf/gittuf/internal/rsl"
 	sslibdsse "github.com/gittuf/gittuf/internal/third_party/go-securesystemslib/dsse"
 	"github.com/gittuf/gittuf/internal/tuf"
@@ -26,13 +30,19 @@ import (
 )
 
 var (
-	ErrVerificationFailed             = errors.New("gittuf policy verification failed")
-	ErrInvalidEntryNotSkipped         = errors.New("invalid entry found not marked as skipped")
-	ErrLastGoodEntryIsSkipped         = errors.New("entry expected to be unskipped is marked as skipped")
+	ErrVerificationFailed     = errors.New("gittuf policy verification failed")
+	ErrInvalidEntryNotSkipped = errors.New("invalid entry found not marked as skipped")
+
+	// Deprecated: ErrLastGoodEntryIsSkipped is no longer returned. The last
+	// good entry for a reference is found among the entries that are not
+	// skipped by authorized annotations.
+	ErrLastGoodEntryIsSkipped = errors.New("entry expected to be unskipped is marked as skipped")
+
 	ErrNoVerifiers                    = errors.New("no verifiers present for verification")
 	ErrInvalidVerifier                = errors.New("verifier has invalid parameters (is threshold 0?)")
 	ErrVerifierConditionsUnmet        = errors.New("verifier's key and threshold constraints not met")
 	ErrCannotVerifyMergeableForTagRef = errors.New("cannot verify mergeable into tag reference")
+	ErrUnauthorizedSkipAnnotation     = errors.New("skip annotations are not signed by a threshold of principals authorized to annotate the reference")
 )
 
 // PolicyVerifier implements various gittuf verification workflows.
@@ -45,23 +55,61 @@ type PolicyVerifier struct { //nolint:revive
 
 	persistentCacheEnabled bool
 	persistentCache        *cache.Persistent
+
+	// report records the checks performed by the last verification workflow
+	// invoked using the verifier.
+	report *VerificationReport
+
+	// workers is the number of RSL entries verified concurrently.
+	workers int
+
+	// useCheckpoints indicates that full verification may begin from the
+	// latest trusted RSL checkpoint.
+	useCheckpoints bool
 }
 
-func NewPolicyVerifier(repo *gitinterface.Repository) *PolicyVerifier {
+func NewPolicyVerifier(repo *gitinterface.Repository, opts ...policyopts.PolicyVerifierOption) *PolicyVerifier {
+	options := &policyopts.PolicyVerifierOptions{}
+	for _, fn := range opts {
+		fn(options)
+	}
+
 	searcher := newSearcher(repo)
 	verifier := &PolicyVerifier{
-		repo:     repo,
-		searcher: searcher,
+		repo:           repo,
+		searcher:       searcher,
+		workers:        options.Workers,
+		useCheckpoints: options.UseCheckpoints,
 	}
 
 	if searcher, isCacheSearcher := searcher.(*cacheSearcher); isCacheSearcher {
 		verifier.persistentCacheEnabled = true
 		verifier.persistentCache = searcher.persistentCache
+
+		if verifier.useCheckpoints {
+			// Checkpoints trusted earlier bound the RSL walks to determine
+			// when entries were recorded
+			for entryID, recordedTime := range verifier.persistentCache.GetCheckpointRecordedTimes() {
+				entryIDHash, err := gitinterface.NewHash(entryID)
+				if err != nil {
+					slog.Debug(fmt.Sprintf("Ignoring invalid checkpoint ID '%s' in cache...", entryID))
+					continue
+				}
+				rsl.SetRecordedTime(entryIDHash, recordedTime)
+			}
+		}
 	}
 
 	return verifier
 }
 
+// Report returns the report of the checks performed by the last verification
+// workflow invoked using the verifier. It is nil if no workflow has been
+// invoked or the workflow failed before any checks were performed.
+func (v *PolicyVerifier) Report() *VerificationReport {
+	return v.report
+}
+
 // VerifyRef verifies the signature on the latest RSL entry for the target ref
 // using the latest policy. The expected Git ID for the ref in the latest RSL
 // entry is returned if the policy verification is successful.
@@ -77,8 +125,10 @@ func (v *PolicyVerifier) VerifyRef(ctx context.Context, target string) (gitinter
 }
 
 // VerifyRefFull verifies the entire RSL for the target ref from the first
-// entry. The expected Git ID for the ref in the latest RSL entry is returned if
-// the policy verification is successful.
+// entry. If the verifier is configured to use checkpoints, verification begins
+// from the latest trusted checkpoint that records the ref instead. The expected
+// Git ID for the ref in the latest RSL entry is returned if the policy
+// verification is successful.
 func (v *PolicyVerifier) VerifyRefFull(ctx context.Context, target string) (gitinterface.Hash, error) {
 	// Trace RSL back to the start
 	slog.Debug(fmt.Sprintf("Identifying first RSL entry for '%s'...", target))
@@ -102,6 +152,19 @@ func (v *PolicyVerifier) VerifyRefFull(ctx context.Context, target string) (giti
 		slog.Debug("Cache doesn't have last verified entry for ref...")
 		fallthrough
 	case false:
+		if v.useCheckpoints {
+			slog.Debug("Checking for trusted checkpoint...")
+			checkpoint, err := v.findLatestTrustedCheckpoint(ctx)
+			if err != nil && !errors.Is(err, ErrCheckpointNotFound) {
+				return gitinterface.ZeroHash, err
+			}
+			if checkpoint != nil && checkpoint.records(target) {
+				slog.Debug(fmt.Sprintf("Verifying '%s' from checkpoint '%s'...", target, checkpoint.entry.GetID().String()))
+				return v.verifyRefFullFromCheckpoint(ctx, checkpoint, target)
+			}
+			slog.Debug("No trusted checkpoint records the reference...")
+		}
+
 		firstEntry, _, err = rsl.GetFirstReferenceUpdaterEntryForRef(v.repo, target)
 		if err != nil {
 			return gitinterface.ZeroHash, err
@@ -222,6 +285,19 @@ func (v *PolicyVerifier) VerifyMergeableForCommit(ctx context.Context, targetRef
 }
 
 func (v *PolicyVerifier) verifyMergeable(ctx context.Context, targetRef string, fromID, featureID gitinterface.Hash) (bool, error) {
+	v.report = NewVerificationReport(targetRef)
+	v.report.FeatureID = featureID.String()
+
+	rslEntrySignatureNeededForThreshold, err := v.checkMergeable(ctx, targetRef, fromID, featureID)
+	v.report.RSLEntrySignatureNeeded = err == nil && rslEntrySignatureNeededForThreshold
+	v.report.SetOutcome(err)
+
+	return rslEntrySignatureNeededForThreshold, err
+}
+
+// checkMergeable implements the workflow for verifyMergeable, recording checks
+// in the verifier's report.
+func (v *PolicyVerifier) checkMergeable(ctx context.Context, targetRef string, fromID, featureID gitinterface.Hash) (bool, error) {
 	// We're specifically focused on commit merges here, this doesn't apply to
 	// tags
 	mergeTreeID, err := v.repo.GetMergeTree(fromID, featureID)
@@ -245,6 +321,22 @@ func (v *PolicyVerifier) verifyMergeable(ctx context.Context, targetRef string,
 	}
 	currentPolicy = state
 
+	// Merging the change creates the target ref or fast-forwards it
+	refAction := tuf.RefActionFastForward
+	if fromID.IsZero() {
+		refAction = tuf.RefActionCreate
+	}
+
+	// The merge's RSL entry will be recorded now, so the policy used to
+	// verify it must not have expired
+	slog.Debug("Checking if latest policy has expired...")
+	if err := currentPolicy.checkExpiryForPathAndAction(fmt.Sprintf("%s:%s", gitReferenceRuleScheme, targetRef), refAction, time.Now()); err != nil {
+		v.report.AddCheck(&VerificationCheck{Type: CheckTypeExpiry, Ref: targetRef, Passed: false, Message: err.Error()})
+		return false, fmt.Errorf("%w: %w", ErrVerificationFailed, err)
+	}
+	v.report.AddCheck(&VerificationCheck{Type: CheckTypeExpiry, Ref: targetRef, Passed: true})
+	warnIfExpiringSoon(currentPolicy, time.Now())
+
 	// Load latest attestations
 	slog.Debug("Loading latest attestations...")
 	initialAttestationsEntry, err := v.searcher.FindLatestAttestationsEntry()
@@ -265,7 +357,9 @@ func (v *PolicyVerifier) verifyMergeable(ctx context.Context, targetRef string,
 		return false, err
 	}
 
-	_, rslEntrySignatureNeededForThreshold, err := verifyGitObjectAndAttestations(ctx, currentPolicy, fmt.Sprintf("%s:%s", gitReferenceRuleScheme, targetRef), gitinterface.ZeroHash, authorizationAttestation, withApproverPrincipalIDs(approverIDs), withVerifyMergeable())
+	recorder := &checkRecorder{report: v.report, base: VerificationCheck{Ref: targetRef}}
+
+	_, rslEntrySignatureNeededForThreshold, err := verifyGitObjectAndAttestations(ctx, currentPolicy, fmt.Sprintf("%s:%s", gitReferenceRuleScheme, targetRef), gitinterface.ZeroHash, authorizationAttestation, withApproverPrincipalIDs(approverIDs), withVerifyMergeable(), withProposedMerge(fromID, featureID), withRefAction(refAction), withCheckRecorder(recorder))
 	if err != nil {
 		return false, fmt.Errorf("not enough approvals to meet Git namespace policies, %w", ErrVerificationFailed)
 	}
@@ -288,6 +382,11 @@ func (v *PolicyVerifier) verifyMergeable(ctx context.Context, targetRef string,
 
 		verifiedUsing := "" // this will be set after one successful verification of the commit to avoid repeated signature verification
 		for _, path := range paths {
+			if err := currentPolicy.checkExpiryForPathAndAction(fmt.Sprintf("%s:%s", fileRuleScheme, path), "", time.Now()); err != nil {
+				recorder.forPath(commitID.String(), path).record(&VerificationCheck{Type: CheckTypeExpiry, Passed: false, Message: err.Error()})
+				return false, fmt.Errorf("%w: %w", ErrVerificationFailed, err)
+			}
+
 			// If we've already verified and identified commit signature, we can
 			// just check if that verifier is trusted for the new path. If not
 			// found, we don't make any assumptions about it being a failure in
@@ -295,7 +394,7 @@ func (v *PolicyVerifier) verifyMergeable(ctx context.Context, targetRef string,
 			// usual. Also, we don't use verifyMergeable=true here. File
 			// verification rules are not met using the signature on the RSL
 			// entry, so we don't count threshold-1 here.
-			verifiedUsing, _, err = verifyGitObjectAndAttestations(ctx, currentPolicy, fmt.Sprintf("%s:%s", fileRuleScheme, path), commitID, authorizationAttestation, withApproverPrincipalIDs(approverIDs), withTrustedVerifier(verifiedUsing))
+			verifiedUsing, _, err = verifyGitObjectAndAttestations(ctx, currentPolicy, fmt.Sprintf("%s:%s", fileRuleScheme, path), commitID, authorizationAttestation, withApproverPrincipalIDs(approverIDs), withTrustedVerifier(verifiedUsing), withCheckRecorder(recorder.forPath(commitID.String(), path)))
 			if err != nil {
 				return false, fmt.Errorf("verifying file namespace policies failed, %w", ErrVerificationFailed)
 			}
@@ -306,8 +405,18 @@ func (v *PolicyVerifier) verifyMergeable(ctx context.Context, targetRef string,
 }
 
 // VerifyRelativeForRef verifies the RSL between specified start and end entries
-// using the provided policy entry for the first entry.
+// using the provided policy entry for the first entry. The checks performed are
+// recorded in the verifier's report.
 func (v *PolicyVerifier) VerifyRelativeForRef(ctx context.Context, firstEntry, lastEntry rsl.ReferenceUpdaterEntry, target string) error {
+	v.report = NewVerificationReport(target)
+
+	err := v.verifyRelativeForRef(ctx, firstEntry, lastEntry, target)
+	v.report.SetOutcome(err)
+
+	return err
+}
+
+func (v *PolicyVerifier) verifyRelativeForRef(ctx context.Context, firstEntry, lastEntry rsl.ReferenceUpdaterEntry, target string) error {
 	/*
 		require firstEntry != nil
 		require lastEntry != nil
@@ -363,6 +472,31 @@ func (v *PolicyVerifier) VerifyRelativeForRef(ctx context.Context, firstEntry, l
 	}
 	// require len(entries) != 0
 
+	loader, err := v.newStateLoader()
+	if err != nil {
+		return err
+	}
+
+	return v.verifyEntriesForRef(ctx, firstEntry, entries, annotations, currentPolicy, currentAttestations, loader, v.report)
+}
+
+// verifyEntriesForRef verifies the entries for a reference in the order they
+// appear in the RSL, looking for a fix when an invalid entry is encountered.
+// The entries must include all entries for the gittuf namespace in the same
+// range so that the policy and attestations in use are updated as they change.
+// currentPolicy and currentAttestations must be the states applicable at
+// firstEntry.
+func (v *PolicyVerifier) verifyEntriesForRef(ctx context.Context, firstEntry rsl.ReferenceUpdaterEntry, entries []rsl.ReferenceUpdaterEntry, annotations map[string][]*rsl.AnnotationEntry, currentPolicy *State, currentAttestations *attestations.Attestations, loader *stateLoader, report *VerificationReport) error {
+	// The initial policy may not have been loaded using the loader, so the
+	// latest policy's key revocations are enforced using it here
+	if currentPolicy != nil {
+		currentPolicy.addKeyRevocations(loader.keyRevocations)
+	}
+
+	// If configured, entries are verified concurrently ahead of the walk
+	// below, which consumes the results in RSL order
+	precomputedResults := v.verifyEntriesConcurrently(ctx, firstEntry, entries, currentPolicy, currentAttestations, loader)
+
 	// Verify each entry, looking for a fix when an invalid entry is encountered
 	var invalidEntry rsl.ReferenceUpdaterEntry
 	var verificationErr error
@@ -392,7 +526,7 @@ func (v *PolicyVerifier) VerifyRelativeForRef(ctx context.Context, firstEntry, l
 						continue
 					}
 
-					newPolicy, err := loadStateForEntry(v.repo, entry)
+					newPolicy, err := loader.loadPolicy(entry)
 					if err != nil {
 						return err
 					}
@@ -404,9 +538,11 @@ func (v *PolicyVerifier) VerifyRelativeForRef(ctx context.Context, firstEntry, l
 						// RSL entry and we only have staging
 						// refs
 						slog.Debug("Verifying new policy using current policy...")
-						if err := currentPolicy.VerifyNewState(ctx, newPolicy); err != nil {
+						if err := loader.verifyNewState(ctx, currentPolicy, entry, newPolicy); err != nil {
+							report.AddCheck(&VerificationCheck{Type: CheckTypePolicy, EntryID: entry.GetID().String(), Ref: PolicyRef, Passed: false, Message: err.Error()})
 							return err
 						}
+						report.AddCheck(&VerificationCheck{Type: CheckTypePolicy, EntryID: entry.GetID().String(), Ref: PolicyRef, Passed: true})
 						slog.Debug("Updating current policy...")
 					} else {
 						slog.Debug("Setting current policy...")
@@ -423,7 +559,7 @@ func (v *PolicyVerifier) VerifyRelativeForRef(ctx context.Context, firstEntry, l
 
 				slog.Debug("Checking if entry is for attestations reference...")
 				if entry.GetRefName() == attestations.Ref {
-					newAttestationsState, err := attestations.LoadAttestationsForEntry(v.repo, entry)
+					newAttestationsState, err := loader.loadAttestations(entry)
 					if err != nil {
 						return err
 					}
@@ -441,11 +577,15 @@ func (v *PolicyVerifier) VerifyRelativeForRef(ctx context.Context, firstEntry, l
 				if currentPolicy == nil {
 					return ErrPolicyNotFound
 				}
-				if err := verifyEntry(ctx, v.repo, currentPolicy, currentAttestations, entry); err != nil {
+				if err := v.verifyEntryUsingResults(ctx, currentPolicy, currentAttestations, entry, precomputedResults, report); err != nil {
 					slog.Debug(fmt.Sprintf("Violation found: %s", err.Error()))
 					slog.Debug("Checking if entry has been revoked...")
 					// If the invalid entry is never marked as skipped, we return err
-					if !entry.SkippedBy(annotations[entry.GetID().String()]) {
+					skipped, skipErr := skippedByAuthorizedAnnotations(ctx, currentPolicy, entry, annotations[entry.GetID().String()], report)
+					if skipErr != nil {
+						return skipErr
+					}
+					if !skipped {
 						return err
 					}
 
@@ -486,15 +626,10 @@ func (v *PolicyVerifier) VerifyRelativeForRef(ctx context.Context, firstEntry, l
 
 		// 1. What's the last good state?
 		slog.Debug("Identifying last valid state...")
-		lastGoodEntry, lastGoodEntryAnnotations, err := rsl.GetLatestReferenceUpdaterEntry(v.repo, rsl.ForReference(invalidEntry.GetRefName()), rsl.BeforeEntryID(invalidEntry.GetID()), rsl.IsUnskipped(), rsl.IsReferenceEntry())
+		lastGoodEntry, err := getPriorUnskippedEntry(ctx, v.repo, currentPolicy, invalidEntry.GetRefName(), invalidEntry.GetID(), rsl.IsReferenceEntry())
 		if err != nil {
 			return err
 		}
-		slog.Debug("Verifying identified last valid entry has not been revoked...")
-		if lastGoodEntry.(*rsl.ReferenceEntry).SkippedBy(lastGoodEntryAnnotations) {
-			// this type assertion is fine because we use the rsl.IsReferenceEntry opt
-			return ErrLastGoodEntryIsSkipped
-		}
 		// require lastGoodEntry != nil
 
 		// TODO: what if the very first entry for a ref is a violation?
@@ -548,7 +683,11 @@ func (v *PolicyVerifier) VerifyRelativeForRef(ctx context.Context, firstEntry, l
 					// If it has been skipped, it's not actually a fix and we need
 					// to keep looking
 					slog.Debug("Verifying potential fix entry has not been revoked...")
-					if !newEntry.SkippedBy(annotations[newEntry.ID.String()]) {
+					skipped, err := skippedByAuthorizedAnnotations(ctx, currentPolicy, newEntry, annotations[newEntry.ID.String()], report)
+					if err != nil {
+						return err
+					}
+					if !skipped {
 						slog.Debug("Fix entry found, proceeding with regular verification workflow...")
 						fixed = true
 						fixEntry = newEntry
@@ -562,7 +701,11 @@ func (v *PolicyVerifier) VerifyRelativeForRef(ctx context.Context, firstEntry, l
 				// newEntry is not tree-same / commit-same, so it is automatically
 				// invalid, check that it's been marked as revoked
 				slog.Debug("Checking non-fix entry has been revoked as well...")
-				if !newEntry.SkippedBy(annotations[newEntry.ID.String()]) {
+				skipped, err := skippedByAuthorizedAnnotations(ctx, currentPolicy, newEntry, annotations[newEntry.ID.String()], report)
+				if err != nil {
+					return err
+				}
+				if !skipped {
 					invalidIntermediateEntries = append(invalidIntermediateEntries, newEntry)
 				}
 			}
@@ -570,15 +713,23 @@ func (v *PolicyVerifier) VerifyRelativeForRef(ctx context.Context, firstEntry, l
 
 		if !fixed {
 			// If we haven't found a fix, return the original error
+			report.AddCheck(&VerificationCheck{Type: CheckTypeRecovery, EntryID: invalidEntry.GetID().String(), Ref: invalidEntry.GetRefName(), Passed: false, Message: "no fix found for skipped invalid entry"})
 			return verificationErr
 		}
 
 		if len(invalidIntermediateEntries) != 0 {
 			// We may have found a fix but if an invalid intermediate entry
 			// wasn't skipped, return error
+			for _, invalidIntermediateEntry := range invalidIntermediateEntries {
+				report.AddCheck(&VerificationCheck{Type: CheckTypeRecovery, EntryID: invalidIntermediateEntry.GetID().String(), Ref: invalidIntermediateEntry.GetRefName(), Passed: false, Message: ErrInvalidEntryNotSkipped.Error()})
+			}
 			return ErrInvalidEntryNotSkipped
 		}
 
+		// The invalid entry has been skipped and fixed
+		report.markEntrySkipped(invalidEntry.GetID().String())
+		report.AddCheck(&VerificationCheck{Type: CheckTypeRecovery, EntryID: invalidEntry.GetID().String(), Ref: invalidEntry.GetRefName(), Passed: true, Message: fmt.Sprintf("skipped invalid entry fixed by entry '%s'", fixEntry.GetID().String())})
+
 		// Reset these trackers to continue verification with rest of the queue
 		// We may encounter other issues
 		invalidEntry = nil
@@ -591,19 +742,208 @@ func (v *PolicyVerifier) VerifyRelativeForRef(ctx context.Context, firstEntry, l
 		}
 	}
 
+	if currentPolicy != nil {
+		warnIfExpiringSoon(currentPolicy, time.Now())
+	}
+
 	return nil
 }
 
+// entryVerificationResult records the outcome of verifying an RSL entry ahead of
+// the sequential verification walk, along with the states used to verify it.
+type entryVerificationResult struct {
+	policy            *State
+	attestationsState *attestations.Attestations
+	report            *VerificationReport
+	err               error
+}
+
+// verifyEntriesConcurrently verifies the reference entries in entries using a
+// pool of the verifier's configured number of workers. The policy and
+// attestations states applicable at each entry are determined by walking the
+// entries in order first, stopping at the first entry whose states cannot be
+// determined; that entry and subsequent entries are left for the sequential
+// walk. The workers stop at the first entry that fails verification, leaving
+// the entries not yet verified for the sequential walk. The results are keyed
+// by entry ID and are nil if the verifier is not configured to verify entries
+// concurrently.
+func (v *PolicyVerifier) verifyEntriesConcurrently(ctx context.Context, firstEntry rsl.ReferenceUpdaterEntry, entries []rsl.ReferenceUpdaterEntry, currentPolicy *State, currentAttestations *attestations.Attestations, loader *stateLoader) map[string]*entryVerificationResult {
+	if v.workers < 2 {
+		return nil
+	}
+
+	type job struct {
+		entry    *rsl.ReferenceEntry
+		result   *entryVerificationResult
+		verified bool
+	}
+
+	jobs := []*job{}
+
+identifyStates:
+	for _, entry := range entries {
+		entry, isReferenceEntry := entry.(*rsl.ReferenceEntry)
+		if !isReferenceEntry {
+			continue
+		}
+
+		switch entry.GetRefName() {
+		case PolicyStagingRef:
+			continue
+
+		case PolicyRef:
+			if entry.GetID().Equal(firstEntry.GetID()) {
+				continue
+			}
+
+			newPolicy, err := loader.loadPolicy(entry)
+			if err != nil {
+				break identifyStates
+			}
+			if currentPolicy != nil {
+				if err := loader.verifyNewState(ctx, currentPolicy, entry, newPolicy); err != nil {
+					break identifyStates
+				}
+			}
+			currentPolicy = newPolicy
+
+		case attestations.Ref:
+			newAttestationsState, err := loader.loadAttestations(entry)
+			if err != nil {
+				break identifyStates
+			}
+			currentAttestations = newAttestationsState
+
+		default:
+			if currentPolicy == nil {
+				break identifyStates
+			}
+
+			jobs = append(jobs, &job{
+				entry: entry,
+				result: &entryVerificationResult{
+					policy:            currentPolicy,
+					attestationsState: currentAttestations,
+					report:            NewVerificationReport(entry.GetRefName()),
+				},
+			})
+		}
+	}
+
+	slog.Debug(fmt.Sprintf("Verifying %d entries using %d workers...", len(jobs), v.workers))
+
+	// The first failure cancels the remaining verification, as the
+	// sequential walk has to process entries from there on anyway
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	queue := make(chan *job)
+	var wg sync.WaitGroup
+	for range min(v.workers, len(jobs)) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			for job := range queue {
+				if ctx.Err() != nil {
+					continue
+				}
+
+				job.result.err = verifyEntry(ctx, v.repo, job.result.policy, job.result.attestationsState, job.entry, job.result.report)
+				if job.result.err == nil {
+					job.verified = true
+					continue
+				}
+
+				// A failure after the context is cancelled may have been
+				// caused by the cancellation, so it isn't recorded
+				if ctx.Err() == nil {
+					job.verified = true
+					cancel()
+				}
+			}
+		}()
+	}
+
+queueJobs:
+	for _, job := range jobs {
+		select {
+		case queue <- job:
+		case <-ctx.Done():
+			break queueJobs
+		}
+	}
+	close(queue)
+	wg.Wait()
+
+	results := make(map[string]*entryVerificationResult, len(jobs))
+	for _, job := range jobs {
+		if job.verified {
+			results[job.entry.GetID().String()] = job.result
+		}
+	}
+
+	return results
+}
+
+// verifyEntryUsingResults returns the outcome of verifying the entry, using
+// the precomputed result for the entry if it was verified using the same
+// states. The checks recorded for the entry are added to the report.
+func (v *PolicyVerifier) verifyEntryUsingResults(ctx context.Context, currentPolicy *State, currentAttestations *attestations.Attestations, entry *rsl.ReferenceEntry, results map[string]*entryVerificationResult, report *VerificationReport) error {
+	result, has := results[entry.GetID().String()]
+	if !has || result.policy != currentPolicy || result.attestationsState != currentAttestations {
+		return verifyEntry(ctx, v.repo, currentPolicy, currentAttestations, entry, report)
+	}
+
+	for _, check := range result.report.Checks {
+		report.AddCheck(check)
+	}
+	return result.err
+}
+
 // VerifyNewState ensures that when a new policy is encountered, its root role
-// is signed by keys trusted in the current policy.
+// is signed by keys trusted in the current policy, and that none of its
+// metadata files are rolled back to an older version than in the current
+// policy.
 func (s *State) VerifyNewState(ctx context.Context, newPolicy *State) error {
+	return s.verifyNewState(ctx, newPolicy, s.versions)
+}
+
+// verifyNewState implements VerifyNewState, checking the new policy's metadata
+// versions against the specified versions instead of only those in the
+// current policy.
+func (s *State) verifyNewState(ctx context.Context, newPolicy *State, metadataVersions map[string]int) error {
 	rootVerifier, err := s.getRootVerifier()
 	if err != nil {
 		return err
 	}
 
-	_, err = rootVerifier.Verify(ctx, gitinterface.ZeroHash, newPolicy.Metadata.RootEnvelope)
-	return err
+	// Key rotation grace periods are checked against when the new policy was
+	// recorded in the RSL
+	verificationTime, err := newPolicy.getLoadedEntryTime()
+	if err != nil {
+		return err
+	}
+
+	// Keys revoked before the new policy was recorded can't sign it
+	var revokedKeyIDs *set.Set[string]
+	if newPolicy.loadedEntry != nil {
+		revokedKeyIDs = s.getRevokedKeyIDs(newPolicy.loadedEntry.GetNumber(), verificationTime)
+	}
+
+	if _, err := rootVerifier.at(verificationTime).withoutKeys(revokedKeyIDs).Verify(ctx, gitinterface.ZeroHash, newPolicy.Metadata.RootEnvelope); err != nil {
+		return err
+	}
+
+	return checkMetadataVersions(metadataVersions, newPolicy)
+}
+
+// warnIfExpiringSoon logs a warning for each metadata file in the policy that
+// expires within MetadataExpiryWarningPeriod of the specified time.
+func warnIfExpiringSoon(policy *State, at time.Time) {
+	for _, name := range policy.GetMetadataExpiringWithin(at, MetadataExpiryWarningPeriod) {
+		slog.Warn(fmt.Sprintf("Policy metadata '%s' expires at %s, its expiry must be extended", name, policy.expiries[name].Format(time.RFC3339)))
+	}
 }
 
 // verifyEntry is a helper to verify an entry's signature using the specified
@@ -611,15 +951,48 @@ func (s *State) VerifyNewState(ctx context.Context, newPolicy *State) error {
 // commit signatures, verifyEntry checks when the commit was first introduced
 // via the RSL across all refs. Then, it uses the policy applicable at the
 // commit's first entry into the repository. If the commit is brand new to the
-// repository, the specified policy is used.
-func verifyEntry(ctx context.Context, repo *gitinterface.Repository, policy *State, attestationsState *attestations.Attestations, entry *rsl.ReferenceEntry) error {
+// repository, the specified policy is used. The checks performed are recorded
+// in report, if set.
+func verifyEntry(ctx context.Context, repo *gitinterface.Repository, policy *State, attestationsState *attestations.Attestations, entry *rsl.ReferenceEntry, report *VerificationReport) error {
 	if entry.RefName == PolicyRef || entry.RefName == attestations.Ref {
 		return nil
 	}
 
+	// Rules may apply only to some actions on the reference
+	refAction, err := getRefAction(ctx, repo, policy, entry)
+	if err != nil {
+		return err
+	}
+	slog.Debug(fmt.Sprintf("Entry's action on '%s' is '%s'", entry.RefName, refAction))
+
+	// Metadata expiry is enforced against the time the entry was recorded in
+	// the RSL. The entry's commit time can be backdated, so the entry is never
+	// considered recorded before the entries that precede it. Only the rule
+	// files used to verify the entry must not have expired.
+	slog.Debug("Checking if policy had expired when entry was recorded...")
+	entryTime, err := rsl.GetRecordedTime(repo, entry)
+	if err != nil {
+		return err
+	}
+	recorder := &checkRecorder{report: report, base: VerificationCheck{EntryID: entry.ID.String(), Ref: entry.RefName}}
+	if err := policy.checkExpiryForPathAndAction(fmt.Sprintf("%s:%s", gitReferenceRuleScheme, entry.RefName), refAction, entryTime); err != nil {
+		recorder.record(&VerificationCheck{Type: CheckTypeExpiry, Passed: false, Message: err.Error()})
+		return fmt.Errorf("%w: %w, entry '%s' recorded at %s", ErrVerificationFailed, err, entry.ID.String(), entryTime.Format(time.RFC3339))
+	}
+	recorder.record(&VerificationCheck{Type: CheckTypeExpiry, Passed: true})
+
+	// Keys revoked in the latest policy are not trusted for entries recorded
+	// after the revocation, even if an earlier policy state trusts them
+	slog.Debug("Checking if entry was signed using a revoked key...")
+	if err := checkEntryNotSignedByRevokedKey(ctx, repo, policy, entry, entryTime); err != nil {
+		recorder.record(&VerificationCheck{Type: CheckTypeKeyRevocation, Passed: false, Message: err.Error()})
+		return fmt.Errorf("%w: %w", ErrVerificationFailed, err)
+	}
+	revokedKeyIDs := policy.getRevokedKeyIDs(entry.GetNumber(), entryTime)
+
 	if strings.HasPrefix(entry.RefName, gitinterface.TagRefPrefix) {
 		slog.Debug("Entry is for a Git tag, using tag verification workflow...")
-		return verifyTagEntry(ctx, repo, policy, attestationsState, entry)
+		return verifyTagEntry(ctx, repo, policy, attestationsState, entry, refAction, recorder)
 	}
 
 	// Load the applicable reference authorization and approvals from trusted
@@ -631,7 +1004,7 @@ func verifyEntry(ctx context.Context, repo *gitinterface.Repository, policy *Sta
 	}
 
 	// Verify Git namespace policies using the RSL entry and attestations
-	if _, _, err := verifyGitObjectAndAttestations(ctx, policy, fmt.Sprintf("%s:%s", gitReferenceRuleScheme, entry.RefName), entry.ID, authorizationAttestation, withApproverPrincipalIDs(approverKeyIDs)); err != nil {
+	if _, _, err := verifyGitObjectAndAttestations(ctx, policy, fmt.Sprintf("%s:%s", gitReferenceRuleScheme, entry.RefName), entry.ID, authorizationAttestation, withApproverPrincipalIDs(approverKeyIDs), withRefAction(refAction), withCheckRecorder(recorder), withVerificationTime(entryTime), withRevokedKeyIDs(revokedKeyIDs), withAttestations(attestationsState)); err != nil {
 		return fmt.Errorf("verifying Git namespace policies failed, %w", ErrVerificationFailed)
 	}
 
@@ -657,12 +1030,17 @@ func verifyEntry(ctx context.Context, repo *gitinterface.Repository, policy *Sta
 
 		verifiedUsing := "" // this will be set after one successful verification of the commit to avoid repeated signature verification
 		for _, path := range paths {
+			if err := policy.checkExpiryForPathAndAction(fmt.Sprintf("%s:%s", fileRuleScheme, path), "", entryTime); err != nil {
+				recorder.forPath(commitID.String(), path).record(&VerificationCheck{Type: CheckTypeExpiry, Passed: false, Message: err.Error()})
+				return fmt.Errorf("%w: %w, entry '%s' recorded at %s", ErrVerificationFailed, err, entry.ID.String(), entryTime.Format(time.RFC3339))
+			}
+
 			// If we've already verified and identified commit signature, we
 			// can just check if that verifier is trusted for the new path.
 			// If not found, we don't make any assumptions about it being a
 			// failure in case of name mismatches. So, the signature check
 			// proceeds as usual.
-			verifiedUsing, _, err = verifyGitObjectAndAttestations(ctx, policy, fmt.Sprintf("%s:%s", fileRuleScheme, path), commitID, authorizationAttestation, withApproverPrincipalIDs(approverKeyIDs), withTrustedVerifier(verifiedUsing))
+			verifiedUsing, _, err = verifyGitObjectAndAttestations(ctx, policy, fmt.Sprintf("%s:%s", fileRuleScheme, path), commitID, authorizationAttestation, withApproverPrincipalIDs(approverKeyIDs), withTrustedVerifier(verifiedUsing), withCheckRecorder(recorder.forPath(commitID.String(), path)), withVerificationTime(entryTime), withRevokedKeyIDs(revokedKeyIDs))
 			if err != nil {
 				return fmt.Errorf("verifying file namespace policies failed, %w", ErrVerificationFailed)
 			}
@@ -672,7 +1050,7 @@ func verifyEntry(ctx context.Context, repo *gitinterface.Repository, policy *Sta
 	return nil
 }
 
-func verifyTagEntry(ctx context.Context, repo *gitinterface.Repository, policy *State, attestationsState *attestations.Attestations, entry *rsl.ReferenceEntry) error {
+func verifyTagEntry(ctx context.Context, repo *gitinterface.Repository, policy *State, attestationsState *attestations.Attestations, entry *rsl.ReferenceEntry, refAction tuf.RefAction, recorder *checkRecorder) error {
 	entryTagRef, err := repo.GetReference(entry.RefName)
 	if err != nil {
 		return err
@@ -684,6 +1062,7 @@ func verifyTagEntry(ctx context.Context, repo *gitinterface.Repository, policy *
 	}
 
 	if !entry.TargetID.Equal(entryTagRef) && !entry.TargetID.Equal(tagTargetID) {
+		recorder.record(&VerificationCheck{Type: CheckTypeRefTip, Passed: false, Message: "tag reference set to unexpected target"})
 		return fmt.Errorf("verifying RSL entry failed, tag reference set to unexpected target")
 	}
 
@@ -692,7 +1071,12 @@ func verifyTagEntry(ctx context.Context, repo *gitinterface.Repository, policy *
 		return err
 	}
 
-	if _, _, err := verifyGitObjectAndAttestations(ctx, policy, fmt.Sprintf("%s:%s", gitReferenceRuleScheme, entry.RefName), entry.GetID(), authorizationAttestation, withApproverPrincipalIDs(approverKeyIDs), withTagObjectID(entry.TargetID)); err != nil {
+	entryTime, err := rsl.GetRecordedTime(repo, entry)
+	if err != nil {
+		return err
+	}
+
+	if _, _, err := verifyGitObjectAndAttestations(ctx, policy, fmt.Sprintf("%s:%s", gitReferenceRuleScheme, entry.RefName), entry.GetID(), authorizationAttestation, withApproverPrincipalIDs(approverKeyIDs), withTagObjectID(entry.TargetID), withRefAction(refAction), withCheckRecorder(recorder), withVerificationTime(entryTime), withRevokedKeyIDs(policy.getRevokedKeyIDs(entry.GetNumber(), entryTime)), withAttestations(attestationsState)); err != nil {
 		return fmt.Errorf("verifying tag entry failed, %w: %w", ErrVerificationFailed, err)
 	}
 
@@ -788,9 +1172,9 @@ func getApproverAttestationAndKeyIDsForIndex(ctx context.Context, repo *gitinter
 				approvalVerifier := &SignatureVerifier{
 					repository: policy.repository,
 					name:       appName,
-					principals: appPrincipals,
 					threshold:  appEntry.GetThreshold(),
 				}
+				approvalVerifier.setPrincipals(appPrincipals, nil)
 				_, err := approvalVerifier.Verify(ctx, nil, githubApprovalAttestation)
 				if err != nil {
 					return nil, nil, fmt.Errorf("%w: failed to verify GitHub app approval attestation, signed by untrusted key", ErrVerificationFailed)
@@ -823,6 +1207,134 @@ func getApproverAttestationAndKeyIDsForIndex(ctx context.Context, repo *gitinter
 	return authorizationAttestation, approverIdentities, nil
 }
 
+// getRefAction identifies the action the entry performs on its ref using the
+// latest RSL entry for the same ref before it that is not skipped by
+// annotations authorized by the policy. An entry for the zero hash deletes the
+// ref, and an entry without a prior entry for the ref, or whose prior entry
+// deleted the ref, creates it.
+func getRefAction(ctx context.Context, repo *gitinterface.Repository, policy *State, entry *rsl.ReferenceEntry) (tuf.RefAction, error) {
+	if entry.TargetID.IsZero() {
+		return tuf.RefActionDelete, nil
+	}
+
+	priorRefEntry, err := getPriorUnskippedEntry(ctx, repo, policy, entry.RefName, entry.ID)
+	if err != nil {
+		if errors.Is(err, rsl.ErrRSLEntryNotFound) {
+			return tuf.RefActionCreate, nil
+		}
+
+		return "", err
+	}
+
+	if priorRefEntry.GetTargetID().IsZero() {
+		return tuf.RefActionCreate, nil
+	}
+
+	if strings.HasPrefix(entry.RefName, gitinterface.TagRefPrefix) {
+		// Tags don't move forward, an existing tag is always overwritten
+		return tuf.RefActionNonFastForward, nil
+	}
+
+	knows, err := repo.KnowsCommit(entry.TargetID, priorRefEntry.GetTargetID())
+	if err != nil {
+		return "", err
+	}
+	if knows {
+		return tuf.RefActionFastForward, nil
+	}
+
+	return tuf.RefActionNonFastForward, nil
+}
+
+// getPriorUnskippedEntry returns the latest RSL entry for refName before the
+// entry identified by entryID that is not skipped by annotations authorized by
+// the policy. Unlike rsl.IsUnskipped, skip annotations that are not authorized
+// are ignored, so they cannot change which entry is treated as the ref's prior
+// state.
+func getPriorUnskippedEntry(ctx context.Context, repo *gitinterface.Repository, policy *State, refName string, entryID gitinterface.Hash, opts ...rsl.GetLatestReferenceUpdaterEntryOption) (rsl.ReferenceUpdaterEntry, error) {
+	for {
+		searchOpts := append([]rsl.GetLatestReferenceUpdaterEntryOption{rsl.ForReference(refName), rsl.BeforeEntryID(entryID)}, opts...)
+		priorEntry, annotations, err := rsl.GetLatestReferenceUpdaterEntry(repo, searchOpts...)
+		if err != nil {
+			return nil, err
+		}
+
+		priorReferenceEntry, isReferenceEntry := priorEntry.(*rsl.ReferenceEntry)
+		if !isReferenceEntry {
+			return priorEntry, nil
+		}
+
+		skipped, err := skippedByAuthorizedAnnotations(ctx, policy, priorReferenceEntry, annotations, nil)
+		if err != nil {
+			return nil, err
+		}
+		if !skipped {
+			return priorEntry, nil
+		}
+
+		entryID = priorEntry.GetID()
+	}
+}
+
+// skippedByAuthorizedAnnotations indicates if the entry is marked as skipped by
+// annotations that are authorized by the policy. If the policy has rules that
+// apply to the annotate action for the entry's reference, the entry is only
+// considered skipped if its skip annotations are together signed by a
+// threshold of the principals trusted by one of these rules. Otherwise, any
+// skip annotation is honoured. Skip annotations that are ignored are recorded
+// in the report.
+func skippedByAuthorizedAnnotations(ctx context.Context, policy *State, entry *rsl.ReferenceEntry, annotations []*rsl.AnnotationEntry, report *VerificationReport) (bool, error) {
+	if !entry.SkippedBy(annotations) {
+		return false, nil
+	}
+
+	entryTime, err := rsl.GetRecordedTime(policy.repository, entry)
+	if err != nil {
+		return false, err
+	}
+
+	// The exhaustive verifier included for global rules trusts every
+	// principal, so only the verifiers of the rules are used
+	verifiers, err := policy.findVerifiersForPathIfProtected(fmt.Sprintf("%s:%s", gitReferenceRuleScheme, entry.RefName), tuf.RefActionAnnotate, entryTime)
+	if err != nil {
+		return false, err
+	}
+	if len(verifiers) == 0 {
+		// Annotations for this reference are not controlled by the policy
+		return true, nil
+	}
+
+	skipAnnotations := []*rsl.AnnotationEntry{}
+	for _, annotation := range annotations {
+		if annotation.RefersTo(entry.ID) && annotation.Skip {
+			skipAnnotations = append(skipAnnotations, annotation)
+		}
+	}
+
+	slog.Debug(fmt.Sprintf("Verifying skip annotations for entry '%s' are authorized...", entry.ID.String()))
+	for _, verifier := range verifiers {
+		approvers := set.NewSet[string]()
+		for _, annotation := range skipAnnotations {
+			principalIDs, err := verifier.Verify(ctx, annotation.GetID(), nil)
+			if err != nil && !errors.Is(err, ErrVerifierConditionsUnmet) {
+				return false, err
+			}
+			if principalIDs != nil {
+				approvers.Extend(principalIDs)
+			}
+		}
+
+		if verifier.countTowardsThreshold(approvers, nil) >= verifier.Threshold() {
+			report.AddCheck(&VerificationCheck{Type: CheckTypeAnnotation, EntryID: entry.ID.String(), Ref: entry.RefName, Rule: verifier.Name(), Threshold: verifier.Threshold(), SignedBy: sortedPrincipalIDs(approvers), Passed: true})
+			return true, nil
+		}
+	}
+
+	slog.Warn(fmt.Sprintf("Ignoring unauthorized skip annotations for entry '%s'", entry.ID.String()))
+	report.AddCheck(&VerificationCheck{Type: CheckTypeAnnotation, EntryID: entry.ID.String(), Ref: entry.RefName, Passed: false, Message: ErrUnauthorizedSkipAnnotation.Error()})
+	return false, nil
+}
+
 // getCommits identifies the commits introduced to the entry's ref since the
 // last RSL entry for the same ref. These commits are then verified for file
 // policies.
@@ -852,6 +1364,13 @@ type verifyGitObjectAndAttestationsOptions struct {
 	verifyMergeable      bool
 	trustedVerifier      string
 	tagObjectID          gitinterface.Hash
+	recorder             *checkRecorder
+	verificationTime     time.Time
+	revokedKeyIDs        *set.Set[string]
+	refAction            tuf.RefAction
+	mergeFromID          gitinterface.Hash
+	mergeFeatureID       gitinterface.Hash
+	attestationsState    *attestations.Attestations
 }
 
 type verifyGitObjectAndAttestationsOption func(o *verifyGitObjectAndAttestationsOptions)
@@ -865,6 +1384,15 @@ func withApproverPrincipalIDs(approverPrincipalIDs *set.Set[string]) verifyGitOb
 	}
 }
 
+// withRefAction is used to specify the action performed on the Git reference
+// under verification, so that only the rules that apply to the action are
+// used.
+func withRefAction(action tuf.RefAction) verifyGitObjectAndAttestationsOption {
+	return func(o *verifyGitObjectAndAttestationsOptions) {
+		o.refAction = action
+	}
+}
+
 // withVerifyMergeable indicates that the verification must check if a change
 // can be merged.
 func withVerifyMergeable() verifyGitObjectAndAttestationsOption {
@@ -873,6 +1401,26 @@ func withVerifyMergeable() verifyGitObjectAndAttestationsOption {
 	}
 }
 
+// withProposedMerge sets the current tip of the reference and the feature
+// commit proposed to be merged into it, used to check global rules that
+// constrain how the reference is updated when verifying if a change is
+// mergeable.
+func withProposedMerge(fromID, featureID gitinterface.Hash) verifyGitObjectAndAttestationsOption {
+	return func(o *verifyGitObjectAndAttestationsOptions) {
+		o.mergeFromID = fromID
+		o.mergeFeatureID = featureID
+	}
+}
+
+// withAttestations sets the attestations applicable to the RSL entry under
+// verification, used to check global rules that require attestations other
+// than the reference authorization.
+func withAttestations(attestationsState *attestations.Attestations) verifyGitObjectAndAttestationsOption {
+	return func(o *verifyGitObjectAndAttestationsOptions) {
+		o.attestationsState = attestationsState
+	}
+}
+
 // withTrustedVerifier is used to specify the name of a verifier that has
 // already been used to verify in the past. If the newly discovered set of
 // verifiers includes the trusted verifier, then we can return early.
@@ -882,6 +1430,13 @@ func withTrustedVerifier(name string) verifyGitObjectAndAttestationsOption {
 	}
 }
 
+// withCheckRecorder is used to record the checks performed during verification.
+func withCheckRecorder(recorder *checkRecorder) verifyGitObjectAndAttestationsOption {
+	return func(o *verifyGitObjectAndAttestationsOptions) {
+		o.recorder = recorder
+	}
+}
+
 // withTagObjectID is used to set the Git ID of a tag object. When this is set,
 // the tag object's signature is also verified in addition to the RSL entry for
 // the tag.
@@ -891,13 +1446,30 @@ func withTagObjectID(objID gitinterface.Hash) verifyGitObjectAndAttestationsOpti
 	}
 }
 
+// withVerificationTime sets the time the grace periods of key rotations are
+// checked against, typically when the RSL entry being verified was recorded.
+func withVerificationTime(verificationTime time.Time) verifyGitObjectAndAttestationsOption {
+	return func(o *verifyGitObjectAndAttestationsOptions) {
+		o.verificationTime = verificationTime
+	}
+}
+
+// withRevokedKeyIDs sets the keys that must not be trusted for any principal,
+// typically as they were revoked before the RSL entry being verified was
+// recorded.
+func withRevokedKeyIDs(revokedKeyIDs *set.Set[string]) verifyGitObjectAndAttestationsOption {
+	return func(o *verifyGitObjectAndAttestationsOptions) {
+		o.revokedKeyIDs = revokedKeyIDs
+	}
+}
+
 func verifyGitObjectAndAttestations(ctx context.Context, policy *State, target string, gitID gitinterface.Hash, authorizationAttestation *sslibdsse.Envelope, opts ...verifyGitObjectAndAttestationsOption) (string, bool, error) {
-	options := &verifyGitObjectAndAttestationsOptions{tagObjectID: gitinterface.ZeroHash}
+	options := &verifyGitObjectAndAttestationsOptions{tagObjectID: gitinterface.ZeroHash, mergeFromID: gitinterface.ZeroHash, mergeFeatureID: gitinterface.ZeroHash}
 	for _, fn := range opts {
 		fn(options)
 	}
 
-	verifiers, err := policy.FindVerifiersForPath(target)
+	verifiers, err := policy.findVerifiersForPathAndActionAt(target, options.refAction, options.verificationTime)
 	if err != nil {
 		return "", false, err
 	}
@@ -907,9 +1479,20 @@ func verifyGitObjectAndAttestations(ctx context.Context, policy *State, target s
 		return "", false, nil
 	}
 
+	if !options.verificationTime.IsZero() || options.revokedKeyIDs != nil {
+		// The verifiers are cached in the policy, so the verification time and
+		// revoked keys are set on copies
+		entryVerifiers := make([]*SignatureVerifier, 0, len(verifiers))
+		for _, verifier := range verifiers {
+			entryVerifiers = append(entryVerifiers, verifier.at(options.verificationTime).withoutKeys(options.revokedKeyIDs))
+		}
+		verifiers = entryVerifiers
+	}
+
 	if options.trustedVerifier != "" {
 		for _, verifier := range verifiers {
 			if verifier.Name() == options.trustedVerifier {
+				options.recorder.record(&VerificationCheck{Type: CheckTypeRule, Rule: verifier.Name(), Threshold: verifier.Threshold(), Passed: true, Message: "commit already verified using rule"})
 				return options.trustedVerifier, false, nil
 			}
 		}
@@ -921,7 +1504,7 @@ func verifyGitObjectAndAttestations(ctx context.Context, policy *State, target s
 			appNames = append(appNames, appName)
 		}
 	}
-	verifiedUsing, acceptedPrincipalIDs, rslSignatureNeededForThreshold, err := verifyGitObjectAndAttestationsUsingVerifiers(ctx, verifiers, gitID, authorizationAttestation, appNames, options.approverPrincipalIDs, options.verifyMergeable)
+	verifiedUsing, acceptedPrincipalIDs, rslSignatureNeededForThreshold, err := verifyGitObjectAndAttestationsUsingVerifiers(ctx, verifiers, gitID, authorizationAttestation, appNames, options.approverPrincipalIDs, options.verifyMergeable, options.recorder)
 	if err != nil {
 		return "", false, err
 	}
@@ -950,8 +1533,10 @@ func verifyGitObjectAndAttestations(ctx context.Context, policy *State, target s
 		}
 
 		if !tagObjVerified {
+			options.recorder.record(&VerificationCheck{Type: CheckTypeTagObject, CommitID: options.tagObjectID.String(), Passed: false, Message: "tag object's signature not verified by any rule"})
 			return "", false, fmt.Errorf("verifying tag object's signature failed")
 		}
+		options.recorder.record(&VerificationCheck{Type: CheckTypeTagObject, CommitID: options.tagObjectID.String(), Passed: true})
 	}
 
 	verifiedPrincipalIDs := 0
@@ -988,10 +1573,88 @@ func verifyGitObjectAndAttestations(ctx context.Context, policy *State, target s
 					// Check if the verifiedPrincipalIDs meets the required global
 					// threshold
 					slog.Debug(fmt.Sprintf("Global rule '%s' not met, required threshold '%d', only have '%d'", rule.GetName(), rule.GetThreshold(), verifiedPrincipalIDs))
+					options.recorder.record(&VerificationCheck{Type: CheckTypeGlobalRule, Rule: rule.GetName(), Threshold: requiredThreshold, SignedBy: sortedPrincipalIDs(acceptedPrincipalIDs), Passed: false})
+					return "", false, ErrVerifierConditionsUnmet
+				}
+
+				slog.Debug(fmt.Sprintf("Successfully verified global rule '%s'", rule.GetName()))
+				options.recorder.record(&VerificationCheck{Type: CheckTypeGlobalRule, Rule: rule.GetName(), Threshold: requiredThreshold, SignedBy: sortedPrincipalIDs(acceptedPrincipalIDs), Passed: true})
+
+			case tuf.GlobalRuleRequireSignedCommits:
+				if !rule.Matches(target) {
+					break
+				}
+
+				// The global rule applies to the namespace under verification
+				slog.Debug(fmt.Sprintf("Verifying require signed commits global rule '%s'...", rule.GetName()))
+
+				if options.verifyMergeable {
+					// The commits of a proposed change are not recorded in
+					// an RSL entry yet
+					slog.Debug("Cannot verify require signed commits global rule when verifying if a change is mergeable")
+					break
+				}
+
+				unsignedCommitID, err := findUnsignedCommit(ctx, policy, gitID, options)
+				if err != nil {
+					return "", false, err
+				}
+				if !unsignedCommitID.IsZero() {
+					slog.Debug(fmt.Sprintf("Commit '%s' is not signed by any principal in the policy", unsignedCommitID.String()))
+					options.recorder.record(&VerificationCheck{Type: CheckTypeGlobalRule, Rule: rule.GetName(), CommitID: unsignedCommitID.String(), Passed: false, Message: "commit is not signed by any principal in the policy"})
 					return "", false, ErrVerifierConditionsUnmet
 				}
 
 				slog.Debug(fmt.Sprintf("Successfully verified global rule '%s'", rule.GetName()))
+				options.recorder.record(&VerificationCheck{Type: CheckTypeGlobalRule, Rule: rule.GetName(), Passed: true})
+
+			case tuf.GlobalRuleMergeStrategy:
+				if !rule.Matches(target) {
+					break
+				}
+
+				// The global rule applies to the namespace under verification
+				slog.Debug(fmt.Sprintf("Verifying merge strategy global rule '%s'...", rule.GetName()))
+
+				violation, err := checkMergeStrategy(ctx, policy, rule.GetMergeStrategy(), gitID, options)
+				if err != nil {
+					return "", false, err
+				}
+				if violation != "" {
+					slog.Debug(fmt.Sprintf("Global rule '%s' not met: %s", rule.GetName(), violation))
+					options.recorder.record(&VerificationCheck{Type: CheckTypeGlobalRule, Rule: rule.GetName(), Passed: false, Message: violation})
+					return "", false, ErrVerifierConditionsUnmet
+				}
+
+				slog.Debug(fmt.Sprintf("Successfully verified global rule '%s'", rule.GetName()))
+				options.recorder.record(&VerificationCheck{Type: CheckTypeGlobalRule, Rule: rule.GetName(), Passed: true})
+
+			case tuf.GlobalRuleRequireHookExecution:
+				if !rule.Matches(target) {
+					break
+				}
+
+				// The global rule applies to the namespace under verification
+				slog.Debug(fmt.Sprintf("Verifying require hook execution global rule '%s'...", rule.GetName()))
+
+				if options.verifyMergeable {
+					// Hooks are run when the merged change is pushed
+					slog.Debug("Cannot verify require hook execution global rule when verifying if a change is mergeable")
+					break
+				}
+
+				violation, err := checkHookExecutions(ctx, policy, rule.GetHookNames(), gitID, options)
+				if err != nil {
+					return "", false, err
+				}
+				if violation != "" {
+					slog.Debug(fmt.Sprintf("Global rule '%s' not met: %s", rule.GetName(), violation))
+					options.recorder.record(&VerificationCheck{Type: CheckTypeGlobalRule, Rule: rule.GetName(), Passed: false, Message: violation})
+					return "", false, ErrVerifierConditionsUnmet
+				}
+
+				slog.Debug(fmt.Sprintf("Successfully verified global rule '%s'", rule.GetName()))
+				options.recorder.record(&VerificationCheck{Type: CheckTypeGlobalRule, Rule: rule.GetName(), Passed: true})
 
 			case tuf.GlobalRuleBlockForcePushes:
 				// TODO: we use policy.repository, not ideal...
@@ -1028,7 +1691,7 @@ func verifyGitObjectAndAttestations(ctx context.Context, policy *State, target s
 					return "", false, rsl.ErrInvalidRSLEntry
 				}
 
-				previousEntryRef, _, err := rsl.GetLatestReferenceUpdaterEntry(policy.repository, rsl.BeforeEntryID(currentEntry.GetID()), rsl.ForReference(currentEntryRef.RefName), rsl.IsUnskipped())
+				previousEntryRef, err := getPriorUnskippedEntry(ctx, policy.repository, policy, currentEntryRef.RefName, currentEntry.GetID())
 				if err != nil {
 					if errors.Is(err, rsl.ErrRSLEntryNotFound) {
 						slog.Debug(fmt.Sprintf("Entry '%s' is the first one for reference '%s', cannot check if it's a force push", currentEntryRef.GetID().String(), currentEntryRef.RefName))
@@ -1044,10 +1707,12 @@ func verifyGitObjectAndAttestations(ctx context.Context, policy *State, target s
 				}
 				if !knows {
 					slog.Debug(fmt.Sprintf("Current entry's commit '%s' is not a descendant of prior entry's commit '%s'", currentEntryRef.TargetID.String(), previousEntryRef.GetTargetID().String()))
+					options.recorder.record(&VerificationCheck{Type: CheckTypeGlobalRule, Rule: rule.GetName(), Passed: false, Message: fmt.Sprintf("'%s' is not a descendant of '%s'", currentEntryRef.TargetID.String(), previousEntryRef.GetTargetID().String())})
 					return "", false, ErrVerifierConditionsUnmet
 				}
 
 				slog.Debug(fmt.Sprintf("Successfully verified global rule '%s' as '%s' is a descendant of '%s'", rule.GetName(), currentEntryRef.TargetID.String(), previousEntryRef.GetTargetID().String()))
+				options.recorder.record(&VerificationCheck{Type: CheckTypeGlobalRule, Rule: rule.GetName(), Passed: true})
 
 			default:
 				slog.Debug("Unknown global rule type, aborting verification...")
@@ -1059,7 +1724,255 @@ func verifyGitObjectAndAttestations(ctx context.Context, policy *State, target s
 	return verifiedUsing, rslSignatureNeededForThreshold, nil
 }
 
-func verifyGitObjectAndAttestationsUsingVerifiers(ctx context.Context, verifiers []*SignatureVerifier, gitID gitinterface.Hash, authorizationAttestation *sslibdsse.Envelope, appNames []string, approverIDs *set.Set[string], verifyMergeable bool) (string, *set.Set[string], bool, error) {
+// findUnsignedCommit returns the first commit introduced by the RSL entry that
+// is not signed by any principal in the policy. The zero hash is returned if
+// every commit is signed.
+func findUnsignedCommit(ctx context.Context, policy *State, entryID gitinterface.Hash, options *verifyGitObjectAndAttestationsOptions) (gitinterface.Hash, error) {
+	// The rule type only accepts git:<> as patterns, so entryID must be for
+	// an RSL reference entry
+	entry, err := rsl.GetEntry(policy.repository, entryID)
+	if err != nil {
+		return nil, err
+	}
+	referenceEntry, isReferenceEntry := entry.(*rsl.ReferenceEntry)
+	if !isReferenceEntry {
+		return nil, rsl.ErrInvalidRSLEntry
+	}
+
+	if referenceEntry.TargetID.IsZero() || strings.HasPrefix(referenceEntry.RefName, gitinterface.TagRefPrefix) {
+		// Deletions don't introduce commits, and tags are verified using
+		// the signatures of the tag objects
+		return gitinterface.ZeroHash, nil
+	}
+
+	verifier := &SignatureVerifier{
+		repository:   policy.repository,
+		name:         tuf.ExhaustiveVerifierName,
+		threshold:    1,
+		keyRotations: policy.keyRotations,
+	}
+	principals := make([]tuf.Principal, 0, len(policy.allPrincipals))
+	for _, principal := range policy.allPrincipals {
+		principals = append(principals, principal)
+	}
+	verifier.setPrincipals(principals, nil)
+	verifier = verifier.at(options.verificationTime).withoutKeys(options.revokedKeyIDs)
+
+	commitIDs, err := getCommits(policy.repository, referenceEntry)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, commitID := range commitIDs {
+		if _, err := verifier.Verify(ctx, commitID, nil); err != nil {
+			if errors.Is(err, ErrVerifierConditionsUnmet) {
+				return commitID, nil
+			}
+
+			return nil, err
+		}
+	}
+
+	return gitinterface.ZeroHash, nil
+}
+
+// checkMergeStrategy checks if an update to a reference follows the merge
+// strategy, returning a description of the violation if it does not. When
+// verifying if a change is mergeable, the update is the proposed merge of the
+// feature commit. Otherwise, entryID identifies the RSL reference entry that
+// records the update.
+func checkMergeStrategy(ctx context.Context, policy *State, strategy string, entryID gitinterface.Hash, options *verifyGitObjectAndAttestationsOptions) (string, error) {
+	repo := policy.repository
+	if options.verifyMergeable {
+		if strategy != tuf.MergeStrategyLinearHistory || options.mergeFeatureID.IsZero() {
+			// The merge commit strategy is met by the merge commit that
+			// will be created for the change
+			return "", nil
+		}
+
+		if !options.mergeFromID.IsZero() {
+			knows, err := repo.KnowsCommit(options.mergeFeatureID, options.mergeFromID)
+			if err != nil {
+				return "", err
+			}
+			if !knows {
+				return fmt.Sprintf("merging '%s' requires a merge commit as it is not a descendant of '%s'", options.mergeFeatureID.String(), options.mergeFromID.String()), nil
+			}
+		}
+
+		commitIDs, err := repo.GetCommitsBetweenRange(options.mergeFeatureID, options.mergeFromID)
+		if err != nil {
+			return "", err
+		}
+		return findMergeCommit(repo, commitIDs)
+	}
+
+	// The rule type only accepts git:<> as patterns, so entryID must be for
+	// an RSL reference entry
+	entry, err := rsl.GetEntry(repo, entryID)
+	if err != nil {
+		return "", err
+	}
+	referenceEntry, isReferenceEntry := entry.(*rsl.ReferenceEntry)
+	if !isReferenceEntry {
+		return "", rsl.ErrInvalidRSLEntry
+	}
+
+	if referenceEntry.TargetID.IsZero() || strings.HasPrefix(referenceEntry.RefName, gitinterface.TagRefPrefix) {
+		// Deletions and tags don't introduce commits to a branch
+		return "", nil
+	}
+
+	switch strategy {
+	case tuf.MergeStrategyLinearHistory:
+		commitIDs, err := getCommits(repo, referenceEntry)
+		if err != nil {
+			return "", err
+		}
+		return findMergeCommit(repo, commitIDs)
+
+	case tuf.MergeStrategyMergeCommit:
+		previousEntry, err := getPriorUnskippedEntry(ctx, repo, policy, referenceEntry.RefName, referenceEntry.GetID())
+		if err != nil {
+			if errors.Is(err, rsl.ErrRSLEntryNotFound) {
+				slog.Debug(fmt.Sprintf("Entry '%s' is the first one for reference '%s', cannot check if it's a merge commit", referenceEntry.GetID().String(), referenceEntry.RefName))
+				return "", nil
+			}
+
+			return "", err
+		}
+
+		previousTargetID := previousEntry.GetTargetID()
+		if previousTargetID.IsZero() || previousTargetID.Equal(referenceEntry.TargetID) {
+			// The reference is being recreated or is unchanged
+			return "", nil
+		}
+
+		parentIDs, err := repo.GetCommitParentIDs(referenceEntry.TargetID)
+		if err != nil {
+			return "", err
+		}
+		if len(parentIDs) < 2 || !parentIDs[0].Equal(previousTargetID) {
+			return fmt.Sprintf("'%s' is not a merge commit whose first parent is '%s'", referenceEntry.TargetID.String(), previousTargetID.String()), nil
+		}
+
+		return "", nil
+
+	default:
+		return "", tuf.ErrInvalidMergeStrategy
+	}
+}
+
+// findMergeCommit returns a description of the first merge commit in the
+// specified commits, or an empty string if none of them are merge commits.
+func findMergeCommit(repo *gitinterface.Repository, commitIDs []gitinterface.Hash) (string, error) {
+	for _, commitID := range commitIDs {
+		parentIDs, err := repo.GetCommitParentIDs(commitID)
+		if err != nil {
+			return "", err
+		}
+		if len(parentIDs) > 1 {
+			return fmt.Sprintf("'%s' is a merge commit", commitID.String()), nil
+		}
+	}
+
+	return "", nil
+}
+
+// checkHookExecutions checks that each of the named hooks passed for the update
+// recorded in the RSL entry, as attested by a principal the hook is run by. A
+// description of the first hook that did not pass is returned, or an empty
+// string if they all passed.
+func checkHookExecutions(ctx context.Context, policy *State, hookNames []string, entryID gitinterface.Hash, options *verifyGitObjectAndAttestationsOptions) (string, error) {
+	// The rule type only accepts git:<> as patterns, so entryID must be for
+	// an RSL reference entry
+	entry, err := rsl.GetEntry(policy.repository, entryID)
+	if err != nil {
+		return "", err
+	}
+	referenceEntry, isReferenceEntry := entry.(*rsl.ReferenceEntry)
+	if !isReferenceEntry {
+		return "", rsl.ErrInvalidRSLEntry
+	}
+
+	if referenceEntry.TargetID.IsZero() {
+		// Hooks are not run when a reference is deleted
+		return "", nil
+	}
+
+	fromID := gitinterface.ZeroHash
+	priorRefEntry, err := getPriorUnskippedEntry(ctx, policy.repository, policy, referenceEntry.RefName, referenceEntry.ID)
+	if err == nil {
+		fromID = priorRefEntry.GetTargetID()
+	} else if !errors.Is(err, rsl.ErrRSLEntryNotFound) {
+		return "", err
+	}
+
+	for _, hookName := range hookNames {
+		var hook tuf.Hook
+		for _, stageHooks := range policy.Hooks {
+			for _, stageHook := range stageHooks {
+				if stageHook.ID() == hookName {
+					hook = stageHook
+				}
+			}
+		}
+		if hook == nil {
+			return fmt.Sprintf("hook '%s' is not defined in the policy", hookName), nil
+		}
+
+		if options.attestationsState == nil {
+			return fmt.Sprintf("no attestation found for hook '%s'", hookName), nil
+		}
+		env, err := options.attestationsState.GetHookExecutionAttestationFor(policy.repository, referenceEntry.RefName, fromID.String(), referenceEntry.TargetID.String(), hookName)
+		if err != nil {
+			if errors.Is(err, hooks.ErrHookExecutionAttestationNotFound) {
+				return fmt.Sprintf("no attestation found for hook '%s'", hookName), nil
+			}
+
+			return "", err
+		}
+
+		execution, err := attestations.GetHookExecution(env)
+		if err != nil {
+			return "", err
+		}
+		if execution.GetExitCode() != 0 {
+			return fmt.Sprintf("hook '%s' failed with exit code %d", hookName, execution.GetExitCode()), nil
+		}
+
+		principals := []tuf.Principal{}
+		for _, principalID := range hook.GetPrincipalIDs().Contents() {
+			if principal, has := policy.allPrincipals[principalID]; has {
+				principals = append(principals, principal)
+			}
+		}
+		if len(principals) == 0 {
+			return fmt.Sprintf("hook '%s' is not run by any principal in the policy", hookName), nil
+		}
+
+		verifier := &SignatureVerifier{
+			repository:   policy.repository,
+			name:         hookName,
+			threshold:    1,
+			keyRotations: policy.keyRotations,
+		}
+		verifier.setPrincipals(principals, nil)
+		verifier = verifier.at(options.verificationTime).withoutKeys(options.revokedKeyIDs)
+
+		if _, err := verifier.Verify(ctx, gitinterface.ZeroHash, env); err != nil {
+			if errors.Is(err, ErrVerifierConditionsUnmet) {
+				return fmt.Sprintf("attestation for hook '%s' is not signed by a principal the hook is run by", hookName), nil
+			}
+
+			return "", err
+		}
+	}
+
+	return "", nil
+}
+
+func verifyGitObjectAndAttestationsUsingVerifiers(ctx context.Context, verifiers []*SignatureVerifier, gitID gitinterface.Hash, authorizationAttestation *sslibdsse.Envelope, appNames []string, approverIDs *set.Set[string], verifyMergeable bool, recorder *checkRecorder) (string, *set.Set[string], bool, error) {
 	if len(verifiers) == 0 {
 		return "", nil, false, ErrNoVerifiers
 	}
@@ -1069,6 +1982,10 @@ func verifyGitObjectAndAttestationsUsingVerifiers(ctx context.Context, verifiers
 		acceptedPrincipalIDs                *set.Set[string]
 		rslEntrySignatureNeededForThreshold bool
 	)
+
+	// failedChecks records the outcome of each verifier that was tried, and
+	// is only added to the report if no verifier is satisfied
+	failedChecks := []*VerificationCheck{}
 	for _, verifier := range verifiers {
 		trustedPrincipalIDs := verifier.TrustedPrincipalIDs()
 
@@ -1077,6 +1994,7 @@ func verifyGitObjectAndAttestationsUsingVerifiers(ctx context.Context, verifiers
 			// We meet requirements just from the authorization attestation's sigs
 			verifiedUsing = verifier.Name()
 			acceptedPrincipalIDs = usedPrincipalIDs
+			recorder.record(&VerificationCheck{Type: CheckTypeRule, Rule: verifier.Name(), Threshold: verifier.Threshold(), SignedBy: sortedPrincipalIDs(usedPrincipalIDs), Passed: true})
 			break
 		} else if !errors.Is(err, ErrVerifierConditionsUnmet) {
 			return "", nil, false, err
@@ -1124,31 +2042,46 @@ func verifyGitObjectAndAttestationsUsingVerifiers(ctx context.Context, verifiers
 			}
 		}
 
+		// Roles declared in the authorization restrict which of the
+		// verifier's teams each principal's approval counts towards
+		principalHats, err := verifier.getPrincipalHats(authorizationAttestation)
+		if err != nil {
+			return "", nil, false, err
+		}
+
 		// Get a list of used principals that are also trusted by the verifier
 		trustedUsedPrincipalIDs := trustedPrincipalIDs.Intersection(usedPrincipalIDs)
-		if trustedUsedPrincipalIDs.Len() >= verifier.Threshold() {
+		if verifier.countTowardsThreshold(trustedUsedPrincipalIDs, principalHats) >= verifier.Threshold() {
 			// With approvals, we now meet threshold!
 			slog.Debug(fmt.Sprintf("Counted '%d' principals towards threshold '%d' for '%s', threshold met!", trustedUsedPrincipalIDs.Len(), verifier.Threshold(), verifier.Name()))
 			verifiedUsing = verifier.Name()
 			acceptedPrincipalIDs = trustedUsedPrincipalIDs
+			recorder.record(&VerificationCheck{Type: CheckTypeRule, Rule: verifier.Name(), Threshold: verifier.Threshold(), SignedBy: sortedPrincipalIDs(trustedUsedPrincipalIDs), Passed: true})
 			break
 		}
 
 		// If verifyMergeable is true, we only need to meet threshold - 1
 		if verifyMergeable && verifier.Threshold() > 1 {
-			if trustedUsedPrincipalIDs.Len() >= verifier.Threshold()-1 {
+			if verifier.thresholdMetWithOneMorePrincipal(trustedUsedPrincipalIDs, principalHats) {
 				slog.Debug(fmt.Sprintf("Counted '%d' principals towards threshold '%d' for '%s', policies can be met if the merge is by authorized person!", trustedUsedPrincipalIDs.Len(), verifier.Threshold(), verifier.Name()))
 				verifiedUsing = verifier.Name()
 				acceptedPrincipalIDs = trustedPrincipalIDs
 				rslEntrySignatureNeededForThreshold = true
+				recorder.record(&VerificationCheck{Type: CheckTypeRule, Rule: verifier.Name(), Threshold: verifier.Threshold(), SignedBy: sortedPrincipalIDs(trustedUsedPrincipalIDs), Passed: true, Message: "threshold met if merge is performed by an authorized person"})
 				break
 			}
 		}
+
+		failedChecks = append(failedChecks, &VerificationCheck{Type: CheckTypeRule, Rule: verifier.Name(), Threshold: verifier.Threshold(), SignedBy: sortedPrincipalIDs(trustedUsedPrincipalIDs), Passed: false, Message: ErrVerifierConditionsUnmet.Error()})
 	}
 
 	if verifiedUsing != "" {
 		return verifiedUsing, acceptedPrincipalIDs, rslEntrySignatureNeededForThreshold, nil
 	}
 
+	for _, check := range failedChecks {
+		recorder.record(check)
+	}
+
 	return "", nil, false, ErrVerifierConditionsUnmet
 }
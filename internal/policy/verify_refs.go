@@ -0,0 +1,436 @@
+// Copyright The gittuf Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package policy
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"slices"
+	"strings"
+
+	"github.com/gittuf/gittuf/internal/attestations"
+	"github.com/gittuf/gittuf/internal/gitinterface"
+	"github.com/gittuf/gittuf/internal/rsl"
+	"github.com/gittuf/gittuf/internal/tuf"
+)
+
+const gittufNamespacePrefix = "refs/gittuf/"
+
+// RefVerificationResult records the outcome of verifying a single reference
+// when several references are verified in one pass.
+type RefVerificationResult struct {
+	Ref string
+
+	// ExpectedTip is the target of the latest RSL entry for the reference.
+	ExpectedTip gitinterface.Hash
+
+	Report *VerificationReport
+	Err    error
+}
+
+// VerifyAllRefs verifies the latest RSL entry of every reference recorded in
+// the RSL that matches the glob pattern, using the policy applicable at each
+// entry. References in the gittuf namespace are not included, and all
+// references are verified if pattern is empty. The RSL is walked once, and the
+// policy and attestations states are loaded and verified once and shared
+// across references. A result is returned for each reference, sorted by the
+// reference's name; an error is only returned if the RSL cannot be inspected.
+func (v *PolicyVerifier) VerifyAllRefs(ctx context.Context, pattern string) ([]*RefVerificationResult, error) {
+	return v.verifyAllRefs(ctx, pattern, true)
+}
+
+// VerifyAllRefsFull verifies the entire RSL for every reference recorded in the
+// RSL that matches the glob pattern. It otherwise behaves like VerifyAllRefs.
+func (v *PolicyVerifier) VerifyAllRefsFull(ctx context.Context, pattern string) ([]*RefVerificationResult, error) {
+	return v.verifyAllRefs(ctx, pattern, false)
+}
+
+func (v *PolicyVerifier) verifyAllRefs(ctx context.Context, pattern string, latestOnly bool) ([]*RefVerificationResult, error) {
+	if pattern != "" {
+		if err := tuf.ValidatePatterns([]string{pattern}); err != nil {
+			return nil, err
+		}
+	}
+
+	// Each reference has its own report, so the verifier's report is not
+	// meaningful for this workflow
+	v.report = nil
+
+	if v.persistentCacheEnabled {
+		defer v.persistentCache.Commit(v.repo) //nolint:errcheck
+	}
+
+	if !latestOnly && v.useCheckpoints {
+		slog.Debug("Checking for trusted checkpoint...")
+		checkpoint, err := v.findLatestTrustedCheckpoint(ctx)
+		if err == nil {
+			slog.Debug(fmt.Sprintf("Verifying from checkpoint '%s'...", checkpoint.entry.GetID().String()))
+			return v.verifyAllRefsFromCheckpoint(ctx, pattern, checkpoint)
+		}
+		if !errors.Is(err, ErrCheckpointNotFound) {
+			return nil, err
+		}
+		slog.Debug("No trusted checkpoint found...")
+	}
+
+	slog.Debug("Identifying first and latest RSL entries...")
+	firstEntry, _, err := rsl.GetFirstEntry(v.repo)
+	if err != nil {
+		return nil, err
+	}
+	latestEntry, _, err := rsl.GetLatestReferenceUpdaterEntry(v.repo)
+	if err != nil {
+		return nil, err
+	}
+
+	slog.Debug("Identifying all entries in the RSL...")
+	allEntries, annotations, err := rsl.GetReferenceUpdaterEntriesInRange(v.repo, firstEntry.GetID(), latestEntry.GetID())
+	if err != nil {
+		return nil, err
+	}
+
+	loader, err := v.newStateLoader()
+	if err != nil {
+		return nil, err
+	}
+
+	slog.Debug("Loading and verifying all policy states...")
+	policyEntries, err := v.searcher.FindPolicyEntriesInRange(firstEntry, latestEntry)
+	if err != nil {
+		return nil, err
+	}
+	policyChainErrs := loader.verifyPolicyChain(ctx, policyEntries)
+
+	// Record the positions of each reference's entries, and the latest policy
+	// and attestations entries at each position in the RSL
+	var (
+		refEntryIndices   = map[string][]int{}
+		entryIndices      = make(map[string]int, len(allEntries))
+		policyIndex       = make([]int, len(allEntries))
+		attestationsIndex = make([]int, len(allEntries))
+	)
+	lastPolicyIndex, lastAttestationsIndex := -1, -1
+	for index, entry := range allEntries {
+		entryIndices[entry.GetID().String()] = index
+
+		refName := entry.GetRefName()
+		switch {
+		case refName == PolicyRef:
+			lastPolicyIndex = index
+		case refName == attestations.Ref:
+			lastAttestationsIndex = index
+		case matchesRefPattern(pattern, refName):
+			refEntryIndices[refName] = append(refEntryIndices[refName], index)
+		}
+
+		policyIndex[index] = lastPolicyIndex
+		attestationsIndex[index] = lastAttestationsIndex
+	}
+
+	refNames := make([]string, 0, len(refEntryIndices))
+	for refName := range refEntryIndices {
+		refNames = append(refNames, refName)
+	}
+	slices.Sort(refNames)
+
+	results := make([]*RefVerificationResult, 0, len(refNames))
+	for _, refName := range refNames {
+		indices := refEntryIndices[refName]
+		startIndex, lastIndex := indices[0], indices[len(indices)-1]
+
+		if latestOnly {
+			startIndex = lastIndex
+		} else if v.persistentCacheEnabled {
+			slog.Debug(fmt.Sprintf("Cache is enabled, checking for last verified entry for '%s'...", refName))
+			if entryNumber, entryID := v.persistentCache.GetLastVerifiedEntryForRef(refName); entryNumber != 0 {
+				if index, has := entryIndices[entryID.String()]; has {
+					startIndex = index
+				}
+			}
+		}
+
+		slog.Debug(fmt.Sprintf("Verifying '%s'...", refName))
+		result := &RefVerificationResult{
+			Ref:         refName,
+			ExpectedTip: allEntries[lastIndex].GetTargetID(),
+			Report:      NewVerificationReport(refName),
+		}
+
+		result.Err = v.verifyRefInRange(ctx, refName, allEntries[startIndex:lastIndex+1], annotations, policyIndex[startIndex], attestationsIndex[startIndex], allEntries, policyChainErrs, loader, result.Report)
+		result.Report.SetOutcome(result.Err)
+
+		results = append(results, result)
+	}
+
+	return results, nil
+}
+
+// verifyRefInRange verifies the entries for refName in the specified range of
+// the RSL. policyIndex and attestationsIndex identify the entries in allEntries
+// for the policy and attestations applicable at the start of the range, and are
+// -1 if no such entry exists.
+func (v *PolicyVerifier) verifyRefInRange(ctx context.Context, refName string, entriesInRange []rsl.ReferenceUpdaterEntry, annotations map[string][]*rsl.AnnotationEntry, policyIndex, attestationsIndex int, allEntries []rsl.ReferenceUpdaterEntry, policyChainErrs map[string]error, loader *stateLoader, report *VerificationReport) error {
+	var (
+		currentPolicy       *State
+		currentAttestations *attestations.Attestations
+		err                 error
+	)
+
+	if policyIndex != -1 {
+		currentPolicy, err = loader.loadVerifiedPolicy(ctx, allEntries[policyIndex], policyChainErrs)
+		if err != nil {
+			return err
+		}
+	}
+
+	if attestationsIndex != -1 {
+		currentAttestations, err = loader.loadAttestations(allEntries[attestationsIndex])
+		if err != nil {
+			return err
+		}
+	}
+
+	entries := []rsl.ReferenceUpdaterEntry{}
+	for _, entry := range entriesInRange {
+		entryRefName := entry.GetRefName()
+		if entryRefName == refName || (strings.HasPrefix(entryRefName, gittufNamespacePrefix) && entryRefName != PolicyStagingRef) {
+			entries = append(entries, entry)
+		}
+	}
+
+	return v.verifyEntriesForRef(ctx, entries[0], entries, annotations, currentPolicy, currentAttestations, loader, report)
+}
+
+// stateLoader loads the policy and attestations states recorded in RSL entries.
+// Loaded states are cached so that each state is loaded and verified at most
+// once when several references are verified in one pass.
+type stateLoader struct {
+	repo *gitinterface.Repository
+
+	policyStates       map[string]*State
+	attestationsStates map[string]*attestations.Attestations
+
+	// verifiedPolicies records the policy state each policy entry's state was
+	// successfully verified with.
+	verifiedPolicies map[string]*State
+
+	// validatedPolicies records the outcome of validating the metadata of
+	// each policy state used as the initial state for verification.
+	validatedPolicies map[string]error
+
+	// metadataVersions records the highest version of each metadata file in
+	// the policy states up to and including each policy entry's state.
+	metadataVersions map[string]map[string]int
+
+	// keyRevocations records the key revocations in the latest policy, which
+	// are enforced using every policy state.
+	keyRevocations []*keyRevocation
+}
+
+func newStateLoader(repo *gitinterface.Repository) *stateLoader {
+	return &stateLoader{
+		repo:               repo,
+		policyStates:       map[string]*State{},
+		attestationsStates: map[string]*attestations.Attestations{},
+		verifiedPolicies:   map[string]*State{},
+		validatedPolicies:  map[string]error{},
+		metadataVersions:   map[string]map[string]int{},
+	}
+}
+
+// newStateLoader returns a state loader for the verifier's repository that
+// enforces the key revocations recorded in the latest policy using every policy
+// state it loads. This ensures a compromised key is not trusted for RSL entries
+// recorded after it was revoked, even when they're verified using policy states
+// from before the revocation.
+func (v *PolicyVerifier) newStateLoader() (*stateLoader, error) {
+	loader := newStateLoader(v.repo)
+
+	latestPolicyEntry, err := v.searcher.FindLatestPolicyEntry()
+	if err != nil {
+		if errors.Is(err, ErrPolicyNotFound) {
+			return loader, nil
+		}
+		return nil, err
+	}
+
+	latestPolicy, err := loader.loadPolicy(latestPolicyEntry)
+	if err != nil {
+		return nil, err
+	}
+	loader.keyRevocations = latestPolicy.keyRevocations
+
+	return loader, nil
+}
+
+// loadPolicy returns the policy state recorded in the entry.
+func (l *stateLoader) loadPolicy(entry rsl.ReferenceUpdaterEntry) (*State, error) {
+	if state, has := l.policyStates[entry.GetID().String()]; has {
+		return state, nil
+	}
+
+	state, err := loadStateForEntry(l.repo, entry)
+	if err != nil {
+		return nil, err
+	}
+	state.addKeyRevocations(l.keyRevocations)
+
+	l.policyStates[entry.GetID().String()] = state
+	return state, nil
+}
+
+// loadAttestations returns the attestations state recorded in the entry.
+func (l *stateLoader) loadAttestations(entry rsl.ReferenceUpdaterEntry) (*attestations.Attestations, error) {
+	if attestationsState, has := l.attestationsStates[entry.GetID().String()]; has {
+		return attestationsState, nil
+	}
+
+	attestationsState, err := attestations.LoadAttestationsForEntry(l.repo, entry)
+	if err != nil {
+		return nil, err
+	}
+
+	l.attestationsStates[entry.GetID().String()] = attestationsState
+	return attestationsState, nil
+}
+
+// verifyNewState verifies the policy state recorded in the entry using the
+// current policy state, skipping the verification if it has already succeeded.
+func (l *stateLoader) verifyNewState(ctx context.Context, currentPolicy *State, entry rsl.ReferenceUpdaterEntry, newPolicy *State) error {
+	if verifiedWith, has := l.verifiedPolicies[entry.GetID().String()]; has && verifiedWith == currentPolicy {
+		return nil
+	}
+
+	metadataVersions, err := l.loadMetadataVersions(currentPolicy)
+	if err != nil {
+		return err
+	}
+
+	if err := currentPolicy.verifyNewState(ctx, newPolicy, metadataVersions); err != nil {
+		return err
+	}
+
+	l.verifiedPolicies[entry.GetID().String()] = currentPolicy
+	return nil
+}
+
+// loadMetadataVersions returns the highest version of each metadata file in the
+// policy states recorded up to and including the entry the policy was loaded
+// from. New policy states are checked against these versions so that a rule
+// file can't be rolled back by removing it and adding an older copy later.
+func (l *stateLoader) loadMetadataVersions(policy *State) (map[string]int, error) {
+	if policy.loadedEntry == nil {
+		return policy.GetMetadataVersions(), nil
+	}
+
+	// Walk back to a policy entry whose versions are known, or to the first
+	// policy entry
+	var (
+		entries  = []rsl.ReferenceUpdaterEntry{policy.loadedEntry}
+		versions map[string]int
+	)
+	for {
+		if known, has := l.metadataVersions[entries[len(entries)-1].GetID().String()]; has {
+			versions = known
+			entries = entries[:len(entries)-1]
+			break
+		}
+
+		priorEntry, _, err := rsl.GetLatestReferenceUpdaterEntry(l.repo, rsl.ForReference(PolicyRef), rsl.BeforeEntryID(entries[len(entries)-1].GetID()))
+		if err != nil {
+			if errors.Is(err, rsl.ErrRSLEntryNotFound) {
+				break
+			}
+			return nil, err
+		}
+		entries = append(entries, priorEntry)
+	}
+
+	for _, entry := range slices.Backward(entries) {
+		state, err := l.loadPolicy(entry)
+		if err != nil {
+			return nil, err
+		}
+
+		versions = mergeMetadataVersions(versions, state)
+		l.metadataVersions[entry.GetID().String()] = versions
+	}
+
+	return versions, nil
+}
+
+// verifyPolicyChain verifies the root of trust of each policy state in the
+// specified entries using the policy state prior to it, trusting the very first
+// policy state. It returns the error for each entry whose state cannot be
+// loaded or verified. Once a state fails verification, all subsequent states
+// are also considered unverified.
+func (l *stateLoader) verifyPolicyChain(ctx context.Context, policyEntries []rsl.ReferenceUpdaterEntry) map[string]error {
+	chainErrs := map[string]error{}
+
+	var (
+		verifiedState *State
+		chainErr      error
+	)
+	for _, entry := range policyEntries {
+		if entry.GetRefName() != PolicyRef {
+			// The searcher _may_ include refs/gittuf/attestations etc. which
+			// should be skipped
+			continue
+		}
+
+		if chainErr == nil {
+			underTestState, err := l.loadPolicy(entry)
+			switch {
+			case err != nil:
+				chainErr = err
+			case verifiedState == nil:
+				slog.Debug(fmt.Sprintf("Trusting root of trust for initial policy '%s'...", entry.GetID().String()))
+				verifiedState = underTestState
+			default:
+				slog.Debug(fmt.Sprintf("Verifying root of trust for policy '%s'...", entry.GetID().String()))
+				if err := l.verifyNewState(ctx, verifiedState, entry, underTestState); err != nil {
+					chainErr = fmt.Errorf("unable to verify roots of trust for policy states: %w", err)
+				} else {
+					verifiedState = underTestState
+				}
+			}
+		}
+
+		if chainErr != nil {
+			chainErrs[entry.GetID().String()] = chainErr
+		}
+	}
+
+	return chainErrs
+}
+
+// loadVerifiedPolicy returns the policy state recorded in the entry after
+// checking that its root of trust was verified and that its metadata is validly
+// signed.
+func (l *stateLoader) loadVerifiedPolicy(ctx context.Context, entry rsl.ReferenceUpdaterEntry, policyChainErrs map[string]error) (*State, error) {
+	if err, has := policyChainErrs[entry.GetID().String()]; has {
+		return nil, err
+	}
+
+	state, err := l.loadPolicy(entry)
+	if err != nil {
+		return nil, err
+	}
+
+	err, validated := l.validatedPolicies[entry.GetID().String()]
+	if !validated {
+		slog.Debug(fmt.Sprintf("Validating policy state at entry '%s'...", entry.GetID().String()))
+		if verifyErr := state.Verify(ctx); verifyErr != nil {
+			err = fmt.Errorf("requested state has invalidly signed metadata: %w", verifyErr)
+		}
+		l.validatedPolicies[entry.GetID().String()] = err
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return state, nil
+}
@@ -0,0 +1,130 @@
+// Copyright The gittuf Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package policy
+
+import (
+	"testing"
+
+	"github.com/gittuf/gittuf/internal/common"
+	"github.com/gittuf/gittuf/internal/dev"
+	"github.com/gittuf/gittuf/internal/rsl"
+	"github.com/gittuf/gittuf/internal/tuf"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestVerifyAllRefs(t *testing.T) {
+	repo, _ := createTestRepository(t, createTestStateWithPolicy)
+
+	mainRef := "refs/heads/main"
+	featureRef := "refs/heads/feature"
+	releaseRef := "refs/heads/release"
+
+	commitIDs := common.AddNTestCommitsToSpecifiedRef(t, repo, mainRef, 1, gpgKeyBytes)
+	common.CreateTestRSLReferenceEntryCommit(t, repo, rsl.NewReferenceEntry(mainRef, commitIDs[0]), gpgKeyBytes)
+	mainTip := commitIDs[0]
+
+	// Policy violation for feature, followed by a valid update
+	commitIDs = common.AddNTestCommitsToSpecifiedRef(t, repo, featureRef, 1, gpgUnauthorizedKeyBytes)
+	common.CreateTestRSLReferenceEntryCommit(t, repo, rsl.NewReferenceEntry(featureRef, commitIDs[0]), gpgKeyBytes)
+	commitIDs = common.AddNTestCommitsToSpecifiedRef(t, repo, featureRef, 1, gpgKeyBytes)
+	common.CreateTestRSLReferenceEntryCommit(t, repo, rsl.NewReferenceEntry(featureRef, commitIDs[0]), gpgKeyBytes)
+	featureTip := commitIDs[0]
+
+	commitIDs = common.AddNTestCommitsToSpecifiedRef(t, repo, releaseRef, 1, gpgKeyBytes)
+	common.CreateTestRSLReferenceEntryCommit(t, repo, rsl.NewReferenceEntry(releaseRef, commitIDs[0]), gpgKeyBytes)
+	releaseTip := commitIDs[0]
+
+	t.Run("full verification", func(t *testing.T) {
+		verifier := NewPolicyVerifier(repo)
+		results, err := verifier.VerifyAllRefsFull(testCtx, "")
+		require.Nil(t, err)
+		require.Len(t, results, 3)
+
+		// Results are sorted by ref name
+		assert.Equal(t, featureRef, results[0].Ref)
+		assert.Equal(t, featureTip, results[0].ExpectedTip)
+		assert.ErrorIs(t, results[0].Err, ErrVerificationFailed)
+		assert.False(t, results[0].Report.Passed)
+		assert.NotEmpty(t, results[0].Report.Failures())
+
+		assert.Equal(t, mainRef, results[1].Ref)
+		assert.Equal(t, mainTip, results[1].ExpectedTip)
+		assert.Nil(t, results[1].Err)
+		assert.True(t, results[1].Report.Passed)
+
+		assert.Equal(t, releaseRef, results[2].Ref)
+		assert.Equal(t, releaseTip, results[2].ExpectedTip)
+		assert.Nil(t, results[2].Err)
+
+		// Results match those of verifying each ref independently
+		for _, result := range results {
+			_, err := NewPolicyVerifier(repo).VerifyRefFull(testCtx, result.Ref)
+			assert.Equal(t, err, result.Err)
+		}
+	})
+
+	t.Run("latest only", func(t *testing.T) {
+		verifier := NewPolicyVerifier(repo)
+		results, err := verifier.VerifyAllRefs(testCtx, "")
+		require.Nil(t, err)
+		require.Len(t, results, 3)
+
+		for _, result := range results {
+			assert.Nil(t, result.Err)
+			assert.True(t, result.Report.Passed)
+		}
+	})
+
+	t.Run("with pattern", func(t *testing.T) {
+		verifier := NewPolicyVerifier(repo)
+		results, err := verifier.VerifyAllRefsFull(testCtx, "refs/heads/ma*")
+		require.Nil(t, err)
+		require.Len(t, results, 1)
+		assert.Equal(t, mainRef, results[0].Ref)
+		assert.Nil(t, results[0].Err)
+
+		results, err = verifier.VerifyAllRefsFull(testCtx, "refs/tags/*")
+		require.Nil(t, err)
+		assert.Empty(t, results)
+
+		// Patterns use the same syntax as rules, so `*` doesn't match path
+		// separators
+		results, err = verifier.VerifyAllRefsFull(testCtx, "refs/*")
+		require.Nil(t, err)
+		assert.Empty(t, results)
+
+		results, err = verifier.VerifyAllRefsFull(testCtx, "refs/**")
+		require.Nil(t, err)
+		assert.Len(t, results, 3)
+
+		_, err = verifier.VerifyAllRefsFull(testCtx, "refs/heads/[ma")
+		assert.ErrorIs(t, err, tuf.ErrInvalidPattern)
+	})
+}
+
+func TestStateLoaderVerifyPolicyChain(t *testing.T) {
+	t.Run("rule file rolled back after removal", func(t *testing.T) {
+		t.Setenv(dev.DevModeKey, "1")
+
+		repo, policyEntries := createTestRepositoryWithReaddedRuleFile(t)
+
+		chainErrs := newStateLoader(repo).verifyPolicyChain(testCtx, policyEntries)
+		assert.Len(t, chainErrs, 1)
+		err := chainErrs[policyEntries[3].GetID().String()]
+		assert.ErrorIs(t, err, ErrMetadataRollback)
+		assert.ErrorContains(t, err, "'1' is at version 1, current version is 2")
+	})
+
+	t.Run("rule file rolled back after removal, starting from a later state", func(t *testing.T) {
+		t.Setenv(dev.DevModeKey, "1")
+
+		repo, policyEntries := createTestRepositoryWithReaddedRuleFile(t)
+
+		// The versions in the policy states before the removal are still
+		// enforced when the chain is verified from the removal onwards
+		chainErrs := newStateLoader(repo).verifyPolicyChain(testCtx, policyEntries[2:])
+		assert.ErrorIs(t, chainErrs[policyEntries[3].GetID().String()], ErrMetadataRollback)
+	})
+}
@@ -4,18 +4,24 @@
 package policy
 
 import (
+	"context"
+	"fmt"
 	"os"
 	"path/filepath"
 	"sort"
 	"testing"
+	"time"
 
 	"github.com/gittuf/gittuf/internal/attestations"
 	authorizationsv01 "github.com/gittuf/gittuf/internal/attestations/authorizations/v01"
 	"github.com/gittuf/gittuf/internal/common"
 	"github.com/gittuf/gittuf/internal/dev"
 	"github.com/gittuf/gittuf/internal/gitinterface"
+	policyopts "github.com/gittuf/gittuf/internal/policy/options/policy"
 	"github.com/gittuf/gittuf/internal/rsl"
 	"github.com/gittuf/gittuf/internal/signerverifier/dsse"
+	"github.com/gittuf/gittuf/internal/signerverifier/gpg"
+	"github.com/gittuf/gittuf/internal/signerverifier/ssh"
 	sslibdsse "github.com/gittuf/gittuf/internal/third_party/go-securesystemslib/dsse"
 	"github.com/gittuf/gittuf/internal/tuf"
 	tufv01 "github.com/gittuf/gittuf/internal/tuf/v01"
@@ -213,6 +219,61 @@ func TestVerifyRelativeForRefUsingPersons(t *testing.T) {
 		assert.Nil(t, err)
 	})
 
+	t.Run("with recovery, skip annotations controlled by policy", func(t *testing.T) {
+		t.Setenv(dev.DevModeKey, "1")
+
+		repo, _ := createTestRepository(t, createTestStateWithAnnotationPolicy)
+		refName := "refs/heads/main"
+
+		commitIDs := common.AddNTestCommitsToSpecifiedRef(t, repo, refName, 1, gpgKeyBytes)
+		firstEntry := rsl.NewReferenceEntry(refName, commitIDs[0])
+		firstEntryID := common.CreateTestRSLReferenceEntryCommit(t, repo, firstEntry, gpgKeyBytes)
+		firstEntry.ID = firstEntryID
+
+		validCommitID := commitIDs[0] // track this for later
+		commitIDs = common.AddNTestCommitsToSpecifiedRef(t, repo, refName, 1, gpgUnauthorizedKeyBytes)
+		invalidEntry := rsl.NewReferenceEntry(refName, commitIDs[0])
+		invalidEntryID := common.CreateTestRSLReferenceEntryCommit(t, repo, invalidEntry, gpgUnauthorizedKeyBytes)
+		invalidEntry.ID = invalidEntryID
+
+		// Fix using the known-good commit, with a skip annotation that doesn't
+		// meet the threshold of the annotation rule
+		if err := repo.SetReference(refName, validCommitID); err != nil {
+			t.Fatal(err)
+		}
+		annotation := rsl.NewAnnotationEntry([]gitinterface.Hash{invalidEntryID}, true, "invalid entry")
+		annotation.ID = common.CreateTestRSLAnnotationEntryCommit(t, repo, annotation, gpgKeyBytes)
+
+		entry := rsl.NewReferenceEntry(refName, validCommitID)
+		entryID := common.CreateTestRSLReferenceEntryCommit(t, repo, entry, gpgKeyBytes)
+		entry.ID = entryID
+
+		// The skip annotation is ignored
+		verifier := NewPolicyVerifier(repo)
+		err := verifier.VerifyRelativeForRef(testCtx, firstEntry, entry, refName)
+		assert.ErrorIs(t, err, ErrVerificationFailed)
+
+		report := verifier.Report()
+		found := false
+		for _, check := range report.Checks {
+			if check.Type == CheckTypeAnnotation {
+				found = true
+				assert.False(t, check.Passed)
+				assert.Equal(t, invalidEntryID.String(), check.EntryID)
+				assert.Equal(t, ErrUnauthorizedSkipAnnotation.Error(), check.Message)
+			}
+		}
+		assert.True(t, found)
+
+		// A second skip annotation meets the threshold
+		annotation = rsl.NewAnnotationEntry([]gitinterface.Hash{invalidEntryID}, true, "invalid entry")
+		annotation.ID = common.CreateTestRSLAnnotationEntryCommit(t, repo, annotation, rootKeyBytes)
+
+		verifier = NewPolicyVerifier(repo)
+		err = verifier.VerifyRelativeForRef(testCtx, firstEntry, entry, refName)
+		assert.Nil(t, err)
+	})
+
 	t.Run("with recovery, commit-same, recovered by unauthorized user", func(t *testing.T) {
 		repo, _ := createTestRepository(t, createTestStateWithPolicyUsingPersons)
 		refName := "refs/heads/main"
@@ -1371,6 +1432,51 @@ func TestVerifyMergeable(t *testing.T) {
 		assert.Nil(t, err)
 		assert.False(t, rslSignatureRequired)
 	})
+
+	t.Run("merge strategy rule requiring linear history", func(t *testing.T) {
+		repo, _ := createTestRepository(t, func(t *testing.T) *State {
+			t.Helper()
+			return createTestStateWithGlobalConstraintMergeStrategy(t, tuf.MergeStrategyLinearHistory)
+		})
+
+		// We need to change the directory for this test because we `checkout`
+		// for older Git versions, modifying the worktree. This chdir ensures
+		// that the temporary directory is used as the worktree.
+		pwd, err := os.Getwd()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := os.Chdir(filepath.Join(repo.GetGitDir(), "..")); err != nil {
+			t.Fatal(err)
+		}
+		defer os.Chdir(pwd) //nolint:errcheck
+
+		commitIDs := common.AddNTestCommitsToSpecifiedRef(t, repo, refName, 1, gpgKeyBytes)
+		entry := rsl.NewReferenceEntry(refName, commitIDs[0])
+		common.CreateTestRSLReferenceEntryCommit(t, repo, entry, gpgKeyBytes)
+
+		if err := repo.SetReference(featureRefName, commitIDs[0]); err != nil {
+			t.Fatal(err)
+		}
+		featureCommitIDs := common.AddNTestCommitsToSpecifiedRef(t, repo, featureRefName, 1, gpgKeyBytes)
+
+		verifier := NewPolicyVerifier(repo)
+
+		// Fast-forwarding main keeps its history linear
+		_, err = verifier.VerifyMergeableForCommit(testCtx, refName, featureCommitIDs[0])
+		assert.Nil(t, err)
+
+		// Merge commits on the feature branch break linear history
+		otherCommitIDs := common.AddNTestCommitsToSpecifiedRef(t, repo, "refs/heads/other", 1, gpgKeyBytes)
+		mergeCommitID := common.AddTestMergeCommitToSpecifiedRef(t, repo, featureRefName, otherCommitIDs[0])
+
+		_, err = verifier.VerifyMergeableForCommit(testCtx, refName, mergeCommitID)
+		assert.ErrorIs(t, err, ErrVerificationFailed)
+		failures := verifier.Report().Failures()
+		if assert.Len(t, failures, 1) {
+			assert.Equal(t, fmt.Sprintf("'%s' is a merge commit", mergeCommitID.String()), failures[0].Message)
+		}
+	})
 }
 
 func TestVerifyMergeableForCommit(t *testing.T) {
@@ -2651,6 +2757,90 @@ func TestVerifyRelativeForRef(t *testing.T) {
 	})
 }
 
+func TestVerifyRelativeForRefConcurrently(t *testing.T) {
+	repo, _ := createTestRepository(t, createTestStateWithPolicy)
+	refName := "refs/heads/main"
+
+	addEntries := func(n int, keyBytes []byte) gitinterface.Hash {
+		var entryID gitinterface.Hash
+		for i := 0; i < n; i++ {
+			commitIDs := common.AddNTestCommitsToSpecifiedRef(t, repo, refName, 1, keyBytes)
+			entryID = common.CreateTestRSLReferenceEntryCommit(t, repo, rsl.NewReferenceEntry(refName, commitIDs[0]), keyBytes)
+		}
+		return entryID
+	}
+
+	verify := func(workers int) (*VerificationReport, error) {
+		firstEntry, _, err := rsl.GetFirstReferenceUpdaterEntryForRef(repo, refName)
+		require.Nil(t, err)
+		lastEntry, _, err := rsl.GetLatestReferenceUpdaterEntry(repo, rsl.ForReference(refName))
+		require.Nil(t, err)
+
+		verifier := NewPolicyVerifier(repo, policyopts.WithWorkers(workers))
+		err = verifier.VerifyRelativeForRef(testCtx, firstEntry, lastEntry, refName)
+		return verifier.Report(), err
+	}
+
+	addEntries(5, gpgKeyBytes)
+	validCommitID, err := repo.GetReference(refName)
+	require.Nil(t, err)
+
+	// Add an invalid entry that is skipped and fixed
+	invalidEntryID := addEntries(1, gpgUnauthorizedKeyBytes)
+	common.CreateTestRSLAnnotationEntryCommit(t, repo, rsl.NewAnnotationEntry([]gitinterface.Hash{invalidEntryID}, true, "invalid entry"), gpgKeyBytes)
+	require.Nil(t, repo.SetReference(refName, validCommitID))
+	common.CreateTestRSLReferenceEntryCommit(t, repo, rsl.NewReferenceEntry(refName, validCommitID), gpgKeyBytes)
+
+	addEntries(5, gpgKeyBytes)
+
+	expectedReport, expectedErr := verify(1)
+	assert.Nil(t, expectedErr)
+	assert.Len(t, checksOfType(expectedReport, CheckTypeRecovery), 1)
+	for _, workers := range []int{2, 4, 16} {
+		report, err := verify(workers)
+		assert.Nil(t, err)
+		assert.Equal(t, expectedReport, report)
+	}
+
+	// Add invalid entries that are not skipped, the first one must be reported
+	invalidEntryID = addEntries(1, gpgUnauthorizedKeyBytes)
+	addEntries(2, gpgKeyBytes)
+	addEntries(1, gpgUnauthorizedKeyBytes)
+
+	expectedReport, expectedErr = verify(1)
+	assert.ErrorIs(t, expectedErr, ErrVerificationFailed)
+	failures := expectedReport.Failures()
+	require.Len(t, failures, 1)
+	assert.Equal(t, invalidEntryID.String(), failures[0].EntryID)
+	for _, workers := range []int{2, 4, 16} {
+		report, err := verify(workers)
+		assert.Equal(t, expectedErr, err)
+		assert.Equal(t, expectedReport, report)
+	}
+
+	// No entries are verified concurrently once the context is cancelled,
+	// they're left for the sequential walk
+	firstEntry, _, err := rsl.GetFirstReferenceUpdaterEntryForRef(repo, refName)
+	require.Nil(t, err)
+	lastEntry, _, err := rsl.GetLatestReferenceUpdaterEntry(repo, rsl.ForReference(refName))
+	require.Nil(t, err)
+	entries, _, err := rsl.GetReferenceUpdaterEntriesInRangeForRef(repo, firstEntry.GetID(), lastEntry.GetID(), refName)
+	require.Nil(t, err)
+	policyEntry, _, err := rsl.GetLatestReferenceUpdaterEntry(repo, rsl.ForReference(PolicyRef))
+	require.Nil(t, err)
+	currentPolicy, err := LoadState(testCtx, repo, policyEntry)
+	require.Nil(t, err)
+
+	verifier := NewPolicyVerifier(repo, policyopts.WithWorkers(4))
+	loader, err := verifier.newStateLoader()
+	require.Nil(t, err)
+
+	cancelledCtx, cancel := context.WithCancel(testCtx)
+	cancel()
+	results := verifier.verifyEntriesConcurrently(cancelledCtx, firstEntry, entries, currentPolicy, nil, loader)
+	assert.Empty(t, results)
+}
+
 func TestVerifyEntry(t *testing.T) {
 	refName := "refs/heads/main"
 
@@ -2662,7 +2852,7 @@ func TestVerifyEntry(t *testing.T) {
 		entryID := common.CreateTestRSLReferenceEntryCommit(t, repo, entry, gpgKeyBytes)
 		entry.ID = entryID
 
-		err := verifyEntry(testCtx, repo, state, nil, entry)
+		err := verifyEntry(testCtx, repo, state, nil, entry, nil)
 		assert.Nil(t, err)
 	})
 
@@ -2676,128 +2866,256 @@ func TestVerifyEntry(t *testing.T) {
 		entryID := common.CreateTestRSLReferenceEntryCommit(t, repo, entry, gpgKeyBytes)
 		entry.ID = entryID
 
-		err := verifyEntry(testCtx, repo, state, nil, entry)
+		err := verifyEntry(testCtx, repo, state, nil, entry, nil)
 		assert.Nil(t, err)
 	})
 
-	t.Run("successful verification with higher threshold using v0.1 reference authorization", func(t *testing.T) {
-		repo, state := createTestRepository(t, createTestStateWithThresholdPolicy)
+	t.Run("unsuccessful verification with expired root metadata", func(t *testing.T) {
+		repo, state := createTestRepository(t, createTestStateWithPolicy)
 
-		currentAttestations, err := attestations.LoadCurrentAttestations(repo)
+		commitIDs := common.AddNTestCommitsToSpecifiedRef(t, repo, refName, 1, gpgKeyBytes)
+		entry := rsl.NewReferenceEntry(refName, commitIDs[0])
+		entryID := common.CreateTestRSLReferenceEntryCommit(t, repo, entry, gpgKeyBytes)
+		entry.ID = entryID
+
+		entryTime, err := repo.GetCommitTime(entryID)
 		if err != nil {
 			t.Fatal(err)
 		}
 
+		// Expire root metadata before the entry was recorded
+		state.expiries[RootRoleName] = entryTime.Add(-time.Hour)
+
+		err = verifyEntry(testCtx, repo, state, nil, entry, nil)
+		assert.ErrorIs(t, err, ErrVerificationFailed)
+		assert.ErrorIs(t, err, ErrMetadataExpired)
+	})
+
+	t.Run("successful verification with metadata expiring after entry", func(t *testing.T) {
+		repo, state := createTestRepository(t, createTestStateWithPolicy)
+
 		commitIDs := common.AddNTestCommitsToSpecifiedRef(t, repo, refName, 1, gpgKeyBytes)
+		entry := rsl.NewReferenceEntry(refName, commitIDs[0])
+		entryID := common.CreateTestRSLReferenceEntryCommit(t, repo, entry, gpgKeyBytes)
+		entry.ID = entryID
 
-		commitTreeID, err := repo.GetCommitTreeID(commitIDs[0])
+		entryTime, err := repo.GetCommitTime(entryID)
 		if err != nil {
 			t.Fatal(err)
 		}
 
-		// Create authorization for this change
-		// We're explicitly using the old type here to ensure policy
-		// verification still works
-		authorization, err := authorizationsv01.NewReferenceAuthorization(refName, gitinterface.ZeroHash.String(), commitTreeID.String())
+		// Rule file expires after the entry was recorded
+		state.expiries[TargetsRoleName] = entryTime.Add(time.Hour)
+
+		err = verifyEntry(testCtx, repo, state, nil, entry, nil)
+		assert.Nil(t, err)
+	})
+
+	t.Run("successful verification with expired rule file not used for entry", func(t *testing.T) {
+		repo, state := createTestRepository(t, createTestStateWithPolicy)
+
+		commitIDs := common.AddNTestCommitsToSpecifiedRef(t, repo, refName, 1, gpgKeyBytes)
+		entry := rsl.NewReferenceEntry(refName, commitIDs[0])
+		entryID := common.CreateTestRSLReferenceEntryCommit(t, repo, entry, gpgKeyBytes)
+		entry.ID = entryID
+
+		entryTime, err := repo.GetCommitTime(entryID)
 		if err != nil {
 			t.Fatal(err)
 		}
 
-		signer := setupSSHKeysForSigning(t, targets1KeyBytes, targets1PubKeyBytes)
+		// Rule file that isn't walked for the ref expired before the entry
+		// was recorded
+		state.expiries["unrelated"] = entryTime.Add(-time.Hour)
 
-		env, err := dsse.CreateEnvelope(authorization)
+		err = verifyEntry(testCtx, repo, state, nil, entry, nil)
+		assert.Nil(t, err)
+	})
+
+	t.Run("unsuccessful verification with principal trusted until before entry", func(t *testing.T) {
+		t.Setenv(dev.DevModeKey, "1")
+
+		repo, state := createTestRepository(t, createTestStateWithPolicy)
+
+		commitIDs := common.AddNTestCommitsToSpecifiedRef(t, repo, refName, 1, gpgKeyBytes)
+		entry := rsl.NewReferenceEntry(refName, commitIDs[0])
+		entryID := common.CreateTestRSLReferenceEntryCommit(t, repo, entry, gpgKeyBytes)
+		entry.ID = entryID
+
+		entryTime, err := repo.GetCommitTime(entryID)
 		if err != nil {
 			t.Fatal(err)
 		}
-		env, err = dsse.SignEnvelope(testCtx, env, signer)
+
+		gpgKeyR, err := gpg.LoadGPGKeyFromBytes(gpgPubKeyBytes)
 		if err != nil {
 			t.Fatal(err)
 		}
 
-		if err := currentAttestations.SetReferenceAuthorization(repo, env, refName, gitinterface.ZeroHash.String(), commitTreeID.String()); err != nil {
+		// Principal's validity window ends before the entry was recorded
+		window, err := tuf.NewValidityWindow(time.Time{}, entryTime.Add(-time.Hour))
+		if err != nil {
 			t.Fatal(err)
 		}
-		if err := currentAttestations.Commit(repo, "Add authorization", true, false); err != nil {
+		updateTestTargetsMetadata(t, state, TargetsRoleName, func(targetsMetadata tuf.TargetsMetadata) {
+			require.Nil(t, targetsMetadata.UpdatePrincipalValidityWindow(tufv01.NewKeyFromSSLibKey(gpgKeyR).KeyID, window))
+		})
+		if err := state.preprocess(); err != nil {
 			t.Fatal(err)
 		}
 
-		currentAttestations, err = attestations.LoadCurrentAttestations(repo)
-		if err != nil {
-			t.Fatal(err)
-		}
+		err = verifyEntry(testCtx, repo, state, nil, entry, nil)
+		assert.ErrorIs(t, err, ErrVerificationFailed)
+	})
+
+	t.Run("successful verification with principal trusted until after entry", func(t *testing.T) {
+		t.Setenv(dev.DevModeKey, "1")
+
+		repo, state := createTestRepository(t, createTestStateWithPolicy)
 
+		commitIDs := common.AddNTestCommitsToSpecifiedRef(t, repo, refName, 1, gpgKeyBytes)
 		entry := rsl.NewReferenceEntry(refName, commitIDs[0])
 		entryID := common.CreateTestRSLReferenceEntryCommit(t, repo, entry, gpgKeyBytes)
 		entry.ID = entryID
 
-		err = verifyEntry(testCtx, repo, state, currentAttestations, entry)
-		assert.Nil(t, err)
-	})
-
-	t.Run("successful verification with higher threshold using latest reference authorization", func(t *testing.T) {
-		repo, state := createTestRepository(t, createTestStateWithThresholdPolicy)
-
-		currentAttestations, err := attestations.LoadCurrentAttestations(repo)
+		entryTime, err := repo.GetCommitTime(entryID)
 		if err != nil {
 			t.Fatal(err)
 		}
 
-		commitIDs := common.AddNTestCommitsToSpecifiedRef(t, repo, refName, 1, gpgKeyBytes)
-
-		commitTreeID, err := repo.GetCommitTreeID(commitIDs[0])
+		gpgKeyR, err := gpg.LoadGPGKeyFromBytes(gpgPubKeyBytes)
 		if err != nil {
 			t.Fatal(err)
 		}
 
-		// Create authorization for this change
-		// This uses the latest reference authorization version
-		authorization, err := attestations.NewReferenceAuthorizationForCommit(refName, gitinterface.ZeroHash.String(), commitTreeID.String())
+		// Principal's validity window ends after the entry was recorded
+		window, err := tuf.NewValidityWindow(time.Time{}, entryTime.Add(time.Hour))
 		if err != nil {
 			t.Fatal(err)
 		}
-
-		signer := setupSSHKeysForSigning(t, targets1KeyBytes, targets1PubKeyBytes)
-
-		env, err := dsse.CreateEnvelope(authorization)
-		if err != nil {
+		updateTestTargetsMetadata(t, state, TargetsRoleName, func(targetsMetadata tuf.TargetsMetadata) {
+			require.Nil(t, targetsMetadata.UpdatePrincipalValidityWindow(tufv01.NewKeyFromSSLibKey(gpgKeyR).KeyID, window))
+		})
+		if err := state.preprocess(); err != nil {
 			t.Fatal(err)
 		}
-		env, err = dsse.SignEnvelope(testCtx, env, signer)
+
+		err = verifyEntry(testCtx, repo, state, nil, entry, nil)
+		assert.Nil(t, err)
+	})
+
+	t.Run("unsuccessful verification with entry backdated into principal's validity window", func(t *testing.T) {
+		t.Setenv(dev.DevModeKey, "1")
+
+		repo, state := createTestRepository(t, createTestStateWithPolicy)
+
+		gpgKeyR, err := gpg.LoadGPGKeyFromBytes(gpgPubKeyBytes)
 		if err != nil {
 			t.Fatal(err)
 		}
 
-		if err := currentAttestations.SetReferenceAuthorization(repo, env, refName, gitinterface.ZeroHash.String(), commitTreeID.String()); err != nil {
+		windowEnd := time.Date(2025, time.March, 8, 0, 0, 0, 0, time.UTC)
+		window, err := tuf.NewValidityWindow(time.Time{}, windowEnd)
+		if err != nil {
 			t.Fatal(err)
 		}
-		if err := currentAttestations.Commit(repo, "Add authorization", true, false); err != nil {
+		updateTestTargetsMetadata(t, state, TargetsRoleName, func(targetsMetadata tuf.TargetsMetadata) {
+			require.Nil(t, targetsMetadata.UpdatePrincipalValidityWindow(tufv01.NewKeyFromSSLibKey(gpgKeyR).KeyID, window))
+		})
+		if err := state.preprocess(); err != nil {
 			t.Fatal(err)
 		}
 
-		currentAttestations, err = attestations.LoadCurrentAttestations(repo)
-		if err != nil {
-			t.Fatal(err)
-		}
+		// Another entry is recorded after the principal's window ends
+		gitinterface.SetTestCommitTime(t, repo, windowEnd.Add(time.Hour))
+		commitIDs := common.AddNTestCommitsToSpecifiedRef(t, repo, "refs/heads/feature", 1, gpgKeyBytes)
+		common.CreateTestRSLReferenceEntryCommit(t, repo, rsl.NewReferenceEntry("refs/heads/feature", commitIDs[0]), gpgKeyBytes)
 
+		// The principal's entry for main claims to be recorded within the
+		// window
+		gitinterface.SetTestCommitTime(t, repo, windowEnd.Add(-time.Hour))
+		commitIDs = common.AddNTestCommitsToSpecifiedRef(t, repo, refName, 1, gpgKeyBytes)
 		entry := rsl.NewReferenceEntry(refName, commitIDs[0])
 		entryID := common.CreateTestRSLReferenceEntryCommit(t, repo, entry, gpgKeyBytes)
 		entry.ID = entryID
 
-		err = verifyEntry(testCtx, repo, state, currentAttestations, entry)
-		assert.Nil(t, err)
+		entryTime, err := repo.GetCommitTime(entryID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		require.True(t, window.IsActiveAt(entryTime))
+
+		err = verifyEntry(testCtx, repo, state, nil, entry, nil)
+		assert.ErrorIs(t, err, ErrVerificationFailed)
 	})
 
-	t.Run("successful verification with higher threshold but using GitHub approval", func(t *testing.T) {
+	t.Run("verification with key rotated out during grace period", func(t *testing.T) {
 		t.Setenv(dev.DevModeKey, "1")
 
-		repo, state := createTestRepository(t, createTestStateWithThresholdPolicyAndGitHubAppTrust)
+		gracePeriodEnd := time.Date(2025, time.March, 8, 0, 0, 0, 0, time.UTC)
+
+		// setupRepository creates a repository where the person's gpg key is
+		// rotated out with a grace period ending at gracePeriodEnd
+		setupRepository := func(t *testing.T) (*gitinterface.Repository, *State) {
+			t.Helper()
+
+			repo, state := createTestRepository(t, createTestStateWithPolicyUsingPersons)
+
+			gpgKeyR, err := gpg.LoadGPGKeyFromBytes(gpgPubKeyBytes)
+			if err != nil {
+				t.Fatal(err)
+			}
+			newKey := tufv01.NewKeyFromSSLibKey(ssh.NewKeyFromBytes(t, targets1PubKeyBytes))
+			updateTestTargetsMetadata(t, state, TargetsRoleName, func(targetsMetadata tuf.TargetsMetadata) {
+				require.Nil(t, targetsMetadata.RotatePersonKey("jane.doe@example.com", tufv01.NewKeyFromSSLibKey(gpgKeyR).KeyID, newKey, gracePeriodEnd))
+			})
+			if err := state.preprocess(); err != nil {
+				t.Fatal(err)
+			}
+
+			return repo, state
+		}
+
+		t.Run("old key trusted within grace period", func(t *testing.T) {
+			repo, state := setupRepository(t)
+
+			gitinterface.SetTestCommitTime(t, repo, gracePeriodEnd.Add(-time.Hour))
+			commitIDs := common.AddNTestCommitsToSpecifiedRef(t, repo, refName, 1, gpgKeyBytes)
+			entry := rsl.NewReferenceEntry(refName, commitIDs[0])
+			entryID := common.CreateTestRSLReferenceEntryCommit(t, repo, entry, gpgKeyBytes)
+			entry.ID = entryID
+
+			err := verifyEntry(testCtx, repo, state, nil, entry, nil)
+			assert.Nil(t, err)
+		})
+
+		t.Run("old key not trusted for entry backdated into grace period", func(t *testing.T) {
+			repo, state := setupRepository(t)
+
+			// Another entry is recorded after the grace period ends
+			gitinterface.SetTestCommitTime(t, repo, gracePeriodEnd.Add(time.Hour))
+			commitIDs := common.AddNTestCommitsToSpecifiedRef(t, repo, "refs/heads/feature", 1, gpgKeyBytes)
+			common.CreateTestRSLReferenceEntryCommit(t, repo, rsl.NewReferenceEntry("refs/heads/feature", commitIDs[0]), gpgKeyBytes)
+
+			gitinterface.SetTestCommitTime(t, repo, gracePeriodEnd.Add(-time.Hour))
+			commitIDs = common.AddNTestCommitsToSpecifiedRef(t, repo, refName, 1, gpgKeyBytes)
+			entry := rsl.NewReferenceEntry(refName, commitIDs[0])
+			entryID := common.CreateTestRSLReferenceEntryCommit(t, repo, entry, gpgKeyBytes)
+			entry.ID = entryID
+
+			err := verifyEntry(testCtx, repo, state, nil, entry, nil)
+			assert.ErrorIs(t, err, ErrVerificationFailed)
+		})
+	})
+
+	t.Run("successful verification with higher threshold using v0.1 reference authorization", func(t *testing.T) {
+		repo, state := createTestRepository(t, createTestStateWithThresholdPolicy)
 
 		currentAttestations, err := attestations.LoadCurrentAttestations(repo)
 		if err != nil {
 			t.Fatal(err)
 		}
 
-		// This is using the jane.doe signer
 		commitIDs := common.AddNTestCommitsToSpecifiedRef(t, repo, refName, 1, gpgKeyBytes)
 
 		commitTreeID, err := repo.GetCommitTreeID(commitIDs[0])
@@ -2805,17 +3123,17 @@ func TestVerifyEntry(t *testing.T) {
 			t.Fatal(err)
 		}
 
-		// Create authorization for this change using john.doe trusted as approver
-		githubAppApproval, err := attestations.NewGitHubPullRequestApprovalAttestation(refName, gitinterface.ZeroHash.String(), commitTreeID.String(), []string{"john.doe"}, nil)
+		// Create authorization for this change
+		// We're explicitly using the old type here to ensure policy
+		// verification still works
+		authorization, err := authorizationsv01.NewReferenceAuthorization(refName, gitinterface.ZeroHash.String(), commitTreeID.String())
 		if err != nil {
 			t.Fatal(err)
 		}
 
-		// This signer for the GitHub app is trusted in the root setup by the
-		// policy state creator helper
 		signer := setupSSHKeysForSigning(t, targets1KeyBytes, targets1PubKeyBytes)
 
-		env, err := dsse.CreateEnvelope(githubAppApproval)
+		env, err := dsse.CreateEnvelope(authorization)
 		if err != nil {
 			t.Fatal(err)
 		}
@@ -2824,10 +3142,10 @@ func TestVerifyEntry(t *testing.T) {
 			t.Fatal(err)
 		}
 
-		if err := currentAttestations.SetGitHubPullRequestApprovalAttestation(repo, env, "https://github.com", 1, tuf.GitHubAppRoleName, refName, gitinterface.ZeroHash.String(), commitTreeID.String()); err != nil {
+		if err := currentAttestations.SetReferenceAuthorization(repo, env, refName, gitinterface.ZeroHash.String(), commitTreeID.String()); err != nil {
 			t.Fatal(err)
 		}
-		if err := currentAttestations.Commit(repo, "Add GitHub pull request approval", true, false); err != nil {
+		if err := currentAttestations.Commit(repo, "Add authorization", true, false); err != nil {
 			t.Fatal(err)
 		}
 
@@ -2840,21 +3158,18 @@ func TestVerifyEntry(t *testing.T) {
 		entryID := common.CreateTestRSLReferenceEntryCommit(t, repo, entry, gpgKeyBytes)
 		entry.ID = entryID
 
-		err = verifyEntry(testCtx, repo, state, currentAttestations, entry)
+		err = verifyEntry(testCtx, repo, state, currentAttestations, entry, nil)
 		assert.Nil(t, err)
 	})
 
-	t.Run("unsuccessful verification with higher threshold but using GitHub approval due to invalid app key", func(t *testing.T) {
-		t.Setenv(dev.DevModeKey, "1")
-
-		repo, state := createTestRepository(t, createTestStateWithThresholdPolicyAndGitHubAppTrust)
+	t.Run("successful verification with higher threshold using latest reference authorization", func(t *testing.T) {
+		repo, state := createTestRepository(t, createTestStateWithThresholdPolicy)
 
 		currentAttestations, err := attestations.LoadCurrentAttestations(repo)
 		if err != nil {
 			t.Fatal(err)
 		}
 
-		// This is using the jane.doe signer
 		commitIDs := common.AddNTestCommitsToSpecifiedRef(t, repo, refName, 1, gpgKeyBytes)
 
 		commitTreeID, err := repo.GetCommitTreeID(commitIDs[0])
@@ -2862,17 +3177,16 @@ func TestVerifyEntry(t *testing.T) {
 			t.Fatal(err)
 		}
 
-		// Create authorization for this change using john.doe trusted as approver
-		githubAppApproval, err := attestations.NewGitHubPullRequestApprovalAttestation(refName, gitinterface.ZeroHash.String(), commitTreeID.String(), []string{"john.doe"}, nil)
+		// Create authorization for this change
+		// This uses the latest reference authorization version
+		authorization, err := attestations.NewReferenceAuthorizationForCommit(refName, gitinterface.ZeroHash.String(), commitTreeID.String())
 		if err != nil {
 			t.Fatal(err)
 		}
 
-		// This signer for the GitHub app is NOT trusted in the root setup by
-		// the policy state creator helper
-		signer := setupSSHKeysForSigning(t, targets2KeyBytes, targets2PubKeyBytes)
+		signer := setupSSHKeysForSigning(t, targets1KeyBytes, targets1PubKeyBytes)
 
-		env, err := dsse.CreateEnvelope(githubAppApproval)
+		env, err := dsse.CreateEnvelope(authorization)
 		if err != nil {
 			t.Fatal(err)
 		}
@@ -2881,10 +3195,10 @@ func TestVerifyEntry(t *testing.T) {
 			t.Fatal(err)
 		}
 
-		if err := currentAttestations.SetGitHubPullRequestApprovalAttestation(repo, env, "https://github.com", 1, tuf.GitHubAppRoleName, refName, gitinterface.ZeroHash.String(), commitTreeID.String()); err != nil {
+		if err := currentAttestations.SetReferenceAuthorization(repo, env, refName, gitinterface.ZeroHash.String(), commitTreeID.String()); err != nil {
 			t.Fatal(err)
 		}
-		if err := currentAttestations.Commit(repo, "Add GitHub pull request approval", true, false); err != nil {
+		if err := currentAttestations.Commit(repo, "Add authorization", true, false); err != nil {
 			t.Fatal(err)
 		}
 
@@ -2897,19 +3211,15 @@ func TestVerifyEntry(t *testing.T) {
 		entryID := common.CreateTestRSLReferenceEntryCommit(t, repo, entry, gpgKeyBytes)
 		entry.ID = entryID
 
-		err = verifyEntry(testCtx, repo, state, currentAttestations, entry)
-		assert.ErrorIs(t, err, ErrVerificationFailed)
+		err = verifyEntry(testCtx, repo, state, currentAttestations, entry, nil)
+		assert.Nil(t, err)
 	})
 
-	t.Run("successful verification with higher threshold but using GitHub approval and reference authorization v0.2", func(t *testing.T) {
-		repo, state := createTestRepository(t, createTestStateWithThresholdPolicyAndGitHubAppTrustForMixedAttestations)
+	t.Run("verification with team thresholds", func(t *testing.T) {
+		t.Setenv(dev.DevModeKey, "1")
 
-		currentAttestations, err := attestations.LoadCurrentAttestations(repo)
-		if err != nil {
-			t.Fatal(err)
-		}
+		repo, state := createTestRepository(t, createTestStateWithTeamPolicy)
 
-		// This is the jane.doe principal
 		commitIDs := common.AddNTestCommitsToSpecifiedRef(t, repo, refName, 1, gpgKeyBytes)
 
 		commitTreeID, err := repo.GetCommitTreeID(commitIDs[0])
@@ -2917,26 +3227,268 @@ func TestVerifyEntry(t *testing.T) {
 			t.Fatal(err)
 		}
 
-		// Approved by jill.doe
-		githubAppApproval, err := attestations.NewGitHubPullRequestApprovalAttestation(refName, gitinterface.ZeroHash.String(), commitTreeID.String(), []string{"jill.doe"}, nil)
+		authorization, err := attestations.NewReferenceAuthorizationForCommit(refName, gitinterface.ZeroHash.String(), commitTreeID.String())
 		if err != nil {
 			t.Fatal(err)
 		}
 
-		// This signer for the GitHub app is trusted in the root setup by the
-		// policy state creator helper
-		signer := setupSSHKeysForSigning(t, targets1KeyBytes, targets1PubKeyBytes)
-
-		env, err := dsse.CreateEnvelope(githubAppApproval)
+		env, err := dsse.CreateEnvelope(authorization)
 		if err != nil {
 			t.Fatal(err)
 		}
-		env, err = dsse.SignEnvelope(testCtx, env, signer)
+
+		// Second member of dev signs, meeting the dev team's threshold of 2
+		// with the RSL entry signature
+		env, err = dsse.SignEnvelope(testCtx, env, setupSSHKeysForSigning(t, targets1KeyBytes, targets1PubKeyBytes))
 		if err != nil {
 			t.Fatal(err)
 		}
 
-		if err := currentAttestations.SetGitHubPullRequestApprovalAttestation(repo, env, "https://github.com", 1, tuf.GitHubAppRoleName, refName, gitinterface.ZeroHash.String(), commitTreeID.String()); err != nil {
+		currentAttestations, err := attestations.LoadCurrentAttestations(repo)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := currentAttestations.SetReferenceAuthorization(repo, env, refName, gitinterface.ZeroHash.String(), commitTreeID.String()); err != nil {
+			t.Fatal(err)
+		}
+		if err := currentAttestations.Commit(repo, "Add authorization", true, false); err != nil {
+			t.Fatal(err)
+		}
+		currentAttestations, err = attestations.LoadCurrentAttestations(repo)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		entry := rsl.NewReferenceEntry(refName, commitIDs[0])
+		entryID := common.CreateTestRSLReferenceEntryCommit(t, repo, entry, gpgKeyBytes)
+		entry.ID = entryID
+
+		// The security team has not approved
+		err = verifyEntry(testCtx, repo, state, currentAttestations, entry, nil)
+		assert.ErrorIs(t, err, ErrVerificationFailed)
+
+		// Security team approves
+		env, err = dsse.SignEnvelope(testCtx, env, setupSSHKeysForSigning(t, targets2KeyBytes, targets2PubKeyBytes))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := currentAttestations.SetReferenceAuthorization(repo, env, refName, gitinterface.ZeroHash.String(), commitTreeID.String()); err != nil {
+			t.Fatal(err)
+		}
+		if err := currentAttestations.Commit(repo, "Add authorization", true, false); err != nil {
+			t.Fatal(err)
+		}
+		currentAttestations, err = attestations.LoadCurrentAttestations(repo)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		err = verifyEntry(testCtx, repo, state, currentAttestations, entry, nil)
+		assert.Nil(t, err)
+	})
+
+	t.Run("verification with team thresholds, team threshold unmet", func(t *testing.T) {
+		t.Setenv(dev.DevModeKey, "1")
+
+		repo, state := createTestRepository(t, createTestStateWithTeamPolicy)
+
+		commitIDs := common.AddNTestCommitsToSpecifiedRef(t, repo, refName, 1, gpgKeyBytes)
+
+		commitTreeID, err := repo.GetCommitTreeID(commitIDs[0])
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		authorization, err := attestations.NewReferenceAuthorizationForCommit(refName, gitinterface.ZeroHash.String(), commitTreeID.String())
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		env, err := dsse.CreateEnvelope(authorization)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		// Only the security team approves in addition to one member of dev
+		// who signs the RSL entry
+		env, err = dsse.SignEnvelope(testCtx, env, setupSSHKeysForSigning(t, targets2KeyBytes, targets2PubKeyBytes))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		currentAttestations, err := attestations.LoadCurrentAttestations(repo)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := currentAttestations.SetReferenceAuthorization(repo, env, refName, gitinterface.ZeroHash.String(), commitTreeID.String()); err != nil {
+			t.Fatal(err)
+		}
+		if err := currentAttestations.Commit(repo, "Add authorization", true, false); err != nil {
+			t.Fatal(err)
+		}
+		currentAttestations, err = attestations.LoadCurrentAttestations(repo)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		entry := rsl.NewReferenceEntry(refName, commitIDs[0])
+		entryID := common.CreateTestRSLReferenceEntryCommit(t, repo, entry, gpgKeyBytes)
+		entry.ID = entryID
+
+		err = verifyEntry(testCtx, repo, state, currentAttestations, entry, nil)
+		assert.ErrorIs(t, err, ErrVerificationFailed)
+	})
+
+	t.Run("successful verification with higher threshold but using GitHub approval", func(t *testing.T) {
+		t.Setenv(dev.DevModeKey, "1")
+
+		repo, state := createTestRepository(t, createTestStateWithThresholdPolicyAndGitHubAppTrust)
+
+		currentAttestations, err := attestations.LoadCurrentAttestations(repo)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		// This is using the jane.doe signer
+		commitIDs := common.AddNTestCommitsToSpecifiedRef(t, repo, refName, 1, gpgKeyBytes)
+
+		commitTreeID, err := repo.GetCommitTreeID(commitIDs[0])
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		// Create authorization for this change using john.doe trusted as approver
+		githubAppApproval, err := attestations.NewGitHubPullRequestApprovalAttestation(refName, gitinterface.ZeroHash.String(), commitTreeID.String(), []string{"john.doe"}, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		// This signer for the GitHub app is trusted in the root setup by the
+		// policy state creator helper
+		signer := setupSSHKeysForSigning(t, targets1KeyBytes, targets1PubKeyBytes)
+
+		env, err := dsse.CreateEnvelope(githubAppApproval)
+		if err != nil {
+			t.Fatal(err)
+		}
+		env, err = dsse.SignEnvelope(testCtx, env, signer)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if err := currentAttestations.SetGitHubPullRequestApprovalAttestation(repo, env, "https://github.com", 1, tuf.GitHubAppRoleName, refName, gitinterface.ZeroHash.String(), commitTreeID.String()); err != nil {
+			t.Fatal(err)
+		}
+		if err := currentAttestations.Commit(repo, "Add GitHub pull request approval", true, false); err != nil {
+			t.Fatal(err)
+		}
+
+		currentAttestations, err = attestations.LoadCurrentAttestations(repo)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		entry := rsl.NewReferenceEntry(refName, commitIDs[0])
+		entryID := common.CreateTestRSLReferenceEntryCommit(t, repo, entry, gpgKeyBytes)
+		entry.ID = entryID
+
+		err = verifyEntry(testCtx, repo, state, currentAttestations, entry, nil)
+		assert.Nil(t, err)
+	})
+
+	t.Run("unsuccessful verification with higher threshold but using GitHub approval due to invalid app key", func(t *testing.T) {
+		t.Setenv(dev.DevModeKey, "1")
+
+		repo, state := createTestRepository(t, createTestStateWithThresholdPolicyAndGitHubAppTrust)
+
+		currentAttestations, err := attestations.LoadCurrentAttestations(repo)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		// This is using the jane.doe signer
+		commitIDs := common.AddNTestCommitsToSpecifiedRef(t, repo, refName, 1, gpgKeyBytes)
+
+		commitTreeID, err := repo.GetCommitTreeID(commitIDs[0])
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		// Create authorization for this change using john.doe trusted as approver
+		githubAppApproval, err := attestations.NewGitHubPullRequestApprovalAttestation(refName, gitinterface.ZeroHash.String(), commitTreeID.String(), []string{"john.doe"}, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		// This signer for the GitHub app is NOT trusted in the root setup by
+		// the policy state creator helper
+		signer := setupSSHKeysForSigning(t, targets2KeyBytes, targets2PubKeyBytes)
+
+		env, err := dsse.CreateEnvelope(githubAppApproval)
+		if err != nil {
+			t.Fatal(err)
+		}
+		env, err = dsse.SignEnvelope(testCtx, env, signer)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if err := currentAttestations.SetGitHubPullRequestApprovalAttestation(repo, env, "https://github.com", 1, tuf.GitHubAppRoleName, refName, gitinterface.ZeroHash.String(), commitTreeID.String()); err != nil {
+			t.Fatal(err)
+		}
+		if err := currentAttestations.Commit(repo, "Add GitHub pull request approval", true, false); err != nil {
+			t.Fatal(err)
+		}
+
+		currentAttestations, err = attestations.LoadCurrentAttestations(repo)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		entry := rsl.NewReferenceEntry(refName, commitIDs[0])
+		entryID := common.CreateTestRSLReferenceEntryCommit(t, repo, entry, gpgKeyBytes)
+		entry.ID = entryID
+
+		err = verifyEntry(testCtx, repo, state, currentAttestations, entry, nil)
+		assert.ErrorIs(t, err, ErrVerificationFailed)
+	})
+
+	t.Run("successful verification with higher threshold but using GitHub approval and reference authorization v0.2", func(t *testing.T) {
+		repo, state := createTestRepository(t, createTestStateWithThresholdPolicyAndGitHubAppTrustForMixedAttestations)
+
+		currentAttestations, err := attestations.LoadCurrentAttestations(repo)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		// This is the jane.doe principal
+		commitIDs := common.AddNTestCommitsToSpecifiedRef(t, repo, refName, 1, gpgKeyBytes)
+
+		commitTreeID, err := repo.GetCommitTreeID(commitIDs[0])
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		// Approved by jill.doe
+		githubAppApproval, err := attestations.NewGitHubPullRequestApprovalAttestation(refName, gitinterface.ZeroHash.String(), commitTreeID.String(), []string{"jill.doe"}, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		// This signer for the GitHub app is trusted in the root setup by the
+		// policy state creator helper
+		signer := setupSSHKeysForSigning(t, targets1KeyBytes, targets1PubKeyBytes)
+
+		env, err := dsse.CreateEnvelope(githubAppApproval)
+		if err != nil {
+			t.Fatal(err)
+		}
+		env, err = dsse.SignEnvelope(testCtx, env, signer)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if err := currentAttestations.SetGitHubPullRequestApprovalAttestation(repo, env, "https://github.com", 1, tuf.GitHubAppRoleName, refName, gitinterface.ZeroHash.String(), commitTreeID.String()); err != nil {
 			t.Fatal(err)
 		}
 		if err := currentAttestations.Commit(repo, "Add GitHub pull request approval", true, false); err != nil {
@@ -2976,7 +3528,7 @@ func TestVerifyEntry(t *testing.T) {
 		entryID := common.CreateTestRSLReferenceEntryCommit(t, repo, entry, gpgKeyBytes)
 		entry.ID = entryID
 
-		err = verifyEntry(testCtx, repo, state, currentAttestations, entry)
+		err = verifyEntry(testCtx, repo, state, currentAttestations, entry, nil)
 		assert.Nil(t, err)
 	})
 
@@ -3055,7 +3607,7 @@ func TestVerifyEntry(t *testing.T) {
 		entryID := common.CreateTestRSLReferenceEntryCommit(t, repo, entry, gpgKeyBytes)
 		entry.ID = entryID
 
-		err = verifyEntry(testCtx, repo, state, currentAttestations, entry)
+		err = verifyEntry(testCtx, repo, state, currentAttestations, entry, nil)
 		assert.ErrorIs(t, err, ErrVerificationFailed)
 	})
 
@@ -3109,7 +3661,7 @@ func TestVerifyEntry(t *testing.T) {
 		entryID := common.CreateTestRSLReferenceEntryCommit(t, repo, entry, gpgKeyBytes)
 		entry.ID = entryID
 
-		err = verifyEntry(testCtx, repo, state, currentAttestations, entry)
+		err = verifyEntry(testCtx, repo, state, currentAttestations, entry, nil)
 		assert.ErrorIs(t, err, ErrVerificationFailed)
 	})
 
@@ -3195,7 +3747,7 @@ func TestVerifyEntry(t *testing.T) {
 		// We have an RSL signature from jane.doe, a GitHub approval from
 		// john.doe and a reference authorization from john.doe
 		// Insufficient to meet threshold 3
-		err = verifyEntry(testCtx, repo, state, currentAttestations, entry)
+		err = verifyEntry(testCtx, repo, state, currentAttestations, entry, nil)
 		assert.ErrorIs(t, err, ErrVerificationFailed)
 	})
 
@@ -3248,7 +3800,7 @@ func TestVerifyEntry(t *testing.T) {
 		entryID := common.CreateTestRSLReferenceEntryCommit(t, repo, entry, gpgKeyBytes)
 		entry.ID = entryID
 
-		err = verifyEntry(testCtx, repo, state, currentAttestations, entry)
+		err = verifyEntry(testCtx, repo, state, currentAttestations, entry, nil)
 		assert.Nil(t, err)
 	})
 
@@ -3301,7 +3853,7 @@ func TestVerifyEntry(t *testing.T) {
 		entryID := common.CreateTestRSLReferenceEntryCommit(t, repo, entry, gpgKeyBytes)
 		entry.ID = entryID
 
-		err = verifyEntry(testCtx, repo, state, currentAttestations, entry)
+		err = verifyEntry(testCtx, repo, state, currentAttestations, entry, nil)
 		assert.ErrorIs(t, err, ErrVerificationFailed)
 	})
 
@@ -3320,7 +3872,7 @@ func TestVerifyEntry(t *testing.T) {
 		}
 
 		// Only one entry, this is fine
-		err = verifyEntry(testCtx, repo, state, currentAttestations, entry)
+		err = verifyEntry(testCtx, repo, state, currentAttestations, entry, nil)
 		assert.Nil(t, err)
 
 		// Add more entries
@@ -3330,7 +3882,7 @@ func TestVerifyEntry(t *testing.T) {
 		entry.ID = entryID
 
 		// Still fine
-		err = verifyEntry(testCtx, repo, state, currentAttestations, entry)
+		err = verifyEntry(testCtx, repo, state, currentAttestations, entry, nil)
 		assert.Nil(t, err)
 
 		// Rewrite history altogether
@@ -3347,10 +3899,249 @@ func TestVerifyEntry(t *testing.T) {
 		entry.ID = entryID
 
 		// Not fine
-		err = verifyEntry(testCtx, repo, state, currentAttestations, entry)
+		err = verifyEntry(testCtx, repo, state, currentAttestations, entry, nil)
+		assert.ErrorIs(t, err, ErrVerificationFailed)
+	})
+
+	t.Run("verify require signed commits rule", func(t *testing.T) {
+		t.Setenv(dev.DevModeKey, "1")
+
+		repo, state := createTestRepository(t, createTestStateWithGlobalConstraintRequireSignedCommits)
+
+		// Commits signed by a principal in the policy
+		commitIDs := common.AddNTestCommitsToSpecifiedRef(t, repo, refName, 2, gpgKeyBytes)
+		entry := rsl.NewReferenceEntry(refName, commitIDs[1])
+		entryID := common.CreateTestRSLReferenceEntryCommit(t, repo, entry, gpgKeyBytes)
+		entry.ID = entryID
+
+		err := verifyEntry(testCtx, repo, state, nil, entry, nil)
+		assert.Nil(t, err)
+
+		// Commit signed by a key not in the policy
+		commitIDs = common.AddNTestCommitsToSpecifiedRef(t, repo, refName, 1, targets1KeyBytes)
+		entry = rsl.NewReferenceEntry(refName, commitIDs[0])
+		entryID = common.CreateTestRSLReferenceEntryCommit(t, repo, entry, gpgKeyBytes)
+		entry.ID = entryID
+
+		err = verifyEntry(testCtx, repo, state, nil, entry, nil)
+		assert.ErrorIs(t, err, ErrVerificationFailed)
+
+		// The rule doesn't apply to other refs
+		otherRefName := "refs/heads/feature"
+		commitIDs = common.AddNTestCommitsToSpecifiedRef(t, repo, otherRefName, 1, targets1KeyBytes)
+		entry = rsl.NewReferenceEntry(otherRefName, commitIDs[0])
+		entryID = common.CreateTestRSLReferenceEntryCommit(t, repo, entry, gpgKeyBytes)
+		entry.ID = entryID
+
+		err = verifyEntry(testCtx, repo, state, nil, entry, nil)
+		assert.Nil(t, err)
+	})
+
+	t.Run("verify require hook execution rule", func(t *testing.T) {
+		t.Setenv(dev.DevModeKey, "1")
+
+		// The hook's blob must exist in the repository for the policy to be
+		// committed
+		repo := gitinterface.CreateTestGitRepository(t, t.TempDir(), false)
+		hookBlobID, err := repo.WriteBlob([]byte("return 0"))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		state := createTestStateWithGlobalConstraintRequireHookExecution(t, hookBlobID)
+		state.repository = repo
+		if err := state.Commit(repo, "Create test state", true, false); err != nil {
+			t.Fatal(err)
+		}
+		if err := Apply(testCtx, repo, false); err != nil {
+			t.Fatal(err)
+		}
+
+		commitIDs := common.AddNTestCommitsToSpecifiedRef(t, repo, refName, 1, gpgKeyBytes)
+		entry := rsl.NewReferenceEntry(refName, commitIDs[0])
+		entryID := common.CreateTestRSLReferenceEntryCommit(t, repo, entry, gpgKeyBytes)
+		entry.ID = entryID
+
+		createHookExecutionAttestations := func(t *testing.T, fromID, toID gitinterface.Hash, exitCode int, keyBytes, pubKeyBytes []byte) *attestations.Attestations {
+			t.Helper()
+
+			statement, err := attestations.NewHookExecutionAttestation(refName, fromID.String(), toID.String(), "lint", exitCode)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			env, err := dsse.CreateEnvelope(statement)
+			if err != nil {
+				t.Fatal(err)
+			}
+			env, err = dsse.SignEnvelope(testCtx, env, setupSSHKeysForSigning(t, keyBytes, pubKeyBytes))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			currentAttestations := &attestations.Attestations{}
+			if err := currentAttestations.SetHookExecutionAttestation(repo, env, refName, fromID.String(), toID.String(), "lint"); err != nil {
+				t.Fatal(err)
+			}
+
+			return currentAttestations
+		}
+
+		// No attestation for the hook
+		err = verifyEntry(testCtx, repo, state, nil, entry, nil)
+		assert.ErrorIs(t, err, ErrVerificationFailed)
+
+		// Hook failed
+		currentAttestations := createHookExecutionAttestations(t, gitinterface.ZeroHash, commitIDs[0], 1, rootKeyBytes, rootPubKeyBytes)
+		err = verifyEntry(testCtx, repo, state, currentAttestations, entry, nil)
+		assert.ErrorIs(t, err, ErrVerificationFailed)
+
+		// Attestation signed by a principal the hook isn't run by
+		currentAttestations = createHookExecutionAttestations(t, gitinterface.ZeroHash, commitIDs[0], 0, targets1KeyBytes, targets1PubKeyBytes)
+		err = verifyEntry(testCtx, repo, state, currentAttestations, entry, nil)
+		assert.ErrorIs(t, err, ErrVerificationFailed)
+
+		// Hook passed and attestation signed by the hook's principal
+		currentAttestations = createHookExecutionAttestations(t, gitinterface.ZeroHash, commitIDs[0], 0, rootKeyBytes, rootPubKeyBytes)
+		err = verifyEntry(testCtx, repo, state, currentAttestations, entry, nil)
+		assert.Nil(t, err)
+
+		// A skipped entry is not the start of the next update's range
+		validCommitID := commitIDs[0]
+		commitIDs = common.AddNTestCommitsToSpecifiedRef(t, repo, refName, 1, gpgKeyBytes)
+		skippedEntry := rsl.NewReferenceEntry(refName, commitIDs[0])
+		skippedEntry.ID = common.CreateTestRSLReferenceEntryCommit(t, repo, skippedEntry, gpgKeyBytes)
+		annotation := rsl.NewAnnotationEntry([]gitinterface.Hash{skippedEntry.ID}, true, "revoke")
+		common.CreateTestRSLAnnotationEntryCommit(t, repo, annotation, gpgKeyBytes)
+
+		if err := repo.SetReference(refName, validCommitID); err != nil {
+			t.Fatal(err)
+		}
+		commitIDs = common.AddNTestCommitsToSpecifiedRef(t, repo, refName, 1, gpgKeyBytes)
+		entry = rsl.NewReferenceEntry(refName, commitIDs[0])
+		entry.ID = common.CreateTestRSLReferenceEntryCommit(t, repo, entry, gpgKeyBytes)
+
+		currentAttestations = createHookExecutionAttestations(t, skippedEntry.TargetID, commitIDs[0], 0, rootKeyBytes, rootPubKeyBytes)
+		err = verifyEntry(testCtx, repo, state, currentAttestations, entry, nil)
+		assert.ErrorIs(t, err, ErrVerificationFailed)
+
+		currentAttestations = createHookExecutionAttestations(t, validCommitID, commitIDs[0], 0, rootKeyBytes, rootPubKeyBytes)
+		err = verifyEntry(testCtx, repo, state, currentAttestations, entry, nil)
+		assert.Nil(t, err)
+
+		// The rule doesn't apply to other refs
+		otherRefName := "refs/heads/feature"
+		commitIDs = common.AddNTestCommitsToSpecifiedRef(t, repo, otherRefName, 1, gpgKeyBytes)
+		entry = rsl.NewReferenceEntry(otherRefName, commitIDs[0])
+		entryID = common.CreateTestRSLReferenceEntryCommit(t, repo, entry, gpgKeyBytes)
+		entry.ID = entryID
+
+		err = verifyEntry(testCtx, repo, state, nil, entry, nil)
+		assert.Nil(t, err)
+	})
+
+	t.Run("verify merge strategy rule requiring linear history", func(t *testing.T) {
+		t.Setenv(dev.DevModeKey, "1")
+
+		repo, state := createTestRepository(t, func(t *testing.T) *State {
+			t.Helper()
+			return createTestStateWithGlobalConstraintMergeStrategy(t, tuf.MergeStrategyLinearHistory)
+		})
+
+		commitIDs := common.AddNTestCommitsToSpecifiedRef(t, repo, refName, 2, gpgKeyBytes)
+		entry := rsl.NewReferenceEntry(refName, commitIDs[1])
+		entryID := common.CreateTestRSLReferenceEntryCommit(t, repo, entry, gpgKeyBytes)
+		entry.ID = entryID
+
+		err := verifyEntry(testCtx, repo, state, nil, entry, nil)
+		assert.Nil(t, err)
+
+		otherCommitIDs := common.AddNTestCommitsToSpecifiedRef(t, repo, "refs/heads/other", 1, gpgKeyBytes)
+		mergeCommitID := common.AddTestMergeCommitToSpecifiedRef(t, repo, refName, otherCommitIDs[0])
+		entry = rsl.NewReferenceEntry(refName, mergeCommitID)
+		entryID = common.CreateTestRSLReferenceEntryCommit(t, repo, entry, gpgKeyBytes)
+		entry.ID = entryID
+
+		err = verifyEntry(testCtx, repo, state, nil, entry, nil)
 		assert.ErrorIs(t, err, ErrVerificationFailed)
 	})
 
+	t.Run("verify merge strategy rule requiring merge commits", func(t *testing.T) {
+		t.Setenv(dev.DevModeKey, "1")
+
+		repo, state := createTestRepository(t, func(t *testing.T) *State {
+			t.Helper()
+			return createTestStateWithGlobalConstraintMergeStrategy(t, tuf.MergeStrategyMergeCommit)
+		})
+
+		// The first entry for the ref creates it
+		commitIDs := common.AddNTestCommitsToSpecifiedRef(t, repo, refName, 1, gpgKeyBytes)
+		entry := rsl.NewReferenceEntry(refName, commitIDs[0])
+		entryID := common.CreateTestRSLReferenceEntryCommit(t, repo, entry, gpgKeyBytes)
+		entry.ID = entryID
+
+		err := verifyEntry(testCtx, repo, state, nil, entry, nil)
+		assert.Nil(t, err)
+
+		otherCommitIDs := common.AddNTestCommitsToSpecifiedRef(t, repo, "refs/heads/other", 1, gpgKeyBytes)
+		mergeCommitID := common.AddTestMergeCommitToSpecifiedRef(t, repo, refName, otherCommitIDs[0])
+		entry = rsl.NewReferenceEntry(refName, mergeCommitID)
+		entryID = common.CreateTestRSLReferenceEntryCommit(t, repo, entry, gpgKeyBytes)
+		entry.ID = entryID
+
+		err = verifyEntry(testCtx, repo, state, nil, entry, nil)
+		assert.Nil(t, err)
+
+		// Commits added directly to the ref are not merge commits
+		commitIDs = common.AddNTestCommitsToSpecifiedRef(t, repo, refName, 1, gpgKeyBytes)
+		entry = rsl.NewReferenceEntry(refName, commitIDs[0])
+		entryID = common.CreateTestRSLReferenceEntryCommit(t, repo, entry, gpgKeyBytes)
+		entry.ID = entryID
+
+		err = verifyEntry(testCtx, repo, state, nil, entry, nil)
+		assert.ErrorIs(t, err, ErrVerificationFailed)
+	})
+
+	t.Run("verify rules limited to reference actions", func(t *testing.T) {
+		t.Setenv(dev.DevModeKey, "1")
+
+		repo, state := createTestRepository(t, createTestStateWithRefActionPolicy)
+
+		// Creating the branch uses the rule that doesn't apply to deletions
+		commitIDs := common.AddNTestCommitsToSpecifiedRef(t, repo, refName, 1, gpgKeyBytes)
+		entry := rsl.NewReferenceEntry(refName, commitIDs[0])
+		entryID := common.CreateTestRSLReferenceEntryCommit(t, repo, entry, gpgKeyBytes)
+		entry.ID = entryID
+
+		err := verifyEntry(testCtx, repo, state, nil, entry, nil)
+		assert.Nil(t, err)
+
+		// The deletion rule doesn't apply to fast-forwards, so the root key
+		// is not trusted for them
+		commitIDs = common.AddNTestCommitsToSpecifiedRef(t, repo, refName, 1, gpgKeyBytes)
+		entry = rsl.NewReferenceEntry(refName, commitIDs[0])
+		entryID = common.CreateTestRSLReferenceEntryCommit(t, repo, entry, rootKeyBytes)
+		entry.ID = entryID
+
+		err = verifyEntry(testCtx, repo, state, nil, entry, nil)
+		assert.ErrorIs(t, err, ErrVerificationFailed)
+
+		// Deleting the branch requires the root key
+		entry = rsl.NewReferenceEntry(refName, gitinterface.ZeroHash)
+		entryID = common.CreateTestRSLReferenceEntryCommit(t, repo, entry, gpgKeyBytes)
+		entry.ID = entryID
+
+		err = verifyEntry(testCtx, repo, state, nil, entry, nil)
+		assert.ErrorIs(t, err, ErrVerificationFailed)
+
+		entry = rsl.NewReferenceEntry(refName, gitinterface.ZeroHash)
+		entryID = common.CreateTestRSLReferenceEntryCommit(t, repo, entry, rootKeyBytes)
+		entry.ID = entryID
+
+		err = verifyEntry(testCtx, repo, state, nil, entry, nil)
+		assert.Nil(t, err)
+	})
+
 	t.Run("verify block force pushes rule for unprotected ref", func(t *testing.T) {
 		refName := "refs/heads/feature"
 		repo, state := createTestRepository(t, createTestStateWithGlobalConstraintBlockForcePushes)
@@ -3367,7 +4158,7 @@ func TestVerifyEntry(t *testing.T) {
 		}
 
 		// Only one entry, this is fine
-		err = verifyEntry(testCtx, repo, state, currentAttestations, entry)
+		err = verifyEntry(testCtx, repo, state, currentAttestations, entry, nil)
 		assert.Nil(t, err)
 
 		// Add more entries
@@ -3377,7 +4168,7 @@ func TestVerifyEntry(t *testing.T) {
 		entry.ID = entryID
 
 		// Still fine
-		err = verifyEntry(testCtx, repo, state, currentAttestations, entry)
+		err = verifyEntry(testCtx, repo, state, currentAttestations, entry, nil)
 		assert.Nil(t, err)
 
 		// Rewrite history altogether
@@ -3394,7 +4185,7 @@ func TestVerifyEntry(t *testing.T) {
 		entry.ID = entryID
 
 		// Still fine; this ref is not protected
-		err = verifyEntry(testCtx, repo, state, currentAttestations, entry)
+		err = verifyEntry(testCtx, repo, state, currentAttestations, entry, nil)
 		assert.Nil(t, err)
 	})
 
@@ -3495,7 +4286,7 @@ func TestVerifyEntry(t *testing.T) {
 		entry.ID = entryID
 
 		// We meet the threshold of with the reference authorization, so this should be successful
-		err = verifyEntry(testCtx, networkRepository, networkState, currentAttestations, entry)
+		err = verifyEntry(testCtx, networkRepository, networkState, currentAttestations, entry, nil)
 		assert.Nil(t, err)
 
 		// Make another change without reference authorization
@@ -3504,7 +4295,7 @@ func TestVerifyEntry(t *testing.T) {
 		entryID = common.CreateTestRSLReferenceEntryCommit(t, networkRepository, entry, gpgKeyBytes)
 		entry.ID = entryID
 
-		err = verifyEntry(testCtx, networkRepository, networkState, currentAttestations, entry)
+		err = verifyEntry(testCtx, networkRepository, networkState, currentAttestations, entry, nil)
 		assert.ErrorIs(t, err, ErrVerificationFailed)
 	})
 }
@@ -3526,7 +4317,7 @@ func TestVerifyTagEntry(t *testing.T) {
 		entryID = common.CreateTestRSLReferenceEntryCommit(t, repo, entry, gpgKeyBytes)
 		entry.ID = entryID
 
-		err := verifyTagEntry(testCtx, repo, policy, nil, entry)
+		err := verifyTagEntry(testCtx, repo, policy, nil, entry, tuf.RefActionCreate, nil)
 		assert.Nil(t, err)
 	})
 
@@ -3546,7 +4337,7 @@ func TestVerifyTagEntry(t *testing.T) {
 		entryID = common.CreateTestRSLReferenceEntryCommit(t, repo, entry, gpgKeyBytes)
 		entry.ID = entryID
 
-		err := verifyTagEntry(testCtx, repo, policy, nil, entry)
+		err := verifyTagEntry(testCtx, repo, policy, nil, entry, tuf.RefActionCreate, nil)
 		assert.Nil(t, err)
 	})
 
@@ -3605,7 +4396,7 @@ func TestVerifyTagEntry(t *testing.T) {
 		entryID = common.CreateTestRSLReferenceEntryCommit(t, repo, entry, gpgKeyBytes)
 		entry.ID = entryID
 
-		err = verifyTagEntry(testCtx, repo, policy, currentAttestations, entry)
+		err = verifyTagEntry(testCtx, repo, policy, currentAttestations, entry, tuf.RefActionCreate, nil)
 		assert.Nil(t, err)
 	})
 
@@ -3625,7 +4416,7 @@ func TestVerifyTagEntry(t *testing.T) {
 		entryID = common.CreateTestRSLReferenceEntryCommit(t, repo, entry, gpgKeyBytes)
 		entry.ID = entryID
 
-		err := verifyTagEntry(testCtx, repo, policy, nil, entry)
+		err := verifyTagEntry(testCtx, repo, policy, nil, entry, tuf.RefActionCreate, nil)
 		assert.ErrorIs(t, err, ErrVerificationFailed)
 	})
 
@@ -3685,11 +4476,78 @@ func TestVerifyTagEntry(t *testing.T) {
 		entryID = common.CreateTestRSLReferenceEntryCommit(t, repo, entry, gpgKeyBytes)
 		entry.ID = entryID
 
-		err = verifyTagEntry(testCtx, repo, policy, currentAttestations, entry)
+		err = verifyTagEntry(testCtx, repo, policy, currentAttestations, entry, tuf.RefActionCreate, nil)
 		assert.ErrorIs(t, err, ErrVerificationFailed)
 	})
 }
 
+func TestGetRefAction(t *testing.T) {
+	t.Setenv(dev.DevModeKey, "1")
+
+	refName := "refs/heads/main"
+
+	repo, state := createTestRepository(t, createTestStateWithAnnotationPolicy)
+
+	commitIDs := common.AddNTestCommitsToSpecifiedRef(t, repo, refName, 2, gpgKeyBytes)
+
+	entry := rsl.NewReferenceEntry(refName, commitIDs[0])
+	entry.ID = common.CreateTestRSLReferenceEntryCommit(t, repo, entry, gpgKeyBytes)
+
+	action, err := getRefAction(testCtx, repo, state, entry)
+	assert.Nil(t, err)
+	assert.Equal(t, tuf.RefActionCreate, action)
+
+	entry = rsl.NewReferenceEntry(refName, commitIDs[1])
+	entry.ID = common.CreateTestRSLReferenceEntryCommit(t, repo, entry, gpgKeyBytes)
+
+	action, err = getRefAction(testCtx, repo, state, entry)
+	assert.Nil(t, err)
+	assert.Equal(t, tuf.RefActionFastForward, action)
+
+	entry = rsl.NewReferenceEntry(refName, commitIDs[0])
+	entry.ID = common.CreateTestRSLReferenceEntryCommit(t, repo, entry, gpgKeyBytes)
+
+	action, err = getRefAction(testCtx, repo, state, entry)
+	assert.Nil(t, err)
+	assert.Equal(t, tuf.RefActionNonFastForward, action)
+
+	entry = rsl.NewReferenceEntry(refName, gitinterface.ZeroHash)
+	entry.ID = common.CreateTestRSLReferenceEntryCommit(t, repo, entry, gpgKeyBytes)
+
+	action, err = getRefAction(testCtx, repo, state, entry)
+	assert.Nil(t, err)
+	assert.Equal(t, tuf.RefActionDelete, action)
+
+	// Recreating a deleted reference is a creation
+	entry = rsl.NewReferenceEntry(refName, commitIDs[1])
+	entry.ID = common.CreateTestRSLReferenceEntryCommit(t, repo, entry, gpgKeyBytes)
+
+	action, err = getRefAction(testCtx, repo, state, entry)
+	assert.Nil(t, err)
+	assert.Equal(t, tuf.RefActionCreate, action)
+
+	// A skip annotation for the tip that isn't authorized by the policy is
+	// ignored when identifying the prior entry
+	skippedEntryID := entry.ID
+	annotation := rsl.NewAnnotationEntry([]gitinterface.Hash{skippedEntryID}, true, "skip")
+	annotation.ID = common.CreateTestRSLAnnotationEntryCommit(t, repo, annotation, gpgKeyBytes)
+
+	entry = rsl.NewReferenceEntry(refName, commitIDs[0])
+	entry.ID = common.CreateTestRSLReferenceEntryCommit(t, repo, entry, gpgKeyBytes)
+
+	action, err = getRefAction(testCtx, repo, state, entry)
+	assert.Nil(t, err)
+	assert.Equal(t, tuf.RefActionNonFastForward, action)
+
+	// Once the skip is authorized, the entry before the skipped tip is used
+	annotation = rsl.NewAnnotationEntry([]gitinterface.Hash{skippedEntryID}, true, "skip")
+	annotation.ID = common.CreateTestRSLAnnotationEntryCommit(t, repo, annotation, rootKeyBytes)
+
+	action, err = getRefAction(testCtx, repo, state, entry)
+	assert.Nil(t, err)
+	assert.Equal(t, tuf.RefActionCreate, action)
+}
+
 func TestGetCommits(t *testing.T) {
 	repo, _ := createTestRepository(t, createTestStateWithPolicy)
 
@@ -3762,3 +4620,46 @@ func TestStateVerifyNewState(t *testing.T) {
 		assert.ErrorIs(t, err, ErrVerifierConditionsUnmet)
 	})
 }
+
+func TestStateVerifyNewStateRollback(t *testing.T) {
+	t.Setenv(dev.DevModeKey, "1")
+
+	t.Run("root rolled back", func(t *testing.T) {
+		currentPolicy := createTestStateWithOnlyRoot(t)
+		newPolicy := createTestStateWithOnlyRoot(t)
+
+		signer := setupSSHKeysForSigning(t, rootKeyBytes, rootPubKeyBytes)
+		rootMetadata, err := currentPolicy.GetRootMetadata(false)
+		require.Nil(t, err)
+		rootMetadata.IncrementMetadataVersion()
+		rootEnv, err := dsse.CreateEnvelope(rootMetadata)
+		require.Nil(t, err)
+		rootEnv, err = dsse.SignEnvelope(testCtx, rootEnv, signer)
+		require.Nil(t, err)
+		currentPolicy.Metadata.RootEnvelope = rootEnv
+		require.Nil(t, currentPolicy.preprocess())
+		require.Nil(t, newPolicy.preprocess())
+
+		err = currentPolicy.VerifyNewState(testCtx, newPolicy)
+		assert.ErrorIs(t, err, ErrMetadataRollback)
+		assert.ErrorContains(t, err, "'root' is at version 1, current version is 2")
+
+		// Moving forward is allowed
+		err = newPolicy.VerifyNewState(testCtx, currentPolicy)
+		assert.Nil(t, err)
+	})
+
+	t.Run("rule file rolled back", func(t *testing.T) {
+		currentPolicy := createTestStateWithDelegatedPolicies(t)
+		newPolicy := createTestStateWithDelegatedPolicies(t)
+
+		updateTestTargetsMetadata(t, currentPolicy, "1", func(targetsMetadata tuf.TargetsMetadata) {
+			targetsMetadata.IncrementMetadataVersion()
+		})
+		require.Nil(t, currentPolicy.preprocess())
+
+		err := currentPolicy.VerifyNewState(testCtx, newPolicy)
+		assert.ErrorIs(t, err, ErrMetadataRollback)
+		assert.ErrorContains(t, err, "'1' is at version 1, current version is 2")
+	})
+}
@@ -5,16 +5,19 @@ package rsl
 
 import (
 	"sync"
+	"time"
 
 	"github.com/gittuf/gittuf/internal/gitinterface"
 )
 
 type rslCache struct {
-	entryCache  map[string]Entry
-	parentCache map[string]string
+	entryCache        map[string]Entry
+	parentCache       map[string]string
+	recordedTimeCache map[string]time.Time
 
-	entryCacheMutex  sync.RWMutex
-	parentCacheMutex sync.RWMutex
+	entryCacheMutex        sync.RWMutex
+	parentCacheMutex       sync.RWMutex
+	recordedTimeCacheMutex sync.RWMutex
 }
 
 func (r *rslCache) getEntry(id gitinterface.Hash) (Entry, bool) {
@@ -55,12 +58,28 @@ func (r *rslCache) setParent(id, parentID gitinterface.Hash) {
 	r.parentCache[id.String()] = parentID.String()
 }
 
+func (r *rslCache) getRecordedTime(id gitinterface.Hash) (time.Time, bool) {
+	r.recordedTimeCacheMutex.RLock()
+	defer r.recordedTimeCacheMutex.RUnlock()
+
+	recordedTime, has := r.recordedTimeCache[id.String()]
+	return recordedTime, has
+}
+
+func (r *rslCache) setRecordedTime(id gitinterface.Hash, recordedTime time.Time) {
+	r.recordedTimeCacheMutex.Lock()
+	defer r.recordedTimeCacheMutex.Unlock()
+
+	r.recordedTimeCache[id.String()] = recordedTime
+}
+
 var cache *rslCache
 
 func newRSLCache() {
 	cache = &rslCache{
-		entryCache:  map[string]Entry{},
-		parentCache: map[string]string{},
+		entryCache:        map[string]Entry{},
+		parentCache:       map[string]string{},
+		recordedTimeCache: map[string]time.Time{},
 	}
 }
 
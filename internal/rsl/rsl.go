@@ -8,8 +8,10 @@ import (
 	"errors"
 	"fmt"
 	"log/slog"
+	"slices"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/gittuf/gittuf/internal/gitinterface"
 	"github.com/gittuf/gittuf/internal/tuf"
@@ -34,6 +36,10 @@ const (
 	UpstreamRepositoryKey  = "upstreamRepository"
 	UpstreamEntryIDKey     = "upstreamEntryID"
 
+	CheckpointEntryHeader = "RSL Checkpoint Entry"
+	PolicyEntryIDKey      = "policyEntryID"
+	RefTipKey             = "refTip"
+
 	remoteTrackerRef       = "refs/remotes/%s/gittuf/reference-state-log"
 	gittufNamespacePrefix  = "refs/gittuf/"
 	gittufPolicyStagingRef = "refs/gittuf/policy-staging"
@@ -524,6 +530,145 @@ func (e *PropagationEntry) createCommitMessage(includeNumber bool) (string, erro
 	return strings.Join(lines, "\n"), nil
 }
 
+// CheckpointEntry is a type of RSL record that captures the state of the
+// repository after it has been verified. It records the tip of every reference
+// that was verified along with the policy entry in effect at that point. A
+// checkpoint is considered trustworthy when its commit and any annotations
+// approving it are signed by a threshold of the repository's root principals,
+// allowing verifiers that trust it to begin verification from the checkpoint
+// rather than from the start of the RSL.
+type CheckpointEntry struct {
+	// ID contains the Git hash for the commit corresponding to the entry.
+	ID gitinterface.Hash
+
+	// PolicyEntryID contains the ID of the RSL entry for the policy in effect
+	// when the checkpoint was created.
+	PolicyEntryID gitinterface.Hash
+
+	// RefTips contains the verified tip of each Git reference recorded in the
+	// checkpoint, keyed by the reference name.
+	RefTips map[string]gitinterface.Hash
+
+	// Number contains a strictly increasing number that hints at entry ordering.
+	Number uint64
+}
+
+// NewCheckpointEntry returns a CheckpointEntry object that records the
+// specified reference tips and policy entry.
+func NewCheckpointEntry(policyEntryID gitinterface.Hash, refTips map[string]gitinterface.Hash) *CheckpointEntry {
+	return &CheckpointEntry{PolicyEntryID: policyEntryID, RefTips: refTips}
+}
+
+func (c *CheckpointEntry) GetID() gitinterface.Hash {
+	return c.ID
+}
+
+// GetRefTip returns the tip recorded for the reference in the checkpoint. The
+// boolean return value is false if the reference is not recorded.
+func (c *CheckpointEntry) GetRefTip(refName string) (gitinterface.Hash, bool) {
+	tip, has := c.RefTips[refName]
+	return tip, has
+}
+
+// Commit creates a commit object in the RSL for the CheckpointEntry. The
+// function looks up the latest committed entry in the RSL and increments the
+// number in the new entry. If a parent entry does not exist or the parent
+// entry's number is 0 (unset), the current entry's number is set to 1. The
+// numbering starts from 1 as 0 is used to signal the lack of numbering.
+func (c *CheckpointEntry) Commit(repo *gitinterface.Repository, sign bool) error {
+	if err := c.setEntryNumber(repo); err != nil {
+		return err
+	}
+
+	message, _ := c.createCommitMessage(true) // we have an error return for annotations, always nil here
+
+	emptyTreeID, err := repo.EmptyTree()
+	if err != nil {
+		return err
+	}
+
+	_, err = repo.Commit(emptyTreeID, Ref, message, sign)
+	return err
+}
+
+// CommitUsingSpecificKey creates a commit object in the RSL for the
+// CheckpointEntry. The commit is signed using the provided PEM encoded SSH or
+// GPG private key. This is only intended for use in gittuf's developer mode or
+// in tests. The function looks up the latest committed entry in the RSL and
+// increments the number in the new entry. If a parent entry does not exist or
+// the parent entry's number is 0 (unset), the current entry's number is set to
+// 1. The numbering starts from 1 as 0 is used to signal the lack of numbering.
+func (c *CheckpointEntry) CommitUsingSpecificKey(repo *gitinterface.Repository, signingKeyBytes []byte) error {
+	if err := c.setEntryNumber(repo); err != nil {
+		return err
+	}
+
+	message, _ := c.createCommitMessage(true) // we have an error return for annotations, always nil here
+
+	emptyTreeID, err := repo.EmptyTree()
+	if err != nil {
+		return err
+	}
+
+	_, err = repo.CommitUsingSpecificKey(emptyTreeID, Ref, message, signingKeyBytes)
+	return err
+}
+
+func (c *CheckpointEntry) GetNumber() uint64 {
+	return c.Number
+}
+
+// SkippedBy returns true if any of the annotations mark the checkpoint as
+// to-be-skipped, i.e., revoked.
+func (c *CheckpointEntry) SkippedBy(annotations []*AnnotationEntry) bool {
+	for _, annotation := range annotations {
+		if annotation.RefersTo(c.ID) && annotation.Skip {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (c *CheckpointEntry) setEntryNumber(repo *gitinterface.Repository) error {
+	latestEntry, err := GetLatestEntry(repo)
+	if err == nil {
+		c.Number = latestEntry.GetNumber() + 1
+	} else {
+		if errors.Is(err, ErrRSLEntryNotFound) {
+			// First entry
+			c.Number = 1
+		} else {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (c *CheckpointEntry) createCommitMessage(includeNumber bool) (string, error) {
+	lines := []string{
+		CheckpointEntryHeader,
+		"",
+		fmt.Sprintf("%s: %s", PolicyEntryIDKey, c.PolicyEntryID.String()),
+	}
+
+	// Reference tips are recorded in a deterministic order
+	refNames := make([]string, 0, len(c.RefTips))
+	for refName := range c.RefTips {
+		refNames = append(refNames, refName)
+	}
+	slices.Sort(refNames)
+	for _, refName := range refNames {
+		lines = append(lines, fmt.Sprintf("%s: %s %s", RefTipKey, refName, c.RefTips[refName].String()))
+	}
+
+	if includeNumber && c.Number > 0 {
+		lines = append(lines, fmt.Sprintf("%s: %d", NumberKey, c.Number))
+	}
+	return strings.Join(lines, "\n"), nil
+}
+
 // GetEntry returns the entry corresponding to entryID.
 func GetEntry(repo *gitinterface.Repository, entryID gitinterface.Hash) (Entry, error) {
 	entry, has := cache.getEntry(entryID)
@@ -590,6 +735,60 @@ func GetParentForEntry(repo *gitinterface.Repository, entry Entry) (Entry, error
 	return parentEntry, nil
 }
 
+// GetRecordedTime returns when the entry was recorded in the RSL. An entry's
+// commit time is set by whoever creates it and can be backdated, but the entry
+// cannot have been recorded before the entries that precede it. So, the
+// recorded time is the later of the entry's commit time and the recorded time
+// of its parent entry. The RSL is walked back to the nearest entry whose
+// recorded time is already known, such as a trusted checkpoint recorded using
+// SetRecordedTime, rather than to the first entry.
+func GetRecordedTime(repo *gitinterface.Repository, entry Entry) (time.Time, error) {
+	// Walk back to the nearest entry whose recorded time is already known, or
+	// to the first entry in the RSL
+	var (
+		recordedTime time.Time
+		pending      []Entry
+	)
+	for current := entry; current != nil; {
+		if knownTime, has := cache.getRecordedTime(current.GetID()); has {
+			recordedTime = knownTime
+			break
+		}
+		pending = append(pending, current)
+
+		parent, err := GetParentForEntry(repo, current)
+		if err != nil {
+			if !errors.Is(err, ErrRSLEntryNotFound) {
+				return time.Time{}, err
+			}
+			parent = nil
+		}
+		current = parent
+	}
+
+	for i := len(pending) - 1; i >= 0; i-- {
+		commitTime, err := repo.GetCommitTime(pending[i].GetID())
+		if err != nil {
+			return time.Time{}, err
+		}
+		if commitTime.After(recordedTime) {
+			recordedTime = commitTime
+		}
+
+		cache.setRecordedTime(pending[i].GetID(), recordedTime)
+	}
+
+	return recordedTime, nil
+}
+
+// SetRecordedTime records when the entry with the specified ID was recorded in
+// the RSL, as determined earlier by the caller. This must only be used for
+// entries the caller trusts, such as RSL checkpoints approved by the root of
+// trust, as GetRecordedTime doesn't walk back past them.
+func SetRecordedTime(entryID gitinterface.Hash, recordedTime time.Time) {
+	cache.setRecordedTime(entryID, recordedTime)
+}
+
 // GetNonGittufParentReferenceUpdaterEntryForEntry returns the first RSL
 // reference updater entry starting from the specified entry's parent that is
 // not for the gittuf namespace.
@@ -1151,6 +1350,8 @@ func parseRSLEntryText(id gitinterface.Hash, text string) (Entry, error) {
 		return parseAnnotationEntryText(id, text)
 	case strings.HasPrefix(text, PropagationEntryHeader):
 		return parsePropagationEntryText(id, text)
+	case strings.HasPrefix(text, CheckpointEntryHeader):
+		return parseCheckpointEntryText(id, text)
 	default:
 		return nil, ErrInvalidRSLEntry
 	}
@@ -1307,6 +1508,63 @@ func parsePropagationEntryText(id gitinterface.Hash, text string) (*PropagationE
 	return entry, nil
 }
 
+func parseCheckpointEntryText(id gitinterface.Hash, text string) (*CheckpointEntry, error) {
+	lines := strings.Split(text, "\n")
+	if len(lines) < 3 {
+		return nil, ErrInvalidRSLEntry
+	}
+	lines = lines[2:]
+
+	entry := &CheckpointEntry{ID: id, RefTips: map[string]gitinterface.Hash{}}
+	for _, l := range lines {
+		l = strings.TrimSpace(l)
+
+		ls := strings.Split(l, ":")
+		if len(ls) < 2 {
+			return nil, ErrInvalidRSLEntry
+		}
+
+		switch strings.TrimSpace(ls[0]) {
+		case PolicyEntryIDKey:
+			policyEntryID, err := gitinterface.NewHash(strings.TrimSpace(ls[1]))
+			if err != nil {
+				return nil, err
+			}
+
+			entry.PolicyEntryID = policyEntryID
+
+		case RefTipKey:
+			// Git reference names cannot contain spaces or `:`, so the
+			// reference and its tip are separated by a space
+			refTip := strings.Fields(ls[1])
+			if len(refTip) != 2 {
+				return nil, ErrInvalidRSLEntry
+			}
+
+			tip, err := gitinterface.NewHash(refTip[1])
+			if err != nil {
+				return nil, err
+			}
+
+			entry.RefTips[refTip[0]] = tip
+
+		case NumberKey:
+			number, err := strconv.ParseUint(strings.TrimSpace(ls[1]), 10, 64)
+			if err != nil {
+				return nil, err
+			}
+
+			entry.Number = number
+		}
+	}
+
+	if entry.PolicyEntryID == nil {
+		return nil, ErrInvalidRSLEntry
+	}
+
+	return entry, nil
+}
+
 func filterAnnotationsForRelevantAnnotations(allAnnotations []*AnnotationEntry, entryID gitinterface.Hash) []*AnnotationEntry {
 	annotations := []*AnnotationEntry{}
 	for _, annotation := range allAnnotations {
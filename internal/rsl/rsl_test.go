@@ -9,6 +9,7 @@ import (
 	"math"
 	"slices"
 	"testing"
+	"time"
 
 	"github.com/gittuf/gittuf/internal/gitinterface"
 	"github.com/gittuf/gittuf/internal/tuf"
@@ -917,6 +918,29 @@ func TestGetEntry(t *testing.T) {
 	assert.True(t, a.Skip)
 	assert.Equal(t, []gitinterface.Hash{initialEntryID}, a.RSLEntryIDs)
 	assert.Equal(t, "This was a mistaken push!", a.Message)
+
+	if err := NewCheckpointEntry(initialEntryID, map[string]gitinterface.Hash{"refs/heads/main": gitinterface.ZeroHash}).Commit(repo, false); err != nil {
+		t.Fatal(err)
+	}
+
+	checkpointID, err := repo.GetReference(Ref)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	entry, err = GetEntry(repo, checkpointID)
+	assert.Nil(t, err)
+	c := entry.(*CheckpointEntry)
+	assert.Equal(t, initialEntryID, c.PolicyEntryID)
+	assert.Equal(t, uint64(4), c.Number)
+	tip, has := c.GetRefTip("refs/heads/main")
+	assert.True(t, has)
+	assert.Equal(t, gitinterface.ZeroHash, tip)
+
+	// The checkpoint is not a reference updater entry
+	latestEntry, _, err := GetLatestReferenceUpdaterEntry(repo)
+	assert.Nil(t, err)
+	assert.NotEqual(t, checkpointID, latestEntry.GetID())
 }
 
 func TestGetParentForEntry(t *testing.T) {
@@ -1000,6 +1024,111 @@ func TestGetParentForEntry(t *testing.T) {
 	})
 }
 
+func TestGetRecordedTime(t *testing.T) {
+	tempDir := t.TempDir()
+	repo := gitinterface.CreateTestGitRepository(t, tempDir, false)
+
+	firstTime := time.Date(2025, time.March, 1, 9, 0, 0, 0, time.UTC)
+	gitinterface.SetTestCommitTime(t, repo, firstTime)
+	if err := NewReferenceEntry("refs/heads/main", gitinterface.ZeroHash).Commit(repo, false); err != nil {
+		t.Fatal(err)
+	}
+	firstEntry, err := GetLatestEntry(repo)
+	require.Nil(t, err)
+
+	// Backdated entry is recorded no earlier than its parent
+	gitinterface.SetTestCommitTime(t, repo, firstTime.Add(-24*time.Hour))
+	if err := NewReferenceEntry("refs/heads/main", gitinterface.ZeroHash).Commit(repo, false); err != nil {
+		t.Fatal(err)
+	}
+	backdatedEntry, err := GetLatestEntry(repo)
+	require.Nil(t, err)
+
+	// Entries after a backdated entry are also recorded no earlier than the
+	// entries before it
+	if err := NewAnnotationEntry([]gitinterface.Hash{backdatedEntry.GetID()}, false, annotationMessage).Commit(repo, false); err != nil {
+		t.Fatal(err)
+	}
+	backdatedAnnotation, err := GetLatestEntry(repo)
+	require.Nil(t, err)
+
+	laterTime := firstTime.Add(time.Hour)
+	gitinterface.SetTestCommitTime(t, repo, laterTime)
+	if err := NewReferenceEntry("refs/heads/main", gitinterface.ZeroHash).Commit(repo, false); err != nil {
+		t.Fatal(err)
+	}
+	laterEntry, err := GetLatestEntry(repo)
+	require.Nil(t, err)
+
+	tests := map[string]struct {
+		entry        Entry
+		expectedTime time.Time
+	}{
+		"first entry": {
+			entry:        firstEntry,
+			expectedTime: firstTime,
+		},
+		"backdated entry": {
+			entry:        backdatedEntry,
+			expectedTime: firstTime,
+		},
+		"entry after backdated entry": {
+			entry:        backdatedAnnotation,
+			expectedTime: firstTime,
+		},
+		"later entry": {
+			entry:        laterEntry,
+			expectedTime: laterTime,
+		},
+	}
+
+	for name, test := range tests {
+		recordedTime, err := GetRecordedTime(repo, test.entry)
+		assert.Nil(t, err, fmt.Sprintf("unexpected error in test '%s'", name))
+		assert.True(t, test.expectedTime.Equal(recordedTime), fmt.Sprintf("unexpected recorded time %s in test '%s'", recordedTime, name))
+	}
+}
+
+func TestSetRecordedTime(t *testing.T) {
+	tempDir := t.TempDir()
+	repo := gitinterface.CreateTestGitRepository(t, tempDir, false)
+
+	firstTime := time.Date(2025, time.March, 2, 9, 0, 0, 0, time.UTC)
+	gitinterface.SetTestCommitTime(t, repo, firstTime)
+	if err := NewReferenceEntry("refs/heads/main", gitinterface.ZeroHash).Commit(repo, false); err != nil {
+		t.Fatal(err)
+	}
+	firstEntry, err := GetLatestEntry(repo)
+	require.Nil(t, err)
+
+	// The checkpoint is backdated, but its recorded time is set by the caller
+	// that trusts it
+	checkpointTime := firstTime.Add(-24 * time.Hour)
+	gitinterface.SetTestCommitTime(t, repo, checkpointTime)
+	if err := NewCheckpointEntry(firstEntry.GetID(), map[string]gitinterface.Hash{"refs/heads/main": gitinterface.ZeroHash}).Commit(repo, false); err != nil {
+		t.Fatal(err)
+	}
+	checkpoint, err := GetLatestEntry(repo)
+	require.Nil(t, err)
+	SetRecordedTime(checkpoint.GetID(), checkpointTime)
+
+	laterTime := checkpointTime.Add(time.Hour)
+	gitinterface.SetTestCommitTime(t, repo, laterTime)
+	if err := NewReferenceEntry("refs/heads/main", gitinterface.ZeroHash).Commit(repo, false); err != nil {
+		t.Fatal(err)
+	}
+	laterEntry, err := GetLatestEntry(repo)
+	require.Nil(t, err)
+
+	// The walk stops at the checkpoint, so the first entry isn't visited
+	recordedTime, err := GetRecordedTime(repo, laterEntry)
+	require.Nil(t, err)
+	assert.True(t, laterTime.Equal(recordedTime))
+
+	_, has := cache.getRecordedTime(firstEntry.GetID())
+	assert.False(t, has)
+}
+
 func TestGetNonGittufParentReferenceUpdaterEntryForEntry(t *testing.T) {
 	t.Run("mix of gittuf and non gittuf entries", func(t *testing.T) {
 		tempDir := t.TempDir()
@@ -2052,6 +2181,47 @@ func TestPropagationEntryCreateCommitMessage(t *testing.T) {
 	}
 }
 
+func TestCheckpointEntryCreateCommitMessage(t *testing.T) {
+	nonZeroHash, err := gitinterface.NewHash("abcdef12345678900987654321fedcbaabcdef12")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := map[string]struct {
+		entry           *CheckpointEntry
+		expectedMessage string
+	}{
+		"checkpoint, no refs": {
+			entry:           NewCheckpointEntry(nonZeroHash, nil),
+			expectedMessage: fmt.Sprintf("%s\n\n%s: %s", CheckpointEntryHeader, PolicyEntryIDKey, nonZeroHash.String()),
+		},
+		"checkpoint, refs in sorted order": {
+			entry: NewCheckpointEntry(nonZeroHash, map[string]gitinterface.Hash{
+				"refs/tags/v1":    gitinterface.ZeroHash,
+				"refs/heads/main": nonZeroHash,
+			}),
+			expectedMessage: fmt.Sprintf("%s\n\n%s: %s\n%s: %s %s\n%s: %s %s", CheckpointEntryHeader, PolicyEntryIDKey, nonZeroHash.String(), RefTipKey, "refs/heads/main", nonZeroHash.String(), RefTipKey, "refs/tags/v1", gitinterface.ZeroHash.String()),
+		},
+		"checkpoint, large number": {
+			entry: &CheckpointEntry{
+				PolicyEntryID: nonZeroHash,
+				RefTips:       map[string]gitinterface.Hash{"refs/heads/main": nonZeroHash},
+				Number:        math.MaxUint64,
+			},
+			expectedMessage: fmt.Sprintf("%s\n\n%s: %s\n%s: %s %s\n%s: %d", CheckpointEntryHeader, PolicyEntryIDKey, nonZeroHash.String(), RefTipKey, "refs/heads/main", nonZeroHash.String(), NumberKey, uint64(math.MaxUint64)),
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			message, _ := test.entry.createCommitMessage(true)
+			if !assert.Equal(t, test.expectedMessage, message) {
+				t.Errorf("expected\n%s\n\ngot\n%s", test.expectedMessage, message)
+			}
+		})
+	}
+}
+
 func TestParseRSLEntryText(t *testing.T) {
 	nonZeroHash, err := gitinterface.NewHash("abcdef12345678900987654321fedcbaabcdef12")
 	if err != nil {
@@ -2166,6 +2336,34 @@ func TestParseRSLEntryText(t *testing.T) {
 			expectedError: ErrInvalidRSLEntry,
 			message:       fmt.Sprintf("%s\n\n%s: %s\n%s: %s\n%s: %s", PropagationEntryHeader, RefKey, "refs/heads/main", TargetIDKey, "abcdef12345678900987654321fedcbaabcdef12", UpstreamRepositoryKey, upstreamRepository),
 		},
+		"checkpoint entry": {
+			expectedEntry: &CheckpointEntry{
+				ID:            gitinterface.ZeroHash,
+				PolicyEntryID: nonZeroHash,
+				RefTips: map[string]gitinterface.Hash{
+					"refs/heads/main":    nonZeroHash,
+					"refs/heads/feature": gitinterface.ZeroHash,
+				},
+				Number: 3,
+			},
+			message: fmt.Sprintf("%s\n\n%s: %s\n%s: %s %s\n%s: %s %s\n%s: %d", CheckpointEntryHeader, PolicyEntryIDKey, nonZeroHash.String(), RefTipKey, "refs/heads/feature", gitinterface.ZeroHash.String(), RefTipKey, "refs/heads/main", nonZeroHash.String(), NumberKey, 3),
+		},
+		"checkpoint entry, no refs": {
+			expectedEntry: &CheckpointEntry{
+				ID:            gitinterface.ZeroHash,
+				PolicyEntryID: nonZeroHash,
+				RefTips:       map[string]gitinterface.Hash{},
+			},
+			message: fmt.Sprintf("%s\n\n%s: %s", CheckpointEntryHeader, PolicyEntryIDKey, nonZeroHash.String()),
+		},
+		"checkpoint entry, missing policy entry": {
+			expectedError: ErrInvalidRSLEntry,
+			message:       fmt.Sprintf("%s\n\n%s: %s %s", CheckpointEntryHeader, RefTipKey, "refs/heads/main", nonZeroHash.String()),
+		},
+		"checkpoint entry, malformed ref tip": {
+			expectedError: ErrInvalidRSLEntry,
+			message:       fmt.Sprintf("%s\n\n%s: %s\n%s: %s", CheckpointEntryHeader, PolicyEntryIDKey, nonZeroHash.String(), RefTipKey, "refs/heads/main"),
+		},
 	}
 
 	for name, test := range tests {
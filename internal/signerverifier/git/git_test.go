@@ -4,9 +4,12 @@
 package git
 
 import (
+	"bytes"
+	"os/exec"
 	"testing"
 
 	"github.com/gittuf/gittuf/internal/gitinterface"
+	"github.com/gittuf/gittuf/internal/signerverifier/gpg"
 	"github.com/gittuf/gittuf/internal/signerverifier/ssh"
 	artifacts "github.com/gittuf/gittuf/internal/testartifacts"
 	"github.com/stretchr/testify/assert"
@@ -27,7 +30,7 @@ func TestLoadSignerFromGitConfig(t *testing.T) {
 		}
 
 		_, err := LoadSignerFromGitConfig(repo)
-		assert.ErrorContains(t, err, "not implemented")
+		assert.ErrorIs(t, err, ErrSigningKeyNotSpecified)
 	})
 
 	t.Run("ssh key configured, but no signing key specified", func(t *testing.T) {
@@ -44,16 +47,37 @@ func TestLoadSignerFromGitConfig(t *testing.T) {
 	})
 
 	t.Run("gpg key specified", func(t *testing.T) {
-		// Test GPG behavior, should error out due to not being implemented
+		// Test a working GPG key configured
 		tmpDir := t.TempDir()
 		repo := gitinterface.CreateTestGitRepository(t, tmpDir, false)
 
+		t.Setenv("GNUPGHOME", t.TempDir())
+		t.Cleanup(func() {
+			exec.Command("gpgconf", "--kill", "gpg-agent").Run() //nolint:errcheck
+		})
+
+		cmd := exec.Command("gpg", "--batch", "--import")
+		cmd.Stdin = bytes.NewReader(artifacts.GPGKey1Private)
+		if output, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("unable to import gpg key: %s: %s", err, string(output))
+		}
+
+		compareKey, err := gpg.LoadGPGKeyFromBytes(artifacts.GPGKey1Public)
+		require.Nil(t, err)
+
 		if err := repo.SetGitConfig("gpg.format", "gpg"); err != nil {
 			t.Fatal(err)
 		}
+		if err := repo.SetGitConfig("user.signingkey", compareKey.KeyID); err != nil {
+			t.Fatal(err)
+		}
 
-		_, err := LoadSignerFromGitConfig(repo)
-		assert.ErrorContains(t, err, "not implemented")
+		signer, err := LoadSignerFromGitConfig(repo)
+		assert.Nil(t, err)
+
+		signerKeyID, err := signer.KeyID()
+		require.Nil(t, err)
+		assert.Equal(t, compareKey.KeyID, signerKeyID)
 	})
 
 	t.Run("ssh key specified", func(t *testing.T) {
@@ -5,7 +5,10 @@ package gpg
 
 import (
 	"bytes"
+	"context"
+	"crypto"
 	"fmt"
+	"os/exec"
 	"strings"
 
 	"github.com/ProtonMail/go-crypto/openpgp"
@@ -14,6 +17,67 @@ import (
 
 const KeyType = "gpg"
 
+// Verifier is a dsse.Verifier implementation for GPG / PGP keys.
+type Verifier struct {
+	keyID   string
+	keyring openpgp.EntityList
+	key     *signerverifier.SSLibKey
+}
+
+// Verify implements the dsse.Verifier.Verify interface for GPG keys. The
+// signature is expected to be an armored detached signature over the data.
+func (v *Verifier) Verify(_ context.Context, data []byte, sig []byte) error {
+	if _, err := openpgp.CheckArmoredDetachedSignature(v.keyring, bytes.NewReader(data), bytes.NewReader(sig), nil); err != nil {
+		return fmt.Errorf("failed to verify gpg signature: %w", err)
+	}
+
+	return nil
+}
+
+// KeyID implements the dsse.Verifier.KeyID interface for GPG keys.
+func (v *Verifier) KeyID() (string, error) {
+	return v.keyID, nil
+}
+
+// Public implements the dsse.Verifier.Public interface for GPG keys.
+func (v *Verifier) Public() crypto.PublicKey {
+	return v.keyring[0].PrimaryKey.PublicKey
+}
+
+// MetadataKey returns the SSLibKey representation of the GPG key for use in
+// gittuf metadata.
+func (v *Verifier) MetadataKey() *signerverifier.SSLibKey {
+	return v.key
+}
+
+// Signer is a dsse.Signer implementation for GPG keys.
+type Signer struct {
+	Fingerprint string
+	*Verifier
+}
+
+// Sign implements the dsse.Signer.Sign interface for GPG keys. It invokes the
+// gpg binary to create an armored detached signature using the key identified
+// by "s.Fingerprint". The private key is never read by gittuf, and the gpg
+// agent is responsible for accessing it. This allows using keys stored on
+// smartcards. The gpg process is killed if ctx is cancelled, for example when
+// the agent is waiting on a pinentry prompt that is never answered.
+func (s *Signer) Sign(ctx context.Context, data []byte) ([]byte, error) {
+	// gpg selects the appropriate signing subkey if the primary key isn't
+	// available for signing. Verification accounts for all subkeys of the
+	// primary key.
+	cmd := exec.CommandContext(ctx, "gpg", "--batch", "--yes", "--armor", "--detach-sign", "--local-user", s.Fingerprint) //nolint:gosec
+
+	cmd.Stdin = bytes.NewBuffer(data)
+
+	output, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("failed to run command %v: %w", cmd, err)
+	}
+
+	return output, nil
+}
+
 // LoadGPGKeyFromBytes returns a signerverifier.SSLibKey for a GPG / PGP key passed in as
 // armored bytes. The returned signerverifier.SSLibKey uses the primary key's fingerprint as the
 // key ID.
@@ -38,3 +102,51 @@ func LoadGPGKeyFromBytes(contents []byte) (*signerverifier.SSLibKey, error) {
 
 	return gpgKey, nil
 }
+
+// NewVerifierFromKey creates a new Verifier from SSLibKey of type gpg.
+func NewVerifierFromKey(key *signerverifier.SSLibKey) (*Verifier, error) {
+	if key.KeyType != KeyType {
+		return nil, fmt.Errorf("wrong keyType: %s", key.KeyType)
+	}
+
+	keyring, err := openpgp.ReadArmoredKeyRing(strings.NewReader(key.KeyVal.Public))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse gpg public key material: %w", err)
+	}
+
+	return &Verifier{
+		keyID:   key.KeyID,
+		keyring: keyring,
+		key:     key,
+	}, nil
+}
+
+// NewSignerFromFingerprint creates a GPG signer for the key with the specified
+// fingerprint. The public key is exported from the local gpg keyring.
+func NewSignerFromFingerprint(fingerprint string) (*Signer, error) {
+	fingerprint = strings.ToLower(strings.TrimSpace(fingerprint))
+
+	cmd := exec.Command("gpg", "--export", "--armor", fingerprint) //nolint:gosec
+	output, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("failed to run command %v: %w", cmd, err)
+	}
+	if len(output) == 0 {
+		return nil, fmt.Errorf("gpg key '%s' not found in local keyring", fingerprint)
+	}
+
+	keyObj, err := LoadGPGKeyFromBytes(output)
+	if err != nil {
+		return nil, err
+	}
+
+	verifier, err := NewVerifierFromKey(keyObj)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Signer{
+		Fingerprint: keyObj.KeyID,
+		Verifier:    verifier,
+	}, nil
+}
@@ -4,12 +4,19 @@
 package gpg
 
 import (
+	"bytes"
+	"context"
+	"os/exec"
+	"strings"
 	"testing"
 
 	artifacts "github.com/gittuf/gittuf/internal/testartifacts"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
+var testCtx = context.Background()
+
 func TestLoadGPGKeyFromBytes(t *testing.T) {
 	keyBytes := artifacts.GPGKey1Public
 
@@ -19,3 +26,65 @@ func TestLoadGPGKeyFromBytes(t *testing.T) {
 	assert.Equal(t, KeyType, key.Scheme)
 	assert.Equal(t, "157507bbe151e378ce8126c1dcfe043cdd2db96e", key.KeyID)
 }
+
+func TestSignerVerifier(t *testing.T) {
+	t.Setenv("GNUPGHOME", t.TempDir())
+	t.Cleanup(func() {
+		exec.Command("gpgconf", "--kill", "gpg-agent").Run() //nolint:errcheck
+	})
+
+	cmd := exec.Command("gpg", "--batch", "--import")
+	cmd.Stdin = bytes.NewReader(artifacts.GPGKey1Private)
+	if output, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("unable to import gpg key: %s: %s", err, string(output))
+	}
+
+	fingerprint := "157507bbe151e378ce8126c1dcfe043cdd2db96e"
+	data := []byte("DATA")
+
+	signer, err := NewSignerFromFingerprint(strings.ToUpper(fingerprint))
+	require.Nil(t, err)
+
+	keyID, err := signer.KeyID()
+	assert.Nil(t, err)
+	assert.Equal(t, fingerprint, keyID)
+	assert.Equal(t, KeyType, signer.MetadataKey().KeyType)
+
+	sig, err := signer.Sign(testCtx, data)
+	require.Nil(t, err)
+
+	err = signer.Verify(testCtx, data, sig)
+	assert.Nil(t, err)
+
+	err = signer.Verify(testCtx, []byte("NOT DATA"), sig)
+	assert.NotNil(t, err)
+
+	// Verify using only the public key as stored in metadata
+	publicKey, err := LoadGPGKeyFromBytes(artifacts.GPGKey1Public)
+	require.Nil(t, err)
+
+	verifier, err := NewVerifierFromKey(publicKey)
+	require.Nil(t, err)
+
+	err = verifier.Verify(testCtx, data, sig)
+	assert.Nil(t, err)
+
+	// Verify using a different key fails
+	otherKey, err := LoadGPGKeyFromBytes(artifacts.GPGKey2Public)
+	require.Nil(t, err)
+
+	verifier, err = NewVerifierFromKey(otherKey)
+	require.Nil(t, err)
+
+	err = verifier.Verify(testCtx, data, sig)
+	assert.NotNil(t, err)
+
+	// Signing with a cancelled context fails
+	cancelledCtx, cancel := context.WithCancel(testCtx)
+	cancel()
+	_, err = signer.Sign(cancelledCtx, data)
+	assert.NotNil(t, err)
+
+	_, err = NewSignerFromFingerprint("0000000000000000000000000000000000000000")
+	assert.NotNil(t, err)
+}
@@ -0,0 +1,87 @@
+// Copyright The gittuf Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package tuf
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// RefAction identifies the kind of change made to a Git reference by an RSL
+// entry. Rules may limit the actions they apply to, so that, for example,
+// deleting a release branch requires different principals than pushing to it.
+type RefAction string
+
+const (
+	// RefActionCreate is the creation of a reference that doesn't exist.
+	RefActionCreate RefAction = "create"
+	// RefActionFastForward is an update of a reference to a descendant of its
+	// current target.
+	RefActionFastForward RefAction = "fast-forward"
+	// RefActionNonFastForward is an update of a reference to a commit that
+	// isn't a descendant of its current target, i.e., a force push.
+	RefActionNonFastForward RefAction = "non-fast-forward"
+	// RefActionDelete is the deletion of a reference.
+	RefActionDelete RefAction = "delete"
+	// RefActionAnnotate is the annotation of RSL entries for a reference, such
+	// as to mark them as skipped.
+	RefActionAnnotate RefAction = "annotate"
+)
+
+// RefActions lists the recognized reference actions in their canonical order.
+var RefActions = []RefAction{RefActionCreate, RefActionFastForward, RefActionNonFastForward, RefActionDelete, RefActionAnnotate}
+
+// ParseRefActions validates the specified actions, returning them without
+// duplicates in their canonical order.
+func ParseRefActions(actions []string) ([]RefAction, error) {
+	parsed := []RefAction{}
+	for _, action := range actions {
+		if !slices.Contains(RefActions, RefAction(action)) {
+			return nil, fmt.Errorf("%w: '%s'", ErrInvalidRefAction, action)
+		}
+		if !slices.Contains(parsed, RefAction(action)) {
+			parsed = append(parsed, RefAction(action))
+		}
+	}
+
+	slices.SortFunc(parsed, func(a, b RefAction) int {
+		return slices.Index(RefActions, a) - slices.Index(RefActions, b)
+	})
+	return parsed, nil
+}
+
+// AllowsAction indicates if a rule that allows the specified actions applies
+// to the action. A rule that doesn't list any actions applies to all actions
+// except annotations, and every rule applies when the action isn't known, such
+// as when a file is verified. Annotations are only controlled by rules that
+// explicitly list them so that existing rules don't restrict who may annotate
+// RSL entries.
+func AllowsAction(allowedActions []RefAction, action RefAction) bool {
+	if action == RefActionAnnotate {
+		return slices.Contains(allowedActions, action)
+	}
+
+	if len(allowedActions) == 0 || action == "" {
+		return true
+	}
+
+	return slices.Contains(allowedActions, action)
+}
+
+// ValidateRefActionPatterns ensures that a rule that lists reference actions
+// only protects Git references, as the actions don't apply to files.
+func ValidateRefActionPatterns(actions []RefAction, patterns []string) error {
+	if len(actions) == 0 {
+		return nil
+	}
+
+	for _, pattern := range patterns {
+		if !strings.HasPrefix(strings.TrimPrefix(pattern, NegatedPatternPrefix), "git:") {
+			return fmt.Errorf("%w: '%s'", ErrRefActionsOnlyApplyToGitPaths, pattern)
+		}
+	}
+
+	return nil
+}
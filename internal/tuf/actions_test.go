@@ -0,0 +1,49 @@
+// Copyright The gittuf Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package tuf
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseRefActions(t *testing.T) {
+	t.Run("canonical order without duplicates", func(t *testing.T) {
+		actions, err := ParseRefActions([]string{"delete", "create", "delete"})
+		assert.Nil(t, err)
+		assert.Equal(t, []RefAction{RefActionCreate, RefActionDelete}, actions)
+	})
+
+	t.Run("no actions", func(t *testing.T) {
+		actions, err := ParseRefActions(nil)
+		assert.Nil(t, err)
+		assert.Empty(t, actions)
+	})
+
+	t.Run("unknown action", func(t *testing.T) {
+		_, err := ParseRefActions([]string{"create", "rename"})
+		assert.ErrorIs(t, err, ErrInvalidRefAction)
+	})
+}
+
+func TestAllowsAction(t *testing.T) {
+	assert.True(t, AllowsAction(nil, RefActionDelete))
+	assert.True(t, AllowsAction([]RefAction{RefActionDelete}, RefActionDelete))
+	assert.True(t, AllowsAction([]RefAction{RefActionDelete}, ""))
+	assert.False(t, AllowsAction([]RefAction{RefActionDelete}, RefActionFastForward))
+
+	// Annotations are only controlled by rules that list them
+	assert.False(t, AllowsAction(nil, RefActionAnnotate))
+	assert.False(t, AllowsAction([]RefAction{RefActionDelete}, RefActionAnnotate))
+	assert.True(t, AllowsAction([]RefAction{RefActionDelete, RefActionAnnotate}, RefActionAnnotate))
+}
+
+func TestValidateRefActionPatterns(t *testing.T) {
+	assert.Nil(t, ValidateRefActionPatterns(nil, []string{"file:*"}))
+	assert.Nil(t, ValidateRefActionPatterns([]RefAction{RefActionDelete}, []string{"git:refs/heads/*", "!git:refs/heads/main"}))
+
+	err := ValidateRefActionPatterns([]RefAction{RefActionDelete}, []string{"git:refs/heads/*", "file:*"})
+	assert.ErrorIs(t, err, ErrRefActionsOnlyApplyToGitPaths)
+}
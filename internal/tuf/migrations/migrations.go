@@ -4,9 +4,13 @@
 package migrations
 
 import (
+	"strings"
+
 	"github.com/gittuf/gittuf/internal/tuf"
 	tufv01 "github.com/gittuf/gittuf/internal/tuf/v01"
 	tufv02 "github.com/gittuf/gittuf/internal/tuf/v02"
+	tufv03 "github.com/gittuf/gittuf/internal/tuf/v03"
+	tufv04 "github.com/gittuf/gittuf/internal/tuf/v04"
 )
 
 /*
@@ -115,3 +119,245 @@ func MigrateTargetsMetadataV01ToV02(targetsMetadata *tufv01.TargetsMetadata) *tu
 
 	return newTargetsMetadata
 }
+
+// MigrateRootMetadataV02ToV03 converts tufv02.RootMetadata into
+// tufv03.RootMetadata.
+func MigrateRootMetadataV02ToV03(rootMetadata *tufv02.RootMetadata) *tufv03.RootMetadata {
+	newRootMetadata := tufv03.NewRootMetadata()
+
+	// Set same expires
+	newRootMetadata.Expires = rootMetadata.Expires
+
+	// Set repository location
+	newRootMetadata.RepositoryLocation = rootMetadata.RepositoryLocation
+
+	// Set principals
+	newRootMetadata.Principals = map[string]tuf.Principal{}
+	for principalID, principal := range rootMetadata.Principals {
+		newRootMetadata.Principals[principalID] = principal
+	}
+
+	// Set roles
+	newRootMetadata.Roles = map[string]tufv03.Role{}
+	for roleName, role := range rootMetadata.Roles {
+		newRootMetadata.Roles[roleName] = role
+	}
+
+	// Set app attestations support
+	newRootMetadata.GitHubApps = rootMetadata.GitHubApps
+
+	// Set global rules
+	newRootMetadata.GlobalRules = rootMetadata.GlobalRules
+
+	// Set propagations
+	newRootMetadata.Propagations = rootMetadata.Propagations
+
+	// Set multi-repository configuration
+	newRootMetadata.MultiRepository = rootMetadata.MultiRepository
+
+	// Set hooks
+	newRootMetadata.Hooks = rootMetadata.Hooks
+
+	return newRootMetadata
+}
+
+// MigrateTargetsMetadataV02ToV03 converts tufv02.TargetsMetadata into
+// tufv03.TargetsMetadata.
+func MigrateTargetsMetadataV02ToV03(targetsMetadata *tufv02.TargetsMetadata) *tufv03.TargetsMetadata {
+	newTargetsMetadata := tufv03.NewTargetsMetadata()
+
+	// Set same expires
+	newTargetsMetadata.Expires = targetsMetadata.Expires
+
+	// Set delegations
+	newTargetsMetadata.Delegations = &tufv03.Delegations{
+		Principals: map[string]tuf.Principal{},
+		Roles:      []*tufv03.Delegation{},
+	}
+	for principalID, principal := range targetsMetadata.Delegations.Principals {
+		newTargetsMetadata.Delegations.Principals[principalID] = principal
+	}
+	for _, role := range targetsMetadata.Delegations.Roles {
+		newRole := &tufv03.Delegation{
+			Name:        role.Name,
+			Paths:       role.Paths,
+			Terminating: role.Terminating,
+			Custom:      role.Custom,
+			Role:        role.Role,
+		}
+
+		newTargetsMetadata.Delegations.Roles = append(newTargetsMetadata.Delegations.Roles, newRole)
+	}
+
+	return newTargetsMetadata
+}
+
+// MigrateRootMetadataV03ToV04 converts tufv03.RootMetadata into
+// tufv04.RootMetadata. The patterns of global rules are converted to the v04
+// pattern syntax.
+func MigrateRootMetadataV03ToV04(rootMetadata *tufv03.RootMetadata) *tufv04.RootMetadata {
+	newRootMetadata := tufv04.NewRootMetadata()
+
+	// Set same expires
+	newRootMetadata.Expires = rootMetadata.Expires
+
+	// Set repository location
+	newRootMetadata.RepositoryLocation = rootMetadata.RepositoryLocation
+
+	// Set principals
+	newRootMetadata.Principals = map[string]tuf.Principal{}
+	for principalID, principal := range rootMetadata.Principals {
+		newRootMetadata.Principals[principalID] = principal
+	}
+
+	// Set roles
+	newRootMetadata.Roles = map[string]tufv04.Role{}
+	for roleName, role := range rootMetadata.Roles {
+		newRootMetadata.Roles[roleName] = role
+	}
+
+	// Set app attestations support
+	newRootMetadata.GitHubApps = rootMetadata.GitHubApps
+
+	// Set global rules
+	if rootMetadata.GlobalRules != nil {
+		newRootMetadata.GlobalRules = []tuf.GlobalRule{}
+	}
+	for _, globalRule := range rootMetadata.GlobalRules {
+		switch globalRule := globalRule.(type) {
+		case *tufv03.GlobalRuleThreshold:
+			newRootMetadata.GlobalRules = append(newRootMetadata.GlobalRules, &tufv04.GlobalRuleThreshold{
+				Name:      globalRule.Name,
+				Type:      globalRule.Type,
+				Paths:     migrateFnmatchPatterns(globalRule.Paths),
+				Threshold: globalRule.Threshold,
+			})
+		case *tufv03.GlobalRuleBlockForcePushes:
+			newRootMetadata.GlobalRules = append(newRootMetadata.GlobalRules, &tufv04.GlobalRuleBlockForcePushes{
+				Name:  globalRule.Name,
+				Type:  globalRule.Type,
+				Paths: migrateFnmatchPatterns(globalRule.Paths),
+			})
+		}
+	}
+
+	// Set propagations
+	newRootMetadata.Propagations = rootMetadata.Propagations
+
+	// Set multi-repository configuration
+	newRootMetadata.MultiRepository = rootMetadata.MultiRepository
+
+	// Set hooks
+	newRootMetadata.Hooks = rootMetadata.Hooks
+
+	// Set key rotations and revocations
+	newRootMetadata.KeyRotations = rootMetadata.KeyRotations
+	newRootMetadata.KeyRevocations = rootMetadata.KeyRevocations
+
+	return newRootMetadata
+}
+
+// MigrateTargetsMetadataV03ToV04 converts tufv03.TargetsMetadata into
+// tufv04.TargetsMetadata. The patterns of rules are converted to the v04
+// pattern syntax.
+func MigrateTargetsMetadataV03ToV04(targetsMetadata *tufv03.TargetsMetadata) *tufv04.TargetsMetadata {
+	newTargetsMetadata := tufv04.NewTargetsMetadata()
+
+	// Set same expires
+	newTargetsMetadata.Expires = targetsMetadata.Expires
+
+	// Set delegations
+	newTargetsMetadata.Delegations = &tufv04.Delegations{
+		Principals:   map[string]tuf.Principal{},
+		Roles:        []*tufv04.Delegation{},
+		KeyRotations: targetsMetadata.Delegations.KeyRotations,
+	}
+	for principalID, principal := range targetsMetadata.Delegations.Principals {
+		newTargetsMetadata.Delegations.Principals[principalID] = principal
+	}
+	for _, role := range targetsMetadata.Delegations.Roles {
+		newRole := &tufv04.Delegation{
+			Name:           role.Name,
+			Paths:          migrateFnmatchPatterns(role.Paths),
+			Terminating:    role.Terminating,
+			Custom:         role.Custom,
+			Role:           role.Role,
+			TeamThresholds: role.TeamThresholds,
+		}
+
+		newTargetsMetadata.Delegations.Roles = append(newTargetsMetadata.Delegations.Roles, newRole)
+	}
+
+	return newTargetsMetadata
+}
+
+// migrateFnmatchPatterns converts fnmatch patterns used until v03 into the v04
+// pattern syntax. In fnmatch patterns, `*` also matches path separators, so
+// each `*` outside a character class is replaced with `**`. Similarly, `?`
+// matches any single character including a path separator, which a single v04
+// pattern can't express, so each pattern with `?` is expanded into one pattern
+// per combination of `?` and `/` in its place. A leading `!` was matched
+// literally, so it's escaped to not negate the migrated pattern.
+func migrateFnmatchPatterns(patterns []string) []string {
+	if patterns == nil {
+		return nil
+	}
+
+	migratedPatterns := make([]string, 0, len(patterns))
+	for _, pattern := range patterns {
+		alternatives := []string{""}
+		appendToAlternatives := func(text string) {
+			for index := range alternatives {
+				alternatives[index] += text
+			}
+		}
+
+		if tuf.IsNegatedPattern(pattern) {
+			appendToAlternatives(`\`)
+		}
+
+		inClass := false
+		for i := 0; i < len(pattern); i++ {
+			switch {
+			case pattern[i] == '\\' && i < len(pattern)-1:
+				appendToAlternatives(pattern[i : i+1])
+				i++
+			case inClass:
+				if pattern[i] == ']' {
+					inClass = false
+				}
+			case pattern[i] == '[' && strings.Contains(pattern[i+1:], "]"):
+				inClass = true
+				// A `]` right after the opening `[` or negation is part of
+				// the class
+				if i+1 < len(pattern) && (pattern[i+1] == '!' || pattern[i+1] == '^') {
+					appendToAlternatives(pattern[i : i+1])
+					i++
+				}
+				if i+1 < len(pattern) && pattern[i+1] == ']' {
+					appendToAlternatives(pattern[i : i+1])
+					i++
+				}
+			case pattern[i] == '*':
+				for i+1 < len(pattern) && pattern[i+1] == '*' {
+					i++
+				}
+				appendToAlternatives("**")
+				continue
+			case pattern[i] == '?':
+				expandedAlternatives := make([]string, 0, 2*len(alternatives))
+				for _, alternative := range alternatives {
+					expandedAlternatives = append(expandedAlternatives, alternative+"?", alternative+"/")
+				}
+				alternatives = expandedAlternatives
+				continue
+			}
+
+			appendToAlternatives(pattern[i : i+1])
+		}
+
+		migratedPatterns = append(migratedPatterns, alternatives...)
+	}
+
+	return migratedPatterns
+}
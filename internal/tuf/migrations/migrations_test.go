@@ -0,0 +1,131 @@
+// Copyright The gittuf Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package migrations
+
+import (
+	"testing"
+
+	"github.com/danwakefield/fnmatch"
+	"github.com/gittuf/gittuf/internal/common/set"
+	"github.com/gittuf/gittuf/internal/tuf"
+	tufv03 "github.com/gittuf/gittuf/internal/tuf/v03"
+	tufv04 "github.com/gittuf/gittuf/internal/tuf/v04"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMigrateFnmatchPatterns(t *testing.T) {
+	patterns := []string{
+		"git:refs/heads/*",
+		"file:src/*.go",
+		"file:**",
+		"file:src/[*]",
+		"file:src/[!*a]*",
+		`file:src/\*`,
+	}
+	expectedPatterns := []string{
+		"git:refs/heads/**",
+		"file:src/**.go",
+		"file:**",
+		"file:src/[*]",
+		"file:src/[!*a]**",
+		`file:src/\*`,
+	}
+	assert.Equal(t, expectedPatterns, migrateFnmatchPatterns(patterns))
+
+	// The migrated patterns match the same targets
+	targets := []string{
+		"git:refs/heads/main",
+		"git:refs/heads/feature/a",
+		"file:src/main.go",
+		"file:src/cmd/main.go",
+		"file:src/*",
+		"file:src/b/c",
+		"file:src/a",
+	}
+	for index, pattern := range patterns {
+		for _, target := range targets {
+			assert.Equal(t, fnmatch.Match(pattern, target, 0), tuf.MatchPatterns([]string{expectedPatterns[index]}, target), "pattern '%s', target '%s'", pattern, target)
+		}
+	}
+}
+
+func TestMigrateFnmatchPatternsWithSingleCharacterWildcards(t *testing.T) {
+	patterns := []string{"file:src?main.go", "git:refs/tags/v?.?"}
+	expectedPatterns := []string{
+		"file:src?main.go",
+		"file:src/main.go",
+		"git:refs/tags/v?.?",
+		"git:refs/tags/v?./",
+		"git:refs/tags/v/.?",
+		"git:refs/tags/v/./",
+	}
+	migratedPatterns := migrateFnmatchPatterns(patterns)
+	assert.Equal(t, expectedPatterns, migratedPatterns)
+
+	// The migrated patterns match the same targets, including those where `?`
+	// matches a path separator
+	targets := []string{
+		"file:src/main.go",
+		"file:srcxmain.go",
+		"file:src/a/main.go",
+		"git:refs/tags/v1.0",
+		"git:refs/tags/v1./",
+		"git:refs/tags/v//./",
+	}
+	for _, target := range targets {
+		assert.Equal(t, fnmatch.Match(patterns[0], target, 0) || fnmatch.Match(patterns[1], target, 0), tuf.MatchPatterns(migratedPatterns, target), "target '%s'", target)
+	}
+}
+
+func TestMigrateFnmatchPatternsWithLeadingExclamationMark(t *testing.T) {
+	patterns := []string{"!file:src/*"}
+	migratedPatterns := migrateFnmatchPatterns(patterns)
+	assert.Equal(t, []string{`\!file:src/**`}, migratedPatterns)
+	assert.Nil(t, tuf.ValidatePatterns(migratedPatterns))
+
+	// The `!` is matched literally rather than negating the pattern
+	for _, target := range []string{"!file:src/a", "file:src/a"} {
+		assert.Equal(t, fnmatch.Match(patterns[0], target, 0), tuf.MatchPatterns(migratedPatterns, target), "target '%s'", target)
+	}
+}
+
+func TestMigrateTargetsMetadataV03ToV04(t *testing.T) {
+	targetsMetadata := tufv03.NewTargetsMetadata()
+	targetsMetadata.Expires = "2030-01-01T00:00:00Z"
+	targetsMetadata.Delegations.Principals = map[string]tuf.Principal{"jane.doe": &tufv03.Person{PersonID: "jane.doe"}}
+	require.Nil(t, targetsMetadata.AddRule("protect-src", []string{"jane.doe"}, []string{"file:src/*"}, 1))
+
+	migratedMetadata := MigrateTargetsMetadataV03ToV04(targetsMetadata)
+	assert.Equal(t, tufv04.TargetsVersion, migratedMetadata.SchemaVersion())
+	assert.Equal(t, targetsMetadata.Expires, migratedMetadata.Expires)
+	assert.Equal(t, targetsMetadata.Delegations.Principals, migratedMetadata.Delegations.Principals)
+	assert.Equal(t, []*tufv04.Delegation{
+		{
+			Name:  "protect-src",
+			Paths: []string{"file:src/**"},
+			Role: tufv04.Role{
+				PrincipalIDs: set.NewSetFromItems("jane.doe"),
+				Threshold:    1,
+			},
+		},
+		tufv04.AllowRule(),
+	}, migratedMetadata.Delegations.Roles)
+	assert.True(t, migratedMetadata.GetRules()[0].Matches("file:src/a/b.go"))
+}
+
+func TestMigrateRootMetadataV03ToV04(t *testing.T) {
+	rootMetadata := tufv03.NewRootMetadata()
+	require.Nil(t, rootMetadata.AddGlobalRule(tufv03.NewGlobalRuleThreshold("threshold", []string{"git:refs/heads/*"}, 2)))
+	blockForcePushes, err := tufv03.NewGlobalRuleBlockForcePushes("block-force-pushes", []string{"git:refs/heads/main"})
+	require.Nil(t, err)
+	require.Nil(t, rootMetadata.AddGlobalRule(blockForcePushes))
+
+	migratedMetadata := MigrateRootMetadataV03ToV04(rootMetadata)
+	assert.Equal(t, tufv04.RootVersion, migratedMetadata.SchemaVersion())
+	assert.Equal(t, []tuf.GlobalRule{
+		&tufv04.GlobalRuleThreshold{Name: "threshold", Type: tuf.GlobalRuleThresholdType, Paths: []string{"git:refs/heads/**"}, Threshold: 2},
+		&tufv04.GlobalRuleBlockForcePushes{Name: "block-force-pushes", Type: tuf.GlobalRuleBlockForcePushesType, Paths: []string{"git:refs/heads/main"}},
+	}, migratedMetadata.GetGlobalRules())
+}
@@ -0,0 +1,236 @@
+// Copyright The gittuf Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package tuf
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+)
+
+// NegatedPatternPrefix marks a pattern that excludes the namespaces it
+// matches.
+const NegatedPatternPrefix = "!"
+
+// IsNegatedPattern indicates if the pattern excludes the namespaces it matches.
+func IsNegatedPattern(pattern string) bool {
+	return strings.HasPrefix(pattern, NegatedPatternPrefix)
+}
+
+// MatchPatterns indicates if the set of patterns matches the target. The
+// target is matched if at least one pattern that isn't negated matches it and
+// no negated pattern matches it. The order of the patterns doesn't matter.
+//
+// Each pattern is a glob where `*` matches any sequence of characters within a
+// path segment, `?` matches a single character within a path segment, `[...]`
+// matches a character class within a path segment, and `**` matches any
+// sequence of characters including path separators. When `**/` starts a path
+// segment, it also matches no directories, so `file:src/**/*.go` matches both
+// `file:src/main.go` and `file:src/cmd/main.go`. A backslash escapes the next
+// character.
+func MatchPatterns(patterns []string, target string) bool {
+	matched := false
+	for _, pattern := range patterns {
+		if IsNegatedPattern(pattern) {
+			if matchGlob(strings.TrimPrefix(pattern, NegatedPatternPrefix), target, true) {
+				return false
+			}
+			continue
+		}
+
+		if !matched && matchGlob(pattern, target, true) {
+			matched = true
+		}
+	}
+
+	return matched
+}
+
+// ValidatePatterns checks that each pattern in the set is well formed and that
+// the set includes at least one pattern that isn't negated.
+func ValidatePatterns(patterns []string) error {
+	hasPatternToMatch := false
+	for _, pattern := range patterns {
+		if !IsNegatedPattern(pattern) {
+			hasPatternToMatch = true
+		}
+
+		if err := validateGlob(strings.TrimPrefix(pattern, NegatedPatternPrefix)); err != nil {
+			return fmt.Errorf("%w '%s': %w", ErrInvalidPattern, pattern, err)
+		}
+	}
+
+	if !hasPatternToMatch {
+		return ErrNoPatternToMatch
+	}
+
+	return nil
+}
+
+// validateGlob checks that the glob isn't empty, that every character class is
+// terminated, and that the glob doesn't end with an unused escape.
+func validateGlob(glob string) error {
+	if glob == "" {
+		return errors.New("pattern is empty")
+	}
+
+	for i := 0; i < len(glob); i++ {
+		switch glob[i] {
+		case '\\':
+			if i == len(glob)-1 {
+				return errors.New("pattern ends with escape character")
+			}
+			i++
+		case '[':
+			end := classEnd(glob[i:])
+			if end == -1 {
+				return errors.New("character class is not terminated")
+			}
+			i += end
+		}
+	}
+
+	return nil
+}
+
+// matchGlob indicates if the glob matches the entire name. segmentStart is set
+// when the glob starts a path segment of the pattern, i.e., at the start of
+// the pattern or after a `/` or the `:` of a namespace prefix.
+func matchGlob(glob, name string, segmentStart bool) bool {
+	for len(glob) > 0 {
+		switch glob[0] {
+		case '*':
+			if strings.HasPrefix(glob, "**") {
+				rest := strings.TrimLeft(glob, "*")
+				if segmentStart && strings.HasPrefix(rest, "/") && matchGlob(rest[1:], name, true) {
+					return true
+				}
+
+				for i := 0; i <= len(name); i++ {
+					if matchGlob(rest, name[i:], false) {
+						return true
+					}
+				}
+				return false
+			}
+
+			rest := glob[1:]
+			for i := 0; i <= len(name); i++ {
+				if matchGlob(rest, name[i:], false) {
+					return true
+				}
+				if i < len(name) && name[i] == '/' {
+					return false
+				}
+			}
+			return false
+
+		case '?':
+			char, width := utf8.DecodeRuneInString(name)
+			if width == 0 || char == '/' {
+				return false
+			}
+			glob, name = glob[1:], name[width:]
+			segmentStart = false
+
+		case '[':
+			end := classEnd(glob)
+			if end == -1 {
+				// Unterminated classes are matched literally
+				if !strings.HasPrefix(name, "[") {
+					return false
+				}
+				glob, name = glob[1:], name[1:]
+				segmentStart = false
+				break
+			}
+
+			char, width := utf8.DecodeRuneInString(name)
+			if width == 0 || char == '/' || !matchClass(glob[1:end], char) {
+				return false
+			}
+			glob, name = glob[end+1:], name[width:]
+			segmentStart = false
+
+		case '\\':
+			if len(glob) > 1 {
+				glob = glob[1:]
+			}
+			fallthrough
+
+		default:
+			if len(name) == 0 || name[0] != glob[0] {
+				return false
+			}
+			segmentStart = glob[0] == '/' || glob[0] == ':'
+			glob, name = glob[1:], name[1:]
+		}
+	}
+
+	return len(name) == 0
+}
+
+// classEnd returns the index of the `]` that terminates the character class
+// at the start of the glob, or -1 if the class isn't terminated. A `]`
+// immediately after the opening `[` or negation is part of the class.
+func classEnd(glob string) int {
+	i := 1
+	if i < len(glob) && (glob[i] == '!' || glob[i] == '^') {
+		i++
+	}
+	if i < len(glob) && glob[i] == ']' {
+		i++
+	}
+
+	for ; i < len(glob); i++ {
+		switch glob[i] {
+		case '\\':
+			i++
+		case ']':
+			return i
+		}
+	}
+
+	return -1
+}
+
+// matchClass indicates if the character is in the class, whose contents are
+// the characters between the enclosing brackets.
+func matchClass(class string, char rune) bool {
+	negated := false
+	if len(class) > 0 && (class[0] == '!' || class[0] == '^') {
+		negated = true
+		class = class[1:]
+	}
+
+	matched := false
+	for len(class) > 0 {
+		low, width := nextClassChar(class)
+		class = class[width:]
+
+		high := low
+		if len(class) > 1 && class[0] == '-' {
+			high, width = nextClassChar(class[1:])
+			class = class[1+width:]
+		}
+
+		if low <= char && char <= high {
+			matched = true
+		}
+	}
+
+	return matched != negated
+}
+
+// nextClassChar returns the next, possibly escaped, character in the class
+// and the number of bytes it uses.
+func nextClassChar(class string) (rune, int) {
+	if class[0] == '\\' && len(class) > 1 {
+		char, width := utf8.DecodeRuneInString(class[1:])
+		return char, width + 1
+	}
+
+	return utf8.DecodeRuneInString(class)
+}
@@ -0,0 +1,126 @@
+// Copyright The gittuf Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package tuf
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMatchPatterns(t *testing.T) {
+	tests := map[string]struct {
+		patterns []string
+		target   string
+		expected bool
+	}{
+		"exact match": {
+			patterns: []string{"git:refs/heads/main"},
+			target:   "git:refs/heads/main",
+			expected: true,
+		},
+		"single star matches within segment": {
+			patterns: []string{"git:refs/heads/*"},
+			target:   "git:refs/heads/main",
+			expected: true,
+		},
+		"single star does not match across segments": {
+			patterns: []string{"git:refs/heads/*"},
+			target:   "git:refs/heads/feature/a",
+			expected: false,
+		},
+		"double star matches across segments": {
+			patterns: []string{"git:refs/heads/**"},
+			target:   "git:refs/heads/feature/a",
+			expected: true,
+		},
+		"double star matches everything": {
+			patterns: []string{"**"},
+			target:   "file:src/a/b.go",
+			expected: true,
+		},
+		"double star directory matches no directories": {
+			patterns: []string{"file:src/**/*.go"},
+			target:   "file:src/main.go",
+			expected: true,
+		},
+		"double star directory matches nested directories": {
+			patterns: []string{"file:src/**/*.go"},
+			target:   "file:src/cmd/tool/main.go",
+			expected: true,
+		},
+		"double star directory after namespace": {
+			patterns: []string{"file:**/generated/*"},
+			target:   "file:generated/a.go",
+			expected: true,
+		},
+		"question mark does not match separator": {
+			patterns: []string{"file:src?a"},
+			target:   "file:src/a",
+			expected: false,
+		},
+		"character class": {
+			patterns: []string{"git:refs/tags/v[0-9]*"},
+			target:   "git:refs/tags/v1.0.0",
+			expected: true,
+		},
+		"negated character class": {
+			patterns: []string{"git:refs/tags/v[!0-9]*"},
+			target:   "git:refs/tags/v1.0.0",
+			expected: false,
+		},
+		"escaped star is literal": {
+			patterns: []string{`file:src/\*`},
+			target:   "file:src/a",
+			expected: false,
+		},
+		"negated pattern excludes": {
+			patterns: []string{"file:src/**", "!file:src/generated/**"},
+			target:   "file:src/generated/a.go",
+			expected: false,
+		},
+		"negated pattern does not exclude others": {
+			patterns: []string{"file:src/**", "!file:src/generated/**"},
+			target:   "file:src/a.go",
+			expected: true,
+		},
+		"negated pattern order does not matter": {
+			patterns: []string{"!file:src/generated/**", "file:src/**"},
+			target:   "file:src/generated/a.go",
+			expected: false,
+		},
+		"negated pattern for git namespace": {
+			patterns: []string{"git:refs/heads/**", "!git:refs/heads/users/**"},
+			target:   "git:refs/heads/users/alice/a",
+			expected: false,
+		},
+		"only negated patterns match nothing": {
+			patterns: []string{"!file:src/generated/**"},
+			target:   "file:src/a.go",
+			expected: false,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, test.expected, MatchPatterns(test.patterns, test.target))
+		})
+	}
+}
+
+func TestValidatePatterns(t *testing.T) {
+	assert.Nil(t, ValidatePatterns([]string{"file:src/**", "!file:src/generated/**", "git:refs/tags/v[0-9]*"}))
+
+	err := ValidatePatterns([]string{"!file:src/generated/**"})
+	assert.ErrorIs(t, err, ErrNoPatternToMatch)
+
+	err = ValidatePatterns([]string{"file:src/**", "!"})
+	assert.ErrorIs(t, err, ErrInvalidPattern)
+
+	err = ValidatePatterns([]string{"git:refs/tags/v[0-9*"})
+	assert.ErrorIs(t, err, ErrInvalidPattern)
+
+	err = ValidatePatterns([]string{`file:src\`})
+	assert.ErrorIs(t, err, ErrInvalidPattern)
+}
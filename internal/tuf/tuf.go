@@ -6,6 +6,7 @@ package tuf
 import (
 	"encoding/json"
 	"errors"
+	"time"
 
 	"github.com/gittuf/gittuf/internal/common/set"
 	"github.com/gittuf/gittuf/internal/gitinterface"
@@ -28,9 +29,15 @@ const (
 	GittufPrefix           = "gittuf-"
 	GittufControllerPrefix = "gittuf-controller"
 
-	GlobalRuleThresholdType        = "threshold"
-	GlobalRuleBlockForcePushesType = "block-force-pushes"
-	RemoveGlobalRuleType           = "remove"
+	GlobalRuleThresholdType            = "threshold"
+	GlobalRuleBlockForcePushesType     = "block-force-pushes"
+	GlobalRuleRequireSignedCommitsType = "require-signed-commits"
+	GlobalRuleMergeStrategyType        = "merge-strategy"
+	GlobalRuleRequireHookExecutionType = "require-hook-execution"
+	RemoveGlobalRuleType               = "remove"
+
+	MergeStrategyLinearHistory = "linear-history"
+	MergeStrategyMergeCommit   = "merge-commit"
 
 	HookStagePreCommitString = "preCommit"
 	HookStagePrePushString   = "prePush"
@@ -44,34 +51,50 @@ const (
 )
 
 var (
-	ErrInvalidRootMetadata                             = errors.New("invalid root metadata")
-	ErrUnknownRootMetadataVersion                      = errors.New("unknown schema version for root metadata")
-	ErrUnknownTargetsMetadataVersion                   = errors.New("unknown schema version for rule file metadata")
-	ErrPrimaryRuleFileInformationNotFoundInRoot        = errors.New("root metadata does not contain primary rule file information")
-	ErrGitHubAppInformationNotFoundInRoot              = errors.New("the special GitHub app role is not defined, but GitHub app approvals is set to trusted")
-	ErrDuplicatedRuleName                              = errors.New("two rules with same name found in policy")
-	ErrDuplicateControllerRepository                   = errors.New("controller repository already exists")
-	ErrDuplicateNetworkRepository                      = errors.New("network repository already exists")
-	ErrInvalidPrincipalID                              = errors.New("principal ID is invalid")
-	ErrInvalidPrincipalType                            = errors.New("invalid principal type (do you have the right gittuf version?)")
-	ErrPrincipalNotFound                               = errors.New("principal not found")
-	ErrPrincipalStillInUse                             = errors.New("principal is still in use")
-	ErrRuleNotFound                                    = errors.New("cannot find rule entry")
-	ErrMissingRules                                    = errors.New("some rules are missing")
-	ErrCannotManipulateRulesWithGittufPrefix           = errors.New("cannot add or change rules whose names have the 'gittuf-' prefix")
-	ErrCannotMeetThreshold                             = errors.New("insufficient keys to meet threshold")
-	ErrUnknownGlobalRuleType                           = errors.New("unknown global rule type")
-	ErrGlobalRuleBlockForcePushesOnlyAppliesToGitPaths = errors.New("all patterns for block force pushes global rule must be for Git references")
-	ErrGlobalRuleNotFound                              = errors.New("global rule not found")
-	ErrGlobalRuleAlreadyExists                         = errors.New("global rule already exists")
-	ErrCannotUpdateGlobalRuleType                      = errors.New("cannot change type of global rule")
-	ErrPropagationDirectiveNotFound                    = errors.New("specified propagation directive not found")
-	ErrNotAControllerRepository                        = errors.New("current repository is not marked as a controller repository")
-	ErrDuplicatedHookName                              = errors.New("two hooks with same name found in policy")
-	ErrInvalidHookStage                                = errors.New("invalid stage for hook")
-	ErrInvalidHookEnvironment                          = errors.New("invalid environment for hook")
-	ErrHookNotFound                                    = errors.New("cannot find hook entry")
-	ErrNoHooksDefined                                  = errors.New("no hooks defined")
+	ErrInvalidRootMetadata                                 = errors.New("invalid root metadata")
+	ErrUnknownRootMetadataVersion                          = errors.New("unknown schema version for root metadata")
+	ErrUnknownTargetsMetadataVersion                       = errors.New("unknown schema version for rule file metadata")
+	ErrPrimaryRuleFileInformationNotFoundInRoot            = errors.New("root metadata does not contain primary rule file information")
+	ErrGitHubAppInformationNotFoundInRoot                  = errors.New("the special GitHub app role is not defined, but GitHub app approvals is set to trusted")
+	ErrDuplicatedRuleName                                  = errors.New("two rules with same name found in policy")
+	ErrDuplicateControllerRepository                       = errors.New("controller repository already exists")
+	ErrDuplicateNetworkRepository                          = errors.New("network repository already exists")
+	ErrInvalidPrincipalID                                  = errors.New("principal ID is invalid")
+	ErrInvalidPrincipalType                                = errors.New("invalid principal type (do you have the right gittuf version?)")
+	ErrPrincipalNotFound                                   = errors.New("principal not found")
+	ErrPrincipalStillInUse                                 = errors.New("principal is still in use")
+	ErrTeamNotFound                                        = errors.New("team not found")
+	ErrRuleNotFound                                        = errors.New("cannot find rule entry")
+	ErrMissingRules                                        = errors.New("some rules are missing")
+	ErrCannotManipulateRulesWithGittufPrefix               = errors.New("cannot add or change rules whose names have the 'gittuf-' prefix")
+	ErrCannotMeetThreshold                                 = errors.New("insufficient keys to meet threshold")
+	ErrUnknownGlobalRuleType                               = errors.New("unknown global rule type")
+	ErrGlobalRuleBlockForcePushesOnlyAppliesToGitPaths     = errors.New("all patterns for block force pushes global rule must be for Git references")
+	ErrGlobalRuleRequireSignedCommitsOnlyAppliesToGitPaths = errors.New("all patterns for require signed commits global rule must be for Git references")
+	ErrGlobalRuleMergeStrategyOnlyAppliesToGitPaths        = errors.New("all patterns for merge strategy global rule must be for Git references")
+	ErrInvalidMergeStrategy                                = errors.New("invalid merge strategy, must be one of 'linear-history' or 'merge-commit'")
+	ErrGlobalRuleRequireHookExecutionOnlyAppliesToGitPaths = errors.New("all patterns for require hook execution global rule must be for Git references")
+	ErrGlobalRuleNotFound                                  = errors.New("global rule not found")
+	ErrGlobalRuleAlreadyExists                             = errors.New("global rule already exists")
+	ErrCannotUpdateGlobalRuleType                          = errors.New("cannot change type of global rule")
+	ErrPropagationDirectiveNotFound                        = errors.New("specified propagation directive not found")
+	ErrNotAControllerRepository                            = errors.New("current repository is not marked as a controller repository")
+	ErrDuplicatedHookName                                  = errors.New("two hooks with same name found in policy")
+	ErrInvalidHookStage                                    = errors.New("invalid stage for hook")
+	ErrInvalidHookEnvironment                              = errors.New("invalid environment for hook")
+	ErrHookNotFound                                        = errors.New("cannot find hook entry")
+	ErrNoHooksDefined                                      = errors.New("no hooks defined")
+	ErrKeyNotFound                                         = errors.New("key not found")
+	ErrKeyRotationNotSupported                             = errors.New("key rotation is not supported by this metadata schema version")
+	ErrKeyRevocationNotSupported                           = errors.New("key revocation is not supported by this metadata schema version")
+	ErrInvalidKeyRevocation                                = errors.New("key revocation must specify exactly one of an RSL entry or a time after which the key is revoked")
+	ErrInvalidPattern                                      = errors.New("invalid pattern")
+	ErrNoPatternToMatch                                    = errors.New("at least one pattern must not be negated")
+	ErrInvalidRefAction                                    = errors.New("invalid reference action, must be one of 'create', 'fast-forward', 'non-fast-forward', or 'delete'")
+	ErrRefActionsOnlyApplyToGitPaths                       = errors.New("all patterns for a rule with reference actions must be for Git references")
+	ErrRefActionsNotSupported                              = errors.New("reference actions in rules are not supported by this metadata schema version")
+	ErrInvalidValidityWindow                               = errors.New("validity window must end after it begins")
+	ErrValidityWindowsNotSupported                         = errors.New("validity windows are not supported by this metadata schema version")
 )
 
 // Principal represents an entity that is granted trust by gittuf metadata. In
@@ -84,12 +107,32 @@ type Principal interface {
 	CustomMetadata() map[string]string
 }
 
+// Team represents a group of principals that is trusted as a single unit. A
+// team is counted towards a threshold when a threshold of its members approve.
+type Team interface {
+	Principal
+
+	// GetMembers returns the principals that are members of the team.
+	GetMembers() []Principal
+	// GetThreshold returns the number of members who must approve for the
+	// team to be counted towards a threshold.
+	GetThreshold() int
+}
+
 // RootMetadata represents the root of trust metadata for gittuf.
 type RootMetadata interface {
 	// SetExpires sets the expiry time for the metadata.
-	// TODO: Does expiry make sense for the gittuf context? This is currently
-	// unenforced
 	SetExpires(expiry string)
+	// GetExpires returns the expiry time for the metadata. The expiry is
+	// enforced against the time an RSL entry is recorded.
+	GetExpires() string
+	// GetMetadataVersion returns the version of the metadata. The version is
+	// incremented each time the metadata is changed, so that an older version
+	// of the metadata cannot be reapplied. Metadata using a schema version
+	// that doesn't record versions is at version 0.
+	GetMetadataVersion() int
+	// IncrementMetadataVersion increments the version of the metadata.
+	IncrementMetadataVersion()
 
 	// SchemaVersion returns the metadata schema version.
 	SchemaVersion() string
@@ -136,6 +179,27 @@ type RootMetadata interface {
 	// sign the primary rule file.
 	GetPrimaryRuleFileThreshold() (int, error)
 
+	// RotateRootKey replaces the key identified by oldKeyID with newKey for
+	// the principal trusted for the root of trust that holds it. If
+	// gracePeriodEnd is set, the old key continues to be trusted for the
+	// principal until then.
+	RotateRootKey(oldKeyID string, newKey Principal, gracePeriodEnd time.Time) error
+	// RotatePrimaryRuleFileKey replaces the key identified by oldKeyID with
+	// newKey for the principal trusted for the primary rule file that holds
+	// it. If gracePeriodEnd is set, the old key continues to be trusted for
+	// the principal until then.
+	RotatePrimaryRuleFileKey(oldKeyID string, newKey Principal, gracePeriodEnd time.Time) error
+	// GetKeyRotations returns the key rotations recorded in the root metadata.
+	GetKeyRotations() []KeyRotation
+
+	// RevokeKey records that signatures made using key after the RSL entry
+	// identified by revokedAfterEntryID, or after revokedAfterTime, are
+	// invalid. Exactly one of the two must be set.
+	RevokeKey(key Principal, revokedAfterEntryID string, revokedAfterTime time.Time) error
+	// GetKeyRevocations returns the key revocations recorded in the root
+	// metadata.
+	GetKeyRevocations() []KeyRevocation
+
 	// AddGlobalRule adds the corresponding rule to the root metadata.
 	AddGlobalRule(globalRule GlobalRule) error
 	// GetGlobalRules returns the global rules declared in the root metadata.
@@ -220,9 +284,17 @@ type RootMetadata interface {
 // TargetsMetadata represents gittuf's rule files. Its name is inspired by TUF.
 type TargetsMetadata interface {
 	// SetExpires sets the expiry time for the metadata.
-	// TODO: Does expiry make sense for the gittuf context? This is currently
-	// unenforced
 	SetExpires(expiry string)
+	// GetExpires returns the expiry time for the metadata. The expiry is
+	// enforced against the time an RSL entry is recorded.
+	GetExpires() string
+	// GetMetadataVersion returns the version of the metadata. The version is
+	// incremented each time the metadata is changed, so that an older version
+	// of the metadata cannot be reapplied. Metadata using a schema version
+	// that doesn't record versions is at version 0.
+	GetMetadataVersion() int
+	// IncrementMetadataVersion increments the version of the metadata.
+	IncrementMetadataVersion()
 
 	// SchemaVersion returns the metadata schema version.
 	SchemaVersion() string
@@ -238,6 +310,18 @@ type TargetsMetadata interface {
 	// UpdateRule updates an existing rule identified by ruleName with the
 	// provided parameters.
 	UpdateRule(ruleName string, authorizedPrincipalIDs, rulePatterns []string, threshold int) error
+	// UpdateRuleTeamThreshold sets the number of members of the team that
+	// must approve for the team to count towards the threshold of the rule
+	// identified by ruleName.
+	UpdateRuleTeamThreshold(ruleName, teamID string, threshold int) error
+	// UpdateRuleActions sets the actions on Git references that the rule
+	// identified by ruleName applies to. If no actions are specified, the
+	// rule applies to all actions.
+	UpdateRuleActions(ruleName string, actions []RefAction) error
+	// UpdateRuleValidityWindow sets the period during which the rule
+	// identified by ruleName is trusted. If window is nil, the rule is
+	// trusted at all times.
+	UpdateRuleValidityWindow(ruleName string, window *ValidityWindow) error
 	// ReorderRules accepts the new order of rules (identified by their
 	// ruleNames).
 	ReorderRules(newRuleNames []string) error
@@ -249,6 +333,21 @@ type TargetsMetadata interface {
 
 	// RemovePrincipal removes a principal from the metadata.
 	RemovePrincipal(principalID string) error
+
+	// UpdatePrincipalValidityWindow sets the period during which the
+	// principal identified by principalID is trusted by the rules in the
+	// metadata. If window is nil, the principal is trusted at all times.
+	UpdatePrincipalValidityWindow(principalID string, window *ValidityWindow) error
+	// GetPrincipalValidityWindows returns the validity windows of the
+	// principals in the rule file, keyed by principal ID.
+	GetPrincipalValidityWindows() map[string]*ValidityWindow
+
+	// RotatePersonKey replaces the key identified by oldKeyID with newKey for
+	// the person identified by personID. If gracePeriodEnd is set, the old key
+	// continues to be trusted for the person until then.
+	RotatePersonKey(personID, oldKeyID string, newKey Principal, gracePeriodEnd time.Time) error
+	// GetKeyRotations returns the key rotations recorded in the rule file.
+	GetKeyRotations() []KeyRotation
 }
 
 // Rule represents a rule entry in a rule file (`TargetsMetadata`).
@@ -269,6 +368,18 @@ type Rule interface {
 	// GetThreshold returns the threshold of principals that must approve to
 	// meet the rule.
 	GetThreshold() int
+	// GetTeamThresholds returns the number of members of each team trusted
+	// by the rule that must approve for the team to count towards the rule's
+	// threshold. Teams without an entry use the threshold in their
+	// definition.
+	GetTeamThresholds() map[string]int
+	// GetAllowedActions returns the actions on Git references that the rule
+	// applies to. If no actions are returned, the rule applies to all
+	// actions.
+	GetAllowedActions() []RefAction
+	// GetValidityWindow returns the period during which the rule is trusted.
+	// If no window is returned, the rule is trusted at all times.
+	GetValidityWindow() *ValidityWindow
 
 	// IsLastTrustedInRuleFile indicates that subsequent rules in the rule file
 	// are not to be trusted if the current rule matches the namespace under
@@ -278,6 +389,37 @@ type Rule interface {
 	IsLastTrustedInRuleFile() bool
 }
 
+// KeyRotation records that a principal's key was replaced with another key.
+// The old key continues to be trusted for the principal until the end of the
+// grace period, so that changes signed with it before the rotation can still
+// be verified.
+type KeyRotation interface {
+	// GetPrincipalID returns the identifier of the principal the old key is
+	// trusted for during the grace period.
+	GetPrincipalID() string
+	// GetOldKey returns the key that was replaced.
+	GetOldKey() *signerverifier.SSLibKey
+	// GetNewKeyID returns the identifier of the key that replaced the old key.
+	GetNewKeyID() string
+	// GetGracePeriodEnd returns the time until which the old key is trusted.
+	GetGracePeriodEnd() time.Time
+}
+
+// KeyRevocation records that a key is compromised. Signatures made using the
+// key after the revocation point are invalid, even when verifying RSL entries
+// using policy states from before the key was revoked.
+type KeyRevocation interface {
+	// GetKey returns the revoked key.
+	GetKey() *signerverifier.SSLibKey
+	// GetRevokedAfterEntryID returns the identifier of the RSL entry after
+	// which the key is revoked. It's empty if the key is revoked after a
+	// time instead.
+	GetRevokedAfterEntryID() string
+	// GetRevokedAfterTime returns the time after which the key is revoked. It's
+	// the zero time if the key is revoked after an RSL entry instead.
+	GetRevokedAfterTime() time.Time
+}
+
 // GlobalRule represents a repository-wide constraint set by the owners in the
 // root metadata.
 type GlobalRule interface {
@@ -313,6 +455,62 @@ type GlobalRuleBlockForcePushes interface {
 	// GetProtectedNamespaces returns the set of namespaces protected by the
 	// rule.
 	GetProtectedNamespaces() []string
+
+	// BlocksForcePushes distinguishes the rule from other global rules with
+	// the same methods. It always returns true.
+	BlocksForcePushes() bool
+}
+
+// GlobalRuleRequireSignedCommits requires every commit introduced to the
+// specified namespaces to be signed by a principal trusted in the policy.
+type GlobalRuleRequireSignedCommits interface {
+	GlobalRule
+
+	// Matches indicates if the rule applies to a specified path.
+	Matches(path string) bool
+
+	// GetProtectedNamespaces returns the set of namespaces protected by the
+	// rule.
+	GetProtectedNamespaces() []string
+
+	// RequiresSignedCommits distinguishes the rule from other global rules
+	// with the same methods. It always returns true.
+	RequiresSignedCommits() bool
+}
+
+// GlobalRuleMergeStrategy requires updates to the specified namespaces to
+// follow a merge strategy. With the linear history strategy, no merge commits
+// may be introduced. With the merge commit strategy, every update must be a
+// merge commit whose first parent is the prior tip of the reference.
+type GlobalRuleMergeStrategy interface {
+	GlobalRule
+
+	// Matches indicates if the rule applies to a specified path.
+	Matches(path string) bool
+
+	// GetProtectedNamespaces returns the set of namespaces protected by the
+	// rule.
+	GetProtectedNamespaces() []string
+
+	// GetMergeStrategy returns the merge strategy required by the rule.
+	GetMergeStrategy() string
+}
+
+// GlobalRuleRequireHookExecution requires updates to the specified namespaces
+// to have a passing run of each of the named hooks, as recorded in a hook
+// execution attestation signed by a principal the hook is run by.
+type GlobalRuleRequireHookExecution interface {
+	GlobalRule
+
+	// Matches indicates if the rule applies to a specified path.
+	Matches(path string) bool
+
+	// GetProtectedNamespaces returns the set of namespaces protected by the
+	// rule.
+	GetProtectedNamespaces() []string
+
+	// GetHookNames returns the names of the hooks that must have passed.
+	GetHookNames() []string
 }
 
 // PropagationDirective represents an instruction to a gittuf client to carry
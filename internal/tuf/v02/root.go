@@ -7,6 +7,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"path"
+	"time"
 
 	"github.com/gittuf/gittuf/internal/common/set"
 	"github.com/gittuf/gittuf/internal/tuf"
@@ -45,6 +46,22 @@ func (r *RootMetadata) SetExpires(expires string) {
 	r.Expires = expires
 }
 
+// GetExpires returns the expiry date of the RootMetadata.
+func (r *RootMetadata) GetExpires() string {
+	return r.Expires
+}
+
+// GetMetadataVersion returns the version of the metadata. v02 does not record
+// metadata versions.
+func (r *RootMetadata) GetMetadataVersion() int {
+	return 0
+}
+
+// IncrementMetadataVersion increments the version of the metadata. v02 does
+// not record metadata versions, so the metadata must be migrated to a newer
+// schema version first.
+func (r *RootMetadata) IncrementMetadataVersion() {}
+
 // SchemaVersion returns the metadata schema version.
 func (r *RootMetadata) SchemaVersion() string {
 	return r.Version
@@ -285,6 +302,36 @@ func (r *RootMetadata) GetRootPrincipals() ([]tuf.Principal, error) {
 	return principals, nil
 }
 
+// RotateRootKey replaces the key trusted for the root of trust. v02 does not
+// support key rotations.
+func (r *RootMetadata) RotateRootKey(_ string, _ tuf.Principal, _ time.Time) error {
+	return tuf.ErrKeyRotationNotSupported
+}
+
+// RotatePrimaryRuleFileKey replaces the key trusted for the primary rule file.
+// v02 does not support key rotations.
+func (r *RootMetadata) RotatePrimaryRuleFileKey(_ string, _ tuf.Principal, _ time.Time) error {
+	return tuf.ErrKeyRotationNotSupported
+}
+
+// GetKeyRotations returns the key rotations recorded in the root metadata.
+// v02 does not support key rotations.
+func (r *RootMetadata) GetKeyRotations() []tuf.KeyRotation {
+	return nil
+}
+
+// RevokeKey records that signatures made using the key after a point in the RSL
+// are invalid. v02 does not support key revocations.
+func (r *RootMetadata) RevokeKey(_ tuf.Principal, _ string, _ time.Time) error {
+	return tuf.ErrKeyRevocationNotSupported
+}
+
+// GetKeyRevocations returns the key revocations recorded in the root metadata.
+// v02 does not support key revocations.
+func (r *RootMetadata) GetKeyRevocations() []tuf.KeyRevocation {
+	return nil
+}
+
 // GetPrimaryRuleFileThreshold returns the threshold of principals that must
 // sign the primary rule file.
 func (r *RootMetadata) GetPrimaryRuleFileThreshold() (int, error) {
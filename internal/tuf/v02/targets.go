@@ -8,6 +8,7 @@ import (
 	"errors"
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/danwakefield/fnmatch"
 	"github.com/gittuf/gittuf/internal/common/set"
@@ -44,6 +45,22 @@ func (t *TargetsMetadata) SetExpires(expires string) {
 	t.Expires = expires
 }
 
+// GetExpires returns the expiry date of the TargetsMetadata.
+func (t *TargetsMetadata) GetExpires() string {
+	return t.Expires
+}
+
+// GetMetadataVersion returns the version of the metadata. v02 does not record
+// metadata versions.
+func (t *TargetsMetadata) GetMetadataVersion() int {
+	return 0
+}
+
+// IncrementMetadataVersion increments the version of the metadata. v02 does
+// not record metadata versions, so the metadata must be migrated to a newer
+// schema version first.
+func (t *TargetsMetadata) IncrementMetadataVersion() {}
+
 // SchemaVersion returns the metadata schema version.
 func (t *TargetsMetadata) SchemaVersion() string {
 	return t.Version
@@ -134,6 +151,49 @@ func (t *TargetsMetadata) UpdateRule(ruleName string, authorizedPrincipalIDs, ru
 	return nil
 }
 
+// UpdateRuleActions sets the actions on Git references that the rule applies
+// to. v02 does not support reference actions.
+func (t *TargetsMetadata) UpdateRuleActions(_ string, _ []tuf.RefAction) error {
+	return tuf.ErrRefActionsNotSupported
+}
+
+// UpdateRuleValidityWindow sets the period during which the rule is trusted.
+// v02 does not support validity windows.
+func (t *TargetsMetadata) UpdateRuleValidityWindow(_ string, _ *tuf.ValidityWindow) error {
+	return tuf.ErrValidityWindowsNotSupported
+}
+
+// UpdatePrincipalValidityWindow sets the period during which the principal is
+// trusted. v02 does not support validity windows.
+func (t *TargetsMetadata) UpdatePrincipalValidityWindow(_ string, _ *tuf.ValidityWindow) error {
+	return tuf.ErrValidityWindowsNotSupported
+}
+
+// GetPrincipalValidityWindows returns the validity windows of the principals in
+// the rule file. v02 does not support validity windows.
+func (t *TargetsMetadata) GetPrincipalValidityWindows() map[string]*tuf.ValidityWindow {
+	return nil
+}
+
+// UpdateRuleTeamThreshold sets the number of members of the team that must
+// approve for the team to count towards the threshold of the rule. v02 does not
+// support teams.
+func (t *TargetsMetadata) UpdateRuleTeamThreshold(_, teamID string, _ int) error {
+	return fmt.Errorf("%w: '%s'", tuf.ErrTeamNotFound, teamID)
+}
+
+// RotatePersonKey replaces the key of a person in the rule file. v02 does not
+// support key rotations.
+func (t *TargetsMetadata) RotatePersonKey(_, _ string, _ tuf.Principal, _ time.Time) error {
+	return tuf.ErrKeyRotationNotSupported
+}
+
+// GetKeyRotations returns the key rotations recorded in the rule file. v02
+// does not support key rotations.
+func (t *TargetsMetadata) GetKeyRotations() []tuf.KeyRotation {
+	return nil
+}
+
 // ReorderRules changes the order of delegations, and the new order is specified
 // in `ruleNames []string`.
 func (t *TargetsMetadata) ReorderRules(ruleNames []string) error {
@@ -384,6 +444,25 @@ func (d *Delegation) GetThreshold() int {
 	return d.Threshold
 }
 
+// GetTeamThresholds returns the per-team thresholds of the rule. v02 does not
+// support teams.
+func (d *Delegation) GetTeamThresholds() map[string]int {
+	return nil
+}
+
+// GetAllowedActions returns the actions on Git references that the rule applies
+// to. v02 does not support reference actions, so the rule applies to all
+// actions.
+func (d *Delegation) GetAllowedActions() []tuf.RefAction {
+	return nil
+}
+
+// GetValidityWindow returns the period during which the rule is trusted. v02
+// does not support validity windows, so the rule is trusted at all times.
+func (d *Delegation) GetValidityWindow() *tuf.ValidityWindow {
+	return nil
+}
+
 // IsLastTrustedInRuleFile indicates that subsequent rules in the rule file are
 // not to be trusted if the current rule matches the namespace under
 // verification (similar to TUF's terminating behavior). However, the current
@@ -0,0 +1,387 @@
+// Copyright The gittuf Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package v03
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/gittuf/gittuf/internal/common/set"
+	"github.com/gittuf/gittuf/internal/tuf"
+	tufv02 "github.com/gittuf/gittuf/internal/tuf/v02"
+)
+
+const (
+	RootVersion = "https://gittuf.dev/policy/root/v0.3"
+)
+
+// RootMetadata defines the schema of TUF's Root role. It extends the v02
+// schema with support for teams as principals and records of key rotations and
+// revocations.
+type RootMetadata struct {
+	tufv02.RootMetadata
+	KeyRotations   []*KeyRotation   `json:"keyRotations,omitempty"`
+	KeyRevocations []*KeyRevocation `json:"keyRevocations,omitempty"`
+}
+
+// NewRootMetadata returns a new instance of RootMetadata.
+func NewRootMetadata() *RootMetadata {
+	return &RootMetadata{
+		RootMetadata: tufv02.RootMetadata{
+			Type:    "root",
+			Version: RootVersion,
+		},
+	}
+}
+
+// AddRootPrincipal adds the specified principal to the root metadata and
+// authorizes the principal for the root role.
+func (r *RootMetadata) AddRootPrincipal(principal tuf.Principal) error {
+	if principal == nil {
+		return tuf.ErrInvalidPrincipalType
+	}
+
+	// Add principal to metadata
+	if err := r.addPrincipal(principal); err != nil {
+		return err
+	}
+
+	rootRole, ok := r.Roles[tuf.RootRoleName]
+	if !ok {
+		// Create a new root role entry with this principal
+		r.addRole(tuf.RootRoleName, Role{
+			PrincipalIDs: set.NewSetFromItems(principal.ID()),
+			Threshold:    1,
+		})
+
+		return nil
+	}
+
+	// Add principal ID to the root role if it's not already in it
+	rootRole.PrincipalIDs.Add(principal.ID())
+	r.Roles[tuf.RootRoleName] = rootRole
+	return nil
+}
+
+// AddPrimaryRuleFilePrincipal adds the 'principal' as a trusted signer in
+// 'rootMetadata' for the top level Targets role.
+func (r *RootMetadata) AddPrimaryRuleFilePrincipal(principal tuf.Principal) error {
+	if principal == nil {
+		return tuf.ErrInvalidPrincipalType
+	}
+
+	// Add principal to the metadata file
+	if err := r.addPrincipal(principal); err != nil {
+		return err
+	}
+
+	targetsRole, ok := r.Roles[tuf.TargetsRoleName]
+	if !ok {
+		// Create a new targets role entry with this principal
+		r.addRole(tuf.TargetsRoleName, Role{
+			PrincipalIDs: set.NewSetFromItems(principal.ID()),
+			Threshold:    1,
+		})
+
+		return nil
+	}
+
+	targetsRole.PrincipalIDs.Add(principal.ID())
+	r.Roles[tuf.TargetsRoleName] = targetsRole
+
+	return nil
+}
+
+// RotateRootKey replaces the key identified by oldKeyID with newKey for the
+// root principal that holds it. If the principal is the key itself, the new key
+// replaces it in the root role. If the principal is a person, the person's key
+// is replaced wherever the person is trusted in the root metadata. If
+// gracePeriodEnd is set, the old key continues to be trusted for the principal
+// until then.
+func (r *RootMetadata) RotateRootKey(oldKeyID string, newKey tuf.Principal, gracePeriodEnd time.Time) error {
+	return r.rotateRoleKey(tuf.RootRoleName, oldKeyID, newKey, gracePeriodEnd)
+}
+
+// RotatePrimaryRuleFileKey replaces the key identified by oldKeyID with newKey
+// for the primary rule file principal that holds it. If the principal is the
+// key itself, the new key replaces it in the primary rule file role. If the
+// principal is a person, the person's key is replaced wherever the person is
+// trusted in the root metadata. If gracePeriodEnd is set, the old key continues
+// to be trusted for the principal until then.
+func (r *RootMetadata) RotatePrimaryRuleFileKey(oldKeyID string, newKey tuf.Principal, gracePeriodEnd time.Time) error {
+	return r.rotateRoleKey(tuf.TargetsRoleName, oldKeyID, newKey, gracePeriodEnd)
+}
+
+// GetKeyRotations returns the key rotations recorded in the root metadata.
+func (r *RootMetadata) GetKeyRotations() []tuf.KeyRotation {
+	keyRotations := make([]tuf.KeyRotation, 0, len(r.KeyRotations))
+	for _, keyRotation := range r.KeyRotations {
+		keyRotations = append(keyRotations, keyRotation)
+	}
+
+	return keyRotations
+}
+
+// rotateRoleKey replaces the key identified by oldKeyID with newKey for the
+// principal trusted for roleName that holds it.
+func (r *RootMetadata) rotateRoleKey(roleName, oldKeyID string, newKey tuf.Principal, gracePeriodEnd time.Time) error {
+	key, isKey := newKey.(*Key)
+	if !isKey {
+		return tuf.ErrInvalidPrincipalType
+	}
+
+	role, has := r.Roles[roleName]
+	if !has {
+		if roleName == tuf.TargetsRoleName {
+			return tuf.ErrPrimaryRuleFileInformationNotFoundInRoot
+		}
+		return tuf.ErrInvalidRootMetadata
+	}
+
+	for _, principalID := range role.PrincipalIDs.Contents() {
+		switch principal := r.Principals[principalID].(type) {
+		case *Key:
+			if principal.KeyID != oldKeyID {
+				continue
+			}
+
+			// The key is trusted directly, so the new key takes its place in
+			// the role
+			if role.PrincipalIDs.Has(key.KeyID) {
+				return fmt.Errorf("%w: '%s' is already trusted", tuf.ErrInvalidPrincipalID, key.KeyID)
+			}
+			if err := r.addPrincipal(key); err != nil {
+				return err
+			}
+			role.PrincipalIDs.Remove(oldKeyID)
+			role.PrincipalIDs.Add(key.KeyID)
+			r.Roles[roleName] = role
+
+			r.recordKeyRotation(newKeyRotation(key.KeyID, principal, key, gracePeriodEnd))
+			return nil
+
+		case *Person:
+			oldKey, has := principal.PublicKeys[oldKeyID]
+			if !has {
+				continue
+			}
+
+			r.Principals[principalID] = rotatePersonKey(principal, oldKeyID, key)
+
+			r.recordKeyRotation(newKeyRotation(principalID, oldKey, key, gracePeriodEnd))
+			return nil
+		}
+	}
+
+	return fmt.Errorf("%w: '%s'", tuf.ErrKeyNotFound, oldKeyID)
+}
+
+// recordKeyRotation adds the key rotation to the metadata if it's set.
+func (r *RootMetadata) recordKeyRotation(keyRotation *KeyRotation) {
+	if keyRotation != nil {
+		r.KeyRotations = append(r.KeyRotations, keyRotation)
+	}
+}
+
+// RevokeKey records that signatures made using key after the RSL entry
+// identified by revokedAfterEntryID, or after revokedAfterTime, are invalid.
+// Exactly one of the two must be set. The key need not be trusted in the root
+// metadata, as it may be trusted in a rule file or an earlier policy state. If
+// the key is already revoked, the existing revocation is replaced.
+func (r *RootMetadata) RevokeKey(key tuf.Principal, revokedAfterEntryID string, revokedAfterTime time.Time) error {
+	revokedKey, isKey := key.(*Key)
+	if !isKey {
+		return tuf.ErrInvalidPrincipalType
+	}
+
+	if (revokedAfterEntryID == "") == revokedAfterTime.IsZero() {
+		return tuf.ErrInvalidKeyRevocation
+	}
+
+	keyRevocation := &KeyRevocation{Key: revokedKey, RevokedAfterEntryID: revokedAfterEntryID}
+	if !revokedAfterTime.IsZero() {
+		revokedAfterTime = revokedAfterTime.UTC()
+		keyRevocation.RevokedAfterTime = &revokedAfterTime
+	}
+
+	for index, existingRevocation := range r.KeyRevocations {
+		if existingRevocation.Key.KeyID == revokedKey.KeyID {
+			r.KeyRevocations[index] = keyRevocation
+			return nil
+		}
+	}
+
+	r.KeyRevocations = append(r.KeyRevocations, keyRevocation)
+	return nil
+}
+
+// GetKeyRevocations returns the key revocations recorded in the root metadata.
+func (r *RootMetadata) GetKeyRevocations() []tuf.KeyRevocation {
+	keyRevocations := make([]tuf.KeyRevocation, 0, len(r.KeyRevocations))
+	for _, keyRevocation := range r.KeyRevocations {
+		keyRevocations = append(keyRevocations, keyRevocation)
+	}
+
+	return keyRevocations
+}
+
+// AddGitHubAppPrincipal adds the 'principal' as a trusted principal in
+// 'rootMetadata' for the special GitHub app role. This key is used to verify
+// GitHub pull request approval attestation signatures.
+func (r *RootMetadata) AddGitHubAppPrincipal(name string, principal tuf.Principal) error {
+	if principal == nil {
+		return tuf.ErrInvalidPrincipalType
+	}
+
+	// TODO: support multiple principals / threshold for app
+	if err := r.addPrincipal(principal); err != nil {
+		return err
+	}
+	entry := &GitHubApp{
+		PrincipalIDs: set.NewSetFromItems(principal.ID()),
+		Threshold:    1,
+	}
+
+	if r.GitHubApps == nil {
+		r.GitHubApps = map[string]*GitHubApp{}
+	}
+	r.GitHubApps[name] = entry
+	return nil
+}
+
+func (r *RootMetadata) UnmarshalJSON(data []byte) error {
+	// this type _has_ to be a copy of RootMetadata, minus the use of
+	// json.RawMessage in place of tuf interfaces
+	type tempType struct {
+		Type               string                     `json:"type"`
+		Version            string                     `json:"schemaVersion"`
+		Expires            string                     `json:"expires"`
+		RepositoryLocation string                     `json:"repositoryLocation,omitempty"`
+		Principals         map[string]json.RawMessage `json:"principals"`
+		Roles              map[string]Role            `json:"roles"`
+		GitHubApps         map[string]*GitHubApp      `json:"githubApps,omitempty"`
+		GlobalRules        []json.RawMessage          `json:"globalRules,omitempty"`
+		Propagations       []json.RawMessage          `json:"propagations,omitempty"`
+		MultiRepository    *MultiRepository           `json:"multiRepository,omitempty"`
+		Hooks              map[tuf.HookStage][]*Hook  `json:"hooks,omitempty"`
+		KeyRotations       []*KeyRotation             `json:"keyRotations,omitempty"`
+		KeyRevocations     []*KeyRevocation           `json:"keyRevocations,omitempty"`
+	}
+
+	temp := &tempType{}
+	if err := json.Unmarshal(data, &temp); err != nil {
+		return fmt.Errorf("unable to unmarshal json: %w", err)
+	}
+
+	r.Type = temp.Type
+	r.Version = temp.Version
+	r.Expires = temp.Expires
+	r.RepositoryLocation = temp.RepositoryLocation
+
+	r.Principals = make(map[string]tuf.Principal)
+	for principalID, principalBytes := range temp.Principals {
+		principal, err := unmarshalPrincipal(principalBytes, true)
+		if err != nil {
+			return err
+		}
+
+		r.Principals[principalID] = principal
+	}
+
+	r.Roles = temp.Roles
+	r.GitHubApps = temp.GitHubApps
+
+	r.GlobalRules = []tuf.GlobalRule{}
+	for _, globalRuleBytes := range temp.GlobalRules {
+		tempGlobalRule := map[string]any{}
+		if err := json.Unmarshal(globalRuleBytes, &tempGlobalRule); err != nil {
+			return fmt.Errorf("unable to unmarshal json: %w", err)
+		}
+
+		switch tempGlobalRule["type"] {
+		case tuf.GlobalRuleThresholdType:
+			globalRule := &GlobalRuleThreshold{}
+			if err := json.Unmarshal(globalRuleBytes, globalRule); err != nil {
+				return fmt.Errorf("unable to unmarshal json: %w", err)
+			}
+
+			r.GlobalRules = append(r.GlobalRules, globalRule)
+
+		case tuf.GlobalRuleBlockForcePushesType:
+			globalRule := &GlobalRuleBlockForcePushes{}
+			if err := json.Unmarshal(globalRuleBytes, globalRule); err != nil {
+				return fmt.Errorf("unable to unmarshal json: %w", err)
+			}
+
+			r.GlobalRules = append(r.GlobalRules, globalRule)
+
+		default:
+			return tuf.ErrUnknownGlobalRuleType
+		}
+	}
+
+	r.Propagations = []tuf.PropagationDirective{}
+	for _, propagationDirectiveBytes := range temp.Propagations {
+		propagationDirective := &PropagationDirective{}
+		if err := json.Unmarshal(propagationDirectiveBytes, propagationDirective); err != nil {
+			return fmt.Errorf("unable to unmarshal json for propagation directive: %w", err)
+		}
+
+		r.Propagations = append(r.Propagations, propagationDirective)
+	}
+
+	r.MultiRepository = temp.MultiRepository
+
+	r.Hooks = temp.Hooks
+
+	r.KeyRotations = temp.KeyRotations
+	r.KeyRevocations = temp.KeyRevocations
+
+	return nil
+}
+
+// AddGlobalRule adds a new global rule to RootMetadata.
+// addPrincipal adds a principal to the RootMetadata instance.  v03 of the
+// metadata supports Key, Person, and Team as supported principal types.
+func (r *RootMetadata) addPrincipal(principal tuf.Principal) error {
+	if r.Principals == nil {
+		r.Principals = map[string]tuf.Principal{}
+	}
+	switch principal := principal.(type) {
+	case *Key, *Person, *Team:
+		r.Principals[principal.ID()] = principal
+	default:
+		return tuf.ErrInvalidPrincipalType
+	}
+
+	return nil
+}
+
+// addRole adds a role object and associates it with roleName in the
+// RootMetadata instance.
+func (r *RootMetadata) addRole(roleName string, role Role) {
+	if r.Roles == nil {
+		r.Roles = map[string]Role{}
+	}
+
+	r.Roles[roleName] = role
+}
+
+type GlobalRuleThreshold = tufv02.GlobalRuleThreshold
+type GlobalRuleBlockForcePushes = tufv02.GlobalRuleBlockForcePushes
+
+var NewGlobalRuleThreshold = tufv02.NewGlobalRuleThreshold
+var NewGlobalRuleBlockForcePushes = tufv02.NewGlobalRuleBlockForcePushes
+
+type PropagationDirective = tufv02.PropagationDirective
+
+var NewPropagationDirective = tufv02.NewPropagationDirective
+
+type MultiRepository = tufv02.MultiRepository
+type OtherRepository = tufv02.OtherRepository
+
+type Hook = tufv02.Hook
+
+type GitHubApp = tufv02.GitHubApp
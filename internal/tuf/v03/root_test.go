@@ -0,0 +1,361 @@
+// Copyright The gittuf Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package v03
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/gittuf/gittuf/internal/common/set"
+	"github.com/gittuf/gittuf/internal/gitinterface"
+	"github.com/gittuf/gittuf/internal/signerverifier/dsse"
+	"github.com/gittuf/gittuf/internal/signerverifier/ssh"
+	artifacts "github.com/gittuf/gittuf/internal/testartifacts"
+	sslibdsse "github.com/gittuf/gittuf/internal/third_party/go-securesystemslib/dsse"
+	"github.com/gittuf/gittuf/internal/tuf"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRootMetadata(t *testing.T) {
+	rootMetadata := NewRootMetadata()
+
+	key := NewKeyFromSSLibKey(ssh.NewKeyFromBytes(t, rootPubKeyBytes))
+	err := rootMetadata.addPrincipal(key)
+	assert.Nil(t, err)
+	assert.Equal(t, key, rootMetadata.Principals[key.KeyID])
+
+	person := &Person{
+		PersonID:   "jane.doe@example.com",
+		PublicKeys: map[string]*Key{key.KeyID: key},
+	}
+	err = rootMetadata.addPrincipal(person)
+	assert.Nil(t, err)
+	assert.Equal(t, person, rootMetadata.Principals[person.PersonID])
+
+	t.Run("test SetExpires", func(t *testing.T) {
+		d := time.Date(1995, time.October, 26, 9, 0, 0, 0, time.UTC)
+		rootMetadata.SetExpires(d.Format(time.RFC3339))
+		assert.Equal(t, "1995-10-26T09:00:00Z", rootMetadata.Expires)
+	})
+
+	t.Run("test addRole", func(t *testing.T) {
+		rootMetadata.addRole("targets", Role{
+			PrincipalIDs: set.NewSetFromItems(key.KeyID),
+			Threshold:    1,
+		})
+		assert.True(t, rootMetadata.Roles["targets"].PrincipalIDs.Has(key.KeyID))
+	})
+
+	t.Run("test SchemaVersion", func(t *testing.T) {
+		schemaVersion := rootMetadata.SchemaVersion()
+		assert.Equal(t, RootVersion, schemaVersion)
+	})
+}
+
+func TestRootMetadataWithSSHKey(t *testing.T) {
+	// Setup test key pair
+	keys := []struct {
+		name string
+		data []byte
+	}{
+		{"rsa", artifacts.SSHRSAPrivate},
+		{"rsa.pub", artifacts.SSHRSAPublicSSH},
+	}
+	tmpDir := t.TempDir()
+	for _, key := range keys {
+		keyPath := filepath.Join(tmpDir, key.name)
+		if err := os.WriteFile(keyPath, key.data, 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	keyPath := filepath.Join(tmpDir, "rsa")
+	sslibKeyO, err := ssh.NewKeyFromFile(keyPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sslibKey := NewKeyFromSSLibKey(sslibKeyO)
+
+	// Create TUF root and add test key
+	rootMetadata := NewRootMetadata()
+	if err := rootMetadata.addPrincipal(sslibKey); err != nil {
+		t.Fatal(err)
+	}
+
+	// Wrap and and sign
+	ctx := context.Background()
+	env, err := dsse.CreateEnvelope(rootMetadata)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	verifier, err := ssh.NewVerifierFromKey(sslibKeyO)
+	if err != nil {
+		t.Fatal()
+	}
+	signer := &ssh.Signer{
+		Verifier: verifier,
+		Path:     keyPath,
+	}
+
+	env, err = dsse.SignEnvelope(ctx, env, signer)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Unwrap and verify
+	// NOTE: For the sake of testing the contained key, we unwrap before we
+	// verify. Typically, in DSSE it should be the other way around.
+	payload, err := env.DecodeB64Payload()
+	if err != nil {
+		t.Fatal(err)
+	}
+	rootMetadata2 := &RootMetadata{}
+	if err := json.Unmarshal(payload, rootMetadata2); err != nil {
+		t.Log(string(payload))
+		t.Fatal(err)
+	}
+
+	sslibKey2 := rootMetadata2.Principals[sslibKey.KeyID]
+
+	// NOTE: Typically, a caller would choose this method, if KeyType==ssh.SSHKeyType
+	verifier2, err := ssh.NewVerifierFromKey(sslibKey2.Keys()[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = dsse.VerifyEnvelope(ctx, env, []sslibdsse.Verifier{verifier2}, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestAddRootPrincipal(t *testing.T) {
+	key := NewKeyFromSSLibKey(ssh.NewKeyFromBytes(t, rootPubKeyBytes))
+
+	t.Run("with root role already in metadata", func(t *testing.T) {
+		rootMetadata := initialTestRootMetadata(t)
+
+		newRootKey := NewKeyFromSSLibKey(ssh.NewKeyFromBytes(t, targets1PubKeyBytes))
+
+		err := rootMetadata.AddRootPrincipal(newRootKey)
+		assert.Nil(t, err)
+		assert.Equal(t, newRootKey, rootMetadata.Principals[newRootKey.KeyID])
+		assert.Equal(t, set.NewSetFromItems(key.KeyID, newRootKey.KeyID), rootMetadata.Roles[tuf.RootRoleName].PrincipalIDs)
+	})
+
+	t.Run("without root role already in metadata", func(t *testing.T) {
+		rootMetadata := NewRootMetadata()
+
+		err := rootMetadata.AddRootPrincipal(key)
+		assert.Nil(t, err)
+		assert.Equal(t, key, rootMetadata.Principals[key.KeyID])
+		assert.Equal(t, set.NewSetFromItems(key.KeyID), rootMetadata.Roles[tuf.RootRoleName].PrincipalIDs)
+	})
+
+	t.Run("with person", func(t *testing.T) {
+		rootMetadata := initialTestRootMetadata(t)
+
+		person := &Person{
+			PersonID: "jane.doe@example.com",
+			PublicKeys: map[string]*Key{
+				key.KeyID: key,
+			},
+		}
+
+		err := rootMetadata.AddRootPrincipal(person)
+		assert.Nil(t, err)
+		assert.Equal(t, person, rootMetadata.Principals[person.PersonID])
+		assert.Equal(t, set.NewSetFromItems(person.PersonID, key.KeyID), rootMetadata.Roles[tuf.RootRoleName].PrincipalIDs)
+	})
+
+	t.Run("with team", func(t *testing.T) {
+		rootMetadata := initialTestRootMetadata(t)
+
+		member := NewKeyFromSSLibKey(ssh.NewKeyFromBytes(t, targets1PubKeyBytes))
+		team, err := NewTeam("maintainers", []tuf.Principal{member}, 1)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		err = rootMetadata.AddRootPrincipal(team)
+		assert.Nil(t, err)
+		assert.Equal(t, team, rootMetadata.Principals[team.TeamID])
+		assert.Equal(t, set.NewSetFromItems(team.TeamID, key.KeyID), rootMetadata.Roles[tuf.RootRoleName].PrincipalIDs)
+
+		rootMetadataBytes, err := json.Marshal(rootMetadata)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		decodedRootMetadata := &RootMetadata{}
+		err = json.Unmarshal(rootMetadataBytes, decodedRootMetadata)
+		assert.Nil(t, err)
+		assert.Equal(t, team, decodedRootMetadata.Principals[team.TeamID])
+	})
+}
+
+func TestAddPrimaryRuleFilePrincipal(t *testing.T) {
+	rootMetadata := initialTestRootMetadata(t)
+
+	targetsKey := NewKeyFromSSLibKey(ssh.NewKeyFromBytes(t, targets1PubKeyBytes))
+
+	err := rootMetadata.AddPrimaryRuleFilePrincipal(nil)
+	assert.ErrorIs(t, err, tuf.ErrInvalidPrincipalType)
+
+	err = rootMetadata.AddPrimaryRuleFilePrincipal(targetsKey)
+	assert.Nil(t, err)
+	assert.Equal(t, targetsKey, rootMetadata.Principals[targetsKey.KeyID])
+	assert.Equal(t, set.NewSetFromItems(targetsKey.KeyID), rootMetadata.Roles[tuf.TargetsRoleName].PrincipalIDs)
+
+	person := &Person{
+		PersonID: "jane.doe@example.com",
+		PublicKeys: map[string]*Key{
+			targetsKey.KeyID: targetsKey,
+		},
+	}
+
+	err = rootMetadata.AddPrimaryRuleFilePrincipal(person)
+	assert.Nil(t, err)
+	assert.Equal(t, person, rootMetadata.Principals[person.PersonID])
+	assert.Equal(t, set.NewSetFromItems(targetsKey.KeyID, person.PersonID), rootMetadata.Roles[tuf.TargetsRoleName].PrincipalIDs)
+}
+
+func TestRotateRootKey(t *testing.T) {
+	rootKey := NewKeyFromSSLibKey(ssh.NewKeyFromBytes(t, rootPubKeyBytes))
+	newRootKey := NewKeyFromSSLibKey(ssh.NewKeyFromBytes(t, targets1PubKeyBytes))
+	personKey := NewKeyFromSSLibKey(ssh.NewKeyFromBytes(t, targets2PubKeyBytes))
+	gracePeriodEnd := time.Date(2030, time.January, 1, 0, 0, 0, 0, time.UTC)
+
+	t.Run("rotate key principal", func(t *testing.T) {
+		rootMetadata := initialTestRootMetadata(t)
+
+		err := rootMetadata.RotateRootKey(rootKey.KeyID, newRootKey, gracePeriodEnd)
+		assert.Nil(t, err)
+		assert.Equal(t, set.NewSetFromItems(newRootKey.KeyID), rootMetadata.Roles[tuf.RootRoleName].PrincipalIDs)
+		assert.Equal(t, newRootKey, rootMetadata.Principals[newRootKey.KeyID])
+		assert.Equal(t, []*KeyRotation{{
+			PrincipalID:    newRootKey.KeyID,
+			OldKey:         rootKey,
+			NewKeyID:       newRootKey.KeyID,
+			GracePeriodEnd: gracePeriodEnd,
+		}}, rootMetadata.KeyRotations)
+	})
+
+	t.Run("rotate person key", func(t *testing.T) {
+		rootMetadata := initialTestRootMetadata(t)
+		person := &Person{
+			PersonID:   "jane.doe@example.com",
+			PublicKeys: map[string]*Key{personKey.KeyID: personKey},
+		}
+		err := rootMetadata.AddRootPrincipal(person)
+		require.Nil(t, err)
+
+		err = rootMetadata.RotateRootKey(personKey.KeyID, newRootKey, time.Time{})
+		assert.Nil(t, err)
+		assert.Equal(t, set.NewSetFromItems(rootKey.KeyID, person.PersonID), rootMetadata.Roles[tuf.RootRoleName].PrincipalIDs)
+		assert.Equal(t, map[string]*Key{newRootKey.KeyID: newRootKey}, rootMetadata.Principals[person.PersonID].(*Person).PublicKeys)
+		assert.Empty(t, rootMetadata.KeyRotations)
+
+		// The person added earlier is unchanged
+		assert.Equal(t, map[string]*Key{personKey.KeyID: personKey}, person.PublicKeys)
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		rootMetadata := initialTestRootMetadata(t)
+
+		err := rootMetadata.RotateRootKey(rootKey.KeyID, nil, gracePeriodEnd)
+		assert.ErrorIs(t, err, tuf.ErrInvalidPrincipalType)
+
+		err = rootMetadata.RotateRootKey(personKey.KeyID, newRootKey, gracePeriodEnd)
+		assert.ErrorIs(t, err, tuf.ErrKeyNotFound)
+
+		err = rootMetadata.RotateRootKey(rootKey.KeyID, rootKey, gracePeriodEnd)
+		assert.ErrorIs(t, err, tuf.ErrInvalidPrincipalID)
+
+		err = rootMetadata.RotatePrimaryRuleFileKey(rootKey.KeyID, newRootKey, gracePeriodEnd)
+		assert.ErrorIs(t, err, tuf.ErrPrimaryRuleFileInformationNotFoundInRoot)
+	})
+}
+
+func TestRotatePrimaryRuleFileKey(t *testing.T) {
+	rootMetadata := initialTestRootMetadata(t)
+
+	rootKey := NewKeyFromSSLibKey(ssh.NewKeyFromBytes(t, rootPubKeyBytes))
+	targetsKey := NewKeyFromSSLibKey(ssh.NewKeyFromBytes(t, targets1PubKeyBytes))
+	newTargetsKey := NewKeyFromSSLibKey(ssh.NewKeyFromBytes(t, targets2PubKeyBytes))
+	gracePeriodEnd := time.Date(2030, time.January, 1, 0, 0, 0, 0, time.UTC)
+
+	err := rootMetadata.AddPrimaryRuleFilePrincipal(targetsKey)
+	require.Nil(t, err)
+
+	err = rootMetadata.RotatePrimaryRuleFileKey(rootKey.KeyID, newTargetsKey, gracePeriodEnd)
+	assert.ErrorIs(t, err, tuf.ErrKeyNotFound)
+
+	err = rootMetadata.RotatePrimaryRuleFileKey(targetsKey.KeyID, newTargetsKey, gracePeriodEnd)
+	assert.Nil(t, err)
+	assert.Equal(t, set.NewSetFromItems(newTargetsKey.KeyID), rootMetadata.Roles[tuf.TargetsRoleName].PrincipalIDs)
+	assert.Equal(t, set.NewSetFromItems(rootKey.KeyID), rootMetadata.Roles[tuf.RootRoleName].PrincipalIDs)
+
+	keyRotations := rootMetadata.GetKeyRotations()
+	require.Len(t, keyRotations, 1)
+	assert.Equal(t, newTargetsKey.KeyID, keyRotations[0].GetPrincipalID())
+	assert.Equal(t, targetsKey.KeyID, keyRotations[0].GetOldKey().KeyID)
+	assert.Equal(t, newTargetsKey.KeyID, keyRotations[0].GetNewKeyID())
+	assert.Equal(t, gracePeriodEnd, keyRotations[0].GetGracePeriodEnd())
+}
+
+func TestRevokeKey(t *testing.T) {
+	rootMetadata := initialTestRootMetadata(t)
+
+	key := NewKeyFromSSLibKey(ssh.NewKeyFromBytes(t, targets1PubKeyBytes))
+	revokedAfter := time.Date(2025, time.January, 1, 0, 0, 0, 0, time.UTC)
+	entryID := gitinterface.ZeroHash.String()
+
+	err := rootMetadata.RevokeKey(nil, entryID, time.Time{})
+	assert.ErrorIs(t, err, tuf.ErrInvalidPrincipalType)
+
+	err = rootMetadata.RevokeKey(key, "", time.Time{})
+	assert.ErrorIs(t, err, tuf.ErrInvalidKeyRevocation)
+
+	err = rootMetadata.RevokeKey(key, entryID, revokedAfter)
+	assert.ErrorIs(t, err, tuf.ErrInvalidKeyRevocation)
+
+	err = rootMetadata.RevokeKey(key, entryID, time.Time{})
+	assert.Nil(t, err)
+	assert.Equal(t, []*KeyRevocation{{Key: key, RevokedAfterEntryID: entryID}}, rootMetadata.KeyRevocations)
+
+	// Revoking the key again replaces the revocation
+	err = rootMetadata.RevokeKey(key, "", revokedAfter)
+	assert.Nil(t, err)
+
+	keyRevocations := rootMetadata.GetKeyRevocations()
+	require.Len(t, keyRevocations, 1)
+	assert.Equal(t, key.KeyID, keyRevocations[0].GetKey().KeyID)
+	assert.Empty(t, keyRevocations[0].GetRevokedAfterEntryID())
+	assert.Equal(t, revokedAfter, keyRevocations[0].GetRevokedAfterTime())
+
+	rootMetadataBytes, err := json.Marshal(rootMetadata)
+	require.Nil(t, err)
+	unmarshalledRootMetadata := &RootMetadata{}
+	err = json.Unmarshal(rootMetadataBytes, unmarshalledRootMetadata)
+	require.Nil(t, err)
+	assert.Equal(t, rootMetadata.KeyRevocations, unmarshalledRootMetadata.KeyRevocations)
+}
+
+func TestAddGitHubAppPrincipal(t *testing.T) {
+	rootMetadata := initialTestRootMetadata(t)
+
+	appKey := NewKeyFromSSLibKey(ssh.NewKeyFromBytes(t, targets1PubKeyBytes))
+
+	err := rootMetadata.AddGitHubAppPrincipal(tuf.GitHubAppRoleName, nil)
+	assert.ErrorIs(t, err, tuf.ErrInvalidPrincipalType)
+
+	err = rootMetadata.AddGitHubAppPrincipal(tuf.GitHubAppRoleName, appKey)
+	assert.Nil(t, err)
+	assert.Equal(t, appKey, rootMetadata.Principals[appKey.KeyID])
+	assert.Equal(t, set.NewSetFromItems(appKey.KeyID), rootMetadata.GitHubApps[tuf.GitHubAppRoleName].PrincipalIDs)
+}
@@ -0,0 +1,285 @@
+// Copyright The gittuf Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package v03
+
+// This package defines gittuf's take on TUF metadata. In addition to the
+// principal types supported in v02, it supports teams, which group principals
+// so that rules can require a threshold of approvals from each team.
+
+import (
+	"encoding/json"
+	"fmt"
+	"maps"
+	"slices"
+	"time"
+
+	"github.com/gittuf/gittuf/internal/tuf"
+	tufv02 "github.com/gittuf/gittuf/internal/tuf/v02"
+	"github.com/secure-systems-lab/go-securesystemslib/signerverifier"
+)
+
+// Key defines the structure for how public keys are stored in TUF metadata. It
+// implements the tuf.Principal and is used for backwards compatibility where a
+// Principal is always represented directly by a signing key or identity.
+type Key = tufv02.Key
+
+// NewKeyFromSSLibKey converts the signerverifier.SSLibKey into a Key object.
+func NewKeyFromSSLibKey(key *signerverifier.SSLibKey) *Key {
+	k := Key(*key)
+	return &k
+}
+
+// Person defines the structure for a principal that may have multiple keys
+// and associated identities.
+type Person = tufv02.Person
+
+// Role records common characteristics recorded in a role entry in Root metadata
+// and in a delegation entry.
+type Role = tufv02.Role
+
+// Team groups a set of principals so that they can be trusted as a single unit
+// in a role or rule. The team is counted towards a threshold when at least
+// Threshold of its members approve, unless a rule specifies a different
+// threshold for the team. A team's members may be keys or persons, but not
+// other teams.
+type Team struct {
+	TeamID     string                   `json:"teamID"`
+	Principals map[string]tuf.Principal `json:"principals"`
+	Threshold  int                      `json:"threshold"`
+	Custom     map[string]string        `json:"custom,omitempty"`
+}
+
+// NewTeam returns a new instance of Team with the specified members.
+func NewTeam(teamID string, members []tuf.Principal, threshold int) (*Team, error) {
+	if teamID == "" {
+		return nil, tuf.ErrInvalidPrincipalID
+	}
+
+	team := &Team{
+		TeamID:     teamID,
+		Principals: make(map[string]tuf.Principal, len(members)),
+		Threshold:  threshold,
+	}
+
+	for _, member := range members {
+		switch member := member.(type) {
+		case *Key, *Person:
+			team.Principals[member.ID()] = member
+		default:
+			return nil, tuf.ErrInvalidPrincipalType
+		}
+	}
+
+	if threshold < 1 || len(team.Principals) < threshold {
+		return nil, tuf.ErrCannotMeetThreshold
+	}
+
+	return team, nil
+}
+
+// ID returns the identifier of the team.
+func (t *Team) ID() string {
+	return t.TeamID
+}
+
+// Keys returns the keys of all the members of the team.
+func (t *Team) Keys() []*signerverifier.SSLibKey {
+	keys := []*signerverifier.SSLibKey{}
+	for _, member := range t.GetMembers() {
+		keys = append(keys, member.Keys()...)
+	}
+
+	return keys
+}
+
+// CustomMetadata returns the custom metadata recorded for the team.
+func (t *Team) CustomMetadata() map[string]string {
+	return t.Custom
+}
+
+// GetMembers returns the principals that are members of the team.
+func (t *Team) GetMembers() []tuf.Principal {
+	members := make([]tuf.Principal, 0, len(t.Principals))
+	for _, memberID := range slices.Sorted(maps.Keys(t.Principals)) {
+		members = append(members, t.Principals[memberID])
+	}
+
+	return members
+}
+
+// GetThreshold returns the number of members who must approve for the team to
+// be counted towards a threshold.
+func (t *Team) GetThreshold() int {
+	return t.Threshold
+}
+
+func (t *Team) UnmarshalJSON(data []byte) error {
+	// this type _has_ to be a copy of Team, minus the use of json.RawMessage
+	// in place of tuf.Principal
+	type tempType struct {
+		TeamID     string                     `json:"teamID"`
+		Principals map[string]json.RawMessage `json:"principals"`
+		Threshold  int                        `json:"threshold"`
+		Custom     map[string]string          `json:"custom,omitempty"`
+	}
+
+	temp := &tempType{}
+	if err := json.Unmarshal(data, temp); err != nil {
+		return fmt.Errorf("unable to unmarshal json: %w", err)
+	}
+
+	t.TeamID = temp.TeamID
+	t.Threshold = temp.Threshold
+	t.Custom = temp.Custom
+
+	t.Principals = make(map[string]tuf.Principal, len(temp.Principals))
+	for principalID, principalBytes := range temp.Principals {
+		// Teams cannot be nested
+		principal, err := unmarshalPrincipal(principalBytes, false)
+		if err != nil {
+			return err
+		}
+
+		t.Principals[principalID] = principal
+	}
+
+	return nil
+}
+
+// KeyRotation records that a principal's key was replaced with another key.
+// The old key continues to be trusted for the principal until GracePeriodEnd.
+type KeyRotation struct {
+	PrincipalID    string    `json:"principalID"`
+	OldKey         *Key      `json:"oldKey"`
+	NewKeyID       string    `json:"newKeyID"`
+	GracePeriodEnd time.Time `json:"gracePeriodEnd"`
+}
+
+// GetPrincipalID returns the identifier of the principal the old key is
+// trusted for during the grace period.
+func (k *KeyRotation) GetPrincipalID() string {
+	return k.PrincipalID
+}
+
+// GetOldKey returns the key that was replaced.
+func (k *KeyRotation) GetOldKey() *signerverifier.SSLibKey {
+	key := signerverifier.SSLibKey(*k.OldKey)
+	return &key
+}
+
+// GetNewKeyID returns the identifier of the key that replaced the old key.
+func (k *KeyRotation) GetNewKeyID() string {
+	return k.NewKeyID
+}
+
+// GetGracePeriodEnd returns the time until which the old key is trusted.
+func (k *KeyRotation) GetGracePeriodEnd() time.Time {
+	return k.GracePeriodEnd
+}
+
+// rotatePersonKey returns a copy of the person with the key identified by
+// oldKeyID replaced by newKey.
+func rotatePersonKey(person *Person, oldKeyID string, newKey *Key) *Person {
+	rotated := *person
+	rotated.PublicKeys = make(map[string]*Key, len(person.PublicKeys))
+	for keyID, key := range person.PublicKeys {
+		if keyID != oldKeyID {
+			rotated.PublicKeys[keyID] = key
+		}
+	}
+	rotated.PublicKeys[newKey.KeyID] = newKey
+
+	return &rotated
+}
+
+// newKeyRotation returns the rotation record for the old key if a grace period
+// is set, and nil otherwise.
+func newKeyRotation(principalID string, oldKey, newKey *Key, gracePeriodEnd time.Time) *KeyRotation {
+	if gracePeriodEnd.IsZero() {
+		return nil
+	}
+
+	return &KeyRotation{
+		PrincipalID:    principalID,
+		OldKey:         oldKey,
+		NewKeyID:       newKey.KeyID,
+		GracePeriodEnd: gracePeriodEnd.UTC(),
+	}
+}
+
+// KeyRevocation records that a key is compromised. Signatures made using the key
+// after the RSL entry identified by RevokedAfterEntryID, or after
+// RevokedAfterTime, are invalid. Exactly one of the two is set.
+type KeyRevocation struct {
+	Key                 *Key       `json:"key"`
+	RevokedAfterEntryID string     `json:"revokedAfterEntryID,omitempty"`
+	RevokedAfterTime    *time.Time `json:"revokedAfterTime,omitempty"`
+}
+
+// GetKey returns the revoked key.
+func (k *KeyRevocation) GetKey() *signerverifier.SSLibKey {
+	key := signerverifier.SSLibKey(*k.Key)
+	return &key
+}
+
+// GetRevokedAfterEntryID returns the identifier of the RSL entry after which
+// the key is revoked.
+func (k *KeyRevocation) GetRevokedAfterEntryID() string {
+	return k.RevokedAfterEntryID
+}
+
+// GetRevokedAfterTime returns the time after which the key is revoked.
+func (k *KeyRevocation) GetRevokedAfterTime() time.Time {
+	if k.RevokedAfterTime == nil {
+		return time.Time{}
+	}
+	return *k.RevokedAfterTime
+}
+
+// UnmarshalPrincipal identifies the type of the serialized principal and
+// returns it. The principal may be a key, a person, or a team.
+func UnmarshalPrincipal(principalBytes []byte) (tuf.Principal, error) {
+	return unmarshalPrincipal(principalBytes, true)
+}
+
+// unmarshalPrincipal identifies the type of the serialized principal and
+// returns it. Teams are only accepted if allowTeam is true.
+func unmarshalPrincipal(principalBytes []byte, allowTeam bool) (tuf.Principal, error) {
+	tempPrincipal := map[string]any{}
+	if err := json.Unmarshal(principalBytes, &tempPrincipal); err != nil {
+		return nil, fmt.Errorf("unable to unmarshal json: %w", err)
+	}
+
+	if _, has := tempPrincipal["keyid"]; has {
+		// this is *Key
+		key := &Key{}
+		if err := json.Unmarshal(principalBytes, key); err != nil {
+			return nil, fmt.Errorf("unable to unmarshal json: %w", err)
+		}
+
+		return key, nil
+	}
+
+	if _, has := tempPrincipal["personID"]; has {
+		// this is *Person
+		person := &Person{}
+		if err := json.Unmarshal(principalBytes, person); err != nil {
+			return nil, fmt.Errorf("unable to unmarshal json: %w", err)
+		}
+
+		return person, nil
+	}
+
+	if _, has := tempPrincipal["teamID"]; has && allowTeam {
+		// this is *Team
+		team := &Team{}
+		if err := json.Unmarshal(principalBytes, team); err != nil {
+			return nil, fmt.Errorf("unable to unmarshal json: %w", err)
+		}
+
+		return team, nil
+	}
+
+	return nil, fmt.Errorf("unrecognized principal type '%s'", string(principalBytes))
+}
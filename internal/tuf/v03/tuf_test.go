@@ -0,0 +1,131 @@
+// Copyright The gittuf Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package v03
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/gittuf/gittuf/internal/signerverifier/ssh"
+	"github.com/gittuf/gittuf/internal/tuf"
+	"github.com/secure-systems-lab/go-securesystemslib/signerverifier"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTeam(t *testing.T) {
+	key1R := ssh.NewKeyFromBytes(t, targets1PubKeyBytes)
+	key1 := NewKeyFromSSLibKey(key1R)
+	key2R := ssh.NewKeyFromBytes(t, targets2PubKeyBytes)
+	key2 := NewKeyFromSSLibKey(key2R)
+
+	person := &Person{
+		PersonID:   "jane.doe",
+		PublicKeys: map[string]*Key{key2.KeyID: key2},
+	}
+
+	team, err := NewTeam("dev", []tuf.Principal{key1, person}, 2)
+	require.Nil(t, err)
+	team.Custom = map[string]string{"owner": "jane.doe"}
+
+	assert.Equal(t, "dev", team.ID())
+	assert.Equal(t, 2, team.GetThreshold())
+	assert.ElementsMatch(t, []*signerverifier.SSLibKey{key1R, key2R}, team.Keys())
+	assert.Equal(t, map[string]string{"owner": "jane.doe"}, team.CustomMetadata())
+
+	members := team.GetMembers()
+	require.Len(t, members, 2)
+	// Members are sorted by their IDs
+	assert.Equal(t, key1.KeyID, members[0].ID())
+	assert.Equal(t, "jane.doe", members[1].ID())
+}
+
+func TestNewTeam(t *testing.T) {
+	key1 := NewKeyFromSSLibKey(ssh.NewKeyFromBytes(t, targets1PubKeyBytes))
+	key2 := NewKeyFromSSLibKey(ssh.NewKeyFromBytes(t, targets2PubKeyBytes))
+
+	t.Run("valid team", func(t *testing.T) {
+		team, err := NewTeam("dev", []tuf.Principal{key1, key2}, 1)
+		assert.Nil(t, err)
+		assert.Len(t, team.Principals, 2)
+	})
+
+	t.Run("missing team ID", func(t *testing.T) {
+		_, err := NewTeam("", []tuf.Principal{key1}, 1)
+		assert.ErrorIs(t, err, tuf.ErrInvalidPrincipalID)
+	})
+
+	t.Run("threshold too high", func(t *testing.T) {
+		_, err := NewTeam("dev", []tuf.Principal{key1, key2}, 3)
+		assert.ErrorIs(t, err, tuf.ErrCannotMeetThreshold)
+	})
+
+	t.Run("threshold too low", func(t *testing.T) {
+		_, err := NewTeam("dev", []tuf.Principal{key1, key2}, 0)
+		assert.ErrorIs(t, err, tuf.ErrCannotMeetThreshold)
+	})
+
+	t.Run("nested team", func(t *testing.T) {
+		team, err := NewTeam("dev", []tuf.Principal{key1}, 1)
+		require.Nil(t, err)
+
+		_, err = NewTeam("eng", []tuf.Principal{team}, 1)
+		assert.ErrorIs(t, err, tuf.ErrInvalidPrincipalType)
+	})
+}
+
+func TestTeamJSON(t *testing.T) {
+	key1 := NewKeyFromSSLibKey(ssh.NewKeyFromBytes(t, targets1PubKeyBytes))
+	key2 := NewKeyFromSSLibKey(ssh.NewKeyFromBytes(t, targets2PubKeyBytes))
+	person := &Person{
+		PersonID:   "jane.doe",
+		PublicKeys: map[string]*Key{key2.KeyID: key2},
+	}
+
+	team, err := NewTeam("dev", []tuf.Principal{key1, person}, 2)
+	require.Nil(t, err)
+
+	teamBytes, err := json.Marshal(team)
+	require.Nil(t, err)
+
+	decodedTeam := &Team{}
+	err = json.Unmarshal(teamBytes, decodedTeam)
+	assert.Nil(t, err)
+	assert.Equal(t, team, decodedTeam)
+
+	t.Run("nested team", func(t *testing.T) {
+		nestedTeamBytes := []byte(`{"teamID": "eng", "threshold": 1, "principals": {"dev": {"teamID": "dev", "threshold": 1, "principals": {}}}}`)
+
+		err := json.Unmarshal(nestedTeamBytes, &Team{})
+		assert.ErrorContains(t, err, "unrecognized principal type")
+	})
+}
+
+func TestUnmarshalPrincipal(t *testing.T) {
+	key1 := NewKeyFromSSLibKey(ssh.NewKeyFromBytes(t, targets1PubKeyBytes))
+	key2 := NewKeyFromSSLibKey(ssh.NewKeyFromBytes(t, targets2PubKeyBytes))
+	person := &Person{
+		PersonID:   "jane.doe",
+		PublicKeys: map[string]*Key{key2.KeyID: key2},
+	}
+	team, err := NewTeam("dev", []tuf.Principal{key1, person}, 2)
+	require.Nil(t, err)
+
+	for _, principal := range []tuf.Principal{key1, person, team} {
+		principalBytes, err := json.Marshal(principal)
+		require.Nil(t, err)
+
+		decodedPrincipal, err := UnmarshalPrincipal(principalBytes)
+		assert.Nil(t, err)
+		assert.Equal(t, principal, decodedPrincipal)
+	}
+
+	t.Run("team not allowed", func(t *testing.T) {
+		teamBytes, err := json.Marshal(team)
+		require.Nil(t, err)
+
+		_, err = unmarshalPrincipal(teamBytes, false)
+		assert.ErrorContains(t, err, "unrecognized principal type")
+	})
+}
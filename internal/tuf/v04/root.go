@@ -0,0 +1,445 @@
+// Copyright The gittuf Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package v04
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/gittuf/gittuf/internal/tuf"
+	tufv03 "github.com/gittuf/gittuf/internal/tuf/v03"
+)
+
+const (
+	RootVersion = "https://gittuf.dev/policy/root/v0.4"
+)
+
+// RootMetadata defines the schema of TUF's Root role. It extends the v03
+// schema with metadata versions, and the patterns of its global rules may be
+// negated and use `**` to match across path separators.
+type RootMetadata struct {
+	tufv03.RootMetadata
+	MetadataVersion int `json:"version,omitempty"`
+}
+
+// NewRootMetadata returns a new instance of RootMetadata.
+func NewRootMetadata() *RootMetadata {
+	rootMetadata := &RootMetadata{
+		RootMetadata:    *tufv03.NewRootMetadata(),
+		MetadataVersion: 1,
+	}
+	rootMetadata.Version = RootVersion
+	return rootMetadata
+}
+
+// GetMetadataVersion returns the version of the RootMetadata.
+func (r *RootMetadata) GetMetadataVersion() int {
+	return r.MetadataVersion
+}
+
+// IncrementMetadataVersion increments the version of the RootMetadata.
+func (r *RootMetadata) IncrementMetadataVersion() {
+	r.MetadataVersion++
+}
+
+func (r *RootMetadata) UnmarshalJSON(data []byte) error {
+	// The global rules use the v04 pattern syntax, so they're unmarshalled
+	// here and the remaining fields are unmarshalled as in v03
+	type tempType struct {
+		MetadataVersion int               `json:"version,omitempty"`
+		GlobalRules     []json.RawMessage `json:"globalRules,omitempty"`
+	}
+
+	temp := &tempType{}
+	if err := json.Unmarshal(data, &temp); err != nil {
+		return fmt.Errorf("unable to unmarshal json: %w", err)
+	}
+
+	fields := map[string]json.RawMessage{}
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return fmt.Errorf("unable to unmarshal json: %w", err)
+	}
+	delete(fields, "globalRules")
+
+	v03Data, err := json.Marshal(fields)
+	if err != nil {
+		return fmt.Errorf("unable to marshal json: %w", err)
+	}
+	if err := r.RootMetadata.UnmarshalJSON(v03Data); err != nil {
+		return err
+	}
+
+	r.MetadataVersion = temp.MetadataVersion
+
+	r.GlobalRules = []tuf.GlobalRule{}
+	for _, globalRuleBytes := range temp.GlobalRules {
+		tempGlobalRule := map[string]any{}
+		if err := json.Unmarshal(globalRuleBytes, &tempGlobalRule); err != nil {
+			return fmt.Errorf("unable to unmarshal json: %w", err)
+		}
+
+		switch tempGlobalRule["type"] {
+		case tuf.GlobalRuleThresholdType:
+			globalRule := &GlobalRuleThreshold{}
+			if err := json.Unmarshal(globalRuleBytes, globalRule); err != nil {
+				return fmt.Errorf("unable to unmarshal json: %w", err)
+			}
+
+			r.GlobalRules = append(r.GlobalRules, globalRule)
+
+		case tuf.GlobalRuleBlockForcePushesType:
+			globalRule := &GlobalRuleBlockForcePushes{}
+			if err := json.Unmarshal(globalRuleBytes, globalRule); err != nil {
+				return fmt.Errorf("unable to unmarshal json: %w", err)
+			}
+
+			r.GlobalRules = append(r.GlobalRules, globalRule)
+
+		case tuf.GlobalRuleRequireSignedCommitsType:
+			globalRule := &GlobalRuleRequireSignedCommits{}
+			if err := json.Unmarshal(globalRuleBytes, globalRule); err != nil {
+				return fmt.Errorf("unable to unmarshal json: %w", err)
+			}
+
+			r.GlobalRules = append(r.GlobalRules, globalRule)
+
+		case tuf.GlobalRuleMergeStrategyType:
+			globalRule := &GlobalRuleMergeStrategy{}
+			if err := json.Unmarshal(globalRuleBytes, globalRule); err != nil {
+				return fmt.Errorf("unable to unmarshal json: %w", err)
+			}
+
+			r.GlobalRules = append(r.GlobalRules, globalRule)
+
+		case tuf.GlobalRuleRequireHookExecutionType:
+			globalRule := &GlobalRuleRequireHookExecution{}
+			if err := json.Unmarshal(globalRuleBytes, globalRule); err != nil {
+				return fmt.Errorf("unable to unmarshal json: %w", err)
+			}
+
+			r.GlobalRules = append(r.GlobalRules, globalRule)
+
+		default:
+			return tuf.ErrUnknownGlobalRuleType
+		}
+	}
+
+	return nil
+}
+
+// UpdateGlobalRule updates the specified global rule from the RootMetadata.
+func (r *RootMetadata) UpdateGlobalRule(globalRule tuf.GlobalRule) error {
+	allGlobalRules := r.GlobalRules
+	updatedGlobalRules := []tuf.GlobalRule{}
+	found := false
+
+	if len(allGlobalRules) == 0 {
+		return tuf.ErrGlobalRuleNotFound
+	}
+
+	for _, oldGlobalRule := range allGlobalRules {
+		if oldGlobalRule.GetName() == globalRule.GetName() {
+			switch oldGlobalRule.(type) {
+			case *GlobalRuleThreshold:
+				if _, ok := globalRule.(*GlobalRuleThreshold); !ok {
+					return tuf.ErrCannotUpdateGlobalRuleType
+				}
+			case *GlobalRuleBlockForcePushes:
+				if _, ok := globalRule.(*GlobalRuleBlockForcePushes); !ok {
+					return tuf.ErrCannotUpdateGlobalRuleType
+				}
+			case *GlobalRuleRequireSignedCommits:
+				if _, ok := globalRule.(*GlobalRuleRequireSignedCommits); !ok {
+					return tuf.ErrCannotUpdateGlobalRuleType
+				}
+			case *GlobalRuleMergeStrategy:
+				if _, ok := globalRule.(*GlobalRuleMergeStrategy); !ok {
+					return tuf.ErrCannotUpdateGlobalRuleType
+				}
+			case *GlobalRuleRequireHookExecution:
+				if _, ok := globalRule.(*GlobalRuleRequireHookExecution); !ok {
+					return tuf.ErrCannotUpdateGlobalRuleType
+				}
+			}
+			found = true
+			updatedGlobalRules = append(updatedGlobalRules, globalRule)
+		} else {
+			updatedGlobalRules = append(updatedGlobalRules, oldGlobalRule)
+		}
+	}
+
+	if !found {
+		return tuf.ErrGlobalRuleNotFound
+	}
+
+	r.GlobalRules = updatedGlobalRules
+
+	return nil
+}
+
+// GlobalRuleThreshold requires a threshold of approvals for changes to the
+// namespaces matched by its patterns. It implements tuf.GlobalRuleThreshold.
+type GlobalRuleThreshold struct {
+	Name      string   `json:"name"`
+	Type      string   `json:"type"`
+	Paths     []string `json:"paths"`
+	Threshold int      `json:"threshold"`
+}
+
+// NewGlobalRuleThreshold returns a new threshold global rule for the specified
+// patterns.
+func NewGlobalRuleThreshold(name string, paths []string, threshold int) (*GlobalRuleThreshold, error) {
+	if err := tuf.ValidatePatterns(paths); err != nil {
+		return nil, err
+	}
+
+	return &GlobalRuleThreshold{
+		Name:      name,
+		Type:      tuf.GlobalRuleThresholdType,
+		Paths:     paths,
+		Threshold: threshold,
+	}, nil
+}
+
+// GetName returns the name of the global rule.
+func (g *GlobalRuleThreshold) GetName() string {
+	return g.Name
+}
+
+// Matches indicates if the global rule's patterns match the path.
+func (g *GlobalRuleThreshold) Matches(path string) bool {
+	return tuf.MatchPatterns(g.Paths, path)
+}
+
+// GetProtectedNamespaces returns the patterns of the global rule.
+func (g *GlobalRuleThreshold) GetProtectedNamespaces() []string {
+	return g.Paths
+}
+
+// GetThreshold returns the number of approvals required by the global rule.
+func (g *GlobalRuleThreshold) GetThreshold() int {
+	return g.Threshold
+}
+
+// GlobalRuleBlockForcePushes blocks force pushes to the Git references matched
+// by its patterns. It implements tuf.GlobalRuleBlockForcePushes.
+type GlobalRuleBlockForcePushes struct {
+	Name  string   `json:"name"`
+	Type  string   `json:"type"`
+	Paths []string `json:"paths"`
+}
+
+// NewGlobalRuleBlockForcePushes returns a new global rule blocking force
+// pushes for the specified patterns, which must all be for Git references.
+func NewGlobalRuleBlockForcePushes(name string, paths []string) (*GlobalRuleBlockForcePushes, error) {
+	for _, path := range paths {
+		if !strings.HasPrefix(strings.TrimPrefix(path, tuf.NegatedPatternPrefix), "git:") { // TODO: set prefix correctly
+			return nil, tuf.ErrGlobalRuleBlockForcePushesOnlyAppliesToGitPaths
+		}
+	}
+
+	if err := tuf.ValidatePatterns(paths); err != nil {
+		return nil, err
+	}
+
+	return &GlobalRuleBlockForcePushes{
+		Name:  name,
+		Type:  tuf.GlobalRuleBlockForcePushesType,
+		Paths: paths,
+	}, nil
+}
+
+// GetName returns the name of the global rule.
+func (g *GlobalRuleBlockForcePushes) GetName() string {
+	return g.Name
+}
+
+// Matches indicates if the global rule's patterns match the path.
+func (g *GlobalRuleBlockForcePushes) Matches(path string) bool {
+	return tuf.MatchPatterns(g.Paths, path)
+}
+
+// GetProtectedNamespaces returns the patterns of the global rule.
+func (g *GlobalRuleBlockForcePushes) GetProtectedNamespaces() []string {
+	return g.Paths
+}
+
+// BlocksForcePushes always returns true.
+func (g *GlobalRuleBlockForcePushes) BlocksForcePushes() bool {
+	return true
+}
+
+// GlobalRuleRequireSignedCommits requires every commit introduced to the Git
+// references matched by its patterns to be signed by a principal trusted in
+// the policy. It implements tuf.GlobalRuleRequireSignedCommits.
+type GlobalRuleRequireSignedCommits struct {
+	Name  string   `json:"name"`
+	Type  string   `json:"type"`
+	Paths []string `json:"paths"`
+}
+
+// NewGlobalRuleRequireSignedCommits returns a new global rule requiring signed
+// commits for the specified patterns, which must all be for Git references.
+func NewGlobalRuleRequireSignedCommits(name string, paths []string) (*GlobalRuleRequireSignedCommits, error) {
+	for _, path := range paths {
+		if !strings.HasPrefix(strings.TrimPrefix(path, tuf.NegatedPatternPrefix), "git:") {
+			return nil, tuf.ErrGlobalRuleRequireSignedCommitsOnlyAppliesToGitPaths
+		}
+	}
+
+	if err := tuf.ValidatePatterns(paths); err != nil {
+		return nil, err
+	}
+
+	return &GlobalRuleRequireSignedCommits{
+		Name:  name,
+		Type:  tuf.GlobalRuleRequireSignedCommitsType,
+		Paths: paths,
+	}, nil
+}
+
+// GetName returns the name of the global rule.
+func (g *GlobalRuleRequireSignedCommits) GetName() string {
+	return g.Name
+}
+
+// Matches indicates if the global rule's patterns match the path.
+func (g *GlobalRuleRequireSignedCommits) Matches(path string) bool {
+	return tuf.MatchPatterns(g.Paths, path)
+}
+
+// GetProtectedNamespaces returns the patterns of the global rule.
+func (g *GlobalRuleRequireSignedCommits) GetProtectedNamespaces() []string {
+	return g.Paths
+}
+
+// RequiresSignedCommits always returns true.
+func (g *GlobalRuleRequireSignedCommits) RequiresSignedCommits() bool {
+	return true
+}
+
+// GlobalRuleMergeStrategy requires updates to the Git references matched by its
+// patterns to follow a merge strategy. It implements
+// tuf.GlobalRuleMergeStrategy.
+type GlobalRuleMergeStrategy struct {
+	Name     string   `json:"name"`
+	Type     string   `json:"type"`
+	Paths    []string `json:"paths"`
+	Strategy string   `json:"strategy"`
+}
+
+// NewGlobalRuleMergeStrategy returns a new global rule requiring the merge
+// strategy for the specified patterns, which must all be for Git references.
+func NewGlobalRuleMergeStrategy(name string, paths []string, strategy string) (*GlobalRuleMergeStrategy, error) {
+	for _, path := range paths {
+		if !strings.HasPrefix(strings.TrimPrefix(path, tuf.NegatedPatternPrefix), "git:") {
+			return nil, tuf.ErrGlobalRuleMergeStrategyOnlyAppliesToGitPaths
+		}
+	}
+
+	if err := tuf.ValidatePatterns(paths); err != nil {
+		return nil, err
+	}
+
+	switch strategy {
+	case tuf.MergeStrategyLinearHistory, tuf.MergeStrategyMergeCommit:
+	default:
+		return nil, tuf.ErrInvalidMergeStrategy
+	}
+
+	return &GlobalRuleMergeStrategy{
+		Name:     name,
+		Type:     tuf.GlobalRuleMergeStrategyType,
+		Paths:    paths,
+		Strategy: strategy,
+	}, nil
+}
+
+// GetName returns the name of the global rule.
+func (g *GlobalRuleMergeStrategy) GetName() string {
+	return g.Name
+}
+
+// Matches indicates if the global rule's patterns match the path.
+func (g *GlobalRuleMergeStrategy) Matches(path string) bool {
+	return tuf.MatchPatterns(g.Paths, path)
+}
+
+// GetProtectedNamespaces returns the patterns of the global rule.
+func (g *GlobalRuleMergeStrategy) GetProtectedNamespaces() []string {
+	return g.Paths
+}
+
+// GetMergeStrategy returns the merge strategy required by the global rule.
+func (g *GlobalRuleMergeStrategy) GetMergeStrategy() string {
+	return g.Strategy
+}
+
+// GlobalRuleRequireHookExecution requires updates to the Git references
+// matched by its patterns to have a passing run of each of the named hooks. It
+// implements tuf.GlobalRuleRequireHookExecution.
+type GlobalRuleRequireHookExecution struct {
+	Name  string   `json:"name"`
+	Type  string   `json:"type"`
+	Paths []string `json:"paths"`
+	Hooks []string `json:"hooks"`
+}
+
+// NewGlobalRuleRequireHookExecution returns a new global rule requiring the
+// named hooks to have passed for the specified patterns, which must all be for
+// Git references.
+func NewGlobalRuleRequireHookExecution(name string, paths, hookNames []string) (*GlobalRuleRequireHookExecution, error) {
+	for _, path := range paths {
+		if !strings.HasPrefix(strings.TrimPrefix(path, tuf.NegatedPatternPrefix), "git:") {
+			return nil, tuf.ErrGlobalRuleRequireHookExecutionOnlyAppliesToGitPaths
+		}
+	}
+
+	if err := tuf.ValidatePatterns(paths); err != nil {
+		return nil, err
+	}
+
+	if len(hookNames) == 0 {
+		return nil, tuf.ErrNoHooksDefined
+	}
+
+	return &GlobalRuleRequireHookExecution{
+		Name:  name,
+		Type:  tuf.GlobalRuleRequireHookExecutionType,
+		Paths: paths,
+		Hooks: hookNames,
+	}, nil
+}
+
+// GetName returns the name of the global rule.
+func (g *GlobalRuleRequireHookExecution) GetName() string {
+	return g.Name
+}
+
+// Matches indicates if the global rule's patterns match the path.
+func (g *GlobalRuleRequireHookExecution) Matches(path string) bool {
+	return tuf.MatchPatterns(g.Paths, path)
+}
+
+// GetProtectedNamespaces returns the patterns of the global rule.
+func (g *GlobalRuleRequireHookExecution) GetProtectedNamespaces() []string {
+	return g.Paths
+}
+
+// GetHookNames returns the names of the hooks that must have passed.
+func (g *GlobalRuleRequireHookExecution) GetHookNames() []string {
+	return g.Hooks
+}
+
+type PropagationDirective = tufv03.PropagationDirective
+
+var NewPropagationDirective = tufv03.NewPropagationDirective
+
+type MultiRepository = tufv03.MultiRepository
+type OtherRepository = tufv03.OtherRepository
+
+type Hook = tufv03.Hook
+
+type GitHubApp = tufv03.GitHubApp
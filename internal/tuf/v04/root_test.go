@@ -0,0 +1,407 @@
+// Copyright The gittuf Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package v04
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/gittuf/gittuf/internal/gitinterface"
+	"github.com/gittuf/gittuf/internal/signerverifier/dsse"
+	"github.com/gittuf/gittuf/internal/signerverifier/ssh"
+	artifacts "github.com/gittuf/gittuf/internal/testartifacts"
+	sslibdsse "github.com/gittuf/gittuf/internal/third_party/go-securesystemslib/dsse"
+	"github.com/gittuf/gittuf/internal/tuf"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRootMetadata(t *testing.T) {
+	rootMetadata := initialTestRootMetadata(t)
+
+	t.Run("test SchemaVersion", func(t *testing.T) {
+		schemaVersion := rootMetadata.SchemaVersion()
+		assert.Equal(t, RootVersion, schemaVersion)
+	})
+
+	t.Run("test IncrementMetadataVersion", func(t *testing.T) {
+		assert.Equal(t, 1, rootMetadata.GetMetadataVersion())
+		rootMetadata.IncrementMetadataVersion()
+		assert.Equal(t, 2, rootMetadata.GetMetadataVersion())
+	})
+
+	t.Run("test fields shared with v03", func(t *testing.T) {
+		member := NewKeyFromSSLibKey(ssh.NewKeyFromBytes(t, targets1PubKeyBytes))
+		team, err := NewTeam("maintainers", []tuf.Principal{member}, 1)
+		if err != nil {
+			t.Fatal(err)
+		}
+		err = rootMetadata.AddRootPrincipal(team)
+		assert.Nil(t, err)
+
+		revokedKey := NewKeyFromSSLibKey(ssh.NewKeyFromBytes(t, targets2PubKeyBytes))
+		err = rootMetadata.RevokeKey(revokedKey, gitinterface.ZeroHash.String(), time.Time{})
+		assert.Nil(t, err)
+
+		rootMetadataBytes, err := json.Marshal(rootMetadata)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		decodedRootMetadata := &RootMetadata{}
+		err = json.Unmarshal(rootMetadataBytes, decodedRootMetadata)
+		assert.Nil(t, err)
+		assert.Equal(t, RootVersion, decodedRootMetadata.SchemaVersion())
+		assert.Equal(t, 2, decodedRootMetadata.GetMetadataVersion())
+		assert.Equal(t, rootMetadata.Principals, decodedRootMetadata.Principals)
+		assert.Equal(t, rootMetadata.Roles, decodedRootMetadata.Roles)
+		assert.Equal(t, rootMetadata.KeyRevocations, decodedRootMetadata.KeyRevocations)
+	})
+}
+
+func TestRootMetadataWithSSHKey(t *testing.T) {
+	// Setup test key pair
+	keys := []struct {
+		name string
+		data []byte
+	}{
+		{"rsa", artifacts.SSHRSAPrivate},
+		{"rsa.pub", artifacts.SSHRSAPublicSSH},
+	}
+	tmpDir := t.TempDir()
+	for _, key := range keys {
+		keyPath := filepath.Join(tmpDir, key.name)
+		if err := os.WriteFile(keyPath, key.data, 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	keyPath := filepath.Join(tmpDir, "rsa")
+	sslibKeyO, err := ssh.NewKeyFromFile(keyPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sslibKey := NewKeyFromSSLibKey(sslibKeyO)
+
+	// Create TUF root and add test key
+	rootMetadata := NewRootMetadata()
+	if err := rootMetadata.AddRootPrincipal(sslibKey); err != nil {
+		t.Fatal(err)
+	}
+
+	// Wrap and and sign
+	ctx := context.Background()
+	env, err := dsse.CreateEnvelope(rootMetadata)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	verifier, err := ssh.NewVerifierFromKey(sslibKeyO)
+	if err != nil {
+		t.Fatal()
+	}
+	signer := &ssh.Signer{
+		Verifier: verifier,
+		Path:     keyPath,
+	}
+
+	env, err = dsse.SignEnvelope(ctx, env, signer)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Unwrap and verify
+	// NOTE: For the sake of testing the contained key, we unwrap before we
+	// verify. Typically, in DSSE it should be the other way around.
+	payload, err := env.DecodeB64Payload()
+	if err != nil {
+		t.Fatal(err)
+	}
+	rootMetadata2 := &RootMetadata{}
+	if err := json.Unmarshal(payload, rootMetadata2); err != nil {
+		t.Log(string(payload))
+		t.Fatal(err)
+	}
+
+	sslibKey2 := rootMetadata2.Principals[sslibKey.KeyID]
+
+	// NOTE: Typically, a caller would choose this method, if KeyType==ssh.SSHKeyType
+	verifier2, err := ssh.NewVerifierFromKey(sslibKey2.Keys()[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = dsse.VerifyEnvelope(ctx, env, []sslibdsse.Verifier{verifier2}, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestGlobalRules(t *testing.T) {
+	rootMetadata := initialTestRootMetadata(t)
+
+	assert.Nil(t, rootMetadata.GlobalRules) // no global rule yet
+
+	thresholdGlobalRule, err := NewGlobalRuleThreshold("threshold-2-main", []string{"git:refs/heads/main"}, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = rootMetadata.AddGlobalRule(thresholdGlobalRule)
+	assert.Nil(t, err)
+	err = rootMetadata.AddGlobalRule(thresholdGlobalRule)
+	assert.ErrorIs(t, err, tuf.ErrGlobalRuleAlreadyExists)
+
+	assert.Equal(t, 1, len(rootMetadata.GlobalRules))
+	assert.Equal(t, "threshold-2-main", rootMetadata.GlobalRules[0].GetName())
+
+	expectedGlobalRule := &GlobalRuleThreshold{
+		Name:      "threshold-2-main",
+		Paths:     []string{"git:refs/heads/main"},
+		Threshold: 2,
+	}
+	globalRules := rootMetadata.GetGlobalRules()
+	assert.Equal(t, expectedGlobalRule.GetName(), globalRules[0].GetName())
+	assert.Equal(t, expectedGlobalRule.GetProtectedNamespaces(), globalRules[0].(tuf.GlobalRuleThreshold).GetProtectedNamespaces())
+	assert.Equal(t, expectedGlobalRule.GetThreshold(), globalRules[0].(tuf.GlobalRuleThreshold).GetThreshold())
+
+	forcePushesGlobalRule, err := NewGlobalRuleBlockForcePushes("block-force-pushes", []string{"git:refs/heads/main"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = rootMetadata.AddGlobalRule(forcePushesGlobalRule)
+	assert.Nil(t, err)
+	err = rootMetadata.AddGlobalRule(forcePushesGlobalRule)
+	assert.ErrorIs(t, err, tuf.ErrGlobalRuleAlreadyExists)
+
+	assert.Equal(t, 2, len(rootMetadata.GlobalRules))
+	assert.Equal(t, "threshold-2-main", rootMetadata.GlobalRules[0].GetName())
+	assert.Equal(t, "block-force-pushes", rootMetadata.GlobalRules[1].GetName())
+
+	updatedThresholdGlobalRule := &GlobalRuleThreshold{
+		Name:      "threshold-2-main",
+		Paths:     []string{"git:refs/heads/main"},
+		Threshold: 3,
+	}
+	err = rootMetadata.UpdateGlobalRule(updatedThresholdGlobalRule)
+	assert.Nil(t, err)
+
+	assert.Equal(t, 2, len(rootMetadata.GlobalRules))
+	assert.Equal(t, "threshold-2-main", rootMetadata.GlobalRules[0].GetName())
+	assert.Equal(t, "block-force-pushes", rootMetadata.GlobalRules[1].GetName())
+
+	updatedForcePushesGlobalRule, err := NewGlobalRuleBlockForcePushes("block-force-pushes", []string{"git:refs/heads/*"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = rootMetadata.UpdateGlobalRule(updatedForcePushesGlobalRule)
+	assert.Nil(t, err)
+
+	assert.Equal(t, 2, len(rootMetadata.GlobalRules))
+	assert.Equal(t, "threshold-2-main", rootMetadata.GlobalRules[0].GetName())
+	assert.Equal(t, "block-force-pushes", rootMetadata.GlobalRules[1].GetName())
+
+	differentNameGlobalRule := &GlobalRuleThreshold{
+		Name:      "threshold-4-main",
+		Paths:     []string{"git:refs/heads/main"},
+		Threshold: 4,
+	}
+	err = rootMetadata.UpdateGlobalRule(differentNameGlobalRule)
+	assert.ErrorIs(t, err, tuf.ErrGlobalRuleNotFound)
+	assert.Equal(t, 2, len(rootMetadata.GlobalRules))
+	assert.Equal(t, "threshold-2-main", rootMetadata.GlobalRules[0].GetName())
+	assert.Equal(t, "block-force-pushes", rootMetadata.GlobalRules[1].GetName())
+
+	err = rootMetadata.DeleteGlobalRule("threshold-2-main")
+	assert.Nil(t, err)
+	err = rootMetadata.DeleteGlobalRule("block-force-pushes")
+	assert.Nil(t, err)
+	assert.Equal(t, 0, len(rootMetadata.GlobalRules))
+
+	err = rootMetadata.DeleteGlobalRule("")
+	assert.ErrorIs(t, err, tuf.ErrGlobalRuleNotFound)
+
+	t.Run("invalid patterns", func(t *testing.T) {
+		_, err := NewGlobalRuleThreshold("threshold-2-main", []string{"!git:refs/heads/main"}, 2)
+		assert.ErrorIs(t, err, tuf.ErrNoPatternToMatch)
+
+		_, err = NewGlobalRuleBlockForcePushes("block-force-pushes", []string{"git:refs/heads/**", "!file:src/**"})
+		assert.ErrorIs(t, err, tuf.ErrGlobalRuleBlockForcePushesOnlyAppliesToGitPaths)
+
+		_, err = NewGlobalRuleBlockForcePushes("block-force-pushes", []string{"git:refs/heads/[main"})
+		assert.ErrorIs(t, err, tuf.ErrInvalidPattern)
+
+		_, err = NewGlobalRuleRequireSignedCommits("require-signed-commits", []string{"git:refs/heads/**", "file:src/**"})
+		assert.ErrorIs(t, err, tuf.ErrGlobalRuleRequireSignedCommitsOnlyAppliesToGitPaths)
+
+		_, err = NewGlobalRuleMergeStrategy("merge-strategy", []string{"file:src/**"}, tuf.MergeStrategyLinearHistory)
+		assert.ErrorIs(t, err, tuf.ErrGlobalRuleMergeStrategyOnlyAppliesToGitPaths)
+
+		_, err = NewGlobalRuleMergeStrategy("merge-strategy", []string{"git:refs/heads/main"}, "squash")
+		assert.ErrorIs(t, err, tuf.ErrInvalidMergeStrategy)
+
+		_, err = NewGlobalRuleRequireHookExecution("require-hook-execution", []string{"file:src/**"}, []string{"lint"})
+		assert.ErrorIs(t, err, tuf.ErrGlobalRuleRequireHookExecutionOnlyAppliesToGitPaths)
+
+		_, err = NewGlobalRuleRequireHookExecution("require-hook-execution", []string{"git:refs/heads/main"}, nil)
+		assert.ErrorIs(t, err, tuf.ErrNoHooksDefined)
+	})
+
+	t.Run("require signed commits", func(t *testing.T) {
+		rootMetadata := initialTestRootMetadata(t)
+
+		signedCommitsGlobalRule, err := NewGlobalRuleRequireSignedCommits("require-signed-commits", []string{"git:refs/heads/main"})
+		if err != nil {
+			t.Fatal(err)
+		}
+		err = rootMetadata.AddGlobalRule(signedCommitsGlobalRule)
+		assert.Nil(t, err)
+
+		globalRule, isSignedCommitsRule := rootMetadata.GetGlobalRules()[0].(tuf.GlobalRuleRequireSignedCommits)
+		assert.True(t, isSignedCommitsRule)
+		assert.True(t, globalRule.Matches("git:refs/heads/main"))
+		assert.False(t, globalRule.Matches("git:refs/heads/feature"))
+
+		// Block force pushes rules are not mistaken for this type
+		forcePushesGlobalRule, err := NewGlobalRuleBlockForcePushes("block-force-pushes", []string{"git:refs/heads/main"})
+		if err != nil {
+			t.Fatal(err)
+		}
+		var forcePushesRule tuf.GlobalRule = forcePushesGlobalRule
+		_, isSignedCommitsRule = forcePushesRule.(tuf.GlobalRuleRequireSignedCommits)
+		assert.False(t, isSignedCommitsRule)
+
+		// The type of the rule can't be changed
+		err = rootMetadata.UpdateGlobalRule(forcePushesGlobalRule)
+		assert.ErrorIs(t, err, tuf.ErrGlobalRuleNotFound)
+		forcePushesGlobalRule.Name = "require-signed-commits"
+		err = rootMetadata.UpdateGlobalRule(forcePushesGlobalRule)
+		assert.ErrorIs(t, err, tuf.ErrCannotUpdateGlobalRuleType)
+
+		updatedSignedCommitsGlobalRule, err := NewGlobalRuleRequireSignedCommits("require-signed-commits", []string{"git:refs/heads/*"})
+		if err != nil {
+			t.Fatal(err)
+		}
+		err = rootMetadata.UpdateGlobalRule(updatedSignedCommitsGlobalRule)
+		assert.Nil(t, err)
+
+		rootMetadataBytes, err := json.Marshal(rootMetadata)
+		if err != nil {
+			t.Fatal(err)
+		}
+		decodedRootMetadata := &RootMetadata{}
+		err = json.Unmarshal(rootMetadataBytes, decodedRootMetadata)
+		assert.Nil(t, err)
+		assert.Equal(t, []tuf.GlobalRule{updatedSignedCommitsGlobalRule}, decodedRootMetadata.GetGlobalRules())
+	})
+
+	t.Run("merge strategy", func(t *testing.T) {
+		rootMetadata := initialTestRootMetadata(t)
+
+		mergeStrategyGlobalRule, err := NewGlobalRuleMergeStrategy("linear-main", []string{"git:refs/heads/main"}, tuf.MergeStrategyLinearHistory)
+		if err != nil {
+			t.Fatal(err)
+		}
+		err = rootMetadata.AddGlobalRule(mergeStrategyGlobalRule)
+		assert.Nil(t, err)
+
+		globalRule, isMergeStrategyRule := rootMetadata.GetGlobalRules()[0].(tuf.GlobalRuleMergeStrategy)
+		assert.True(t, isMergeStrategyRule)
+		assert.Equal(t, tuf.MergeStrategyLinearHistory, globalRule.GetMergeStrategy())
+		assert.True(t, globalRule.Matches("git:refs/heads/main"))
+		assert.False(t, globalRule.Matches("git:refs/heads/feature"))
+
+		updatedMergeStrategyGlobalRule, err := NewGlobalRuleMergeStrategy("linear-main", []string{"git:refs/heads/main"}, tuf.MergeStrategyMergeCommit)
+		if err != nil {
+			t.Fatal(err)
+		}
+		err = rootMetadata.UpdateGlobalRule(updatedMergeStrategyGlobalRule)
+		assert.Nil(t, err)
+
+		forcePushesGlobalRule, err := NewGlobalRuleBlockForcePushes("linear-main", []string{"git:refs/heads/main"})
+		if err != nil {
+			t.Fatal(err)
+		}
+		err = rootMetadata.UpdateGlobalRule(forcePushesGlobalRule)
+		assert.ErrorIs(t, err, tuf.ErrCannotUpdateGlobalRuleType)
+
+		rootMetadataBytes, err := json.Marshal(rootMetadata)
+		if err != nil {
+			t.Fatal(err)
+		}
+		decodedRootMetadata := &RootMetadata{}
+		err = json.Unmarshal(rootMetadataBytes, decodedRootMetadata)
+		assert.Nil(t, err)
+		assert.Equal(t, []tuf.GlobalRule{updatedMergeStrategyGlobalRule}, decodedRootMetadata.GetGlobalRules())
+	})
+
+	t.Run("require hook execution", func(t *testing.T) {
+		rootMetadata := initialTestRootMetadata(t)
+
+		hookExecutionGlobalRule, err := NewGlobalRuleRequireHookExecution("require-lint", []string{"git:refs/heads/main"}, []string{"lint"})
+		if err != nil {
+			t.Fatal(err)
+		}
+		err = rootMetadata.AddGlobalRule(hookExecutionGlobalRule)
+		assert.Nil(t, err)
+
+		globalRule, isHookExecutionRule := rootMetadata.GetGlobalRules()[0].(tuf.GlobalRuleRequireHookExecution)
+		assert.True(t, isHookExecutionRule)
+		assert.Equal(t, []string{"lint"}, globalRule.GetHookNames())
+		assert.True(t, globalRule.Matches("git:refs/heads/main"))
+		assert.False(t, globalRule.Matches("git:refs/heads/feature"))
+
+		updatedHookExecutionGlobalRule, err := NewGlobalRuleRequireHookExecution("require-lint", []string{"git:refs/heads/main"}, []string{"lint", "test"})
+		if err != nil {
+			t.Fatal(err)
+		}
+		err = rootMetadata.UpdateGlobalRule(updatedHookExecutionGlobalRule)
+		assert.Nil(t, err)
+
+		forcePushesGlobalRule, err := NewGlobalRuleBlockForcePushes("require-lint", []string{"git:refs/heads/main"})
+		if err != nil {
+			t.Fatal(err)
+		}
+		err = rootMetadata.UpdateGlobalRule(forcePushesGlobalRule)
+		assert.ErrorIs(t, err, tuf.ErrCannotUpdateGlobalRuleType)
+
+		rootMetadataBytes, err := json.Marshal(rootMetadata)
+		if err != nil {
+			t.Fatal(err)
+		}
+		decodedRootMetadata := &RootMetadata{}
+		err = json.Unmarshal(rootMetadataBytes, decodedRootMetadata)
+		assert.Nil(t, err)
+		assert.Equal(t, []tuf.GlobalRule{updatedHookExecutionGlobalRule}, decodedRootMetadata.GetGlobalRules())
+	})
+
+	t.Run("each rule has a single global rule type", func(t *testing.T) {
+		thresholdGlobalRule, err := NewGlobalRuleThreshold("threshold", []string{"git:refs/heads/main"}, 2)
+		require.Nil(t, err)
+		forcePushesGlobalRule, err := NewGlobalRuleBlockForcePushes("block-force-pushes", []string{"git:refs/heads/main"})
+		require.Nil(t, err)
+		signedCommitsGlobalRule, err := NewGlobalRuleRequireSignedCommits("require-signed-commits", []string{"git:refs/heads/main"})
+		require.Nil(t, err)
+		mergeStrategyGlobalRule, err := NewGlobalRuleMergeStrategy("linear-main", []string{"git:refs/heads/main"}, tuf.MergeStrategyLinearHistory)
+		require.Nil(t, err)
+		hookExecutionGlobalRule, err := NewGlobalRuleRequireHookExecution("require-lint", []string{"git:refs/heads/main"}, []string{"lint"})
+		require.Nil(t, err)
+
+		for _, globalRule := range []tuf.GlobalRule{thresholdGlobalRule, forcePushesGlobalRule, signedCommitsGlobalRule, mergeStrategyGlobalRule, hookExecutionGlobalRule} {
+			_, isThresholdRule := globalRule.(tuf.GlobalRuleThreshold)
+			_, isForcePushesRule := globalRule.(tuf.GlobalRuleBlockForcePushes)
+			_, isSignedCommitsRule := globalRule.(tuf.GlobalRuleRequireSignedCommits)
+			_, isMergeStrategyRule := globalRule.(tuf.GlobalRuleMergeStrategy)
+			_, isHookExecutionRule := globalRule.(tuf.GlobalRuleRequireHookExecution)
+
+			types := 0
+			for _, isType := range []bool{isThresholdRule, isForcePushesRule, isSignedCommitsRule, isMergeStrategyRule, isHookExecutionRule} {
+				if isType {
+					types++
+				}
+			}
+			assert.Equal(t, 1, types, globalRule.GetName())
+		}
+	})
+}
@@ -0,0 +1,658 @@
+// Copyright The gittuf Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package v04
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/gittuf/gittuf/internal/common/set"
+	"github.com/gittuf/gittuf/internal/tuf"
+)
+
+const (
+	TargetsVersion = "http://gittuf.dev/policy/rule-file/v0.4"
+)
+
+var ErrTargetsNotEmpty = errors.New("`targets` field in gittuf Targets metadata must be empty")
+
+// TargetsMetadata defines the schema of TUF's Targets role.
+type TargetsMetadata struct {
+	Type            string         `json:"type"`
+	Version         string         `json:"schemaVersion"`
+	MetadataVersion int            `json:"version,omitempty"`
+	Expires         string         `json:"expires"`
+	Targets         map[string]any `json:"targets"`
+	Delegations     *Delegations   `json:"delegations"`
+}
+
+// NewTargetsMetadata returns a new instance of TargetsMetadata.
+func NewTargetsMetadata() *TargetsMetadata {
+	return &TargetsMetadata{
+		Type:            "targets",
+		Version:         TargetsVersion,
+		MetadataVersion: 1,
+		Delegations:     &Delegations{Roles: []*Delegation{AllowRule()}},
+	}
+}
+
+// SetExpires sets the expiry date of the TargetsMetadata to the value passed
+// in.
+func (t *TargetsMetadata) SetExpires(expires string) {
+	t.Expires = expires
+}
+
+// GetExpires returns the expiry date of the TargetsMetadata.
+func (t *TargetsMetadata) GetExpires() string {
+	return t.Expires
+}
+
+// GetMetadataVersion returns the version of the TargetsMetadata.
+func (t *TargetsMetadata) GetMetadataVersion() int {
+	return t.MetadataVersion
+}
+
+// IncrementMetadataVersion increments the version of the TargetsMetadata.
+func (t *TargetsMetadata) IncrementMetadataVersion() {
+	t.MetadataVersion++
+}
+
+// SchemaVersion returns the metadata schema version.
+func (t *TargetsMetadata) SchemaVersion() string {
+	return t.Version
+}
+
+// Validate ensures the instance of TargetsMetadata matches gittuf expectations.
+func (t *TargetsMetadata) Validate() error {
+	if len(t.Targets) != 0 {
+		return ErrTargetsNotEmpty
+	}
+	return nil
+}
+
+// AddRule adds a new delegation to TargetsMetadata.
+func (t *TargetsMetadata) AddRule(ruleName string, authorizedPrincipalIDs, rulePatterns []string, threshold int) error {
+	if strings.HasPrefix(ruleName, tuf.GittufPrefix) {
+		return tuf.ErrCannotManipulateRulesWithGittufPrefix
+	}
+
+	for _, principalID := range authorizedPrincipalIDs {
+		if _, has := t.Delegations.Principals[principalID]; !has {
+			return tuf.ErrPrincipalNotFound
+		}
+	}
+
+	if len(authorizedPrincipalIDs) < threshold {
+		return tuf.ErrCannotMeetThreshold
+	}
+
+	if err := tuf.ValidatePatterns(rulePatterns); err != nil {
+		return err
+	}
+
+	allDelegations := t.Delegations.Roles
+	if allDelegations == nil {
+		allDelegations = []*Delegation{}
+	}
+
+	newDelegation := &Delegation{
+		Name:        ruleName,
+		Paths:       rulePatterns,
+		Terminating: false,
+		Role: Role{
+			PrincipalIDs: set.NewSetFromItems(authorizedPrincipalIDs...),
+			Threshold:    threshold,
+		},
+	}
+	allDelegations = append(allDelegations[:len(allDelegations)-1], newDelegation, AllowRule())
+	t.Delegations.Roles = allDelegations
+	return nil
+}
+
+// UpdateRule is used to amend a delegation in TargetsMetadata.
+func (t *TargetsMetadata) UpdateRule(ruleName string, authorizedPrincipalIDs, rulePatterns []string, threshold int) error {
+	if strings.HasPrefix(ruleName, tuf.GittufPrefix) {
+		return tuf.ErrCannotManipulateRulesWithGittufPrefix
+	}
+
+	for _, principalID := range authorizedPrincipalIDs {
+		if _, has := t.Delegations.Principals[principalID]; !has {
+			return tuf.ErrPrincipalNotFound
+		}
+	}
+
+	if len(authorizedPrincipalIDs) < threshold {
+		return tuf.ErrCannotMeetThreshold
+	}
+
+	if err := tuf.ValidatePatterns(rulePatterns); err != nil {
+		return err
+	}
+
+	allDelegations := []*Delegation{}
+	for _, delegation := range t.Delegations.Roles {
+		if delegation.ID() == tuf.AllowRuleName {
+			break
+		}
+
+		if delegation.ID() != ruleName {
+			allDelegations = append(allDelegations, delegation)
+			continue
+		}
+
+		if delegation.Name == ruleName {
+			if err := tuf.ValidateRefActionPatterns(delegation.Actions, rulePatterns); err != nil {
+				return err
+			}
+
+			delegation.Paths = rulePatterns
+			delegation.Role = Role{
+				PrincipalIDs: set.NewSetFromItems(authorizedPrincipalIDs...),
+				Threshold:    threshold,
+			}
+
+			for teamID := range delegation.TeamThresholds {
+				if !delegation.PrincipalIDs.Has(teamID) {
+					delete(delegation.TeamThresholds, teamID)
+				}
+			}
+		}
+
+		allDelegations = append(allDelegations, delegation)
+	}
+	allDelegations = append(allDelegations, AllowRule())
+	t.Delegations.Roles = allDelegations
+	return nil
+}
+
+// UpdateRuleTeamThreshold sets the number of members of the specified team
+// that must approve for the team to count towards the threshold of the rule.
+func (t *TargetsMetadata) UpdateRuleTeamThreshold(ruleName, teamID string, threshold int) error {
+	if strings.HasPrefix(ruleName, tuf.GittufPrefix) {
+		return tuf.ErrCannotManipulateRulesWithGittufPrefix
+	}
+
+	team, isTeam := t.Delegations.Principals[teamID].(*Team)
+	if !isTeam {
+		return fmt.Errorf("%w: '%s'", tuf.ErrTeamNotFound, teamID)
+	}
+
+	if threshold < 1 || len(team.Principals) < threshold {
+		return tuf.ErrCannotMeetThreshold
+	}
+
+	for _, delegation := range t.Delegations.Roles {
+		if delegation.Name != ruleName {
+			continue
+		}
+
+		if !delegation.PrincipalIDs.Has(teamID) {
+			return fmt.Errorf("%w: '%s' is not trusted by rule '%s'", tuf.ErrTeamNotFound, teamID, ruleName)
+		}
+
+		if delegation.TeamThresholds == nil {
+			delegation.TeamThresholds = map[string]int{}
+		}
+		delegation.TeamThresholds[teamID] = threshold
+		return nil
+	}
+
+	return tuf.ErrRuleNotFound
+}
+
+// UpdateRuleActions sets the actions on Git references that the rule applies
+// to. If no actions are specified, the rule applies to all actions.
+func (t *TargetsMetadata) UpdateRuleActions(ruleName string, actions []tuf.RefAction) error {
+	if strings.HasPrefix(ruleName, tuf.GittufPrefix) {
+		return tuf.ErrCannotManipulateRulesWithGittufPrefix
+	}
+
+	actionNames := make([]string, 0, len(actions))
+	for _, action := range actions {
+		actionNames = append(actionNames, string(action))
+	}
+	actions, err := tuf.ParseRefActions(actionNames)
+	if err != nil {
+		return err
+	}
+
+	for _, delegation := range t.Delegations.Roles {
+		if delegation.Name != ruleName {
+			continue
+		}
+
+		if err := tuf.ValidateRefActionPatterns(actions, delegation.Paths); err != nil {
+			return err
+		}
+
+		if len(actions) == 0 {
+			delegation.Actions = nil
+		} else {
+			delegation.Actions = actions
+		}
+		return nil
+	}
+
+	return tuf.ErrRuleNotFound
+}
+
+// UpdateRuleValidityWindow sets the period during which the rule is trusted. If
+// window is nil, the rule is trusted at all times.
+func (t *TargetsMetadata) UpdateRuleValidityWindow(ruleName string, window *tuf.ValidityWindow) error {
+	if strings.HasPrefix(ruleName, tuf.GittufPrefix) {
+		return tuf.ErrCannotManipulateRulesWithGittufPrefix
+	}
+
+	if err := window.Validate(); err != nil {
+		return err
+	}
+
+	for _, delegation := range t.Delegations.Roles {
+		if delegation.Name == ruleName {
+			delegation.Validity = window
+			return nil
+		}
+	}
+
+	return tuf.ErrRuleNotFound
+}
+
+// ReorderRules changes the order of delegations, and the new order is specified
+// in `ruleNames []string`.
+func (t *TargetsMetadata) ReorderRules(ruleNames []string) error {
+	// Create a map of all existing delegations for quick look up
+	rolesMap := make(map[string]*Delegation)
+
+	// Create a set of current rules in metadata, skipping the allow rule
+	currentRules := set.NewSet[string]()
+	for _, delegation := range t.Delegations.Roles {
+		if delegation.Name == tuf.AllowRuleName {
+			continue
+		}
+		rolesMap[delegation.Name] = delegation
+		currentRules.Add(delegation.Name)
+	}
+
+	specifiedRules := set.NewSet[string]()
+	for _, name := range ruleNames {
+		if specifiedRules.Has(name) {
+			return fmt.Errorf("%w: '%s'", tuf.ErrDuplicatedRuleName, name)
+		}
+		specifiedRules.Add(name)
+	}
+
+	if !currentRules.Equal(specifiedRules) {
+		onlyInSpecifiedRules := specifiedRules.Minus(currentRules)
+		if onlyInSpecifiedRules.Len() != 0 {
+			if onlyInSpecifiedRules.Has(tuf.AllowRuleName) {
+				return fmt.Errorf("%w: do not specify allow rule", tuf.ErrCannotManipulateRulesWithGittufPrefix)
+			}
+
+			contents := onlyInSpecifiedRules.Contents()
+			return fmt.Errorf("%w: rules '%s' do not exist in current rule file", tuf.ErrRuleNotFound, strings.Join(contents, ", "))
+		}
+
+		onlyInCurrentRules := currentRules.Minus(specifiedRules)
+		if onlyInCurrentRules.Len() != 0 {
+			contents := onlyInCurrentRules.Contents()
+			return fmt.Errorf("%w: rules '%s' not specified", tuf.ErrMissingRules, strings.Join(contents, ", "))
+		}
+	}
+
+	// Create newDelegations and set it in the targetsMetadata after adding allow rule
+	newDelegations := make([]*Delegation, 0, len(rolesMap)+1)
+	for _, ruleName := range ruleNames {
+		newDelegations = append(newDelegations, rolesMap[ruleName])
+	}
+	newDelegations = append(newDelegations, AllowRule())
+	t.Delegations.Roles = newDelegations
+	return nil
+}
+
+// RemoveRule deletes a delegation entry from TargetsMetadata.
+func (t *TargetsMetadata) RemoveRule(ruleName string) error {
+	if strings.HasPrefix(ruleName, tuf.GittufPrefix) {
+		return tuf.ErrCannotManipulateRulesWithGittufPrefix
+	}
+
+	allDelegations := t.Delegations.Roles
+	updatedDelegations := []*Delegation{}
+
+	for _, delegation := range allDelegations {
+		if delegation.Name != ruleName {
+			updatedDelegations = append(updatedDelegations, delegation)
+		}
+	}
+	t.Delegations.Roles = updatedDelegations
+	return nil
+}
+
+// GetPrincipals returns all the principals in the rule file.
+func (t *TargetsMetadata) GetPrincipals() map[string]tuf.Principal {
+	principals := map[string]tuf.Principal{}
+	for id, principal := range t.Delegations.Principals {
+		principals[id] = principal
+	}
+
+	return principals
+}
+
+// GetRules returns all the rules in the metadata.
+func (t *TargetsMetadata) GetRules() []tuf.Rule {
+	if t.Delegations == nil {
+		return nil
+	}
+
+	rules := make([]tuf.Rule, 0, len(t.Delegations.Roles))
+	for _, delegation := range t.Delegations.Roles {
+		rules = append(rules, delegation)
+	}
+
+	return rules
+}
+
+// AddPrincipal adds a principal to the metadata.
+//
+// TODO: this isn't associated with a specific rule; with the removal of
+// verify-commit and verify-tag, it may not make sense anymore
+func (t *TargetsMetadata) AddPrincipal(principal tuf.Principal) error {
+	return t.Delegations.addPrincipal(principal)
+}
+
+// RemovePrincipal removes a principal from the metadata.
+func (t *TargetsMetadata) RemovePrincipal(principalID string) error {
+	return t.Delegations.removePrincipal(principalID)
+}
+
+// UpdatePrincipalValidityWindow sets the period during which the principal is
+// trusted by the rules in the metadata. If window is nil, the principal is
+// trusted at all times.
+func (t *TargetsMetadata) UpdatePrincipalValidityWindow(principalID string, window *tuf.ValidityWindow) error {
+	return t.Delegations.updatePrincipalValidityWindow(principalID, window)
+}
+
+// GetPrincipalValidityWindows returns the validity windows of the principals in
+// the rule file, keyed by principal ID.
+func (t *TargetsMetadata) GetPrincipalValidityWindows() map[string]*tuf.ValidityWindow {
+	if t.Delegations == nil {
+		return nil
+	}
+
+	return t.Delegations.PrincipalValidity
+}
+
+// RotatePersonKey replaces the key identified by oldKeyID with newKey for the
+// person identified by personID, including in the teams that the person is a
+// member of. If gracePeriodEnd is set, the old key continues to be trusted for
+// the person until then.
+func (t *TargetsMetadata) RotatePersonKey(personID, oldKeyID string, newKey tuf.Principal, gracePeriodEnd time.Time) error {
+	return t.Delegations.rotatePersonKey(personID, oldKeyID, newKey, gracePeriodEnd)
+}
+
+// GetKeyRotations returns the key rotations recorded in the rule file.
+func (t *TargetsMetadata) GetKeyRotations() []tuf.KeyRotation {
+	if t.Delegations == nil {
+		return nil
+	}
+
+	keyRotations := make([]tuf.KeyRotation, 0, len(t.Delegations.KeyRotations))
+	for _, keyRotation := range t.Delegations.KeyRotations {
+		keyRotations = append(keyRotations, keyRotation)
+	}
+
+	return keyRotations
+}
+
+// Delegations defines the schema for specifying delegations in TUF's Targets
+// metadata.
+type Delegations struct {
+	Principals   map[string]tuf.Principal `json:"principals"`
+	Roles        []*Delegation            `json:"roles"`
+	KeyRotations []*KeyRotation           `json:"keyRotations,omitempty"`
+
+	// PrincipalValidity records the periods during which principals are
+	// trusted by the rules in the rule file. Principals without an entry are
+	// trusted at all times.
+	PrincipalValidity map[string]*tuf.ValidityWindow `json:"principalValidity,omitempty"`
+}
+
+func (d *Delegations) UnmarshalJSON(data []byte) error {
+	// this type _has_ to be a copy of Delegations, minus the use of
+	// json.RawMessage in place of tuf.Principal
+	type tempType struct {
+		Principals        map[string]json.RawMessage     `json:"principals"`
+		Roles             []*Delegation                  `json:"roles"`
+		KeyRotations      []*KeyRotation                 `json:"keyRotations,omitempty"`
+		PrincipalValidity map[string]*tuf.ValidityWindow `json:"principalValidity,omitempty"`
+	}
+
+	temp := &tempType{}
+	if err := json.Unmarshal(data, temp); err != nil {
+		return fmt.Errorf("unable to unmarshal json: %w", err)
+	}
+
+	d.Principals = make(map[string]tuf.Principal)
+	for principalID, principalBytes := range temp.Principals {
+		principal, err := UnmarshalPrincipal(principalBytes)
+		if err != nil {
+			return err
+		}
+
+		d.Principals[principalID] = principal
+	}
+
+	d.Roles = temp.Roles
+	d.KeyRotations = temp.KeyRotations
+	d.PrincipalValidity = temp.PrincipalValidity
+
+	return nil
+}
+
+// addPrincipal adds a delegations key, person, or team.  v04 supports Key,
+// Person, and Team as principal types.
+func (d *Delegations) addPrincipal(principal tuf.Principal) error {
+	if d.Principals == nil {
+		d.Principals = map[string]tuf.Principal{}
+	}
+
+	switch principal := principal.(type) {
+	case *Key, *Person, *Team:
+		d.Principals[principal.ID()] = principal
+	default:
+		return tuf.ErrInvalidPrincipalType
+	}
+
+	return nil
+}
+
+// removePrincipal removes a delegations key, person, or team. v04 supports
+// Key, Person, and Team as principal types.
+func (d *Delegations) removePrincipal(principalID string) error {
+	if d.Principals == nil {
+		return tuf.ErrPrincipalNotFound
+	}
+	if principalID == "" {
+		return tuf.ErrInvalidPrincipalID
+	}
+	for _, curRole := range d.Roles {
+		if curRole.GetPrincipalIDs() != nil && curRole.GetPrincipalIDs().Has(principalID) {
+			return tuf.ErrPrincipalStillInUse
+		}
+	}
+	delete(d.Principals, principalID)
+	delete(d.PrincipalValidity, principalID)
+	return nil
+}
+
+// updatePrincipalValidityWindow sets the validity window of a delegations
+// principal.
+func (d *Delegations) updatePrincipalValidityWindow(principalID string, window *tuf.ValidityWindow) error {
+	if err := window.Validate(); err != nil {
+		return err
+	}
+
+	if d == nil || d.Principals == nil {
+		return tuf.ErrPrincipalNotFound
+	}
+	if _, has := d.Principals[principalID]; !has {
+		return tuf.ErrPrincipalNotFound
+	}
+
+	if window == nil {
+		delete(d.PrincipalValidity, principalID)
+		if len(d.PrincipalValidity) == 0 {
+			d.PrincipalValidity = nil
+		}
+		return nil
+	}
+
+	if d.PrincipalValidity == nil {
+		d.PrincipalValidity = map[string]*tuf.ValidityWindow{}
+	}
+	d.PrincipalValidity[principalID] = window
+	return nil
+}
+
+// rotatePersonKey replaces the key identified by oldKeyID with newKey for the
+// person identified by personID.
+func (d *Delegations) rotatePersonKey(personID, oldKeyID string, newKey tuf.Principal, gracePeriodEnd time.Time) error {
+	key, isKey := newKey.(*Key)
+	if !isKey {
+		return tuf.ErrInvalidPrincipalType
+	}
+
+	if d == nil || d.Principals == nil {
+		return tuf.ErrPrincipalNotFound
+	}
+	principal, has := d.Principals[personID]
+	if !has {
+		return tuf.ErrPrincipalNotFound
+	}
+	person, isPerson := principal.(*Person)
+	if !isPerson {
+		return tuf.ErrInvalidPrincipalType
+	}
+
+	oldKey, has := person.PublicKeys[oldKeyID]
+	if !has {
+		return fmt.Errorf("%w: '%s' for person '%s'", tuf.ErrKeyNotFound, oldKeyID, personID)
+	}
+
+	rotated := rotatePersonKey(person, oldKeyID, key)
+	d.Principals[personID] = rotated
+
+	// Teams record their own copy of each member
+	for _, principal := range d.Principals {
+		if team, isTeam := principal.(*Team); isTeam {
+			if _, isMember := team.Principals[personID]; isMember {
+				team.Principals[personID] = rotated
+			}
+		}
+	}
+
+	if keyRotation := newKeyRotation(personID, oldKey, key, gracePeriodEnd); keyRotation != nil {
+		d.KeyRotations = append(d.KeyRotations, keyRotation)
+	}
+
+	return nil
+}
+
+// AllowRule returns the default, last rule for all policy files.
+func AllowRule() *Delegation {
+	return &Delegation{
+		Name:        tuf.AllowRuleName,
+		Paths:       []string{"**"},
+		Terminating: true,
+		Role: Role{
+			Threshold: 1,
+		},
+	}
+}
+
+// Delegation defines the schema for a single delegation entry. It differs from
+// the standard TUF schema by allowing a `custom` field to record details
+// pertaining to the delegation, and by allowing per-team thresholds. It
+// implements the tuf.Rule interface.
+type Delegation struct {
+	Name        string           `json:"name"`
+	Paths       []string         `json:"paths"`
+	Terminating bool             `json:"terminating"`
+	Custom      *json.RawMessage `json:"custom,omitempty"`
+	Role
+
+	// TeamThresholds records the number of members of a team that must
+	// approve for the team to count towards the rule's threshold. It
+	// overrides the default threshold recorded in the team's definition.
+	TeamThresholds map[string]int `json:"teamThresholds,omitempty"`
+
+	// Actions records the actions on Git references that the rule applies
+	// to. If it's empty, the rule applies to all actions.
+	Actions []tuf.RefAction `json:"actions,omitempty"`
+
+	// Validity records the period during which the rule is trusted. If it's
+	// not set, the rule is trusted at all times.
+	Validity *tuf.ValidityWindow `json:"validity,omitempty"`
+}
+
+// ID returns the identifier of the delegation, its name.
+func (d *Delegation) ID() string {
+	return d.Name
+}
+
+// Matches checks if the delegation's patterns match the target, i.e., at least
+// one of its patterns that isn't negated matches the target and none of its
+// negated patterns do.
+func (d *Delegation) Matches(target string) bool {
+	// We validate patterns when they're added to / updated in the metadata
+	return tuf.MatchPatterns(d.Paths, target)
+}
+
+// GetPrincipalIDs returns the identifiers of the principals that are listed as
+// trusted by the rule.
+func (d *Delegation) GetPrincipalIDs() *set.Set[string] {
+	return d.PrincipalIDs
+}
+
+// GetThreshold returns the threshold of principals that must approve to meet
+// the rule.
+func (d *Delegation) GetThreshold() int {
+	return d.Threshold
+}
+
+// GetTeamThresholds returns the number of members of each team that must
+// approve for the team to count towards the rule's threshold. Teams without an
+// entry use the threshold in their definition.
+func (d *Delegation) GetTeamThresholds() map[string]int {
+	return d.TeamThresholds
+}
+
+// GetAllowedActions returns the actions on Git references that the rule applies
+// to. If no actions are returned, the rule applies to all actions.
+func (d *Delegation) GetAllowedActions() []tuf.RefAction {
+	return d.Actions
+}
+
+// GetValidityWindow returns the period during which the rule is trusted. If no
+// window is returned, the rule is trusted at all times.
+func (d *Delegation) GetValidityWindow() *tuf.ValidityWindow {
+	return d.Validity
+}
+
+// IsLastTrustedInRuleFile indicates that subsequent rules in the rule file are
+// not to be trusted if the current rule matches the namespace under
+// verification (similar to TUF's terminating behavior). However, the current
+// rule's delegated rules as well as other rules already in the queue are
+// trusted.
+func (d *Delegation) IsLastTrustedInRuleFile() bool {
+	return d.Terminating
+}
+
+// GetProtectedNamespaces returns the set of namespaces protected by the
+// delegation.
+func (d *Delegation) GetProtectedNamespaces() []string {
+	return d.Paths
+}
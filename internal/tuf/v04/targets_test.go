@@ -0,0 +1,801 @@
+// Copyright The gittuf Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package v04
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/gittuf/gittuf/internal/common/set"
+	"github.com/gittuf/gittuf/internal/signerverifier/ssh"
+	"github.com/gittuf/gittuf/internal/tuf"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTargetsMetadataAndDelegations(t *testing.T) {
+	targetsMetadata := NewTargetsMetadata()
+
+	t.Run("test SetExpires", func(t *testing.T) {
+		d := time.Date(1995, time.October, 26, 9, 0, 0, 0, time.UTC)
+		targetsMetadata.SetExpires(d.Format(time.RFC3339))
+		assert.Equal(t, "1995-10-26T09:00:00Z", targetsMetadata.Expires)
+	})
+
+	t.Run("test IncrementMetadataVersion", func(t *testing.T) {
+		assert.Equal(t, 1, targetsMetadata.GetMetadataVersion())
+		targetsMetadata.IncrementMetadataVersion()
+		assert.Equal(t, 2, targetsMetadata.GetMetadataVersion())
+	})
+
+	t.Run("test Validate", func(t *testing.T) {
+		err := targetsMetadata.Validate()
+		assert.Nil(t, err)
+
+		targetsMetadata.Targets = map[string]any{"test": true}
+		err = targetsMetadata.Validate()
+		assert.ErrorIs(t, err, ErrTargetsNotEmpty)
+		targetsMetadata.Targets = nil
+	})
+
+	key := NewKeyFromSSLibKey(ssh.NewKeyFromBytes(t, rootPubKeyBytes))
+	person := &Person{
+		PersonID:   "jane.doe",
+		PublicKeys: map[string]*Key{key.KeyID: key},
+	}
+
+	t.Run("test addPrincipal", func(t *testing.T) {
+		delegations := &Delegations{}
+		assert.Nil(t, delegations.Principals)
+
+		err := delegations.addPrincipal(key)
+		assert.Nil(t, err)
+		assert.Equal(t, key, delegations.Principals[key.KeyID])
+
+		err = delegations.addPrincipal(person)
+		assert.Nil(t, err)
+		assert.Equal(t, person, delegations.Principals[person.PersonID])
+	})
+
+	t.Run("test removePrincipal", func(t *testing.T) {
+		delegations := &Delegations{}
+
+		err := delegations.addPrincipal(key)
+		assert.Nil(t, err)
+		assert.Equal(t, key, delegations.Principals[key.KeyID])
+
+		err = delegations.addPrincipal(person)
+		assert.Nil(t, err)
+		assert.Equal(t, person, delegations.Principals[person.PersonID])
+
+		assert.NotEmpty(t, delegations.Principals)
+
+		err = delegations.removePrincipal(key.KeyID)
+		assert.Nil(t, err)
+		_, exists := delegations.Principals[key.KeyID]
+		assert.False(t, exists)
+
+		err = delegations.removePrincipal(person.PersonID)
+		assert.Nil(t, err)
+		_, exists = delegations.Principals[person.PersonID]
+		assert.False(t, exists)
+
+		assert.Empty(t, delegations.Principals)
+	})
+}
+
+func TestDelegation(t *testing.T) {
+	t.Run("matches", func(t *testing.T) {
+		tests := map[string]struct {
+			patterns []string
+			target   string
+			expected bool
+		}{
+			"full path, matches": {
+				patterns: []string{"foo"},
+				target:   "foo",
+				expected: true,
+			},
+			"artifact in directory, matches": {
+				patterns: []string{"foo/*"},
+				target:   "foo/bar",
+				expected: true,
+			},
+			"artifact in directory, does not match": {
+				patterns: []string{"foo/*.txt"},
+				target:   "foo/bar.tgz",
+				expected: false,
+			},
+			"artifact in directory, one pattern matches": {
+				patterns: []string{"foo/*.txt", "foo/*.tgz"},
+				target:   "foo/bar.tgz",
+				expected: true,
+			},
+			"artifact in subdirectory, does not match": {
+				patterns: []string{"foo/*"},
+				target:   "foo/bar/foobar",
+				expected: false,
+			},
+			"artifact in subdirectory with specified extension, does not match": {
+				patterns: []string{"foo/*.tgz"},
+				target:   "foo/bar/foobar.tgz",
+				expected: false,
+			},
+			"pattern with single character selector, matches": {
+				patterns: []string{"foo/?.tgz"},
+				target:   "foo/a.tgz",
+				expected: true,
+			},
+			"pattern with character sequence, matches": {
+				patterns: []string{"foo/[abc].tgz"},
+				target:   "foo/a.tgz",
+				expected: true,
+			},
+			"pattern with character sequence, does not match": {
+				patterns: []string{"foo/[abc].tgz"},
+				target:   "foo/x.tgz",
+				expected: false,
+			},
+			"pattern with negative character sequence, matches": {
+				patterns: []string{"foo/[!abc].tgz"},
+				target:   "foo/x.tgz",
+				expected: true,
+			},
+			"pattern with negative character sequence, does not match": {
+				patterns: []string{"foo/[!abc].tgz"},
+				target:   "foo/a.tgz",
+				expected: false,
+			},
+			"artifact in nested directory, does not match": {
+				patterns: []string{"*/*.txt"},
+				target:   "foo/bar/foobar.txt",
+				expected: false,
+			},
+			"artifact with specific name in nested directory, does not match": {
+				patterns: []string{"*/foobar.txt"},
+				target:   "foo/bar/foobar.txt",
+				expected: false,
+			},
+			"artifact with nested subdirectories, does not match": {
+				patterns: []string{"foo/*/foobar.txt"},
+				target:   "foo/bar/baz/foobar.txt",
+				expected: false,
+			},
+			"artifact in arbitrary directory, does not match": {
+				patterns: []string{"*.txt"},
+				target:   "foo/bar/foobar.txtfile",
+				expected: false,
+			},
+			"arbitrary directory, does not match": {
+				patterns: []string{"*_test"},
+				target:   "foo/bar_test/foobar",
+				expected: false,
+			},
+			"no patterns": {
+				patterns: nil,
+				target:   "foo",
+				expected: false,
+			},
+			"pattern with multiple consecutive wildcards, matches": {
+				patterns: []string{"foo/*/*/*.txt"},
+				target:   "foo/bar/baz/qux.txt",
+				expected: true,
+			},
+			"pattern with multiple non-consecutive wildcards, matches": {
+				patterns: []string{"foo/*/baz/*.txt"},
+				target:   "foo/bar/baz/qux.txt",
+				expected: true,
+			},
+			"pattern with gittuf git prefix, matches": {
+				patterns: []string{"git:refs/heads/*"},
+				target:   "git:refs/heads/main",
+				expected: true,
+			},
+			"pattern with gittuf file prefix for directory contents, does not match": {
+				patterns: []string{"file:src/signatures/*"},
+				target:   "file:src/signatures/rsa/rsa.go",
+				expected: false,
+			},
+			"artifact in subdirectory with recursive wildcard, matches": {
+				patterns: []string{"foo/**"},
+				target:   "foo/bar/foobar",
+				expected: true,
+			},
+			"artifact in arbitrary directory, matches": {
+				patterns: []string{"**/*.txt"},
+				target:   "foo/bar/foobar.txt",
+				expected: true,
+			},
+			"artifact with specific name in arbitrary directory, matches": {
+				patterns: []string{"**/foobar.txt"},
+				target:   "foobar.txt",
+				expected: true,
+			},
+			"artifact with arbitrary subdirectories, matches": {
+				patterns: []string{"foo/**/foobar.txt"},
+				target:   "foo/bar/baz/foobar.txt",
+				expected: true,
+			},
+			"pattern with gittuf file prefix for all recursive contents, matches": {
+				patterns: []string{"file:src/signatures/**"},
+				target:   "file:src/signatures/rsa/rsa.go",
+				expected: true,
+			},
+			"negated pattern, does not match": {
+				patterns: []string{"file:src/**", "!file:src/generated/**"},
+				target:   "file:src/generated/types.go",
+				expected: false,
+			},
+			"negated pattern, matches": {
+				patterns: []string{"file:src/**", "!file:src/generated/**"},
+				target:   "file:src/main.go",
+				expected: true,
+			},
+			"negated pattern with gittuf git prefix, does not match": {
+				patterns: []string{"git:refs/heads/**", "!git:refs/heads/users/**"},
+				target:   "git:refs/heads/users/jane/feature",
+				expected: false,
+			},
+		}
+
+		for name, test := range tests {
+			delegation := Delegation{Paths: test.patterns}
+			got := delegation.Matches(test.target)
+			assert.Equal(t, test.expected, got, fmt.Sprintf("unexpected result in test '%s'", name))
+		}
+	})
+
+	t.Run("threshold", func(t *testing.T) {
+		delegation := &Delegation{}
+
+		threshold := delegation.GetThreshold()
+		assert.Equal(t, 0, threshold)
+
+		delegation.Threshold = 1
+		threshold = delegation.GetThreshold()
+		assert.Equal(t, 1, threshold)
+	})
+
+	t.Run("terminating", func(t *testing.T) {
+		delegation := &Delegation{}
+
+		isTerminating := delegation.IsLastTrustedInRuleFile()
+		assert.False(t, isTerminating)
+
+		delegation.Terminating = true
+		isTerminating = delegation.IsLastTrustedInRuleFile()
+		assert.True(t, isTerminating)
+	})
+
+	t.Run("protected namespaces", func(t *testing.T) {
+		delegation := &Delegation{
+			Paths: []string{"1", "2"},
+		}
+
+		protected := delegation.GetProtectedNamespaces()
+		assert.Equal(t, []string{"1", "2"}, protected)
+	})
+
+	t.Run("principal IDs", func(t *testing.T) {
+		keyIDs := set.NewSetFromItems("1", "2")
+		delegation := &Delegation{
+			Role: Role{PrincipalIDs: keyIDs},
+		}
+
+		principalIDs := delegation.GetPrincipalIDs()
+		assert.Equal(t, keyIDs, principalIDs)
+	})
+}
+
+func TestAddRuleAndGetRules(t *testing.T) {
+	targetsMetadata := initialTestTargetsMetadata(t)
+
+	key1 := NewKeyFromSSLibKey(ssh.NewKeyFromBytes(t, targets1PubKeyBytes))
+	key2 := NewKeyFromSSLibKey(ssh.NewKeyFromBytes(t, targets2PubKeyBytes))
+	person := &Person{
+		PersonID:   "jane.doe",
+		PublicKeys: map[string]*Key{key1.KeyID: key1},
+	}
+
+	if err := targetsMetadata.AddPrincipal(key1); err != nil {
+		t.Fatal(err)
+	}
+	if err := targetsMetadata.AddPrincipal(key2); err != nil {
+		t.Fatal(err)
+	}
+	if err := targetsMetadata.AddPrincipal(person); err != nil {
+		t.Fatal(err)
+	}
+
+	err := targetsMetadata.AddRule("test-rule", []string{key1.KeyID, key2.KeyID, person.PersonID}, []string{"test/"}, 1)
+	assert.Nil(t, err)
+	assert.Contains(t, targetsMetadata.Delegations.Principals, key1.KeyID)
+	assert.Equal(t, key1, targetsMetadata.Delegations.Principals[key1.KeyID])
+	assert.Contains(t, targetsMetadata.Delegations.Principals, key2.KeyID)
+	assert.Equal(t, key2, targetsMetadata.Delegations.Principals[key2.KeyID])
+	assert.Contains(t, targetsMetadata.Delegations.Principals, person.PersonID)
+	assert.Equal(t, person, targetsMetadata.Delegations.Principals[person.PersonID])
+	assert.Contains(t, targetsMetadata.Delegations.Roles, AllowRule())
+
+	rule := &Delegation{
+		Name:        "test-rule",
+		Paths:       []string{"test/"},
+		Terminating: false,
+		Role:        Role{PrincipalIDs: set.NewSetFromItems(key1.KeyID, key2.KeyID, person.PersonID), Threshold: 1},
+	}
+	assert.Equal(t, rule, targetsMetadata.Delegations.Roles[0])
+
+	rules := targetsMetadata.GetRules()
+	assert.Equal(t, 2, len(rules))
+	assert.Equal(t, []tuf.Rule{rule, AllowRule()}, rules)
+
+	t.Run("invalid patterns", func(t *testing.T) {
+		err := targetsMetadata.AddRule("negated-rule", []string{key1.KeyID}, []string{"!file:src/generated/**"}, 1)
+		assert.ErrorIs(t, err, tuf.ErrNoPatternToMatch)
+
+		err = targetsMetadata.UpdateRule("test-rule", []string{key1.KeyID}, []string{"file:src/[a-z"}, 1)
+		assert.ErrorIs(t, err, tuf.ErrInvalidPattern)
+		assert.Equal(t, []tuf.Rule{rule, AllowRule()}, targetsMetadata.GetRules())
+	})
+}
+
+func TestUpdateDelegation(t *testing.T) {
+	targetsMetadata := initialTestTargetsMetadata(t)
+
+	key1 := NewKeyFromSSLibKey(ssh.NewKeyFromBytes(t, targets1PubKeyBytes))
+	key2 := NewKeyFromSSLibKey(ssh.NewKeyFromBytes(t, targets2PubKeyBytes))
+
+	if err := targetsMetadata.AddPrincipal(key1); err != nil {
+		t.Fatal(err)
+	}
+	err := targetsMetadata.AddRule("test-rule", []string{key1.KeyID}, []string{"test/"}, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Contains(t, targetsMetadata.Delegations.Principals, key1.KeyID)
+	assert.Equal(t, key1, targetsMetadata.Delegations.Principals[key1.KeyID])
+	assert.Contains(t, targetsMetadata.Delegations.Roles, AllowRule())
+	assert.Equal(t, &Delegation{
+		Name:        "test-rule",
+		Paths:       []string{"test/"},
+		Terminating: false,
+		Role:        Role{PrincipalIDs: set.NewSetFromItems(key1.KeyID), Threshold: 1},
+	}, targetsMetadata.Delegations.Roles[0])
+
+	if err := targetsMetadata.AddPrincipal(key2); err != nil {
+		t.Fatal(err)
+	}
+	err = targetsMetadata.UpdateRule("test-rule", []string{key1.KeyID, key2.KeyID}, []string{"test/"}, 1)
+	assert.Nil(t, err)
+	assert.Contains(t, targetsMetadata.Delegations.Principals, key1.KeyID)
+	assert.Equal(t, key1, targetsMetadata.Delegations.Principals[key1.KeyID])
+	assert.Contains(t, targetsMetadata.Delegations.Principals, key2.KeyID)
+	assert.Equal(t, key2, targetsMetadata.Delegations.Principals[key2.KeyID])
+	assert.Contains(t, targetsMetadata.Delegations.Roles, AllowRule())
+	assert.Equal(t, &Delegation{
+		Name:        "test-rule",
+		Paths:       []string{"test/"},
+		Terminating: false,
+		Role:        Role{PrincipalIDs: set.NewSetFromItems(key1.KeyID, key2.KeyID), Threshold: 1},
+	}, targetsMetadata.Delegations.Roles[0])
+}
+
+func TestUpdateRuleTeamThreshold(t *testing.T) {
+	targetsMetadata := initialTestTargetsMetadata(t)
+
+	key1 := NewKeyFromSSLibKey(ssh.NewKeyFromBytes(t, targets1PubKeyBytes))
+	key2 := NewKeyFromSSLibKey(ssh.NewKeyFromBytes(t, targets2PubKeyBytes))
+	team, err := NewTeam("dev", []tuf.Principal{key1, key2}, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := targetsMetadata.AddPrincipal(key1); err != nil {
+		t.Fatal(err)
+	}
+	if err := targetsMetadata.AddPrincipal(team); err != nil {
+		t.Fatal(err)
+	}
+	if err := targetsMetadata.AddRule("test-rule", []string{team.TeamID}, []string{"test/"}, 1); err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("set team threshold", func(t *testing.T) {
+		err := targetsMetadata.UpdateRuleTeamThreshold("test-rule", team.TeamID, 2)
+		assert.Nil(t, err)
+		assert.Equal(t, map[string]int{team.TeamID: 2}, targetsMetadata.Delegations.Roles[0].GetTeamThresholds())
+	})
+
+	t.Run("threshold exceeds team size", func(t *testing.T) {
+		err := targetsMetadata.UpdateRuleTeamThreshold("test-rule", team.TeamID, 3)
+		assert.ErrorIs(t, err, tuf.ErrCannotMeetThreshold)
+	})
+
+	t.Run("principal is not a team", func(t *testing.T) {
+		err := targetsMetadata.UpdateRuleTeamThreshold("test-rule", key1.KeyID, 1)
+		assert.ErrorIs(t, err, tuf.ErrTeamNotFound)
+	})
+
+	t.Run("team not trusted by rule", func(t *testing.T) {
+		if err := targetsMetadata.AddRule("other-rule", []string{key1.KeyID}, []string{"other/"}, 1); err != nil {
+			t.Fatal(err)
+		}
+
+		err := targetsMetadata.UpdateRuleTeamThreshold("other-rule", team.TeamID, 1)
+		assert.ErrorIs(t, err, tuf.ErrTeamNotFound)
+	})
+
+	t.Run("rule not found", func(t *testing.T) {
+		err := targetsMetadata.UpdateRuleTeamThreshold("missing-rule", team.TeamID, 1)
+		assert.ErrorIs(t, err, tuf.ErrRuleNotFound)
+	})
+
+	t.Run("team removed from rule", func(t *testing.T) {
+		err := targetsMetadata.UpdateRule("test-rule", []string{key1.KeyID}, []string{"test/"}, 1)
+		assert.Nil(t, err)
+		assert.Empty(t, targetsMetadata.Delegations.Roles[0].GetTeamThresholds())
+	})
+
+	t.Run("serialize and deserialize", func(t *testing.T) {
+		if err := targetsMetadata.UpdateRule("test-rule", []string{team.TeamID}, []string{"test/"}, 1); err != nil {
+			t.Fatal(err)
+		}
+		if err := targetsMetadata.UpdateRuleTeamThreshold("test-rule", team.TeamID, 2); err != nil {
+			t.Fatal(err)
+		}
+
+		targetsMetadataBytes, err := json.Marshal(targetsMetadata)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		decodedTargetsMetadata := &TargetsMetadata{}
+		err = json.Unmarshal(targetsMetadataBytes, decodedTargetsMetadata)
+		assert.Nil(t, err)
+		assert.Equal(t, team, decodedTargetsMetadata.Delegations.Principals[team.TeamID])
+		assert.Equal(t, map[string]int{team.TeamID: 2}, decodedTargetsMetadata.Delegations.Roles[0].GetTeamThresholds())
+	})
+}
+
+func TestUpdateRuleActions(t *testing.T) {
+	targetsMetadata := initialTestTargetsMetadata(t)
+
+	key1 := NewKeyFromSSLibKey(ssh.NewKeyFromBytes(t, targets1PubKeyBytes))
+	if err := targetsMetadata.AddPrincipal(key1); err != nil {
+		t.Fatal(err)
+	}
+	if err := targetsMetadata.AddRule("protect-branches", []string{key1.KeyID}, []string{"git:refs/heads/*"}, 1); err != nil {
+		t.Fatal(err)
+	}
+	if err := targetsMetadata.AddRule("protect-files", []string{key1.KeyID}, []string{"file:*"}, 1); err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("set actions", func(t *testing.T) {
+		err := targetsMetadata.UpdateRuleActions("protect-branches", []tuf.RefAction{tuf.RefActionDelete, tuf.RefActionCreate})
+		assert.Nil(t, err)
+		assert.Equal(t, []tuf.RefAction{tuf.RefActionCreate, tuf.RefActionDelete}, targetsMetadata.Delegations.Roles[0].GetAllowedActions())
+	})
+
+	t.Run("actions for file rule", func(t *testing.T) {
+		err := targetsMetadata.UpdateRuleActions("protect-files", []tuf.RefAction{tuf.RefActionDelete})
+		assert.ErrorIs(t, err, tuf.ErrRefActionsOnlyApplyToGitPaths)
+	})
+
+	t.Run("file pattern added to rule with actions", func(t *testing.T) {
+		err := targetsMetadata.UpdateRule("protect-branches", []string{key1.KeyID}, []string{"git:refs/heads/*", "file:*"}, 1)
+		assert.ErrorIs(t, err, tuf.ErrRefActionsOnlyApplyToGitPaths)
+	})
+
+	t.Run("invalid action", func(t *testing.T) {
+		err := targetsMetadata.UpdateRuleActions("protect-branches", []tuf.RefAction{"rename"})
+		assert.ErrorIs(t, err, tuf.ErrInvalidRefAction)
+	})
+
+	t.Run("rule not found", func(t *testing.T) {
+		err := targetsMetadata.UpdateRuleActions("missing-rule", []tuf.RefAction{tuf.RefActionDelete})
+		assert.ErrorIs(t, err, tuf.ErrRuleNotFound)
+	})
+
+	t.Run("serialize and deserialize", func(t *testing.T) {
+		targetsMetadataBytes, err := json.Marshal(targetsMetadata)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		decodedTargetsMetadata := &TargetsMetadata{}
+		err = json.Unmarshal(targetsMetadataBytes, decodedTargetsMetadata)
+		assert.Nil(t, err)
+		assert.Equal(t, []tuf.RefAction{tuf.RefActionCreate, tuf.RefActionDelete}, decodedTargetsMetadata.Delegations.Roles[0].GetAllowedActions())
+	})
+
+	t.Run("clear actions", func(t *testing.T) {
+		err := targetsMetadata.UpdateRuleActions("protect-branches", nil)
+		assert.Nil(t, err)
+		assert.Nil(t, targetsMetadata.Delegations.Roles[0].GetAllowedActions())
+	})
+}
+
+func TestUpdateRuleValidityWindow(t *testing.T) {
+	targetsMetadata := initialTestTargetsMetadata(t)
+
+	key1 := NewKeyFromSSLibKey(ssh.NewKeyFromBytes(t, targets1PubKeyBytes))
+	if err := targetsMetadata.AddPrincipal(key1); err != nil {
+		t.Fatal(err)
+	}
+	if err := targetsMetadata.AddRule("protect-main", []string{key1.KeyID}, []string{"git:refs/heads/main"}, 1); err != nil {
+		t.Fatal(err)
+	}
+
+	notAfter := time.Date(2025, time.January, 8, 0, 0, 0, 0, time.UTC)
+	window, err := tuf.NewValidityWindow(time.Time{}, notAfter)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("set window", func(t *testing.T) {
+		err := targetsMetadata.UpdateRuleValidityWindow("protect-main", window)
+		assert.Nil(t, err)
+		assert.Equal(t, window, targetsMetadata.Delegations.Roles[0].GetValidityWindow())
+	})
+
+	t.Run("window kept when rule is updated", func(t *testing.T) {
+		err := targetsMetadata.UpdateRule("protect-main", []string{key1.KeyID}, []string{"git:refs/heads/main", "git:refs/heads/release"}, 1)
+		assert.Nil(t, err)
+		assert.Equal(t, window, targetsMetadata.Delegations.Roles[0].GetValidityWindow())
+	})
+
+	t.Run("rule not found", func(t *testing.T) {
+		err := targetsMetadata.UpdateRuleValidityWindow("missing-rule", window)
+		assert.ErrorIs(t, err, tuf.ErrRuleNotFound)
+	})
+
+	t.Run("serialize and deserialize", func(t *testing.T) {
+		targetsMetadataBytes, err := json.Marshal(targetsMetadata)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		decodedTargetsMetadata := &TargetsMetadata{}
+		err = json.Unmarshal(targetsMetadataBytes, decodedTargetsMetadata)
+		assert.Nil(t, err)
+		assert.Nil(t, decodedTargetsMetadata.Delegations.Roles[0].GetValidityWindow().NotBefore)
+		assert.True(t, notAfter.Equal(*decodedTargetsMetadata.Delegations.Roles[0].GetValidityWindow().NotAfter))
+	})
+
+	t.Run("clear window", func(t *testing.T) {
+		err := targetsMetadata.UpdateRuleValidityWindow("protect-main", nil)
+		assert.Nil(t, err)
+		assert.Nil(t, targetsMetadata.Delegations.Roles[0].GetValidityWindow())
+	})
+}
+
+func TestUpdatePrincipalValidityWindow(t *testing.T) {
+	targetsMetadata := initialTestTargetsMetadata(t)
+
+	key1 := NewKeyFromSSLibKey(ssh.NewKeyFromBytes(t, targets1PubKeyBytes))
+	if err := targetsMetadata.AddPrincipal(key1); err != nil {
+		t.Fatal(err)
+	}
+
+	notBefore := time.Date(2025, time.January, 1, 0, 0, 0, 0, time.UTC)
+	window, err := tuf.NewValidityWindow(notBefore, notBefore.AddDate(0, 0, 7))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("set window", func(t *testing.T) {
+		err := targetsMetadata.UpdatePrincipalValidityWindow(key1.KeyID, window)
+		assert.Nil(t, err)
+		assert.Equal(t, map[string]*tuf.ValidityWindow{key1.KeyID: window}, targetsMetadata.GetPrincipalValidityWindows())
+	})
+
+	t.Run("principal not found", func(t *testing.T) {
+		err := targetsMetadata.UpdatePrincipalValidityWindow("missing-principal", window)
+		assert.ErrorIs(t, err, tuf.ErrPrincipalNotFound)
+	})
+
+	t.Run("serialize and deserialize", func(t *testing.T) {
+		targetsMetadataBytes, err := json.Marshal(targetsMetadata)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		decodedTargetsMetadata := &TargetsMetadata{}
+		err = json.Unmarshal(targetsMetadataBytes, decodedTargetsMetadata)
+		assert.Nil(t, err)
+		assert.Contains(t, decodedTargetsMetadata.GetPrincipalValidityWindows(), key1.KeyID)
+	})
+
+	t.Run("window removed with principal", func(t *testing.T) {
+		err := targetsMetadata.RemovePrincipal(key1.KeyID)
+		assert.Nil(t, err)
+		assert.Empty(t, targetsMetadata.GetPrincipalValidityWindows())
+	})
+
+	t.Run("clear window", func(t *testing.T) {
+		if err := targetsMetadata.AddPrincipal(key1); err != nil {
+			t.Fatal(err)
+		}
+		if err := targetsMetadata.UpdatePrincipalValidityWindow(key1.KeyID, window); err != nil {
+			t.Fatal(err)
+		}
+
+		err := targetsMetadata.UpdatePrincipalValidityWindow(key1.KeyID, nil)
+		assert.Nil(t, err)
+		assert.Nil(t, targetsMetadata.GetPrincipalValidityWindows())
+	})
+}
+
+func TestReorderRules(t *testing.T) {
+	targetsMetadata := initialTestTargetsMetadata(t)
+
+	key1 := NewKeyFromSSLibKey(ssh.NewKeyFromBytes(t, targets1PubKeyBytes))
+	key2 := NewKeyFromSSLibKey(ssh.NewKeyFromBytes(t, targets2PubKeyBytes))
+
+	if err := targetsMetadata.AddPrincipal(key1); err != nil {
+		t.Fatal(err)
+	}
+	if err := targetsMetadata.AddPrincipal(key2); err != nil {
+		t.Fatal(err)
+	}
+
+	err := targetsMetadata.AddRule("rule-1", []string{key1.KeyID}, []string{"path1/"}, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = targetsMetadata.AddRule("rule-2", []string{key2.KeyID}, []string{"path2/"}, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = targetsMetadata.AddRule("rule-3", []string{key1.KeyID, key2.KeyID}, []string{"path3/"}, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := map[string]struct {
+		ruleNames     []string
+		expected      []string
+		expectedError error
+	}{
+		"reverse order (valid input)": {
+			ruleNames:     []string{"rule-3", "rule-2", "rule-1"},
+			expected:      []string{"rule-3", "rule-2", "rule-1", tuf.AllowRuleName},
+			expectedError: nil,
+		},
+		"rule not specified in new order": {
+			ruleNames:     []string{"rule-3", "rule-2"},
+			expectedError: tuf.ErrMissingRules,
+		},
+		"rule repeated in the new order": {
+			ruleNames:     []string{"rule-3", "rule-2", "rule-1", "rule-3"},
+			expectedError: tuf.ErrDuplicatedRuleName,
+		},
+		"unknown rule in the new order": {
+			ruleNames:     []string{"rule-3", "rule-2", "rule-1", "rule-4"},
+			expectedError: tuf.ErrRuleNotFound,
+		},
+		"unknown rule in the new order (with correct length)": {
+			ruleNames:     []string{"rule-3", "rule-2", "rule-4"},
+			expectedError: tuf.ErrRuleNotFound,
+		},
+		"allow rule appears in the new order": {
+			ruleNames:     []string{"rule-2", "rule-3", "rule-1", tuf.AllowRuleName},
+			expectedError: tuf.ErrCannotManipulateRulesWithGittufPrefix,
+		},
+	}
+
+	for name, test := range tests {
+		err = targetsMetadata.ReorderRules(test.ruleNames)
+		if test.expectedError != nil {
+			assert.ErrorIs(t, err, test.expectedError, fmt.Sprintf("unexpected error in test '%s'", name))
+		} else {
+			assert.Nil(t, err, fmt.Sprintf("unexpected error in test '%s'", name))
+			assert.Equal(t, len(test.expected), len(targetsMetadata.Delegations.Roles),
+				fmt.Sprintf("expected %d rules in test '%s', but got %d rules",
+					len(test.expected), name, len(targetsMetadata.Delegations.Roles)))
+			for i, ruleName := range test.expected {
+				assert.Equal(t, ruleName, targetsMetadata.Delegations.Roles[i].Name,
+					fmt.Sprintf("expected rule '%s' at index %d in test '%s', but got '%s'",
+						ruleName, i, name, targetsMetadata.Delegations.Roles[i].Name))
+			}
+		}
+	}
+}
+
+func TestRemoveRule(t *testing.T) {
+	targetsMetadata := initialTestTargetsMetadata(t)
+
+	key := NewKeyFromSSLibKey(ssh.NewKeyFromBytes(t, targets1PubKeyBytes))
+	if err := targetsMetadata.AddPrincipal(key); err != nil {
+		t.Fatal(err)
+	}
+
+	err := targetsMetadata.AddRule("test-rule", []string{key.KeyID}, []string{"test/"}, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, 2, len(targetsMetadata.Delegations.Roles))
+
+	err = targetsMetadata.RemoveRule("test-rule")
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(targetsMetadata.Delegations.Roles))
+	assert.Contains(t, targetsMetadata.Delegations.Roles, AllowRule())
+	assert.Contains(t, targetsMetadata.Delegations.Principals, key.KeyID)
+}
+
+func TestGetPrincipals(t *testing.T) {
+	targetsMetadata := initialTestTargetsMetadata(t)
+	key1 := NewKeyFromSSLibKey(ssh.NewKeyFromBytes(t, targets1PubKeyBytes))
+	if err := targetsMetadata.AddPrincipal(key1); err != nil {
+		t.Fatal(err)
+	}
+
+	principals := targetsMetadata.GetPrincipals()
+	assert.Equal(t, map[string]tuf.Principal{key1.KeyID: key1}, principals)
+
+	key2 := NewKeyFromSSLibKey(ssh.NewKeyFromBytes(t, targets2PubKeyBytes))
+	if err := targetsMetadata.AddPrincipal(key2); err != nil {
+		t.Fatal(err)
+	}
+
+	principals = targetsMetadata.GetPrincipals()
+	assert.Equal(t, map[string]tuf.Principal{key1.KeyID: key1, key2.KeyID: key2}, principals)
+}
+
+func TestRotatePersonKey(t *testing.T) {
+	key1 := NewKeyFromSSLibKey(ssh.NewKeyFromBytes(t, targets1PubKeyBytes))
+	key2 := NewKeyFromSSLibKey(ssh.NewKeyFromBytes(t, targets2PubKeyBytes))
+	gracePeriodEnd := time.Date(2030, time.January, 1, 0, 0, 0, 0, time.UTC)
+
+	targetsMetadata := initialTestTargetsMetadata(t)
+	person := &Person{
+		PersonID:   "jane.doe@example.com",
+		PublicKeys: map[string]*Key{key1.KeyID: key1},
+	}
+	team := &Team{
+		TeamID:     "reviewers",
+		Principals: map[string]tuf.Principal{person.PersonID: person},
+		Threshold:  1,
+	}
+	require.Nil(t, targetsMetadata.AddPrincipal(key1))
+	require.Nil(t, targetsMetadata.AddPrincipal(person))
+	require.Nil(t, targetsMetadata.AddPrincipal(team))
+
+	err := targetsMetadata.RotatePersonKey("john.doe@example.com", key1.KeyID, key2, gracePeriodEnd)
+	assert.ErrorIs(t, err, tuf.ErrPrincipalNotFound)
+
+	err = targetsMetadata.RotatePersonKey(key1.KeyID, key1.KeyID, key2, gracePeriodEnd)
+	assert.ErrorIs(t, err, tuf.ErrInvalidPrincipalType)
+
+	err = targetsMetadata.RotatePersonKey(person.PersonID, key2.KeyID, key1, gracePeriodEnd)
+	assert.ErrorIs(t, err, tuf.ErrKeyNotFound)
+
+	err = targetsMetadata.RotatePersonKey(person.PersonID, key1.KeyID, key2, gracePeriodEnd)
+	assert.Nil(t, err)
+
+	rotated := targetsMetadata.Delegations.Principals[person.PersonID].(*Person)
+	assert.Equal(t, map[string]*Key{key2.KeyID: key2}, rotated.PublicKeys)
+	assert.Equal(t, rotated, targetsMetadata.Delegations.Principals[team.TeamID].(*Team).Principals[person.PersonID])
+	assert.Equal(t, []*KeyRotation{{
+		PrincipalID:    person.PersonID,
+		OldKey:         key1,
+		NewKeyID:       key2.KeyID,
+		GracePeriodEnd: gracePeriodEnd,
+	}}, targetsMetadata.Delegations.KeyRotations)
+	assert.Len(t, targetsMetadata.GetKeyRotations(), 1)
+}
+
+func TestAllowRule(t *testing.T) {
+	allowRule := AllowRule()
+	assert.Equal(t, tuf.AllowRuleName, allowRule.Name)
+	assert.Equal(t, []string{"**"}, allowRule.Paths)
+	assert.True(t, allowRule.Terminating)
+	assert.Empty(t, allowRule.PrincipalIDs)
+	assert.Equal(t, 1, allowRule.Threshold)
+}
@@ -0,0 +1,83 @@
+// Copyright The gittuf Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package v04
+
+// This package defines gittuf's take on TUF metadata. It uses the same
+// principal types as v03, but the patterns in rules and global rules may be
+// negated and use `**` to match across path separators. See tuf.MatchPatterns
+// for the pattern syntax.
+
+import (
+	"time"
+
+	tufv03 "github.com/gittuf/gittuf/internal/tuf/v03"
+	"github.com/secure-systems-lab/go-securesystemslib/signerverifier"
+)
+
+// Key defines the structure for how public keys are stored in TUF metadata. It
+// implements the tuf.Principal and is used for backwards compatibility where a
+// Principal is always represented directly by a signing key or identity.
+type Key = tufv03.Key
+
+// NewKeyFromSSLibKey converts the signerverifier.SSLibKey into a Key object.
+func NewKeyFromSSLibKey(key *signerverifier.SSLibKey) *Key {
+	k := Key(*key)
+	return &k
+}
+
+// Person defines the structure for a principal that may have multiple keys
+// and associated identities.
+type Person = tufv03.Person
+
+// Role records common characteristics recorded in a role entry in Root metadata
+// and in a delegation entry.
+type Role = tufv03.Role
+
+// Team groups a set of principals so that they can be trusted as a single unit
+// in a role or rule.
+type Team = tufv03.Team
+
+// NewTeam returns a new instance of Team with the specified members.
+var NewTeam = tufv03.NewTeam
+
+// KeyRotation records that a principal's key was replaced with another key,
+// and the time until which the old key is trusted for the principal.
+type KeyRotation = tufv03.KeyRotation
+
+// KeyRevocation records that a key is compromised.
+type KeyRevocation = tufv03.KeyRevocation
+
+// rotatePersonKey returns a copy of the person with the key identified by
+// oldKeyID replaced by newKey.
+func rotatePersonKey(person *Person, oldKeyID string, newKey *Key) *Person {
+	rotated := *person
+	rotated.PublicKeys = make(map[string]*Key, len(person.PublicKeys))
+	for keyID, key := range person.PublicKeys {
+		if keyID != oldKeyID {
+			rotated.PublicKeys[keyID] = key
+		}
+	}
+	rotated.PublicKeys[newKey.KeyID] = newKey
+
+	return &rotated
+}
+
+// newKeyRotation returns the rotation record for the old key if a grace period
+// is set, and nil otherwise.
+func newKeyRotation(principalID string, oldKey, newKey *Key, gracePeriodEnd time.Time) *KeyRotation {
+	if gracePeriodEnd.IsZero() {
+		return nil
+	}
+
+	return &KeyRotation{
+		PrincipalID:    principalID,
+		OldKey:         oldKey,
+		NewKeyID:       newKey.KeyID,
+		GracePeriodEnd: gracePeriodEnd.UTC(),
+	}
+}
+
+// UnmarshalPrincipal identifies the type of the serialized principal and
+// returns it. The principal may be a key, a person, or a team.
+var UnmarshalPrincipal = tufv03.UnmarshalPrincipal
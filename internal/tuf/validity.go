@@ -0,0 +1,97 @@
+// Copyright The gittuf Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package tuf
+
+import (
+	"fmt"
+	"time"
+)
+
+// ValidityWindow records the period during which a rule or a principal in a
+// rule file is trusted, so that temporary access, such as for a contractor or
+// an incident responder, lapses without the policy having to be updated. A
+// bound that isn't set leaves that end of the window open.
+type ValidityWindow struct {
+	NotBefore *time.Time `json:"notBefore,omitempty"`
+	NotAfter  *time.Time `json:"notAfter,omitempty"`
+}
+
+// NewValidityWindow returns a validity window with the specified bounds. A zero
+// time leaves that end of the window open. If neither bound is set, nil is
+// returned as the window doesn't restrict anything.
+func NewValidityWindow(notBefore, notAfter time.Time) (*ValidityWindow, error) {
+	if notBefore.IsZero() && notAfter.IsZero() {
+		return nil, nil
+	}
+
+	window := &ValidityWindow{}
+	if !notBefore.IsZero() {
+		notBefore = notBefore.UTC()
+		window.NotBefore = &notBefore
+	}
+	if !notAfter.IsZero() {
+		notAfter = notAfter.UTC()
+		window.NotAfter = &notAfter
+	}
+
+	if err := window.Validate(); err != nil {
+		return nil, err
+	}
+
+	return window, nil
+}
+
+// Validate ensures that the window ends after it begins.
+func (w *ValidityWindow) Validate() error {
+	if w != nil && w.NotBefore != nil && w.NotAfter != nil && !w.NotAfter.After(*w.NotBefore) {
+		return ErrInvalidValidityWindow
+	}
+
+	return nil
+}
+
+// Equal indicates if the two windows have the same bounds.
+func (w *ValidityWindow) Equal(other *ValidityWindow) bool {
+	if w == nil || other == nil {
+		return w == other
+	}
+
+	equalBound := func(a, b *time.Time) bool {
+		if a == nil || b == nil {
+			return a == b
+		}
+		return a.Equal(*b)
+	}
+	return equalBound(w.NotBefore, other.NotBefore) && equalBound(w.NotAfter, other.NotAfter)
+}
+
+// String describes the bounds of the window.
+func (w *ValidityWindow) String() string {
+	switch {
+	case w == nil || (w.NotBefore == nil && w.NotAfter == nil):
+		return "always"
+	case w.NotBefore == nil:
+		return fmt.Sprintf("until %s", w.NotAfter.Format(time.RFC3339))
+	case w.NotAfter == nil:
+		return fmt.Sprintf("from %s", w.NotBefore.Format(time.RFC3339))
+	default:
+		return fmt.Sprintf("from %s until %s", w.NotBefore.Format(time.RFC3339), w.NotAfter.Format(time.RFC3339))
+	}
+}
+
+// IsActiveAt indicates if the window includes the specified time. A nil window
+// includes all times.
+func (w *ValidityWindow) IsActiveAt(at time.Time) bool {
+	return !w.HasExpiredAt(at) && !w.IsPendingAt(at)
+}
+
+// HasExpiredAt indicates if the window ended before the specified time.
+func (w *ValidityWindow) HasExpiredAt(at time.Time) bool {
+	return w != nil && w.NotAfter != nil && at.After(*w.NotAfter)
+}
+
+// IsPendingAt indicates if the window starts after the specified time.
+func (w *ValidityWindow) IsPendingAt(at time.Time) bool {
+	return w != nil && w.NotBefore != nil && at.Before(*w.NotBefore)
+}
@@ -0,0 +1,94 @@
+// Copyright The gittuf Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package tuf
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewValidityWindow(t *testing.T) {
+	notBefore := time.Date(2025, time.January, 1, 0, 0, 0, 0, time.UTC)
+	notAfter := notBefore.AddDate(0, 0, 7)
+
+	t.Run("both bounds", func(t *testing.T) {
+		window, err := NewValidityWindow(notBefore, notAfter)
+		assert.Nil(t, err)
+		assert.Equal(t, notBefore, *window.NotBefore)
+		assert.Equal(t, notAfter, *window.NotAfter)
+	})
+
+	t.Run("only end", func(t *testing.T) {
+		window, err := NewValidityWindow(time.Time{}, notAfter)
+		assert.Nil(t, err)
+		assert.Nil(t, window.NotBefore)
+		assert.Equal(t, notAfter, *window.NotAfter)
+	})
+
+	t.Run("no bounds", func(t *testing.T) {
+		window, err := NewValidityWindow(time.Time{}, time.Time{})
+		assert.Nil(t, err)
+		assert.Nil(t, window)
+	})
+
+	t.Run("end before start", func(t *testing.T) {
+		_, err := NewValidityWindow(notAfter, notBefore)
+		assert.ErrorIs(t, err, ErrInvalidValidityWindow)
+	})
+}
+
+func TestValidityWindowIsActiveAt(t *testing.T) {
+	notBefore := time.Date(2025, time.January, 1, 0, 0, 0, 0, time.UTC)
+	notAfter := notBefore.AddDate(0, 0, 7)
+
+	window, err := NewValidityWindow(notBefore, notAfter)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	before := notBefore.Add(-time.Hour)
+	assert.False(t, window.IsActiveAt(before))
+	assert.True(t, window.IsPendingAt(before))
+	assert.False(t, window.HasExpiredAt(before))
+
+	during := notBefore.Add(time.Hour)
+	assert.True(t, window.IsActiveAt(during))
+	assert.False(t, window.IsPendingAt(during))
+	assert.False(t, window.HasExpiredAt(during))
+
+	after := notAfter.Add(time.Hour)
+	assert.False(t, window.IsActiveAt(after))
+	assert.False(t, window.IsPendingAt(after))
+	assert.True(t, window.HasExpiredAt(after))
+
+	var noWindow *ValidityWindow
+	assert.True(t, noWindow.IsActiveAt(after))
+}
+
+func TestValidityWindowEqual(t *testing.T) {
+	notBefore := time.Date(2025, time.January, 1, 0, 0, 0, 0, time.UTC)
+
+	window, err := NewValidityWindow(notBefore, time.Time{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	sameWindow, err := NewValidityWindow(notBefore.In(time.FixedZone("UTC+1", 3600)), time.Time{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	otherWindow, err := NewValidityWindow(time.Time{}, notBefore)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assert.True(t, window.Equal(sameWindow))
+	assert.False(t, window.Equal(otherWindow))
+	assert.False(t, window.Equal(nil))
+	assert.True(t, (*ValidityWindow)(nil).Equal(nil))
+
+	assert.Equal(t, "from 2025-01-01T00:00:00Z", window.String())
+	assert.Equal(t, "until 2025-01-01T00:00:00Z", otherWindow.String())
+}